@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/services"
+)
+
+// commands are the subcommands that run once and exit instead of starting the server, keyed by name. Each returns
+// the exit code.
+var commands = map[string]func(args []string) int{
+	"estimate": runEstimate,
+	"onboard":  runOnboard,
+	"schema":   runSchema,
+	"validate": runValidate,
+}
+
+// newCommandJiraService creates the Jira service of a subcommand, authenticating as configured
+func newCommandJiraService(config *models.Config) (services.JiraService, error) {
+	if err := services.ResolveSecrets(context.Background(), config, Logger); err != nil {
+		return nil, err
+	}
+	metrics := services.NewMetrics()
+	if config.Jira.AuthMode != models.JiraAuthModeConnect {
+		jiraService := services.NewJiraService(config, metrics, Logger)
+		if _, err := jiraService.DetectTokenType(context.Background()); errors.Is(err, services.ErrJiraAuthRejected) {
+			return nil, err
+		}
+		return jiraService, nil
+	}
+	jiraConnectService, err := services.NewJiraConnectService(config, Logger)
+	if err != nil {
+		return nil, err
+	}
+	return services.NewConnectJiraService(config, jiraConnectService, metrics, Logger), nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/services"
+
+	"go.uber.org/zap"
+)
+
+// runEstimate runs the estimate subcommand, which reports the projected AI usage and cost of processing the tickets
+// matching a JQL query without running the AI, and returns the exit code
+func runEstimate(args []string) int {
+	flags := flag.NewFlagSet("estimate", flag.ContinueOnError)
+	configPath := flags.String("config", "config.yaml", "Path to configuration file")
+	jql := flags.String("jql", "", "JQL query selecting the tickets to estimate")
+	asJSON := flags.Bool("json", false, "Write the report as JSON")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *jql == "" {
+		fmt.Fprintln(os.Stderr, "estimate requires -jql")
+		flags.Usage()
+		return 2
+	}
+
+	config, err := models.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		return 1
+	}
+
+	// The report goes to stdout, so it can be redirected without the logs
+	InitLogger(config, os.Stderr)
+	defer Logger.Sync()
+
+	jiraService, err := newCommandJiraService(config)
+	if err != nil {
+		Logger.Error("Failed to initialize Jira service", zap.Error(err))
+		return 1
+	}
+
+	estimate, err := services.NewCostEstimator(jiraService, config, Logger).Estimate(context.Background(), *jql)
+	if err != nil {
+		Logger.Error("Failed to estimate tickets", zap.Error(err))
+		return 1
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(estimate)
+	} else {
+		err = services.WriteCostEstimate(os.Stdout, estimate)
+	}
+	if err != nil {
+		Logger.Error("Failed to write estimate", zap.Error(err))
+		return 1
+	}
+	return 0
+}
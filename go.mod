@@ -2,9 +2,9 @@ module jira-ai-issue-solver
 
 go 1.24
 
-require gopkg.in/yaml.v3 v3.0.1
-
 require (
-	go.uber.org/multierr v1.10.0 // indirect
-	go.uber.org/zap v1.27.0 // indirect
+	go.uber.org/zap v1.27.0
+	gopkg.in/yaml.v3 v3.0.1
 )
+
+require go.uber.org/multierr v1.10.0 // indirect
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/services"
+
+	"go.uber.org/zap"
+)
+
+// costReportDateLayout is the layout of the from and to query parameters of cost reports
+const costReportDateLayout = "2006-01-02"
+
+// CostReportHandler exposes the AI cost reports per Jira project and component to the admin API
+type CostReportHandler struct {
+	reporter services.CostReporter
+	config   *models.Config
+	logger   *zap.Logger
+}
+
+// NewCostReportHandler creates a new CostReportHandler
+func NewCostReportHandler(reporter services.CostReporter, config *models.Config, logger *zap.Logger) *CostReportHandler {
+	return &CostReportHandler{
+		reporter: reporter,
+		config:   config,
+		logger:   logger,
+	}
+}
+
+// HandleCosts returns the cost report selected by the query parameters: period (day, week or month; cost_reports.period
+// by default), from and to dates (YYYY-MM-DD, to exclusive; the whole history by default) and format (json or csv).
+// Requests must carry the admin token as a bearer token.
+func (h *CostReportHandler) HandleCosts(w http.ResponseWriter, r *http.Request) {
+	if !adminAuthorized(r, h.config) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	period := h.config.CostReports.Period
+	if value := query.Get("period"); value != "" {
+		period = models.CostReportPeriod(value)
+		if !period.IsValid() {
+			http.Error(w, "invalid period: must be day, week or month", http.StatusBadRequest)
+			return
+		}
+	}
+	from, err := parseReportDate(query.Get("from"), time.Time{})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	to, err := parseReportDate(query.Get("to"), time.Now().UTC())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.reporter.Report(period, from, to)
+	if err != nil {
+		h.logger.Error("Failed to build cost report", zap.Error(err))
+		http.Error(w, "failed to build cost report", http.StatusInternalServerError)
+		return
+	}
+
+	switch query.Get("format") {
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="costs.csv"`)
+		err = services.WriteCostReportCSV(w, report)
+	case "", "json":
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(report)
+	default:
+		http.Error(w, "invalid format: must be json or csv", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to write cost report", zap.Error(err))
+	}
+}
+
+// parseReportDate parses a date query parameter, returning the fallback if it's empty
+func parseReportDate(value string, fallback time.Time) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	date, err := time.Parse(costReportDateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: must be YYYY-MM-DD", value)
+	}
+	return date, nil
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// fakeCostReporter returns a fixed report, remembering what it was asked for
+type fakeCostReporter struct {
+	period   models.CostReportPeriod
+	from, to time.Time
+}
+
+func (f *fakeCostReporter) Report(period models.CostReportPeriod, from, to time.Time) (*models.CostReport, error) {
+	f.period, f.from, f.to = period, from, to
+	return &models.CostReport{Period: period, From: from, To: to, Rows: []models.CostReportRow{
+		{Period: "2024-03", Project: "API", Component: "backend", Runs: 2, CostUSD: 4},
+	}, TotalRuns: 2, TotalCostUSD: 4}, nil
+}
+
+func (f *fakeCostReporter) Start() {}
+func (f *fakeCostReporter) Stop()  {}
+
+func TestCostReportHandler_HandleCosts(t *testing.T) {
+	config := &models.Config{}
+	config.Server.AdminToken = "secret"
+	config.CostReports.Period = models.CostReportMonthly
+
+	request := func(target string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		return req
+	}
+
+	t.Run("json", func(t *testing.T) {
+		reporter := &fakeCostReporter{}
+		recorder := httptest.NewRecorder()
+		NewCostReportHandler(reporter, config, zap.NewNop()).HandleCosts(recorder, request("/admin/costs?period=week&from=2024-03-01&to=2024-04-01"))
+
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+		}
+		if reporter.period != models.CostReportWeekly || !reporter.from.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) ||
+			!reporter.to.Equal(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("Unexpected report request: %s from %v to %v", reporter.period, reporter.from, reporter.to)
+		}
+		var report models.CostReport
+		if err := json.NewDecoder(recorder.Body).Decode(&report); err != nil {
+			t.Fatalf("Failed to decode report: %v", err)
+		}
+		if report.TotalCostUSD != 4 || len(report.Rows) != 1 {
+			t.Errorf("Unexpected report: %+v", report)
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		reporter := &fakeCostReporter{}
+		recorder := httptest.NewRecorder()
+		NewCostReportHandler(reporter, config, zap.NewNop()).HandleCosts(recorder, request("/admin/costs?format=csv"))
+
+		if reporter.period != models.CostReportMonthly {
+			t.Errorf("Expected the configured period by default, got %s", reporter.period)
+		}
+		if recorder.Header().Get("Content-Type") != "text/csv" || !strings.Contains(recorder.Body.String(), "2024-03,API,backend,2,") {
+			t.Errorf("Unexpected CSV response: %s", recorder.Body.String())
+		}
+	})
+
+	for _, target := range []string{"/admin/costs?period=year", "/admin/costs?from=March", "/admin/costs?format=xml"} {
+		recorder := httptest.NewRecorder()
+		NewCostReportHandler(&fakeCostReporter{}, config, zap.NewNop()).HandleCosts(recorder, request(target))
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", target, recorder.Code)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	NewCostReportHandler(&fakeCostReporter{}, config, zap.NewNop()).HandleCosts(recorder, httptest.NewRequest(http.MethodGet, "/admin/costs", nil))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 without the admin token, got %d", recorder.Code)
+	}
+}
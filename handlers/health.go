@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"jira-ai-issue-solver/services"
+
+	"go.uber.org/zap"
+)
+
+// HealthHandler reports whether the application can use its dependencies
+type HealthHandler struct {
+	checker services.HealthChecker
+	logger  *zap.Logger
+}
+
+// NewHealthHandler creates a new HealthHandler
+func NewHealthHandler(checker services.HealthChecker, logger *zap.Logger) *HealthHandler {
+	return &HealthHandler{
+		checker: checker,
+		logger:  logger,
+	}
+}
+
+// HandleReady reports the result of every dependency check, with a 503 if any failed
+func (h *HealthHandler) HandleReady(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	report := h.checker.Check(r.Context())
+	statusCode := http.StatusOK
+	if !report.Healthy {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		h.logger.Error("Failed to write health report", zap.Error(err))
+	}
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// fakeHealthChecker returns a fixed report
+type fakeHealthChecker struct {
+	report models.HealthReport
+}
+
+func (f *fakeHealthChecker) Check(ctx context.Context) models.HealthReport { return f.report }
+
+func TestHealthHandler_HandleReady(t *testing.T) {
+	tests := []struct {
+		name       string
+		report     models.HealthReport
+		wantStatus int
+	}{
+		{
+			name:       "healthy",
+			report:     models.HealthReport{Healthy: true, Checks: []models.HealthCheck{{Name: "jira", Healthy: true, Detail: "authenticated as ai-bot"}}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "unhealthy",
+			report:     models.HealthReport{Checks: []models.HealthCheck{{Name: "jira", Detail: "status code: 401"}}},
+			wantStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHealthHandler(&fakeHealthChecker{report: tt.report}, zap.NewNop())
+			recorder := httptest.NewRecorder()
+			handler.HandleReady(recorder, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, recorder.Code)
+			}
+			var report models.HealthReport
+			if err := json.NewDecoder(recorder.Body).Decode(&report); err != nil {
+				t.Fatalf("Failed to decode report: %v", err)
+			}
+			if len(report.Checks) != 1 || report.Checks[0].Detail != tt.report.Checks[0].Detail {
+				t.Errorf("Unexpected checks: %+v", report.Checks)
+			}
+		})
+	}
+
+	recorder := httptest.NewRecorder()
+	NewHealthHandler(&fakeHealthChecker{}, zap.NewNop()).HandleReady(recorder, httptest.NewRequest(http.MethodPost, "/health/ready", nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405 for POST, got %d", recorder.Code)
+	}
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/services"
+
+	"go.uber.org/zap"
+)
+
+// JiraWebhookHandler receives Jira issue webhooks and triggers an immediate scan when a ticket
+// may have become ready for processing, instead of waiting for the next scan interval
+type JiraWebhookHandler struct {
+	scanner        services.JiraIssueScannerService
+	connectService services.JiraConnectService
+	maintenance    services.MaintenanceService
+	config         *models.Config
+	logger         *zap.Logger
+}
+
+// NewJiraWebhookHandler creates a new JiraWebhookHandler.
+// Requests are authenticated with the Connect JWT of the installed Jira site.
+func NewJiraWebhookHandler(
+	scanner services.JiraIssueScannerService,
+	connectService services.JiraConnectService,
+	maintenance services.MaintenanceService,
+	config *models.Config,
+	logger *zap.Logger,
+) *JiraWebhookHandler {
+	return &JiraWebhookHandler{
+		scanner:        scanner,
+		connectService: connectService,
+		maintenance:    maintenance,
+		config:         config,
+		logger:         logger,
+	}
+}
+
+// HandleWebhook handles a Jira issue webhook
+func (h *JiraWebhookHandler) HandleWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	installation, err := h.connectService.VerifyRequest(r)
+	if err != nil {
+		h.logger.Warn("Rejected Jira webhook", zap.Error(err))
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Jira retries refused webhooks, so tickets changed during maintenance aren't lost
+	if h.maintenance.IsEnabled() {
+		setRetryAfter(w, h.config)
+		http.Error(w, "in maintenance mode", http.StatusServiceUnavailable)
+		return
+	}
+
+	var event models.JiraWebhookEvent
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize)).Decode(&event); err != nil {
+		h.logger.Warn("Invalid Jira webhook payload", zap.Error(err))
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	h.logger.Debug("Received Jira webhook",
+		zap.String("event", event.WebhookEvent),
+		zap.String("ticket", event.Issue.Key),
+		zap.String("client_key", installation.ClientKey))
+
+	if h.isReadyForProcessing(&event) {
+		h.logger.Info("Ticket is ready for processing, triggering scan", zap.String("ticket", event.Issue.Key))
+		h.scanner.TriggerScan()
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// isReadyForProcessing reports whether the event is about an issue marked for processing by the trigger mode: in the
+// "To Do" status, with the trigger label, or both. The scan decides whether the ticket is actually picked up.
+func (h *JiraWebhookHandler) isReadyForProcessing(event *models.JiraWebhookEvent) bool {
+	switch event.WebhookEvent {
+	case "jira:issue_created", "jira:issue_updated":
+	default:
+		return false
+	}
+	mode := h.config.Jira.TriggerMode
+	if mode.UsesStatus() && !strings.EqualFold(event.Issue.Fields.Status.Name, h.config.Jira.StatusTransitions.Todo) {
+		return false
+	}
+	if mode.UsesLabel() && !slices.Contains(event.Issue.Fields.Labels, h.config.Jira.TriggerLabel) {
+		return false
+	}
+	return true
+}
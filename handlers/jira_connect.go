@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/services"
+
+	"go.uber.org/zap"
+)
+
+// maxRequestBodySize limits the size of request bodies accepted from Jira
+const maxRequestBodySize = 1 << 20
+
+// JiraConnectHandler serves the Atlassian Connect app descriptor and installation lifecycle callbacks
+type JiraConnectHandler struct {
+	connectService services.JiraConnectService
+	logger         *zap.Logger
+}
+
+// NewJiraConnectHandler creates a new JiraConnectHandler
+func NewJiraConnectHandler(connectService services.JiraConnectService, logger *zap.Logger) *JiraConnectHandler {
+	return &JiraConnectHandler{
+		connectService: connectService,
+		logger:         logger,
+	}
+}
+
+// HandleDescriptor serves the app descriptor Jira reads when the app is installed
+func (h *JiraConnectHandler) HandleDescriptor(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.connectService.Descriptor()); err != nil {
+		h.logger.Error("Failed to write app descriptor", zap.Error(err))
+	}
+}
+
+// HandleInstalled handles the installed lifecycle callback
+func (h *JiraConnectHandler) HandleInstalled(w http.ResponseWriter, r *http.Request) {
+	payload, ok := h.decodeLifecycle(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.connectService.HandleInstalled(r, payload); err != nil {
+		h.logger.Warn("Rejected Jira Connect installation", zap.String("client_key", payload.ClientKey), zap.Error(err))
+		http.Error(w, "installation rejected", http.StatusUnauthorized)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleUninstalled handles the uninstalled lifecycle callback
+func (h *JiraConnectHandler) HandleUninstalled(w http.ResponseWriter, r *http.Request) {
+	payload, ok := h.decodeLifecycle(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.connectService.HandleUninstalled(r, payload); err != nil {
+		h.logger.Warn("Rejected Jira Connect uninstallation", zap.String("client_key", payload.ClientKey), zap.Error(err))
+		http.Error(w, "uninstallation rejected", http.StatusUnauthorized)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeLifecycle decodes a lifecycle payload, writing an error response when it is invalid
+func (h *JiraConnectHandler) decodeLifecycle(w http.ResponseWriter, r *http.Request) (*models.JiraConnectLifecycle, bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil, false
+	}
+
+	var payload models.JiraConnectLifecycle
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize)).Decode(&payload); err != nil {
+		h.logger.Warn("Invalid Jira Connect lifecycle payload", zap.Error(err))
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return nil, false
+	}
+
+	return &payload, true
+}
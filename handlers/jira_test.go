@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/services"
+
+	"go.uber.org/zap"
+)
+
+// fakeScanner records scan triggers
+type fakeScanner struct {
+	triggered int
+}
+
+func (f *fakeScanner) Start()          {}
+func (f *fakeScanner) Stop()           {}
+func (f *fakeScanner) IsRunning() bool { return true }
+func (f *fakeScanner) TriggerScan()    { f.triggered++ }
+func (f *fakeScanner) Status() services.ScannerStatus {
+	return services.ScannerStatus{Running: true, InFlight: []string{}}
+}
+
+func TestJiraWebhookHandler_HandleWebhook(t *testing.T) {
+	tests := []struct {
+		name             string
+		triggerMode      models.TriggerMode
+		body             string
+		verifyErr        error
+		maintenance      bool
+		expectedStatus   int
+		expectedTriggers int
+	}{
+		{
+			name:             "ticket moved to todo triggers scan",
+			body:             `{"webhookEvent": "jira:issue_updated", "issue": {"key": "TEST-1", "fields": {"status": {"name": "To Do"}}}}`,
+			expectedStatus:   http.StatusNoContent,
+			expectedTriggers: 1,
+		},
+		{
+			name:           "ticket in another status is ignored",
+			body:           `{"webhookEvent": "jira:issue_updated", "issue": {"key": "TEST-1", "fields": {"status": {"name": "Done"}}}}`,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:             "labelled ticket triggers scan in the label mode",
+			triggerMode:      models.TriggerModeLabel,
+			body:             `{"webhookEvent": "jira:issue_updated", "issue": {"key": "TEST-1", "fields": {"status": {"name": "Backlog"}, "labels": ["good-for-ai"]}}}`,
+			expectedStatus:   http.StatusNoContent,
+			expectedTriggers: 1,
+		},
+		{
+			name:           "ticket without the label is ignored in the label mode",
+			triggerMode:    models.TriggerModeLabel,
+			body:           `{"webhookEvent": "jira:issue_updated", "issue": {"key": "TEST-1", "fields": {"status": {"name": "To Do"}}}}`,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "labelled ticket in another status is ignored in the both mode",
+			triggerMode:    models.TriggerModeBoth,
+			body:           `{"webhookEvent": "jira:issue_updated", "issue": {"key": "TEST-1", "fields": {"status": {"name": "Backlog"}, "labels": ["good-for-ai"]}}}`,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "unrelated event is ignored",
+			body:           `{"webhookEvent": "comment_created", "issue": {"key": "TEST-1", "fields": {"status": {"name": "To Do"}}}}`,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "invalid JWT is rejected",
+			body:           `{"webhookEvent": "jira:issue_updated", "issue": {"key": "TEST-1", "fields": {"status": {"name": "To Do"}}}}`,
+			verifyErr:      errors.New("invalid JWT signature"),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "maintenance mode refuses webhooks",
+			body:           `{"webhookEvent": "jira:issue_updated", "issue": {"key": "TEST-1", "fields": {"status": {"name": "To Do"}}}}`,
+			maintenance:    true,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "malformed payload is rejected",
+			body:           `not json`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &models.Config{}
+			config.Jira.StatusTransitions.Todo = "To Do"
+			config.Jira.TriggerMode = tt.triggerMode
+			config.Jira.TriggerLabel = "good-for-ai"
+
+			scanner := &fakeScanner{}
+			connectService := &mocks.MockJiraConnectService{
+				VerifyRequestFunc: func(r *http.Request) (*models.JiraConnectInstallation, error) {
+					if tt.verifyErr != nil {
+						return nil, tt.verifyErr
+					}
+					return &models.JiraConnectInstallation{ClientKey: "client"}, nil
+				},
+			}
+
+			maintenance := services.NewMaintenanceService(nil, zap.NewNop())
+			if tt.maintenance {
+				maintenance.Enable("upgrade")
+			}
+
+			handler := NewJiraWebhookHandler(scanner, connectService, maintenance, config, zap.NewNop())
+			req := httptest.NewRequest(http.MethodPost, "/jira/webhook", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			handler.HandleWebhook(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if scanner.triggered != tt.expectedTriggers {
+				t.Errorf("Expected %d scan triggers, got %d", tt.expectedTriggers, scanner.triggered)
+			}
+		})
+	}
+}
+
+func TestJiraConnectHandler_HandleInstalled(t *testing.T) {
+	var installed *models.JiraConnectLifecycle
+	connectService := &mocks.MockJiraConnectService{
+		HandleInstalledFunc: func(r *http.Request, payload *models.JiraConnectLifecycle) error {
+			if payload.ClientKey == "rejected" {
+				return errors.New("invalid JWT signature")
+			}
+			installed = payload
+			return nil
+		},
+	}
+	handler := NewJiraConnectHandler(connectService, zap.NewNop())
+
+	body := `{"key": "ai-solver", "clientKey": "client", "sharedSecret": "secret", "baseUrl": "https://example.atlassian.net"}`
+	rec := httptest.NewRecorder()
+	handler.HandleInstalled(rec, httptest.NewRequest(http.MethodPost, "/jira/installed", strings.NewReader(body)))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, rec.Code)
+	}
+	if installed == nil || installed.SharedSecret != "secret" {
+		t.Errorf("Expected installation to be passed to the service, got %+v", installed)
+	}
+
+	body = `{"key": "ai-solver", "clientKey": "rejected"}`
+	rec = httptest.NewRecorder()
+	handler.HandleInstalled(rec, httptest.NewRequest(http.MethodPost, "/jira/installed", strings.NewReader(body)))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
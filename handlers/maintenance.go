@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/services"
+
+	"go.uber.org/zap"
+)
+
+// MaintenanceHandler exposes the maintenance mode to operators and to readiness probes
+type MaintenanceHandler struct {
+	maintenance services.MaintenanceService
+	config      *models.Config
+	logger      *zap.Logger
+}
+
+// NewMaintenanceHandler creates a new MaintenanceHandler
+func NewMaintenanceHandler(maintenance services.MaintenanceService, config *models.Config, logger *zap.Logger) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		maintenance: maintenance,
+		config:      config,
+		logger:      logger,
+	}
+}
+
+// maintenanceRequest is the body of a request enabling maintenance mode
+type maintenanceRequest struct {
+	Reason string `json:"reason"`
+}
+
+// HandleMaintenance reports the maintenance mode on GET, enables it on POST and disables it on DELETE.
+// Requests must carry the admin token as a bearer token.
+func (h *MaintenanceHandler) HandleMaintenance(w http.ResponseWriter, r *http.Request) {
+	if !adminAuthorized(r, h.config) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var request maintenanceRequest
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize)).Decode(&request); err != nil {
+				http.Error(w, "invalid payload", http.StatusBadRequest)
+				return
+			}
+		}
+		h.maintenance.Enable(request.Reason)
+	case http.MethodDelete:
+		h.maintenance.Disable()
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, h.maintenance.Status())
+}
+
+// HandleReady reports whether the application accepts new work; it doesn't in maintenance mode
+func (h *MaintenanceHandler) HandleReady(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status := h.maintenance.Status()
+	if status.Enabled {
+		setRetryAfter(w, h.config)
+		h.writeJSON(w, http.StatusServiceUnavailable, status)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, status)
+}
+
+// adminAuthorized reports whether the request carries the admin token. Without a configured token nobody is.
+func adminAuthorized(r *http.Request, config *models.Config) bool {
+	token := config.Server.AdminToken
+	provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return token != "" && ok && subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1
+}
+
+// writeJSON writes the value as a JSON response with the status code
+func (h *MaintenanceHandler) writeJSON(w http.ResponseWriter, statusCode int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		h.logger.Error("Failed to write maintenance status", zap.Error(err))
+	}
+}
+
+// setRetryAfter tells clients when to retry a request refused in maintenance mode
+func setRetryAfter(w http.ResponseWriter, config *models.Config) {
+	w.Header().Set("Retry-After", strconv.Itoa(config.Maintenance.RetryAfterSeconds))
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/services"
+
+	"go.uber.org/zap"
+)
+
+func TestMaintenanceHandler(t *testing.T) {
+	config := &models.Config{}
+	config.Server.AdminToken = "secret"
+	config.Maintenance.RetryAfterSeconds = 120
+
+	scanner := &fakeScanner{}
+	maintenance := services.NewMaintenanceService([]services.Pausable{scanner}, zap.NewNop())
+	handler := NewMaintenanceHandler(maintenance, config, zap.NewNop())
+
+	request := func(method, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/admin/maintenance", strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		handler.HandleMaintenance(rec, req)
+		return rec
+	}
+
+	if rec := request(http.MethodPost, "wrong", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected a wrong token to be rejected, got %d", rec.Code)
+	}
+	if maintenance.IsEnabled() {
+		t.Fatal("Expected an unauthorized request not to enable maintenance mode")
+	}
+
+	rec := request(http.MethodPost, "secret", `{"reason": "upgrade to 2.0"}`)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "upgrade to 2.0") {
+		t.Errorf("Expected maintenance mode to be enabled, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	ready := httptest.NewRecorder()
+	handler.HandleReady(ready, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if ready.Code != http.StatusServiceUnavailable || ready.Header().Get("Retry-After") != "120" {
+		t.Errorf("Expected not to be ready in maintenance mode, got %d with Retry-After %q", ready.Code, ready.Header().Get("Retry-After"))
+	}
+
+	if rec := request(http.MethodDelete, "secret", ""); rec.Code != http.StatusOK || maintenance.IsEnabled() {
+		t.Errorf("Expected maintenance mode to be disabled, got %d", rec.Code)
+	}
+	ready = httptest.NewRecorder()
+	handler.HandleReady(ready, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if ready.Code != http.StatusOK {
+		t.Errorf("Expected to be ready, got %d", ready.Code)
+	}
+}
+
+func TestMaintenanceHandler_WithoutToken(t *testing.T) {
+	maintenance := services.NewMaintenanceService(nil, zap.NewNop())
+	handler := NewMaintenanceHandler(maintenance, &models.Config{}, zap.NewNop())
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/maintenance", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	rec := httptest.NewRecorder()
+	handler.HandleMaintenance(rec, req)
+	if rec.Code != http.StatusUnauthorized || maintenance.IsEnabled() {
+		t.Errorf("Expected the admin API to be closed without a token, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+
+	"jira-ai-issue-solver/services"
+
+	"go.uber.org/zap"
+)
+
+// MetricsHandler exposes the application metrics to Prometheus
+type MetricsHandler struct {
+	metrics services.Metrics
+	logger  *zap.Logger
+}
+
+// NewMetricsHandler creates a new MetricsHandler
+func NewMetricsHandler(metrics services.Metrics, logger *zap.Logger) *MetricsHandler {
+	return &MetricsHandler{
+		metrics: metrics,
+		logger:  logger,
+	}
+}
+
+// HandleMetrics writes all metrics in the Prometheus text exposition format
+func (h *MetricsHandler) HandleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if err := h.metrics.WritePrometheus(w); err != nil {
+		h.logger.Error("Failed to write metrics", zap.Error(err))
+	}
+}
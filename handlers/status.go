@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"time"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/services"
+
+	"go.uber.org/zap"
+)
+
+// BuildInfo identifies the running build
+type BuildInfo struct {
+	Version    string `json:"version"`
+	Commit     string `json:"commit,omitempty"`
+	CommitTime string `json:"commit_time,omitempty"`
+	Modified   bool   `json:"modified,omitempty"` // The build had uncommitted changes
+	GoVersion  string `json:"go_version"`
+}
+
+// NewBuildInfo returns the build info of the running binary, with the version set at build time and the commit
+// recorded by the Go toolchain
+func NewBuildInfo(version string) BuildInfo {
+	info := BuildInfo{Version: version, GoVersion: runtime.Version()}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				info.Commit = setting.Value
+			case "vcs.time":
+				info.CommitTime = setting.Value
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+	return info
+}
+
+// ScannerStatusReporter is a background scanner reporting its live state
+type ScannerStatusReporter interface {
+	Status() services.ScannerStatus
+}
+
+// InFlightTicket is a ticket being processed and its pipeline phase
+type InFlightTicket struct {
+	Ticket  string             `json:"ticket"`
+	Scanner string             `json:"scanner"`
+	Phase   models.TicketState `json:"phase,omitempty"`
+	Since   *time.Time         `json:"since,omitempty"` // When the ticket entered the phase
+}
+
+// StatusResponse is the live state of the application
+type StatusResponse struct {
+	Build       BuildInfo                         `json:"build"`
+	Maintenance services.MaintenanceStatus        `json:"maintenance"`
+	Scanners    map[string]services.ScannerStatus `json:"scanners"`
+	QueueDepth  int                               `json:"queue_depth"` // In-flight tickets that haven't started being worked on
+	InFlight    []InFlightTicket                  `json:"in_flight"`
+}
+
+// StatusHandler exposes the live state of the scanners and the tickets being processed
+type StatusHandler struct {
+	build        BuildInfo
+	scanners     map[string]ScannerStatusReporter
+	stateMachine services.TicketStateMachine
+	maintenance  services.MaintenanceService
+	logger       *zap.Logger
+}
+
+// NewStatusHandler creates a new StatusHandler reporting the scanners by name
+func NewStatusHandler(
+	build BuildInfo,
+	scanners map[string]ScannerStatusReporter,
+	stateMachine services.TicketStateMachine,
+	maintenance services.MaintenanceService,
+	logger *zap.Logger,
+) *StatusHandler {
+	return &StatusHandler{
+		build:        build,
+		scanners:     scanners,
+		stateMachine: stateMachine,
+		maintenance:  maintenance,
+		logger:       logger,
+	}
+}
+
+// HandleStatus writes the build, the scanners' state and the in-flight tickets with their pipeline phase as JSON
+func (h *StatusHandler) HandleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	response := StatusResponse{
+		Build:       h.build,
+		Maintenance: h.maintenance.Status(),
+		Scanners:    make(map[string]services.ScannerStatus),
+		InFlight:    []InFlightTicket{},
+	}
+	records := make(map[string]models.TicketStateRecord)
+	for _, record := range h.stateMachine.Records() {
+		records[record.Ticket] = record
+	}
+	for name, scanner := range h.scanners {
+		status := scanner.Status()
+		response.Scanners[name] = status
+		response.QueueDepth += status.Queued
+		for _, ticketKey := range status.InFlight {
+			ticket := InFlightTicket{Ticket: ticketKey, Scanner: name}
+			if record, ok := records[ticketKey]; ok {
+				ticket.Phase, ticket.Since = record.State, &record.UpdatedAt
+			}
+			response.InFlight = append(response.InFlight, ticket)
+		}
+	}
+	sort.Slice(response.InFlight, func(i, j int) bool {
+		if response.InFlight[i].Ticket != response.InFlight[j].Ticket {
+			return response.InFlight[i].Ticket < response.InFlight[j].Ticket
+		}
+		return response.InFlight[i].Scanner < response.InFlight[j].Scanner
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error("Failed to write status", zap.Error(err))
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/services"
+
+	"go.uber.org/zap"
+)
+
+// fakeStatusScanner reports a fixed status
+type fakeStatusScanner struct {
+	status services.ScannerStatus
+}
+
+func (f *fakeStatusScanner) Status() services.ScannerStatus { return f.status }
+
+func TestStatusHandler_HandleStatus(t *testing.T) {
+	stateMachine, err := services.NewTicketStateMachine(&models.Config{}, services.NewMetrics(), zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, state := range []models.TicketState{models.TicketStateQueued, models.TicketStateCloning} {
+		if err := stateMachine.Transition("TEST-1", state, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	maintenance := services.NewMaintenanceService(nil, zap.NewNop())
+	handler := NewStatusHandler(BuildInfo{Version: "1.2.3"}, map[string]ScannerStatusReporter{
+		"jira_issues": &fakeStatusScanner{status: services.ScannerStatus{Running: true, InFlight: []string{"TEST-1"}}},
+		"pr_feedback": &fakeStatusScanner{status: services.ScannerStatus{Running: true, InFlight: []string{"TEST-0", "TEST-9"}, Queued: 1}},
+	}, stateMachine, maintenance, zap.NewNop())
+
+	rec := httptest.NewRecorder()
+	handler.HandleStatus(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+
+	var response StatusResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Build.Version != "1.2.3" || !response.Scanners["jira_issues"].Running || len(response.Scanners) != 2 {
+		t.Errorf("Unexpected build or scanners: %+v", response)
+	}
+	if response.QueueDepth != 1 {
+		t.Errorf("Expected a queue depth of 1, got %d", response.QueueDepth)
+	}
+	if len(response.InFlight) != 3 {
+		t.Fatalf("Expected three in-flight tickets, got %+v", response.InFlight)
+	}
+	if ticket := response.InFlight[1]; ticket.Ticket != "TEST-1" || ticket.Scanner != "jira_issues" || ticket.Phase != models.TicketStateCloning || ticket.Since == nil {
+		t.Errorf("Expected TEST-1 to be cloning, got %+v", ticket)
+	}
+	if ticket := response.InFlight[0]; ticket.Ticket != "TEST-0" || ticket.Phase != "" {
+		t.Errorf("Expected an untracked ticket without phase, got %+v", ticket)
+	}
+}
+
+func TestStatusHandler_MethodNotAllowed(t *testing.T) {
+	handler := NewStatusHandler(BuildInfo{}, nil, nil, nil, zap.NewNop())
+	rec := httptest.NewRecorder()
+	handler.HandleStatus(rec, httptest.NewRequest(http.MethodPost, "/status", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", rec.Code)
+	}
+}
+
+func TestNewBuildInfo(t *testing.T) {
+	info := NewBuildInfo("1.2.3")
+	if info.Version != "1.2.3" || info.GoVersion == "" {
+		t.Errorf("Expected the version and Go version, got %+v", info)
+	}
+}
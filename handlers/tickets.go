@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"jira-ai-issue-solver/services"
+
+	"go.uber.org/zap"
+)
+
+// TicketsHandler exposes the pipeline state of the tracked tickets
+type TicketsHandler struct {
+	stateMachine services.TicketStateMachine
+	logger       *zap.Logger
+}
+
+// NewTicketsHandler creates a new TicketsHandler
+func NewTicketsHandler(stateMachine services.TicketStateMachine, logger *zap.Logger) *TicketsHandler {
+	return &TicketsHandler{
+		stateMachine: stateMachine,
+		logger:       logger,
+	}
+}
+
+// HandleTickets writes the state records of all tracked tickets as JSON.
+// A ticket query parameter limits the response to a single ticket.
+func (h *TicketsHandler) HandleTickets(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	records := h.stateMachine.Records()
+	if ticketKey := r.URL.Query().Get("ticket"); ticketKey != "" {
+		for _, record := range records {
+			if record.Ticket == ticketKey {
+				h.writeJSON(w, record)
+				return
+			}
+		}
+		http.Error(w, "ticket not found", http.StatusNotFound)
+		return
+	}
+
+	h.writeJSON(w, records)
+}
+
+// writeJSON writes the value as a JSON response
+func (h *TicketsHandler) writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		h.logger.Error("Failed to write ticket states", zap.Error(err))
+	}
+}
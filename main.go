@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 	"syscall"
 	"time"
 
+	"jira-ai-issue-solver/handlers"
 	"jira-ai-issue-solver/models"
 	"jira-ai-issue-solver/services"
 
@@ -19,8 +21,11 @@ import (
 
 var Logger *zap.Logger
 
-// InitLogger initializes the global logger with appropriate configuration
-func InitLogger(config *models.Config) {
+// version is the release of the build, set with -ldflags "-X main.version=<version>"
+var version = "dev"
+
+// InitLogger initializes the global logger with appropriate configuration, writing to the given output
+func InitLogger(config *models.Config, output *os.File) {
 	// Get log level from config
 	level := getLogLevel(config.Logging.Level)
 
@@ -38,19 +43,19 @@ func InitLogger(config *models.Config) {
 		encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
 	}
 
-	// Create core based on format
+	// Create core based on format; secrets are masked in every entry
 	var core zapcore.Core
 	if config.Logging.Format == models.LogFormatJSON {
 		core = zapcore.NewCore(
 			zapcore.NewJSONEncoder(encoderConfig),
-			zapcore.AddSync(os.Stdout),
+			services.NewRedactingWriteSyncer(zapcore.AddSync(output), config),
 			level,
 		)
 	} else {
 		// Console format (default)
 		core = zapcore.NewCore(
 			zapcore.NewConsoleEncoder(encoderConfig),
-			zapcore.AddSync(os.Stdout),
+			services.NewRedactingWriteSyncer(zapcore.AddSync(output), config),
 			level,
 		)
 	}
@@ -75,7 +80,24 @@ func getLogLevel(level models.LogLevel) zapcore.Level {
 	}
 }
 
+// limitConcurrentRuns caps the CLI processes of an AI provider running at once if max_concurrent_runs is set
+func limitConcurrentRuns(provider string, aiService services.AIService, config *models.Config, metrics services.Metrics) services.AIService {
+	maxRuns := config.GetMaxConcurrentRuns(provider)
+	if maxRuns == 0 {
+		return aiService
+	}
+	Logger.Info("Limiting concurrent AI runs", zap.String("provider", provider), zap.Int("max_concurrent_runs", maxRuns))
+	return services.NewConcurrencyLimitedAIService(provider, aiService, maxRuns, metrics, Logger)
+}
+
 func main() {
+	// Subcommands run once and exit instead of starting the server
+	if len(os.Args) > 1 {
+		if run, ok := commands[os.Args[1]]; ok {
+			os.Exit(run(os.Args[2:]))
+		}
+	}
+
 	// Parse command line flags
 	configPath := flag.String("config", "config.yaml", "Path to configuration file")
 	flag.Parse()
@@ -88,62 +110,176 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Keep the configuration as loaded to tell the changes of reloaded files apart from startup changes
+	loadedConfig := *config
+
 	// Initialize logger
-	InitLogger(config)
+	InitLogger(config, os.Stdout)
 	defer Logger.Sync()
 
-	// Validate required configuration
-	if config.Jira.BaseURL == "" {
-		Logger.Fatal("JIRA_BASE_URL is required")
-	}
-	if config.Jira.Username == "" {
-		Logger.Fatal("JIRA_USERNAME is required")
+	// Read the secrets kept in Vault
+	if err := services.ResolveSecrets(context.Background(), config, Logger); err != nil {
+		Logger.Fatal("Failed to resolve secrets", zap.Error(err))
 	}
-	if config.Jira.APIToken == "" {
-		Logger.Fatal("JIRA_API_TOKEN is required")
+
+	// Validate required configuration
+	for _, setting := range config.MissingSettings() {
+		Logger.Fatal("Required configuration is missing",
+			zap.String("setting", setting),
+			zap.String("env", models.EnvVarName(setting)))
 	}
-	if config.GitHub.PersonalAccessToken == "" {
-		Logger.Fatal("GITHUB_PERSONAL_ACCESS_TOKEN is required")
+	if config.GitHub.AuthMode == models.GitHubAuthModeApp {
+		// Fail fast on an unreadable or malformed private key instead of on the first GitHub call
+		if _, err := services.NewGitHubAppService(config, Logger); err != nil {
+			Logger.Fatal("Invalid GitHub App configuration", zap.Error(err))
+		}
 	}
-	if config.GitHub.BotUsername == "" {
-		Logger.Fatal("GITHUB_BOT_USERNAME is required")
+	if config.GitHub.Signing.Format != models.CommitSigningNone {
+		if _, err := os.Stat(config.GitHub.Signing.KeyFile); err != nil {
+			Logger.Fatal("Commit signing key is not readable", zap.String("key_file", config.GitHub.Signing.KeyFile), zap.Error(err))
+		}
 	}
-	if config.GitHub.BotEmail == "" {
-		Logger.Fatal("GITHUB_BOT_EMAIL is required")
+
+	// Canceling the application context on shutdown aborts the git commands, AI runs and API requests in flight
+	appCtx, cancelApp := context.WithCancel(context.Background())
+	defer cancelApp()
+
+	// Create services
+	metrics := services.NewMetrics()
+	var jiraService services.JiraService
+	var jiraConnectService services.JiraConnectService
+	if config.Jira.AuthMode == models.JiraAuthModeConnect {
+		jiraConnectService, err = services.NewJiraConnectService(config, Logger)
+		if err != nil {
+			Logger.Fatal("Failed to initialize Jira Connect app", zap.Error(err))
+		}
+		jiraService = services.NewConnectJiraService(config, jiraConnectService, metrics, Logger)
+		Logger.Info("Authenticating with Jira as a Connect app", zap.String("app_key", config.Jira.Connect.AppKey))
+	} else {
+		jiraService = services.NewJiraService(config, metrics, Logger)
+		// A misconfigured token fails the startup instead of every Jira request
+		tokenType, err := jiraService.DetectTokenType(appCtx)
+		if errors.Is(err, services.ErrJiraAuthRejected) {
+			Logger.Fatal("Failed to authenticate with Jira", zap.Error(err))
+		} else if err != nil {
+			Logger.Warn("Failed to check the Jira API token, authenticating by the base URL", zap.Error(err))
+		} else {
+			Logger.Info("Authenticating with Jira", zap.String("token_type", string(tokenType)))
+		}
 	}
-	if len(config.ComponentToRepo) == 0 {
-		Logger.Fatal("At least one component_to_repo mapping is required")
+	githubService, err := services.NewGitHubService(config, metrics, Logger)
+	if err != nil {
+		Logger.Fatal("Failed to create GitHub service", zap.Error(err))
 	}
+	// Refuse to push commits containing secrets
+	githubService = services.NewSecretScanningGitHubService(githubService, config, Logger)
 
-	// Create services
-	jiraService := services.NewJiraService(config)
-	githubService := services.NewGitHubService(config, Logger)
+	// Record every change made in Jira and on GitHub in the audit log, pushes blocked by the secret scan included
+	jiraService = services.NewAuditedJiraService(jiraService, config, Logger)
+	githubService = services.NewAuditedGitHubService(githubService, config, Logger)
+
+	// Install the pinned AI CLI versions, replacing the configured CLI paths
+	if config.CLIBootstrap.Enabled {
+		bootstrapper := services.NewCLIBootstrapper(config, Logger)
+		for _, provider := range config.AIProviders() {
+			cliPath, err := bootstrapper.Bootstrap(provider)
+			if err != nil {
+				Logger.Fatal("Failed to bootstrap AI CLI", zap.String("provider", provider), zap.Error(err))
+			}
+			if provider == "gemini" {
+				config.Gemini.CLIPath = cliPath
+			} else {
+				config.Claude.CLIPath = cliPath
+			}
+		}
+	}
 
 	// Create AI service based on provider selection
-	var aiService services.AIService
-	switch config.AIProvider {
-	case "claude":
-		aiService = services.NewClaudeService(config, Logger)
-		Logger.Info("Using Claude AI service")
-	case "gemini":
-		aiService = services.NewGeminiService(config, Logger)
-		Logger.Info("Using Gemini AI service")
-	default:
+	aiService, err := services.NewAIService(config.AIProvider, config, Logger)
+	if err != nil {
 		Logger.Fatal("Unsupported AI provider", zap.String("provider", config.AIProvider))
 	}
+	aiService = limitConcurrentRuns(config.AIProvider, aiService, config, metrics)
+	Logger.Info("Using AI service", zap.String("provider", config.AIProvider))
 
-	jiraIssueScannerService := services.NewJiraIssueScannerService(jiraService, githubService, aiService, config, Logger)
-	prFeedbackScannerService := services.NewPRFeedbackScannerService(jiraService, githubService, aiService, config, Logger)
+	// Fall back to a secondary provider when the primary one is unavailable
+	if config.AIFallbackProvider != "" {
+		fallbackService, err := services.NewAIService(config.AIFallbackProvider, config, Logger)
+		if err != nil {
+			Logger.Fatal("Unsupported AI fallback provider", zap.String("provider", config.AIFallbackProvider))
+		}
+		fallbackService = limitConcurrentRuns(config.AIFallbackProvider, fallbackService, config, metrics)
+		aiService = services.NewFailoverAIService(config.AIProvider, aiService, config.AIFallbackProvider, fallbackService, metrics, Logger)
+		Logger.Info("Using AI fallback service", zap.String("provider", config.AIFallbackProvider))
+	}
 
-	// Start the Jira issue scanner service for periodic ticket scanning
-	Logger.Info("Starting Jira issue scanner service...")
-	jiraIssueScannerService.Start()
+	// Fail AI runs that modified files outside the repository checkout
+	if config.WorkspaceGuard.Enabled {
+		aiService = services.NewWorkspaceGuardAIService(aiService, config, metrics, Logger)
+		Logger.Info("Guarding files outside the repository checkout during AI runs")
+	}
 
-	// Start the PR feedback scanner service for processing PR review feedback
-	Logger.Info("Starting PR feedback scanner service...")
-	prFeedbackScannerService.Start()
+	// Track tickets through the pipeline states so interrupted tickets resume after a restart
+	stateMachine, err := services.NewTicketStateMachine(config, metrics, Logger)
+	if err != nil {
+		Logger.Fatal("Failed to load ticket states", zap.Error(err))
+	}
+
+	jiraIssueScannerService := services.NewJiraIssueScannerService(appCtx, jiraService, githubService, aiService, stateMachine, config, Logger)
+	prFeedbackScannerService := services.NewPRFeedbackScannerService(appCtx, jiraService, githubService, aiService, stateMachine, config, Logger)
+
+	// Reset tickets stuck in progress without a pull request
+	var janitorService services.JanitorService
+	backgroundServices := []services.Pausable{jiraIssueScannerService, prFeedbackScannerService}
+	if config.Janitor.Enabled {
+		janitorService = services.NewJanitorService(appCtx, jiraService, stateMachine, config, Logger)
+		backgroundServices = append(backgroundServices, janitorService)
+	}
+
+	// Delete the bot's branches whose pull requests were closed long ago
+	var branchCleanupService services.BranchCleanupService
+	if config.BranchCleanup.Enabled {
+		branchCleanupService = services.NewBranchCleanupService(appCtx, githubService, config, Logger)
+		backgroundServices = append(backgroundServices, branchCleanupService)
+	}
+
+	// Write the cost report of every closed period for chargeback
+	costReporter := services.NewCostReporter(config, Logger)
+	if config.CostReports.ExportDir != "" {
+		costReporter.Start()
+	}
+
+	// Maintenance mode stops the background services from picking up new work
+	maintenanceService := services.NewMaintenanceService(backgroundServices, Logger)
+	if config.Maintenance.Enabled {
+		maintenanceService.Enable("maintenance.enabled is set in the configuration")
+	} else {
+		// Start the Jira issue scanner service for periodic ticket scanning
+		Logger.Info("Starting Jira issue scanner service...")
+		jiraIssueScannerService.Start()
+
+		// Start the PR feedback scanner service for processing PR review feedback
+		Logger.Info("Starting PR feedback scanner service...")
+		prFeedbackScannerService.Start()
 
-	// Create HTTP server (simplified for health checks only)
+		if janitorService != nil {
+			Logger.Info("Starting janitor service...")
+			janitorService.Start()
+		}
+
+		if branchCleanupService != nil {
+			Logger.Info("Starting branch cleanup service...")
+			branchCleanupService.Start()
+		}
+	}
+
+	// Apply changes of the configuration file without a restart
+	configReloader := services.NewConfigReloader(*configPath, config, &loadedConfig, maintenanceService, metrics, Logger)
+	if config.ConfigReload.Watch {
+		configReloader.Start()
+	}
+
+	// Create HTTP server
 	mux := http.NewServeMux()
 
 	// Add a health check endpoint
@@ -155,6 +291,41 @@ func main() {
 		}
 	})
 
+	// Check the Jira credentials, the GitHub token and the AI CLIs
+	healthHandler := handlers.NewHealthHandler(services.NewHealthChecker(jiraService, githubService, config, Logger), Logger)
+	mux.HandleFunc("/health/ready", healthHandler.HandleReady)
+
+	// Report readiness, and let operators toggle the maintenance mode
+	maintenanceHandler := handlers.NewMaintenanceHandler(maintenanceService, config, Logger)
+	mux.HandleFunc("/readyz", maintenanceHandler.HandleReady)
+	if config.Server.AdminToken != "" {
+		mux.HandleFunc("/admin/maintenance", maintenanceHandler.HandleMaintenance)
+		mux.HandleFunc("/admin/costs", handlers.NewCostReportHandler(costReporter, config, Logger).HandleCosts)
+	}
+
+	// Expose metrics in the Prometheus text format
+	mux.HandleFunc("/metrics", handlers.NewMetricsHandler(metrics, Logger).HandleMetrics)
+
+	// Expose the live state of the scanners and the tickets being processed
+	statusHandler := handlers.NewStatusHandler(handlers.NewBuildInfo(version), map[string]handlers.ScannerStatusReporter{
+		"jira_issues": jiraIssueScannerService,
+		"pr_feedback": prFeedbackScannerService,
+	}, stateMachine, maintenanceService, Logger)
+	mux.HandleFunc("/status", statusHandler.HandleStatus)
+
+	// Expose the pipeline state of each ticket for dashboards
+	mux.HandleFunc("/tickets", handlers.NewTicketsHandler(stateMachine, Logger).HandleTickets)
+
+	// Serve the Connect app descriptor, installation lifecycle and webhooks
+	if jiraConnectService != nil {
+		connectHandler := handlers.NewJiraConnectHandler(jiraConnectService, Logger)
+		webhookHandler := handlers.NewJiraWebhookHandler(jiraIssueScannerService, jiraConnectService, maintenanceService, config, Logger)
+		mux.HandleFunc(services.JiraConnectDescriptorPath, connectHandler.HandleDescriptor)
+		mux.HandleFunc(services.JiraConnectInstalledPath, connectHandler.HandleInstalled)
+		mux.HandleFunc(services.JiraConnectUninstalledPath, connectHandler.HandleUninstalled)
+		mux.HandleFunc(services.JiraConnectWebhookPath, webhookHandler.HandleWebhook)
+	}
+
 	// Create server
 	server := &http.Server{
 		Addr:    fmt.Sprintf(":%d", config.Server.Port),
@@ -169,15 +340,37 @@ func main() {
 		}
 	}()
 
-	// Wait for interrupt signal
+	// Reload the configuration on SIGHUP until an interrupt signal
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
-	<-stop
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+wait:
+	for {
+		select {
+		case <-hangup:
+			Logger.Info("Reloading configuration...")
+			if err := configReloader.Reload(); err != nil {
+				Logger.Error("Rejected configuration change", zap.Error(err))
+			}
+		case <-stop:
+			break wait
+		}
+	}
 
-	// Gracefully shutdown the scanner services
+	// Gracefully shutdown the scanner services. Interrupted tickets resume on the next start.
 	Logger.Info("Shutting down scanner services...")
+	configReloader.Stop()
+	cancelApp()
 	jiraIssueScannerService.Stop()
 	prFeedbackScannerService.Stop()
+	if janitorService != nil {
+		janitorService.Stop()
+	}
+	if branchCleanupService != nil {
+		branchCleanupService.Stop()
+	}
+	costReporter.Stop()
 
 	// Gracefully shutdown the server
 	Logger.Info("Shutting down server...")
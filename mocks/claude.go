@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"context"
 	"fmt"
 	"jira-ai-issue-solver/models"
 	"os"
@@ -14,7 +15,7 @@ type MockClaudeService struct {
 }
 
 // GenerateCode is the mock implementation of ClaudeService's GenerateCode method
-func (m *MockClaudeService) GenerateCode(prompt string, repoDir string) (interface{}, error) {
+func (m *MockClaudeService) GenerateCode(ctx context.Context, prompt string, repoDir string) (interface{}, error) {
 	if m.GenerateCodeFunc != nil {
 		return m.GenerateCodeFunc(prompt, repoDir)
 	}
@@ -54,7 +55,7 @@ The implementation includes comprehensive unit tests that cover all edge cases.`
 }
 
 // GenerateDocumentation is the mock implementation of ClaudeService's GenerateDocumentation method
-func (m *MockClaudeService) GenerateDocumentation(repoDir string) error {
+func (m *MockClaudeService) GenerateDocumentation(ctx context.Context, repoDir string) error {
 	// Create a mock CLAUDE.md file
 	claudePath := filepath.Join(repoDir, "CLAUDE.md")
 	content := `# CLAUDE.md
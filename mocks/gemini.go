@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"context"
 	"fmt"
 	"jira-ai-issue-solver/models"
 	"os"
@@ -14,7 +15,7 @@ type MockGeminiService struct {
 }
 
 // GenerateCode is the mock implementation of GeminiService's GenerateCode method
-func (m *MockGeminiService) GenerateCode(prompt string, repoDir string) (interface{}, error) {
+func (m *MockGeminiService) GenerateCode(ctx context.Context, prompt string, repoDir string) (interface{}, error) {
 	if m.GenerateCodeFunc != nil {
 		return m.GenerateCodeFunc(prompt, repoDir)
 	}
@@ -55,7 +56,7 @@ The implementation includes comprehensive unit tests that cover all edge cases.`
 }
 
 // GenerateDocumentation is the mock implementation of GeminiService's GenerateDocumentation method
-func (m *MockGeminiService) GenerateDocumentation(repoDir string) error {
+func (m *MockGeminiService) GenerateDocumentation(ctx context.Context, repoDir string) error {
 	// Create a mock GEMINI.md file
 	geminiPath := filepath.Join(repoDir, "GEMINI.md")
 	content := `# GEMINI.md
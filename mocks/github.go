@@ -1,47 +1,84 @@
 package mocks
 
 import (
+	"context"
 	"jira-ai-issue-solver/models"
 )
 
 // MockGitHubService is a mock implementation of the GitHubService interface
 type MockGitHubService struct {
-	CloneRepositoryFunc      func(repoURL, directory string) error
-	CreateBranchFunc         func(directory, branchName string) error
-	CommitChangesFunc        func(directory, message string) error
-	PushChangesFunc          func(directory, branchName string) error
-	CreatePullRequestFunc    func(owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error)
-	ForkRepositoryFunc       func(owner, repo string) (string, error)
-	CheckForkExistsFunc      func(owner, repo string) (exists bool, cloneURL string, err error)
-	ResetForkFunc            func(forkCloneURL, directory string) error
-	SyncForkWithUpstreamFunc func(owner, repo string) error
-	SwitchToTargetBranchFunc func(directory string) error
-	SwitchToBranchFunc       func(directory, branchName string) error
-	PullChangesFunc          func(directory, branchName string) error
-	AddPRCommentFunc         func(owner, repo string, prNumber int, body string) error
-	ListPRCommentsFunc       func(owner, repo string, prNumber int) ([]models.GitHubPRComment, error)
-	GetPRDetailsFunc         func(owner, repo string, prNumber int) (*models.GitHubPRDetails, error)
-	ListPRReviewsFunc        func(owner, repo string, prNumber int) ([]models.GitHubReview, error)
+	CloneRepositoryFunc         func(repoURL, directory string, opts models.CloneOptions) error
+	CreateBranchFunc            func(directory, branchName, baseBranch string) error
+	CommitChangesFunc           func(directory, message string) error
+	PushChangesFunc             func(directory, branchName string) error
+	CreatePullRequestFunc       func(owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error)
+	CreateDraftPullRequestFunc  func(owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error)
+	MarkPullRequestReadyFunc    func(owner, repo string, prNumber int) error
+	ForkRepositoryFunc          func(owner, repo string) (string, error)
+	CheckForkExistsFunc         func(owner, repo string) (exists bool, cloneURL string, err error)
+	ResetForkFunc               func(forkCloneURL, directory string) error
+	SyncForkWithUpstreamFunc    func(owner, repo, branch string) error
+	GetDefaultBranchFunc        func(owner, repo string) (string, error)
+	SwitchToTargetBranchFunc    func(directory, targetBranch string) error
+	SwitchToBranchFunc          func(directory, branchName string) error
+	PullChangesFunc             func(directory, branchName string) error
+	ApplyPatchFunc              func(directory, patch string) error
+	AddPRCommentFunc            func(owner, repo string, prNumber int, body string) error
+	ListPRCommentsFunc          func(owner, repo string, prNumber int) ([]models.GitHubPRComment, error)
+	GetPRDetailsFunc            func(owner, repo string, prNumber int) (*models.GitHubPRDetails, error)
+	ListPRReviewsFunc           func(owner, repo string, prNumber int) ([]models.GitHubReview, error)
+	RequestReviewersFunc        func(owner, repo string, prNumber int, reviewers, teamReviewers []string) error
+	AddLabelsFunc               func(owner, repo string, prNumber int, labels []string) error
+	SetMilestoneFunc            func(owner, repo string, prNumber int, title string) error
+	AddToProjectFunc            func(projectOwner string, projectNumber int, contentID, field, column string) error
+	ListBranchesFunc            func(owner, repo string) ([]models.GitHubBranch, error)
+	ListPullRequestsByHeadFunc  func(owner, repo, head string) ([]models.GitHubPRDetails, error)
+	DeleteBranchFunc            func(owner, repo, branch string) error
+	CountOpenReviewRequestsFunc func(username string) (int, error)
+	ListPRFilesFunc             func(owner, repo string, prNumber int) ([]models.GitHubPRFile, error)
+	CommitFilesFunc             func(directory, message string, files []string) error
+	CountChangedLinesFunc       func(directory string) (int, error)
+	CreateBranchFromHeadFunc    func(directory, branchName string) error
+	CreateWorktreeFunc          func(repoURL, directory, branch, startPoint string, opts models.CloneOptions) error
+	RemoveWorktreeFunc          func(repoURL, directory string) error
+	ListPRReviewCommentsFunc    func(owner, repo string, prNumber int) ([]models.GitHubPRComment, error)
+	ReplyToReviewCommentFunc    func(owner, repo string, prNumber int, commentID int64, body string) error
+	ListCheckRunsFunc           func(owner, repo, ref string) ([]models.GitHubCheckRun, error)
+	GetCombinedStatusFunc       func(owner, repo, ref string) (*models.GitHubCombinedStatus, error)
+	GetJobLogsFunc              func(owner, repo string, jobID int64) (string, error)
+	RebaseOntoFunc              func(directory, owner, repo, branch string) ([]string, error)
+	ContinueRebaseFunc          func(directory string) ([]string, error)
+	AbortRebaseFunc             func(directory string) error
+	ForcePushChangesFunc        func(directory, branchName string) error
+	HasChangesFunc              func(directory string) (bool, error)
+	ChangedFilesFunc            func(directory string) ([]string, error)
+	DiffStatsFunc               func(directory string) (*models.DiffStats, error)
+	OutgoingPatchFunc           func(directory, branchName string) (string, error)
+	RevertProtectedPathsFunc    func(directory string, patterns []string) ([]string, error)
+	RelatedCommitsFunc          func(directory string, keywords []string, maxCommits, maxDiffLines int) ([]models.GitCommit, error)
+	MergePullRequestFunc        func(owner, repo string, prNumber int, sha string, method models.MergeMethod) (*models.GitHubMergePRResponse, error)
+	SetCommitAuthorFunc         func(directory string, author *models.GitIdentity)
+	GetTokenScopesFunc          func() ([]string, error)
 }
 
 // CloneRepository is the mock implementation of GitHubService's CloneRepository method
-func (m *MockGitHubService) CloneRepository(repoURL, directory string) error {
+func (m *MockGitHubService) CloneRepository(ctx context.Context, repoURL, directory string, opts models.CloneOptions) error {
 	if m.CloneRepositoryFunc != nil {
-		return m.CloneRepositoryFunc(repoURL, directory)
+		return m.CloneRepositoryFunc(repoURL, directory, opts)
 	}
 	return nil
 }
 
 // CreateBranch is the mock implementation of GitHubService's CreateBranch method
-func (m *MockGitHubService) CreateBranch(directory, branchName string) error {
+func (m *MockGitHubService) CreateBranch(ctx context.Context, directory, branchName, baseBranch string) error {
 	if m.CreateBranchFunc != nil {
-		return m.CreateBranchFunc(directory, branchName)
+		return m.CreateBranchFunc(directory, branchName, baseBranch)
 	}
 	return nil
 }
 
 // CommitChanges is the mock implementation of GitHubService's CommitChanges method
-func (m *MockGitHubService) CommitChanges(directory, message string) error {
+func (m *MockGitHubService) CommitChanges(ctx context.Context, directory, message string) error {
 	if m.CommitChangesFunc != nil {
 		return m.CommitChangesFunc(directory, message)
 	}
@@ -49,7 +86,7 @@ func (m *MockGitHubService) CommitChanges(directory, message string) error {
 }
 
 // PushChanges is the mock implementation of GitHubService's PushChanges method
-func (m *MockGitHubService) PushChanges(directory, branchName string) error {
+func (m *MockGitHubService) PushChanges(ctx context.Context, directory, branchName string) error {
 	if m.PushChangesFunc != nil {
 		return m.PushChangesFunc(directory, branchName)
 	}
@@ -57,15 +94,31 @@ func (m *MockGitHubService) PushChanges(directory, branchName string) error {
 }
 
 // CreatePullRequest is the mock implementation of GitHubService's CreatePullRequest method
-func (m *MockGitHubService) CreatePullRequest(owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error) {
+func (m *MockGitHubService) CreatePullRequest(ctx context.Context, owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error) {
 	if m.CreatePullRequestFunc != nil {
 		return m.CreatePullRequestFunc(owner, repo, title, body, head, base)
 	}
 	return nil, nil
 }
 
+// CreateDraftPullRequest is the mock implementation of GitHubService's CreateDraftPullRequest method
+func (m *MockGitHubService) CreateDraftPullRequest(ctx context.Context, owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error) {
+	if m.CreateDraftPullRequestFunc != nil {
+		return m.CreateDraftPullRequestFunc(owner, repo, title, body, head, base)
+	}
+	return nil, nil
+}
+
+// MarkPullRequestReady is the mock implementation of GitHubService's MarkPullRequestReady method
+func (m *MockGitHubService) MarkPullRequestReady(ctx context.Context, owner, repo string, prNumber int) error {
+	if m.MarkPullRequestReadyFunc != nil {
+		return m.MarkPullRequestReadyFunc(owner, repo, prNumber)
+	}
+	return nil
+}
+
 // ForkRepository is the mock implementation of GitHubService's ForkRepository method
-func (m *MockGitHubService) ForkRepository(owner, repo string) (string, error) {
+func (m *MockGitHubService) ForkRepository(ctx context.Context, owner, repo string) (string, error) {
 	if m.ForkRepositoryFunc != nil {
 		return m.ForkRepositoryFunc(owner, repo)
 	}
@@ -73,7 +126,7 @@ func (m *MockGitHubService) ForkRepository(owner, repo string) (string, error) {
 }
 
 // CheckForkExists is the mock implementation of GitHubService's CheckForkExists method
-func (m *MockGitHubService) CheckForkExists(owner, repo string) (exists bool, cloneURL string, err error) {
+func (m *MockGitHubService) CheckForkExists(ctx context.Context, owner, repo string) (exists bool, cloneURL string, err error) {
 	if m.CheckForkExistsFunc != nil {
 		return m.CheckForkExistsFunc(owner, repo)
 	}
@@ -81,7 +134,7 @@ func (m *MockGitHubService) CheckForkExists(owner, repo string) (exists bool, cl
 }
 
 // ResetFork is the mock implementation of GitHubService's ResetFork method
-func (m *MockGitHubService) ResetFork(forkCloneURL, directory string) error {
+func (m *MockGitHubService) ResetFork(ctx context.Context, forkCloneURL, directory string) error {
 	if m.ResetForkFunc != nil {
 		return m.ResetForkFunc(forkCloneURL, directory)
 	}
@@ -89,23 +142,31 @@ func (m *MockGitHubService) ResetFork(forkCloneURL, directory string) error {
 }
 
 // SyncForkWithUpstream is the mock implementation of GitHubService's SyncForkWithUpstream method
-func (m *MockGitHubService) SyncForkWithUpstream(owner, repo string) error {
+func (m *MockGitHubService) SyncForkWithUpstream(ctx context.Context, owner, repo, branch string) error {
 	if m.SyncForkWithUpstreamFunc != nil {
-		return m.SyncForkWithUpstreamFunc(owner, repo)
+		return m.SyncForkWithUpstreamFunc(owner, repo, branch)
 	}
 	return nil
 }
 
+// GetDefaultBranch is the mock implementation of GitHubService's GetDefaultBranch method
+func (m *MockGitHubService) GetDefaultBranch(ctx context.Context, owner, repo string) (string, error) {
+	if m.GetDefaultBranchFunc != nil {
+		return m.GetDefaultBranchFunc(owner, repo)
+	}
+	return "", nil
+}
+
 // SwitchToTargetBranch is the mock implementation of GitHubService's SwitchToTargetBranch method
-func (m *MockGitHubService) SwitchToTargetBranch(directory string) error {
+func (m *MockGitHubService) SwitchToTargetBranch(ctx context.Context, directory, targetBranch string) error {
 	if m.SwitchToTargetBranchFunc != nil {
-		return m.SwitchToTargetBranchFunc(directory)
+		return m.SwitchToTargetBranchFunc(directory, targetBranch)
 	}
 	return nil
 }
 
 // SwitchToBranch is the mock implementation of GitHubService's SwitchToBranch method
-func (m *MockGitHubService) SwitchToBranch(directory, branchName string) error {
+func (m *MockGitHubService) SwitchToBranch(ctx context.Context, directory, branchName string) error {
 	if m.SwitchToBranchFunc != nil {
 		return m.SwitchToBranchFunc(directory, branchName)
 	}
@@ -113,7 +174,7 @@ func (m *MockGitHubService) SwitchToBranch(directory, branchName string) error {
 }
 
 // PullChanges is the mock implementation of GitHubService's PullChanges method
-func (m *MockGitHubService) PullChanges(directory, branchName string) error {
+func (m *MockGitHubService) PullChanges(ctx context.Context, directory, branchName string) error {
 	if m.PullChangesFunc != nil {
 		return m.PullChangesFunc(directory, branchName)
 	}
@@ -121,7 +182,7 @@ func (m *MockGitHubService) PullChanges(directory, branchName string) error {
 }
 
 // GetPRDetails is the mock implementation of GitHubService's GetPRDetails method
-func (m *MockGitHubService) GetPRDetails(owner, repo string, prNumber int) (*models.GitHubPRDetails, error) {
+func (m *MockGitHubService) GetPRDetails(ctx context.Context, owner, repo string, prNumber int) (*models.GitHubPRDetails, error) {
 	if m.GetPRDetailsFunc != nil {
 		return m.GetPRDetailsFunc(owner, repo, prNumber)
 	}
@@ -129,15 +190,23 @@ func (m *MockGitHubService) GetPRDetails(owner, repo string, prNumber int) (*mod
 }
 
 // ListPRReviews is the mock implementation of GitHubService's ListPRReviews method
-func (m *MockGitHubService) ListPRReviews(owner, repo string, prNumber int) ([]models.GitHubReview, error) {
+func (m *MockGitHubService) ListPRReviews(ctx context.Context, owner, repo string, prNumber int) ([]models.GitHubReview, error) {
 	if m.ListPRReviewsFunc != nil {
 		return m.ListPRReviewsFunc(owner, repo, prNumber)
 	}
 	return nil, nil
 }
 
+// ApplyPatch is the mock implementation of GitHubService's ApplyPatch method
+func (m *MockGitHubService) ApplyPatch(ctx context.Context, directory, patch string) error {
+	if m.ApplyPatchFunc != nil {
+		return m.ApplyPatchFunc(directory, patch)
+	}
+	return nil
+}
+
 // AddPRComment is the mock implementation of GitHubService's AddPRComment method
-func (m *MockGitHubService) AddPRComment(owner, repo string, prNumber int, body string) error {
+func (m *MockGitHubService) AddPRComment(ctx context.Context, owner, repo string, prNumber int, body string) error {
 	if m.AddPRCommentFunc != nil {
 		return m.AddPRCommentFunc(owner, repo, prNumber, body)
 	}
@@ -145,9 +214,264 @@ func (m *MockGitHubService) AddPRComment(owner, repo string, prNumber int, body
 }
 
 // ListPRComments is the mock implementation of GitHubService's ListPRComments method
-func (m *MockGitHubService) ListPRComments(owner, repo string, prNumber int) ([]models.GitHubPRComment, error) {
+func (m *MockGitHubService) ListPRComments(ctx context.Context, owner, repo string, prNumber int) ([]models.GitHubPRComment, error) {
 	if m.ListPRCommentsFunc != nil {
 		return m.ListPRCommentsFunc(owner, repo, prNumber)
 	}
 	return nil, nil
 }
+
+// RequestReviewers is the mock implementation of GitHubService's RequestReviewers method
+func (m *MockGitHubService) RequestReviewers(ctx context.Context, owner, repo string, prNumber int, reviewers, teamReviewers []string) error {
+	if m.RequestReviewersFunc != nil {
+		return m.RequestReviewersFunc(owner, repo, prNumber, reviewers, teamReviewers)
+	}
+	return nil
+}
+
+// AddLabels is the mock implementation of GitHubService's AddLabels method
+func (m *MockGitHubService) AddLabels(ctx context.Context, owner, repo string, prNumber int, labels []string) error {
+	if m.AddLabelsFunc != nil {
+		return m.AddLabelsFunc(owner, repo, prNumber, labels)
+	}
+	return nil
+}
+
+// SetMilestone is the mock implementation of GitHubService's SetMilestone method
+func (m *MockGitHubService) SetMilestone(ctx context.Context, owner, repo string, prNumber int, title string) error {
+	if m.SetMilestoneFunc != nil {
+		return m.SetMilestoneFunc(owner, repo, prNumber, title)
+	}
+	return nil
+}
+
+// AddToProject is the mock implementation of GitHubService's AddToProject method
+func (m *MockGitHubService) AddToProject(ctx context.Context, projectOwner string, projectNumber int, contentID, field, column string) error {
+	if m.AddToProjectFunc != nil {
+		return m.AddToProjectFunc(projectOwner, projectNumber, contentID, field, column)
+	}
+	return nil
+}
+
+// ListBranches is the mock implementation of GitHubService's ListBranches method
+func (m *MockGitHubService) ListBranches(ctx context.Context, owner, repo string) ([]models.GitHubBranch, error) {
+	if m.ListBranchesFunc != nil {
+		return m.ListBranchesFunc(owner, repo)
+	}
+	return nil, nil
+}
+
+// ListPullRequestsByHead is the mock implementation of GitHubService's ListPullRequestsByHead method
+func (m *MockGitHubService) ListPullRequestsByHead(ctx context.Context, owner, repo, head string) ([]models.GitHubPRDetails, error) {
+	if m.ListPullRequestsByHeadFunc != nil {
+		return m.ListPullRequestsByHeadFunc(owner, repo, head)
+	}
+	return nil, nil
+}
+
+// DeleteBranch is the mock implementation of GitHubService's DeleteBranch method
+func (m *MockGitHubService) DeleteBranch(ctx context.Context, owner, repo, branch string) error {
+	if m.DeleteBranchFunc != nil {
+		return m.DeleteBranchFunc(owner, repo, branch)
+	}
+	return nil
+}
+
+// CountOpenReviewRequests is the mock implementation of GitHubService's CountOpenReviewRequests method
+func (m *MockGitHubService) CountOpenReviewRequests(ctx context.Context, username string) (int, error) {
+	if m.CountOpenReviewRequestsFunc != nil {
+		return m.CountOpenReviewRequestsFunc(username)
+	}
+	return 0, nil
+}
+
+// ListPRFiles is the mock implementation of GitHubService's ListPRFiles method
+func (m *MockGitHubService) ListPRFiles(ctx context.Context, owner, repo string, prNumber int) ([]models.GitHubPRFile, error) {
+	if m.ListPRFilesFunc != nil {
+		return m.ListPRFilesFunc(owner, repo, prNumber)
+	}
+	return nil, nil
+}
+
+// CommitFiles is the mock implementation of GitHubService's CommitFiles method
+func (m *MockGitHubService) CommitFiles(ctx context.Context, directory, message string, files []string) error {
+	if m.CommitFilesFunc != nil {
+		return m.CommitFilesFunc(directory, message, files)
+	}
+	return nil
+}
+
+// CountChangedLines is the mock implementation of GitHubService's CountChangedLines method
+func (m *MockGitHubService) CountChangedLines(ctx context.Context, directory string) (int, error) {
+	if m.CountChangedLinesFunc != nil {
+		return m.CountChangedLinesFunc(directory)
+	}
+	return 0, nil
+}
+
+// CreateBranchFromHead is the mock implementation of GitHubService's CreateBranchFromHead method
+func (m *MockGitHubService) CreateBranchFromHead(ctx context.Context, directory, branchName string) error {
+	if m.CreateBranchFromHeadFunc != nil {
+		return m.CreateBranchFromHeadFunc(directory, branchName)
+	}
+	return nil
+}
+
+// CreateWorktree is the mock implementation of GitHubService's CreateWorktree method
+func (m *MockGitHubService) CreateWorktree(ctx context.Context, repoURL, directory, branch, startPoint string, opts models.CloneOptions) error {
+	if m.CreateWorktreeFunc != nil {
+		return m.CreateWorktreeFunc(repoURL, directory, branch, startPoint, opts)
+	}
+	return nil
+}
+
+// RemoveWorktree is the mock implementation of GitHubService's RemoveWorktree method
+func (m *MockGitHubService) RemoveWorktree(ctx context.Context, repoURL, directory string) error {
+	if m.RemoveWorktreeFunc != nil {
+		return m.RemoveWorktreeFunc(repoURL, directory)
+	}
+	return nil
+}
+
+// ListPRReviewComments is the mock implementation of GitHubService's ListPRReviewComments method
+func (m *MockGitHubService) ListPRReviewComments(ctx context.Context, owner, repo string, prNumber int) ([]models.GitHubPRComment, error) {
+	if m.ListPRReviewCommentsFunc != nil {
+		return m.ListPRReviewCommentsFunc(owner, repo, prNumber)
+	}
+	return nil, nil
+}
+
+// ReplyToReviewComment is the mock implementation of GitHubService's ReplyToReviewComment method
+func (m *MockGitHubService) ReplyToReviewComment(ctx context.Context, owner, repo string, prNumber int, commentID int64, body string) error {
+	if m.ReplyToReviewCommentFunc != nil {
+		return m.ReplyToReviewCommentFunc(owner, repo, prNumber, commentID, body)
+	}
+	return nil
+}
+
+// ListCheckRuns is the mock implementation of GitHubService's ListCheckRuns method
+func (m *MockGitHubService) ListCheckRuns(ctx context.Context, owner, repo, ref string) ([]models.GitHubCheckRun, error) {
+	if m.ListCheckRunsFunc != nil {
+		return m.ListCheckRunsFunc(owner, repo, ref)
+	}
+	return nil, nil
+}
+
+// GetCombinedStatus is the mock implementation of GitHubService's GetCombinedStatus method
+func (m *MockGitHubService) GetCombinedStatus(ctx context.Context, owner, repo, ref string) (*models.GitHubCombinedStatus, error) {
+	if m.GetCombinedStatusFunc != nil {
+		return m.GetCombinedStatusFunc(owner, repo, ref)
+	}
+	return &models.GitHubCombinedStatus{}, nil
+}
+
+// GetJobLogs is the mock implementation of GitHubService's GetJobLogs method
+func (m *MockGitHubService) GetJobLogs(ctx context.Context, owner, repo string, jobID int64) (string, error) {
+	if m.GetJobLogsFunc != nil {
+		return m.GetJobLogsFunc(owner, repo, jobID)
+	}
+	return "", nil
+}
+
+// RebaseOnto is the mock implementation of GitHubService's RebaseOnto method
+func (m *MockGitHubService) RebaseOnto(ctx context.Context, directory, owner, repo, branch string) ([]string, error) {
+	if m.RebaseOntoFunc != nil {
+		return m.RebaseOntoFunc(directory, owner, repo, branch)
+	}
+	return nil, nil
+}
+
+// ContinueRebase is the mock implementation of GitHubService's ContinueRebase method
+func (m *MockGitHubService) ContinueRebase(ctx context.Context, directory string) ([]string, error) {
+	if m.ContinueRebaseFunc != nil {
+		return m.ContinueRebaseFunc(directory)
+	}
+	return nil, nil
+}
+
+// AbortRebase is the mock implementation of GitHubService's AbortRebase method
+func (m *MockGitHubService) AbortRebase(ctx context.Context, directory string) error {
+	if m.AbortRebaseFunc != nil {
+		return m.AbortRebaseFunc(directory)
+	}
+	return nil
+}
+
+// ForcePushChanges is the mock implementation of GitHubService's ForcePushChanges method
+func (m *MockGitHubService) ForcePushChanges(ctx context.Context, directory, branchName string) error {
+	if m.ForcePushChangesFunc != nil {
+		return m.ForcePushChangesFunc(directory, branchName)
+	}
+	return nil
+}
+
+// HasChanges is the mock implementation of GitHubService's HasChanges method
+func (m *MockGitHubService) HasChanges(ctx context.Context, directory string) (bool, error) {
+	if m.HasChangesFunc != nil {
+		return m.HasChangesFunc(directory)
+	}
+	return true, nil
+}
+
+// DiffStats is the mock implementation of GitHubService's DiffStats method
+func (m *MockGitHubService) DiffStats(ctx context.Context, directory string) (*models.DiffStats, error) {
+	if m.DiffStatsFunc != nil {
+		return m.DiffStatsFunc(directory)
+	}
+	return &models.DiffStats{}, nil
+}
+
+// OutgoingPatch is the mock implementation of GitHubService's OutgoingPatch method
+func (m *MockGitHubService) OutgoingPatch(ctx context.Context, directory, branchName string) (string, error) {
+	if m.OutgoingPatchFunc != nil {
+		return m.OutgoingPatchFunc(directory, branchName)
+	}
+	return "", nil
+}
+
+// RelatedCommits is the mock implementation of GitHubService's RelatedCommits method
+func (m *MockGitHubService) RelatedCommits(ctx context.Context, directory string, keywords []string, maxCommits, maxDiffLines int) ([]models.GitCommit, error) {
+	if m.RelatedCommitsFunc != nil {
+		return m.RelatedCommitsFunc(directory, keywords, maxCommits, maxDiffLines)
+	}
+	return nil, nil
+}
+
+// RevertProtectedPaths is the mock implementation of GitHubService's RevertProtectedPaths method
+func (m *MockGitHubService) RevertProtectedPaths(ctx context.Context, directory string, patterns []string) ([]string, error) {
+	if m.RevertProtectedPathsFunc != nil {
+		return m.RevertProtectedPathsFunc(directory, patterns)
+	}
+	return nil, nil
+}
+
+// ChangedFiles is the mock implementation of GitHubService's ChangedFiles method
+func (m *MockGitHubService) ChangedFiles(ctx context.Context, directory string) ([]string, error) {
+	if m.ChangedFilesFunc != nil {
+		return m.ChangedFilesFunc(directory)
+	}
+	return nil, nil
+}
+
+// MergePullRequest is the mock implementation of GitHubService's MergePullRequest method
+func (m *MockGitHubService) MergePullRequest(ctx context.Context, owner, repo string, prNumber int, sha string, method models.MergeMethod) (*models.GitHubMergePRResponse, error) {
+	if m.MergePullRequestFunc != nil {
+		return m.MergePullRequestFunc(owner, repo, prNumber, sha, method)
+	}
+	return &models.GitHubMergePRResponse{Merged: true}, nil
+}
+
+// SetCommitAuthor mocks the SetCommitAuthor method
+func (m *MockGitHubService) SetCommitAuthor(directory string, author *models.GitIdentity) {
+	if m.SetCommitAuthorFunc != nil {
+		m.SetCommitAuthorFunc(directory, author)
+	}
+}
+
+// GetTokenScopes is the mock implementation of GitHubService's GetTokenScopes method
+func (m *MockGitHubService) GetTokenScopes(ctx context.Context) ([]string, error) {
+	if m.GetTokenScopesFunc != nil {
+		return m.GetTokenScopesFunc()
+	}
+	return nil, nil
+}
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"context"
+	"encoding/json"
 	"jira-ai-issue-solver/models"
 )
 
@@ -15,10 +17,23 @@ type MockJiraService struct {
 	GetFieldIDByNameFunc            func(fieldName string) (string, error)
 	AddCommentFunc                  func(key string, comment string) error
 	SearchTicketsFunc               func(jql string) (*models.JiraSearchResponse, error)
+	GetCommentsFunc                 func(key string) ([]models.JiraComment, error)
+	UpdateCommentFunc               func(key, commentID, comment string) error
+	CreateTicketFunc                func(fields models.JiraCreateIssueFields) (string, error)
+	LinkTicketsFunc                 func(linkType, inwardKey, outwardKey string) error
+	AddRemoteLinkFunc               func(key string, link models.JiraRemoteLink) error
+	AddWorklogFunc                  func(key string, worklog models.JiraWorklog) error
+	GetProjectStatusesFunc          func(projectKey string) ([]string, error)
+	GetActiveSprintsFunc            func(boardID int) ([]models.JiraSprint, error)
+	GetProjectPropertyFunc          func(projectKey, propertyKey string) (json.RawMessage, error)
+	CreateCustomFieldFunc           func(name, fieldType string) (string, error)
+	GetCurrentUserFunc              func() (*models.JiraUser, error)
+	DetectTokenTypeFunc             func() (models.JiraTokenType, error)
+	AddAttachmentFunc               func(key, filename string, content []byte) error
 }
 
 // GetTicket is the mock implementation of JiraService's GetTicket method
-func (m *MockJiraService) GetTicket(key string) (*models.JiraTicketResponse, error) {
+func (m *MockJiraService) GetTicket(ctx context.Context, key string) (*models.JiraTicketResponse, error) {
 	if m.GetTicketFunc != nil {
 		return m.GetTicketFunc(key)
 	}
@@ -26,7 +41,7 @@ func (m *MockJiraService) GetTicket(key string) (*models.JiraTicketResponse, err
 }
 
 // GetTicketWithExpandedFields is the mock implementation of JiraService's GetTicketWithExpandedFields method
-func (m *MockJiraService) GetTicketWithExpandedFields(key string) (map[string]interface{}, map[string]string, error) {
+func (m *MockJiraService) GetTicketWithExpandedFields(ctx context.Context, key string) (map[string]interface{}, map[string]string, error) {
 	if m.GetTicketWithExpandedFieldsFunc != nil {
 		return m.GetTicketWithExpandedFieldsFunc(key)
 	}
@@ -34,7 +49,7 @@ func (m *MockJiraService) GetTicketWithExpandedFields(key string) (map[string]in
 }
 
 // UpdateTicketLabels is the mock implementation of JiraService's UpdateTicketLabels method
-func (m *MockJiraService) UpdateTicketLabels(key string, addLabels, removeLabels []string) error {
+func (m *MockJiraService) UpdateTicketLabels(ctx context.Context, key string, addLabels, removeLabels []string) error {
 	if m.UpdateTicketLabelsFunc != nil {
 		return m.UpdateTicketLabelsFunc(key, addLabels, removeLabels)
 	}
@@ -42,7 +57,7 @@ func (m *MockJiraService) UpdateTicketLabels(key string, addLabels, removeLabels
 }
 
 // UpdateTicketStatus is the mock implementation of JiraService's UpdateTicketStatus method
-func (m *MockJiraService) UpdateTicketStatus(key string, status string) error {
+func (m *MockJiraService) UpdateTicketStatus(ctx context.Context, key string, status string) error {
 	if m.UpdateTicketStatusFunc != nil {
 		return m.UpdateTicketStatusFunc(key, status)
 	}
@@ -50,7 +65,7 @@ func (m *MockJiraService) UpdateTicketStatus(key string, status string) error {
 }
 
 // UpdateTicketField is the mock implementation of JiraService's UpdateTicketField method
-func (m *MockJiraService) UpdateTicketField(key string, fieldID string, value interface{}) error {
+func (m *MockJiraService) UpdateTicketField(ctx context.Context, key string, fieldID string, value interface{}) error {
 	if m.UpdateTicketFieldFunc != nil {
 		return m.UpdateTicketFieldFunc(key, fieldID, value)
 	}
@@ -58,7 +73,7 @@ func (m *MockJiraService) UpdateTicketField(key string, fieldID string, value in
 }
 
 // UpdateTicketFieldByName is the mock implementation of JiraService's UpdateTicketFieldByName method
-func (m *MockJiraService) UpdateTicketFieldByName(key string, fieldName string, value interface{}) error {
+func (m *MockJiraService) UpdateTicketFieldByName(ctx context.Context, key string, fieldName string, value interface{}) error {
 	if m.UpdateTicketFieldByNameFunc != nil {
 		return m.UpdateTicketFieldByNameFunc(key, fieldName, value)
 	}
@@ -66,7 +81,7 @@ func (m *MockJiraService) UpdateTicketFieldByName(key string, fieldName string,
 }
 
 // GetFieldIDByName is the mock implementation of JiraService's GetFieldIDByName method
-func (m *MockJiraService) GetFieldIDByName(fieldName string) (string, error) {
+func (m *MockJiraService) GetFieldIDByName(ctx context.Context, fieldName string) (string, error) {
 	if m.GetFieldIDByNameFunc != nil {
 		return m.GetFieldIDByNameFunc(fieldName)
 	}
@@ -74,7 +89,7 @@ func (m *MockJiraService) GetFieldIDByName(fieldName string) (string, error) {
 }
 
 // AddComment is the mock implementation of JiraService's AddComment method
-func (m *MockJiraService) AddComment(key string, comment string) error {
+func (m *MockJiraService) AddComment(ctx context.Context, key string, comment string) error {
 	if m.AddCommentFunc != nil {
 		return m.AddCommentFunc(key, comment)
 	}
@@ -82,9 +97,113 @@ func (m *MockJiraService) AddComment(key string, comment string) error {
 }
 
 // SearchTickets is the mock implementation of JiraService's SearchTickets method
-func (m *MockJiraService) SearchTickets(jql string) (*models.JiraSearchResponse, error) {
+func (m *MockJiraService) SearchTickets(ctx context.Context, jql string) (*models.JiraSearchResponse, error) {
 	if m.SearchTicketsFunc != nil {
 		return m.SearchTicketsFunc(jql)
 	}
 	return nil, nil
 }
+
+// GetComments is the mock implementation of JiraService's GetComments method
+func (m *MockJiraService) GetComments(ctx context.Context, key string) ([]models.JiraComment, error) {
+	if m.GetCommentsFunc != nil {
+		return m.GetCommentsFunc(key)
+	}
+	return nil, nil
+}
+
+// UpdateComment is the mock implementation of JiraService's UpdateComment method
+func (m *MockJiraService) UpdateComment(ctx context.Context, key, commentID, comment string) error {
+	if m.UpdateCommentFunc != nil {
+		return m.UpdateCommentFunc(key, commentID, comment)
+	}
+	return nil
+}
+
+// CreateTicket is the mock implementation of JiraService's CreateTicket method
+func (m *MockJiraService) CreateTicket(ctx context.Context, fields models.JiraCreateIssueFields) (string, error) {
+	if m.CreateTicketFunc != nil {
+		return m.CreateTicketFunc(fields)
+	}
+	return "", nil
+}
+
+// LinkTickets is the mock implementation of JiraService's LinkTickets method
+func (m *MockJiraService) LinkTickets(ctx context.Context, linkType, inwardKey, outwardKey string) error {
+	if m.LinkTicketsFunc != nil {
+		return m.LinkTicketsFunc(linkType, inwardKey, outwardKey)
+	}
+	return nil
+}
+
+// AddRemoteLink is the mock implementation of JiraService's AddRemoteLink method
+func (m *MockJiraService) AddRemoteLink(ctx context.Context, key string, link models.JiraRemoteLink) error {
+	if m.AddRemoteLinkFunc != nil {
+		return m.AddRemoteLinkFunc(key, link)
+	}
+	return nil
+}
+
+// AddWorklog is the mock implementation of JiraService's AddWorklog method
+func (m *MockJiraService) AddWorklog(ctx context.Context, key string, worklog models.JiraWorklog) error {
+	if m.AddWorklogFunc != nil {
+		return m.AddWorklogFunc(key, worklog)
+	}
+	return nil
+}
+
+// GetProjectProperty is the mock implementation of JiraService's GetProjectProperty method
+func (m *MockJiraService) GetProjectProperty(ctx context.Context, projectKey, propertyKey string) (json.RawMessage, error) {
+	if m.GetProjectPropertyFunc != nil {
+		return m.GetProjectPropertyFunc(projectKey, propertyKey)
+	}
+	return nil, nil
+}
+
+// GetActiveSprints is the mock implementation of JiraService's GetActiveSprints method
+func (m *MockJiraService) GetActiveSprints(ctx context.Context, boardID int) ([]models.JiraSprint, error) {
+	if m.GetActiveSprintsFunc != nil {
+		return m.GetActiveSprintsFunc(boardID)
+	}
+	return nil, nil
+}
+
+// GetProjectStatuses is the mock implementation of JiraService's GetProjectStatuses method
+func (m *MockJiraService) GetProjectStatuses(ctx context.Context, projectKey string) ([]string, error) {
+	if m.GetProjectStatusesFunc != nil {
+		return m.GetProjectStatusesFunc(projectKey)
+	}
+	return nil, nil
+}
+
+// CreateCustomField is the mock implementation of JiraService's CreateCustomField method
+func (m *MockJiraService) CreateCustomField(ctx context.Context, name, fieldType string) (string, error) {
+	if m.CreateCustomFieldFunc != nil {
+		return m.CreateCustomFieldFunc(name, fieldType)
+	}
+	return "", nil
+}
+
+// DetectTokenType is the mock implementation of JiraService's DetectTokenType method
+func (m *MockJiraService) DetectTokenType(ctx context.Context) (models.JiraTokenType, error) {
+	if m.DetectTokenTypeFunc != nil {
+		return m.DetectTokenTypeFunc()
+	}
+	return "", nil
+}
+
+// GetCurrentUser is the mock implementation of JiraService's GetCurrentUser method
+func (m *MockJiraService) GetCurrentUser(ctx context.Context) (*models.JiraUser, error) {
+	if m.GetCurrentUserFunc != nil {
+		return m.GetCurrentUserFunc()
+	}
+	return &models.JiraUser{}, nil
+}
+
+// AddAttachment is the mock implementation of JiraService's AddAttachment method
+func (m *MockJiraService) AddAttachment(ctx context.Context, key, filename string, content []byte) error {
+	if m.AddAttachmentFunc != nil {
+		return m.AddAttachmentFunc(key, filename, content)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package mocks
+
+import (
+	"net/http"
+
+	"jira-ai-issue-solver/models"
+)
+
+// MockJiraConnectService is a mock implementation of the JiraConnectService interface
+type MockJiraConnectService struct {
+	DescriptorFunc        func() map[string]interface{}
+	HandleInstalledFunc   func(r *http.Request, payload *models.JiraConnectLifecycle) error
+	HandleUninstalledFunc func(r *http.Request, payload *models.JiraConnectLifecycle) error
+	VerifyRequestFunc     func(r *http.Request) (*models.JiraConnectInstallation, error)
+	SignRequestFunc       func(req *http.Request) error
+}
+
+// Descriptor is the mock implementation of JiraConnectService's Descriptor method
+func (m *MockJiraConnectService) Descriptor() map[string]interface{} {
+	if m.DescriptorFunc != nil {
+		return m.DescriptorFunc()
+	}
+	return map[string]interface{}{}
+}
+
+// HandleInstalled is the mock implementation of JiraConnectService's HandleInstalled method
+func (m *MockJiraConnectService) HandleInstalled(r *http.Request, payload *models.JiraConnectLifecycle) error {
+	if m.HandleInstalledFunc != nil {
+		return m.HandleInstalledFunc(r, payload)
+	}
+	return nil
+}
+
+// HandleUninstalled is the mock implementation of JiraConnectService's HandleUninstalled method
+func (m *MockJiraConnectService) HandleUninstalled(r *http.Request, payload *models.JiraConnectLifecycle) error {
+	if m.HandleUninstalledFunc != nil {
+		return m.HandleUninstalledFunc(r, payload)
+	}
+	return nil
+}
+
+// VerifyRequest is the mock implementation of JiraConnectService's VerifyRequest method
+func (m *MockJiraConnectService) VerifyRequest(r *http.Request) (*models.JiraConnectInstallation, error) {
+	if m.VerifyRequestFunc != nil {
+		return m.VerifyRequestFunc(r)
+	}
+	return &models.JiraConnectInstallation{}, nil
+}
+
+// SignRequest is the mock implementation of JiraConnectService's SignRequest method
+func (m *MockJiraConnectService) SignRequest(req *http.Request) error {
+	if m.SignRequestFunc != nil {
+		return m.SignRequestFunc(req)
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+package mocks
+
+import "context"
+
+type MockPRReviewProcessor struct {
+	ProcessPRReviewFeedbackFunc func(ticketKey string) error
+}
+
+func (m *MockPRReviewProcessor) ProcessPRReviewFeedback(ctx context.Context, ticketKey string) error {
+	if m.ProcessPRReviewFeedbackFunc != nil {
+		return m.ProcessPRReviewFeedbackFunc(ticketKey)
+	}
+	return nil
+}
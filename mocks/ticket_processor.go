@@ -1,12 +1,22 @@
 package mocks
 
+import "context"
+
 type MockTicketProcessor struct {
-	ProcessTicketFunc func(key string) error
+	ProcessTicketFunc      func(key string) error
+	ProcessTicketBatchFunc func(keys []string) error
 }
 
-func (m *MockTicketProcessor) ProcessTicket(key string) error {
+func (m *MockTicketProcessor) ProcessTicket(ctx context.Context, key string) error {
 	if m.ProcessTicketFunc != nil {
 		return m.ProcessTicketFunc(key)
 	}
 	return nil
 }
+
+func (m *MockTicketProcessor) ProcessTicketBatch(ctx context.Context, keys []string) error {
+	if m.ProcessTicketBatchFunc != nil {
+		return m.ProcessTicketBatchFunc(keys)
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// AuditEntry is a line of the audit log
+type AuditEntry struct {
+	Time       time.Time `json:"time"`
+	Ticket     string    `json:"ticket,omitempty"`
+	Action     string    `json:"action"`
+	Actor      string    `json:"actor,omitempty"`   // Jira or GitHub identity that performed an external action
+	Target     string    `json:"target,omitempty"`  // What an external action changed, e.g. owner/repo#12
+	Payload    string    `json:"payload,omitempty"` // Summary of the request of an external action
+	Name       string    `json:"name,omitempty"`
+	Command    string    `json:"command,omitempty"`
+	ExitCode   int       `json:"exit_code"`
+	DurationMS int64     `json:"duration_ms"`
+	Output     string    `json:"output,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// BotChange is a line of the bot change index, describing a pull request the bot opened for a ticket. A line with
+// only the ticket and MergedAt marks the ticket's pull request merged.
+type BotChange struct {
+	Ticket      string     `json:"ticket"`
+	Component   string     `json:"component,omitempty"`
+	Repo        string     `json:"repo,omitempty"` // owner/repo
+	Summary     string     `json:"summary,omitempty"`
+	PRURL       string     `json:"pr_url,omitempty"`
+	Files       []string   `json:"files,omitempty"`       // Files the change added or modified
+	Description string     `json:"description,omitempty"` // What the AI reported doing, shortened
+	OpenedAt    time.Time  `json:"opened_at,omitempty"`
+	MergedAt    *time.Time `json:"merged_at,omitempty"`
+}
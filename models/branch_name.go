@@ -0,0 +1,80 @@
+package models
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// DefaultBranchTemplate names a ticket branch after the ticket key, behind the branch prefix
+const DefaultBranchTemplate = "{{.Prefix}}{{.Key}}"
+
+// BranchNameData is what a branch name template renders
+type BranchNameData struct {
+	Prefix    string // github.branch.prefix, or the repository's branch.prefix
+	Key       string // Ticket key, e.g. PROJ-123
+	Summary   string // Ticket summary
+	Type      string // Ticket issue type, e.g. Bug
+	Component string // Component the ticket is processed for
+}
+
+// branchTemplateFuncs are the functions of branch name templates
+var branchTemplateFuncs = template.FuncMap{"slug": Slug}
+
+// Slug lowercases the text and joins its runs of letters and digits with dashes, e.g. "Fix: CSV export" becomes
+// "fix-csv-export"
+func Slug(text string) string {
+	var slug strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(text) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && slug.Len() > 0 {
+				slug.WriteByte('-')
+			}
+			slug.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return slug.String()
+}
+
+// RenderBranchName renders a branch name template, cut to maxLength characters if it's positive, and checks that the
+// result is a valid git branch name
+func RenderBranchName(text string, maxLength int, data BranchNameData) (string, error) {
+	tmpl, err := template.New("branch").Funcs(branchTemplateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse branch template: %w", err)
+	}
+	var name bytes.Buffer
+	if err := tmpl.Execute(&name, data); err != nil {
+		return "", fmt.Errorf("failed to render branch template: %w", err)
+	}
+
+	branch := strings.TrimSpace(name.String())
+	if runes := []rune(branch); maxLength > 0 && len(runes) > maxLength {
+		branch = string(runes[:maxLength])
+	}
+	// Cutting the name or an empty summary can leave it ending in a separator
+	branch = strings.TrimRight(branch, "-_./")
+	if err := ValidateBranchName(branch); err != nil {
+		return "", err
+	}
+	return branch, nil
+}
+
+// ValidateBranchName checks that the name is a valid git branch name
+func ValidateBranchName(name string) error {
+	if name == "" {
+		return fmt.Errorf("branch name is empty")
+	}
+	if err := validateBranchPrefix(name); err != nil {
+		return fmt.Errorf("invalid branch name %q: not allowed in a git branch name", name)
+	}
+	if strings.HasSuffix(name, ".lock") || strings.Contains(name, "@{") || strings.Contains(name, "/.") {
+		return fmt.Errorf("invalid branch name %q: not allowed in a git branch name", name)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package models
+
+import "testing"
+
+func TestSlug(t *testing.T) {
+	tests := map[string]string{
+		"Fix: CSV export":            "fix-csv-export",
+		"  Add  retries (HTTP 503) ": "add-retries-http-503",
+		"!!!":                        "",
+	}
+	for text, want := range tests {
+		if got := Slug(text); got != want {
+			t.Errorf("Slug(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestRenderBranchName(t *testing.T) {
+	data := BranchNameData{Prefix: "feature", Key: "PROJ-123", Summary: "Fix: CSV export of invoices", Type: "Bug"}
+	tests := []struct {
+		name      string
+		template  string
+		maxLength int
+		want      string
+		wantErr   bool
+	}{
+		{name: "default", template: DefaultBranchTemplate, want: "featurePROJ-123"},
+		{name: "slugged summary", template: "{{.Prefix}}/{{.Key}}-{{slug .Summary}}", want: "feature/PROJ-123-fix-csv-export-of-invoices"},
+		{name: "issue type", template: "{{slug .Type}}/{{.Key}}", want: "bug/PROJ-123"},
+		{name: "cut to max length", template: "{{.Key}}-{{slug .Summary}}", maxLength: 20, want: "PROJ-123-fix-csv-exp"},
+		{name: "separator left by the cut", template: "{{.Key}}-{{slug .Summary}}", maxLength: 12, want: "PROJ-123-fix"},
+		{name: "trailing separator", template: "{{.Key}}-{{slug .Component}}", maxLength: 100, want: "PROJ-123"},
+		{name: "unslugged summary", template: "{{.Key}} {{.Summary}}", wantErr: true},
+		{name: "unknown field", template: "{{.Ticket}}", wantErr: true},
+		{name: "empty", template: "{{slug .Component}}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderBranchName(tt.template, tt.maxLength, data)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RenderBranchName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
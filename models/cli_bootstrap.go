@@ -0,0 +1,28 @@
+package models
+
+import "strings"
+
+// CLIRelease pins the version of an AI CLI binary the application installs at startup
+type CLIRelease struct {
+	Version string `yaml:"version"`
+	// URL of the binary; {version}, {os} and {arch} are replaced, e.g. https://example.com/{version}/{os}-{arch}/claude
+	URL string `yaml:"url"`
+	// SHA-256 checksums of the binary, keyed by platform, e.g. linux-amd64
+	SHA256 map[string]string `yaml:"sha256"`
+}
+
+// Platform returns the key a platform's checksum is stored under
+func Platform(goos, goarch string) string {
+	return goos + "-" + goarch
+}
+
+// DownloadURL returns the URL of the binary for the platform
+func (r CLIRelease) DownloadURL(goos, goarch string) string {
+	return strings.NewReplacer("{version}", r.Version, "{os}", goos, "{arch}", goarch).Replace(r.URL)
+}
+
+// Checksum returns the expected SHA-256 checksum of the binary for the platform, lower-cased
+func (r CLIRelease) Checksum(goos, goarch string) (string, bool) {
+	checksum, ok := r.SHA256[Platform(goos, goarch)]
+	return strings.ToLower(checksum), ok && checksum != ""
+}
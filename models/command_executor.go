@@ -1,5 +1,8 @@
 package models
 
-import "os/exec"
+import (
+	"context"
+	"os/exec"
+)
 
-type CommandExecutor func(name string, args ...string) *exec.Cmd
+type CommandExecutor func(ctx context.Context, name string, args ...string) *exec.Cmd
@@ -4,7 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -89,13 +93,277 @@ func (f *LogFormat) UnmarshalYAML(value *yaml.Node) error {
 	return nil
 }
 
+// JiraAuthMode represents how the application authenticates against Jira
+type JiraAuthMode string
+
+const (
+	JiraAuthModeToken   JiraAuthMode = "token"
+	JiraAuthModeConnect JiraAuthMode = "connect"
+)
+
+// IsValid checks if the JiraAuthMode is valid
+func (m JiraAuthMode) IsValid() bool {
+	switch m {
+	case JiraAuthModeToken, JiraAuthModeConnect:
+		return true
+	default:
+		return false
+	}
+}
+
+// JiraTokenType represents how the API token is sent to Jira in the token auth mode
+type JiraTokenType string
+
+const (
+	JiraTokenTypeAuto   JiraTokenType = "auto"   // Detected at startup
+	JiraTokenTypeBearer JiraTokenType = "bearer" // Personal access token of Jira Server and Data Center
+	JiraTokenTypeBasic  JiraTokenType = "basic"  // Email and API token of Jira Cloud
+)
+
+// IsValid checks if the JiraTokenType is valid
+func (t JiraTokenType) IsValid() bool {
+	switch t {
+	case JiraTokenTypeAuto, JiraTokenTypeBearer, JiraTokenTypeBasic:
+		return true
+	default:
+		return false
+	}
+}
+
+// GitHubAuthMode represents how the application authenticates against GitHub
+type GitHubAuthMode string
+
+const (
+	GitHubAuthModePAT GitHubAuthMode = "pat"
+	GitHubAuthModeApp GitHubAuthMode = "app"
+)
+
+// IsValid checks if the GitHubAuthMode is valid
+func (m GitHubAuthMode) IsValid() bool {
+	switch m {
+	case GitHubAuthModePAT, GitHubAuthModeApp:
+		return true
+	default:
+		return false
+	}
+}
+
+// GitProtocol represents how git clones from and pushes to GitHub
+type GitProtocol string
+
+const (
+	GitProtocolHTTPS GitProtocol = "https" // HTTPS with the token embedded in the remote URL
+	GitProtocolSSH   GitProtocol = "ssh"   // SSH with a private key or the SSH agent
+)
+
+// IsValid checks if the GitProtocol is valid
+func (p GitProtocol) IsValid() bool {
+	switch p {
+	case GitProtocolHTTPS, GitProtocolSSH:
+		return true
+	default:
+		return false
+	}
+}
+
+// ComplianceMode represents how contribution requirements (CLA/DCO) of external repositories are handled
+type ComplianceMode string
+
+const (
+	ComplianceModeSkip    ComplianceMode = "skip"    // Don't check contribution requirements
+	ComplianceModeAuto    ComplianceMode = "auto"    // Detect DCO/CLA requirements from the repository
+	ComplianceModeSignOff ComplianceMode = "signoff" // Always add a DCO sign-off trailer
+	ComplianceModeBlock   ComplianceMode = "block"   // Never contribute automatically
+)
+
+// IsValid checks if the ComplianceMode is valid
+func (m ComplianceMode) IsValid() bool {
+	switch m {
+	case ComplianceModeSkip, ComplianceModeAuto, ComplianceModeSignOff, ComplianceModeBlock:
+		return true
+	default:
+		return false
+	}
+}
+
+// MergeMethod represents how approved pull requests are merged
+type MergeMethod string
+
+const (
+	MergeMethodMerge  MergeMethod = "merge"  // Create a merge commit
+	MergeMethodSquash MergeMethod = "squash" // Squash the PR into a single commit
+	MergeMethodRebase MergeMethod = "rebase" // Rebase the PR's commits onto the target branch
+)
+
+// IsValid checks if the MergeMethod is valid
+func (m MergeMethod) IsValid() bool {
+	switch m {
+	case MergeMethodMerge, MergeMethodSquash, MergeMethodRebase:
+		return true
+	default:
+		return false
+	}
+}
+
+// FeedbackTrigger represents which PR comments and reviews trigger feedback processing
+type FeedbackTrigger string
+
+const (
+	FeedbackTriggerAny     FeedbackTrigger = "any"     // Any new comment or review from someone other than the bot
+	FeedbackTriggerMention FeedbackTrigger = "mention" // Only comments and reviews that @mention the bot
+)
+
+// IsValid checks if the FeedbackTrigger is valid
+func (t FeedbackTrigger) IsValid() bool {
+	switch t {
+	case FeedbackTriggerAny, FeedbackTriggerMention:
+		return true
+	default:
+		return false
+	}
+}
+
+// CheckoutMode represents how tickets are checked out of the cached clone of their repository
+type CheckoutMode string
+
+const (
+	CheckoutModeWorktree  CheckoutMode = "worktree"  // A git worktree sharing the cached clone's object store
+	CheckoutModeReference CheckoutMode = "reference" // A standalone clone that copies its objects from the cached clone
+)
+
+// IsValid checks if the CheckoutMode is valid
+func (m CheckoutMode) IsValid() bool {
+	switch m {
+	case CheckoutModeWorktree, CheckoutModeReference:
+		return true
+	default:
+		return false
+	}
+}
+
+// GenerateDocsMode represents when the AI's documentation file (CLAUDE.md or GEMINI.md) is generated
+type GenerateDocsMode string
+
+const (
+	GenerateDocsOff    GenerateDocsMode = "off"    // Never
+	GenerateDocsOnce   GenerateDocsMode = "once"   // For the first ticket of each repository only
+	GenerateDocsAlways GenerateDocsMode = "always" // For every ticket whose checkout lacks the file
+)
+
+// IsValid checks if the GenerateDocsMode is valid
+func (m GenerateDocsMode) IsValid() bool {
+	switch m {
+	case GenerateDocsOff, GenerateDocsOnce, GenerateDocsAlways:
+		return true
+	default:
+		return false
+	}
+}
+
+// CommitSigningFormat represents how the bot signs its commits
+type CommitSigningFormat string
+
+const (
+	CommitSigningNone CommitSigningFormat = ""    // Commits are not signed
+	CommitSigningGPG  CommitSigningFormat = "gpg" // Sign with an OpenPGP key
+	CommitSigningSSH  CommitSigningFormat = "ssh" // Sign with an SSH key
+)
+
+// IsValid checks if the CommitSigningFormat is valid
+func (f CommitSigningFormat) IsValid() bool {
+	switch f {
+	case CommitSigningNone, CommitSigningGPG, CommitSigningSSH:
+		return true
+	default:
+		return false
+	}
+}
+
+// PRPlanningConfig attaches the bot's pull requests to the team's planning on GitHub: a milestone of the target
+// repository and a column of a Projects board
+type PRPlanningConfig struct {
+	Milestone string `yaml:"milestone"` // Title of an open milestone of the target repository
+	Project   struct {
+		Owner  string `yaml:"owner"`                  // Organization or user owning the project
+		Number int    `yaml:"number"`                 // Project number, as in its URL; no project if zero
+		Field  string `yaml:"field" default:"Status"` // Single-select field the board's columns are the options of
+		Column string `yaml:"column"`                 // Option the pull request is put in; the board's default if empty
+	} `yaml:"project"`
+}
+
+// TransitionFieldsConfig holds the fields a transition screen requires, such as the resolution of a Done transition
+type TransitionFieldsConfig struct {
+	Resolution string `yaml:"resolution"` // Name of the resolution set by the transition, e.g. Done
+	// Screen fields by ID or name, with values as Jira's REST API expects them, e.g. fixVersions: [{name: "2.3"}]
+	Fields map[string]interface{} `yaml:"fields"`
+}
+
+// LanguageInstructionsConfig holds the curated instructions added to the generation prompt for the repository's
+// dominant languages and frameworks
+type LanguageInstructionsConfig struct {
+	Enabled  bool              `yaml:"enabled" default:"false"`
+	MinShare int               `yaml:"min_share" default:"20"` // Percentage of the source files a language needs to be dominant
+	Blocks   map[string]string `yaml:"blocks"`                 // By language or framework name; replace the built-in blocks, an empty one removes it
+}
+
+// PromptLimitsConfig limits the ticket comments and pull request diffs passed to the AI
+type PromptLimitsConfig struct {
+	MaxCommentTokens int  `yaml:"max_comment_tokens" default:"8000"` // Older comments are summarized beyond it
+	RecentComments   int  `yaml:"recent_comments" default:"5"`       // Newest comments kept verbatim
+	MaxDiffTokens    int  `yaml:"max_diff_tokens" default:"15000"`   // The largest file diffs of feedback prompts are summarized beyond it
+	Summarize        bool `yaml:"summarize" default:"false"`         // Summarize older comments with an AI run instead of excerpts
+}
+
+// ComponentConfig holds per-component overrides of global settings.
+// Pointer fields are nil when the component does not override the global value.
+type ComponentConfig struct {
+	DraftPR          *bool         `yaml:"draft_pr"`
+	SameRepo         *bool         `yaml:"same_repo"` // Replaces github.same_repo
+	ReviewerPool     []string      `yaml:"reviewer_pool"`
+	DefaultReviewers []string      `yaml:"default_reviewers"`
+	PRLabels         []string      `yaml:"pr_labels"`     // Replace the global pull request labels
+	TargetBranch     string        `yaml:"target_branch"` // Replaces github.target_branch, e.g. develop
+	Clone            *CloneOptions `yaml:"clone"`
+	// Pipeline replaces the global pipeline steps for the component
+	Pipeline []PipelineStepConfig `yaml:"pipeline"`
+	// Hooks replace the global commands of the hook points they list
+	Hooks PipelineHooks `yaml:"hooks"`
+	// CommitAuthor replaces the global commit author settings for the component
+	CommitAuthor *CommitAuthorConfig `yaml:"commit_author"`
+	// GenerateDocs replaces the global documentation generation mode for the component
+	GenerateDocs GenerateDocsMode `yaml:"generate_docs"`
+	// Build replaces the global build check for the component
+	Build *CheckCommandConfig `yaml:"build"`
+	// Test replaces the global test check for the component
+	Test *CheckCommandConfig `yaml:"test"`
+	// Format replaces the global formatters for the component; an empty list runs none
+	Format []HookCommand `yaml:"format"`
+	// Lint replaces the global lint check for the component
+	Lint *CheckCommandConfig `yaml:"lint"`
+	// Planning replaces the global milestone and project board of the component's pull requests
+	Planning *PRPlanningConfig `yaml:"planning"`
+}
+
 // Config represents the application configuration
 type Config struct {
 	// Server configuration
 	Server struct {
-		Port int `yaml:"port" default:"8080"`
+		Port           int    `yaml:"port" default:"8080"`
+		AdminToken     string `yaml:"admin_token"`      // Bearer token of the admin API; the API is disabled without one
+		AdminTokenFile string `yaml:"admin_token_file"` // File holding the admin token, instead of admin_token
 	} `yaml:"server"`
 
+	// Maintenance mode stops picking up new work while tickets being processed finish, for safe upgrades
+	Maintenance struct {
+		Enabled           bool `yaml:"enabled" default:"false"`           // Start in maintenance mode
+		RetryAfterSeconds int  `yaml:"retry_after_seconds" default:"300"` // Retry-After of requests refused in maintenance mode
+	} `yaml:"maintenance"`
+
+	// Dependency checks of /health/ready
+	Health struct {
+		CacheSeconds int `yaml:"cache_seconds" default:"60"` // How long check results are reused, so probes don't hammer Jira and GitHub
+	} `yaml:"health"`
+
 	// Logging configuration
 	Logging struct {
 		Level  LogLevel  `yaml:"level" default:"info"`
@@ -104,30 +372,216 @@ type Config struct {
 
 	// Jira configuration
 	Jira struct {
-		BaseURL                 string `yaml:"base_url"`
-		Username                string `yaml:"username"`
-		APIToken                string `yaml:"api_token"`
-		IntervalSeconds         int    `yaml:"interval_seconds" default:"300"`
-		DisableErrorComments    bool   `yaml:"disable_error_comments" default:"false"`
-		GitPullRequestFieldName string `yaml:"git_pull_request_field_name"`
-		StatusTransitions       struct {
+		AuthMode JiraAuthMode `yaml:"auth_mode" default:"token"` // "token" or "connect"
+		BaseURL  string       `yaml:"base_url"`
+		Username string       `yaml:"username"`
+		APIToken string       `yaml:"api_token"`
+		// File holding the API token, instead of api_token
+		APITokenFile string `yaml:"api_token_file"`
+		// How the API token is sent: bearer for Server and Data Center PATs, basic for Cloud tokens, auto to detect it
+		TokenType JiraTokenType `yaml:"token_type" default:"auto"`
+		// Atlassian Connect app configuration (used when auth_mode: connect)
+		Connect struct {
+			AppKey            string   `yaml:"app_key"`
+			AppName           string   `yaml:"app_name" default:"Jira AI Issue Solver"`
+			BaseURL           string   `yaml:"base_url"` // Public URL of this service that Jira calls back
+			Scopes            []string `yaml:"scopes"`
+			InstallationsFile string   `yaml:"installations_file" default:"jira-connect-installations.json"`
+		} `yaml:"connect"`
+		IntervalSeconds         int      `yaml:"interval_seconds" default:"300"`
+		DisableErrorComments    bool     `yaml:"disable_error_comments" default:"false"`
+		DisableProgressUpdates  bool     `yaml:"disable_progress_updates" default:"false"` // Only post the progress comment once the branch exists
+		DisableRemoteLinks      bool     `yaml:"disable_remote_links" default:"false"`     // Don't add pull requests to the tickets' links
+		RichComments            bool     `yaml:"rich_comments" default:"false"`            // Format comments as success and error panels with collapsed details
+		GitPullRequestFieldName string   `yaml:"git_pull_request_field_name"`
+		TargetBranchFieldName   string   `yaml:"target_branch_field_name"` // Custom field naming the ticket's target branch, overriding the component's
+		PromptFields            []string `yaml:"prompt_fields"`            // Names of custom fields added to the prompt, e.g. Acceptance Criteria
+		IssueTypes              struct {
+			Skip         []string          `yaml:"skip"`         // Issue types left to humans, e.g. Epic
+			Instructions map[string]string `yaml:"instructions"` // By issue type name; replace the built-in instructions, an empty one removes them
+		} `yaml:"issue_types"`
+		StatusTransitions struct {
 			Todo       string `yaml:"todo" default:"To Do"`
 			InProgress string `yaml:"in_progress" default:"In Progress"`
 			InReview   string `yaml:"in_review" default:"In Review"`
+			Done       string `yaml:"done" default:"Done"` // Status once the PR is merged
+			HandedOff  string `yaml:"handed_off"`          // Status once a human takes the PR over, unchanged if empty
+			Closed     string `yaml:"closed"`              // Status once the PR is closed without merging, unchanged if empty
 		} `yaml:"status_transitions"`
+		// Resolution and screen fields set by the transitions to a status, keyed by status name
+		TransitionFields map[string]TransitionFieldsConfig `yaml:"transition_fields"`
+		Worklog          struct {
+			Enabled bool   `yaml:"enabled" default:"false"`                                 // Log the time spent processing tickets as Jira worklogs
+			Comment string `yaml:"comment" default:"Automated work by the AI issue solver"` // Comment of the worklogs
+			// Token of the account the worklogs are logged as, instead of api_token
+			APIToken string `yaml:"api_token"`
+			// File holding the worklog token, instead of api_token
+			APITokenFile string `yaml:"api_token_file"`
+		} `yaml:"worklog"`
+		TriggerMode  TriggerMode `yaml:"trigger_mode" default:"status"`       // What marks tickets for processing: status, label or both
+		TriggerLabel string      `yaml:"trigger_label" default:"good-for-ai"` // Label marking tickets for processing in the label and both trigger modes
+		ScanOrder    ScanOrder   `yaml:"scan_order" default:"priority"`       // Order tickets are processed in: priority, rank, created or updated
+		// Tickets found by a scan processed at once; the others wait their turn in the scan's order
+		MaxConcurrentTickets int `yaml:"max_concurrent_tickets" default:"4"`
+		Sprint               struct {
+			ActiveOnly bool `yaml:"active_only" default:"false"` // Only process tickets in an active sprint
+			// Board whose active sprints are scanned; all open sprints are scanned without a board
+			BoardID int `yaml:"board_id"`
+		} `yaml:"sprint"`
+		FollowUps struct {
+			Enabled   bool     `yaml:"enabled" default:"false"`     // Create Jira tickets for follow-up work the AI couldn't complete
+			IssueType string   `yaml:"issue_type" default:"Task"`   // Issue type of follow-up tickets
+			LinkType  string   `yaml:"link_type" default:"Relates"` // Issue link type between the ticket and its follow-ups
+			Labels    []string `yaml:"labels"`                      // Labels added to follow-up tickets
+			MaxIssues int      `yaml:"max_issues" default:"5"`      // Follow-up tickets created per ticket at most
+		} `yaml:"follow_ups"`
+		EpicBatching struct {
+			Enabled    bool   `yaml:"enabled" default:"false"`     // Process tickets of the same epic mapping to the same repository in one pull request
+			MaxTickets int    `yaml:"max_tickets" default:"5"`     // Tickets batched in one pull request at most
+			Label      string `yaml:"label"`                       // Only batch tickets with this label; all tickets of an epic if empty
+			LinkType   string `yaml:"link_type" default:"Relates"` // Issue link type between the batched tickets
+		} `yaml:"epic_batching"`
+		RateLimit struct {
+			RequestsPerSecond float64 `yaml:"requests_per_second" default:"10"` // API requests sent per second at most; negative disables throttling
+			MaxRetries        int     `yaml:"max_retries" default:"3"`          // Retries of API requests refused with 429 or failed with 503
+			MaxWaitSeconds    int     `yaml:"max_wait_seconds" default:"300"`   // Longest Retry-After to wait for; longer waits fail the request
+		} `yaml:"rate_limit"`
 	} `yaml:"jira"`
 
 	// GitHub configuration
 	GitHub struct {
-		PersonalAccessToken string `yaml:"personal_access_token"`
-		BotUsername         string `yaml:"bot_username"`
-		BotEmail            string `yaml:"bot_email"`
-		TargetBranch        string `yaml:"target_branch" default:"main"`
-		PRLabel             string `yaml:"pr_label" default:"ai-pr"`
+		AuthMode              GitHubAuthMode  `yaml:"auth_mode" default:"pat"` // "pat" or "app"
+		PersonalAccessToken   string          `yaml:"personal_access_token"`
+		BotUsername           string          `yaml:"bot_username"`
+		BotEmail              string          `yaml:"bot_email"`
+		ForkOrganization      string          `yaml:"fork_organization"`                       // Organization forks are created in; the bot account if empty
+		SameRepo              bool            `yaml:"same_repo" default:"false"`               // Push ticket branches to the repository itself instead of a fork
+		DisableBranchDeletion bool            `yaml:"disable_branch_deletion" default:"false"` // Keep the branches of merged and closed pull requests
+		FeedbackTrigger       FeedbackTrigger `yaml:"feedback_trigger" default:"any"`          // "any" or "mention"
+		FeedbackConcurrency   int             `yaml:"feedback_concurrency" default:"4"`        // Tickets whose PR feedback is processed at once
+		TargetBranch          string          `yaml:"target_branch"`                           // Branch pull requests target; the repository's default branch if empty
+		Branch                struct {
+			Template  string `yaml:"template" default:"{{.Prefix}}{{.Key}}"` // Go template of ticket branch names, see BranchNameData
+			Prefix    string `yaml:"prefix"`                                 // Branch prefix, unless the repository's .ai-solver.yaml sets one
+			MaxLength int    `yaml:"max_length" default:"100"`               // Ticket branch names are cut to this many characters
+		} `yaml:"branch"`
+		PRLabel             string           `yaml:"pr_label" default:"ai-pr"`
+		PRLabels            []string         `yaml:"pr_labels"` // More labels added to the bot's pull requests
+		DraftPR             bool             `yaml:"draft_pr" default:"false"`
+		DisablePRTemplate   bool             `yaml:"disable_pr_template" default:"false"` // Use the generic body instead of the repository's pull request template
+		Planning            PRPlanningConfig `yaml:"planning"`                            // Milestone and project board the bot's pull requests are added to
+		ReviewerPool        []string         `yaml:"reviewer_pool"`                       // Candidates for automatic reviewer assignment
+		CodeownersReviewers bool             `yaml:"codeowners_reviewers"`                // Request reviews from CODEOWNERS of the changed paths
+		DefaultReviewers    []string         `yaml:"default_reviewers"`                   // Users or org/team slugs requested when CODEOWNERS yields nobody
+		Git                 struct {
+			Protocol     GitProtocol            `yaml:"protocol" default:"https"` // Protocol for repositories without an override
+			Repositories map[string]GitProtocol `yaml:"repositories"`             // Per-repository protocol overrides, keyed by owner/repo
+			SSH          struct {
+				KeyFile           string   `yaml:"key_file"`             // Private key; empty uses the SSH agent
+				KnownHosts        []string `yaml:"known_hosts"`          // Trusted host key lines, e.g. from ssh-keyscan github.com
+				KnownHostsFile    string   `yaml:"known_hosts_file"`     // Known hosts file SSH uses (default: <temp_dir>/known_hosts)
+				AcceptNewHostKeys bool     `yaml:"accept_new_host_keys"` // Trust unknown host keys on first use
+			} `yaml:"ssh"`
+		} `yaml:"git"`
+		Clone        CloneOptions       `yaml:"clone"`         // Shallow/partial clone settings for large repositories
+		CommitAuthor CommitAuthorConfig `yaml:"commit_author"` // Author of the bot's commits; the bot stays the committer
+		Compliance   struct {
+			Mode         ComplianceMode            `yaml:"mode" default:"skip"` // Mode for repositories outside internal_orgs
+			InternalOrgs []string                  `yaml:"internal_orgs"`       // Repositories owned by these orgs are never checked
+			CLASigned    []string                  `yaml:"cla_signed"`          // owner/repo entries whose CLA the bot has signed
+			Repositories map[string]ComplianceMode `yaml:"repositories"`        // Per-repository mode overrides, keyed by owner/repo
+		} `yaml:"compliance"`
+		StackedPRs struct {
+			Enabled         bool `yaml:"enabled" default:"false"`
+			MinChangedLines int  `yaml:"min_changed_lines" default:"400"` // Changes smaller than this are never split
+		} `yaml:"stacked_prs"`
+		Signing struct {
+			Format  CommitSigningFormat `yaml:"format"`   // "gpg" or "ssh"; empty disables signing
+			KeyFile string              `yaml:"key_file"` // Private signing key (ASCII-armored OpenPGP key or SSH private key)
+			KeyID   string              `yaml:"key_id"`   // OpenPGP key ID or fingerprint (gpg only)
+		} `yaml:"signing"`
+		Worktrees struct {
+			Enabled  bool         `yaml:"enabled" default:"false"` // Check tickets out of one cached clone per repository
+			CacheDir string       `yaml:"cache_dir"`               // Where cached clones live (default: <temp_dir>/repo-cache)
+			Mode     CheckoutMode `yaml:"mode" default:"worktree"` // "worktree" or "reference"
+		} `yaml:"worktrees"`
+		CIFeedback struct {
+			Enabled     bool `yaml:"enabled" default:"false"`     // Let the AI fix failing check runs and statuses on its PRs
+			MaxLogLines int  `yaml:"max_log_lines" default:"200"` // Lines kept from the end of each failing job's log
+			MaxAttempts int  `yaml:"max_attempts" default:"3"`    // Fix attempts per PR before leaving CI failures to humans
+		} `yaml:"ci_feedback"`
+		ConflictResolution struct {
+			Enabled bool `yaml:"enabled" default:"false"` // Rebase conflicted PRs onto the target branch and let the AI resolve conflicts
+		} `yaml:"conflict_resolution"`
+		Handoff struct {
+			Command string `yaml:"command" default:"ai:stop"` // Comment text that stops the bot on a PR
+			Label   string `yaml:"label" default:"ai-stop"`   // PR label that stops the bot on a PR
+		} `yaml:"handoff"`
+		APIRetry struct {
+			MaxRetries     int `yaml:"max_retries" default:"3"`        // Retries of API requests failing with rate limits, 5xx or network errors
+			MaxWaitSeconds int `yaml:"max_wait_seconds" default:"300"` // Longest wait for a rate limit reset; longer waits fail the request
+		} `yaml:"api_retry"`
+		AutoMerge struct {
+			Enabled bool        `yaml:"enabled" default:"false"` // Merge approved PRs with green checks and close their tickets
+			Method  MergeMethod `yaml:"method" default:"squash"` // "merge", "squash" or "rebase"
+		} `yaml:"auto_merge"`
+		// GitHub App configuration (used when auth_mode: app)
+		AppID          int64  `yaml:"app_id"`
+		InstallationID int64  `yaml:"installation_id"`
+		PrivateKeyPath string `yaml:"private_key_path"`
+		PrivateKey     string `yaml:"private_key"` // PEM encoded private key, instead of private_key_path
+		// File holding the personal access token, instead of personal_access_token
+		PersonalAccessTokenFile string `yaml:"personal_access_token_file"`
 	} `yaml:"github"`
 
 	// AI Provider selection
-	AIProvider string `yaml:"ai_provider" default:"claude"` // "claude" or "gemini"
+	AIProvider         string `yaml:"ai_provider" default:"claude"` // "claude" or "gemini"
+	AIFallbackProvider string `yaml:"ai_fallback_provider"`         // Used when the primary provider is unavailable
+
+	// Settings shared by the AI providers
+	AI struct {
+		GenerateDocs      GenerateDocsMode `yaml:"generate_docs" default:"always"`                    // "off", "once" or "always"
+		DocsBootstrapFile string           `yaml:"docs_bootstrap_file" default:"docs-bootstrap.json"` // Repositories documentation was generated for in "once" mode
+		NoChangeRetries   *int             `yaml:"no_change_retries" default:"1"`                     // AI runs retrying a ticket it made no changes for before failing it; 0 fails it right away
+
+		// Map of the checkout added to the generation prompt, so the AI doesn't rediscover the project's structure
+		RepoMap struct {
+			Enabled    bool `yaml:"enabled" default:"false"`
+			MaxDepth   int  `yaml:"max_depth" default:"3"`     // Directory levels shown in the tree
+			MaxEntries int  `yaml:"max_entries" default:"100"` // Directories shown in the tree; deeper ones are left out first
+		} `yaml:"repo_map"`
+
+		// Recent commits related to the ticket added to the generation prompt by the git_history pipeline step
+		GitHistory struct {
+			Enabled      bool `yaml:"enabled" default:"false"`
+			MaxCommits   int  `yaml:"max_commits" default:"5"`     // Related commits shown
+			MaxDiffLines int  `yaml:"max_diff_lines" default:"40"` // Diff lines shown per commit
+		} `yaml:"git_history"`
+
+		// Summaries of the bot's earlier merged changes to the ticket's component added to the generation prompt, so the
+		// AI follows the precedents they established
+		Precedents struct {
+			Enabled    bool   `yaml:"enabled" default:"false"`
+			IndexFile  string `yaml:"index_file" default:"bot-changes.log"` // Pull requests opened and merged, as JSON lines
+			MaxChanges int    `yaml:"max_changes" default:"3"`              // Earlier changes shown
+		} `yaml:"precedents"`
+
+		// Condensed summaries of the AI's sessions on a ticket added to the prompts of later feedback rounds, so the AI
+		// remembers the decisions it already made
+		SessionSummaries struct {
+			Enabled     bool   `yaml:"enabled" default:"false"`
+			File        string `yaml:"file" default:"ai-sessions.log"` // Summaries of the sessions, as JSON lines
+			MaxSessions int    `yaml:"max_sessions" default:"5"`       // Latest sessions of the ticket shown
+			MaxTokens   int    `yaml:"max_tokens" default:"300"`       // Length of each summary at most
+		} `yaml:"session_summaries"`
+
+		// Curated instructions for the repository's dominant languages and frameworks added to the generation prompt
+		LanguageInstructions LanguageInstructionsConfig `yaml:"language_instructions"`
+
+		// Limits of the ticket comments and pull request diffs passed to the AI, estimated at 4 characters per token,
+		// since the CLIs take the prompt as an argument
+		Prompt PromptLimitsConfig `yaml:"prompt"`
+	} `yaml:"ai"`
 
 	// Claude CLI configuration
 	Claude struct {
@@ -136,26 +590,179 @@ type Config struct {
 		DangerouslySkipPermissions bool   `yaml:"dangerously_skip_permissions" default:"false"`
 		AllowedTools               string `yaml:"allowed_tools" default:"Bash Edit"`
 		DisallowedTools            string `yaml:"disallowed_tools" default:"Python"`
+		MaxConcurrentRuns          int    `yaml:"max_concurrent_runs" default:"0"` // CLI processes running at once, 0 is unlimited
 	} `yaml:"claude"`
 
 	// Gemini CLI configuration
 	Gemini struct {
-		CLIPath  string `yaml:"cli_path" default:"gemini"`
-		Timeout  int    `yaml:"timeout" default:"300"`
-		Model    string `yaml:"model" default:"gemini-2.5-pro"`
-		AllFiles bool   `yaml:"all_files" default:"false"`
-		Sandbox  bool   `yaml:"sandbox" default:"false"`
-		APIKey   string `yaml:"api_key"`
+		CLIPath           string `yaml:"cli_path" default:"gemini"`
+		Timeout           int    `yaml:"timeout" default:"300"`
+		Model             string `yaml:"model" default:"gemini-2.5-pro"`
+		AllFiles          bool   `yaml:"all_files" default:"false"`
+		Sandbox           bool   `yaml:"sandbox" default:"false"`
+		APIKey            string `yaml:"api_key"`
+		APIKeyFile        string `yaml:"api_key_file"`                    // File holding the API key, instead of api_key
+		MaxConcurrentRuns int    `yaml:"max_concurrent_runs" default:"0"` // CLI processes running at once, 0 is unlimited
 	} `yaml:"gemini"`
 
+	// Installs pinned AI CLI versions into a cache directory at startup, replacing the providers' cli_path
+	CLIBootstrap struct {
+		Enabled  bool       `yaml:"enabled" default:"false"`
+		CacheDir string     `yaml:"cache_dir" default:"cli-cache"` // Installed binaries, by provider, version and platform
+		Offline  bool       `yaml:"offline" default:"false"`       // Never download; use the cache or the CLI at cli_path
+		Claude   CLIRelease `yaml:"claude"`
+		Gemini   CLIRelease `yaml:"gemini"`
+	} `yaml:"cli_bootstrap"`
+
+	// Where the AI CLIs run; in Docker, a run can only touch its checkout and reach the network it is given
+	Sandbox struct {
+		Backend SandboxBackend      `yaml:"backend" default:"host"` // host or docker
+		Docker  DockerSandboxConfig `yaml:"docker"`
+	} `yaml:"sandbox"`
+
 	// Component to Repository mapping
 	ComponentToRepo map[string]string `yaml:"component_to_repo"`
 
+	// Pattern-based mappings, tried in order for components without a component_to_repo mapping
+	RepoMappings []RepoMapping `yaml:"repo_mappings"`
+
+	// Jira project property holding the repository of the project's tickets matching no mapping, such as
+	// ai.bot.github.repo; not looked up if empty
+	RepoProjectProperty string `yaml:"repo_project_property"`
+
+	// Repository of tickets matching no mapping, including tickets without components; none if empty
+	DefaultRepo string `yaml:"default_repo"`
+
+	// Per-component overrides, keyed by Jira component name
+	Components map[string]ComponentConfig `yaml:"components"`
+
+	// Ordered ticket pipeline steps; empty runs the default steps
+	Pipeline []PipelineStepConfig `yaml:"pipeline"`
+
+	// Commands run at the pipeline hook points
+	Hooks PipelineHooks `yaml:"hooks"`
+
+	// Build command that must pass before the AI's changes are committed; failures are fed back to the AI
+	Build CheckCommandConfig `yaml:"build"`
+
+	// Test command that must pass before the AI's changes are committed, after the build; failures are fed back to
+	// the AI and the results are shown in the pull request
+	Test CheckCommandConfig `yaml:"test"`
+
+	// Formatters applied to the AI's changes before the checks and after every AI fix, e.g. gofmt -w .
+	Format []HookCommand `yaml:"format"`
+
+	// Lint command that must pass before the AI's changes are committed, after the build and before the tests;
+	// failures are fed back to the AI
+	Lint CheckCommandConfig `yaml:"lint"`
+
 	// Temporary directory for cloning repositories
 	TempDir string `yaml:"temp_dir" default:"/tmp/jira-ai-issue-solver"`
+
+	// File the ticket pipeline states are persisted to, so interrupted tickets resume after a restart
+	StateFile string `yaml:"state_file" default:"ticket-states.json"`
+
+	// File the feedback processing watermark of each pull request is persisted to
+	FeedbackWatermarkFile string `yaml:"feedback_watermark_file" default:"feedback-watermarks.json"`
+
+	// File custom commands and their output are recorded to as JSON lines; empty disables the audit log
+	AuditLog string `yaml:"audit_log" default:"audit.log"`
+
+	// File the AI usage of processed tickets is recorded to as JSON lines, the basis of cost estimates
+	UsageHistoryFile string `yaml:"usage_history_file" default:"ai-usage.log"`
+
+	// Raw output of AI CLI runs, written to rotating files per ticket instead of the main log
+	AILogs struct {
+		Dir           string `yaml:"dir" default:"ai-logs"`       // Directory of the per-ticket log files
+		MaxSizeMB     int    `yaml:"max_size_mb" default:"10"`    // Size at which a ticket's log file is rotated
+		MaxFiles      int    `yaml:"max_files" default:"3"`       // Rotated files kept per ticket
+		RetentionDays int    `yaml:"retention_days" default:"14"` // Log files not written to for this long are deleted
+	} `yaml:"ai_logs"`
+
+	// Processing log of every ticket, attached to the ticket when processing completes or fails, so developers can
+	// debug runs without server access
+	ProcessingLogs struct {
+		Attach    bool `yaml:"attach" default:"false"`     // Attach the log of each run to its ticket
+		MaxSizeKB int  `yaml:"max_size_kb" default:"1024"` // Size above which the start of the log is dropped
+	} `yaml:"processing_logs"`
+
+	// HashiCorp Vault the secret values referencing it are read from, e.g. api_token: vault:secret/data/ai#jira_token
+	Vault struct {
+		Address   string `yaml:"address"`    // Vault server URL, e.g. https://vault.example.com:8200
+		Token     string `yaml:"token"`      // Vault token
+		TokenFile string `yaml:"token_file"` // File holding the Vault token, instead of token
+		Namespace string `yaml:"namespace"`  // Vault Enterprise namespace, if any
+	} `yaml:"vault"`
+
+	// Reloads the configuration file on SIGHUP and, if watched, when it changes
+	ConfigReload struct {
+		Watch           bool `yaml:"watch" default:"false"`         // Reload the configuration file when it changes
+		IntervalSeconds int  `yaml:"interval_seconds" default:"10"` // How often the watched file is checked for changes
+	} `yaml:"config_reload"`
+
+	// Periodically resets tickets stuck in the in progress status without a pull request
+	Janitor struct {
+		Enabled           bool   `yaml:"enabled" default:"false"`
+		IntervalSeconds   int    `yaml:"interval_seconds" default:"900"`    // How often stuck tickets are searched
+		StuckAfterMinutes int    `yaml:"stuck_after_minutes" default:"120"` // Time without progress after which a ticket is stuck
+		Requeue           bool   `yaml:"requeue" default:"false"`           // Move stuck tickets back to the todo status to retry them
+		Label             string `yaml:"label" default:"ai-stuck"`          // Added to stuck tickets that aren't requeued
+	} `yaml:"janitor"`
+
+	// Periodically deletes the bot's branches whose pull requests were merged or closed long ago
+	BranchCleanup struct {
+		Enabled         bool     `yaml:"enabled" default:"false"`
+		IntervalSeconds int      `yaml:"interval_seconds" default:"86400"` // How often the branches are checked
+		AfterDays       int      `yaml:"after_days" default:"14"`          // Days after its pull request was closed before a branch is deleted
+		Protected       []string `yaml:"protected"`                        // Glob patterns of branches never deleted, e.g. release/*
+	} `yaml:"branch_cleanup"`
+
+	// Bounds operations that could otherwise hang forever; the AI CLIs have their own timeouts
+	Timeouts struct {
+		GitSeconds    int `yaml:"git_seconds" default:"600"`  // Maximum duration of a git operation, such as a clone or push
+		HTTPSeconds   int `yaml:"http_seconds" default:"60"`  // Maximum duration of a single Jira or GitHub API request
+		TicketMinutes int `yaml:"ticket_minutes" default:"0"` // Maximum duration of processing a ticket; 0 means unlimited
+	} `yaml:"timeouts"`
+
+	// Limits on the size of the AI's changes; larger changes aren't pushed, and the ticket asks for human guidance
+	DiffGuard struct {
+		MaxFiles int `yaml:"max_files" default:"0"` // Changed files allowed, 0 is unlimited
+		MaxLines int `yaml:"max_lines" default:"0"` // Changed lines allowed, 0 is unlimited
+		Retries  int `yaml:"retries" default:"0"`   // AI runs asked to narrow a change that is too large before giving up
+	} `yaml:"diff_guard"`
+
+	// Paths the AI must never modify, in CODEOWNERS (gitignore) syntax; its changes to them are reverted
+	ProtectedPaths struct {
+		Paths        []string            `yaml:"paths"`        // Protected in every repository, e.g. .github/workflows/
+		Repositories map[string][]string `yaml:"repositories"` // Also protected in a repository, by owner/repo
+	} `yaml:"protected_paths"`
+
+	// Scans the commits about to be pushed for secrets, blocking pushes that would publish them
+	SecretScan SecretScanConfig `yaml:"secret_scan"`
+
+	// Checks that AI runs don't modify files outside the repository checkout
+	WorkspaceGuard struct {
+		Enabled      bool     `yaml:"enabled" default:"false"` // Fail AI runs that modified files outside the repository checkout
+		GuardedPaths []string `yaml:"guarded_paths"`           // Directories checked for changes (default: the home and working directories)
+		IgnoredPaths []string `yaml:"ignored_paths"`           // Paths the AI may change besides the built-in caches and AI CLI state
+	} `yaml:"workspace_guard"`
+
+	// Notifications of PRs, failures and applied feedback sent to chat tools
+	Notifications struct {
+		Slack SlackNotificationConfig `yaml:"slack"`
+		Teams TeamsNotificationConfig `yaml:"teams"`
+		Email EmailNotificationConfig `yaml:"email"`
+	} `yaml:"notifications"`
+
+	// Cost reports aggregating the AI usage history per Jira project and component, for chargeback
+	CostReports struct {
+		Period    CostReportPeriod `yaml:"period" default:"month"` // Period usage is aggregated by: day, week or month
+		ExportDir string           `yaml:"export_dir"`             // Directory the CSV report of every closed period is written to; no exports if empty
+	} `yaml:"cost_reports"`
 }
 
-// LoadConfig loads configuration from a YAML file
+// LoadConfig loads configuration from a YAML file, overridden by environment variables named after the YAML paths
+// of the values, e.g. JIRA_API_TOKEN for jira.api_token
 func LoadConfig(configPath string) (*Config, error) {
 	// Read the config file
 	data, err := os.ReadFile(configPath)
@@ -163,64 +770,1168 @@ func LoadConfig(configPath string) (*Config, error) {
 		return nil, err
 	}
 
-	// Parse YAML
+	// Parse YAML, rejecting unknown keys and values of the wrong type with their location
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
 	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
+	if root.Kind != 0 {
+		if err := validateSchema(&root, ConfigSchema()); err != nil {
+			return nil, fmt.Errorf("invalid configuration file %s:\n%w", configPath, err)
+		}
+		if err := root.Decode(&config); err != nil {
+			return nil, err
+		}
+	}
+	if err := config.applyEnvOverrides(os.LookupEnv); err != nil {
+		return nil, err
+	}
+	if err := config.readSecretFiles(); err != nil {
 		return nil, err
 	}
 
-	// Set default for TargetBranch if not set
-	if config.GitHub.TargetBranch == "" {
-		config.GitHub.TargetBranch = "main"
+	// Set defaults for ticket branch names if not set
+	if config.GitHub.Branch.Template == "" {
+		config.GitHub.Branch.Template = DefaultBranchTemplate
+	}
+	if config.GitHub.Branch.MaxLength == 0 {
+		config.GitHub.Branch.MaxLength = 100
 	}
 
-	// Validate AI provider configuration
-	if err := config.validateAIProvider(); err != nil {
-		return nil, err
+	// Set defaults for Jira authentication if not set
+	if config.Jira.AuthMode == "" {
+		config.Jira.AuthMode = JiraAuthModeToken
+	}
+	if config.Jira.TokenType == "" {
+		config.Jira.TokenType = JiraTokenTypeAuto
+	}
+	if config.Jira.Connect.AppName == "" {
+		config.Jira.Connect.AppName = "Jira AI Issue Solver"
+	}
+	if len(config.Jira.Connect.Scopes) == 0 {
+		config.Jira.Connect.Scopes = []string{"READ", "WRITE"}
+	}
+	if config.Jira.Connect.InstallationsFile == "" {
+		config.Jira.Connect.InstallationsFile = "jira-connect-installations.json"
 	}
 
-	// Validate status transitions configuration
-	if err := config.validateStatusTransitions(); err != nil {
-		return nil, err
+	// Set default for AuthMode if not set
+	if config.GitHub.AuthMode == "" {
+		config.GitHub.AuthMode = GitHubAuthModePAT
 	}
 
-	// Validate logging configuration
-	if err := config.validateLogging(); err != nil {
-		return nil, err
+	// Set defaults for what marks tickets for processing and the order they're processed in if not set
+	if config.Jira.TriggerMode == "" {
+		config.Jira.TriggerMode = TriggerModeStatus
+	}
+	if config.Jira.TriggerLabel == "" {
+		config.Jira.TriggerLabel = LabelGoodForAI.String()
+	}
+	if config.Jira.ScanOrder == "" {
+		config.Jira.ScanOrder = ScanOrderPriority
+	}
+	if config.Jira.MaxConcurrentTickets == 0 {
+		config.Jira.MaxConcurrentTickets = 4
 	}
 
-	return &config, nil
-}
+	// Set default for the worklog comment if not set
+	if config.Jira.Worklog.Comment == "" {
+		config.Jira.Worklog.Comment = "Automated work by the AI issue solver"
+	}
 
-// validateAIProvider ensures only one AI provider is configured
-func (c *Config) validateAIProvider() error {
-	if c.AIProvider != "claude" && c.AIProvider != "gemini" {
-		return errors.New("ai_provider must be either 'claude' or 'gemini'")
+	// Set defaults for follow-up tickets if not set
+	if config.Jira.FollowUps.IssueType == "" {
+		config.Jira.FollowUps.IssueType = "Task"
+	}
+	if config.Jira.FollowUps.LinkType == "" {
+		config.Jira.FollowUps.LinkType = "Relates"
+	}
+	if config.Jira.FollowUps.MaxIssues == 0 {
+		config.Jira.FollowUps.MaxIssues = 5
+	}
+	if config.Jira.EpicBatching.MaxTickets == 0 {
+		config.Jira.EpicBatching.MaxTickets = 5
+	}
+	if config.Jira.EpicBatching.LinkType == "" {
+		config.Jira.EpicBatching.LinkType = "Relates"
 	}
-	return nil
-}
 
-// validateStatusTransitions ensures status transitions are properly configured
-func (c *Config) validateStatusTransitions() error {
-	if c.Jira.StatusTransitions.Todo == "" {
-		return errors.New("jira.status_transitions.todo cannot be empty")
+	// Set defaults for auto-merge if not set
+	if config.Jira.StatusTransitions.Done == "" {
+		config.Jira.StatusTransitions.Done = "Done"
 	}
-	if c.Jira.StatusTransitions.InProgress == "" {
-		return errors.New("jira.status_transitions.in_progress cannot be empty")
+	if config.GitHub.AutoMerge.Method == "" {
+		config.GitHub.AutoMerge.Method = MergeMethodSquash
 	}
-	if c.Jira.StatusTransitions.InReview == "" {
-		return errors.New("jira.status_transitions.in_review cannot be empty")
+
+	// Set default for the ticket state file if not set
+	if config.StateFile == "" {
+		config.StateFile = "ticket-states.json"
 	}
-	return nil
-}
 
-// validateLogging ensures logging configuration is valid
-func (c *Config) validateLogging() error {
-	if !c.Logging.Level.IsValid() {
-		return fmt.Errorf("invalid log level: %s. Valid options are: debug, info, warn, error", c.Logging.Level)
+	// Set defaults for documentation generation if not set
+	if config.AI.GenerateDocs == "" {
+		config.AI.GenerateDocs = GenerateDocsAlways
 	}
-	if !c.Logging.Format.IsValid() {
-		return fmt.Errorf("invalid log format: %s. Valid options are: console, json", c.Logging.Format)
+	if config.AI.DocsBootstrapFile == "" {
+		config.AI.DocsBootstrapFile = "docs-bootstrap.json"
 	}
-	return nil
+	if config.AI.NoChangeRetries == nil {
+		noChangeRetries := 1
+		config.AI.NoChangeRetries = &noChangeRetries
+	}
+	if config.AI.RepoMap.MaxDepth == 0 {
+		config.AI.RepoMap.MaxDepth = 3
+	}
+	if config.AI.RepoMap.MaxEntries == 0 {
+		config.AI.RepoMap.MaxEntries = 100
+	}
+	if config.AI.GitHistory.MaxCommits == 0 {
+		config.AI.GitHistory.MaxCommits = 5
+	}
+	if config.AI.GitHistory.MaxDiffLines == 0 {
+		config.AI.GitHistory.MaxDiffLines = 40
+	}
+	if config.AI.Precedents.IndexFile == "" {
+		config.AI.Precedents.IndexFile = "bot-changes.log"
+	}
+	if config.AI.Precedents.MaxChanges == 0 {
+		config.AI.Precedents.MaxChanges = 3
+	}
+	if config.AI.SessionSummaries.File == "" {
+		config.AI.SessionSummaries.File = "ai-sessions.log"
+	}
+	if config.AI.SessionSummaries.MaxSessions == 0 {
+		config.AI.SessionSummaries.MaxSessions = 5
+	}
+	if config.AI.SessionSummaries.MaxTokens == 0 {
+		config.AI.SessionSummaries.MaxTokens = 300
+	}
+	if config.AI.LanguageInstructions.MinShare == 0 {
+		config.AI.LanguageInstructions.MinShare = 20
+	}
+	if config.AI.Prompt.MaxCommentTokens == 0 {
+		config.AI.Prompt.MaxCommentTokens = 8000
+	}
+	if config.AI.Prompt.RecentComments == 0 {
+		config.AI.Prompt.RecentComments = 5
+	}
+	if config.AI.Prompt.MaxDiffTokens == 0 {
+		config.AI.Prompt.MaxDiffTokens = 15000
+	}
+
+	// Set default for the maintenance mode if not set
+	if config.Maintenance.RetryAfterSeconds == 0 {
+		config.Maintenance.RetryAfterSeconds = 300
+	}
+	if config.Health.CacheSeconds == 0 {
+		config.Health.CacheSeconds = 60
+	}
+	if config.Sandbox.Backend == "" {
+		config.Sandbox.Backend = SandboxBackendHost
+	}
+	if config.Sandbox.Docker.Network == "" {
+		config.Sandbox.Docker.Network = "none"
+	}
+	if config.Sandbox.Docker.CPUs == "" {
+		config.Sandbox.Docker.CPUs = "2"
+	}
+	if config.Sandbox.Docker.Memory == "" {
+		config.Sandbox.Docker.Memory = "4g"
+	}
+	if config.Sandbox.Docker.PIDs == 0 {
+		config.Sandbox.Docker.PIDs = 512
+	}
+	if config.Sandbox.Docker.Binary == "" {
+		config.Sandbox.Docker.Binary = "docker"
+	}
+	if config.CostReports.Period == "" {
+		config.CostReports.Period = CostReportMonthly
+	}
+
+	// Set default for the configuration reload if not set
+	if config.ConfigReload.IntervalSeconds == 0 {
+		config.ConfigReload.IntervalSeconds = 10
+	}
+
+	// Set default for the email notification SMTP port if not set
+	if config.Notifications.Email.Port == 0 {
+		config.Notifications.Email.Port = 587
+	}
+
+	// Set defaults for the janitor if not set
+	if config.Janitor.IntervalSeconds == 0 {
+		config.Janitor.IntervalSeconds = 900
+	}
+	if config.Janitor.StuckAfterMinutes == 0 {
+		config.Janitor.StuckAfterMinutes = 120
+	}
+	if config.Janitor.Label == "" {
+		config.Janitor.Label = "ai-stuck"
+	}
+	if config.BranchCleanup.IntervalSeconds == 0 {
+		config.BranchCleanup.IntervalSeconds = 86400
+	}
+	if config.BranchCleanup.AfterDays == 0 {
+		config.BranchCleanup.AfterDays = 14
+	}
+
+	// Set default for the CLI bootstrap cache if not set
+	if config.CLIBootstrap.CacheDir == "" {
+		config.CLIBootstrap.CacheDir = "cli-cache"
+	}
+
+	// Set default for the feedback watermark file if not set
+	if config.FeedbackWatermarkFile == "" {
+		config.FeedbackWatermarkFile = "feedback-watermarks.json"
+	}
+
+	// Set defaults for the AI output logs if not set
+	if config.ProcessingLogs.MaxSizeKB == 0 {
+		config.ProcessingLogs.MaxSizeKB = 1024
+	}
+	if config.AILogs.Dir == "" {
+		config.AILogs.Dir = "ai-logs"
+	}
+	if config.AILogs.MaxSizeMB == 0 {
+		config.AILogs.MaxSizeMB = 10
+	}
+	if config.AILogs.MaxFiles == 0 {
+		config.AILogs.MaxFiles = 3
+	}
+	if config.AILogs.RetentionDays == 0 {
+		config.AILogs.RetentionDays = 14
+	}
+
+	// Set defaults for the timeouts if not set
+	if config.Timeouts.GitSeconds == 0 {
+		config.Timeouts.GitSeconds = 600
+	}
+	if config.Timeouts.HTTPSeconds == 0 {
+		config.Timeouts.HTTPSeconds = 60
+	}
+
+	// Set default for the audit log if not set
+	if config.AuditLog == "" {
+		config.AuditLog = "audit.log"
+	}
+
+	// Set defaults for the build, lint and test check fix attempts if not set
+	checks := []*CheckCommandConfig{&config.Build, &config.Lint, &config.Test}
+	for _, override := range config.Components {
+		checks = append(checks, override.Build, override.Lint, override.Test)
+	}
+	for _, check := range checks {
+		if check != nil && check.MaxFixAttempts == 0 {
+			check.MaxFixAttempts = 2
+		}
+	}
+
+	// Set default for the AI usage history if not set
+	if config.UsageHistoryFile == "" {
+		config.UsageHistoryFile = "ai-usage.log"
+	}
+
+	// Set defaults for CI failure feedback if not set
+	if config.GitHub.CIFeedback.MaxLogLines == 0 {
+		config.GitHub.CIFeedback.MaxLogLines = 200
+	}
+	if config.GitHub.CIFeedback.MaxAttempts == 0 {
+		config.GitHub.CIFeedback.MaxAttempts = 3
+	}
+
+	// Set default for the stacked PR size threshold if not set
+	if config.GitHub.StackedPRs.MinChangedLines == 0 {
+		config.GitHub.StackedPRs.MinChangedLines = 400
+	}
+
+	// Set default for the compliance mode if not set
+	if config.GitHub.Compliance.Mode == "" {
+		config.GitHub.Compliance.Mode = ComplianceModeSkip
+	}
+
+	// Set defaults for git transport if not set
+	if config.GitHub.Git.Protocol == "" {
+		config.GitHub.Git.Protocol = GitProtocolHTTPS
+	}
+	if config.GitHub.Git.SSH.KnownHostsFile == "" {
+		config.GitHub.Git.SSH.KnownHostsFile = filepath.Join(config.TempDir, "known_hosts")
+	}
+
+	// Set default for the checkout mode of cached clones if not set
+	if config.GitHub.Worktrees.Mode == "" {
+		config.GitHub.Worktrees.Mode = CheckoutModeWorktree
+	}
+
+	// Set default for the feedback trigger if not set
+	if config.GitHub.FeedbackTrigger == "" {
+		config.GitHub.FeedbackTrigger = FeedbackTriggerAny
+	}
+
+	// Set default for the PR feedback concurrency if not set
+	if config.GitHub.FeedbackConcurrency == 0 {
+		config.GitHub.FeedbackConcurrency = 4
+	}
+
+	// Set defaults for the Jira API rate limit if not set. A negative rate disables throttling.
+	if config.Jira.RateLimit.RequestsPerSecond == 0 {
+		config.Jira.RateLimit.RequestsPerSecond = 10
+	}
+	if config.Jira.RateLimit.MaxRetries == 0 {
+		config.Jira.RateLimit.MaxRetries = 3
+	}
+	if config.Jira.RateLimit.MaxWaitSeconds == 0 {
+		config.Jira.RateLimit.MaxWaitSeconds = 300
+	}
+
+	// Set defaults for GitHub API retries if not set
+	if config.GitHub.APIRetry.MaxRetries == 0 {
+		config.GitHub.APIRetry.MaxRetries = 3
+	}
+	if config.GitHub.APIRetry.MaxWaitSeconds == 0 {
+		config.GitHub.APIRetry.MaxWaitSeconds = 300
+	}
+
+	// Set defaults for the handoff controls if not set
+	if config.GitHub.Handoff.Command == "" {
+		config.GitHub.Handoff.Command = "ai:stop"
+	}
+	if config.GitHub.Handoff.Label == "" {
+		config.GitHub.Handoff.Label = "ai-stop"
+	}
+
+	// Validate AI provider configuration
+	if err := config.validateAIProvider(); err != nil {
+		return nil, err
+	}
+
+	// Validate the sandbox the AI CLIs run in
+	if err := config.validateSandbox(); err != nil {
+		return nil, err
+	}
+
+	// Validate status transitions configuration
+	if err := config.validateStatusTransitions(); err != nil {
+		return nil, err
+	}
+
+	// Validate logging configuration
+	if err := config.validateLogging(); err != nil {
+		return nil, err
+	}
+
+	// Validate Jira authentication configuration
+	if err := config.validateJiraAuth(); err != nil {
+		return nil, err
+	}
+
+	// Validate GitHub authentication configuration
+	if err := config.validateGitHubAuth(); err != nil {
+		return nil, err
+	}
+
+	// Validate contribution compliance configuration
+	if err := config.validateCompliance(); err != nil {
+		return nil, err
+	}
+
+	// Validate commit signing configuration
+	if err := config.validateSigning(); err != nil {
+		return nil, err
+	}
+
+	// Validate auto-merge configuration
+	if err := config.validateAutoMerge(); err != nil {
+		return nil, err
+	}
+
+	// Validate git transport configuration
+	if err := config.validateGitTransport(); err != nil {
+		return nil, err
+	}
+	if !config.GitHub.Worktrees.Mode.IsValid() {
+		return nil, fmt.Errorf("invalid github worktrees mode: %s. Valid options are: worktree, reference", config.GitHub.Worktrees.Mode)
+	}
+
+	// Validate the feedback trigger configuration
+	if err := config.validateFeedbackTrigger(); err != nil {
+		return nil, err
+	}
+
+	// Validate the documentation generation configuration
+	if err := config.validateGenerateDocs(); err != nil {
+		return nil, err
+	}
+
+	// Validate the CLI bootstrap configuration
+	if err := config.validateCLIBootstrap(); err != nil {
+		return nil, err
+	}
+
+	// Validate the commit author configuration
+	if err := config.validateCommitAuthor(); err != nil {
+		return nil, err
+	}
+
+	// Validate the ticket branch names
+	if err := config.validateBranch(); err != nil {
+		return nil, err
+	}
+
+	// Validate the milestone and project board configuration
+	if err := config.validatePlanning(); err != nil {
+		return nil, err
+	}
+
+	// Validate pipeline steps and hooks configuration
+	if err := config.validatePipeline(); err != nil {
+		return nil, err
+	}
+
+	// Validate the timeouts
+	if err := config.validateTimeouts(); err != nil {
+		return nil, err
+	}
+
+	// Validate the branch cleanup
+	if err := config.validateBranchCleanup(); err != nil {
+		return nil, err
+	}
+
+	if err := config.validateDiffGuard(); err != nil {
+		return nil, err
+	}
+
+	if err := config.validateSecretScan(); err != nil {
+		return nil, err
+	}
+
+	// Validate the pattern-based repository mappings
+	if err := config.validateRepoMappings(); err != nil {
+		return nil, err
+	}
+
+	// Validate the notification sinks
+	if err := config.validateNotifications(); err != nil {
+		return nil, err
+	}
+
+	if !config.Jira.TriggerMode.IsValid() {
+		return nil, fmt.Errorf("invalid jira.trigger_mode: %s (must be status, label or both)", config.Jira.TriggerMode)
+	}
+
+	if !config.Jira.ScanOrder.IsValid() {
+		return nil, fmt.Errorf("invalid jira.scan_order: %s (must be priority, rank, created or updated)", config.Jira.ScanOrder)
+	}
+
+	if config.Jira.MaxConcurrentTickets < 0 {
+		return nil, fmt.Errorf("invalid jira.max_concurrent_tickets: %d (must be positive)", config.Jira.MaxConcurrentTickets)
+	}
+
+	if !config.CostReports.Period.IsValid() {
+		return nil, fmt.Errorf("invalid cost_reports.period: %s (must be day, week or month)", config.CostReports.Period)
+	}
+
+	// Validate the Vault references of secret values
+	if err := config.validateVault(); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// MissingSettings returns the YAML paths of the settings the application needs to start that aren't set
+func (c *Config) MissingSettings() []string {
+	var missing []string
+	if c.Jira.BaseURL == "" {
+		missing = append(missing, "jira.base_url")
+	}
+	if c.Jira.AuthMode == JiraAuthModeToken && c.Jira.Username == "" {
+		missing = append(missing, "jira.username")
+	}
+	if c.Jira.AuthMode == JiraAuthModeToken && c.Jira.APIToken == "" {
+		missing = append(missing, "jira.api_token")
+	}
+	if c.GitHub.AuthMode == GitHubAuthModePAT && c.GitHub.PersonalAccessToken == "" {
+		missing = append(missing, "github.personal_access_token")
+	}
+	if c.GitHub.BotUsername == "" {
+		missing = append(missing, "github.bot_username")
+	}
+	if c.GitHub.BotEmail == "" {
+		missing = append(missing, "github.bot_email")
+	}
+	if !c.HasRepoMappings() {
+		missing = append(missing, "component_to_repo")
+	}
+	return missing
+}
+
+// IsDraftPR reports whether pull requests for the given component should be opened as drafts
+func (c *Config) IsDraftPR(component string) bool {
+	if override, ok := c.componentOverride(component); ok && override.DraftPR != nil {
+		return *override.DraftPR
+	}
+	return c.GitHub.DraftPR
+}
+
+// IsSameRepo reports whether the given component's ticket branches are pushed to the repository itself instead of the
+// bot's fork
+func (c *Config) IsSameRepo(component string) bool {
+	if override, ok := c.componentOverride(component); ok && override.SameRepo != nil {
+		return *override.SameRepo
+	}
+	return c.GitHub.SameRepo
+}
+
+// GetReviewerPool returns the reviewer candidates for the given component, falling back to the global pool
+func (c *Config) GetReviewerPool(component string) []string {
+	if override, ok := c.componentOverride(component); ok && len(override.ReviewerPool) > 0 {
+		return override.ReviewerPool
+	}
+	return c.GitHub.ReviewerPool
+}
+
+// GetTransitionFields returns the resolution and screen fields of transitions to the status, matching the status
+// name case-insensitively like the transitions themselves
+func (c *Config) GetTransitionFields(status string) (TransitionFieldsConfig, bool) {
+	if fields, ok := c.Jira.TransitionFields[status]; ok {
+		return fields, true
+	}
+	for name, fields := range c.Jira.TransitionFields {
+		if strings.EqualFold(name, status) {
+			return fields, true
+		}
+	}
+	return TransitionFieldsConfig{}, false
+}
+
+// GetTargetBranch returns the branch the given component's pull requests target, falling back to the global one
+func (c *Config) GetTargetBranch(component string) string {
+	if override, ok := c.componentOverride(component); ok && override.TargetBranch != "" {
+		return override.TargetBranch
+	}
+	return c.GitHub.TargetBranch
+}
+
+// GetCloneOptions returns the clone settings for the given component, falling back to the global settings
+func (c *Config) GetCloneOptions(component string) CloneOptions {
+	if override, ok := c.componentOverride(component); ok && override.Clone != nil {
+		return *override.Clone
+	}
+	return c.GitHub.Clone
+}
+
+// GetCommitAuthor returns the commit author settings for the given component, falling back to the global settings
+func (c *Config) GetCommitAuthor(component string) CommitAuthorConfig {
+	if override, ok := c.componentOverride(component); ok && override.CommitAuthor != nil {
+		return *override.CommitAuthor
+	}
+	return c.GitHub.CommitAuthor
+}
+
+// GetGenerateDocs returns the documentation generation mode for the given component, falling back to the global mode
+func (c *Config) GetGenerateDocs(component string) GenerateDocsMode {
+	if override, ok := c.componentOverride(component); ok && override.GenerateDocs != "" {
+		return override.GenerateDocs
+	}
+	return c.AI.GenerateDocs
+}
+
+// GetDefaultReviewers returns the default reviewers for the given component, falling back to the global list
+func (c *Config) GetDefaultReviewers(component string) []string {
+	if override, ok := c.componentOverride(component); ok && len(override.DefaultReviewers) > 0 {
+		return override.DefaultReviewers
+	}
+	return c.GitHub.DefaultReviewers
+}
+
+// GetPRLabels returns the labels added to the pull requests of the given component, falling back to the global
+// pr_label and pr_labels
+func (c *Config) GetPRLabels(component string) []string {
+	if override, ok := c.componentOverride(component); ok && len(override.PRLabels) > 0 {
+		return override.PRLabels
+	}
+	var labels []string
+	for _, label := range append([]string{c.GitHub.PRLabel}, c.GitHub.PRLabels...) {
+		if label != "" && !slices.Contains(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// GetPlanning returns the milestone and project board of the given component's pull requests, falling back to the
+// global settings
+func (c *Config) GetPlanning(component string) PRPlanningConfig {
+	planning := c.GitHub.Planning
+	if override, ok := c.componentOverride(component); ok && override.Planning != nil {
+		planning = *override.Planning
+	}
+	if planning.Project.Field == "" {
+		planning.Project.Field = "Status"
+	}
+	return planning
+}
+
+// GetPipelineSteps returns the enabled pipeline steps for the given component in order,
+// falling back to the global pipeline and then to the default steps
+func (c *Config) GetPipelineSteps(component string) []PipelineStepConfig {
+	steps := c.Pipeline
+	if override, ok := c.componentOverride(component); ok && len(override.Pipeline) > 0 {
+		steps = override.Pipeline
+	}
+	if len(steps) == 0 {
+		defaults := make([]PipelineStepConfig, 0, len(DefaultPipelineSteps))
+		for _, name := range DefaultPipelineSteps {
+			defaults = append(defaults, PipelineStepConfig{Name: name})
+		}
+		return defaults
+	}
+
+	var enabled []PipelineStepConfig
+	for _, step := range steps {
+		if step.IsEnabled() {
+			enabled = append(enabled, step)
+		}
+	}
+	return enabled
+}
+
+// validatePipeline validates the global and per-component pipeline steps and hooks
+func (c *Config) validatePipeline() error {
+	if len(c.Pipeline) > 0 {
+		if err := validatePipelineSteps(c.Pipeline); err != nil {
+			return fmt.Errorf("invalid pipeline: %w", err)
+		}
+	}
+	if err := c.Hooks.validate(); err != nil {
+		return fmt.Errorf("invalid hooks: %w", err)
+	}
+	if err := c.Build.validate(); err != nil {
+		return fmt.Errorf("invalid build: %w", err)
+	}
+	if err := c.Test.validate(); err != nil {
+		return fmt.Errorf("invalid test: %w", err)
+	}
+	if err := c.Lint.validate(); err != nil {
+		return fmt.Errorf("invalid lint: %w", err)
+	}
+	if err := validateFormatters(c.Format); err != nil {
+		return fmt.Errorf("invalid format: %w", err)
+	}
+	for component, override := range c.Components {
+		if len(override.Pipeline) > 0 {
+			if err := validatePipelineSteps(override.Pipeline); err != nil {
+				return fmt.Errorf("invalid pipeline for component %s: %w", component, err)
+			}
+		}
+		if err := override.Hooks.validate(); err != nil {
+			return fmt.Errorf("invalid hooks for component %s: %w", component, err)
+		}
+		if override.Build != nil {
+			if err := override.Build.validate(); err != nil {
+				return fmt.Errorf("invalid build for component %s: %w", component, err)
+			}
+		}
+		if override.Test != nil {
+			if err := override.Test.validate(); err != nil {
+				return fmt.Errorf("invalid test for component %s: %w", component, err)
+			}
+		}
+		if override.Lint != nil {
+			if err := override.Lint.validate(); err != nil {
+				return fmt.Errorf("invalid lint for component %s: %w", component, err)
+			}
+		}
+		if err := validateFormatters(override.Format); err != nil {
+			return fmt.Errorf("invalid format for component %s: %w", component, err)
+		}
+	}
+	return nil
+}
+
+// GetBuildCheck returns the build check for the given component, falling back to the global build check
+func (c *Config) GetBuildCheck(component string) CheckCommandConfig {
+	if override, ok := c.componentOverride(component); ok && override.Build != nil {
+		return *override.Build
+	}
+	return c.Build
+}
+
+// GetTestCheck returns the test check for the given component, falling back to the global test check
+func (c *Config) GetTestCheck(component string) CheckCommandConfig {
+	if override, ok := c.componentOverride(component); ok && override.Test != nil {
+		return *override.Test
+	}
+	return c.Test
+}
+
+// GetLintCheck returns the lint check for the given component, falling back to the global lint check
+func (c *Config) GetLintCheck(component string) CheckCommandConfig {
+	if override, ok := c.componentOverride(component); ok && override.Lint != nil {
+		return *override.Lint
+	}
+	return c.Lint
+}
+
+// GetFormatters returns the formatters for the given component, falling back to the global formatters
+func (c *Config) GetFormatters(component string) []HookCommand {
+	if override, ok := c.componentOverride(component); ok && override.Format != nil {
+		return override.Format
+	}
+	return c.Format
+}
+
+// GetHooks returns the commands to run at the hook point for the given component,
+// falling back to the global hooks when the component doesn't configure the hook point
+func (c *Config) GetHooks(component string, point HookPoint) []HookCommand {
+	if override, ok := c.componentOverride(component); ok {
+		if commands, ok := override.Hooks[point]; ok {
+			return commands
+		}
+	}
+	return c.Hooks[point]
+}
+
+// validateAIProvider ensures only one AI provider is configured
+func (c *Config) validateAIProvider() error {
+	if c.AIProvider != "claude" && c.AIProvider != "gemini" {
+		return errors.New("ai_provider must be either 'claude' or 'gemini'")
+	}
+	if c.AIFallbackProvider != "" {
+		if c.AIFallbackProvider != "claude" && c.AIFallbackProvider != "gemini" {
+			return errors.New("ai_fallback_provider must be either 'claude' or 'gemini'")
+		}
+		if c.AIFallbackProvider == c.AIProvider {
+			return errors.New("ai_fallback_provider must differ from ai_provider")
+		}
+	}
+	if c.Claude.MaxConcurrentRuns < 0 || c.Gemini.MaxConcurrentRuns < 0 {
+		return errors.New("max_concurrent_runs must not be negative")
+	}
+	return nil
+}
+
+// AIProviders returns the configured AI providers, the primary one first
+func (c *Config) AIProviders() []string {
+	if c.AIFallbackProvider == "" {
+		return []string{c.AIProvider}
+	}
+	return []string{c.AIProvider, c.AIFallbackProvider}
+}
+
+// GetCLIRelease returns the pinned CLI release of an AI provider
+func (c *Config) GetCLIRelease(provider string) CLIRelease {
+	if provider == "gemini" {
+		return c.CLIBootstrap.Gemini
+	}
+	return c.CLIBootstrap.Claude
+}
+
+// GetCLIPath returns the path of an AI provider's CLI
+func (c *Config) GetCLIPath(provider string) string {
+	if provider == "gemini" {
+		return c.Gemini.CLIPath
+	}
+	return c.Claude.CLIPath
+}
+
+// GetNoChangeRetries returns how many times a run without changes is retried, once if not set
+func (c *Config) GetNoChangeRetries() int {
+	if c.AI.NoChangeRetries == nil {
+		return 1
+	}
+	return *c.AI.NoChangeRetries
+}
+
+// GetMaxConcurrentRuns returns how many CLI processes of an AI provider may run at once, 0 meaning unlimited
+func (c *Config) GetMaxConcurrentRuns(provider string) int {
+	if provider == "gemini" {
+		return c.Gemini.MaxConcurrentRuns
+	}
+	return c.Claude.MaxConcurrentRuns
+}
+
+// validateCLIBootstrap ensures every configured AI provider has a pinned release to install when the
+// CLI bootstrap is enabled
+func (c *Config) validateCLIBootstrap() error {
+	if !c.CLIBootstrap.Enabled {
+		return nil
+	}
+	for _, provider := range c.AIProviders() {
+		release := c.GetCLIRelease(provider)
+		if release.Version == "" {
+			return fmt.Errorf("cli_bootstrap.%s.version is required when the CLI bootstrap is enabled", provider)
+		}
+		if c.CLIBootstrap.Offline {
+			continue
+		}
+		if release.URL == "" {
+			return fmt.Errorf("cli_bootstrap.%s.url is required unless cli_bootstrap.offline is set", provider)
+		}
+		if len(release.SHA256) == 0 {
+			return fmt.Errorf("cli_bootstrap.%s.sha256 is required unless cli_bootstrap.offline is set", provider)
+		}
+	}
+	return nil
+}
+
+// validateStatusTransitions ensures status transitions are properly configured
+func (c *Config) validateStatusTransitions() error {
+	if c.Jira.StatusTransitions.Todo == "" {
+		return errors.New("jira.status_transitions.todo cannot be empty")
+	}
+	if c.Jira.StatusTransitions.InProgress == "" {
+		return errors.New("jira.status_transitions.in_progress cannot be empty")
+	}
+	if c.Jira.StatusTransitions.InReview == "" {
+		return errors.New("jira.status_transitions.in_review cannot be empty")
+	}
+	return nil
+}
+
+// validateLogging ensures logging configuration is valid
+func (c *Config) validateLogging() error {
+	if !c.Logging.Level.IsValid() {
+		return fmt.Errorf("invalid log level: %s. Valid options are: debug, info, warn, error", c.Logging.Level)
+	}
+	if !c.Logging.Format.IsValid() {
+		return fmt.Errorf("invalid log format: %s. Valid options are: console, json", c.Logging.Format)
+	}
+	return nil
+}
+
+// validateGitHubAuth ensures the GitHub authentication mode is properly configured
+func (c *Config) validateGitHubAuth() error {
+	if !c.GitHub.AuthMode.IsValid() {
+		return fmt.Errorf("invalid github auth mode: %s. Valid options are: pat, app", c.GitHub.AuthMode)
+	}
+	if c.GitHub.AuthMode == GitHubAuthModeApp {
+		if c.GitHub.AppID == 0 {
+			return errors.New("github.app_id is required when github.auth_mode is 'app'")
+		}
+		if c.GitHub.InstallationID == 0 {
+			return errors.New("github.installation_id is required when github.auth_mode is 'app'")
+		}
+		if c.GitHub.PrivateKeyPath == "" && c.GitHub.PrivateKey == "" {
+			return errors.New("github.private_key_path or github.private_key is required when github.auth_mode is 'app'")
+		}
+	}
+	return nil
+}
+
+// validateJiraAuth validates the Jira authentication configuration
+func (c *Config) validateJiraAuth() error {
+	if !c.Jira.AuthMode.IsValid() {
+		return fmt.Errorf("invalid jira auth mode: %s. Valid options are: token, connect", c.Jira.AuthMode)
+	}
+	if !c.Jira.TokenType.IsValid() {
+		return fmt.Errorf("invalid jira token type: %s. Valid options are: auto, bearer, basic", c.Jira.TokenType)
+	}
+	if c.Jira.AuthMode == JiraAuthModeConnect {
+		if c.Jira.Connect.AppKey == "" {
+			return errors.New("jira.connect.app_key is required when jira.auth_mode is 'connect'")
+		}
+		if c.Jira.Connect.BaseURL == "" {
+			return errors.New("jira.connect.base_url is required when jira.auth_mode is 'connect'")
+		}
+	}
+	return nil
+}
+
+// validateCompliance validates the contribution compliance configuration
+func (c *Config) validateCompliance() error {
+	if !c.GitHub.Compliance.Mode.IsValid() {
+		return fmt.Errorf("invalid github compliance mode: %s. Valid options are: skip, auto, signoff, block", c.GitHub.Compliance.Mode)
+	}
+	for repo, mode := range c.GitHub.Compliance.Repositories {
+		if !mode.IsValid() {
+			return fmt.Errorf("invalid github compliance mode for repository %s: %s. Valid options are: skip, auto, signoff, block", repo, mode)
+		}
+	}
+	return nil
+}
+
+// validateAutoMerge ensures auto-merge is properly configured
+func (c *Config) validateAutoMerge() error {
+	if !c.GitHub.AutoMerge.Enabled {
+		return nil
+	}
+	if !c.GitHub.AutoMerge.Method.IsValid() {
+		return fmt.Errorf("invalid github auto-merge method: %s. Valid options are: merge, squash, rebase", c.GitHub.AutoMerge.Method)
+	}
+	if c.Jira.StatusTransitions.Done == "" {
+		return errors.New("jira.status_transitions.done cannot be empty when auto-merge is enabled")
+	}
+	return nil
+}
+
+// validateFeedbackTrigger ensures the feedback trigger is properly configured
+func (c *Config) validateFeedbackTrigger() error {
+	if !c.GitHub.FeedbackTrigger.IsValid() {
+		return fmt.Errorf("invalid github feedback trigger: %s. Valid options are: any, mention", c.GitHub.FeedbackTrigger)
+	}
+	if c.GitHub.FeedbackTrigger == FeedbackTriggerMention && c.GitHub.BotUsername == "" {
+		return errors.New("github.bot_username cannot be empty when the feedback trigger is mention")
+	}
+	if c.GitHub.FeedbackConcurrency < 0 {
+		return errors.New("github.feedback_concurrency must be positive")
+	}
+	return nil
+}
+
+// validateGenerateDocs ensures the global and per-component documentation generation modes are valid
+func (c *Config) validateGenerateDocs() error {
+	if !c.AI.GenerateDocs.IsValid() {
+		return fmt.Errorf("invalid ai generate_docs: %s. Valid options are: off, once, always", c.AI.GenerateDocs)
+	}
+	for component, override := range c.Components {
+		if override.GenerateDocs != "" && !override.GenerateDocs.IsValid() {
+			return fmt.Errorf("invalid components.%s.generate_docs: %s. Valid options are: off, once, always", component, override.GenerateDocs)
+		}
+	}
+	return nil
+}
+
+// validateCommitAuthor ensures the global and per-component commit author settings are properly configured
+func (c *Config) validateCommitAuthor() error {
+	if err := validateCommitAuthorConfig("github.commit_author", c.GitHub.CommitAuthor); err != nil {
+		return err
+	}
+	for component, override := range c.Components {
+		if override.CommitAuthor == nil {
+			continue
+		}
+		if err := validateCommitAuthorConfig(fmt.Sprintf("components.%s.commit_author", component), *override.CommitAuthor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateCommitAuthorConfig ensures a commit author configuration is complete for its mode
+func validateCommitAuthorConfig(path string, author CommitAuthorConfig) error {
+	if !author.Mode.IsValid() {
+		return fmt.Errorf("invalid %s mode: %s. Valid options are: bot, identity, reporter", path, author.Mode)
+	}
+	if author.Mode == CommitAuthorIdentity && !author.Identity.IsSet() {
+		return fmt.Errorf("%s.identity name and email must be set in identity mode", path)
+	}
+	if author.Identity != (GitIdentity{}) && !author.Identity.IsSet() {
+		return fmt.Errorf("%s.identity must have both a name and an email", path)
+	}
+	for reporter, identity := range author.Reporters {
+		if !identity.IsSet() {
+			return fmt.Errorf("%s.reporters.%s must have both a name and an email", path, reporter)
+		}
+	}
+	return nil
+}
+
+// validateBranch ensures the target branches are valid branch names and the branch template renders one
+func (c *Config) validateBranch() error {
+	if c.GitHub.Branch.MaxLength < 0 {
+		return fmt.Errorf("github.branch.max_length must not be negative")
+	}
+	if err := validateBranchPrefix(c.GitHub.Branch.Prefix); err != nil {
+		return fmt.Errorf("invalid github.branch.prefix %q: not allowed in a git branch name", c.GitHub.Branch.Prefix)
+	}
+	if c.GitHub.TargetBranch != "" {
+		if err := ValidateBranchName(c.GitHub.TargetBranch); err != nil {
+			return fmt.Errorf("invalid github.target_branch: %w", err)
+		}
+	}
+	for component, override := range c.Components {
+		if override.TargetBranch == "" {
+			continue
+		}
+		if err := ValidateBranchName(override.TargetBranch); err != nil {
+			return fmt.Errorf("invalid components.%s.target_branch: %w", component, err)
+		}
+	}
+	example := BranchNameData{Prefix: c.GitHub.Branch.Prefix, Key: "PROJ-123", Summary: "Add CSV export", Type: "Task", Component: "backend"}
+	if _, err := RenderBranchName(c.GitHub.Branch.Template, c.GitHub.Branch.MaxLength, example); err != nil {
+		return fmt.Errorf("invalid github.branch.template: %w", err)
+	}
+	return nil
+}
+
+// validatePlanning ensures the project boards pull requests are added to are fully identified
+func (c *Config) validatePlanning() error {
+	if err := validatePlanningConfig("github.planning", c.GitHub.Planning); err != nil {
+		return err
+	}
+	for component, override := range c.Components {
+		if override.Planning == nil {
+			continue
+		}
+		if err := validatePlanningConfig(fmt.Sprintf("components.%s.planning", component), *override.Planning); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validatePlanningConfig ensures a project board has both its owner and number
+func validatePlanningConfig(path string, planning PRPlanningConfig) error {
+	project := planning.Project
+	if project.Number < 0 {
+		return fmt.Errorf("%s.project.number must not be negative", path)
+	}
+	if (project.Owner == "") != (project.Number == 0) {
+		return fmt.Errorf("%s.project needs both an owner and a number", path)
+	}
+	return nil
+}
+
+// validateGitTransport ensures the git protocols and SSH settings are properly configured
+func (c *Config) validateGitTransport() error {
+	if !c.GitHub.Git.Protocol.IsValid() {
+		return fmt.Errorf("invalid github git protocol: %s. Valid options are: https, ssh", c.GitHub.Git.Protocol)
+	}
+	for repo, protocol := range c.GitHub.Git.Repositories {
+		if !protocol.IsValid() {
+			return fmt.Errorf("invalid github git protocol for repository %s: %s. Valid options are: https, ssh", repo, protocol)
+		}
+	}
+	if !c.usesSSH() {
+		return nil
+	}
+
+	ssh := c.GitHub.Git.SSH
+	if ssh.KeyFile != "" {
+		if _, err := os.Stat(ssh.KeyFile); err != nil {
+			return fmt.Errorf("github git ssh key file not accessible: %w", err)
+		}
+	} else if os.Getenv("SSH_AUTH_SOCK") == "" {
+		return errors.New("github.git.ssh.key_file is required for SSH when no SSH agent is running")
+	}
+	if len(ssh.KnownHosts) == 0 && !ssh.AcceptNewHostKeys {
+		if _, err := os.Stat(ssh.KnownHostsFile); err != nil {
+			return errors.New("github.git.ssh.known_hosts is required for SSH unless known_hosts_file exists or accept_new_host_keys is enabled")
+		}
+	}
+	return nil
+}
+
+// validateSigning ensures commit signing is properly configured
+func (c *Config) validateSigning() error {
+	signing := c.GitHub.Signing
+	if !signing.Format.IsValid() {
+		return fmt.Errorf("invalid github signing format: %s. Valid options are: gpg, ssh", signing.Format)
+	}
+	if signing.Format == CommitSigningNone {
+		return nil
+	}
+	if signing.KeyFile == "" {
+		return errors.New("github.signing.key_file is required when commit signing is enabled")
+	}
+	if signing.Format == CommitSigningGPG && signing.KeyID == "" {
+		return errors.New("github.signing.key_id is required when github.signing.format is 'gpg'")
+	}
+	return nil
+}
+
+// validateTimeouts validates the operation timeouts
+func (c *Config) validateTimeouts() error {
+	if c.Timeouts.GitSeconds < 0 || c.Timeouts.HTTPSeconds < 0 {
+		return errors.New("timeouts.git_seconds and timeouts.http_seconds must be positive")
+	}
+	if c.Timeouts.TicketMinutes < 0 {
+		return errors.New("timeouts.ticket_minutes must not be negative")
+	}
+	return nil
+}
+
+// validateBranchCleanup ensures the cleanup interval and age are positive and the protected patterns are valid globs
+func (c *Config) validateBranchCleanup() error {
+	if c.BranchCleanup.IntervalSeconds < 0 || c.BranchCleanup.AfterDays < 0 {
+		return errors.New("branch_cleanup.interval_seconds and branch_cleanup.after_days must be positive")
+	}
+	for _, pattern := range c.BranchCleanup.Protected {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid branch_cleanup.protected pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// validateDiffGuard ensures the diff size limits aren't negative
+func (c *Config) validateDiffGuard() error {
+	if c.DiffGuard.MaxFiles < 0 || c.DiffGuard.MaxLines < 0 || c.DiffGuard.Retries < 0 {
+		return errors.New("diff_guard.max_files, diff_guard.max_lines and diff_guard.retries must not be negative")
+	}
+	return nil
+}
+
+// HTTPTimeout returns the maximum duration of a single Jira or GitHub API request
+func (c *Config) HTTPTimeout() time.Duration {
+	return time.Duration(c.Timeouts.HTTPSeconds) * time.Second
+}
+
+// ForkOwner returns the account the bot's forks belong to: the fork organization if configured, otherwise the bot
+func (c *Config) ForkOwner() string {
+	if c.GitHub.ForkOrganization != "" {
+		return c.GitHub.ForkOrganization
+	}
+	return c.GitHub.BotUsername
+}
+
+// GetProtectedPaths returns the patterns of paths the AI must never modify in a repository: the global ones and the
+// repository's own
+func (c *Config) GetProtectedPaths(owner, repo string) []string {
+	return append(slices.Clone(c.ProtectedPaths.Paths), c.ProtectedPaths.Repositories[owner+"/"+repo]...)
+}
+
+// GetGitProtocol returns the protocol git uses for a repository, falling back to the global protocol
+func (c *Config) GetGitProtocol(owner, repo string) GitProtocol {
+	if protocol, ok := c.GitHub.Git.Repositories[owner+"/"+repo]; ok {
+		return protocol
+	}
+	if c.GitHub.Git.Protocol == "" {
+		return GitProtocolHTTPS
+	}
+	return c.GitHub.Git.Protocol
+}
+
+// usesSSH reports whether any repository is cloned over SSH
+func (c *Config) usesSSH() bool {
+	if c.GitHub.Git.Protocol == GitProtocolSSH {
+		return true
+	}
+	for _, protocol := range c.GitHub.Git.Repositories {
+		if protocol == GitProtocolSSH {
+			return true
+		}
+	}
+	return false
+}
+
+// GetComplianceMode returns the contribution compliance mode for a repository.
+// Per-repository overrides take precedence; repositories of internal orgs are never checked.
+func (c *Config) GetComplianceMode(owner, repo string) ComplianceMode {
+	if mode, ok := c.GitHub.Compliance.Repositories[owner+"/"+repo]; ok {
+		return mode
+	}
+	for _, org := range c.GitHub.Compliance.InternalOrgs {
+		if strings.EqualFold(org, owner) {
+			return ComplianceModeSkip
+		}
+	}
+	if c.GitHub.Compliance.Mode == "" {
+		return ComplianceModeSkip
+	}
+	return c.GitHub.Compliance.Mode
+}
+
+// IsCLASigned reports whether the bot has signed the CLA of a repository
+func (c *Config) IsCLASigned(owner, repo string) bool {
+	for _, signed := range c.GitHub.Compliance.CLASigned {
+		if strings.EqualFold(signed, owner+"/"+repo) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvVarName returns the environment variable overriding the configuration value at the YAML path, e.g.
+// JIRA_API_TOKEN for jira.api_token
+func EnvVarName(path string) string {
+	return strings.ToUpper(strings.ReplaceAll(path, ".", "_"))
+}
+
+// applyEnvOverrides sets the configuration values whose environment variable is set, so secrets don't need to be
+// kept in the configuration file. Lists are given comma-separated. Maps and lists of objects can only be configured
+// in the file.
+func (c *Config) applyEnvOverrides(lookupEnv func(string) (string, bool)) error {
+	return applyEnvOverrides(reflect.ValueOf(c).Elem(), "", lookupEnv)
+}
+
+// applyEnvOverrides sets the fields of the struct value at the YAML path from their environment variables
+func applyEnvOverrides(value reflect.Value, path string, lookupEnv func(string) (string, bool)) error {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+		if path != "" {
+			name = path + "." + name
+		}
+
+		fieldValue := value.Field(i)
+		var node *yaml.Node
+		switch fieldValue.Kind() {
+		case reflect.Struct:
+			if err := applyEnvOverrides(fieldValue, name, lookupEnv); err != nil {
+				return err
+			}
+			continue
+		case reflect.String, reflect.Bool, reflect.Int, reflect.Int64, reflect.Float64:
+			env, ok := lookupEnv(EnvVarName(name))
+			if !ok {
+				continue
+			}
+			node = &yaml.Node{Kind: yaml.ScalarNode, Value: env}
+		case reflect.Slice:
+			if fieldValue.Type().Elem().Kind() != reflect.String {
+				continue
+			}
+			env, ok := lookupEnv(EnvVarName(name))
+			if !ok {
+				continue
+			}
+			node = &yaml.Node{Kind: yaml.SequenceNode}
+			for _, item := range strings.Split(env, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: item})
+				}
+			}
+		default:
+			continue
+		}
+
+		// Decoding like a YAML value applies the same parsing and checks as the configuration file
+		decoded := reflect.New(fieldValue.Type())
+		if err := node.Decode(decoded.Interface()); err != nil {
+			return fmt.Errorf("invalid value of %s: %w", EnvVarName(name), err)
+		}
+		fieldValue.Set(decoded.Elem())
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConfig_applyEnvOverrides(t *testing.T) {
+	env := map[string]string{
+		"JIRA_API_TOKEN":               "jira-token",
+		"GITHUB_PERSONAL_ACCESS_TOKEN": "github-token",
+		"GITHUB_APP_ID":                "12345",
+		"GITHUB_DRAFT_PR":              "true",
+		"JIRA_CONNECT_SCOPES":          "READ, WRITE,ADMIN",
+		"LOGGING_LEVEL":                "debug",
+		"GITHUB_TARGET_BRANCH":         "develop: #1",
+	}
+	lookupEnv := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	config := Config{}
+	config.Jira.APIToken = "from-file"
+	config.Jira.Username = "from-file"
+	if err := config.applyEnvOverrides(lookupEnv); err != nil {
+		t.Fatalf("applyEnvOverrides() error = %v", err)
+	}
+
+	if config.Jira.APIToken != "jira-token" {
+		t.Errorf("Jira.APIToken = %q, want jira-token", config.Jira.APIToken)
+	}
+	if config.Jira.Username != "from-file" {
+		t.Errorf("Jira.Username = %q, want the value from the file", config.Jira.Username)
+	}
+	if config.GitHub.PersonalAccessToken != "github-token" {
+		t.Errorf("GitHub.PersonalAccessToken = %q, want github-token", config.GitHub.PersonalAccessToken)
+	}
+	if config.GitHub.AppID != 12345 {
+		t.Errorf("GitHub.AppID = %d, want 12345", config.GitHub.AppID)
+	}
+	if !config.GitHub.DraftPR {
+		t.Error("GitHub.DraftPR = false, want true")
+	}
+	if want := []string{"READ", "WRITE", "ADMIN"}; !reflect.DeepEqual(config.Jira.Connect.Scopes, want) {
+		t.Errorf("Jira.Connect.Scopes = %v, want %v", config.Jira.Connect.Scopes, want)
+	}
+	if config.Logging.Level != LogLevelDebug {
+		t.Errorf("Logging.Level = %q, want debug", config.Logging.Level)
+	}
+	if config.GitHub.TargetBranch != "develop: #1" {
+		t.Errorf("GitHub.TargetBranch = %q, want the value verbatim", config.GitHub.TargetBranch)
+	}
+}
+
+func TestConfig_applyEnvOverrides_InvalidValue(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+	}{
+		{name: "integer", env: "GITHUB_APP_ID"},
+		{name: "boolean", env: "GITHUB_DRAFT_PR"},
+		{name: "custom type", env: "LOGGING_LEVEL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookupEnv := func(name string) (string, bool) {
+				if name == tt.env {
+					return "not-valid", true
+				}
+				return "", false
+			}
+
+			config := Config{}
+			err := config.applyEnvOverrides(lookupEnv)
+			if err == nil || !strings.Contains(err.Error(), tt.env) {
+				t.Errorf("applyEnvOverrides() error = %v, want an error naming %s", err, tt.env)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_EnvOverrides(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	configContent := `
+logging:
+  level: info
+  format: console
+ai_provider: "claude"
+jira:
+  api_token: from-file
+  status_transitions:
+    todo: "To Do"
+    in_progress: "In Progress"
+    in_review: "In Review"
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("JIRA_API_TOKEN", "from-env")
+
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.Jira.APIToken != "from-env" {
+		t.Errorf("Jira.APIToken = %q, want from-env", config.Jira.APIToken)
+	}
+}
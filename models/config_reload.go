@@ -0,0 +1,110 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// reloadMu guards the reloadable settings, which tickets being processed read while a reload replaces them
+var reloadMu sync.RWMutex
+
+// ApplyReloadable copies the settings that can change while the application runs from the new configuration.
+// Maps are replaced rather than modified, so tickets being processed see either the old or the new ones.
+func (c *Config) ApplyReloadable(next *Config) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	c.ComponentToRepo = next.ComponentToRepo
+	c.RepoMappings = next.RepoMappings
+	c.RepoProjectProperty = next.RepoProjectProperty
+	c.DefaultRepo = next.DefaultRepo
+	c.Components = next.Components
+	c.Jira.IntervalSeconds = next.Jira.IntervalSeconds
+	c.Janitor.IntervalSeconds = next.Janitor.IntervalSeconds
+	c.Janitor.StuckAfterMinutes = next.Janitor.StuckAfterMinutes
+	c.Jira.PromptFields = next.Jira.PromptFields
+	c.Jira.IssueTypes.Instructions = next.Jira.IssueTypes.Instructions
+	c.AI.LanguageInstructions = next.AI.LanguageInstructions
+	c.AI.Prompt = next.AI.Prompt
+}
+
+// componentOverride returns the overrides of the component, if it has any
+func (c *Config) componentOverride(component string) (ComponentConfig, bool) {
+	reloadMu.RLock()
+	defer reloadMu.RUnlock()
+	override, ok := c.Components[component]
+	return override, ok
+}
+
+// RepoFallbacks returns the project property and the default repository tickets without a mapped repository use
+func (c *Config) RepoFallbacks() (property, defaultRepo string) {
+	reloadMu.RLock()
+	defer reloadMu.RUnlock()
+	return c.RepoProjectProperty, c.DefaultRepo
+}
+
+// PromptFieldNames returns the names of the custom fields added to the prompt
+func (c *Config) PromptFieldNames() []string {
+	reloadMu.RLock()
+	defer reloadMu.RUnlock()
+	return c.Jira.PromptFields
+}
+
+// IssueTypeInstructions returns the configured instructions by issue type name
+func (c *Config) IssueTypeInstructions() map[string]string {
+	reloadMu.RLock()
+	defer reloadMu.RUnlock()
+	return c.Jira.IssueTypes.Instructions
+}
+
+// LanguageInstructionSettings returns the settings of the language and framework instructions
+func (c *Config) LanguageInstructionSettings() LanguageInstructionsConfig {
+	reloadMu.RLock()
+	defer reloadMu.RUnlock()
+	return c.AI.LanguageInstructions
+}
+
+// PromptLimits returns the limits of the comments and diffs passed to the AI
+func (c *Config) PromptLimits() PromptLimitsConfig {
+	reloadMu.RLock()
+	defer reloadMu.RUnlock()
+	return c.AI.Prompt
+}
+
+// ReloadableChanges returns the YAML paths of the settings that differ in the new configuration and can be applied
+// without a restart
+func (c *Config) ReloadableChanges(next *Config) []string {
+	current := *next
+	current.ApplyReloadable(c)
+	return configDiff(reflect.ValueOf(current), reflect.ValueOf(*next), "")
+}
+
+// UnsafeChanges returns the YAML paths of the settings that differ in the new configuration and only take effect
+// after a restart
+func (c *Config) UnsafeChanges(next *Config) []string {
+	expected := *next
+	expected.ApplyReloadable(c)
+	return configDiff(reflect.ValueOf(*c), reflect.ValueOf(expected), "")
+}
+
+// configDiff returns the YAML paths of the values that differ between the two struct values
+func configDiff(a, b reflect.Value, path string) []string {
+	var paths []string
+	for i := 0; i < a.NumField(); i++ {
+		field := a.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+		if path != "" {
+			name = path + "." + name
+		}
+
+		if field.Type.Kind() == reflect.Struct {
+			paths = append(paths, configDiff(a.Field(i), b.Field(i), name)...)
+		} else if !reflect.DeepEqual(a.Field(i).Interface(), b.Field(i).Interface()) {
+			paths = append(paths, name)
+		}
+	}
+	return paths
+}
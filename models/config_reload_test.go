@@ -0,0 +1,53 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConfig_ReloadableAndUnsafeChanges(t *testing.T) {
+	current := &Config{}
+	current.ComponentToRepo = map[string]string{"backend": "https://github.com/example/backend.git"}
+	current.Jira.IntervalSeconds = 300
+	current.GitHub.TargetBranch = "main"
+
+	next := *current
+	next.ComponentToRepo = map[string]string{"backend": "https://github.com/example/api.git"}
+	next.Jira.IntervalSeconds = 60
+
+	if changes := current.ReloadableChanges(&next); !reflect.DeepEqual(changes, []string{"jira.interval_seconds", "component_to_repo"}) {
+		t.Errorf("ReloadableChanges() = %v", changes)
+	}
+	if unsafe := current.UnsafeChanges(&next); len(unsafe) != 0 {
+		t.Errorf("UnsafeChanges() = %v, want none", unsafe)
+	}
+
+	next.GitHub.TargetBranch = "develop"
+	next.Logging.Level = LogLevelDebug
+	if unsafe := current.UnsafeChanges(&next); !reflect.DeepEqual(unsafe, []string{"logging.level", "github.target_branch"}) {
+		t.Errorf("UnsafeChanges() = %v", unsafe)
+	}
+}
+
+func TestConfig_ApplyReloadable(t *testing.T) {
+	current := &Config{}
+	current.GitHub.TargetBranch = "main"
+
+	next := &Config{}
+	next.ComponentToRepo = map[string]string{"backend": "https://github.com/example/backend.git"}
+	next.Janitor.IntervalSeconds = 60
+	next.Jira.IssueTypes.Instructions = map[string]string{"Bug": "Add a regression test."}
+	next.AI.Prompt.MaxDiffTokens = 5000
+	next.GitHub.TargetBranch = "develop"
+
+	current.ApplyReloadable(next)
+	if current.ComponentToRepo["backend"] == "" || current.Janitor.IntervalSeconds != 60 {
+		t.Errorf("Expected the reloadable settings to be applied, got %+v", current)
+	}
+	if current.IssueTypeInstructions()["Bug"] == "" || current.PromptLimits().MaxDiffTokens != 5000 {
+		t.Errorf("Expected the prompt settings to be applied, got %+v", current.AI.Prompt)
+	}
+	if current.GitHub.TargetBranch != "main" {
+		t.Errorf("Expected the target branch to be kept, got %s", current.GitHub.TargetBranch)
+	}
+}
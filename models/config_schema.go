@@ -0,0 +1,238 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// JSONSchema is a JSON Schema describing a configuration value
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	AdditionalProperties interface{}            `json:"additionalProperties,omitempty"` // false or the schema of map values
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Default              interface{}            `json:"default,omitempty"`
+}
+
+// ConfigSchema returns the JSON Schema of the configuration file, generated from the Config struct
+func ConfigSchema() *JSONSchema {
+	schema := typeSchema(reflect.TypeOf(Config{}))
+	schema.Schema = "https://json-schema.org/draft/2020-12/schema"
+	schema.Title = "jira-ai-issue-solver configuration"
+	return schema
+}
+
+// typeSchema returns the JSON Schema of values of the type
+func typeSchema(t reflect.Type) *JSONSchema {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return typeSchema(t.Elem())
+	case reflect.Struct:
+		schema := &JSONSchema{Type: "object", Properties: make(map[string]*JSONSchema), AdditionalProperties: false}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if name == "" || name == "-" || !field.IsExported() {
+				continue
+			}
+			property := typeSchema(field.Type)
+			if value, ok := field.Tag.Lookup("default"); ok {
+				property.Default = defaultValue(property.Type, value)
+			}
+			schema.Properties[name] = property
+		}
+		return schema
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: typeSchema(t.Elem())}
+	case reflect.Slice:
+		return &JSONSchema{Type: "array", Items: typeSchema(t.Elem())}
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int64:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.Interface:
+		// Values passed on verbatim, e.g. Jira field values, may have any type
+		return &JSONSchema{}
+	default:
+		return &JSONSchema{Type: "string"}
+	}
+}
+
+// defaultValue converts the default tag of a field to a value of the field's JSON type
+func defaultValue(schemaType, value string) interface{} {
+	switch schemaType {
+	case "boolean":
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	case "integer":
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	case "number":
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	case "string":
+		return value
+	}
+	return nil
+}
+
+// ConfigSchemaError is a value of the configuration file that doesn't match the schema
+type ConfigSchemaError struct {
+	Line    int
+	Column  int
+	Path    string // YAML path of the value, e.g. jira.status_transitions
+	Message string
+}
+
+func (e *ConfigSchemaError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s: %s", e.Line, e.Column, e.Path, e.Message)
+}
+
+// validateSchema checks the parsed configuration file against the schema, returning an error for every unknown key
+// and every value of the wrong type
+func validateSchema(node *yaml.Node, schema *JSONSchema) error {
+	var errs []error
+	if node.Kind == yaml.DocumentNode {
+		for _, content := range node.Content {
+			errs = append(errs, checkSchema(content, schema, "")...)
+		}
+	} else {
+		errs = checkSchema(node, schema, "")
+	}
+	return errors.Join(errs...)
+}
+
+// checkSchema returns the errors of the node at the YAML path against the schema
+func checkSchema(node *yaml.Node, schema *JSONSchema, path string) []error {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind == yaml.ScalarNode && node.ShortTag() == "!!null" {
+		return nil
+	}
+	mismatch := func(expected string) []error {
+		return []error{&ConfigSchemaError{Line: node.Line, Column: node.Column, Path: displayPath(path), Message: "expected " + expected}}
+	}
+
+	switch schema.Type {
+	case "object":
+		if node.Kind != yaml.MappingNode {
+			return mismatch("a mapping")
+		}
+		var errs []error
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "<<" {
+				// Merge keys are checked where the merged mapping is defined
+				continue
+			}
+			keyPath := key.Value
+			if path != "" {
+				keyPath = path + "." + key.Value
+			}
+			property, known := schema.Properties[key.Value]
+			if !known {
+				if valueSchema, ok := schema.AdditionalProperties.(*JSONSchema); ok {
+					property, known = valueSchema, true
+				}
+			}
+			if !known {
+				message := "unknown key"
+				if suggestion := closestKey(key.Value, schema.Properties); suggestion != "" {
+					message += fmt.Sprintf(" (did you mean %s?)", suggestion)
+				}
+				errs = append(errs, &ConfigSchemaError{Line: key.Line, Column: key.Column, Path: keyPath, Message: message})
+				continue
+			}
+			errs = append(errs, checkSchema(value, property, keyPath)...)
+		}
+		return errs
+	case "array":
+		if node.Kind != yaml.SequenceNode {
+			return mismatch("a list")
+		}
+		var errs []error
+		for i, item := range node.Content {
+			errs = append(errs, checkSchema(item, schema.Items, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return errs
+	case "boolean":
+		if node.Kind != yaml.ScalarNode || node.ShortTag() != "!!bool" {
+			return mismatch("true or false")
+		}
+	case "integer":
+		if node.Kind != yaml.ScalarNode || node.ShortTag() != "!!int" {
+			return mismatch("an integer")
+		}
+	case "number":
+		if node.Kind != yaml.ScalarNode || (node.ShortTag() != "!!int" && node.ShortTag() != "!!float") {
+			return mismatch("a number")
+		}
+	case "":
+		// Any value
+	default:
+		if node.Kind != yaml.ScalarNode {
+			return mismatch("a string")
+		}
+	}
+	return nil
+}
+
+// displayPath returns the YAML path for messages, naming the top level of the file if empty
+func displayPath(path string) string {
+	if path == "" {
+		return "configuration"
+	}
+	return path
+}
+
+// closestKey returns the known key a mistyped key most likely meant, or an empty string if none is close
+func closestKey(key string, properties map[string]*JSONSchema) string {
+	candidates := make([]string, 0, len(properties))
+	for candidate := range properties {
+		candidates = append(candidates, candidate)
+	}
+	sort.Strings(candidates)
+
+	best, bestDistance := "", 3
+	for _, candidate := range candidates {
+		if distance := editDistance(key, candidate); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidateSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr []string
+	}{
+		{
+			name: "valid",
+			yaml: `
+jira:
+  interval_seconds: 60
+  status_transitions:
+    todo: To Do
+  transition_fields:
+    Done:
+      resolution: Fixed
+      fields:
+        fixVersions: [{name: "2.3"}]
+        Story Points: 3
+github:
+  draft_pr: true
+  reviewer_pool: [alice, bob]
+component_to_repo:
+  backend: https://github.com/example/backend.git
+pipeline:
+  - name: test
+    command: make test
+`,
+		},
+		{
+			name: "misspelled key",
+			yaml: `
+jira:
+  status_transistions:
+    todo: To Do
+`,
+			wantErr: []string{"line 3, column 3: jira.status_transistions: unknown key (did you mean status_transitions?)"},
+		},
+		{
+			name: "unknown key without suggestion",
+			yaml: `
+something_else: true
+`,
+			wantErr: []string{"line 2, column 1: something_else: unknown key"},
+		},
+		{
+			name: "wrong types",
+			yaml: `
+jira:
+  interval_seconds: often
+github:
+  draft_pr: sometimes
+  reviewer_pool: alice
+component_to_repo:
+  backend:
+    url: https://github.com/example/backend.git
+`,
+			wantErr: []string{
+				"line 3, column 21: jira.interval_seconds: expected an integer",
+				"line 5, column 13: github.draft_pr: expected true or false",
+				"line 6, column 18: github.reviewer_pool: expected a list",
+				"line 9, column 5: component_to_repo.backend: expected a string",
+			},
+		},
+		{
+			name: "unknown key in list item",
+			yaml: `
+pipeline:
+  - name: test
+    comand: make test
+`,
+			wantErr: []string{"line 4, column 5: pipeline[0].comand: unknown key (did you mean command?)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var root yaml.Node
+			if err := yaml.Unmarshal([]byte(tt.yaml), &root); err != nil {
+				t.Fatal(err)
+			}
+			err := validateSchema(&root, ConfigSchema())
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Errorf("validateSchema() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("validateSchema() error = nil, want %v", tt.wantErr)
+			}
+			if got := strings.Split(err.Error(), "\n"); strings.Join(got, "|") != strings.Join(tt.wantErr, "|") {
+				t.Errorf("validateSchema() errors =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.wantErr, "\n"))
+			}
+		})
+	}
+}
+
+func TestLoadConfig_RejectsUnknownKeys(t *testing.T) {
+	configPath := t.TempDir() + "/config.yaml"
+	if err := os.WriteFile(configPath, []byte("jira:\n  status_transistions:\n    todo: To Do\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadConfig(configPath)
+	if err == nil || !strings.Contains(err.Error(), "did you mean status_transitions?") {
+		t.Errorf("LoadConfig() error = %v, want the misspelled key reported", err)
+	}
+}
+
+func TestConfigSchema_MatchesFile(t *testing.T) {
+	data, err := os.ReadFile("../config.schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated, err := json.MarshalIndent(ConfigSchema(), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(generated)+"\n" {
+		t.Error("config.schema.json is outdated; regenerate it with `go run . schema > config.schema.json`")
+	}
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// vaultReferencePrefix marks secret values read from Vault, e.g. vault:secret/data/ai#jira_token
+const vaultReferencePrefix = "vault:"
+
+// SecretSetting is a configuration value that can be read from a file or Vault instead of being inlined
+type SecretSetting struct {
+	Path     string  // YAML path of the value, e.g. jira.api_token
+	Value    *string // The value
+	File     string  // File holding the value, if set
+	FilePath string  // YAML path of the file setting
+}
+
+// SecretSettings returns the secret values of the configuration
+func (c *Config) SecretSettings() []SecretSetting {
+	return []SecretSetting{
+		{Path: "server.admin_token", Value: &c.Server.AdminToken, File: c.Server.AdminTokenFile, FilePath: "server.admin_token_file"},
+		{Path: "jira.api_token", Value: &c.Jira.APIToken, File: c.Jira.APITokenFile, FilePath: "jira.api_token_file"},
+		{Path: "jira.worklog.api_token", Value: &c.Jira.Worklog.APIToken, File: c.Jira.Worklog.APITokenFile, FilePath: "jira.worklog.api_token_file"},
+		{Path: "github.personal_access_token", Value: &c.GitHub.PersonalAccessToken, File: c.GitHub.PersonalAccessTokenFile, FilePath: "github.personal_access_token_file"},
+		{Path: "github.private_key", Value: &c.GitHub.PrivateKey},
+		{Path: "gemini.api_key", Value: &c.Gemini.APIKey, File: c.Gemini.APIKeyFile, FilePath: "gemini.api_key_file"},
+		{Path: "notifications.slack.webhook_url", Value: &c.Notifications.Slack.WebhookURL, File: c.Notifications.Slack.WebhookURLFile, FilePath: "notifications.slack.webhook_url_file"},
+		{Path: "notifications.teams.webhook_url", Value: &c.Notifications.Teams.WebhookURL, File: c.Notifications.Teams.WebhookURLFile, FilePath: "notifications.teams.webhook_url_file"},
+		{Path: "notifications.email.password", Value: &c.Notifications.Email.Password, File: c.Notifications.Email.PasswordFile, FilePath: "notifications.email.password_file"},
+		{Path: "vault.token", Value: &c.Vault.Token, File: c.Vault.TokenFile, FilePath: "vault.token_file"},
+	}
+}
+
+// readSecretFiles sets the secret values configured as files to the files' content
+func (c *Config) readSecretFiles() error {
+	for _, setting := range c.SecretSettings() {
+		if setting.File == "" {
+			continue
+		}
+		if *setting.Value != "" {
+			return fmt.Errorf("only one of %s and %s can be set", setting.Path, setting.FilePath)
+		}
+		data, err := os.ReadFile(setting.File)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", setting.FilePath, err)
+		}
+		// Files mounted from secret stores often end with a newline
+		*setting.Value = strings.TrimSpace(string(data))
+	}
+	return nil
+}
+
+// ParseVaultReference returns the Vault path and key a secret value of the form vault:<path>#<key> refers to
+func ParseVaultReference(value string) (path, key string, ok bool) {
+	reference, isReference := strings.CutPrefix(value, vaultReferencePrefix)
+	if !isReference {
+		return "", "", false
+	}
+	path, key, found := strings.Cut(reference, "#")
+	if !found || path == "" || key == "" {
+		return "", "", false
+	}
+	return strings.Trim(path, "/"), key, true
+}
+
+// validateVault ensures secret values referencing Vault are well-formed and Vault is configured to read them from
+func (c *Config) validateVault() error {
+	for _, setting := range c.SecretSettings() {
+		if !strings.HasPrefix(*setting.Value, vaultReferencePrefix) {
+			continue
+		}
+		if _, _, ok := ParseVaultReference(*setting.Value); !ok {
+			return fmt.Errorf("invalid vault reference in %s: expected vault:<path>#<key>", setting.Path)
+		}
+		if setting.Path == "vault.token" {
+			return errors.New("vault.token can't be read from Vault")
+		}
+		if c.Vault.Address == "" {
+			return fmt.Errorf("vault.address is required when %s is read from Vault", setting.Path)
+		}
+	}
+	return nil
+}
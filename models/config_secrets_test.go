@@ -0,0 +1,111 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfig_readSecretFiles(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "jira-token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := Config{}
+	config.Jira.APITokenFile = tokenFile
+	if err := config.readSecretFiles(); err != nil {
+		t.Fatalf("readSecretFiles() error = %v", err)
+	}
+	if config.Jira.APIToken != "file-token" {
+		t.Errorf("Jira.APIToken = %q, want the trimmed file content", config.Jira.APIToken)
+	}
+
+	config = Config{}
+	config.Jira.APIToken = "inline"
+	config.Jira.APITokenFile = tokenFile
+	if err := config.readSecretFiles(); err == nil {
+		t.Error("Expected an error when both api_token and api_token_file are set")
+	}
+
+	config = Config{}
+	config.GitHub.PersonalAccessTokenFile = filepath.Join(t.TempDir(), "missing")
+	if err := config.readSecretFiles(); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestParseVaultReference(t *testing.T) {
+	tests := []struct {
+		value    string
+		wantPath string
+		wantKey  string
+		wantOK   bool
+	}{
+		{value: "vault:secret/data/ai#jira_token", wantPath: "secret/data/ai", wantKey: "jira_token", wantOK: true},
+		{value: "vault:/secret/data/ai/#token", wantPath: "secret/data/ai", wantKey: "token", wantOK: true},
+		{value: "vault:secret/data/ai", wantOK: false},
+		{value: "vault:#token", wantOK: false},
+		{value: "plain-token", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			path, key, ok := ParseVaultReference(tt.value)
+			if ok != tt.wantOK || path != tt.wantPath || key != tt.wantKey {
+				t.Errorf("ParseVaultReference() = %q, %q, %v, want %q, %q, %v", path, key, ok, tt.wantPath, tt.wantKey, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestConfig_validateVault(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(c *Config)
+		wantErr bool
+	}{
+		{
+			name:  "no references",
+			setup: func(c *Config) { c.Jira.APIToken = "token" },
+		},
+		{
+			name: "reference with address",
+			setup: func(c *Config) {
+				c.Jira.APIToken = "vault:secret/data/ai#jira_token"
+				c.Vault.Address = "https://vault.example.com"
+			},
+		},
+		{
+			name:    "reference without address",
+			setup:   func(c *Config) { c.Jira.APIToken = "vault:secret/data/ai#jira_token" },
+			wantErr: true,
+		},
+		{
+			name: "malformed reference",
+			setup: func(c *Config) {
+				c.GitHub.PersonalAccessToken = "vault:secret/data/ai"
+				c.Vault.Address = "https://vault.example.com"
+			},
+			wantErr: true,
+		},
+		{
+			name: "vault token from vault",
+			setup: func(c *Config) {
+				c.Vault.Token = "vault:secret/data/ai#token"
+				c.Vault.Address = "https://vault.example.com"
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{}
+			tt.setup(&config)
+			if err := config.validateVault(); (err != nil) != tt.wantErr {
+				t.Errorf("validateVault() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,136 +2,57 @@ package models
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
 func TestConfig_validateStatusTransitions(t *testing.T) {
 	tests := []struct {
-		name    string
-		config  Config
-		wantErr bool
+		name       string
+		todo       string
+		inProgress string
+		inReview   string
+		wantErr    bool
 	}{
 		{
-			name: "valid status transitions",
-			config: Config{
-				Jira: struct {
-					BaseURL                 string `yaml:"base_url"`
-					Username                string `yaml:"username"`
-					APIToken                string `yaml:"api_token"`
-					IntervalSeconds         int    `yaml:"interval_seconds" default:"300"`
-					DisableErrorComments    bool   `yaml:"disable_error_comments" default:"false"`
-					GitPullRequestFieldName string `yaml:"git_pull_request_field_name"`
-					StatusTransitions       struct {
-						Todo       string `yaml:"todo" default:"To Do"`
-						InProgress string `yaml:"in_progress" default:"In Progress"`
-						InReview   string `yaml:"in_review" default:"In Review"`
-					} `yaml:"status_transitions"`
-				}{
-					StatusTransitions: struct {
-						Todo       string `yaml:"todo" default:"To Do"`
-						InProgress string `yaml:"in_progress" default:"In Progress"`
-						InReview   string `yaml:"in_review" default:"In Review"`
-					}{
-						Todo:       "To Do",
-						InProgress: "In Progress",
-						InReview:   "In Review",
-					},
-				},
-			},
-			wantErr: false,
+			name:       "valid status transitions",
+			todo:       "To Do",
+			inProgress: "In Progress",
+			inReview:   "In Review",
+			wantErr:    false,
 		},
 		{
-			name: "empty todo status",
-			config: Config{
-				Jira: struct {
-					BaseURL                 string `yaml:"base_url"`
-					Username                string `yaml:"username"`
-					APIToken                string `yaml:"api_token"`
-					IntervalSeconds         int    `yaml:"interval_seconds" default:"300"`
-					DisableErrorComments    bool   `yaml:"disable_error_comments" default:"false"`
-					GitPullRequestFieldName string `yaml:"git_pull_request_field_name"`
-					StatusTransitions       struct {
-						Todo       string `yaml:"todo" default:"To Do"`
-						InProgress string `yaml:"in_progress" default:"In Progress"`
-						InReview   string `yaml:"in_review" default:"In Review"`
-					} `yaml:"status_transitions"`
-				}{
-					StatusTransitions: struct {
-						Todo       string `yaml:"todo" default:"To Do"`
-						InProgress string `yaml:"in_progress" default:"In Progress"`
-						InReview   string `yaml:"in_review" default:"In Review"`
-					}{
-						Todo:       "",
-						InProgress: "In Progress",
-						InReview:   "In Review",
-					},
-				},
-			},
-			wantErr: true,
+			name:       "empty todo status",
+			todo:       "",
+			inProgress: "In Progress",
+			inReview:   "In Review",
+			wantErr:    true,
 		},
 		{
-			name: "empty in_progress status",
-			config: Config{
-				Jira: struct {
-					BaseURL                 string `yaml:"base_url"`
-					Username                string `yaml:"username"`
-					APIToken                string `yaml:"api_token"`
-					IntervalSeconds         int    `yaml:"interval_seconds" default:"300"`
-					DisableErrorComments    bool   `yaml:"disable_error_comments" default:"false"`
-					GitPullRequestFieldName string `yaml:"git_pull_request_field_name"`
-					StatusTransitions       struct {
-						Todo       string `yaml:"todo" default:"To Do"`
-						InProgress string `yaml:"in_progress" default:"In Progress"`
-						InReview   string `yaml:"in_review" default:"In Review"`
-					} `yaml:"status_transitions"`
-				}{
-					StatusTransitions: struct {
-						Todo       string `yaml:"todo" default:"To Do"`
-						InProgress string `yaml:"in_progress" default:"In Progress"`
-						InReview   string `yaml:"in_review" default:"In Review"`
-					}{
-						Todo:       "To Do",
-						InProgress: "",
-						InReview:   "In Review",
-					},
-				},
-			},
-			wantErr: true,
+			name:       "empty in_progress status",
+			todo:       "To Do",
+			inProgress: "",
+			inReview:   "In Review",
+			wantErr:    true,
 		},
 		{
-			name: "empty in_review status",
-			config: Config{
-				Jira: struct {
-					BaseURL                 string `yaml:"base_url"`
-					Username                string `yaml:"username"`
-					APIToken                string `yaml:"api_token"`
-					IntervalSeconds         int    `yaml:"interval_seconds" default:"300"`
-					DisableErrorComments    bool   `yaml:"disable_error_comments" default:"false"`
-					GitPullRequestFieldName string `yaml:"git_pull_request_field_name"`
-					StatusTransitions       struct {
-						Todo       string `yaml:"todo" default:"To Do"`
-						InProgress string `yaml:"in_progress" default:"In Progress"`
-						InReview   string `yaml:"in_review" default:"In Review"`
-					} `yaml:"status_transitions"`
-				}{
-					StatusTransitions: struct {
-						Todo       string `yaml:"todo" default:"To Do"`
-						InProgress string `yaml:"in_progress" default:"In Progress"`
-						InReview   string `yaml:"in_review" default:"In Review"`
-					}{
-						Todo:       "To Do",
-						InProgress: "In Progress",
-						InReview:   "",
-					},
-				},
-			},
-			wantErr: true,
+			name:       "empty in_review status",
+			todo:       "To Do",
+			inProgress: "In Progress",
+			inReview:   "",
+			wantErr:    true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := tt.config.validateStatusTransitions()
+			config := Config{}
+			config.Jira.StatusTransitions.Todo = tt.todo
+			config.Jira.StatusTransitions.InProgress = tt.inProgress
+			config.Jira.StatusTransitions.InReview = tt.inReview
+
+			err := config.validateStatusTransitions()
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Config.validateStatusTransitions() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -190,8 +111,8 @@ github:
 	}
 }
 
-func TestLoadConfig_WithDefaultTargetBranch(t *testing.T) {
-	// Create a temporary config file without target_branch (should default to "main")
+func TestLoadConfig_WithoutTargetBranch(t *testing.T) {
+	// Create a temporary config file without target_branch (the repository's default branch is used)
 	configContent := `
 logging:
   level: info
@@ -222,8 +143,732 @@ jira:
 		t.Fatalf("Failed to load config: %v", err)
 	}
 
-	// Verify target branch defaults to "main"
-	if config.GitHub.TargetBranch != "main" {
-		t.Errorf("Expected default target branch 'main', got '%s'", config.GitHub.TargetBranch)
+	// Verify target branch is left empty, for the repository's default branch to be detected
+	if config.GitHub.TargetBranch != "" {
+		t.Errorf("Expected no default target branch, got '%s'", config.GitHub.TargetBranch)
+	}
+
+	// Verify tickets are processed in priority order by default
+	if config.Jira.ScanOrder != ScanOrderPriority {
+		t.Errorf("Expected the default scan order to be priority, got '%s'", config.Jira.ScanOrder)
+	}
+
+	// Verify a run without changes is retried once by default
+	if retries := config.GetNoChangeRetries(); retries != 1 {
+		t.Errorf("Expected one retry of runs without changes by default, got %d", retries)
+	}
+	if retries := (&Config{}).GetNoChangeRetries(); retries != 1 {
+		t.Errorf("Expected one retry of runs without changes when unset, got %d", retries)
+	}
+}
+
+func TestLoadConfig_WithoutNoChangeRetries(t *testing.T) {
+	configContent := `
+logging:
+  level: info
+  format: console
+ai_provider: "claude"
+ai:
+  no_change_retries: 0
+jira:
+  status_transitions:
+    todo: "To Do"
+    in_progress: "In Progress"
+    in_review: "In Review"
+`
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(configContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if retries := config.GetNoChangeRetries(); retries != 0 {
+		t.Errorf("Expected runs without changes not to be retried, got %d retries", retries)
+	}
+}
+
+func TestConfig_validateGitHubAuth(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(c *Config)
+		wantErr bool
+	}{
+		{
+			name:    "pat mode",
+			setup:   func(c *Config) { c.GitHub.AuthMode = GitHubAuthModePAT },
+			wantErr: false,
+		},
+		{
+			name: "app mode fully configured",
+			setup: func(c *Config) {
+				c.GitHub.AuthMode = GitHubAuthModeApp
+				c.GitHub.AppID = 1
+				c.GitHub.InstallationID = 2
+				c.GitHub.PrivateKeyPath = "/tmp/key.pem"
+			},
+			wantErr: false,
+		},
+		{
+			name: "app mode missing private key",
+			setup: func(c *Config) {
+				c.GitHub.AuthMode = GitHubAuthModeApp
+				c.GitHub.AppID = 1
+				c.GitHub.InstallationID = 2
+			},
+			wantErr: true,
+		},
+		{
+			name:    "invalid mode",
+			setup:   func(c *Config) { c.GitHub.AuthMode = "oauth" },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{}
+			tt.setup(config)
+			err := config.validateGitHubAuth()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Config.validateGitHubAuth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_validateAIProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		fallback string
+		wantErr  bool
+	}{
+		{name: "no fallback", provider: "claude", wantErr: false},
+		{name: "gemini fallback", provider: "claude", fallback: "gemini", wantErr: false},
+		{name: "fallback to itself", provider: "gemini", fallback: "gemini", wantErr: true},
+		{name: "unknown fallback", provider: "claude", fallback: "gpt", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{AIProvider: tt.provider, AIFallbackProvider: tt.fallback}
+			err := config.validateAIProvider()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Config.validateAIProvider() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_validateJiraAuth(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(c *Config)
+		wantErr bool
+	}{
+		{
+			name:    "token mode",
+			setup:   func(c *Config) { c.Jira.AuthMode = JiraAuthModeToken },
+			wantErr: false,
+		},
+		{
+			name: "connect mode fully configured",
+			setup: func(c *Config) {
+				c.Jira.AuthMode = JiraAuthModeConnect
+				c.Jira.Connect.AppKey = "ai-solver"
+				c.Jira.Connect.BaseURL = "https://solver.example.com"
+			},
+			wantErr: false,
+		},
+		{
+			name: "connect mode missing base url",
+			setup: func(c *Config) {
+				c.Jira.AuthMode = JiraAuthModeConnect
+				c.Jira.Connect.AppKey = "ai-solver"
+			},
+			wantErr: true,
+		},
+		{
+			name:    "invalid mode",
+			setup:   func(c *Config) { c.Jira.AuthMode = "oauth" },
+			wantErr: true,
+		},
+		{
+			name: "bearer token type",
+			setup: func(c *Config) {
+				c.Jira.AuthMode = JiraAuthModeToken
+				c.Jira.TokenType = JiraTokenTypeBearer
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid token type",
+			setup: func(c *Config) {
+				c.Jira.AuthMode = JiraAuthModeToken
+				c.Jira.TokenType = "oauth"
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{}
+			config.Jira.TokenType = JiraTokenTypeAuto
+			tt.setup(config)
+			err := config.validateJiraAuth()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Config.validateJiraAuth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_IsDraftPR(t *testing.T) {
+	enabled, disabled := true, false
+	config := &Config{}
+	config.GitHub.DraftPR = true
+	config.Components = map[string]ComponentConfig{
+		"backend": {DraftPR: &disabled},
+		"mobile":  {DraftPR: &enabled},
+		"docs":    {},
+	}
+
+	tests := map[string]bool{
+		"backend":  false,
+		"mobile":   true,
+		"docs":     true,
+		"frontend": true,
+	}
+	for component, want := range tests {
+		if got := config.IsDraftPR(component); got != want {
+			t.Errorf("IsDraftPR(%q) = %v, want %v", component, got, want)
+		}
+	}
+}
+
+func TestConfig_GetComplianceMode(t *testing.T) {
+	config := &Config{}
+	config.GitHub.Compliance.Mode = ComplianceModeAuto
+	config.GitHub.Compliance.InternalOrgs = []string{"My-Org"}
+	config.GitHub.Compliance.Repositories = map[string]ComplianceMode{
+		"my-org/mirror":   ComplianceModeSignOff,
+		"vendor/licensed": ComplianceModeBlock,
+	}
+
+	tests := map[string]ComplianceMode{
+		"my-org/service":  ComplianceModeSkip,
+		"my-org/mirror":   ComplianceModeSignOff,
+		"vendor/licensed": ComplianceModeBlock,
+		"oss/project":     ComplianceModeAuto,
+	}
+	for fullName, want := range tests {
+		parts := strings.SplitN(fullName, "/", 2)
+		if got := config.GetComplianceMode(parts[0], parts[1]); got != want {
+			t.Errorf("GetComplianceMode(%q) = %v, want %v", fullName, got, want)
+		}
+	}
+
+	config.GitHub.Compliance.Repositories["oss/project"] = "sometimes"
+	if err := config.validateCompliance(); err == nil {
+		t.Error("Expected validation error for invalid repository compliance mode")
+	}
+}
+
+func TestConfig_GetCloneOptions(t *testing.T) {
+	config := &Config{}
+	config.GitHub.Clone = CloneOptions{Depth: 100}
+	config.Components = map[string]ComponentConfig{
+		"monorepo": {Clone: &CloneOptions{Depth: 1, Filter: "blob:none", SingleBranch: true}},
+	}
+
+	if got := config.GetCloneOptions("monorepo"); got.Depth != 1 || got.Filter != "blob:none" || !got.SingleBranch {
+		t.Errorf("Expected component clone options, got %+v", got)
+	}
+	if got := config.GetCloneOptions("frontend"); got.Depth != 100 || got.Filter != "" {
+		t.Errorf("Expected global clone options, got %+v", got)
+	}
+}
+
+func TestConfig_validateSigning(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(c *Config)
+		wantErr bool
+	}{
+		{
+			name:    "disabled",
+			setup:   func(c *Config) {},
+			wantErr: false,
+		},
+		{
+			name: "ssh with key file",
+			setup: func(c *Config) {
+				c.GitHub.Signing.Format = CommitSigningSSH
+				c.GitHub.Signing.KeyFile = "/secrets/signing_key"
+			},
+			wantErr: false,
+		},
+		{
+			name: "gpg without key id",
+			setup: func(c *Config) {
+				c.GitHub.Signing.Format = CommitSigningGPG
+				c.GitHub.Signing.KeyFile = "/secrets/signing_key.asc"
+			},
+			wantErr: true,
+		},
+		{
+			name:    "ssh without key file",
+			setup:   func(c *Config) { c.GitHub.Signing.Format = CommitSigningSSH },
+			wantErr: true,
+		},
+		{
+			name:    "invalid format",
+			setup:   func(c *Config) { c.GitHub.Signing.Format = "x509" },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{}
+			tt.setup(config)
+			err := config.validateSigning()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Config.validateSigning() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_validatePipeline(t *testing.T) {
+	disabled := false
+	tests := []struct {
+		name    string
+		steps   []PipelineStepConfig
+		wantErr bool
+	}{
+		{
+			name:    "default",
+			steps:   nil,
+			wantErr: false,
+		},
+		{
+			name: "custom step and disabled step",
+			steps: []PipelineStepConfig{
+				{Name: PipelineStepClone},
+				{Name: PipelineStepGenerate},
+				{Name: "lint", Command: "make lint"},
+				{Name: PipelineStepVerify, Enabled: &disabled},
+				{Name: PipelineStepCommit},
+				{Name: PipelineStepPush},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "without generate",
+			steps:   []PipelineStepConfig{{Name: PipelineStepClone}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown step",
+			steps:   []PipelineStepConfig{{Name: PipelineStepClone}, {Name: PipelineStepGenerate}, {Name: "deploy"}},
+			wantErr: true,
+		},
+		{
+			name:    "step before its dependency",
+			steps:   []PipelineStepConfig{{Name: PipelineStepGenerate}, {Name: PipelineStepClone}},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate step",
+			steps:   []PipelineStepConfig{{Name: PipelineStepClone}, {Name: PipelineStepGenerate}, {Name: PipelineStepGenerate}},
+			wantErr: true,
+		},
+		{
+			name:    "custom step shadowing a built-in step",
+			steps:   []PipelineStepConfig{{Name: PipelineStepClone}, {Name: PipelineStepGenerate}, {Name: PipelineStepVerify, Command: "make test"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{}
+			config.Components = map[string]ComponentConfig{"backend": {Pipeline: tt.steps}}
+			err := config.validatePipeline()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Config.validatePipeline() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_GetPipelineSteps(t *testing.T) {
+	disabled := false
+	config := &Config{}
+	config.Components = map[string]ComponentConfig{
+		"backend": {Pipeline: []PipelineStepConfig{
+			{Name: PipelineStepClone},
+			{Name: PipelineStepGenerateDocs, Enabled: &disabled},
+			{Name: PipelineStepGenerate},
+		}},
+	}
+
+	var names []PipelineStepName
+	for _, step := range config.GetPipelineSteps("backend") {
+		names = append(names, step.Name)
+	}
+	if len(names) != 2 || names[0] != PipelineStepClone || names[1] != PipelineStepGenerate {
+		t.Errorf("Expected the enabled component steps, got %v", names)
+	}
+	if got := config.GetPipelineSteps("frontend"); len(got) != len(DefaultPipelineSteps) {
+		t.Errorf("Expected the default steps, got %v", got)
+	}
+}
+
+func TestConfig_validateHooks(t *testing.T) {
+	config := &Config{}
+	config.Hooks = PipelineHooks{HookPrePush: {{Name: "test", Command: "make test"}}}
+	if err := config.validatePipeline(); err != nil {
+		t.Errorf("Expected valid hooks, got %v", err)
+	}
+
+	config.Hooks = PipelineHooks{"post_push": {{Command: "make test"}}}
+	if err := config.validatePipeline(); err == nil {
+		t.Error("Expected an unknown hook point to be rejected")
+	}
+
+	config.Hooks = nil
+	config.Components = map[string]ComponentConfig{"backend": {Hooks: PipelineHooks{HookPreGenerate: {{Name: "empty"}}}}}
+	if err := config.validatePipeline(); err == nil {
+		t.Error("Expected a hook without a command to be rejected")
+	}
+}
+
+func TestConfig_GetBuildCheck(t *testing.T) {
+	config := &Config{}
+	config.Build = CheckCommandConfig{Command: "go build ./...", MaxFixAttempts: 2}
+	config.Components = map[string]ComponentConfig{"frontend": {Build: &CheckCommandConfig{Command: "npm run build"}}}
+
+	if got := config.GetBuildCheck("frontend").Command; got != "npm run build" {
+		t.Errorf("Expected the component override, got %q", got)
+	}
+	if got := config.GetBuildCheck("backend").Command; got != "go build ./..." {
+		t.Errorf("Expected the global build check, got %q", got)
+	}
+	if got := config.GetTestCheck("frontend").Command; got != "" {
+		t.Errorf("Expected no test check, got %q", got)
+	}
+
+	config.Components["frontend"].Build.MaxFixAttempts = -1
+	if err := config.validatePipeline(); err == nil {
+		t.Error("Expected negative fix attempts to be rejected")
+	}
+}
+
+func TestConfig_GetPRLabels(t *testing.T) {
+	config := &Config{}
+	config.GitHub.PRLabel = "ai-pr"
+	config.GitHub.PRLabels = []string{"automated", "ai-pr", ""}
+	config.Components = map[string]ComponentConfig{"frontend": {PRLabels: []string{"ui", "ai-generated"}}}
+
+	if got := strings.Join(config.GetPRLabels("frontend"), ","); got != "ui,ai-generated" {
+		t.Errorf("Expected the component override, got %q", got)
+	}
+	if got := strings.Join(config.GetPRLabels("backend"), ","); got != "ai-pr,automated" {
+		t.Errorf("Expected the global labels once each, got %q", got)
+	}
+
+	config.GitHub.PRLabel, config.GitHub.PRLabels = "", nil
+	if got := config.GetPRLabels("backend"); len(got) != 0 {
+		t.Errorf("Expected no labels, got %q", got)
+	}
+}
+
+func TestConfig_ValidatePlanning(t *testing.T) {
+	config := &Config{}
+	config.GitHub.Planning.Project.Owner = "my-org"
+	config.GitHub.Planning.Project.Number = 5
+	if err := config.validatePlanning(); err != nil {
+		t.Errorf("Expected a complete project to be valid, got %v", err)
+	}
+	if got := config.GetPlanning("backend").Project.Field; got != "Status" {
+		t.Errorf("Expected the Status field by default, got %q", got)
+	}
+
+	partial := &PRPlanningConfig{}
+	partial.Project.Number = 3
+	config.Components = map[string]ComponentConfig{"backend": {Planning: partial}}
+	if err := config.validatePlanning(); err == nil || !strings.Contains(err.Error(), "components.backend.planning") {
+		t.Errorf("Expected a project without owner to be rejected, got %v", err)
+	}
+}
+
+func TestConfig_validateBranch(t *testing.T) {
+	config := &Config{}
+	config.GitHub.TargetBranch = "main"
+	config.Components = map[string]ComponentConfig{"backend": {TargetBranch: "release/2.x"}}
+	config.GitHub.Branch.Template = "{{.Prefix}}{{.Key}}-{{slug .Summary}}"
+	config.GitHub.Branch.Prefix = "ai/"
+	config.GitHub.Branch.MaxLength = 60
+	if err := config.validateBranch(); err != nil {
+		t.Errorf("Expected a valid branch template, got %v", err)
+	}
+
+	config.GitHub.Branch.Template = "{{.Prefix}}{{.Key}} {{.Summary}}"
+	if err := config.validateBranch(); err == nil || !strings.Contains(err.Error(), "github.branch.template") {
+		t.Errorf("Expected a template rendering spaces to be rejected, got %v", err)
+	}
+
+	config.GitHub.Branch.Template = DefaultBranchTemplate
+	config.GitHub.Branch.Prefix = "-ai/"
+	if err := config.validateBranch(); err == nil || !strings.Contains(err.Error(), "github.branch.prefix") {
+		t.Errorf("Expected an invalid prefix to be rejected, got %v", err)
+	}
+
+	config.GitHub.Branch.Prefix = ""
+	config.Components["backend"] = ComponentConfig{TargetBranch: "--orphan"}
+	if err := config.validateBranch(); err == nil || !strings.Contains(err.Error(), "components.backend.target_branch") {
+		t.Errorf("Expected an invalid component target branch to be rejected, got %v", err)
+	}
+
+	config.Components["backend"] = ComponentConfig{TargetBranch: "develop"}
+	if got := config.GetTargetBranch("backend"); got != "develop" {
+		t.Errorf("Expected the component's target branch, got %q", got)
+	}
+	if got := config.GetTargetBranch("frontend"); got != "main" {
+		t.Errorf("Expected the global target branch for other components, got %q", got)
+	}
+}
+
+func TestConfig_GetFormatters(t *testing.T) {
+	config := &Config{}
+	config.Format = []HookCommand{{Command: "gofmt -w ."}}
+	config.Components = map[string]ComponentConfig{"docs": {Format: []HookCommand{}}}
+
+	if got := config.GetFormatters("backend"); len(got) != 1 {
+		t.Errorf("Expected the global formatters, got %v", got)
+	}
+	if got := config.GetFormatters("docs"); len(got) != 0 {
+		t.Errorf("Expected an empty override to disable the formatters, got %v", got)
+	}
+
+	config.Format = []HookCommand{{Name: "empty"}}
+	if err := config.validatePipeline(); err == nil {
+		t.Error("Expected a formatter without a command to be rejected")
+	}
+}
+
+func TestConfig_validateAutoMerge(t *testing.T) {
+	config := &Config{}
+	config.GitHub.AutoMerge.Method = "fast-forward"
+	if err := config.validateAutoMerge(); err != nil {
+		t.Errorf("Expected a disabled auto-merge not to be validated, got %v", err)
+	}
+
+	config.GitHub.AutoMerge.Enabled = true
+	if err := config.validateAutoMerge(); err == nil {
+		t.Error("Expected an invalid merge method to be rejected")
+	}
+
+	config.GitHub.AutoMerge.Method = MergeMethodSquash
+	config.Jira.StatusTransitions.Done = "Done"
+	if err := config.validateAutoMerge(); err != nil {
+		t.Errorf("Expected a valid auto-merge config, got %v", err)
+	}
+}
+
+func TestConfig_validateFeedbackTrigger(t *testing.T) {
+	config := &Config{}
+	config.GitHub.FeedbackTrigger = "label"
+	if err := config.validateFeedbackTrigger(); err == nil {
+		t.Error("Expected an invalid feedback trigger to be rejected")
+	}
+
+	config.GitHub.FeedbackTrigger = FeedbackTriggerMention
+	if err := config.validateFeedbackTrigger(); err == nil {
+		t.Error("Expected the mention trigger to require a bot username")
+	}
+
+	config.GitHub.BotUsername = "ai-bot"
+	if err := config.validateFeedbackTrigger(); err != nil {
+		t.Errorf("Expected a valid feedback trigger config, got %v", err)
+	}
+}
+
+func TestConfig_validateCommitAuthor(t *testing.T) {
+	tests := []struct {
+		name    string
+		author  CommitAuthorConfig
+		wantErr bool
+	}{
+		{name: "bot by default", author: CommitAuthorConfig{}},
+		{name: "invalid mode", author: CommitAuthorConfig{Mode: "committer"}, wantErr: true},
+		{name: "identity", author: CommitAuthorConfig{Mode: CommitAuthorIdentity, Identity: GitIdentity{Name: "Payments Team", Email: "payments@example.com"}}},
+		{name: "identity without email", author: CommitAuthorConfig{Mode: CommitAuthorIdentity, Identity: GitIdentity{Name: "Payments Team"}}, wantErr: true},
+		{name: "reporter without fallback", author: CommitAuthorConfig{Mode: CommitAuthorReporter, Reporters: map[string]GitIdentity{"jane@example.com": {Name: "Jane Doe", Email: "jane@users.noreply.github.com"}}}},
+		{name: "incomplete reporter", author: CommitAuthorConfig{Mode: CommitAuthorReporter, Reporters: map[string]GitIdentity{"jane": {Email: "jane@example.com"}}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{}
+			config.Components = map[string]ComponentConfig{"payments": {CommitAuthor: &tt.author}}
+			err := config.validateCommitAuthor()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConfig_GetCommitAuthor(t *testing.T) {
+	config := &Config{}
+	config.GitHub.CommitAuthor.Mode = CommitAuthorReporter
+	config.Components = map[string]ComponentConfig{
+		"payments": {CommitAuthor: &CommitAuthorConfig{Mode: CommitAuthorIdentity}},
+	}
+
+	if got := config.GetCommitAuthor("payments").Mode; got != CommitAuthorIdentity {
+		t.Errorf("Expected the component override, got %s", got)
+	}
+	if got := config.GetCommitAuthor("frontend").Mode; got != CommitAuthorReporter {
+		t.Errorf("Expected the global settings, got %s", got)
+	}
+}
+
+func TestConfig_validateGitTransport(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(keyFile, []byte("key"), 0600); err != nil {
+		t.Fatalf("Failed to write key file: %v", err)
+	}
+
+	config := &Config{}
+	config.GitHub.Git.Protocol = GitProtocolHTTPS
+	if err := config.validateGitTransport(); err != nil {
+		t.Errorf("Expected HTTPS to need no SSH settings, got %v", err)
+	}
+
+	config.GitHub.Git.Repositories = map[string]GitProtocol{"example/repo": "git"}
+	if err := config.validateGitTransport(); err == nil {
+		t.Error("Expected an invalid repository protocol to be rejected")
+	}
+
+	config.GitHub.Git.Repositories = map[string]GitProtocol{"example/repo": GitProtocolSSH}
+	config.GitHub.Git.SSH.KeyFile = keyFile
+	config.GitHub.Git.SSH.KnownHostsFile = filepath.Join(t.TempDir(), "missing")
+	if err := config.validateGitTransport(); err == nil {
+		t.Error("Expected SSH without trusted host keys to be rejected")
+	}
+
+	config.GitHub.Git.SSH.KnownHosts = []string{"github.com ssh-ed25519 AAAA"}
+	if err := config.validateGitTransport(); err != nil {
+		t.Errorf("Expected a valid SSH config, got %v", err)
+	}
+
+	config.GitHub.Git.SSH.KeyFile = filepath.Join(t.TempDir(), "missing")
+	if err := config.validateGitTransport(); err == nil {
+		t.Error("Expected a missing key file to be rejected")
+	}
+}
+
+func TestConfig_GetGitProtocol(t *testing.T) {
+	config := &Config{}
+	config.GitHub.Git.Repositories = map[string]GitProtocol{"example/private": GitProtocolSSH}
+
+	if got := config.GetGitProtocol("example", "private"); got != GitProtocolSSH {
+		t.Errorf("Expected the repository override, got %s", got)
+	}
+	if got := config.GetGitProtocol("example", "public"); got != GitProtocolHTTPS {
+		t.Errorf("Expected HTTPS by default, got %s", got)
+	}
+}
+
+func TestConfig_validateGenerateDocs(t *testing.T) {
+	config := &Config{}
+	config.AI.GenerateDocs = "never"
+	if err := config.validateGenerateDocs(); err == nil {
+		t.Error("Expected an invalid generate_docs mode to be rejected")
+	}
+
+	config.AI.GenerateDocs = GenerateDocsAlways
+	config.Components = map[string]ComponentConfig{"payments": {GenerateDocs: "sometimes"}}
+	if err := config.validateGenerateDocs(); err == nil {
+		t.Error("Expected an invalid component generate_docs mode to be rejected")
+	}
+
+	config.Components["payments"] = ComponentConfig{GenerateDocs: GenerateDocsOff}
+	if err := config.validateGenerateDocs(); err != nil {
+		t.Errorf("Expected valid generate_docs modes, got %v", err)
+	}
+	if got := config.GetGenerateDocs("payments"); got != GenerateDocsOff {
+		t.Errorf("Expected the component override, got %s", got)
+	}
+	if got := config.GetGenerateDocs("frontend"); got != GenerateDocsAlways {
+		t.Errorf("Expected the global mode, got %s", got)
+	}
+}
+
+func TestConfig_validateCLIBootstrap(t *testing.T) {
+	config := &Config{AIProvider: "claude", AIFallbackProvider: "gemini"}
+	if err := config.validateCLIBootstrap(); err != nil {
+		t.Errorf("Expected a disabled bootstrap to be valid, got %v", err)
+	}
+
+	config.CLIBootstrap.Enabled = true
+	config.CLIBootstrap.Claude = CLIRelease{Version: "1.2.3", URL: "https://example.com/{version}/{os}-{arch}/claude", SHA256: map[string]string{"linux-amd64": "abc"}}
+	if err := config.validateCLIBootstrap(); err == nil {
+		t.Error("Expected the fallback provider to require a pinned release")
+	}
+
+	config.CLIBootstrap.Gemini = CLIRelease{Version: "0.9.0"}
+	if err := config.validateCLIBootstrap(); err == nil {
+		t.Error("Expected a release without URL to be rejected")
+	}
+
+	config.CLIBootstrap.Offline = true
+	if err := config.validateCLIBootstrap(); err != nil {
+		t.Errorf("Expected versions to suffice in offline mode, got %v", err)
+	}
+
+	if url := config.CLIBootstrap.Claude.DownloadURL("linux", "arm64"); url != "https://example.com/1.2.3/linux-arm64/claude" {
+		t.Errorf("Unexpected download URL %s", url)
+	}
+}
+
+func TestConfig_validateTimeouts(t *testing.T) {
+	config := &Config{}
+	config.Timeouts.GitSeconds = 600
+	config.Timeouts.HTTPSeconds = 60
+	if err := config.validateTimeouts(); err != nil {
+		t.Errorf("Expected an unlimited ticket timeout to be valid, got %v", err)
+	}
+
+	config.Timeouts.TicketMinutes = -1
+	if err := config.validateTimeouts(); err == nil {
+		t.Error("Expected a negative ticket timeout to be rejected")
+	}
+
+	config.Timeouts.TicketMinutes = 60
+	config.Timeouts.HTTPSeconds = -1
+	if err := config.validateTimeouts(); err == nil {
+		t.Error("Expected a negative HTTP timeout to be rejected")
+	}
+}
+
+func TestConfig_MissingSettings(t *testing.T) {
+	config := Config{}
+	config.Jira.AuthMode = JiraAuthModeConnect
+	config.Jira.BaseURL = "https://example.atlassian.net"
+	config.GitHub.AuthMode = GitHubAuthModePAT
+	config.GitHub.BotUsername = "ai-bot"
+
+	want := []string{"github.personal_access_token", "github.bot_email", "component_to_repo"}
+	if got := config.MissingSettings(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("MissingSettings() = %v, want %v", got, want)
 	}
 }
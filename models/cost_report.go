@@ -0,0 +1,76 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// CostReportPeriod is the period AI usage is aggregated by in cost reports
+type CostReportPeriod string
+
+const (
+	CostReportDaily   CostReportPeriod = "day"
+	CostReportWeekly  CostReportPeriod = "week"
+	CostReportMonthly CostReportPeriod = "month"
+)
+
+// IsValid checks if the cost report period is valid
+func (p CostReportPeriod) IsValid() bool {
+	switch p {
+	case CostReportDaily, CostReportWeekly, CostReportMonthly:
+		return true
+	default:
+		return false
+	}
+}
+
+// Start returns the start of the period containing the time, in UTC. Weeks start on Monday.
+func (p CostReportPeriod) Start(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch p {
+	case CostReportWeekly:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case CostReportMonthly:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// Label returns the name of the period containing the time, such as 2024-03-05, 2024-W10 or 2024-03
+func (p CostReportPeriod) Label(t time.Time) string {
+	t = t.UTC()
+	switch p {
+	case CostReportWeekly:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case CostReportMonthly:
+		return t.Format("2006-01")
+	default:
+		return t.Format("2006-01-02")
+	}
+}
+
+// CostReportRow is the AI usage of a Jira project and component in one period
+type CostReportRow struct {
+	Period            string  `json:"period"`
+	Project           string  `json:"project"`
+	Component         string  `json:"component"` // Empty for tickets without components
+	Runs              int     `json:"runs"`
+	InputTokens       int     `json:"input_tokens"`
+	OutputTokens      int     `json:"output_tokens"`
+	CostUSD           float64 `json:"cost_usd"`
+	ProcessingSeconds float64 `json:"processing_seconds"` // Time the AI spent on the tickets
+}
+
+// CostReport is the AI usage of every Jira project and component over a time range, for chargeback
+type CostReport struct {
+	Period                 CostReportPeriod `json:"period"`
+	From                   time.Time        `json:"from"`
+	To                     time.Time        `json:"to"` // Exclusive
+	Rows                   []CostReportRow  `json:"rows"`
+	TotalRuns              int              `json:"total_runs"`
+	TotalCostUSD           float64          `json:"total_cost_usd"`
+	TotalProcessingSeconds float64          `json:"total_processing_seconds"`
+}
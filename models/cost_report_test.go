@@ -0,0 +1,35 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCostReportPeriod_StartAndLabel(t *testing.T) {
+	// A Wednesday in the tenth ISO week
+	at := time.Date(2024, 3, 6, 15, 30, 0, 0, time.UTC)
+	tests := []struct {
+		period    CostReportPeriod
+		wantStart time.Time
+		wantLabel string
+	}{
+		{period: CostReportDaily, wantStart: time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC), wantLabel: "2024-03-06"},
+		{period: CostReportWeekly, wantStart: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), wantLabel: "2024-W10"},
+		{period: CostReportMonthly, wantStart: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), wantLabel: "2024-03"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.period), func(t *testing.T) {
+			if got := tt.period.Start(at); !got.Equal(tt.wantStart) {
+				t.Errorf("Start() = %v, want %v", got, tt.wantStart)
+			}
+			if got := tt.period.Label(at); got != tt.wantLabel {
+				t.Errorf("Label() = %s, want %s", got, tt.wantLabel)
+			}
+		})
+	}
+
+	sunday := time.Date(2024, 3, 10, 23, 0, 0, 0, time.UTC)
+	if got := CostReportWeekly.Start(sunday); !got.Equal(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected a Sunday to belong to the week starting on Monday, got %v", got)
+	}
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// FeedbackWatermark records how far the feedback on a pull request has been processed. Feedback is new when its
+// ID is above the newest processed ID of its kind; kinds without a processed ID yet fall back to ProcessedAt,
+// which is all a watermark migrated from the old timestamp comments has.
+type FeedbackWatermark struct {
+	ReviewID        int64     `json:"review_id,omitempty"`         // Newest review processed
+	CommentID       int64     `json:"comment_id,omitempty"`        // Newest PR comment processed
+	ReviewCommentID int64     `json:"review_comment_id,omitempty"` // Newest inline review comment processed
+	ProcessedAt     time.Time `json:"processed_at"`                // When the processed feedback was fetched
+}
+
+// IsNewReview reports whether the review was submitted after the watermark
+func (w FeedbackWatermark) IsNewReview(review GitHubReview) bool {
+	return w.isNew(review.ID, w.ReviewID, review.SubmittedAt)
+}
+
+// IsNewComment reports whether the PR comment was created after the watermark
+func (w FeedbackWatermark) IsNewComment(comment GitHubPRComment) bool {
+	return w.isNew(comment.ID, w.CommentID, comment.CreatedAt)
+}
+
+// IsNewReviewComment reports whether the inline review comment was created after the watermark
+func (w FeedbackWatermark) IsNewReviewComment(comment GitHubPRComment) bool {
+	return w.isNew(comment.ID, w.ReviewCommentID, comment.CreatedAt)
+}
+
+// isNew compares GitHub's increasing IDs when an ID of the kind was processed, and times otherwise
+func (w FeedbackWatermark) isNew(id, processedID int64, at time.Time) bool {
+	if processedID > 0 {
+		return id > processedID
+	}
+	return at.After(w.ProcessedAt)
+}
+
+// Advance returns the watermark moved past all feedback on the PR, which was fetched at the given time
+func (w FeedbackWatermark) Advance(pr *GitHubPRDetails, fetchedAt time.Time) FeedbackWatermark {
+	for _, review := range pr.Reviews {
+		w.ReviewID = max(w.ReviewID, review.ID)
+	}
+	for _, comment := range pr.Comments {
+		w.CommentID = max(w.CommentID, comment.ID)
+	}
+	for _, comment := range pr.ReviewComments {
+		w.ReviewCommentID = max(w.ReviewCommentID, comment.ID)
+	}
+	w.ProcessedAt = fetchedAt
+	return w
+}
@@ -53,6 +53,12 @@ type GitHubRef struct {
 	Repo  GitHubRepository `json:"repo"`
 }
 
+// GitHubBranch represents a branch of a repository
+type GitHubBranch struct {
+	Name      string `json:"name"`
+	Protected bool   `json:"protected"` // Whether a branch protection rule applies to the branch
+}
+
 // GitHubReview represents a GitHub pull request review
 type GitHubReview struct {
 	ID          int64      `json:"id"`
@@ -65,21 +71,23 @@ type GitHubReview struct {
 
 // GitHubCreatePRRequest represents the request to create a pull request
 type GitHubCreatePRRequest struct {
-	Title  string   `json:"title"`
-	Body   string   `json:"body"`
-	Head   string   `json:"head"`
-	Base   string   `json:"base"`
-	Labels []string `json:"labels,omitempty"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+	Draft bool   `json:"draft,omitempty"`
 }
 
 // GitHubCreatePRResponse represents the response from creating a pull request
 type GitHubCreatePRResponse struct {
 	ID        int64     `json:"id"`
+	NodeID    string    `json:"node_id"`
 	Number    int       `json:"number"`
 	State     string    `json:"state"`
 	Title     string    `json:"title"`
 	Body      string    `json:"body"`
 	HTMLURL   string    `json:"html_url"`
+	Draft     bool      `json:"draft"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -87,30 +95,50 @@ type GitHubCreatePRResponse struct {
 // GitHubPRComment represents a PR comment
 // (moved from pr_review_processor.go)
 type GitHubPRComment struct {
-	ID        int64      `json:"id"`
-	User      GitHubUser `json:"user"`
-	Body      string     `json:"body"`
-	Path      string     `json:"path"`
-	Line      int        `json:"line"`
-	HTMLURL   string     `json:"html_url"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
+	ID           int64      `json:"id"`
+	User         GitHubUser `json:"user"`
+	Body         string     `json:"body"`
+	Path         string     `json:"path"`
+	Line         int        `json:"line"`
+	StartLine    int        `json:"start_line,omitempty"`     // First line of a multi-line comment
+	InReplyTo    int64      `json:"in_reply_to_id,omitempty"` // Set on replies in an inline review thread
+	DiffHunk     string     `json:"diff_hunk,omitempty"`      // Diff context the inline comment was made on
+	OriginalLine int        `json:"original_line,omitempty"`  // Line the comment was made on, kept when the code changed since
+	HTMLURL      string     `json:"html_url"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
 }
 
 // GitHubPRDetails represents detailed PR information including reviews
 type GitHubPRDetails struct {
-	Number    int               `json:"number"`
-	State     string            `json:"state"`
-	Title     string            `json:"title"`
-	Body      string            `json:"body"`
-	HTMLURL   string            `json:"html_url"`
-	Head      GitHubRef         `json:"head"`
-	Base      GitHubRef         `json:"base"`
-	Reviews   []GitHubReview    `json:"reviews,omitempty"`
-	Comments  []GitHubPRComment `json:"-"` // We'll populate this separately
-	Files     []GitHubPRFile    `json:"files,omitempty"`
-	CreatedAt time.Time         `json:"created_at"`
-	UpdatedAt time.Time         `json:"updated_at"`
+	NodeID   string            `json:"node_id"`
+	Number   int               `json:"number"`
+	State    string            `json:"state"`
+	Title    string            `json:"title"`
+	Body     string            `json:"body"`
+	HTMLURL  string            `json:"html_url"`
+	Draft    bool              `json:"draft"`
+	Head     GitHubRef         `json:"head"`
+	Base     GitHubRef         `json:"base"`
+	Reviews  []GitHubReview    `json:"reviews,omitempty"`
+	Comments []GitHubPRComment `json:"-"` // We'll populate this separately
+	// Inline review comments on the diff, populated separately
+	ReviewComments []GitHubPRComment `json:"-"`
+	Files          []GitHubPRFile    `json:"files,omitempty"`
+	Mergeable      *bool             `json:"mergeable"`       // Computed asynchronously by GitHub, nil until known
+	MergeableState string            `json:"mergeable_state"` // "dirty" when the PR has merge conflicts
+	Merged         bool              `json:"merged"`
+	ClosedAt       *time.Time        `json:"closed_at"` // nil while the PR is open
+	User           GitHubUser        `json:"user"`      // Author of the PR
+	MergeCommitSHA string            `json:"merge_commit_sha"`
+	Labels         []GitHubLabel     `json:"labels,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// GitHubLabel represents a label on an issue or PR
+type GitHubLabel struct {
+	Name string `json:"name"`
 }
 
 // GitHubPRFile represents a file changed in a PR
@@ -123,3 +151,140 @@ type GitHubPRFile struct {
 	Changes   int    `json:"changes"`
 	Patch     string `json:"patch"`
 }
+
+// GitIdentity is the name and email address of a git author or committer
+type GitIdentity struct {
+	Name  string `yaml:"name"`
+	Email string `yaml:"email"`
+}
+
+// String formats the identity as git expects it, e.g. "Jane Doe <jane@example.com>"
+func (i GitIdentity) String() string {
+	return i.Name + " <" + i.Email + ">"
+}
+
+// IsSet reports whether both the name and email address are set
+func (i GitIdentity) IsSet() bool {
+	return i.Name != "" && i.Email != ""
+}
+
+// CommitAuthorMode represents who is recorded as the author of the bot's commits
+type CommitAuthorMode string
+
+const (
+	CommitAuthorBot      CommitAuthorMode = "bot"      // The bot authors its commits
+	CommitAuthorIdentity CommitAuthorMode = "identity" // A configured service identity authors the commits
+	CommitAuthorReporter CommitAuthorMode = "reporter" // The ticket's reporter authors the commits, mapped to a git identity
+)
+
+// IsValid checks if the CommitAuthorMode is valid
+func (m CommitAuthorMode) IsValid() bool {
+	switch m {
+	case "", CommitAuthorBot, CommitAuthorIdentity, CommitAuthorReporter:
+		return true
+	default:
+		return false
+	}
+}
+
+// CommitAuthorConfig controls the author of the bot's commits. The committer is always the bot.
+type CommitAuthorConfig struct {
+	Mode      CommitAuthorMode       `yaml:"mode" default:"bot"` // "bot", "identity" or "reporter"
+	Identity  GitIdentity            `yaml:"identity"`           // Author in identity mode, and of tickets with unmapped reporters in reporter mode
+	Reporters map[string]GitIdentity `yaml:"reporters"`          // Jira reporter (email address, username or account ID) to git author
+}
+
+// CloneOptions controls how much of a repository's history and content is cloned
+type CloneOptions struct {
+	Depth        int    `yaml:"depth"`         // Shallow clone depth, 0 clones the full history
+	Filter       string `yaml:"filter"`        // Partial clone filter, e.g. "blob:none"
+	SingleBranch bool   `yaml:"single_branch"` // Only fetch the branch being cloned
+	Branch       string `yaml:"-"`             // Branch to check out, set by the caller
+	Reference    string `yaml:"-"`             // Local repository to copy objects from instead of downloading them, set by the caller
+}
+
+// IsShallow reports whether the clone has truncated history
+func (o CloneOptions) IsShallow() bool {
+	return o.Depth > 0
+}
+
+// GitHubCheckRun represents a check run reported on a commit
+type GitHubCheckRun struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`     // queued, in_progress or completed
+	Conclusion string `json:"conclusion"` // success, failure, timed_out, cancelled, ... once completed
+	HTMLURL    string `json:"html_url"`
+	DetailsURL string `json:"details_url"`
+	Output     struct {
+		Title   string `json:"title"`
+		Summary string `json:"summary"`
+		Text    string `json:"text"`
+	} `json:"output"`
+	App struct {
+		Slug string `json:"slug"`
+	} `json:"app"`
+}
+
+// GitHubCheckRunsResponse represents the response of the check runs API
+type GitHubCheckRunsResponse struct {
+	TotalCount int              `json:"total_count"`
+	CheckRuns  []GitHubCheckRun `json:"check_runs"`
+}
+
+// GitHubCommitStatus represents a commit status reported by an external CI system
+type GitHubCommitStatus struct {
+	Context     string `json:"context"`
+	State       string `json:"state"` // error, failure, pending or success
+	Description string `json:"description"`
+	TargetURL   string `json:"target_url"`
+}
+
+// GitHubCombinedStatus represents the combined commit status of a ref
+type GitHubCombinedStatus struct {
+	State    string               `json:"state"`
+	Statuses []GitHubCommitStatus `json:"statuses"`
+}
+
+// GitHubMergePRRequest represents the request to merge a pull request
+type GitHubMergePRRequest struct {
+	SHA         string `json:"sha"` // Head commit the PR must still point at
+	MergeMethod string `json:"merge_method"`
+}
+
+// GitHubMergePRResponse represents the response from merging a pull request
+type GitHubMergePRResponse struct {
+	SHA     string `json:"sha"`
+	Merged  bool   `json:"merged"`
+	Message string `json:"message"`
+}
+
+// FileDiffStat is the size of the change of one file in the working tree
+type FileDiffStat struct {
+	Path    string
+	Added   int
+	Removed int
+}
+
+// DiffStats is the size of the changes in the working tree, by file
+type DiffStats struct {
+	Files []FileDiffStat
+}
+
+// ChangedLines returns the lines added and removed in all files; binary files don't count
+func (d *DiffStats) ChangedLines() int {
+	total := 0
+	for _, file := range d.Files {
+		total += file.Added + file.Removed
+	}
+	return total
+}
+
+// GitCommit is a commit of the repository's history with an excerpt of its diff
+type GitCommit struct {
+	SHA     string
+	Author  string
+	Date    string // YYYY-MM-DD
+	Subject string
+	Diff    string // Excerpt of the diff, limited to the files the commit was found by if any
+}
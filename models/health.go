@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// HealthCheck is the result of checking one dependency of the application
+type HealthCheck struct {
+	Name      string `json:"name"`
+	Healthy   bool   `json:"healthy"`
+	Detail    string `json:"detail"` // What was found, or why the check failed
+	LatencyMS int64  `json:"latency_ms"`
+}
+
+// HealthReport is the result of checking every dependency of the application
+type HealthReport struct {
+	Healthy   bool          `json:"healthy"` // Whether every check passed
+	CheckedAt time.Time     `json:"checked_at"`
+	Checks    []HealthCheck `json:"checks"`
+}
@@ -59,6 +59,20 @@ type JiraFields struct {
 	Reporter    JiraUser        `json:"reporter"`
 	Assignee    *JiraUser       `json:"assignee,omitempty"`
 	Comment     JiraComments    `json:"comment,omitempty"`
+	Parent      *JiraParent     `json:"parent,omitempty"`
+	IssueType   JiraIssueType   `json:"issuetype"`
+}
+
+// JiraIssueType represents the type of a Jira issue, such as Bug or Story
+type JiraIssueType struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// JiraParent represents the parent of a Jira issue, such as the epic of a story
+type JiraParent struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
 }
 
 // JiraStatus represents the status of a Jira issue
@@ -115,6 +129,15 @@ type JiraTicketResponse struct {
 	Self   string     `json:"self"`
 	Key    string     `json:"key"`
 	Fields JiraFields `json:"fields"`
+
+	PromptFields   []JiraPromptField `json:"-"` // Configured custom fields shown in the prompt, resolved by the ticket processor
+	CommentSummary string            `json:"-"` // Summary of older comments left out of the prompt, set by the ticket processor
+}
+
+// JiraPromptField is the value of a custom field shown in the prompt under the field's name
+type JiraPromptField struct {
+	Name  string
+	Value string
 }
 
 // JiraChangelog represents the changelog of a Jira issue
@@ -216,3 +239,95 @@ type GeminiResponse struct {
 	Usage        GeminiUsage    `json:"usage"`
 	Message      *GeminiMessage `json:"message"`
 }
+
+// JiraCreateIssueRequest represents the request to create a Jira issue
+type JiraCreateIssueRequest struct {
+	Fields JiraCreateIssueFields `json:"fields"`
+}
+
+// JiraCreateIssueFields holds the fields of a Jira issue to create
+type JiraCreateIssueFields struct {
+	Project     JiraKeyRef    `json:"project"`
+	IssueType   JiraNameRef   `json:"issuetype"`
+	Summary     string        `json:"summary"`
+	Description string        `json:"description,omitempty"`
+	Labels      []string      `json:"labels,omitempty"`
+	Components  []JiraNameRef `json:"components,omitempty"`
+}
+
+// JiraCreateIssueResponse represents the response from creating a Jira issue
+type JiraCreateIssueResponse struct {
+	ID   string `json:"id"`
+	Key  string `json:"key"`
+	Self string `json:"self"`
+}
+
+// JiraIssueLinkRequest represents the request to link two Jira issues
+type JiraIssueLinkRequest struct {
+	Type         JiraNameRef `json:"type"`
+	InwardIssue  JiraKeyRef  `json:"inwardIssue"`
+	OutwardIssue JiraKeyRef  `json:"outwardIssue"`
+}
+
+// JiraRemoteLink represents a link from a Jira issue to an object outside Jira, such as a pull request. Creating a
+// link with the GlobalID of an existing one updates it.
+type JiraRemoteLink struct {
+	GlobalID     string               `json:"globalId,omitempty"`
+	Application  JiraRemoteLinkApp    `json:"application"`
+	Relationship string               `json:"relationship,omitempty"`
+	Object       JiraRemoteLinkObject `json:"object"`
+}
+
+// JiraRemoteLinkApp identifies the application a remote link points to
+type JiraRemoteLinkApp struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// JiraRemoteLinkObject describes the object a remote link points to
+type JiraRemoteLinkObject struct {
+	URL     string                `json:"url"`
+	Title   string                `json:"title"`
+	Summary string                `json:"summary,omitempty"`
+	Icon    *JiraRemoteLinkIcon   `json:"icon,omitempty"`
+	Status  *JiraRemoteLinkStatus `json:"status,omitempty"`
+}
+
+// JiraRemoteLinkIcon is an icon shown next to a remote link
+type JiraRemoteLinkIcon struct {
+	URL16x16 string `json:"url16x16"`
+	Title    string `json:"title,omitempty"`
+}
+
+// JiraRemoteLinkStatus is the status of a remote link's object; resolved objects are shown struck through
+type JiraRemoteLinkStatus struct {
+	Resolved bool                `json:"resolved"`
+	Icon     *JiraRemoteLinkIcon `json:"icon,omitempty"`
+}
+
+// JiraKeyRef references a Jira entity by key
+type JiraKeyRef struct {
+	Key string `json:"key"`
+}
+
+// JiraNameRef references a Jira entity by name
+type JiraNameRef struct {
+	Name string `json:"name"`
+}
+
+// JiraWorklogTimeFormat is the time format of a worklog's start
+const JiraWorklogTimeFormat = "2006-01-02T15:04:05.000-0700"
+
+// JiraWorklog represents time logged on a Jira issue
+type JiraWorklog struct {
+	Started          string `json:"started"` // In JiraWorklogTimeFormat
+	TimeSpentSeconds int    `json:"timeSpentSeconds"`
+	Comment          string `json:"comment,omitempty"`
+}
+
+// JiraSprint represents a sprint of a Jira Software board
+type JiraSprint struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	State string `json:"state"` // future, active or closed
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// JiraConnectLifecycle is the payload Jira sends to the installed and uninstalled lifecycle callbacks
+type JiraConnectLifecycle struct {
+	Key            string `json:"key"`
+	ClientKey      string `json:"clientKey"`
+	SharedSecret   string `json:"sharedSecret"`
+	BaseURL        string `json:"baseUrl"`
+	DisplayURL     string `json:"displayUrl"`
+	ProductType    string `json:"productType"`
+	Description    string `json:"description"`
+	EventType      string `json:"eventType"`
+	ServerVersion  string `json:"serverVersion"`
+	PluginsVersion string `json:"pluginsVersion"`
+}
+
+// JiraConnectInstallation is a Jira site the Connect app is installed on
+type JiraConnectInstallation struct {
+	ClientKey    string    `json:"client_key"`
+	SharedSecret string    `json:"shared_secret"`
+	BaseURL      string    `json:"base_url"`
+	InstalledAt  time.Time `json:"installed_at"`
+}
+
+// JiraWebhookEvent is the payload of a Jira issue webhook
+type JiraWebhookEvent struct {
+	Timestamp    int64     `json:"timestamp"`
+	WebhookEvent string    `json:"webhookEvent"`
+	User         JiraUser  `json:"user"`
+	Issue        JiraIssue `json:"issue"`
+}
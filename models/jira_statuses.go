@@ -7,7 +7,7 @@ type JiraTicketStatus string
 const (
 	// StatusInProgress indicates that the ticket is being worked on
 	StatusInProgress JiraTicketStatus = "In Progress"
-	
+
 	// StatusInReview indicates that the ticket is ready for review
 	StatusInReview JiraTicketStatus = "In Review"
 )
@@ -15,4 +15,4 @@ const (
 // String returns the string representation of a JiraTicketStatus
 func (s JiraTicketStatus) String() string {
 	return string(s)
-}
\ No newline at end of file
+}
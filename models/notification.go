@@ -0,0 +1,189 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// NotificationEvent is an event of the bot that can be notified to chat tools
+type NotificationEvent string
+
+const (
+	NotificationPRCreated        NotificationEvent = "pr_created"        // A pull request was opened for a ticket
+	NotificationProcessingFailed NotificationEvent = "processing_failed" // Processing a ticket failed
+	NotificationBudgetExceeded   NotificationEvent = "budget_exceeded"   // Processing a ticket exceeded the ticket timeout
+	NotificationFeedbackApplied  NotificationEvent = "feedback_applied"  // Fixes for PR feedback were pushed
+)
+
+// NotificationEvents lists every notification event
+var NotificationEvents = []NotificationEvent{
+	NotificationPRCreated,
+	NotificationProcessingFailed,
+	NotificationBudgetExceeded,
+	NotificationFeedbackApplied,
+}
+
+// IsValid checks if the notification event is valid
+func (e NotificationEvent) IsValid() bool {
+	for _, event := range NotificationEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification is an event of the bot about a ticket, rendered by the message templates
+type Notification struct {
+	Event     NotificationEvent
+	Ticket    string // Ticket key
+	TicketURL string // Ticket URL in Jira
+	PRURL     string // Pull request URL, if the event concerns one
+	Message   string // Details of the event, e.g. the failure
+}
+
+// NotificationRoute configures how a sink sends the notifications of one event
+type NotificationRoute struct {
+	Channel    string `yaml:"channel"`     // Slack channel the event is posted to, instead of the sink's channel
+	WebhookURL string `yaml:"webhook_url"` // Webhook the event is sent to, instead of the sink's webhook
+	Template   string `yaml:"template"`    // Go template of the message, instead of the default one
+}
+
+// SlackNotificationConfig configures the Slack notification sink, which posts to incoming webhooks
+type SlackNotificationConfig struct {
+	WebhookURL     string `yaml:"webhook_url"`      // Incoming webhook URL; Slack notifications are disabled if empty
+	WebhookURLFile string `yaml:"webhook_url_file"` // File holding the incoming webhook URL, instead of webhook_url
+	Channel        string `yaml:"channel"`          // Channel override for legacy webhooks; the webhook's channel if empty
+	Username       string `yaml:"username"`         // Name the messages are posted as; the webhook's name if empty
+
+	// Events sent to Slack and their routing; every event is sent with the defaults if empty
+	Events map[NotificationEvent]NotificationRoute `yaml:"events"`
+}
+
+// Route returns the routing of an event, and whether the event is sent at all
+func (s SlackNotificationConfig) Route(event NotificationEvent) (NotificationRoute, bool) {
+	return notificationRoute(s.Events, event)
+}
+
+// TeamsNotificationConfig configures the Microsoft Teams notification sink, which posts Adaptive Cards to incoming
+// webhooks
+type TeamsNotificationConfig struct {
+	WebhookURL     string `yaml:"webhook_url"`      // Incoming webhook URL; Teams notifications are disabled if empty
+	WebhookURLFile string `yaml:"webhook_url_file"` // File holding the incoming webhook URL, instead of webhook_url
+
+	// Events sent to Teams and their routing; every event is sent with the defaults if empty. Teams webhooks post to
+	// the channel they were created in, so routes select channels by webhook_url.
+	Events map[NotificationEvent]NotificationRoute `yaml:"events"`
+}
+
+// Route returns the routing of an event, and whether the event is sent at all
+func (t TeamsNotificationConfig) Route(event NotificationEvent) (NotificationRoute, bool) {
+	return notificationRoute(t.Events, event)
+}
+
+// EmailRoute configures the email sent for one event
+type EmailRoute struct {
+	Subject  string `yaml:"subject"`  // Go template of the subject, instead of the default one
+	Template string `yaml:"template"` // Go template of the body, instead of the default one
+}
+
+// EmailNotificationConfig configures the email notification sink, which mails the reporter and assignee of the ticket
+type EmailNotificationConfig struct {
+	Host         string   `yaml:"host"`               // SMTP server; email notifications are disabled if empty
+	Port         int      `yaml:"port" default:"587"` // SMTP port; STARTTLS is used if the server supports it
+	Username     string   `yaml:"username"`           // SMTP user; no authentication if empty
+	Password     string   `yaml:"password"`           // SMTP password
+	PasswordFile string   `yaml:"password_file"`      // File holding the SMTP password, instead of password
+	From         string   `yaml:"from"`               // Sender address
+	OptOut       []string `yaml:"opt_out"`            // Email addresses, Jira usernames or account IDs never mailed
+
+	// Events mailed and their templates; pr_created, processing_failed and budget_exceeded if empty
+	Events map[NotificationEvent]EmailRoute `yaml:"events"`
+}
+
+// defaultEmailEvents are the events mailed when no events are listed; the ones the ticket's people act upon
+var defaultEmailEvents = []NotificationEvent{NotificationPRCreated, NotificationProcessingFailed, NotificationBudgetExceeded}
+
+// Route returns the templates of an event, and whether the event is mailed at all
+func (e EmailNotificationConfig) Route(event NotificationEvent) (EmailRoute, bool) {
+	if len(e.Events) == 0 {
+		for _, defaultEvent := range defaultEmailEvents {
+			if event == defaultEvent {
+				return EmailRoute{}, true
+			}
+		}
+		return EmailRoute{}, false
+	}
+	route, ok := e.Events[event]
+	return route, ok
+}
+
+// IsOptedOut reports whether a user opted out of email notifications by email address, username or account ID
+func (e EmailNotificationConfig) IsOptedOut(user JiraUser) bool {
+	for _, optOut := range e.OptOut {
+		for _, id := range []string{user.EmailAddress, user.Name, user.ID} {
+			if id != "" && strings.EqualFold(id, optOut) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// notificationRoute returns the routing of an event among a sink's events, sending every event if none is listed
+func notificationRoute(events map[NotificationEvent]NotificationRoute, event NotificationEvent) (NotificationRoute, bool) {
+	if len(events) == 0 {
+		return NotificationRoute{}, true
+	}
+	route, ok := events[event]
+	return route, ok
+}
+
+// validateNotificationEvents ensures a sink only routes known events with valid templates
+func validateNotificationEvents(path string, events map[NotificationEvent]NotificationRoute) error {
+	for event, route := range events {
+		if !event.IsValid() {
+			return fmt.Errorf("invalid event in %s: %s (must be one of %v)", path, event, NotificationEvents)
+		}
+		if route.Template == "" {
+			continue
+		}
+		if _, err := template.New(string(event)).Parse(route.Template); err != nil {
+			return fmt.Errorf("invalid template of %s.%s: %w", path, event, err)
+		}
+	}
+	return nil
+}
+
+// validateNotifications ensures the notification sinks are properly configured
+func (c *Config) validateNotifications() error {
+	if err := validateNotificationEvents("notifications.slack.events", c.Notifications.Slack.Events); err != nil {
+		return err
+	}
+	if err := validateNotificationEvents("notifications.teams.events", c.Notifications.Teams.Events); err != nil {
+		return err
+	}
+	for event, route := range c.Notifications.Teams.Events {
+		if route.Channel != "" {
+			return fmt.Errorf("notifications.teams.events.%s.channel is not supported: Teams webhooks post to the channel they were created in, set webhook_url instead", event)
+		}
+	}
+
+	email := c.Notifications.Email
+	if email.Host != "" && email.From == "" {
+		return errors.New("notifications.email.from is required when notifications.email.host is set")
+	}
+	for event, route := range email.Events {
+		if !event.IsValid() {
+			return fmt.Errorf("invalid event in notifications.email.events: %s (must be one of %v)", event, NotificationEvents)
+		}
+		for name, text := range map[string]string{"subject": route.Subject, "template": route.Template} {
+			if _, err := template.New(name).Parse(text); err != nil {
+				return fmt.Errorf("invalid %s of notifications.email.events.%s: %w", name, event, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package models
+
+import "testing"
+
+func TestSlackNotificationConfig_Route(t *testing.T) {
+	all := SlackNotificationConfig{}
+	if _, ok := all.Route(NotificationBudgetExceeded); !ok {
+		t.Error("Expected every event to be routed when no events are listed")
+	}
+
+	listed := SlackNotificationConfig{Events: map[NotificationEvent]NotificationRoute{
+		NotificationPRCreated: {Channel: "#prs"},
+	}}
+	route, ok := listed.Route(NotificationPRCreated)
+	if !ok || route.Channel != "#prs" {
+		t.Errorf("Route(pr_created) = %+v, %v, want the #prs route", route, ok)
+	}
+	if _, ok := listed.Route(NotificationProcessingFailed); ok {
+		t.Error("Expected events that aren't listed not to be routed")
+	}
+}
+
+func TestConfig_validateNotifications(t *testing.T) {
+	tests := []struct {
+		name    string
+		events  map[NotificationEvent]NotificationRoute
+		wantErr bool
+	}{
+		{name: "defaults", events: map[NotificationEvent]NotificationRoute{NotificationPRCreated: {}}},
+		{name: "template", events: map[NotificationEvent]NotificationRoute{NotificationProcessingFailed: {Template: "{{.Ticket}} failed: {{.Message}}"}}},
+		{name: "unknown event", events: map[NotificationEvent]NotificationRoute{"pr_merged": {}}, wantErr: true},
+		{name: "invalid template", events: map[NotificationEvent]NotificationRoute{NotificationPRCreated: {Template: "{{.Ticket"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{}
+			config.Notifications.Slack.Events = tt.events
+			if err := config.validateNotifications(); (err != nil) != tt.wantErr {
+				t.Errorf("validateNotifications() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	for _, tt := range tests {
+		t.Run("teams "+tt.name, func(t *testing.T) {
+			config := Config{}
+			config.Notifications.Teams.Events = tt.events
+			if err := config.validateNotifications(); (err != nil) != tt.wantErr {
+				t.Errorf("validateNotifications() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_validateNotifications_TeamsChannel(t *testing.T) {
+	config := Config{}
+	config.Notifications.Teams.Events = map[NotificationEvent]NotificationRoute{NotificationPRCreated: {Channel: "#prs"}}
+	if err := config.validateNotifications(); err == nil {
+		t.Error("Expected an error for a Teams route with a channel")
+	}
+}
+
+func TestEmailNotificationConfig_IsOptedOut(t *testing.T) {
+	email := EmailNotificationConfig{OptOut: []string{"Dev@Example.com", "jdoe"}}
+	tests := []struct {
+		user JiraUser
+		want bool
+	}{
+		{user: JiraUser{EmailAddress: "dev@example.com"}, want: true},
+		{user: JiraUser{Name: "jdoe", EmailAddress: "john@example.com"}, want: true},
+		{user: JiraUser{ID: "557058:abc", EmailAddress: "other@example.com"}, want: false},
+	}
+	for _, tt := range tests {
+		if got := email.IsOptedOut(tt.user); got != tt.want {
+			t.Errorf("IsOptedOut(%+v) = %v, want %v", tt.user, got, tt.want)
+		}
+	}
+}
+
+func TestConfig_validateNotifications_Email(t *testing.T) {
+	config := Config{}
+	config.Notifications.Email.Host = "smtp.example.com"
+	if err := config.validateNotifications(); err == nil {
+		t.Error("Expected an error without a sender address")
+	}
+
+	config.Notifications.Email.From = "ai-bot@example.com"
+	config.Notifications.Email.Events = map[NotificationEvent]EmailRoute{NotificationPRCreated: {Subject: "{{.Ticket"}}
+	if err := config.validateNotifications(); err == nil {
+		t.Error("Expected an error for an invalid subject template")
+	}
+
+	config.Notifications.Email.Events = map[NotificationEvent]EmailRoute{NotificationPRCreated: {Subject: "{{.Ticket}} has a PR"}}
+	if err := config.validateNotifications(); err != nil {
+		t.Errorf("validateNotifications() error = %v", err)
+	}
+}
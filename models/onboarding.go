@@ -0,0 +1,40 @@
+package models
+
+// OnboardingResult is the outcome of checking a Jira setting the application depends on
+type OnboardingResult string
+
+const (
+	// OnboardingResultOK means the setting exists
+	OnboardingResultOK OnboardingResult = "ok"
+	// OnboardingResultMissing means the setting is missing and needs a Jira administrator
+	OnboardingResultMissing OnboardingResult = "missing"
+	// OnboardingResultCreated means the setting was missing and has been created
+	OnboardingResultCreated OnboardingResult = "created"
+	// OnboardingResultUnused means the label isn't used in the project yet; Jira creates it when first added
+	OnboardingResultUnused OnboardingResult = "unused"
+)
+
+// OnboardingCheck is the check of a single status, label or field
+type OnboardingCheck struct {
+	Kind   string           `json:"kind"`   // "status", "label" or "field"
+	Name   string           `json:"name"`   // Name of the status, label or field in Jira
+	Source string           `json:"source"` // Configuration key the name comes from
+	Result OnboardingResult `json:"result"`
+	Detail string           `json:"detail,omitempty"`
+}
+
+// OnboardingReport is the result of checking a Jira project's setup against the configuration
+type OnboardingReport struct {
+	Project string            `json:"project"`
+	Checks  []OnboardingCheck `json:"checks"`
+}
+
+// Ready reports whether nothing the application depends on is missing
+func (r *OnboardingReport) Ready() bool {
+	for _, check := range r.Checks {
+		if check.Result == OnboardingResultMissing {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,212 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PipelineStepName identifies a built-in ticket pipeline step
+type PipelineStepName string
+
+// Built-in ticket pipeline steps
+const (
+	// PipelineStepClone forks the repository, checks it out on the ticket branch and checks contribution requirements
+	PipelineStepClone PipelineStepName = "clone"
+	// PipelineStepGenerateDocs lets the AI write its documentation file (CLAUDE.md or GEMINI.md) if missing
+	PipelineStepGenerateDocs PipelineStepName = "generate_docs"
+	// PipelineStepGitHistory collects recent commits related to the ticket for the generation prompt
+	PipelineStepGitHistory PipelineStepName = "git_history"
+	// PipelineStepGenerate lets the AI change the code for the ticket
+	PipelineStepGenerate PipelineStepName = "generate"
+	// PipelineStepVerify checks the AI's changes before they are committed
+	PipelineStepVerify PipelineStepName = "verify"
+	// PipelineStepCommit commits the changes, split into stacked branches when planned
+	PipelineStepCommit PipelineStepName = "commit"
+	// PipelineStepPush pushes the ticket branches to the fork
+	PipelineStepPush PipelineStepName = "push"
+	// PipelineStepCreatePR opens the pull requests and requests reviews
+	PipelineStepCreatePR PipelineStepName = "create_pr"
+	// PipelineStepNotify links the pull requests on the Jira ticket and moves it to the "In Review" status
+	PipelineStepNotify PipelineStepName = "notify"
+)
+
+// DefaultPipelineSteps is the ticket pipeline used when none is configured
+var DefaultPipelineSteps = []PipelineStepName{
+	PipelineStepClone,
+	PipelineStepGenerateDocs,
+	PipelineStepGitHistory,
+	PipelineStepGenerate,
+	PipelineStepVerify,
+	PipelineStepCommit,
+	PipelineStepPush,
+	PipelineStepCreatePR,
+	PipelineStepNotify,
+}
+
+// pipelineStepDependencies lists the built-in steps that must run before each built-in step
+var pipelineStepDependencies = map[PipelineStepName][]PipelineStepName{
+	PipelineStepClone:        {},
+	PipelineStepGenerateDocs: {PipelineStepClone},
+	PipelineStepGitHistory:   {PipelineStepClone},
+	PipelineStepGenerate:     {PipelineStepClone},
+	PipelineStepVerify:       {PipelineStepGenerate},
+	PipelineStepCommit:       {PipelineStepGenerate},
+	PipelineStepPush:         {PipelineStepCommit},
+	PipelineStepCreatePR:     {PipelineStepPush},
+	PipelineStepNotify:       {PipelineStepCreatePR},
+}
+
+// IsValid checks if the PipelineStepName is a built-in step
+func (n PipelineStepName) IsValid() bool {
+	_, ok := pipelineStepDependencies[n]
+	return ok
+}
+
+// PipelineStepConfig configures a step of the ticket pipeline.
+// A step with a command is a custom step that runs the command in the repository checkout.
+type PipelineStepConfig struct {
+	Name           PipelineStepName `yaml:"name"`
+	Enabled        *bool            `yaml:"enabled"`         // Defaults to true
+	Command        string           `yaml:"command"`         // Shell command of a custom step
+	TimeoutSeconds int              `yaml:"timeout_seconds"` // Timeout of a custom step (default: 300)
+}
+
+// IsEnabled reports whether the step runs
+func (s PipelineStepConfig) IsEnabled() bool {
+	return s.Enabled == nil || *s.Enabled
+}
+
+// IsCustom reports whether the step runs a configured command instead of a built-in step
+func (s PipelineStepConfig) IsCustom() bool {
+	return s.Command != ""
+}
+
+// validatePipelineSteps checks that step names are unique and known, and that every enabled built-in step
+// runs after the steps it depends on. Custom steps need the repository checkout.
+func validatePipelineSteps(steps []PipelineStepConfig) error {
+	configured := make(map[PipelineStepName]bool)
+	enabled := make(map[PipelineStepName]bool)
+	for _, step := range steps {
+		if step.Name == "" {
+			return fmt.Errorf("pipeline step without a name")
+		}
+		if configured[step.Name] {
+			return fmt.Errorf("pipeline step %q is configured twice", step.Name)
+		}
+		configured[step.Name] = true
+		if !step.IsCustom() && !step.Name.IsValid() {
+			return fmt.Errorf("unknown pipeline step %q, custom steps need a command", step.Name)
+		}
+		if step.IsCustom() && step.Name.IsValid() {
+			return fmt.Errorf("custom pipeline step %q can't use the name of a built-in step", step.Name)
+		}
+		if step.TimeoutSeconds < 0 {
+			return fmt.Errorf("pipeline step %q has a negative timeout", step.Name)
+		}
+
+		if !step.IsEnabled() {
+			continue
+		}
+		dependencies := pipelineStepDependencies[step.Name]
+		if step.IsCustom() {
+			dependencies = []PipelineStepName{PipelineStepClone}
+		}
+		var missing []string
+		for _, dependency := range dependencies {
+			if !enabled[dependency] {
+				missing = append(missing, string(dependency))
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("pipeline step %q must run after %s", step.Name, strings.Join(missing, ", "))
+		}
+		enabled[step.Name] = true
+	}
+	if !enabled[PipelineStepGenerate] {
+		return fmt.Errorf("pipeline must include the %q step", PipelineStepGenerate)
+	}
+	return nil
+}
+
+// HookPoint identifies a point of the pipeline where configured commands run
+type HookPoint string
+
+// Pipeline hook points
+const (
+	// HookPreGenerate runs before the AI changes the code
+	HookPreGenerate HookPoint = "pre_generate"
+	// HookPostGenerate runs after the AI changed the code
+	HookPostGenerate HookPoint = "post_generate"
+	// HookPrePush runs before the changes are pushed
+	HookPrePush HookPoint = "pre_push"
+)
+
+// IsValid checks if the HookPoint is a known hook point
+func (h HookPoint) IsValid() bool {
+	switch h {
+	case HookPreGenerate, HookPostGenerate, HookPrePush:
+		return true
+	}
+	return false
+}
+
+// HookCommand is a shell command run in the repository checkout at a hook point
+type HookCommand struct {
+	Name           string `yaml:"name"`
+	Command        string `yaml:"command"`
+	TimeoutSeconds int    `yaml:"timeout_seconds"` // Default: 300
+}
+
+// CheckCommandConfig configures a command that must pass before the AI's changes are committed, such as the build or
+// the tests
+type CheckCommandConfig struct {
+	Command        string `yaml:"command"`                      // e.g. go build ./... or make test; no check if empty
+	TimeoutSeconds int    `yaml:"timeout_seconds"`              // Default: 300
+	MaxFixAttempts int    `yaml:"max_fix_attempts" default:"2"` // AI runs fixing a failing check before the ticket fails
+}
+
+// validate checks that the check has no negative limits
+func (b CheckCommandConfig) validate() error {
+	if b.TimeoutSeconds < 0 {
+		return errors.New("negative timeout")
+	}
+	if b.MaxFixAttempts < 0 {
+		return errors.New("negative max_fix_attempts")
+	}
+	return nil
+}
+
+// validateFormatters checks that every formatter has a command
+func validateFormatters(formatters []HookCommand) error {
+	for i, formatter := range formatters {
+		if formatter.Command == "" {
+			return fmt.Errorf("formatter %d has no command", i+1)
+		}
+		if formatter.TimeoutSeconds < 0 {
+			return fmt.Errorf("formatter %d has a negative timeout", i+1)
+		}
+	}
+	return nil
+}
+
+// PipelineHooks lists the commands run at each hook point, in order
+type PipelineHooks map[HookPoint][]HookCommand
+
+// validate checks that the hook points are known and every hook has a command
+func (h PipelineHooks) validate() error {
+	for point, commands := range h {
+		if !point.IsValid() {
+			return fmt.Errorf("unknown hook point %q", point)
+		}
+		for i, command := range commands {
+			if command.Command == "" {
+				return fmt.Errorf("hook %d of %s has no command", i+1, point)
+			}
+			if command.TimeoutSeconds < 0 {
+				return fmt.Errorf("hook %d of %s has a negative timeout", i+1, point)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RepoConfigFile is the file a repository checks in to tell the bot how to work on it
+const RepoConfigFile = ".ai-solver.yaml"
+
+// RepoConfig is the configuration a repository checks in as RepoConfigFile. It is merged into the configuration of
+// every ticket of the repository.
+type RepoConfig struct {
+	Instructions   string              `yaml:"instructions"`    // Added to the AI's generation prompt
+	ForbiddenPaths []string            `yaml:"forbidden_paths"` // Protected in addition to protected_paths, in CODEOWNERS syntax
+	Build          *CheckCommandConfig `yaml:"build"`           // Replaces the configured build check
+	Test           *CheckCommandConfig `yaml:"test"`            // Replaces the configured test check
+	Lint           *CheckCommandConfig `yaml:"lint"`            // Replaces the configured lint check
+	Branch         struct {
+		Prefix string `yaml:"prefix"` // Prepended to the ticket key to name the ticket branch, e.g. ai/
+	} `yaml:"branch"`
+}
+
+// ParseRepoConfig parses a repository's configuration file, rejecting unknown keys. An empty file is an empty
+// configuration.
+func ParseRepoConfig(data []byte) (*RepoConfig, error) {
+	var config RepoConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	for name, check := range map[string]*CheckCommandConfig{"build": config.Build, "test": config.Test, "lint": config.Lint} {
+		if check == nil {
+			continue
+		}
+		if err := check.validate(); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		if check.MaxFixAttempts == 0 {
+			check.MaxFixAttempts = 2
+		}
+	}
+	if err := validateBranchPrefix(config.Branch.Prefix); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// validateBranchPrefix checks that the prefix can start a git branch name
+func validateBranchPrefix(prefix string) error {
+	if prefix == "" {
+		return nil
+	}
+	if strings.HasPrefix(prefix, "-") || strings.HasPrefix(prefix, "/") || strings.Contains(prefix, "..") ||
+		strings.Contains(prefix, "//") || strings.ContainsAny(prefix, " ~^:?*[\\") {
+		return fmt.Errorf("invalid branch.prefix %q: not allowed in a git branch name", prefix)
+	}
+	return nil
+}
+
+// GetCheck returns the repository's check of the given name (build, test or lint), or the configured one if the
+// repository doesn't set it
+func (r *RepoConfig) GetCheck(name string, configured CheckCommandConfig) CheckCommandConfig {
+	if r == nil {
+		return configured
+	}
+	var check *CheckCommandConfig
+	switch name {
+	case "build":
+		check = r.Build
+	case "test":
+		check = r.Test
+	case "lint":
+		check = r.Lint
+	}
+	if check == nil {
+		return configured
+	}
+	return *check
+}
+
+// GetForbiddenPaths returns the paths the repository protects from the AI
+func (r *RepoConfig) GetForbiddenPaths() []string {
+	if r == nil {
+		return nil
+	}
+	return r.ForbiddenPaths
+}
+
+// GetBranchPrefix returns the repository's branch prefix, or the configured one if the repository doesn't set it
+func (r *RepoConfig) GetBranchPrefix(configured string) string {
+	if r == nil || r.Branch.Prefix == "" {
+		return configured
+	}
+	return r.Branch.Prefix
+}
@@ -0,0 +1,75 @@
+package models
+
+import "testing"
+
+func TestParseRepoConfig(t *testing.T) {
+	data := []byte(`
+instructions: Use the logger package, never fmt.Println.
+forbidden_paths:
+  - migrations/
+test:
+  command: make test
+branch:
+  prefix: ai/
+`)
+	config, err := ParseRepoConfig(data)
+	if err != nil {
+		t.Fatalf("ParseRepoConfig() error = %v", err)
+	}
+	if config.Instructions != "Use the logger package, never fmt.Println." {
+		t.Errorf("Unexpected instructions %q", config.Instructions)
+	}
+	if len(config.GetForbiddenPaths()) != 1 || config.GetForbiddenPaths()[0] != "migrations/" {
+		t.Errorf("Unexpected forbidden paths %q", config.GetForbiddenPaths())
+	}
+
+	configured := CheckCommandConfig{Command: "go build ./...", MaxFixAttempts: 1}
+	if check := config.GetCheck("test", configured); check.Command != "make test" || check.MaxFixAttempts != 2 {
+		t.Errorf("Expected the repository's test check with the default fix attempts, got %+v", check)
+	}
+	if check := config.GetCheck("build", configured); check != configured {
+		t.Errorf("Expected the configured build check, got %+v", check)
+	}
+	if prefix := config.GetBranchPrefix("bot/"); prefix != "ai/" {
+		t.Errorf("GetBranchPrefix() = %q, want ai/", prefix)
+	}
+}
+
+func TestParseRepoConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "unknown key", data: "instruction: typo\n"},
+		{name: "negative fix attempts", data: "lint:\n  command: make lint\n  max_fix_attempts: -1\n"},
+		{name: "invalid branch prefix", data: "branch:\n  prefix: 'ai bot/'\n"},
+		{name: "not yaml", data: "instructions: [\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseRepoConfig([]byte(tt.data)); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestRepoConfig_Nil(t *testing.T) {
+	var config *RepoConfig
+	if prefix := config.GetBranchPrefix("bot/"); prefix != "bot/" {
+		t.Errorf("GetBranchPrefix() = %q, want bot/", prefix)
+	}
+	configured := CheckCommandConfig{Command: "make test"}
+	if check := config.GetCheck("test", configured); check != configured {
+		t.Errorf("Expected the configured check, got %+v", check)
+	}
+	if paths := config.GetForbiddenPaths(); paths != nil {
+		t.Errorf("Expected no forbidden paths, got %q", paths)
+	}
+
+	empty, err := ParseRepoConfig(nil)
+	if err != nil || empty == nil {
+		t.Fatalf("Expected an empty file to be an empty configuration, got %v, %v", empty, err)
+	}
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+)
+
+// RepoMapping maps the components matching a pattern, optionally only in one Jira project, to a repository
+type RepoMapping struct {
+	Project        string `yaml:"project"`         // Jira project key the mapping is limited to; all projects if empty
+	Component      string `yaml:"component"`       // Glob pattern of component names, e.g. *backend*
+	ComponentRegex string `yaml:"component_regex"` // Regular expression of component names, instead of component
+	Repo           string `yaml:"repo"`            // Repository URL
+}
+
+// Matches reports whether the mapping applies to a component of a ticket in the project. Mappings without a
+// component pattern apply to every component of their project.
+func (m RepoMapping) Matches(project, component string) bool {
+	if m.Project != "" && m.Project != project {
+		return false
+	}
+	switch {
+	case m.Component != "":
+		matched, _ := path.Match(m.Component, component)
+		return matched
+	case m.ComponentRegex != "":
+		matched, _ := regexp.MatchString(m.ComponentRegex, component)
+		return matched
+	default:
+		return component != ""
+	}
+}
+
+// HasRepoMappings reports whether any ticket can be mapped to a repository
+func (c *Config) HasRepoMappings() bool {
+	reloadMu.RLock()
+	defer reloadMu.RUnlock()
+	return len(c.ComponentToRepo) > 0 || len(c.RepoMappings) > 0 || c.RepoProjectProperty != "" || c.DefaultRepo != ""
+}
+
+// ResolveRepo returns the repository of a ticket in the project with the component, empty for tickets without
+// components. An exact component_to_repo mapping takes precedence over the repo_mappings, which are tried in order;
+// the default_repo is the fallback.
+func (c *Config) ResolveRepo(project, component string) (string, bool) {
+	if repoURL, ok := c.ResolveMappedRepo(project, component); ok {
+		return repoURL, true
+	}
+	if _, defaultRepo := c.RepoFallbacks(); defaultRepo != "" {
+		return defaultRepo, true
+	}
+	return "", false
+}
+
+// ResolveMappedRepo returns the repository the component_to_repo mapping or the repo_mappings give a ticket in the
+// project with the component, without falling back to the default_repo
+func (c *Config) ResolveMappedRepo(project, component string) (string, bool) {
+	reloadMu.RLock()
+	defer reloadMu.RUnlock()
+	if repoURL := c.ComponentToRepo[component]; component != "" && repoURL != "" {
+		return repoURL, true
+	}
+	for _, mapping := range c.RepoMappings {
+		if mapping.Matches(project, component) {
+			return mapping.Repo, true
+		}
+	}
+	return "", false
+}
+
+// validateRepoMappings ensures every pattern-based repository mapping has a repository and a valid pattern
+func (c *Config) validateRepoMappings() error {
+	for i, mapping := range c.RepoMappings {
+		if mapping.Repo == "" {
+			return fmt.Errorf("repo_mappings[%d].repo is required", i)
+		}
+		if mapping.Component != "" && mapping.ComponentRegex != "" {
+			return fmt.Errorf("repo_mappings[%d] can only have one of component and component_regex", i)
+		}
+		if mapping.Project == "" && mapping.Component == "" && mapping.ComponentRegex == "" {
+			return fmt.Errorf("repo_mappings[%d] needs a project, component or component_regex", i)
+		}
+		if _, err := path.Match(mapping.Component, ""); errors.Is(err, path.ErrBadPattern) {
+			return fmt.Errorf("invalid glob pattern in repo_mappings[%d].component: %s", i, mapping.Component)
+		}
+		if _, err := regexp.Compile(mapping.ComponentRegex); err != nil {
+			return fmt.Errorf("invalid regular expression in repo_mappings[%d].component_regex: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package models
+
+import "testing"
+
+func TestConfig_ResolveRepo(t *testing.T) {
+	config := &Config{}
+	config.ComponentToRepo = map[string]string{"payments-backend-legacy": "https://github.com/example/legacy.git"}
+	config.RepoMappings = []RepoMapping{
+		{Project: "PAY", Component: "*backend*", Repo: "https://github.com/example/payments.git"},
+		{ComponentRegex: "^(web|mobile)-", Repo: "https://github.com/example/clients.git"},
+		{Project: "OPS", Repo: "https://github.com/example/infra.git"},
+	}
+	config.DefaultRepo = "https://github.com/example/monorepo.git"
+
+	tests := []struct {
+		name      string
+		project   string
+		component string
+		want      string
+	}{
+		{name: "exact mapping first", project: "PAY", component: "payments-backend-legacy", want: "https://github.com/example/legacy.git"},
+		{name: "project scoped glob", project: "PAY", component: "ledger-backend", want: "https://github.com/example/payments.git"},
+		{name: "glob limited to project", project: "SHOP", component: "ledger-backend", want: "https://github.com/example/monorepo.git"},
+		{name: "regex", project: "SHOP", component: "web-checkout", want: "https://github.com/example/clients.git"},
+		{name: "any component of project", project: "OPS", component: "terraform", want: "https://github.com/example/infra.git"},
+		{name: "no component", project: "OPS", component: "", want: "https://github.com/example/monorepo.git"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := config.ResolveRepo(tt.project, tt.component)
+			if !ok || got != tt.want {
+				t.Errorf("ResolveRepo() = %q, %v, want %q", got, ok, tt.want)
+			}
+		})
+	}
+
+	config.DefaultRepo = ""
+	if got, ok := config.ResolveRepo("SHOP", "unknown"); ok {
+		t.Errorf("ResolveRepo() = %q, want no repository without a default", got)
+	}
+}
+
+func TestConfig_validateRepoMappings(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping RepoMapping
+		wantErr bool
+	}{
+		{name: "glob", mapping: RepoMapping{Component: "*backend*", Repo: "https://github.com/example/a.git"}},
+		{name: "project only", mapping: RepoMapping{Project: "PAY", Repo: "https://github.com/example/a.git"}},
+		{name: "missing repo", mapping: RepoMapping{Component: "*"}, wantErr: true},
+		{name: "no pattern or project", mapping: RepoMapping{Repo: "https://github.com/example/a.git"}, wantErr: true},
+		{name: "glob and regex", mapping: RepoMapping{Component: "*", ComponentRegex: ".*", Repo: "https://github.com/example/a.git"}, wantErr: true},
+		{name: "invalid glob", mapping: RepoMapping{Component: "[backend", Repo: "https://github.com/example/a.git"}, wantErr: true},
+		{name: "invalid regex", mapping: RepoMapping{ComponentRegex: "(backend", Repo: "https://github.com/example/a.git"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{RepoMappings: []RepoMapping{tt.mapping}}
+			if err := config.validateRepoMappings(); (err != nil) != tt.wantErr {
+				t.Errorf("validateRepoMappings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SandboxBackend is where the AI CLIs run
+type SandboxBackend string
+
+const (
+	SandboxBackendHost   SandboxBackend = "host"   // Directly on the host, as the application's user
+	SandboxBackendDocker SandboxBackend = "docker" // In a Docker container per run, with only the checkout mounted
+)
+
+// IsValid checks if the sandbox backend is valid
+func (b SandboxBackend) IsValid() bool {
+	return b == SandboxBackendHost || b == SandboxBackendDocker
+}
+
+// DockerSandboxConfig configures the containers the AI CLIs run in with the docker sandbox backend
+type DockerSandboxConfig struct {
+	Image   string   `yaml:"image"`                   // Image with the AI CLIs installed; required
+	Network string   `yaml:"network" default:"none"`  // Network of the containers; none cuts them off entirely
+	Proxy   string   `yaml:"proxy"`                   // HTTP(S) proxy the CLIs reach the network through, e.g. one allowing only the AI provider's API
+	CPUs    string   `yaml:"cpus" default:"2"`        // CPU limit of a container
+	Memory  string   `yaml:"memory" default:"4g"`     // Memory limit of a container
+	PIDs    int      `yaml:"pids" default:"512"`      // Process limit of a container
+	Env     []string `yaml:"env"`                     // Host environment variables passed to the CLIs, e.g. ANTHROPIC_API_KEY
+	Volumes []string `yaml:"volumes"`                 // Extra mounts in docker -v syntax, e.g. CLI credentials; HOME is /tmp
+	Binary  string   `yaml:"binary" default:"docker"` // Docker CLI, or a compatible one such as podman
+}
+
+// validateSandbox ensures the sandbox the AI CLIs run in is properly configured
+func (c *Config) validateSandbox() error {
+	if !c.Sandbox.Backend.IsValid() {
+		return fmt.Errorf("invalid sandbox.backend: %s (must be host or docker)", c.Sandbox.Backend)
+	}
+	if c.Sandbox.Backend == SandboxBackendDocker && c.Sandbox.Docker.Image == "" {
+		return errors.New("sandbox.docker.image is required when sandbox.backend is docker")
+	}
+	if c.Sandbox.Backend == SandboxBackendDocker && c.Sandbox.Docker.Network == "none" && c.Sandbox.Docker.Proxy != "" {
+		return errors.New("sandbox.docker.proxy can't be reached on sandbox.docker.network none; set the network to a Docker network the proxy is reachable on")
+	}
+	return nil
+}
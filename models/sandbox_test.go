@@ -0,0 +1,34 @@
+package models
+
+import "testing"
+
+func TestConfig_validateSandbox(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend SandboxBackend
+		image   string
+		network string
+		proxy   string
+		wantErr bool
+	}{
+		{name: "host", backend: SandboxBackendHost},
+		{name: "docker", backend: SandboxBackendDocker, image: "ai-clis:1"},
+		{name: "docker without image", backend: SandboxBackendDocker, wantErr: true},
+		{name: "docker with proxy", backend: SandboxBackendDocker, image: "ai-clis:1", network: "egress", proxy: "http://proxy:3128"},
+		{name: "proxy without network", backend: SandboxBackendDocker, image: "ai-clis:1", network: "none", proxy: "http://proxy:3128", wantErr: true},
+		{name: "unknown backend", backend: "firecracker", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{}
+			config.Sandbox.Backend = tt.backend
+			config.Sandbox.Docker.Image = tt.image
+			config.Sandbox.Docker.Network = tt.network
+			config.Sandbox.Docker.Proxy = tt.proxy
+			if err := config.validateSandbox(); (err != nil) != tt.wantErr {
+				t.Errorf("validateSandbox() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
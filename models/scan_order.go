@@ -0,0 +1,35 @@
+package models
+
+// ScanOrder is the order the issue scanner processes the tickets it finds in
+type ScanOrder string
+
+const (
+	ScanOrderPriority ScanOrder = "priority" // Highest priority first, e.g. Blocker before Major
+	ScanOrderRank     ScanOrder = "rank"     // Board rank, as ordered on the backlog
+	ScanOrderCreated  ScanOrder = "created"  // Oldest first
+	ScanOrderUpdated  ScanOrder = "updated"  // Most recently updated first
+)
+
+// IsValid checks if the scan order is valid
+func (o ScanOrder) IsValid() bool {
+	switch o {
+	case ScanOrderPriority, ScanOrderRank, ScanOrderCreated, ScanOrderUpdated:
+		return true
+	default:
+		return false
+	}
+}
+
+// OrderBy returns the JQL ORDER BY clause of the scan order. Tickets of the same priority are taken oldest first.
+func (o ScanOrder) OrderBy() string {
+	switch o {
+	case ScanOrderRank:
+		return "ORDER BY Rank ASC"
+	case ScanOrderCreated:
+		return "ORDER BY created ASC"
+	case ScanOrderUpdated:
+		return "ORDER BY updated DESC"
+	default:
+		return "ORDER BY priority DESC, created ASC"
+	}
+}
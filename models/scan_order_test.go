@@ -0,0 +1,29 @@
+package models
+
+import "testing"
+
+func TestScanOrder_OrderBy(t *testing.T) {
+	tests := []struct {
+		order ScanOrder
+		want  string
+	}{
+		{order: ScanOrderPriority, want: "ORDER BY priority DESC, created ASC"},
+		{order: ScanOrderRank, want: "ORDER BY Rank ASC"},
+		{order: ScanOrderCreated, want: "ORDER BY created ASC"},
+		{order: ScanOrderUpdated, want: "ORDER BY updated DESC"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.order), func(t *testing.T) {
+			if !tt.order.IsValid() {
+				t.Errorf("Expected %s to be valid", tt.order)
+			}
+			if got := tt.order.OrderBy(); got != tt.want {
+				t.Errorf("OrderBy() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if ScanOrder("votes").IsValid() {
+		t.Error("Expected an unknown scan order to be invalid")
+	}
+}
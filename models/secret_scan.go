@@ -0,0 +1,58 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// SecretRule is a pattern of secrets the secret scanner looks for in added lines
+type SecretRule struct {
+	ID      string `yaml:"id"`      // Name of the rule in findings, e.g. internal-api-key
+	Pattern string `yaml:"pattern"` // Go regular expression matching the secret
+}
+
+// SecretScanConfig configures the scan of the commits about to be pushed for secrets
+type SecretScanConfig struct {
+	Enabled       bool         `yaml:"enabled" default:"false"` // Block pushes of commits containing secrets
+	Rules         []SecretRule `yaml:"rules"`                   // Rules checked in addition to the built-in ones
+	DisabledRules []string     `yaml:"disabled_rules"`          // IDs of built-in rules that aren't checked
+	AllowedPaths  []string     `yaml:"allowed_paths"`           // Regular expressions of files never reported, e.g. test fixtures
+	AllowedValues []string     `yaml:"allowed_values"`          // Regular expressions of matches never reported, e.g. well-known example keys
+	Command       string       `yaml:"command"`                 // External scanner reading the patch on stdin, e.g. gitleaks stdin --redact; a non-zero exit status blocks the push
+}
+
+// SecretFinding is a possible secret in a line about to be pushed
+type SecretFinding struct {
+	RuleID string
+	File   string // Empty for findings of an external scanner, which are described by Match
+	Line   int    // Line in the new version of the file
+	Match  string // Masked match, so reports don't leak the secret
+}
+
+// String describes the finding without revealing the secret
+func (f SecretFinding) String() string {
+	if f.File == "" {
+		return fmt.Sprintf("%s: %s", f.RuleID, f.Match)
+	}
+	return fmt.Sprintf("%s:%d: %s (%s)", f.File, f.Line, f.RuleID, f.Match)
+}
+
+// validateSecretScan ensures the secret scan's rules and allowlists are valid regular expressions
+func (c *Config) validateSecretScan() error {
+	for i, rule := range c.SecretScan.Rules {
+		if rule.ID == "" {
+			return fmt.Errorf("secret_scan.rules[%d] has no id", i)
+		}
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern of secret_scan rule %s: %w", rule.ID, err)
+		}
+	}
+	for name, patterns := range map[string][]string{"allowed_paths": c.SecretScan.AllowedPaths, "allowed_values": c.SecretScan.AllowedValues} {
+		for _, pattern := range patterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("invalid secret_scan.%s pattern %q: %w", name, pattern, err)
+			}
+		}
+	}
+	return nil
+}
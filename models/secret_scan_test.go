@@ -0,0 +1,26 @@
+package models
+
+import "testing"
+
+func TestConfig_validateSecretScan(t *testing.T) {
+	tests := []struct {
+		name    string
+		scan    SecretScanConfig
+		wantErr bool
+	}{
+		{name: "defaults"},
+		{name: "rule", scan: SecretScanConfig{Rules: []SecretRule{{ID: "internal", Pattern: `itk_[a-z]+`}}}},
+		{name: "rule without id", scan: SecretScanConfig{Rules: []SecretRule{{Pattern: `itk_[a-z]+`}}}, wantErr: true},
+		{name: "invalid pattern", scan: SecretScanConfig{Rules: []SecretRule{{ID: "internal", Pattern: `itk_[`}}}, wantErr: true},
+		{name: "invalid allowed path", scan: SecretScanConfig{AllowedPaths: []string{`(`}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{SecretScan: tt.scan}
+			if err := config.validateSecretScan(); (err != nil) != tt.wantErr {
+				t.Errorf("validateSecretScan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// AISession kinds, the step of the ticket an AI session was run for
+const (
+	AISessionInitial  = "initial"  // Generating the pull request
+	AISessionFeedback = "feedback" // Applying review feedback to the pull request
+)
+
+// AISessionSummary is a line of the session summary log, condensing what an AI session on a ticket did
+type AISessionSummary struct {
+	Ticket  string    `json:"ticket"`
+	Kind    string    `json:"kind"`
+	Summary string    `json:"summary"` // The AI's final report, shortened
+	At      time.Time `json:"at"`
+}
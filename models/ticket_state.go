@@ -0,0 +1,118 @@
+package models
+
+import "time"
+
+// TicketState is a stage of the ticket pipeline
+type TicketState string
+
+// Ticket pipeline states
+const (
+	// TicketStateQueued means the ticket was picked up and is waiting for its repository to be prepared
+	TicketStateQueued TicketState = "queued"
+	// TicketStateCloning means the repository is being forked and checked out
+	TicketStateCloning TicketState = "cloning"
+	// TicketStateGenerating means the AI is changing the code
+	TicketStateGenerating TicketState = "generating"
+	// TicketStateVerifying means the AI's changes are being checked before they are pushed
+	TicketStateVerifying TicketState = "verifying"
+	// TicketStatePushing means the changes are being committed, pushed and opened as pull requests
+	TicketStatePushing TicketState = "pushing"
+	// TicketStatePROpen means the pull request is open and waiting for review
+	TicketStatePROpen TicketState = "pr_open"
+	// TicketStateFeedback means review or CI feedback is being applied to the pull request
+	TicketStateFeedback TicketState = "feedback"
+	// TicketStateDone means the pull request was merged or the ticket closed
+	TicketStateDone TicketState = "done"
+	// TicketStateFailed means processing failed; the error is kept on the ticket's record
+	TicketStateFailed TicketState = "failed"
+	// TicketStateHandedOff means a human took the pull request over and the bot no longer touches it
+	TicketStateHandedOff TicketState = "handed_off"
+)
+
+// TicketStates lists all ticket pipeline states in pipeline order
+var TicketStates = []TicketState{
+	TicketStateQueued,
+	TicketStateCloning,
+	TicketStateGenerating,
+	TicketStateVerifying,
+	TicketStatePushing,
+	TicketStatePROpen,
+	TicketStateFeedback,
+	TicketStateDone,
+	TicketStateFailed,
+	TicketStateHandedOff,
+}
+
+// String returns the string representation of a TicketState
+func (s TicketState) String() string {
+	return string(s)
+}
+
+// IsValid reports whether the state is a known ticket pipeline state
+func (s TicketState) IsValid() bool {
+	return s.index() >= 0
+}
+
+// index returns the position of the state in TicketStates, or -1 for unknown states
+func (s TicketState) index() int {
+	for i, state := range TicketStates {
+		if state == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// IsTerminal reports whether the ticket pipeline has finished
+func (s TicketState) IsTerminal() bool {
+	return s == TicketStateDone || s == TicketStateFailed || s == TicketStateHandedOff
+}
+
+// IsInterrupted reports whether a ticket left in this state was stopped before its pull request was opened
+func (s TicketState) IsInterrupted() bool {
+	switch s {
+	case TicketStateQueued, TicketStateCloning, TicketStateGenerating, TicketStateVerifying, TicketStatePushing:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo reports whether a ticket may move from this state to the given state.
+// Every state may be queued again when the ticket is restarted after an interruption, reopened or sent
+// back to the todo status, and every non-terminal state may finish, fail or be handed off. Up to the open PR the states
+// only move forward, skipping the states of disabled pipeline steps; afterwards the ticket alternates
+// between waiting for review and applying feedback.
+func (s TicketState) CanTransitionTo(to TicketState) bool {
+	switch {
+	case to == TicketStateQueued:
+		return true
+	case s.IsTerminal():
+		return false
+	case to == TicketStateFailed, to == TicketStateDone, to == TicketStateHandedOff:
+		return true
+	case to == TicketStateFeedback:
+		return s == TicketStatePROpen
+	case to == TicketStatePROpen && s == TicketStateFeedback:
+		return true
+	case s.IsInterrupted() && (to.IsInterrupted() || to == TicketStatePROpen):
+		return to.index() > s.index()
+	}
+	return false
+}
+
+// TicketStateTransition records a ticket moving between pipeline states
+type TicketStateTransition struct {
+	From  TicketState `json:"from,omitempty"`
+	To    TicketState `json:"to"`
+	At    time.Time   `json:"at"`
+	Error string      `json:"error,omitempty"`
+}
+
+// TicketStateRecord is the persisted pipeline state of a ticket
+type TicketStateRecord struct {
+	Ticket      string                  `json:"ticket"`
+	State       TicketState             `json:"state"`
+	UpdatedAt   time.Time               `json:"updated_at"`
+	Error       string                  `json:"error,omitempty"`
+	Transitions []TicketStateTransition `json:"transitions"`
+}
@@ -0,0 +1,30 @@
+package models
+
+// TriggerMode is what marks a ticket for processing by the AI
+type TriggerMode string
+
+const (
+	TriggerModeStatus TriggerMode = "status" // Tickets of the bot in the todo status
+	TriggerModeLabel  TriggerMode = "label"  // Tickets with the trigger label, whatever their status
+	TriggerModeBoth   TriggerMode = "both"   // Tickets in the todo status with the trigger label
+)
+
+// IsValid checks if the trigger mode is valid
+func (m TriggerMode) IsValid() bool {
+	switch m {
+	case TriggerModeStatus, TriggerModeLabel, TriggerModeBoth:
+		return true
+	default:
+		return false
+	}
+}
+
+// UsesStatus reports whether tickets must be in the todo status to be processed
+func (m TriggerMode) UsesStatus() bool {
+	return m != TriggerModeLabel
+}
+
+// UsesLabel reports whether tickets must have the trigger label to be processed
+func (m TriggerMode) UsesLabel() bool {
+	return m == TriggerModeLabel || m == TriggerModeBoth
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// AIUsageRecord is a line of the AI usage history, describing the AI run of a processed ticket
+type AIUsageRecord struct {
+	Time         time.Time `json:"time"`
+	Ticket       string    `json:"ticket"`
+	Provider     string    `json:"provider,omitempty"`
+	PromptChars  int       `json:"prompt_chars"`
+	InputTokens  int       `json:"input_tokens"`
+	OutputTokens int       `json:"output_tokens"`
+	CostUSD      float64   `json:"cost_usd"`
+
+	Project         string  `json:"project,omitempty"`          // Jira project of the ticket
+	Component       string  `json:"component,omitempty"`        // Component of the ticket that decided its repository
+	DurationSeconds float64 `json:"duration_seconds,omitempty"` // How long the AI ran
+}
+
+// TicketEstimate is the projected AI usage of processing a single ticket
+type TicketEstimate struct {
+	Ticket       string  `json:"ticket"`
+	Summary      string  `json:"summary"`
+	PromptChars  int     `json:"prompt_chars"`
+	PromptTokens int     `json:"prompt_tokens"`
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	CostUSD      float64 `json:"cost_usd"`
+	Skipped      string  `json:"skipped,omitempty"` // Why the ticket wouldn't reach the AI, if it wouldn't
+}
+
+// CostEstimate is the projected AI usage of processing the tickets matching a JQL query
+type CostEstimate struct {
+	JQL                 string           `json:"jql"`
+	HistoryRuns         int              `json:"history_runs"` // AI runs the averages are based on
+	AveragePromptTokens int              `json:"average_prompt_tokens"`
+	AverageInputTokens  int              `json:"average_input_tokens"`
+	AverageOutputTokens int              `json:"average_output_tokens"`
+	AverageCostUSD      float64          `json:"average_cost_usd"`
+	Tickets             []TicketEstimate `json:"tickets"`
+	TotalInputTokens    int              `json:"total_input_tokens"`
+	TotalOutputTokens   int              `json:"total_output_tokens"`
+	TotalCostUSD        float64          `json:"total_cost_usd"`
+}
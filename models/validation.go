@@ -0,0 +1,35 @@
+package models
+
+// ValidationResult is the outcome of checking a configuration setting
+type ValidationResult string
+
+const (
+	// ValidationResultOK means the setting is valid
+	ValidationResultOK ValidationResult = "ok"
+	// ValidationResultFailed means the setting is missing or invalid
+	ValidationResultFailed ValidationResult = "failed"
+	// ValidationResultSkipped means the setting couldn't be checked, e.g. because Jira wasn't contacted
+	ValidationResultSkipped ValidationResult = "skipped"
+)
+
+// ValidationCheck is the check of a single configuration setting
+type ValidationCheck struct {
+	Setting string           `json:"setting"` // Configuration key checked, e.g. jira.base_url
+	Result  ValidationResult `json:"result"`
+	Detail  string           `json:"detail,omitempty"`
+}
+
+// ValidationReport is the result of validating a configuration
+type ValidationReport struct {
+	Checks []ValidationCheck `json:"checks"`
+}
+
+// Valid reports whether no check failed
+func (r *ValidationReport) Valid() bool {
+	for _, check := range r.Checks {
+		if check.Result == ValidationResultFailed {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/services"
+
+	"go.uber.org/zap"
+)
+
+// runOnboard runs the onboard subcommand, which checks that a Jira project has the statuses, labels and fields the
+// configuration refers to, and returns the exit code. It fails if anything the application depends on is missing.
+func runOnboard(args []string) int {
+	flags := flag.NewFlagSet("onboard", flag.ContinueOnError)
+	configPath := flags.String("config", "config.yaml", "Path to configuration file")
+	project := flags.String("project", "", "Key of the Jira project to check")
+	createField := flags.Bool("create-field", false, "Create the Git Pull Request custom field if it's missing")
+	asJSON := flags.Bool("json", false, "Write the report as JSON")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *project == "" {
+		fmt.Fprintln(os.Stderr, "onboard requires -project")
+		flags.Usage()
+		return 2
+	}
+
+	config, err := models.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		return 1
+	}
+
+	// The report goes to stdout, so it can be redirected without the logs
+	InitLogger(config, os.Stderr)
+	defer Logger.Sync()
+
+	jiraService, err := newCommandJiraService(config)
+	if err != nil {
+		Logger.Error("Failed to initialize Jira service", zap.Error(err))
+		return 1
+	}
+
+	report, err := services.NewJiraOnboarder(jiraService, config, Logger).Onboard(context.Background(), *project, *createField)
+	if err != nil {
+		Logger.Error("Failed to check Jira project", zap.String("project", *project), zap.Error(err))
+		return 1
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	} else {
+		err = services.WriteOnboardingReport(os.Stdout, report)
+	}
+	if err != nil {
+		Logger.Error("Failed to write report", zap.Error(err))
+		return 1
+	}
+	if !report.Ready() {
+		return 1
+	}
+	return 0
+}
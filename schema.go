@@ -0,0 +1,27 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"jira-ai-issue-solver/models"
+)
+
+// runSchema runs the schema subcommand, which writes the JSON Schema of the configuration file for editors and CI
+// checks, and returns the exit code
+func runSchema(args []string) int {
+	flags := flag.NewFlagSet("schema", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(models.ConfigSchema()); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write schema: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ConcurrencyLimitedAIServiceImpl implements AIService by running another AIService with at most a fixed number of
+// concurrent runs, queueing the others, so bursts of tickets don't trip the provider's own throttling
+type ConcurrencyLimitedAIServiceImpl struct {
+	service  AIService
+	provider string
+	slots    chan struct{} // Holds a token per running CLI process
+	metrics  Metrics
+	logger   *zap.Logger
+
+	mu     sync.Mutex
+	queued int
+	active int
+}
+
+// NewConcurrencyLimitedAIService creates an AIService that runs at most maxRuns CLI processes of the provider at once
+func NewConcurrencyLimitedAIService(provider string, service AIService, maxRuns int, metrics Metrics, logger *zap.Logger) AIService {
+	return &ConcurrencyLimitedAIServiceImpl{
+		service:  service,
+		provider: provider,
+		slots:    make(chan struct{}, maxRuns),
+		metrics:  metrics,
+		logger:   logger,
+	}
+}
+
+// GenerateCode generates code once a run slot of the provider is free
+func (s *ConcurrencyLimitedAIServiceImpl) GenerateCode(ctx context.Context, prompt string, repoDir string) (interface{}, error) {
+	if err := s.acquire(ctx, repoDir); err != nil {
+		return nil, err
+	}
+	defer s.release()
+	return s.service.GenerateCode(ctx, prompt, repoDir)
+}
+
+// GenerateDocumentation generates documentation once a run slot of the provider is free
+func (s *ConcurrencyLimitedAIServiceImpl) GenerateDocumentation(ctx context.Context, repoDir string) error {
+	if err := s.acquire(ctx, repoDir); err != nil {
+		return err
+	}
+	defer s.release()
+	return s.service.GenerateDocumentation(ctx, repoDir)
+}
+
+// acquire waits for a free run slot, or until the context is done
+func (s *ConcurrencyLimitedAIServiceImpl) acquire(ctx context.Context, repoDir string) error {
+	select {
+	case s.slots <- struct{}{}:
+		s.update(0, 1)
+		return nil
+	default:
+	}
+
+	s.logger.Info("AI provider is at its concurrency limit, queueing run",
+		zap.String("provider", s.provider),
+		zap.Int("max_concurrent_runs", cap(s.slots)),
+		zap.String("repo_dir", repoDir))
+	labels := map[string]string{"provider": s.provider}
+	s.metrics.IncCounter("ai_runs_queued_total", labels)
+	s.update(1, 0)
+	queuedAt := time.Now()
+	defer func() {
+		s.metrics.AddCounter("ai_runs_queue_wait_seconds_total", labels, time.Since(queuedAt).Seconds())
+	}()
+
+	select {
+	case s.slots <- struct{}{}:
+		s.update(-1, 1)
+		return nil
+	case <-ctx.Done():
+		s.update(-1, 0)
+		return ctx.Err()
+	}
+}
+
+// release frees the run slot
+func (s *ConcurrencyLimitedAIServiceImpl) release() {
+	<-s.slots
+	s.update(0, -1)
+}
+
+// update adjusts the queued and active run counts and exposes them as gauges
+func (s *ConcurrencyLimitedAIServiceImpl) update(queued, active int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queued += queued
+	s.active += active
+	labels := map[string]string{"provider": s.provider}
+	s.metrics.SetGauge("ai_runs_queued", labels, float64(s.queued))
+	s.metrics.SetGauge("ai_runs_active", labels, float64(s.active))
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestConcurrencyLimitedAIService_LimitsConcurrentRuns(t *testing.T) {
+	var running, maxRunning atomic.Int32
+	release := make(chan struct{})
+	started := make(chan struct{}, 5)
+	service := &mocks.MockClaudeService{
+		GenerateCodeFunc: func(prompt string, repoDir string) (*models.ClaudeResponse, error) {
+			current := running.Add(1)
+			for {
+				previous := maxRunning.Load()
+				if current <= previous || maxRunning.CompareAndSwap(previous, current) {
+					break
+				}
+			}
+			started <- struct{}{}
+			<-release
+			running.Add(-1)
+			return &models.ClaudeResponse{}, nil
+		},
+	}
+
+	metrics := NewMetrics()
+	limited := NewConcurrencyLimitedAIService("claude", service, 2, metrics, zap.NewNop())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := limited.GenerateCode(context.Background(), "prompt", t.TempDir()); err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		}()
+	}
+
+	// Two runs start, the other three queue
+	<-started
+	<-started
+	waitForMetric(t, metrics, `ai_runs_queued{provider="claude"} 3`)
+	select {
+	case <-started:
+		t.Fatal("Expected only two runs to start")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	wg.Wait()
+
+	if maxRunning.Load() != 2 {
+		t.Errorf("Expected at most 2 concurrent runs, got %d", maxRunning.Load())
+	}
+	for _, expected := range []string{
+		`ai_runs_queued_total{provider="claude"} 3`,
+		`ai_runs_queued{provider="claude"} 0`,
+		`ai_runs_active{provider="claude"} 0`,
+	} {
+		waitForMetric(t, metrics, expected)
+	}
+}
+
+// waitForMetric waits until the metrics contain the expected sample
+func waitForMetric(t *testing.T, metrics Metrics, expected string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var out strings.Builder
+		if err := metrics.WritePrometheus(&out); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(out.String(), expected) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %s in metrics, got:\n%s", expected, out.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestConcurrencyLimitedAIService_QueuedRunCancelled(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	service := &mocks.MockClaudeService{
+		GenerateCodeFunc: func(prompt string, repoDir string) (*models.ClaudeResponse, error) {
+			started <- struct{}{}
+			<-release
+			return &models.ClaudeResponse{}, nil
+		},
+	}
+
+	metrics := NewMetrics()
+	limited := NewConcurrencyLimitedAIService("claude", service, 1, metrics, zap.NewNop())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := limited.GenerateCode(context.Background(), "prompt", t.TempDir()); err != nil {
+			t.Errorf("Expected no error but got: %v", err)
+		}
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := limited.GenerateCode(ctx, "prompt", t.TempDir()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the queued run to stop with its context, got: %v", err)
+	}
+	waitForMetric(t, metrics, `ai_runs_queued{provider="claude"} 0`)
+	waitForMetric(t, metrics, `ai_runs_queue_wait_seconds_total{provider="claude"} 0.`)
+
+	close(release)
+	<-done
+	waitForMetric(t, metrics, `ai_runs_active{provider="claude"} 0`)
+}
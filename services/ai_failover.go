@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// aiUnavailableMarkers are error fragments reported by the AI CLIs when the provider can't serve
+// requests at all, as opposed to failing on the task itself
+var aiUnavailableMarkers = []string{
+	"quota",
+	"rate limit",
+	"rate_limit",
+	"usage limit",
+	"credit balance",
+	"resource_exhausted",
+	"resource exhausted",
+	"overloaded",
+}
+
+// AIFailoverResponse wraps the response of the fallback provider when the primary provider was unavailable
+type AIFailoverResponse struct {
+	Provider       string      // Provider that produced the response
+	FailedProvider string      // Provider that was tried first
+	Reason         string      // Error returned by the failed provider
+	Response       interface{} // Response of the fallback provider
+}
+
+// FailoverAIServiceImpl implements AIService by trying a primary provider and falling back to a
+// secondary one when the primary is unavailable (missing CLI, exhausted quota, rate limiting)
+type FailoverAIServiceImpl struct {
+	primary       AIService
+	primaryName   string
+	secondary     AIService
+	secondaryName string
+	metrics       Metrics
+	logger        *zap.Logger
+}
+
+// NewFailoverAIService creates an AIService that fails over from primary to secondary
+func NewFailoverAIService(primaryName string, primary AIService, secondaryName string, secondary AIService, metrics Metrics, logger *zap.Logger) AIService {
+	return &FailoverAIServiceImpl{
+		primary:       primary,
+		primaryName:   primaryName,
+		secondary:     secondary,
+		secondaryName: secondaryName,
+		metrics:       metrics,
+		logger:        logger,
+	}
+}
+
+// GenerateCode generates code with the primary provider, or with the secondary one if the primary is unavailable.
+// Responses of the secondary provider are wrapped in an AIFailoverResponse.
+func (s *FailoverAIServiceImpl) GenerateCode(ctx context.Context, prompt string, repoDir string) (interface{}, error) {
+	response, err := s.primary.GenerateCode(ctx, prompt, repoDir)
+	if err == nil || !isAIProviderUnavailable(err) {
+		return response, err
+	}
+
+	s.recordFailover("generate_code", repoDir, err)
+	fallbackResponse, fallbackErr := s.secondary.GenerateCode(ctx, prompt, repoDir)
+	if fallbackErr != nil {
+		return nil, fmt.Errorf("%s is unavailable (%v) and fallback to %s failed: %w", s.primaryName, err, s.secondaryName, fallbackErr)
+	}
+
+	return &AIFailoverResponse{
+		Provider:       s.secondaryName,
+		FailedProvider: s.primaryName,
+		Reason:         err.Error(),
+		Response:       fallbackResponse,
+	}, nil
+}
+
+// GenerateDocumentation generates documentation with the primary provider, or with the secondary one if the primary is unavailable
+func (s *FailoverAIServiceImpl) GenerateDocumentation(ctx context.Context, repoDir string) error {
+	err := s.primary.GenerateDocumentation(ctx, repoDir)
+	if err == nil || !isAIProviderUnavailable(err) {
+		return err
+	}
+
+	s.recordFailover("generate_documentation", repoDir, err)
+	if fallbackErr := s.secondary.GenerateDocumentation(ctx, repoDir); fallbackErr != nil {
+		return fmt.Errorf("%s is unavailable (%v) and fallback to %s failed: %w", s.primaryName, err, s.secondaryName, fallbackErr)
+	}
+	return nil
+}
+
+// recordFailover logs a failover and counts it in the metrics
+func (s *FailoverAIServiceImpl) recordFailover(operation, repoDir string, err error) {
+	s.logger.Warn("AI provider unavailable, failing over",
+		zap.String("from", s.primaryName),
+		zap.String("to", s.secondaryName),
+		zap.String("operation", operation),
+		zap.String("repo_dir", repoDir),
+		zap.Error(err))
+	s.metrics.IncCounter("ai_provider_failovers_total", map[string]string{
+		"from":      s.primaryName,
+		"to":        s.secondaryName,
+		"operation": operation,
+	})
+}
+
+// isAIProviderUnavailable reports whether an AI error means the provider can't be used right now
+func isAIProviderUnavailable(err error) bool {
+	// The CLI isn't installed or cli_path points nowhere
+	if errors.Is(err, exec.ErrNotFound) || errors.Is(err, fs.ErrNotExist) {
+		return true
+	}
+	message := strings.ToLower(err.Error())
+	for _, marker := range aiUnavailableMarkers {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// aiFailoverNote describes a provider failover for a PR body, or returns an empty string if none happened
+func aiFailoverNote(response interface{}) string {
+	failover, ok := response.(*AIFailoverResponse)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("\n\n**Note:** %s was unavailable, so this change was generated by %s.", failover.FailedProvider, failover.Provider)
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestFailoverAIService_GenerateCode(t *testing.T) {
+	tests := []struct {
+		name         string
+		primaryErr   error
+		wantFailover bool
+		wantErr      bool
+	}{
+		{
+			name:         "primary succeeds",
+			primaryErr:   nil,
+			wantFailover: false,
+		},
+		{
+			name:         "primary CLI missing",
+			primaryErr:   fmt.Errorf("failed to start Claude CLI: %w", exec.ErrNotFound),
+			wantFailover: true,
+		},
+		{
+			name:         "primary quota exhausted",
+			primaryErr:   errors.New("gemini CLI failed: exit status 1, stderr: RESOURCE_EXHAUSTED: Quota exceeded"),
+			wantFailover: true,
+		},
+		{
+			name:         "primary fails on the task",
+			primaryErr:   errors.New("claude CLI timed out after 300 seconds"),
+			wantFailover: false,
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := &mocks.MockClaudeService{
+				GenerateCodeFunc: func(prompt string, repoDir string) (*models.ClaudeResponse, error) {
+					if tt.primaryErr != nil {
+						return nil, tt.primaryErr
+					}
+					return &models.ClaudeResponse{Result: "primary"}, nil
+				},
+			}
+			var secondaryCalled bool
+			secondary := &mocks.MockClaudeService{
+				GenerateCodeFunc: func(prompt string, repoDir string) (*models.ClaudeResponse, error) {
+					secondaryCalled = true
+					return &models.ClaudeResponse{Result: "secondary"}, nil
+				},
+			}
+			metrics := NewMetrics()
+			service := NewFailoverAIService("claude", primary, "gemini", secondary, metrics, zap.NewNop())
+
+			response, err := service.GenerateCode(context.Background(), "prompt", t.TempDir())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GenerateCode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if secondaryCalled != tt.wantFailover {
+				t.Errorf("Expected secondary called = %v, got %v", tt.wantFailover, secondaryCalled)
+			}
+
+			note := aiFailoverNote(response)
+			var output bytes.Buffer
+			if err := metrics.WritePrometheus(&output); err != nil {
+				t.Fatalf("Failed to write metrics: %v", err)
+			}
+			if tt.wantFailover {
+				if !strings.Contains(note, "claude was unavailable") || !strings.Contains(note, "gemini") {
+					t.Errorf("Expected failover note, got '%s'", note)
+				}
+				if !strings.Contains(output.String(), `ai_provider_failovers_total{from="claude",operation="generate_code",to="gemini"} 1`) {
+					t.Errorf("Expected failover to be counted, got:\n%s", output.String())
+				}
+			} else if note != "" || output.Len() != 0 {
+				t.Errorf("Expected no failover to be recorded, got note '%s' and metrics:\n%s", note, output.String())
+			}
+		})
+	}
+}
+
+func TestFailoverAIService_BothProvidersUnavailable(t *testing.T) {
+	unavailable := &mocks.MockClaudeService{
+		GenerateCodeFunc: func(prompt string, repoDir string) (*models.ClaudeResponse, error) {
+			return nil, errors.New("claude CLI returned an error: Credit balance is too low")
+		},
+	}
+	service := NewFailoverAIService("claude", unavailable, "gemini", unavailable, NewMetrics(), zap.NewNop())
+
+	if _, err := service.GenerateCode(context.Background(), "prompt", t.TempDir()); err == nil || !strings.Contains(err.Error(), "fallback to gemini failed") {
+		t.Errorf("Expected fallback failure error, got: %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+)
+
+// sandboxWorkspace is where the checkout is mounted in sandbox containers
+const sandboxWorkspace = "/workspace"
+
+// aiCommand creates the command running an AI CLI in the checkout, on the host or in a Docker container per
+// sandbox.backend. env holds the variables the CLI needs on top of the application's environment. Without a
+// checkout, nothing is mounted.
+func aiCommand(ctx context.Context, config *models.Config, repoDir, cliPath string, env []string, args ...string) *exec.Cmd {
+	if config.Sandbox.Backend != models.SandboxBackendDocker {
+		cmd := exec.CommandContext(ctx, cliPath, args...)
+		cmd.Dir = repoDir
+		cmd.Env = append(os.Environ(), env...)
+		return cmd
+	}
+
+	name := sandboxContainerName(repoDir)
+	dockerArgs := append(dockerRunArgs(config.Sandbox.Docker, name, repoDir, env), config.Sandbox.Docker.Image, filepath.Base(cliPath))
+	cmd := exec.CommandContext(ctx, config.Sandbox.Docker.Binary, append(dockerArgs, args...)...)
+	// Values are read from the environment of the docker CLI, so secrets never show up in its arguments
+	cmd.Env = append(os.Environ(), env...)
+	// Killing the docker CLI leaves the container running, so a timeout or shutdown removes the container itself
+	cmd.Cancel = func() error {
+		_ = exec.Command(config.Sandbox.Docker.Binary, "rm", "--force", name).Run()
+		return cmd.Process.Kill()
+	}
+	return cmd
+}
+
+// dockerRunArgs returns the arguments of docker run for a sandbox container with the checkout mounted, up to the
+// image
+func dockerRunArgs(docker models.DockerSandboxConfig, name, repoDir string, env []string) []string {
+	args := []string{
+		"run", "--rm", "--init", "--interactive",
+		"--name", name,
+		"--network", docker.Network,
+		"--cpus", docker.CPUs,
+		"--memory", docker.Memory,
+		"--pids-limit", strconv.Itoa(docker.PIDs),
+		"--cap-drop", "ALL",
+		"--security-opt", "no-new-privileges",
+		// Files the CLI writes in the checkout stay owned by the application's user
+		"--user", fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid()),
+		"--tmpfs", "/tmp",
+		"--env", "HOME=/tmp",
+	}
+	if repoDir != "" {
+		args = append(args, "--volume", repoDir+":"+sandboxWorkspace, "--workdir", sandboxWorkspace)
+	}
+	if docker.Proxy != "" {
+		for _, name := range []string{"HTTP_PROXY", "HTTPS_PROXY", "http_proxy", "https_proxy"} {
+			args = append(args, "--env", name+"="+docker.Proxy)
+		}
+	}
+	for _, variable := range env {
+		name, _, _ := strings.Cut(variable, "=")
+		args = append(args, "--env", name)
+	}
+	for _, name := range docker.Env {
+		args = append(args, "--env", name)
+	}
+	for _, volume := range docker.Volumes {
+		args = append(args, "--volume", volume)
+	}
+	return args
+}
+
+// sandboxContainerName returns a unique name of the container of a run in the checkout, starting with its ticket
+func sandboxContainerName(repoDir string) string {
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	prefix := "ai-solver"
+	if repoDir != "" {
+		prefix += "-" + strings.Map(func(r rune) rune {
+			if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '_' || r == '.' {
+				return r
+			}
+			return '-'
+		}, strings.ToLower(aiLogName(repoDir)))
+	}
+	return prefix + "-" + hex.EncodeToString(suffix)
+}
+
+// aiCLIVersion returns what an AI CLI reports with `--version` where it runs, on the host or in its sandbox image
+func aiCLIVersion(ctx context.Context, config *models.Config, cliPath string) (string, error) {
+	if config.Sandbox.Backend != models.SandboxBackendDocker {
+		return cliVersion(ctx, cliPath)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cliVersionTimeout)
+	defer cancel()
+	output, err := aiCommand(ctx, config, "", cliPath, nil, "--version").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to run %s --version in %s: %w: %s", filepath.Base(cliPath), config.Sandbox.Docker.Image, err, strings.TrimSpace(string(output)))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+)
+
+func newTestSandboxConfig() *models.Config {
+	config := &models.Config{}
+	config.Sandbox.Backend = models.SandboxBackendDocker
+	config.Sandbox.Docker = models.DockerSandboxConfig{
+		Image:   "registry.example.com/ai-clis:1",
+		Network: "none",
+		CPUs:    "2",
+		Memory:  "4g",
+		PIDs:    512,
+		Env:     []string{"ANTHROPIC_API_KEY"},
+		Volumes: []string{"/etc/ai:/etc/ai:ro"},
+		Binary:  "docker",
+	}
+	return config
+}
+
+func TestAICommand_Host(t *testing.T) {
+	config := &models.Config{}
+	config.Sandbox.Backend = models.SandboxBackendHost
+
+	cmd := aiCommand(context.Background(), config, "/work/PROJ-1", "/usr/bin/claude", []string{"API_KEY=secret"}, "-p", "fix")
+	if cmd.Dir != "/work/PROJ-1" {
+		t.Errorf("Dir = %q, want the checkout", cmd.Dir)
+	}
+	if !slices.Equal(cmd.Args, []string{"/usr/bin/claude", "-p", "fix"}) {
+		t.Errorf("Args = %v, want the CLI itself", cmd.Args)
+	}
+	if !slices.Contains(cmd.Env, "API_KEY=secret") {
+		t.Error("Expected the CLI's environment to be added")
+	}
+}
+
+func TestAICommand_Docker(t *testing.T) {
+	config := newTestSandboxConfig()
+
+	cmd := aiCommand(context.Background(), config, "/work/PROJ-1", "/opt/cli-cache/claude", []string{"GEMINI_API_KEY=secret"}, "-p", "fix")
+	args := strings.Join(cmd.Args, " ")
+	for _, want := range []string{
+		"docker run --rm --init",
+		"--network none",
+		"--cpus 2",
+		"--memory 4g",
+		"--pids-limit 512",
+		"--cap-drop ALL",
+		"--volume /work/PROJ-1:/workspace --workdir /workspace",
+		"--env GEMINI_API_KEY ",
+		"--env ANTHROPIC_API_KEY ",
+		"--volume /etc/ai:/etc/ai:ro registry.example.com/ai-clis:1 claude -p fix",
+	} {
+		if !strings.Contains(args, want) {
+			t.Errorf("Args = %s, want %q", args, want)
+		}
+	}
+	if strings.Contains(args, "secret") {
+		t.Error("Expected secrets to be passed through the environment, not the arguments")
+	}
+	if !slices.Contains(cmd.Env, "GEMINI_API_KEY=secret") {
+		t.Error("Expected the CLI's environment to be passed to docker")
+	}
+	if cmd.Dir != "" {
+		t.Errorf("Dir = %q, want docker to run in the working directory", cmd.Dir)
+	}
+	if cmd.Cancel == nil {
+		t.Error("Expected canceling to remove the container")
+	}
+}
+
+func TestDockerRunArgs_Proxy(t *testing.T) {
+	docker := newTestSandboxConfig().Sandbox.Docker
+	docker.Network = "egress"
+	docker.Proxy = "http://proxy:3128"
+
+	args := strings.Join(dockerRunArgs(docker, "ai-solver-test", "", nil), " ")
+	if !strings.Contains(args, "--network egress") || !strings.Contains(args, "--env HTTPS_PROXY=http://proxy:3128") {
+		t.Errorf("Args = %s, want the network and proxy", args)
+	}
+	if strings.Contains(args, "/workspace") {
+		t.Errorf("Args = %s, want no mount without a checkout", args)
+	}
+}
+
+func TestSandboxContainerName(t *testing.T) {
+	first, second := sandboxContainerName("/work/PROJ-1"), sandboxContainerName("/work/PROJ-1")
+	if first == second {
+		t.Errorf("Expected unique names, got %s twice", first)
+	}
+	if !strings.HasPrefix(first, "ai-solver-proj-1-") {
+		t.Errorf("sandboxContainerName() = %s, want it to start with the ticket", first)
+	}
+}
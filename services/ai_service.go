@@ -1,11 +1,20 @@
 package services
 
+import (
+	"context"
+	"fmt"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
 // AIService defines the unified interface for AI services
 type AIService interface {
 	// GenerateCode generates code using the AI service
-	GenerateCode(prompt string, repoDir string) (interface{}, error)
+	GenerateCode(ctx context.Context, prompt string, repoDir string) (interface{}, error)
 	// GenerateDocumentation generates documentation file (CLAUDE.md or GEMINI.md) if it doesn't exist
-	GenerateDocumentation(repoDir string) error
+	GenerateDocumentation(ctx context.Context, repoDir string) error
 }
 
 // AIResponse represents a generic AI response that can be used by consumers
@@ -18,3 +27,15 @@ type AIResponse struct {
 	Usage        interface{} `json:"usage"`
 	Message      interface{} `json:"message"`
 }
+
+// NewAIService creates the AI service for a provider name ("claude" or "gemini")
+func NewAIService(provider string, config *models.Config, logger *zap.Logger) (AIService, error) {
+	switch provider {
+	case "claude":
+		return NewClaudeService(config, logger), nil
+	case "gemini":
+		return NewGeminiService(config, logger), nil
+	default:
+		return nil, fmt.Errorf("unsupported AI provider: %s", provider)
+	}
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ticketKeyPrefixPattern matches the ticket key checkouts are named after, e.g. PROJ-123 in PROJ-123-feedback
+var ticketKeyPrefixPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*-\d+`)
+
+// aiLogName returns the name of the log file of the AI runs in a checkout: the ticket key it was made for
+func aiLogName(repoDir string) string {
+	base := filepath.Base(repoDir)
+	if key := ticketKeyPrefixPattern.FindString(base); key != "" {
+		return key
+	}
+	return base
+}
+
+// newAIStreamLogger returns a logger for the raw output of an AI run in the checkout, writing to the rotating log
+// file of its ticket. Without a log directory, or when the file can't be opened, the output goes to the main
+// logger at debug level. The returned function closes the file.
+func newAIStreamLogger(config *models.Config, logger *zap.Logger, repoDir string) (*zap.Logger, func()) {
+	settings := config.AILogs
+	if settings.Dir == "" {
+		return logger, func() {}
+	}
+
+	if err := os.MkdirAll(settings.Dir, 0755); err != nil {
+		logger.Warn("Failed to create AI log directory, logging AI output here", zap.Error(err))
+		return logger, func() {}
+	}
+	removeExpiredAILogs(settings.Dir, settings.RetentionDays, logger)
+
+	file := &rotatingLogFile{
+		path:     filepath.Join(settings.Dir, aiLogName(repoDir)+".log"),
+		maxSize:  int64(settings.MaxSizeMB) * 1024 * 1024,
+		maxFiles: settings.MaxFiles,
+	}
+	if err := file.open(); err != nil {
+		logger.Warn("Failed to open AI log file, logging AI output here", zap.Error(err))
+		return logger, func() {}
+	}
+	logger.Info("Writing AI output to log file", zap.String("file", file.path))
+
+	encoderConfig := zap.NewDevelopmentEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	// The AI's output can echo tokens from the environment or the checkout, so it's redacted like the main log
+	core := zapcore.NewCore(zapcore.NewConsoleEncoder(encoderConfig), NewRedactingWriteSyncer(file, config), zapcore.DebugLevel)
+	streamLogger := zap.New(core).With(zap.String("repo_dir", repoDir))
+	return streamLogger, func() {
+		if err := file.Close(); err != nil {
+			logger.Warn("Failed to close AI log file", zap.String("file", file.path), zap.Error(err))
+		}
+	}
+}
+
+// removeExpiredAILogs deletes log files that weren't written to within the retention period
+func removeExpiredAILogs(dir string, retentionDays int, logger *zap.Logger) {
+	if retentionDays <= 0 {
+		return
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		logger.Warn("Failed to list AI log files", zap.Error(err))
+		return
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -retentionDays)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.Contains(entry.Name(), ".log") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+			logger.Warn("Failed to remove expired AI log file", zap.String("file", entry.Name()), zap.Error(err))
+		}
+	}
+}
+
+// rotatingLogFile is a log file that is moved to name.1, name.2, ... once it reached its maximum size, keeping
+// maxFiles rotated files
+type rotatingLogFile struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// open opens the log file for appending, rotating it first if it is full
+func (f *rotatingLogFile) open() error {
+	if info, err := os.Stat(f.path); err == nil && f.maxSize > 0 && info.Size() >= f.maxSize {
+		f.rotate()
+	}
+	return f.openFile()
+}
+
+// openFile opens the log file for appending
+func (f *rotatingLogFile) openFile() error {
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+	f.file, f.size = file, info.Size()
+	return nil
+}
+
+// rotate shifts the rotated files by one, dropping the oldest, and moves the log file to name.1
+func (f *rotatingLogFile) rotate() {
+	if f.maxFiles <= 0 {
+		os.Remove(f.path)
+		return
+	}
+	os.Remove(fmt.Sprintf("%s.%d", f.path, f.maxFiles))
+	for i := f.maxFiles - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", f.path, i), fmt.Sprintf("%s.%d", f.path, i+1))
+	}
+	os.Rename(f.path, f.path+".1")
+}
+
+// Write appends to the log file, rotating it once it is full
+func (f *rotatingLogFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.file.Close(); err != nil {
+			return 0, fmt.Errorf("failed to close log file: %w", err)
+		}
+		f.rotate()
+		if err := f.openFile(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Sync flushes the log file
+func (f *rotatingLogFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Sync()
+}
+
+// Close closes the log file
+func (f *rotatingLogFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// auditLogMutex serializes writes of all audit logs, which may share a file
+var auditLogMutex sync.Mutex
+
+// maxAuditPayload bounds the request summary recorded for an external action
+const maxAuditPayload = 500
+
+// auditTicketKey is the context key of the ticket external actions are performed for
+type auditTicketKey struct{}
+
+// withAuditTicket returns a context attributing the external actions performed with it to the ticket
+func withAuditTicket(ctx context.Context, ticketKey string) context.Context {
+	return context.WithValue(ctx, auditTicketKey{}, ticketKey)
+}
+
+// auditTicket returns the ticket external actions performed with the context are attributed to, if any
+func auditTicket(ctx context.Context) string {
+	ticketKey, _ := ctx.Value(auditTicketKey{}).(string)
+	return ticketKey
+}
+
+// AuditLog records actions of the bot for later inspection
+type AuditLog interface {
+	// Record appends an entry to the audit log
+	Record(entry models.AuditEntry) error
+}
+
+// AuditLogImpl implements the AuditLog interface as an append-only file of JSON lines
+type AuditLogImpl struct {
+	path string
+}
+
+// NewAuditLog creates a new AuditLog writing to the given file. An empty path discards all entries.
+func NewAuditLog(path string) AuditLog {
+	return &AuditLogImpl{path: path}
+}
+
+// Record appends an entry to the audit log
+func (a *AuditLogImpl) Record(entry models.AuditEntry) error {
+	if a.path == "" {
+		return nil
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+
+	auditLogMutex.Lock()
+	defer auditLogMutex.Unlock()
+
+	file, err := os.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// recordExternalAction records an external action that was attempted in the audit log, with its outcome. A failure to
+// record is logged rather than failing the action, which already happened.
+func recordExternalAction(auditLog AuditLog, logger *zap.Logger, entry models.AuditEntry, started time.Time, err error) {
+	entry.DurationMS = time.Since(started).Milliseconds()
+	entry.Payload = headString(entry.Payload, maxAuditPayload)
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if auditErr := auditLog.Record(entry); auditErr != nil {
+		logger.Warn("Failed to record action in audit log",
+			zap.String("ticket", entry.Ticket),
+			zap.String("action", entry.Action),
+			zap.Error(auditErr))
+	}
+}
+
+// headString returns at most the first n bytes of s, marking truncation
+func headString(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+)
+
+func TestAuditLog_Record(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	auditLog := NewAuditLog(path)
+
+	for _, name := range []string{"lint", "test"} {
+		if err := auditLog.Record(models.AuditEntry{Ticket: "TEST-1", Action: "command", Name: name}); err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read audit log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"name":"lint"`) || !strings.Contains(lines[1], `"name":"test"`) {
+		t.Errorf("Expected one JSON line per entry in order, got:\n%s", data)
+	}
+	if !strings.Contains(lines[0], `"time":"`) {
+		t.Errorf("Expected entries to be timestamped, got %s", lines[0])
+	}
+
+	// Without a file the audit log is disabled
+	if err := NewAuditLog("").Record(models.AuditEntry{Action: "command"}); err != nil {
+		t.Errorf("Expected a disabled audit log to discard entries, got %v", err)
+	}
+}
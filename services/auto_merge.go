@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// processApprovedPR merges the PR once it is approved and its checks are green, and closes the ticket
+func (p *PRReviewProcessorImpl) processApprovedPR(ctx context.Context, ticketKey, owner, repo string, pr *models.GitHubPRDetails) error {
+	if pr.State != "open" || pr.Draft {
+		return nil
+	}
+	if !p.isApproved(pr.Reviews) {
+		p.logger.Debug("PR not approved yet", zap.String("ticket", ticketKey), zap.Int("pr_number", pr.Number))
+		return nil
+	}
+	if pr.Mergeable != nil && !*pr.Mergeable {
+		p.logger.Info("Approved PR is not mergeable", zap.String("ticket", ticketKey), zap.Int("pr_number", pr.Number))
+		return nil
+	}
+
+	failures, pending, err := p.collectCIFailures(ctx, ticketKey, owner, repo, pr.Head.SHA)
+	if err != nil {
+		return err
+	}
+	if pending || len(failures) > 0 {
+		p.logger.Debug("Checks of approved PR not green",
+			zap.String("ticket", ticketKey),
+			zap.Int("pr_number", pr.Number),
+			zap.Bool("pending", pending),
+			zap.Int("failures", len(failures)))
+		return nil
+	}
+
+	method := p.config.GitHub.AutoMerge.Method
+	p.logger.Info("Merging approved pull request",
+		zap.String("ticket", ticketKey),
+		zap.Int("pr_number", pr.Number),
+		zap.String("method", string(method)))
+
+	// Merging at the reviewed head commit keeps commits pushed after the checks ran from being merged unchecked
+	merge, err := p.githubService.MergePullRequest(ctx, owner, repo, pr.Number, pr.Head.SHA, method)
+	if err != nil {
+		return fmt.Errorf("failed to merge pull request: %w", err)
+	}
+	if !merge.Merged {
+		return fmt.Errorf("pull request was not merged: %s", merge.Message)
+	}
+
+	return p.closeMergedTicket(ctx, ticketKey, pr, merge.SHA)
+}
+
+// isApproved reports whether the latest review of at least one reviewer approves the PR and no
+// reviewer's latest review requests changes. Reviews are in submission order; comment-only reviews
+// don't change a reviewer's verdict.
+func (p *PRReviewProcessorImpl) isApproved(reviews []models.GitHubReview) bool {
+	latest := make(map[string]string)
+	for _, review := range reviews {
+		if review.User.Login == p.config.GitHub.BotUsername {
+			continue
+		}
+		switch state := strings.ToUpper(review.State); state {
+		case "APPROVED", "CHANGES_REQUESTED", "DISMISSED":
+			latest[review.User.Login] = state
+		}
+	}
+
+	approved := false
+	for _, state := range latest {
+		switch state {
+		case "CHANGES_REQUESTED":
+			return false
+		case "APPROVED":
+			approved = true
+		}
+	}
+	return approved
+}
+
+// closeMergedTicket moves the ticket of a merged PR, and the tickets batched into it, to the "Done" status with a
+// closing comment, then cleans up after the PR
+func (p *PRReviewProcessorImpl) closeMergedTicket(ctx context.Context, ticketKey string, pr *models.GitHubPRDetails, mergeCommit string) error {
+	if err := p.closeTicket(ctx, ticketKey, pr, mergeCommit); err != nil {
+		return err
+	}
+	for _, batchedKey := range batchedTickets(pr.Body) {
+		if err := p.closeTicket(ctx, batchedKey, pr, mergeCommit); err != nil {
+			p.logger.Error("Failed to close batched ticket",
+				zap.String("ticket", ticketKey),
+				zap.String("batched_ticket", batchedKey),
+				zap.Error(err))
+		}
+	}
+
+	p.cleanupClosedPR(ctx, ticketKey, pr)
+	return nil
+}
+
+// closeTicket moves a ticket resolved by a merged PR to the "Done" status with a closing comment
+func (p *PRReviewProcessorImpl) closeTicket(ctx context.Context, ticketKey string, pr *models.GitHubPRDetails, mergeCommit string) error {
+	comment := fmt.Sprintf("AI-generated pull request %s was merged", pr.HTMLURL)
+	if mergeCommit != "" {
+		comment += fmt.Sprintf(" in commit %s", mergeCommit)
+	}
+	comment += "."
+	if err := upsertJiraComment(ctx, p.jiraService, p.logger, ticketKey, commentKeyMerged, comment); err != nil {
+		p.logger.Error("Failed to add closing comment", zap.String("ticket", ticketKey), zap.Error(err))
+		// Continue closing the ticket even if the comment fails
+	}
+
+	linkPullRequest(ctx, p.jiraService, p.config, p.logger, ticketKey, pr.HTMLURL, pr.Title, true)
+
+	if err := p.jiraService.UpdateTicketStatus(ctx, ticketKey, p.config.Jira.StatusTransitions.Done); err != nil {
+		return fmt.Errorf("failed to update ticket status: %w", err)
+	}
+
+	if err := p.stateMachine.Transition(ticketKey, models.TicketStateDone, nil); err != nil {
+		p.logger.Warn("Failed to change ticket state", zap.String("ticket", ticketKey), zap.Error(err))
+	}
+	if p.config.AI.Precedents.Enabled {
+		if err := p.botChanges.MarkMerged(ticketKey, time.Now()); err != nil {
+			p.logger.Warn("Failed to mark the change merged in the bot change index", zap.String("ticket", ticketKey), zap.Error(err))
+		}
+	}
+
+	p.logger.Info("Closed ticket of merged pull request",
+		zap.String("ticket", ticketKey),
+		zap.Int("pr_number", pr.Number),
+		zap.String("merge_commit", mergeCommit))
+	return nil
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// newAutoMergeConfig returns a bot config with auto-merge enabled
+func newAutoMergeConfig() *models.Config {
+	config := newBotConfig("ai-bot")
+	config.GitHub.AutoMerge.Enabled = true
+	config.GitHub.AutoMerge.Method = models.MergeMethodRebase
+	config.Jira.StatusTransitions.Done = "Closed"
+	return config
+}
+
+// newApprovedPR returns an open PR with the given reviews
+func newApprovedPR(reviews ...models.GitHubReview) *models.GitHubPRDetails {
+	pr := &models.GitHubPRDetails{Number: 7, State: "open", HTMLURL: "https://github.com/example/repo/pull/7", Reviews: reviews}
+	pr.Head.SHA = "abc1234"
+	return pr
+}
+
+// review returns a review of the given user submitted minutes after a fixed time
+func review(login, state string, minutes int) models.GitHubReview {
+	return models.GitHubReview{
+		User:        models.GitHubUser{Login: login},
+		State:       state,
+		SubmittedAt: time.Date(2024, 1, 1, 0, minutes, 0, 0, time.UTC),
+	}
+}
+
+func TestPRReviewProcessor_ProcessApprovedPR(t *testing.T) {
+	config := newAutoMergeConfig()
+	var mergedSHA string
+	var mergeMethod models.MergeMethod
+	githubService := &mocks.MockGitHubService{
+		ListCheckRunsFunc: func(owner, repo, ref string) ([]models.GitHubCheckRun, error) {
+			return []models.GitHubCheckRun{{Name: "test", Status: "completed", Conclusion: "success"}}, nil
+		},
+		MergePullRequestFunc: func(owner, repo string, prNumber int, sha string, method models.MergeMethod) (*models.GitHubMergePRResponse, error) {
+			mergedSHA, mergeMethod = sha, method
+			return &models.GitHubMergePRResponse{SHA: "def5678", Merged: true}, nil
+		},
+	}
+	var status string
+	var comments []string
+	jiraService := &mocks.MockJiraService{
+		UpdateTicketStatusFunc: func(key, newStatus string) error {
+			status = newStatus
+			return nil
+		},
+		AddCommentFunc: func(key, comment string) error {
+			comments = append(comments, comment)
+			return nil
+		},
+	}
+	stateMachine := newTestStateMachine(config)
+	processor := &PRReviewProcessorImpl{
+		jiraService:   jiraService,
+		githubService: githubService,
+		stateMachine:  stateMachine,
+		config:        config,
+		logger:        zap.NewNop(),
+	}
+
+	pr := newApprovedPR(review("alice", "CHANGES_REQUESTED", 1), review("alice", "APPROVED", 2), review("bob", "COMMENTED", 3))
+	if err := processor.processApprovedPR(context.Background(), "TEST-1", "example", "repo", pr); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if mergedSHA != "abc1234" || mergeMethod != models.MergeMethodRebase {
+		t.Errorf("Expected the head commit to be merged with the configured method, got %s with %s", mergedSHA, mergeMethod)
+	}
+	if status != "Closed" {
+		t.Errorf("Expected the ticket to move to the done status, got %q", status)
+	}
+	if len(comments) != 1 || !strings.Contains(comments[0], "https://github.com/example/repo/pull/7 was merged in commit def5678") {
+		t.Errorf("Expected a closing comment with the merge commit, got %v", comments)
+	}
+	if state, _ := stateMachine.State("TEST-1"); state != models.TicketStateDone {
+		t.Errorf("Expected the ticket to be done, got %s", state)
+	}
+}
+
+func TestPRReviewProcessor_ProcessApprovedPR_NotReady(t *testing.T) {
+	tests := []struct {
+		name      string
+		pr        *models.GitHubPRDetails
+		checkRuns []models.GitHubCheckRun
+	}{
+		{
+			name: "not approved",
+			pr:   newApprovedPR(review("alice", "COMMENTED", 1)),
+		},
+		{
+			name: "changes requested by another reviewer",
+			pr:   newApprovedPR(review("alice", "APPROVED", 1), review("bob", "CHANGES_REQUESTED", 2)),
+		},
+		{
+			name:      "checks pending",
+			pr:        newApprovedPR(review("alice", "APPROVED", 1)),
+			checkRuns: []models.GitHubCheckRun{{Name: "test", Status: "in_progress"}},
+		},
+		{
+			name:      "checks failing",
+			pr:        newApprovedPR(review("alice", "APPROVED", 1)),
+			checkRuns: []models.GitHubCheckRun{{Name: "test", Status: "completed", Conclusion: "failure"}},
+		},
+		{
+			name: "draft",
+			pr: func() *models.GitHubPRDetails {
+				pr := newApprovedPR(review("alice", "APPROVED", 1))
+				pr.Draft = true
+				return pr
+			}(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor := &PRReviewProcessorImpl{
+				githubService: &mocks.MockGitHubService{
+					ListCheckRunsFunc: func(owner, repo, ref string) ([]models.GitHubCheckRun, error) {
+						return tt.checkRuns, nil
+					},
+					MergePullRequestFunc: func(owner, repo string, prNumber int, sha string, method models.MergeMethod) (*models.GitHubMergePRResponse, error) {
+						t.Error("Expected the PR not to be merged")
+						return nil, nil
+					},
+				},
+				config: newAutoMergeConfig(),
+				logger: zap.NewNop(),
+			}
+
+			if err := processor.processApprovedPR(context.Background(), "TEST-1", "example", "repo", tt.pr); err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestPRReviewProcessor_MergedPRClosesTicket(t *testing.T) {
+	config := newAutoMergeConfig()
+	config.Jira.GitPullRequestFieldName = "Git Pull Request"
+
+	var status string
+	var links []models.JiraRemoteLink
+	jiraService := &mocks.MockJiraService{
+		GetTicketFunc: func(key string) (*models.JiraTicketResponse, error) {
+			return &models.JiraTicketResponse{Key: key}, nil
+		},
+		AddRemoteLinkFunc: func(key string, link models.JiraRemoteLink) error {
+			links = append(links, link)
+			return nil
+		},
+		GetFieldIDByNameFunc: func(fieldName string) (string, error) {
+			return "customfield_10001", nil
+		},
+		GetTicketWithExpandedFieldsFunc: func(key string) (map[string]interface{}, map[string]string, error) {
+			return map[string]interface{}{"customfield_10001": "https://github.com/example/repo/pull/7"}, nil, nil
+		},
+		UpdateTicketStatusFunc: func(key, newStatus string) error {
+			status = newStatus
+			return nil
+		},
+	}
+	githubService := &mocks.MockGitHubService{
+		GetPRDetailsFunc: func(owner, repo string, prNumber int) (*models.GitHubPRDetails, error) {
+			pr := newApprovedPR()
+			pr.State = "closed"
+			pr.Merged = true
+			pr.MergeCommitSHA = "def5678"
+			// Feedback on a merged PR must not be applied
+			pr.Comments = []models.GitHubPRComment{{Body: "Thanks!", User: models.GitHubUser{Login: "alice"}, CreatedAt: time.Now()}}
+			return pr, nil
+		},
+		CloneRepositoryFunc: func(repoURL, directory string, opts models.CloneOptions) error {
+			t.Error("Expected no checkout for a merged PR")
+			return nil
+		},
+	}
+	processor := NewPRReviewProcessor(jiraService, githubService, &mocks.MockClaudeService{}, newTestStateMachine(config), config, zap.NewNop())
+
+	if err := processor.ProcessPRReviewFeedback(context.Background(), "TEST-1"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if status != "Closed" {
+		t.Errorf("Expected the ticket of the merged PR to be closed, got %q", status)
+	}
+	if len(links) != 1 || links[0].Object.URL != "https://github.com/example/repo/pull/7" || !links[0].Object.Status.Resolved {
+		t.Errorf("Expected the ticket's link to the PR to be marked merged, got %+v", links)
+	}
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// botChangeIndexMutex serializes writes of all bot change indexes, since the ticket and PR review processors share
+// the file
+var botChangeIndexMutex sync.Mutex
+
+// maxPrecedentFiles is the number of files listed per earlier change in the prompt
+const maxPrecedentFiles = 10
+
+// maxPrecedentDescription is the length of the AI's report of an earlier change kept in the index
+const maxPrecedentDescription = 300
+
+// BotChangeIndex records the pull requests the bot opened and which of them were merged, so later tickets can follow
+// the precedents they established
+type BotChangeIndex interface {
+	// Record appends a change to the index
+	Record(change models.BotChange) error
+	// MarkMerged records that the pull request of a ticket was merged
+	MarkMerged(ticket string, mergedAt time.Time) error
+	// Load returns the changes of the index, one per ticket in the order they were opened
+	Load() ([]models.BotChange, error)
+}
+
+// BotChangeIndexImpl implements the BotChangeIndex interface as an append-only file of JSON lines
+type BotChangeIndexImpl struct {
+	path string
+}
+
+// NewBotChangeIndex creates a new BotChangeIndex stored in the given file. An empty path discards all changes.
+func NewBotChangeIndex(path string) BotChangeIndex {
+	return &BotChangeIndexImpl{path: path}
+}
+
+// Record appends a change to the index
+func (i *BotChangeIndexImpl) Record(change models.BotChange) error {
+	if change.OpenedAt.IsZero() {
+		change.OpenedAt = time.Now().UTC()
+	}
+	return i.append(change)
+}
+
+// MarkMerged records that the pull request of a ticket was merged
+func (i *BotChangeIndexImpl) MarkMerged(ticket string, mergedAt time.Time) error {
+	mergedAt = mergedAt.UTC()
+	return i.append(models.BotChange{Ticket: ticket, MergedAt: &mergedAt})
+}
+
+// append writes a line to the index file
+func (i *BotChangeIndexImpl) append(change models.BotChange) error {
+	if i.path == "" {
+		return nil
+	}
+	line, err := json.Marshal(change)
+	if err != nil {
+		return fmt.Errorf("failed to marshal bot change: %w", err)
+	}
+
+	botChangeIndexMutex.Lock()
+	defer botChangeIndexMutex.Unlock()
+
+	file, err := os.OpenFile(i.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open bot change index: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write bot change index: %w", err)
+	}
+	return nil
+}
+
+// Load returns the changes of the index, one per ticket in the order they were opened. A ticket processed again
+// keeps its latest change, and merge lines set the merge time of the ticket's change. A missing file is an empty
+// index.
+func (i *BotChangeIndexImpl) Load() ([]models.BotChange, error) {
+	if i.path == "" {
+		return nil, nil
+	}
+	file, err := os.Open(i.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bot change index: %w", err)
+	}
+	defer file.Close()
+
+	var changes []models.BotChange
+	byTicket := make(map[string]int)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var change models.BotChange
+		if err := json.Unmarshal(scanner.Bytes(), &change); err != nil {
+			return nil, fmt.Errorf("failed to parse bot change index: %w", err)
+		}
+
+		index, known := byTicket[change.Ticket]
+		switch {
+		case change.PRURL == "" && change.MergedAt != nil:
+			if known {
+				changes[index].MergedAt = change.MergedAt
+			}
+		case known:
+			changes[index] = change
+		default:
+			byTicket[change.Ticket] = len(changes)
+			changes = append(changes, change)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read bot change index: %w", err)
+	}
+	return changes, nil
+}
+
+// collectChangedFiles remembers the files of the change before they are committed, for the bot change index
+func (p *TicketProcessorImpl) collectChangedFiles(ctx context.Context, run *TicketRun) {
+	if !p.config.AI.Precedents.Enabled {
+		return
+	}
+	files, err := p.githubService.ChangedFiles(ctx, run.RepoDir)
+	if err != nil {
+		p.logger.Warn("Failed to list the changed files for the bot change index", zap.String("ticket", run.Key), zap.Error(err))
+		return
+	}
+	run.ChangedFiles = files
+}
+
+// recordBotChange adds the ticket's pull request to the bot change index. Failures are only logged, since the index
+// only improves later prompts.
+func (p *TicketProcessorImpl) recordBotChange(run *TicketRun) {
+	if !p.config.AI.Precedents.Enabled || len(run.PRs) == 0 {
+		return
+	}
+	change := models.BotChange{
+		Ticket:      run.Key,
+		Component:   run.Component,
+		Repo:        run.Owner + "/" + run.Repo,
+		Summary:     run.Ticket.Fields.Summary,
+		PRURL:       run.PRs[0].HTMLURL,
+		Files:       run.ChangedFiles,
+		Description: precedentDescription(aiResultText(run.AIResponse)),
+	}
+	if err := p.botChanges.Record(change); err != nil {
+		p.logger.Warn("Failed to record the change in the bot change index", zap.String("ticket", run.Key), zap.Error(err))
+	}
+}
+
+// precedentDescription shortens the AI's report of a change to its first paragraph
+func precedentDescription(result string) string {
+	result = strings.TrimSpace(result)
+	if paragraph, _, found := strings.Cut(result, "\n\n"); found {
+		result = paragraph
+	}
+	result = strings.Join(strings.Fields(result), " ")
+	if len(result) > maxPrecedentDescription {
+		result = result[:maxPrecedentDescription] + "..."
+	}
+	return result
+}
+
+// precedentsPrompt returns the summaries of the bot's earlier merged changes to the ticket's component, or to its
+// repository without a component, most related to the ticket first. Empty if disabled or there are none.
+func (p *TicketProcessorImpl) precedentsPrompt(run *TicketRun) string {
+	settings := p.config.AI.Precedents
+	if !settings.Enabled {
+		return ""
+	}
+	changes, err := p.botChanges.Load()
+	if err != nil {
+		p.logger.Warn("Failed to load the bot change index", zap.String("ticket", run.Key), zap.Error(err))
+		return ""
+	}
+	precedents := relatedPrecedents(changes, run, settings.MaxChanges)
+	if len(precedents) == 0 {
+		return ""
+	}
+
+	var prompt strings.Builder
+	prompt.WriteString("\n\nEarlier changes made the same way to this part of the codebase were reviewed and merged. " +
+		"Follow the naming and patterns they established where they apply:\n")
+	for _, change := range precedents {
+		prompt.WriteString(fmt.Sprintf("- %s (merged %s, %s): %s\n", change.Ticket, change.MergedAt.Format("2006-01-02"), change.PRURL, change.Summary))
+		if len(change.Files) > 0 {
+			files := change.Files
+			more := ""
+			if len(files) > maxPrecedentFiles {
+				more = fmt.Sprintf(" and %d more", len(files)-maxPrecedentFiles)
+				files = files[:maxPrecedentFiles]
+			}
+			prompt.WriteString("  Files: " + strings.Join(files, ", ") + more + "\n")
+		}
+		if change.Description != "" {
+			prompt.WriteString("  Done: " + change.Description + "\n")
+		}
+	}
+	return prompt.String()
+}
+
+// relatedPrecedents selects the merged changes of the ticket's component, or of its repository without a component,
+// sharing the most keywords with the ticket's summary, the most recently merged first among equals
+func relatedPrecedents(changes []models.BotChange, run *TicketRun, maxChanges int) []models.BotChange {
+	keywords := historyKeywords(run.Ticket.Fields.Summary)
+	type candidate struct {
+		change models.BotChange
+		score  int
+	}
+	var candidates []candidate
+	for _, change := range changes {
+		if change.MergedAt == nil || change.Ticket == run.Key {
+			continue
+		}
+		if run.Component != "" && change.Component != run.Component {
+			continue
+		}
+		if run.Component == "" && change.Repo != run.Owner+"/"+run.Repo {
+			continue
+		}
+		text := strings.ToLower(change.Summary + " " + change.Description + " " + strings.Join(change.Files, " "))
+		score := 0
+		for _, keyword := range keywords {
+			if strings.Contains(text, strings.ToLower(keyword)) {
+				score++
+			}
+		}
+		candidates = append(candidates, candidate{change: change, score: score})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].change.MergedAt.After(*candidates[j].change.MergedAt)
+	})
+	if len(candidates) > maxChanges {
+		candidates = candidates[:maxChanges]
+	}
+	precedents := make([]models.BotChange, 0, len(candidates))
+	for _, candidate := range candidates {
+		precedents = append(precedents, candidate.change)
+	}
+	return precedents
+}
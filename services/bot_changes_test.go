@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestBotChangeIndex(t *testing.T) {
+	index := NewBotChangeIndex(filepath.Join(t.TempDir(), "bot-changes.log"))
+
+	if changes, err := index.Load(); err != nil || len(changes) != 0 {
+		t.Fatalf("Expected an empty index before anything is recorded, got %v, %v", changes, err)
+	}
+	for _, change := range []models.BotChange{
+		{Ticket: "TEST-1", Component: "api", Summary: "First attempt", PRURL: "https://github.com/example/repo/pull/1"},
+		{Ticket: "TEST-2", Component: "api", Summary: "Other ticket", PRURL: "https://github.com/example/repo/pull/2"},
+		{Ticket: "TEST-1", Component: "api", Summary: "Second attempt", PRURL: "https://github.com/example/repo/pull/3"},
+	} {
+		if err := index.Record(change); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+	mergedAt := time.Date(2024, 1, 4, 10, 0, 0, 0, time.UTC)
+	if err := index.MarkMerged("TEST-1", mergedAt); err != nil {
+		t.Fatalf("MarkMerged() error = %v", err)
+	}
+	if err := index.MarkMerged("TEST-9", mergedAt); err != nil {
+		t.Fatalf("MarkMerged() error = %v", err)
+	}
+
+	changes, err := index.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("Load() = %+v, want one change per recorded ticket", changes)
+	}
+	if changes[0].Summary != "Second attempt" || changes[0].MergedAt == nil || !changes[0].MergedAt.Equal(mergedAt) {
+		t.Errorf("Expected the latest change of TEST-1 marked merged, got %+v", changes[0])
+	}
+	if changes[0].OpenedAt.IsZero() {
+		t.Error("Expected the opening time to be set")
+	}
+	if changes[1].MergedAt != nil {
+		t.Errorf("Expected TEST-2 not to be merged, got %+v", changes[1])
+	}
+}
+
+func TestRelatedPrecedents(t *testing.T) {
+	day := func(d int) *time.Time {
+		merged := time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC)
+		return &merged
+	}
+	changes := []models.BotChange{
+		{Ticket: "TEST-1", Component: "api", Summary: "Add invoice export", MergedAt: day(1)},
+		{Ticket: "TEST-2", Component: "api", Summary: "Rename the user handler", MergedAt: day(5)},
+		{Ticket: "TEST-3", Component: "api", Summary: "Paginate invoices", Files: []string{"api/invoice.go"}, MergedAt: day(3)},
+		{Ticket: "TEST-4", Component: "web", Summary: "Invoice page", MergedAt: day(6)},
+		{Ticket: "TEST-5", Component: "api", Summary: "Invoice totals", MergedAt: nil},
+		{Ticket: "TEST-6", Component: "api", Summary: "Add invoice totals", MergedAt: day(2)},
+	}
+	ticket := &models.JiraTicketResponse{Key: "TEST-6"}
+	ticket.Fields.Summary = "Invoice totals are rounded"
+	run := &TicketRun{Key: "TEST-6", Ticket: ticket, Component: "api"}
+
+	got := relatedPrecedents(changes, run, 3)
+	var tickets []string
+	for _, change := range got {
+		tickets = append(tickets, change.Ticket)
+	}
+	if want := "TEST-3,TEST-1,TEST-2"; strings.Join(tickets, ",") != want {
+		t.Errorf("relatedPrecedents() = %q, want %s", tickets, want)
+	}
+}
+
+func TestTicketProcessor_PrecedentsPrompt(t *testing.T) {
+	config := &models.Config{}
+	config.AI.Precedents.Enabled = true
+	config.AI.Precedents.IndexFile = filepath.Join(t.TempDir(), "bot-changes.log")
+	config.AI.Precedents.MaxChanges = 3
+	processor := newCheckTestProcessor(t, config, &mocks.MockClaudeService{}, &mocks.MockJiraService{}, &mocks.MockGitHubService{
+		ChangedFilesFunc: func(directory string) ([]string, error) {
+			return []string{"api/invoice.go"}, nil
+		},
+	})
+
+	ticket := &models.JiraTicketResponse{Key: "TEST-1"}
+	ticket.Fields.Summary = "Add invoice export"
+	run := &TicketRun{
+		Key:        "TEST-1",
+		Ticket:     ticket,
+		Component:  "api",
+		Owner:      "example",
+		Repo:       "repo",
+		AIResponse: &models.ClaudeResponse{Result: "Added an ExportInvoices handler.\n\nDetails follow."},
+		PRs:        []*models.GitHubCreatePRResponse{{HTMLURL: "https://github.com/example/repo/pull/1"}},
+	}
+	processor.collectChangedFiles(context.Background(), run)
+	processor.recordBotChange(run)
+
+	next := &TicketRun{Key: "TEST-2", Ticket: &models.JiraTicketResponse{Key: "TEST-2"}, Component: "api"}
+	next.Ticket.Fields.Summary = "Invoice import"
+	if prompt := processor.precedentsPrompt(next); prompt != "" {
+		t.Errorf("Expected unmerged changes not to be precedents, got %q", prompt)
+	}
+
+	reviewProcessor := &PRReviewProcessorImpl{
+		jiraService:  &mocks.MockJiraService{},
+		stateMachine: newTestStateMachine(config),
+		botChanges:   NewBotChangeIndex(config.AI.Precedents.IndexFile),
+		config:       config,
+		logger:       zap.NewNop(),
+	}
+	pr := &models.GitHubPRDetails{Number: 1, HTMLURL: "https://github.com/example/repo/pull/1"}
+	if err := reviewProcessor.closeMergedTicket(context.Background(), "TEST-1", pr, "abc1234"); err != nil {
+		t.Fatalf("closeMergedTicket() error = %v", err)
+	}
+
+	prompt := processor.precedentsPrompt(next)
+	for _, want := range []string{
+		"- TEST-1 (merged " + time.Now().UTC().Format("2006-01-02") + ", https://github.com/example/repo/pull/1): Add invoice export\n",
+		"  Files: api/invoice.go\n",
+		"  Done: Added an ExportInvoices handler.\n",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("Expected the prompt to contain %q, got:\n%s", want, prompt)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// BranchCleanupService defines the interface for the service deleting the bot's stale branches
+type BranchCleanupService interface {
+	// Start starts the periodic cleanup of stale branches
+	Start()
+	// Stop stops the periodic cleanup, waiting for a running sweep to finish. The cleanup can be started again.
+	Stop()
+}
+
+// BranchCleanupServiceImpl implements the BranchCleanupService interface
+type BranchCleanupServiceImpl struct {
+	ctx           context.Context // Bounds the work of the service; canceling it aborts work in progress
+	githubService GitHubService
+	config        *models.Config
+	logger        *zap.Logger
+
+	mu        sync.Mutex
+	stopChan  chan struct{} // Closed to stop the current sweep loop
+	doneChan  chan struct{} // Closed once the current sweep loop returned
+	isRunning bool
+}
+
+// branchCleanupTarget is a repository whose pull requests are opened from branches of the head owner's repository
+type branchCleanupTarget struct {
+	Owner     string // Owner of the repository the pull requests are opened in
+	Repo      string
+	HeadOwner string // Owner of the repository the branches are pushed to: the fork's, or Owner in same-repo mode
+}
+
+// NewBranchCleanupService creates a new BranchCleanupService
+func NewBranchCleanupService(ctx context.Context, githubService GitHubService, config *models.Config, logger *zap.Logger) BranchCleanupService {
+	return &BranchCleanupServiceImpl{
+		ctx:           ctx,
+		githubService: githubService,
+		config:        config,
+		logger:        logger,
+	}
+}
+
+// Start starts the periodic cleanup of stale branches
+func (s *BranchCleanupServiceImpl) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.isRunning {
+		s.logger.Info("Branch cleanup is already running")
+		return
+	}
+
+	s.isRunning = true
+	s.stopChan = make(chan struct{})
+	s.doneChan = make(chan struct{})
+	s.logger.Info("Starting branch cleanup...")
+
+	stopChan, doneChan := s.stopChan, s.doneChan
+	go func() {
+		defer close(doneChan)
+		ticker := time.NewTicker(time.Duration(s.config.BranchCleanup.IntervalSeconds) * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.deleteStaleBranches(s.ctx)
+			case <-stopChan:
+				s.logger.Info("Stopping branch cleanup...")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the periodic cleanup, waiting for a running sweep to finish. The cleanup can be started again.
+func (s *BranchCleanupServiceImpl) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.isRunning {
+		return
+	}
+
+	s.isRunning = false
+	close(s.stopChan)
+	<-s.doneChan
+}
+
+// deleteStaleBranches deletes the branches of every configured repository whose pull requests were opened by the bot
+// and closed more than the configured number of days ago
+func (s *BranchCleanupServiceImpl) deleteStaleBranches(ctx context.Context) {
+	cutoff := time.Now().Add(-time.Duration(s.config.BranchCleanup.AfterDays) * 24 * time.Hour)
+	for _, target := range branchCleanupTargets(s.config) {
+		if ctx.Err() != nil {
+			return
+		}
+		s.cleanupRepository(ctx, target, cutoff)
+	}
+}
+
+// cleanupRepository deletes the stale branches of one repository. Branches without a pull request are kept, since
+// they may belong to a ticket still being processed.
+func (s *BranchCleanupServiceImpl) cleanupRepository(ctx context.Context, target branchCleanupTarget, cutoff time.Time) {
+	branches, err := s.githubService.ListBranches(ctx, target.HeadOwner, target.Repo)
+	if err != nil {
+		s.logger.Error("Failed to list branches",
+			zap.String("owner", target.HeadOwner),
+			zap.String("repo", target.Repo),
+			zap.Error(err))
+		return
+	}
+
+	for _, branch := range branches {
+		if ctx.Err() != nil {
+			return
+		}
+		if branch.Protected || s.isProtected(branch.Name) {
+			continue
+		}
+
+		prs, err := s.githubService.ListPullRequestsByHead(ctx, target.Owner, target.Repo, target.HeadOwner+":"+branch.Name)
+		if err != nil {
+			s.logger.Warn("Failed to list the pull requests of branch",
+				zap.String("owner", target.Owner),
+				zap.String("repo", target.Repo),
+				zap.String("branch", branch.Name),
+				zap.Error(err))
+			continue
+		}
+		if !isStaleBotBranch(prs, s.config.GitHub.BotUsername, cutoff) {
+			continue
+		}
+
+		if err := s.githubService.DeleteBranch(ctx, target.HeadOwner, target.Repo, branch.Name); err != nil {
+			s.logger.Error("Failed to delete stale branch",
+				zap.String("owner", target.HeadOwner),
+				zap.String("repo", target.Repo),
+				zap.String("branch", branch.Name),
+				zap.Error(err))
+			continue
+		}
+		s.logger.Info("Deleted stale branch",
+			zap.String("owner", target.HeadOwner),
+			zap.String("repo", target.Repo),
+			zap.String("branch", branch.Name))
+	}
+}
+
+// isProtected reports whether the branch matches a pattern of the protection list
+func (s *BranchCleanupServiceImpl) isProtected(branch string) bool {
+	for _, pattern := range s.config.BranchCleanup.Protected {
+		if matched, _ := path.Match(pattern, branch); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// isStaleBotBranch reports whether a branch has pull requests and all of them were opened by the bot and closed
+// before the cutoff
+func isStaleBotBranch(prs []models.GitHubPRDetails, botUsername string, cutoff time.Time) bool {
+	if len(prs) == 0 {
+		return false
+	}
+	for _, pr := range prs {
+		if pr.ClosedAt == nil || pr.ClosedAt.After(cutoff) || !strings.EqualFold(pr.User.Login, botUsername) {
+			return false
+		}
+	}
+	return true
+}
+
+// branchCleanupTargets returns the configured repositories with the owner their ticket branches are pushed to, in a
+// stable order. Repositories of components in same-repo mode have their own branches cleaned up.
+func branchCleanupTargets(config *models.Config) []branchCleanupTarget {
+	repos := make(map[string]bool)
+	for component, repoURL := range config.ComponentToRepo {
+		repos[repoURL] = repos[repoURL] || config.IsSameRepo(component)
+	}
+	for _, mapping := range config.RepoMappings {
+		repos[mapping.Repo] = repos[mapping.Repo] || config.GitHub.SameRepo
+	}
+	if config.DefaultRepo != "" {
+		repos[config.DefaultRepo] = repos[config.DefaultRepo] || config.GitHub.SameRepo
+	}
+
+	seen := make(map[branchCleanupTarget]bool)
+	var targets []branchCleanupTarget
+	for repoURL, sameRepo := range repos {
+		owner, repo, err := ExtractRepoInfo(repoURL)
+		if err != nil {
+			continue
+		}
+		target := branchCleanupTarget{Owner: owner, Repo: repo, HeadOwner: config.ForkOwner()}
+		if sameRepo {
+			target.HeadOwner = owner
+		}
+		if !seen[target] {
+			seen[target] = true
+			targets = append(targets, target)
+		}
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].Owner != targets[j].Owner {
+			return targets[i].Owner < targets[j].Owner
+		}
+		return targets[i].Repo < targets[j].Repo
+	})
+	return targets
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestBranchCleanupService_DeleteStaleBranches(t *testing.T) {
+	config := &models.Config{}
+	config.GitHub.BotUsername = "ai-bot"
+	config.ComponentToRepo = map[string]string{"frontend": "https://github.com/example/frontend.git"}
+	config.BranchCleanup.AfterDays = 14
+	config.BranchCleanup.Protected = []string{"keep/*"}
+
+	closed := func(days int) *time.Time {
+		at := time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+		return &at
+	}
+	prsByHead := map[string][]models.GitHubPRDetails{
+		// Merged a month ago
+		"ai-bot:TEST-1": {{Number: 1, ClosedAt: closed(30), User: models.GitHubUser{Login: "ai-bot"}}},
+		// Closed yesterday
+		"ai-bot:TEST-2": {{Number: 2, ClosedAt: closed(1), User: models.GitHubUser{Login: "ai-bot"}}},
+		// Reopened as a new PR that is still open
+		"ai-bot:TEST-3": {{Number: 4, User: models.GitHubUser{Login: "ai-bot"}}, {Number: 3, ClosedAt: closed(30), User: models.GitHubUser{Login: "ai-bot"}}},
+		// Opened by a human from the bot's fork
+		"ai-bot:TEST-4":      {{Number: 5, ClosedAt: closed(30), User: models.GitHubUser{Login: "alice"}}},
+		"ai-bot:keep/TEST-5": {{Number: 6, ClosedAt: closed(30), User: models.GitHubUser{Login: "ai-bot"}}},
+	}
+
+	var deleted []string
+	githubService := &mocks.MockGitHubService{
+		ListBranchesFunc: func(owner, repo string) ([]models.GitHubBranch, error) {
+			if owner != "ai-bot" || repo != "frontend" {
+				t.Errorf("Expected the branches of the fork, got %s/%s", owner, repo)
+			}
+			return []models.GitHubBranch{
+				{Name: "main", Protected: true}, {Name: "TEST-1"}, {Name: "TEST-2"}, {Name: "TEST-3"},
+				{Name: "TEST-4"}, {Name: "keep/TEST-5"}, {Name: "TEST-6"},
+			}, nil
+		},
+		ListPullRequestsByHeadFunc: func(owner, repo, head string) ([]models.GitHubPRDetails, error) {
+			if owner != "example" || repo != "frontend" {
+				t.Errorf("Expected the pull requests of the upstream repository, got %s/%s", owner, repo)
+			}
+			return prsByHead[head], nil
+		},
+		DeleteBranchFunc: func(owner, repo, branch string) error {
+			deleted = append(deleted, owner+"/"+repo+":"+branch)
+			return nil
+		},
+	}
+
+	service := NewBranchCleanupService(context.Background(), githubService, config, zap.NewNop()).(*BranchCleanupServiceImpl)
+	service.deleteStaleBranches(context.Background())
+
+	if want := []string{"ai-bot/frontend:TEST-1"}; !reflect.DeepEqual(deleted, want) {
+		t.Errorf("Expected only the branch of the PR closed long ago to be deleted, got %v", deleted)
+	}
+}
+
+func TestBranchCleanupTargets(t *testing.T) {
+	config := &models.Config{}
+	config.GitHub.BotUsername = "ai-bot"
+	config.GitHub.ForkOrganization = "ai-forks"
+	sameRepo := true
+	config.Components = map[string]models.ComponentConfig{"backend": {SameRepo: &sameRepo}}
+	config.ComponentToRepo = map[string]string{
+		"frontend": "https://github.com/example/frontend.git",
+		"web":      "https://github.com/example/frontend.git",
+		"backend":  "https://github.com/example/backend.git",
+	}
+	config.DefaultRepo = "https://github.com/example/docs.git"
+
+	want := []branchCleanupTarget{
+		{Owner: "example", Repo: "backend", HeadOwner: "example"},
+		{Owner: "example", Repo: "docs", HeadOwner: "ai-forks"},
+		{Owner: "example", Repo: "frontend", HeadOwner: "ai-forks"},
+	}
+	if got := branchCleanupTargets(config); !reflect.DeepEqual(got, want) {
+		t.Errorf("branchCleanupTargets() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDeleteBranch(t *testing.T) {
+	statusCode := http.StatusNoContent
+	mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		if req.Method != "DELETE" || req.URL.Path != "/repos/ai-bot/frontend/git/refs/heads/ai/TEST-1" {
+			t.Errorf("Unexpected request %s %s", req.Method, req.URL.Path)
+		}
+		return &http.Response{StatusCode: statusCode, Body: io.NopCloser(bytes.NewReader(nil))}, nil
+	})
+	config := &models.Config{}
+	config.GitHub.PersonalAccessToken = "test-token"
+	service := &GitHubServiceImpl{config: config, client: mockClient, executor: execCommand, logger: zap.NewNop()}
+
+	if err := service.DeleteBranch(context.Background(), "ai-bot", "frontend", "ai/TEST-1"); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	statusCode = http.StatusUnprocessableEntity
+	if err := service.DeleteBranch(context.Background(), "ai-bot", "frontend", "ai/TEST-1"); err != nil {
+		t.Errorf("Expected a branch that no longer exists not to be an error, got: %v", err)
+	}
+	statusCode = http.StatusForbidden
+	if err := service.DeleteBranch(context.Background(), "ai-bot", "frontend", "ai/TEST-1"); err == nil {
+		t.Error("Expected an error when the branch can't be deleted")
+	}
+}
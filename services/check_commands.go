@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// maxCheckOutput bounds how much of a check's output is shown in the pull request
+const maxCheckOutput = 3000
+
+// CheckResult is the outcome of a check command that passed before the ticket's changes were committed
+type CheckResult struct {
+	Name        string // build, lint or tests
+	Command     string
+	FixAttempts int    // AI runs it took to make the check pass
+	Output      string // End of the output of the passing run
+}
+
+// checkCommand is a check the AI's changes must pass before they are committed
+type checkCommand struct {
+	name    string
+	problem string // What a failure means, completing "The changes you made to this repository ..."
+	config  models.CheckCommandConfig
+}
+
+// runChecks applies the component's formatters and then runs its build, lint and test commands in the checkout. While
+// a check fails, the AI is asked to fix it, up to the configured number of attempts, after which the ticket fails
+// rather than committing changes that don't build, lint or pass the tests.
+func (p *TicketProcessorImpl) runChecks(ctx context.Context, run *TicketRun) error {
+	if err := p.runFormatters(ctx, run); err != nil {
+		return err
+	}
+
+	checks := []checkCommand{
+		{name: "build", problem: "break the build", config: p.checkConfig(run, "build")},
+		{name: "lint", problem: "have lint errors", config: p.checkConfig(run, "lint")},
+		{name: "tests", problem: "make the tests fail", config: p.checkConfig(run, "test")},
+	}
+	for _, check := range checks {
+		if check.config.Command == "" {
+			continue
+		}
+		if err := p.runCheck(ctx, run, check); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runCheck runs a check command until it passes or the AI ran out of fix attempts, recording the result on the run
+func (p *TicketProcessorImpl) runCheck(ctx context.Context, run *TicketRun, check checkCommand) error {
+	for attempt := 0; ; attempt++ {
+		env, err := p.checkEnv(ctx, run)
+		if err != nil {
+			return err
+		}
+		output, checkErr := p.commandRunner.RunCommandOutput(ctx, check.name, check.config.Command, check.config.TimeoutSeconds, env)
+		if checkErr == nil {
+			if attempt > 0 {
+				p.logger.Info("AI fixed the check",
+					zap.String("ticket", run.Key),
+					zap.String("check", check.name),
+					zap.Int("attempts", attempt))
+			}
+			run.Checks = append(run.Checks, CheckResult{
+				Name:        check.name,
+				Command:     check.config.Command,
+				FixAttempts: attempt,
+				Output:      tailString(strings.TrimSpace(output), maxCheckOutput),
+			})
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if attempt == check.config.MaxFixAttempts {
+			p.logger.Error("Check failed",
+				zap.String("ticket", run.Key),
+				zap.String("check", check.name),
+				zap.Int("fix_attempts", attempt),
+				zap.Error(checkErr))
+			p.handleFailure(ctx, run.Key, fmt.Sprintf("Check %s failed after %d fix attempts: %v", check.name, attempt, checkErr))
+			return checkErr
+		}
+
+		p.logger.Warn("Check failed, asking the AI to fix it",
+			zap.String("ticket", run.Key),
+			zap.String("check", check.name),
+			zap.Int("attempt", attempt+1),
+			zap.Int("max_fix_attempts", check.config.MaxFixAttempts),
+			zap.Error(checkErr))
+		prompt := checkFixPrompt(check, tailString(output, maxCommandErrorOutput))
+		started := time.Now()
+		response, err := p.aiService.GenerateCode(ctx, prompt, run.RepoDir)
+		if err != nil {
+			p.logger.Error("Failed to fix the check",
+				zap.String("ticket", run.Key),
+				zap.String("check", check.name),
+				zap.String("repo_dir", run.RepoDir),
+				zap.Error(err))
+			p.handleFailure(ctx, run.Key, fmt.Sprintf("Failed to fix the %s: %v", check.name, err))
+			return err
+		}
+		p.recordUsage(run, prompt, time.Since(started), response)
+		if err := p.revertProtectedPaths(ctx, run); err != nil {
+			return err
+		}
+
+		// The fix is formatted like the original changes
+		if err := p.runFormatters(ctx, run); err != nil {
+			return err
+		}
+	}
+}
+
+// runFormatters applies the component's formatters to the AI's changes. A failing formatter fails the ticket.
+func (p *TicketProcessorImpl) runFormatters(ctx context.Context, run *TicketRun) error {
+	formatters := p.config.GetFormatters(run.Component)
+	if len(formatters) == 0 {
+		return nil
+	}
+	env, err := p.checkEnv(ctx, run)
+	if err != nil {
+		return err
+	}
+	for i, formatter := range formatters {
+		name := formatter.Name
+		if name == "" {
+			name = fmt.Sprintf("format[%d]", i+1)
+		}
+		if err := p.commandRunner.RunCommand(ctx, name, formatter.Command, formatter.TimeoutSeconds, env); err != nil {
+			p.logger.Error("Formatter failed",
+				zap.String("ticket", run.Key),
+				zap.String("formatter", name),
+				zap.Error(err))
+			p.handleFailure(ctx, run.Key, fmt.Sprintf("Formatter %v", err))
+			return err
+		}
+	}
+	return nil
+}
+
+// checkEnv returns the environment of formatters and checks, listing the files the AI changed
+func (p *TicketProcessorImpl) checkEnv(ctx context.Context, run *TicketRun) (CommandEnv, error) {
+	env := p.commandEnv(run)
+	files, err := p.githubService.ChangedFiles(ctx, run.RepoDir)
+	if err != nil {
+		p.logger.Error("Failed to list changed files",
+			zap.String("ticket", run.Key),
+			zap.String("repo_dir", run.RepoDir),
+			zap.Error(err))
+		p.handleFailure(ctx, run.Key, fmt.Sprintf("Failed to list changed files: %v", err))
+		return env, err
+	}
+	env.ChangedFiles = files
+	return env, nil
+}
+
+// checkFixPrompt generates a prompt for the AI service to fix the check its changes broke
+func checkFixPrompt(check checkCommand, output string) string {
+	var prompt strings.Builder
+
+	prompt.WriteString(fmt.Sprintf("The changes you made to this repository %s. Fix them so the command below passes.\n\n", check.problem))
+	prompt.WriteString("## Command\n")
+	prompt.WriteString(fmt.Sprintf("`%s`\n\n", check.config.Command))
+	prompt.WriteString("## Output\n")
+	prompt.WriteString(fmt.Sprintf("```\n%s\n```\n\n", output))
+
+	prompt.WriteString("## Instructions\n")
+	prompt.WriteString("1. Fix the failures in the output while keeping the intent of your changes\n")
+	prompt.WriteString("2. Do not disable, skip or delete code, checks or tests to make the command pass\n")
+	prompt.WriteString("3. Do not commit; the changes are committed for you once the command passes\n")
+
+	return prompt.String()
+}
+
+// checksNote returns the section of the pull request body showing the checks the changes passed
+func checksNote(config *models.Config, checks []CheckResult) string {
+	if len(checks) == 0 {
+		return ""
+	}
+
+	var note strings.Builder
+	note.WriteString("\n\n**Checks:**")
+	for _, check := range checks {
+		result := "passed"
+		switch check.FixAttempts {
+		case 0:
+		case 1:
+			result += " after 1 AI fix"
+		default:
+			result += fmt.Sprintf(" after %d AI fixes", check.FixAttempts)
+		}
+		note.WriteString(fmt.Sprintf("\n- %s (`%s`): %s", check.Name, check.Command, result))
+	}
+	for _, check := range checks {
+		if check.Output == "" {
+			continue
+		}
+		note.WriteString(fmt.Sprintf("\n\n<details><summary>Output of %s</summary>\n\n```\n%s\n```\n</details>",
+			check.Name, RedactSecrets(config, check.Output)))
+	}
+	return note.String()
+}
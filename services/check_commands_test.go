@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func newCheckTestProcessor(t *testing.T, config *models.Config, aiService *mocks.MockClaudeService, jiraService *mocks.MockJiraService, githubService *mocks.MockGitHubService) *TicketProcessorImpl {
+	config.TempDir = t.TempDir()
+	processor := NewTicketProcessor(jiraService, githubService, aiService, newTestStateMachine(config), config, zap.NewNop())
+	return processor.(*TicketProcessorImpl)
+}
+
+func TestTicketProcessor_RunChecks_FixedByAI(t *testing.T) {
+	repoDir := t.TempDir()
+	config := &models.Config{}
+	config.Build = models.CheckCommandConfig{Command: "true", MaxFixAttempts: 2}
+	config.Test = models.CheckCommandConfig{Command: "test -f fixed || { echo 'FAIL: TestCount' >&2; exit 1; }", MaxFixAttempts: 2}
+
+	var prompts []string
+	aiService := &mocks.MockClaudeService{
+		GenerateCodeFunc: func(prompt, dir string) (*models.ClaudeResponse, error) {
+			prompts = append(prompts, prompt)
+			return &models.ClaudeResponse{}, os.WriteFile(filepath.Join(dir, "fixed"), nil, 0644)
+		},
+	}
+	processor := newCheckTestProcessor(t, config, aiService, &mocks.MockJiraService{}, &mocks.MockGitHubService{})
+
+	run := &TicketRun{Key: "TEST-1", RepoDir: repoDir}
+	if err := processor.runChecks(context.Background(), run); err != nil {
+		t.Fatalf("Expected the fixed tests to pass but got: %v", err)
+	}
+	if len(prompts) != 1 {
+		t.Fatalf("Expected one fix attempt, got %d", len(prompts))
+	}
+	if !strings.Contains(prompts[0], "FAIL: TestCount") || !strings.Contains(prompts[0], "make the tests fail") {
+		t.Errorf("Expected the test output in the prompt, got %q", prompts[0])
+	}
+
+	if len(run.Checks) != 2 || run.Checks[0].Name != "build" || run.Checks[1].FixAttempts != 1 {
+		t.Fatalf("Expected the passed build and tests on the run, got %+v", run.Checks)
+	}
+	note := checksNote(config, run.Checks)
+	if !strings.Contains(note, "- build (`true`): passed\n") || !strings.Contains(note, "- tests (`test -f fixed") || !strings.Contains(note, "passed after 1 AI fix") {
+		t.Errorf("Expected the check results in the pull request note, got %q", note)
+	}
+}
+
+func TestTicketProcessor_RunChecks_FixAttemptsExhausted(t *testing.T) {
+	config := &models.Config{}
+	config.Build = models.CheckCommandConfig{Command: "exit 1", MaxFixAttempts: 2}
+	config.Components = map[string]models.ComponentConfig{"docs": {Build: &models.CheckCommandConfig{}}}
+
+	attempts := 0
+	aiService := &mocks.MockClaudeService{
+		GenerateCodeFunc: func(prompt, dir string) (*models.ClaudeResponse, error) {
+			attempts++
+			return &models.ClaudeResponse{}, nil
+		},
+	}
+	var failureComment string
+	jiraService := &mocks.MockJiraService{
+		AddCommentFunc: func(key, body string) error {
+			failureComment = body
+			return nil
+		},
+	}
+	processor := newCheckTestProcessor(t, config, aiService, jiraService, &mocks.MockGitHubService{})
+
+	if err := processor.runChecks(context.Background(), &TicketRun{Key: "TEST-1", RepoDir: t.TempDir()}); err == nil {
+		t.Fatal("Expected an error for a build the AI didn't fix")
+	}
+	if attempts != 2 {
+		t.Errorf("Expected 2 fix attempts, got %d", attempts)
+	}
+	if !strings.Contains(failureComment, "Check build failed after 2 fix attempts") {
+		t.Errorf("Expected the build failure on the ticket, got %q", failureComment)
+	}
+
+	if err := processor.runChecks(context.Background(), &TicketRun{Key: "TEST-2", Component: "docs", RepoDir: t.TempDir()}); err != nil {
+		t.Errorf("Expected no build check for a component disabling it, got: %v", err)
+	}
+}
+
+func TestTicketProcessor_RunChecks_FormatAndLint(t *testing.T) {
+	repoDir := t.TempDir()
+	config := &models.Config{}
+	config.Format = []models.HookCommand{{Name: "fmt", Command: `for f in $CHANGED_FILES; do echo formatted > "$f"; done`}}
+	config.Lint = models.CheckCommandConfig{Command: "grep -q formatted main.go && test -f lint-fixed", MaxFixAttempts: 1}
+
+	githubService := &mocks.MockGitHubService{
+		ChangedFilesFunc: func(directory string) ([]string, error) {
+			return []string{"main.go"}, nil
+		},
+	}
+	aiService := &mocks.MockClaudeService{
+		GenerateCodeFunc: func(prompt, dir string) (*models.ClaudeResponse, error) {
+			if !strings.Contains(prompt, "have lint errors") {
+				t.Errorf("Expected a lint fix prompt, got %q", prompt)
+			}
+			// The fix isn't formatted until the formatters run again
+			if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("unformatted"), 0644); err != nil {
+				return nil, err
+			}
+			return &models.ClaudeResponse{}, os.WriteFile(filepath.Join(dir, "lint-fixed"), nil, 0644)
+		},
+	}
+	processor := newCheckTestProcessor(t, config, aiService, &mocks.MockJiraService{}, githubService)
+
+	run := &TicketRun{Key: "TEST-1", RepoDir: repoDir}
+	if err := processor.runChecks(context.Background(), run); err != nil {
+		t.Fatalf("Expected the formatted and fixed changes to pass but got: %v", err)
+	}
+	if len(run.Checks) != 1 || run.Checks[0].Name != "lint" || run.Checks[0].FixAttempts != 1 {
+		t.Errorf("Expected lint to pass after one fix, got %+v", run.Checks)
+	}
+}
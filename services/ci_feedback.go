@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// ciFixAttemptMarker prefixes the PR comment recording a CI fix attempt for a commit
+const ciFixAttemptMarker = "🤖 AI CI Fix Attempt: "
+
+var ciFixAttemptPattern = regexp.MustCompile(regexp.QuoteMeta(ciFixAttemptMarker) + `([0-9a-f]{7,40})`)
+
+// ciFailure is a failed check run or commit status of a PR's head commit
+type ciFailure struct {
+	Name       string
+	Conclusion string
+	URL        string
+	Summary    string
+	Logs       string
+}
+
+// processCIFailures feeds the failing checks of the PR's head commit to the AI and pushes a fix.
+// Each head commit is attempted at most once, and at most MaxAttempts times per PR.
+func (p *PRReviewProcessorImpl) processCIFailures(ctx context.Context, ticketKey, component, owner, repo string, pr *models.GitHubPRDetails) error {
+	sha := pr.Head.SHA
+	if sha == "" {
+		return nil
+	}
+
+	attempted, attempts, err := p.getCIFixAttempts(ctx, owner, repo, pr.Number)
+	if err != nil {
+		return err
+	}
+	if attempted[sha] {
+		p.logger.Debug("CI failures of head commit already attempted", zap.String("ticket", ticketKey), zap.String("sha", sha))
+		return nil
+	}
+
+	failures, pending, err := p.collectCIFailures(ctx, ticketKey, owner, repo, sha)
+	if err != nil {
+		return err
+	}
+	if pending {
+		p.logger.Debug("Checks still running, waiting for CI to finish", zap.String("ticket", ticketKey), zap.String("sha", sha))
+		return nil
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	if attempts >= p.config.GitHub.CIFeedback.MaxAttempts {
+		p.logger.Info("CI fix attempts exhausted, leaving failures to humans",
+			zap.String("ticket", ticketKey),
+			zap.Int("pr_number", pr.Number),
+			zap.Int("attempts", attempts))
+		return nil
+	}
+
+	repoURL, err := p.getRepositoryURLFromPR(pr)
+	if err != nil {
+		return fmt.Errorf("failed to get repository URL from PR: %w", err)
+	}
+
+	// Record the attempt first so a fix that keeps failing is not retried on every scan
+	names := make([]string, 0, len(failures))
+	for _, failure := range failures {
+		names = append(names, failure.Name)
+	}
+	commentBody := fmt.Sprintf(`%s%s
+
+AI is fixing the failing checks (%s) of this commit for ticket %s, attempt %d of %d.`,
+		ciFixAttemptMarker, sha, strings.Join(names, ", "), ticketKey, attempts+1, p.config.GitHub.CIFeedback.MaxAttempts)
+	if err := p.githubService.AddPRComment(ctx, owner, repo, pr.Number, commentBody); err != nil {
+		return fmt.Errorf("failed to record CI fix attempt: %w", err)
+	}
+
+	p.logger.Info("Fixing failing CI checks",
+		zap.String("ticket", ticketKey),
+		zap.Int("pr_number", pr.Number),
+		zap.String("sha", sha),
+		zap.Strings("checks", names))
+
+	// Inline review comments are handled by review feedback processing, not here
+	ciPR := *pr
+	ciPR.ReviewComments = nil
+	err = p.inFeedbackState(ctx, ticketKey, func() error {
+		_, err := p.applyFeedbackFixes(ctx, ticketKey, component, repoURL, &ciPR, p.formatCIFeedback(failures), nil, false)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply CI fixes: %w", err)
+	}
+
+	return nil
+}
+
+// getCIFixAttempts returns the commits CI fixes were attempted for and the number of attempts on the PR
+func (p *PRReviewProcessorImpl) getCIFixAttempts(ctx context.Context, owner, repo string, prNumber int) (map[string]bool, int, error) {
+	comments, err := p.githubService.ListPRComments(ctx, owner, repo, prNumber)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get PR comments: %w", err)
+	}
+
+	attempted := p.findBotMarkers(comments, ciFixAttemptPattern)
+	return attempted, len(attempted), nil
+}
+
+// collectCIFailures collects the failed check runs and commit statuses of a commit.
+// pending reports whether any check has not finished yet.
+func (p *PRReviewProcessorImpl) collectCIFailures(ctx context.Context, ticketKey, owner, repo, sha string) (failures []ciFailure, pending bool, err error) {
+	checkRuns, err := p.githubService.ListCheckRuns(ctx, owner, repo, sha)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list check runs: %w", err)
+	}
+
+	for _, run := range checkRuns {
+		if run.Status != "completed" {
+			pending = true
+			continue
+		}
+		switch run.Conclusion {
+		case "failure", "timed_out", "startup_failure":
+		default:
+			continue
+		}
+
+		failure := ciFailure{
+			Name:       run.Name,
+			Conclusion: run.Conclusion,
+			URL:        run.HTMLURL,
+			Summary:    strings.TrimSpace(strings.Join([]string{run.Output.Title, run.Output.Summary}, "\n")),
+		}
+
+		// The check run of a GitHub Actions job shares the job's ID
+		if run.App.Slug == "github-actions" {
+			logs, err := p.githubService.GetJobLogs(ctx, owner, repo, run.ID)
+			if err != nil {
+				p.logger.Warn("Failed to download job logs",
+					zap.String("ticket", ticketKey),
+					zap.String("check", run.Name),
+					zap.Error(err))
+			} else {
+				failure.Logs = tailLines(logs, p.config.GitHub.CIFeedback.MaxLogLines)
+			}
+		}
+		if failure.Logs == "" {
+			failure.Logs = tailLines(run.Output.Text, p.config.GitHub.CIFeedback.MaxLogLines)
+		}
+
+		failures = append(failures, failure)
+	}
+
+	status, err := p.githubService.GetCombinedStatus(ctx, owner, repo, sha)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get combined status: %w", err)
+	}
+
+	for _, commitStatus := range status.Statuses {
+		switch commitStatus.State {
+		case "pending":
+			pending = true
+		case "failure", "error":
+			failures = append(failures, ciFailure{
+				Name:       commitStatus.Context,
+				Conclusion: commitStatus.State,
+				URL:        commitStatus.TargetURL,
+				Summary:    commitStatus.Description,
+			})
+		}
+	}
+
+	return failures, pending, nil
+}
+
+// formatCIFeedback formats failing checks as feedback for the AI
+func (p *PRReviewProcessorImpl) formatCIFeedback(failures []ciFailure) string {
+	var feedback strings.Builder
+
+	feedback.WriteString("## CI Check Failures\n\n")
+	feedback.WriteString("The following checks failed on the latest commit of this PR. Fix the code so they pass; do not disable or skip the checks.\n\n")
+	for _, failure := range failures {
+		feedback.WriteString(fmt.Sprintf("### %s (%s)\n", failure.Name, failure.Conclusion))
+		if failure.URL != "" {
+			feedback.WriteString(fmt.Sprintf("**Details:** %s\n", failure.URL))
+		}
+		if failure.Summary != "" {
+			feedback.WriteString(fmt.Sprintf("%s\n", failure.Summary))
+		}
+		if failure.Logs != "" {
+			feedback.WriteString("```\n")
+			feedback.WriteString(failure.Logs)
+			feedback.WriteString("\n```\n")
+		}
+		feedback.WriteString("\n")
+	}
+
+	return feedback.String()
+}
+
+// tailLines returns the last n lines of text
+func tailLines(text string, n int) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if n > 0 && len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// newCIFeedbackConfig returns a bot config with CI feedback enabled
+func newCIFeedbackConfig() *models.Config {
+	config := newBotConfig("ai-bot")
+	config.GitHub.CIFeedback.Enabled = true
+	config.GitHub.CIFeedback.MaxLogLines = 2
+	config.GitHub.CIFeedback.MaxAttempts = 2
+	return config
+}
+
+func TestPRReviewProcessor_CollectCIFailures(t *testing.T) {
+	processor := &PRReviewProcessorImpl{
+		githubService: &mocks.MockGitHubService{
+			ListCheckRunsFunc: func(owner, repo, ref string) ([]models.GitHubCheckRun, error) {
+				failed := models.GitHubCheckRun{ID: 101, Name: "test", Status: "completed", Conclusion: "failure"}
+				failed.App.Slug = "github-actions"
+				external := models.GitHubCheckRun{ID: 102, Name: "sonar", Status: "completed", Conclusion: "timed_out"}
+				external.Output.Text = "analysis\ntook\ntoo long"
+				return []models.GitHubCheckRun{
+					failed,
+					external,
+					{ID: 103, Name: "lint", Status: "completed", Conclusion: "success"},
+				}, nil
+			},
+			GetJobLogsFunc: func(owner, repo string, jobID int64) (string, error) {
+				return "setup\nbuild\n--- FAIL: TestSomething\n", nil
+			},
+			GetCombinedStatusFunc: func(owner, repo, ref string) (*models.GitHubCombinedStatus, error) {
+				return &models.GitHubCombinedStatus{State: "failure", Statuses: []models.GitHubCommitStatus{
+					{Context: "ci/jenkins", State: "error", Description: "Build errored"},
+					{Context: "ci/coverage", State: "success"},
+				}}, nil
+			},
+		},
+		config: newCIFeedbackConfig(),
+		logger: zap.NewNop(),
+	}
+
+	failures, pending, err := processor.collectCIFailures(context.Background(), "TEST-1", "example", "repo", "abc1234")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if pending {
+		t.Error("Expected no pending checks")
+	}
+	if len(failures) != 3 {
+		t.Fatalf("Expected 3 failures, got %+v", failures)
+	}
+	if failures[0].Logs != "build\n--- FAIL: TestSomething" {
+		t.Errorf("Expected the tail of the job log, got %q", failures[0].Logs)
+	}
+	if failures[1].Logs != "took\ntoo long" {
+		t.Errorf("Expected the tail of the check output, got %q", failures[1].Logs)
+	}
+	if failures[2].Name != "ci/jenkins" || failures[2].Summary != "Build errored" {
+		t.Errorf("Unexpected commit status failure: %+v", failures[2])
+	}
+
+	feedback := processor.formatCIFeedback(failures)
+	if !strings.Contains(feedback, "### test (failure)") || !strings.Contains(feedback, "--- FAIL: TestSomething") {
+		t.Errorf("Unexpected feedback:\n%s", feedback)
+	}
+}
+
+func TestPRReviewProcessor_ProcessCIFailures_Skips(t *testing.T) {
+	failingRun := []models.GitHubCheckRun{{ID: 101, Name: "test", Status: "completed", Conclusion: "failure"}}
+
+	tests := []struct {
+		name      string
+		comments  []models.GitHubPRComment
+		checkRuns []models.GitHubCheckRun
+	}{
+		{
+			name:      "head commit already attempted",
+			comments:  []models.GitHubPRComment{{User: models.GitHubUser{Login: "ai-bot"}, Body: ciFixAttemptMarker + "abc1234"}},
+			checkRuns: failingRun,
+		},
+		{
+			name: "attempts exhausted",
+			comments: []models.GitHubPRComment{
+				{User: models.GitHubUser{Login: "ai-bot"}, Body: ciFixAttemptMarker + "0000001"},
+				{User: models.GitHubUser{Login: "ai-bot"}, Body: ciFixAttemptMarker + "0000002"},
+			},
+			checkRuns: failingRun,
+		},
+		{
+			name:      "checks still running",
+			checkRuns: append([]models.GitHubCheckRun{{Name: "lint", Status: "in_progress"}}, failingRun...),
+		},
+		{
+			name:      "all checks passed",
+			checkRuns: []models.GitHubCheckRun{{Name: "test", Status: "completed", Conclusion: "success"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor := &PRReviewProcessorImpl{
+				githubService: &mocks.MockGitHubService{
+					ListPRCommentsFunc: func(owner, repo string, prNumber int) ([]models.GitHubPRComment, error) {
+						return tt.comments, nil
+					},
+					ListCheckRunsFunc: func(owner, repo, ref string) ([]models.GitHubCheckRun, error) {
+						return tt.checkRuns, nil
+					},
+					AddPRCommentFunc: func(owner, repo string, prNumber int, body string) error {
+						t.Errorf("Expected no CI fix attempt, got comment:\n%s", body)
+						return nil
+					},
+				},
+				aiService: &mocks.MockClaudeService{
+					GenerateCodeFunc: func(prompt, repoDir string) (*models.ClaudeResponse, error) {
+						t.Error("Expected the AI not to be invoked")
+						return nil, nil
+					},
+				},
+				config: newCIFeedbackConfig(),
+				logger: zap.NewNop(),
+			}
+
+			pr := &models.GitHubPRDetails{Number: 7, Head: models.GitHubRef{SHA: "abc1234"}}
+			if err := processor.processCIFailures(context.Background(), "TEST-1", "", "example", "repo", pr); err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestPRReviewProcessor_ProcessCIFailures_RecordsAttempt(t *testing.T) {
+	var commentBody string
+	config := newCIFeedbackConfig()
+	stateMachine := newTestStateMachine(config)
+	processor := &PRReviewProcessorImpl{
+		githubService: &mocks.MockGitHubService{
+			ListCheckRunsFunc: func(owner, repo, ref string) ([]models.GitHubCheckRun, error) {
+				return []models.GitHubCheckRun{{ID: 101, Name: "test", Status: "completed", Conclusion: "failure"}}, nil
+			},
+			AddPRCommentFunc: func(owner, repo string, prNumber int, body string) error {
+				commentBody = body
+				return nil
+			},
+			CloneRepositoryFunc: func(repoURL, directory string, opts models.CloneOptions) error {
+				return errors.New("clone failed")
+			},
+		},
+		stateMachine: stateMachine,
+		config:       config,
+		logger:       zap.NewNop(),
+	}
+
+	pr := &models.GitHubPRDetails{Number: 7, Head: models.GitHubRef{SHA: "abc1234", Ref: "feature/TEST-1"}}
+	pr.Head.Repo.CloneURL = "https://github.com/ai-bot/repo.git"
+	if err := processor.processCIFailures(context.Background(), "TEST-1", "", "example", "repo", pr); err == nil {
+		t.Error("Expected the clone error to be returned")
+	}
+
+	// The attempt is recorded even though the fix failed, so it is not retried for this commit
+	if !strings.Contains(commentBody, ciFixAttemptMarker+"abc1234") || !strings.Contains(commentBody, "attempt 1 of 2") {
+		t.Errorf("Unexpected attempt comment:\n%s", commentBody)
+	}
+
+	// The failed fix returns the ticket to the PR open state with the error recorded
+	records := stateMachine.Records()
+	if len(records) != 1 || records[0].State != models.TicketStatePROpen || !strings.Contains(records[0].Error, "clone failed") {
+		t.Errorf("Unexpected ticket state records: %+v", records)
+	}
+}
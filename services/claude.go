@@ -52,7 +52,7 @@ func getContentAsString(content interface{}) string {
 type ClaudeService interface {
 	AIService
 	// GenerateCodeClaude generates code using Claude CLI and returns ClaudeResponse
-	GenerateCodeClaude(prompt string, repoDir string) (*models.ClaudeResponse, error)
+	GenerateCodeClaude(ctx context.Context, prompt string, repoDir string) (*models.ClaudeResponse, error)
 }
 
 // ClaudeServiceImpl implements the ClaudeService interface
@@ -64,7 +64,7 @@ type ClaudeServiceImpl struct {
 
 // NewClaudeService creates a new ClaudeService
 func NewClaudeService(config *models.Config, logger *zap.Logger, executor ...models.CommandExecutor) ClaudeService {
-	commandExecutor := exec.Command
+	commandExecutor := exec.CommandContext
 	if len(executor) > 0 {
 		commandExecutor = executor[0]
 	}
@@ -76,12 +76,12 @@ func NewClaudeService(config *models.Config, logger *zap.Logger, executor ...mod
 }
 
 // GenerateCode implements the AIService interface
-func (s *ClaudeServiceImpl) GenerateCode(prompt string, repoDir string) (interface{}, error) {
-	return s.GenerateCodeClaude(prompt, repoDir)
+func (s *ClaudeServiceImpl) GenerateCode(ctx context.Context, prompt string, repoDir string) (interface{}, error) {
+	return s.GenerateCodeClaude(ctx, prompt, repoDir)
 }
 
 // GenerateDocumentation implements the AIService interface
-func (s *ClaudeServiceImpl) GenerateDocumentation(repoDir string) error {
+func (s *ClaudeServiceImpl) GenerateDocumentation(ctx context.Context, repoDir string) error {
 	// Check if CLAUDE.md already exists
 	claudePath := filepath.Join(repoDir, "CLAUDE.md")
 	if _, err := os.Stat(claudePath); err == nil {
@@ -136,7 +136,7 @@ Search the entire repository for all .md files and create a comprehensive index
 IMPORTANT: Verify that you actually created and wrote CLAUDE.md at the root of the project!`
 
 	// Generate the documentation using Claude
-	response, err := s.GenerateCodeClaude(prompt, repoDir)
+	response, err := s.GenerateCodeClaude(ctx, prompt, repoDir)
 	if err != nil {
 		return fmt.Errorf("failed to generate CLAUDE.md: %w", err)
 	}
@@ -166,9 +166,14 @@ IMPORTANT: Verify that you actually created and wrote CLAUDE.md at the root of t
 }
 
 // GenerateCodeClaude generates code using Claude CLI
-func (s *ClaudeServiceImpl) GenerateCodeClaude(prompt string, repoDir string) (*models.ClaudeResponse, error) {
+func (s *ClaudeServiceImpl) GenerateCodeClaude(ctx context.Context, prompt string, repoDir string) (*models.ClaudeResponse, error) {
 	// Build command arguments based on configuration
 	s.logger.Info("Generating code for repo", zap.String("repo_dir", repoDir))
+
+	// The raw stream goes to the ticket's AI log, the main log only gets the high-level events
+	streamLogger, closeStreamLog := newAIStreamLogger(s.config, s.logger, repoDir)
+	defer closeStreamLog()
+
 	args := []string{"--output-format", "stream-json", "--verbose", "-p", prompt}
 
 	// Add dangerous permissions flag if configured
@@ -188,22 +193,18 @@ func (s *ClaudeServiceImpl) GenerateCodeClaude(prompt string, repoDir string) (*
 
 	// Set up a context with timeout
 	timeout := time.Duration(s.config.Claude.Timeout) * time.Second
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	// Create the command with context
-	cmd := exec.CommandContext(ctx, s.config.Claude.CLIPath, args...)
-	cmd.Dir = repoDir
+	// Create the command with context, in the configured sandbox
+	cmd := aiCommand(ctx, s.config, repoDir, s.config.Claude.CLIPath, nil, args...)
 
 	// Print the actual command being executed
-	s.logger.Debug("Executing Claude CLI",
-		zap.String("command", s.config.Claude.CLIPath),
-		zap.Strings("args", args),
+	streamLogger.Debug("Executing Claude CLI",
+		zap.String("command", cmd.Path),
+		zap.Strings("args", cmd.Args[1:]),
 		zap.String("directory", repoDir))
 
-	// Set environment variables
-	cmd.Env = os.Environ()
-
 	// Create pipes for stdout and stderr
 	stdoutPipe, err := cmd.StdoutPipe()
 	if err != nil {
@@ -232,7 +233,7 @@ func (s *ClaudeServiceImpl) GenerateCodeClaude(prompt string, repoDir string) (*
 		defer wg.Done()
 		scanner := bufio.NewScanner(stderrPipe)
 		for scanner.Scan() {
-			s.logger.Error("stderr", zap.String("line", scanner.Text()))
+			streamLogger.Error("stderr", zap.String("line", scanner.Text()))
 		}
 	}()
 
@@ -251,7 +252,7 @@ func (s *ClaudeServiceImpl) GenerateCodeClaude(prompt string, repoDir string) (*
 
 			var response models.ClaudeResponse
 			if err := json.Unmarshal([]byte(line), &response); err != nil {
-				s.logger.Error("Failed to parse JSON line", zap.String("line", line), zap.Error(err))
+				streamLogger.Error("Failed to parse JSON line", zap.String("line", line), zap.Error(err))
 				continue
 			}
 
@@ -285,10 +286,10 @@ func (s *ClaudeServiceImpl) GenerateCodeClaude(prompt string, repoDir string) (*
 			// Log in concise format: Role: content (one line per content item with tab prefix)
 			if role != "" && len(contents) > 0 {
 				for _, content := range contents {
-					s.logger.Debug("Claude response", zap.String("role", role), zap.String("content", content))
+					streamLogger.Debug("Claude response", zap.String("role", role), zap.String("content", content))
 				}
 			} else if response.IsError {
-				s.logger.Error("Claude error", zap.String("result", response.Result))
+				streamLogger.Error("Claude error", zap.String("result", response.Result))
 			}
 
 			// Check if there was an error
@@ -353,8 +354,14 @@ func PreparePrompt(ticket *models.JiraTicketResponse) string {
 	sb.WriteString("# Task\n\n")
 	sb.WriteString(fmt.Sprintf("## %s\n\n", ticket.Fields.Summary))
 	sb.WriteString(fmt.Sprintf("%s\n\n", ticket.Fields.Description))
+	for _, field := range ticket.PromptFields {
+		sb.WriteString(fmt.Sprintf("## %s\n\n%s\n\n", field.Name, field.Value))
+	}
 
 	// Add comments if available
+	if ticket.CommentSummary != "" {
+		sb.WriteString(fmt.Sprintf("## Earlier Comments\n\n%s\n\n", ticket.CommentSummary))
+	}
 	if len(ticket.Fields.Comment.Comments) > 0 {
 		sb.WriteString("## Comments\n\n")
 		for _, comment := range ticket.Fields.Comment.Comments {
@@ -392,7 +399,7 @@ func PreparePrompt(ticket *models.JiraTicketResponse) string {
 }
 
 // PreparePromptForPRFeedback prepares a prompt for Claude CLI based on PR feedback
-func PreparePromptForPRFeedback(pr *models.GitHubPullRequest, review *models.GitHubReview, repoDir string) (string, error) {
+func PreparePromptForPRFeedback(ctx context.Context, pr *models.GitHubPullRequest, review *models.GitHubReview, repoDir string) (string, error) {
 	var sb strings.Builder
 
 	sb.WriteString("# Pull Request Feedback\n\n")
@@ -403,7 +410,7 @@ func PreparePromptForPRFeedback(pr *models.GitHubPullRequest, review *models.Git
 	sb.WriteString(fmt.Sprintf("**%s**:\n%s\n\n", review.User.Login, review.Body))
 
 	// Get the diff of the PR
-	cmd := exec.Command("git", "diff", "origin/main...HEAD")
+	cmd := exec.CommandContext(ctx, "git", "diff", "origin/main...HEAD")
 	cmd.Dir = repoDir
 
 	var stdout bytes.Buffer
@@ -446,8 +453,8 @@ func PreparePromptForPRFeedback(pr *models.GitHubPullRequest, review *models.Git
 }
 
 // GetChangedFiles gets a list of files changed in the current branch
-func GetChangedFiles(repoDir string) ([]string, error) {
-	cmd := exec.Command("git", "diff", "--name-only", "origin/main...HEAD")
+func GetChangedFiles(ctx context.Context, repoDir string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "diff", "--name-only", "origin/main...HEAD")
 	cmd.Dir = repoDir
 
 	var stdout bytes.Buffer
@@ -1,6 +1,7 @@
 package services_test
 
 import (
+	"context"
 	"os"
 	"testing"
 
@@ -30,7 +31,7 @@ func TestGenerateCode(t *testing.T) {
 			},
 		}
 		var ai services.AIService = mockClaude
-		result, err := ai.GenerateCode("Test prompt", tempDir)
+		result, err := ai.GenerateCode(context.Background(), "Test prompt", tempDir)
 		if err != nil {
 			t.Fatalf("GenerateCode returned an error: %v", err)
 		}
@@ -70,7 +71,7 @@ func TestGenerateCode(t *testing.T) {
 			},
 		}
 		var ai services.AIService = mockClaude
-		result, err := ai.GenerateCode("Test prompt", tempDir)
+		result, err := ai.GenerateCode(context.Background(), "Test prompt", tempDir)
 		if err != nil {
 			t.Fatalf("GenerateCode returned an error: %v", err)
 		}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// cliVersionTimeout bounds the `--version` check of an installed CLI
+const cliVersionTimeout = 30 * time.Second
+
+// CLIBootstrapper installs the pinned versions of the AI CLIs, so container images don't need to include them
+type CLIBootstrapper interface {
+	// Bootstrap makes the provider's pinned CLI release available and returns the path of its binary
+	Bootstrap(provider string) (string, error)
+}
+
+// CLIBootstrapperImpl implements the CLIBootstrapper interface by downloading checksummed binaries into a cache
+// directory
+type CLIBootstrapperImpl struct {
+	config *models.Config
+	client *http.Client
+	goos   string
+	goarch string
+	logger *zap.Logger
+}
+
+// NewCLIBootstrapper creates a new CLIBootstrapper for the platform the application runs on
+func NewCLIBootstrapper(config *models.Config, logger *zap.Logger) CLIBootstrapper {
+	return &CLIBootstrapperImpl{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Minute},
+		goos:   runtime.GOOS,
+		goarch: runtime.GOARCH,
+		logger: logger,
+	}
+}
+
+// Bootstrap makes the provider's pinned CLI release available and returns the path of its binary. A cached binary
+// matching its checksum is used as is. Otherwise the binary is downloaded, unless in offline mode, where the CLI at
+// cli_path must report the pinned version instead.
+func (b *CLIBootstrapperImpl) Bootstrap(provider string) (string, error) {
+	release := b.config.GetCLIRelease(provider)
+	platform := models.Platform(b.goos, b.goarch)
+	path := filepath.Join(b.config.CLIBootstrap.CacheDir, provider, release.Version, platform, provider)
+	checksum, hasChecksum := release.Checksum(b.goos, b.goarch)
+
+	if _, err := os.Stat(path); err == nil {
+		if !hasChecksum {
+			// Offline installs may seed the cache without publishing checksums
+			b.logger.Info("Using cached AI CLI", zap.String("provider", provider), zap.String("path", path))
+			return path, nil
+		}
+		err := verifyChecksum(path, checksum)
+		if err == nil {
+			b.logger.Info("Using cached AI CLI", zap.String("provider", provider), zap.String("path", path))
+			return path, nil
+		}
+		b.logger.Warn("Cached AI CLI is corrupt", zap.String("provider", provider), zap.String("path", path), zap.Error(err))
+	}
+
+	if b.config.CLIBootstrap.Offline {
+		cliPath := b.config.GetCLIPath(provider)
+		if err := verifyCLIVersion(cliPath, release.Version); err != nil {
+			return "", fmt.Errorf("no cached %s CLI %s for %s and the installed CLI can't be used: %w", provider, release.Version, platform, err)
+		}
+		b.logger.Info("Using installed AI CLI", zap.String("provider", provider), zap.String("path", cliPath))
+		return cliPath, nil
+	}
+
+	if !hasChecksum {
+		return "", fmt.Errorf("cli_bootstrap.%s.sha256 has no checksum for platform %s", provider, platform)
+	}
+	url := release.DownloadURL(b.goos, b.goarch)
+	b.logger.Info("Downloading AI CLI",
+		zap.String("provider", provider),
+		zap.String("version", release.Version),
+		zap.String("url", url))
+	if err := b.download(url, path, checksum); err != nil {
+		return "", fmt.Errorf("failed to install %s CLI %s: %w", provider, release.Version, err)
+	}
+	return path, nil
+}
+
+// download fetches the binary into the cache, installing it only if its checksum matches
+func (b *CLIBootstrapperImpl) download(url, path, checksum string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	resp, err := b.client.Get(url)
+	if err != nil {
+		return fmt.Errorf("failed to download binary: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download binary: status %d", resp.StatusCode)
+	}
+
+	// Download next to the destination so a partial download is never mistaken for the binary
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tmp, hash), resp.Body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write binary: %w", err)
+	}
+
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != checksum {
+		return fmt.Errorf("checksum mismatch: expected %s, got %s", checksum, actual)
+	}
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		return fmt.Errorf("failed to make binary executable: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to install binary: %w", err)
+	}
+	return nil
+}
+
+// verifyChecksum checks the SHA-256 checksum of a file
+func verifyChecksum(path, checksum string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != checksum {
+		return fmt.Errorf("checksum mismatch: expected %s, got %s", checksum, actual)
+	}
+	return nil
+}
+
+// verifyCLIVersion checks that the CLI reports the version with `--version`
+func verifyCLIVersion(cliPath, version string) error {
+	output, err := cliVersion(context.Background(), cliPath)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(output, version) {
+		return fmt.Errorf("%s reports version %q, expected %s", cliPath, output, version)
+	}
+	return nil
+}
+
+// cliVersion returns what the CLI reports with `--version`
+func cliVersion(ctx context.Context, cliPath string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, cliVersionTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, cliPath, "--version").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to run %s --version: %w", cliPath, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
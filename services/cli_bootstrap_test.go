@@ -0,0 +1,123 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func newTestCLIBootstrapper(t *testing.T, url, checksum string) (*CLIBootstrapperImpl, *models.Config) {
+	config := &models.Config{}
+	config.CLIBootstrap.Enabled = true
+	config.CLIBootstrap.CacheDir = t.TempDir()
+	config.CLIBootstrap.Claude = models.CLIRelease{
+		Version: "1.2.3",
+		URL:     url + "/{version}/{os}-{arch}/claude",
+		SHA256:  map[string]string{"linux-arm64": checksum},
+	}
+	return &CLIBootstrapperImpl{
+		config: config,
+		client: http.DefaultClient,
+		goos:   "linux",
+		goarch: "arm64",
+		logger: zap.NewNop(),
+	}, config
+}
+
+func TestCLIBootstrapper_Bootstrap(t *testing.T) {
+	binary := []byte("#!/bin/sh\necho 1.2.3\n")
+	sum := sha256.Sum256(binary)
+
+	downloads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/1.2.3/linux-arm64/claude" {
+			http.NotFound(w, r)
+			return
+		}
+		downloads++
+		w.Write(binary)
+	}))
+	defer server.Close()
+
+	bootstrapper, config := newTestCLIBootstrapper(t, server.URL, hex.EncodeToString(sum[:]))
+	path, err := bootstrapper.Bootstrap("claude")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if expected := filepath.Join(config.CLIBootstrap.CacheDir, "claude", "1.2.3", "linux-arm64", "claude"); path != expected {
+		t.Errorf("Expected the binary at %s, got %s", expected, path)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Errorf("Expected an executable binary, got %v, %v", info, err)
+	}
+
+	// The cached binary is reused
+	if _, err := bootstrapper.Bootstrap("claude"); err != nil || downloads != 1 {
+		t.Errorf("Expected the cached binary to be used, got %d downloads, %v", downloads, err)
+	}
+
+	// A corrupt cached binary is downloaded again
+	if err := os.WriteFile(path, []byte("corrupt"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bootstrapper.Bootstrap("claude"); err != nil || downloads != 2 {
+		t.Errorf("Expected the corrupt binary to be replaced, got %d downloads, %v", downloads, err)
+	}
+}
+
+func TestCLIBootstrapper_Bootstrap_ChecksumMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("tampered"))
+	}))
+	defer server.Close()
+
+	bootstrapper, config := newTestCLIBootstrapper(t, server.URL, "0000")
+	if _, err := bootstrapper.Bootstrap("claude"); err == nil {
+		t.Fatal("Expected a checksum mismatch to fail the bootstrap")
+	}
+	if _, err := os.Stat(filepath.Join(config.CLIBootstrap.CacheDir, "claude", "1.2.3", "linux-arm64", "claude")); !os.IsNotExist(err) {
+		t.Error("Expected the mismatching binary not to be installed")
+	}
+}
+
+func TestCLIBootstrapper_Bootstrap_Offline(t *testing.T) {
+	bootstrapper, config := newTestCLIBootstrapper(t, "http://unreachable.invalid", "")
+	config.CLIBootstrap.Offline = true
+
+	// The installed CLI must report the pinned version
+	cliPath := filepath.Join(t.TempDir(), "claude")
+	if err := os.WriteFile(cliPath, []byte("#!/bin/sh\necho 'claude 1.0.0'\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config.Claude.CLIPath = cliPath
+	if _, err := bootstrapper.Bootstrap("claude"); err == nil {
+		t.Error("Expected an installed CLI of another version to be rejected")
+	}
+
+	if err := os.WriteFile(cliPath, []byte("#!/bin/sh\necho 'claude 1.2.3'\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if path, err := bootstrapper.Bootstrap("claude"); err != nil || path != cliPath {
+		t.Errorf("Expected the installed CLI to be used, got %s, %v", path, err)
+	}
+
+	// A seeded cache takes precedence
+	cached := filepath.Join(config.CLIBootstrap.CacheDir, "claude", "1.2.3", "linux-arm64", "claude")
+	if err := os.MkdirAll(filepath.Dir(cached), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cached, []byte("binary"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if path, err := bootstrapper.Bootstrap("claude"); err != nil || path != cached {
+		t.Errorf("Expected the cached CLI to be used, got %s, %v", path, err)
+	}
+}
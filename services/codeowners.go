@@ -0,0 +1,153 @@
+package services
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// codeownersLocations are the paths GitHub looks for a CODEOWNERS file in, in order of precedence
+var codeownersLocations = []string{
+	".github/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+}
+
+// CodeownersRule is a single pattern line of a CODEOWNERS file
+type CodeownersRule struct {
+	Pattern string
+	Owners  []string
+	regex   *regexp.Regexp
+}
+
+// Codeowners holds the parsed rules of a CODEOWNERS file
+type Codeowners struct {
+	Rules []CodeownersRule
+}
+
+// LoadCodeowners reads the CODEOWNERS file of a cloned repository.
+// It returns nil without error when the repository has no CODEOWNERS file.
+func LoadCodeowners(repoDir string) (*Codeowners, error) {
+	for _, location := range codeownersLocations {
+		file, err := os.Open(filepath.Join(repoDir, location))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return ParseCodeowners(file)
+	}
+	return nil, nil
+}
+
+// ParseCodeowners parses CODEOWNERS content
+func ParseCodeowners(r io.Reader) (*Codeowners, error) {
+	codeowners := &Codeowners{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// Strip trailing comments
+		if idx := strings.Index(line, " #"); idx >= 0 {
+			line = strings.TrimSpace(line[:idx])
+		}
+
+		fields := strings.Fields(line)
+		codeowners.Rules = append(codeowners.Rules, CodeownersRule{
+			Pattern: fields[0],
+			Owners:  fields[1:],
+			regex:   pathPatternToRegex(fields[0]),
+		})
+	}
+	return codeowners, scanner.Err()
+}
+
+// OwnersFor returns the owners of a path. As on GitHub, the last matching rule wins.
+func (c *Codeowners) OwnersFor(path string) []string {
+	path = strings.TrimPrefix(path, "/")
+	for i := len(c.Rules) - 1; i >= 0; i-- {
+		if c.Rules[i].regex.MatchString(path) {
+			return c.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// SplitCodeowners splits CODEOWNERS owners into user logins and team slugs.
+// Owners are written as @user or @org/team; email owners can't be requested as reviewers and are dropped.
+func SplitCodeowners(owners []string) (users, teams []string) {
+	for _, owner := range owners {
+		if !strings.HasPrefix(owner, "@") {
+			continue
+		}
+		owner = strings.TrimPrefix(owner, "@")
+		if idx := strings.Index(owner, "/"); idx >= 0 {
+			teams = append(teams, owner[idx+1:])
+		} else {
+			users = append(users, owner)
+		}
+	}
+	return users, teams
+}
+
+// pathPatternToRegex converts a gitignore-style path pattern, as in CODEOWNERS, to a regular expression
+func pathPatternToRegex(pattern string) *regexp.Regexp {
+	anchored := strings.HasPrefix(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	// A pattern containing a slash in the middle is relative to the repository root
+	if strings.Contains(strings.TrimSuffix(pattern, "/"), "/") {
+		anchored = true
+	}
+
+	directory := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	var sb strings.Builder
+	if anchored {
+		sb.WriteString("^")
+	} else {
+		sb.WriteString("(^|/)")
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '*' && i+1 < len(pattern) && pattern[i+1] == '*':
+			// ** matches across directories
+			sb.WriteString(".*")
+			i++
+			if i+1 < len(pattern) && pattern[i+1] == '/' {
+				i++
+				sb.WriteString("/?")
+			}
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	switch {
+	case directory:
+		// A directory pattern matches everything below the directory
+		sb.WriteString("/.*$")
+	case strings.ContainsAny(pattern[strings.LastIndex(pattern, "/")+1:], "*?"):
+		// A wildcard in the last segment only matches at that level, e.g. docs/* not docs/a/b.md
+		sb.WriteString("$")
+	default:
+		// A file pattern also matches everything below a directory of that name
+		sb.WriteString("(/.*)?$")
+	}
+
+	return regexp.MustCompile(sb.String())
+}
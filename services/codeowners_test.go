@@ -0,0 +1,102 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCodeowners_OwnersFor(t *testing.T) {
+	repoDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repoDir, ".github"), 0755); err != nil {
+		t.Fatalf("Failed to create .github directory: %v", err)
+	}
+	content := `# Default owners
+*                   @org/core
+*.go                @gopher
+/docs/              @writer user@example.com
+apps/**/config.yml  @ops # inline comment
+build/              @org/release
+`
+	if err := os.WriteFile(filepath.Join(repoDir, ".github", "CODEOWNERS"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write CODEOWNERS: %v", err)
+	}
+
+	codeowners, err := LoadCodeowners(repoDir)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if codeowners == nil {
+		t.Fatal("Expected CODEOWNERS to be found")
+	}
+
+	tests := []struct {
+		path     string
+		expected []string
+	}{
+		{"README.md", []string{"@org/core"}},
+		{"services/github.go", []string{"@gopher"}},
+		{"docs/guide.md", []string{"@writer", "user@example.com"}},
+		{"src/docs/guide.md", []string{"@org/core"}},
+		{"apps/web/prod/config.yml", []string{"@ops"}},
+		{"apps/config.yml", []string{"@ops"}},
+		{"tools/build/Makefile", []string{"@org/release"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			owners := codeowners.OwnersFor(tt.path)
+			if !reflect.DeepEqual(owners, tt.expected) {
+				t.Errorf("Expected owners %v, got %v", tt.expected, owners)
+			}
+		})
+	}
+}
+
+func TestLoadCodeowners_Missing(t *testing.T) {
+	codeowners, err := LoadCodeowners(t.TempDir())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if codeowners != nil {
+		t.Error("Expected nil CODEOWNERS for a repository without one")
+	}
+}
+
+func TestSplitCodeowners(t *testing.T) {
+	users, teams := SplitCodeowners([]string{"@alice", "@org/backend", "bob@example.com"})
+	if !reflect.DeepEqual(users, []string{"alice"}) {
+		t.Errorf("Expected users [alice], got %v", users)
+	}
+	if !reflect.DeepEqual(teams, []string{"backend"}) {
+		t.Errorf("Expected teams [backend], got %v", teams)
+	}
+}
+
+func TestPathPatternToRegex(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		matches bool
+	}{
+		{"*", "services/github.go", true},
+		{"*.go", "services/github.go", true},
+		{"docs/*", "docs/guide.md", true},
+		{"docs/*", "docs/a/b.md", false},
+		{"docs/**", "docs/a/b.md", true},
+		{"docs/", "docs/a/b.md", true},
+		{"docs", "docs/a/b.md", true},
+		{"/build/*.sh", "build/release.sh", true},
+		{"/build/*.sh", "build/scripts/release.sh", false},
+		{"apps/**/config.yml", "apps/web/config.yml", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			if got := pathPatternToRegex(tt.pattern).MatchString(tt.path); got != tt.matches {
+				t.Errorf("Expected %s matching %s to be %t", tt.pattern, tt.path, tt.matches)
+			}
+		})
+	}
+}
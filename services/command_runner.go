@@ -0,0 +1,166 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// defaultCommandTimeout bounds configured commands without a timeout
+const defaultCommandTimeout = 300 * time.Second
+
+// maxCommandErrorOutput bounds how much of a failing command's output is reported in its error
+const maxCommandErrorOutput = 4000
+
+// maxCommandAuditOutput bounds how much of a command's output is kept in the audit log
+const maxCommandAuditOutput = 64 * 1024
+
+// CommandEnv is the ticket context passed to configured commands as environment variables
+type CommandEnv struct {
+	TicketKey string
+	RepoDir   string
+	Component string
+	Branch    string
+	PRURL     string // Empty until the ticket has a pull request
+
+	ChangedFiles []string // Files the AI added or modified, only listed for formatters and checks
+}
+
+// CommandRunner runs configured shell commands in repository checkouts
+type CommandRunner interface {
+	// RunCommand runs a shell command in the repository checkout and records it in the audit log.
+	// A non-zero exit status or exceeding the timeout is an error.
+	RunCommand(ctx context.Context, name, command string, timeoutSeconds int, env CommandEnv) error
+
+	// RunCommandOutput runs a shell command like RunCommand and also returns its combined output
+	RunCommandOutput(ctx context.Context, name, command string, timeoutSeconds int, env CommandEnv) (string, error)
+
+	// RunHooks runs the commands configured for the hook point in order, stopping at the first failure
+	RunHooks(ctx context.Context, point models.HookPoint, env CommandEnv) error
+}
+
+// CommandRunnerImpl implements the CommandRunner interface
+type CommandRunnerImpl struct {
+	auditLog AuditLog
+	config   *models.Config
+	logger   *zap.Logger
+}
+
+// NewCommandRunner creates a new CommandRunner recording to the configured audit log
+func NewCommandRunner(config *models.Config, logger *zap.Logger) CommandRunner {
+	return &CommandRunnerImpl{
+		auditLog: NewAuditLog(config.AuditLog),
+		config:   config,
+		logger:   logger,
+	}
+}
+
+// RunHooks runs the commands configured for the hook point in order, stopping at the first failure
+func (r *CommandRunnerImpl) RunHooks(ctx context.Context, point models.HookPoint, env CommandEnv) error {
+	for i, hook := range r.config.GetHooks(env.Component, point) {
+		name := hook.Name
+		if name == "" {
+			name = fmt.Sprintf("%s[%d]", point, i+1)
+		}
+		if err := r.RunCommand(ctx, name, hook.Command, hook.TimeoutSeconds, env); err != nil {
+			return fmt.Errorf("%s hook failed: %w", point, err)
+		}
+	}
+	return nil
+}
+
+// RunCommand runs a shell command in the repository checkout and records it in the audit log
+func (r *CommandRunnerImpl) RunCommand(ctx context.Context, name, command string, timeoutSeconds int, env CommandEnv) error {
+	_, err := r.RunCommandOutput(ctx, name, command, timeoutSeconds, env)
+	return err
+}
+
+// RunCommandOutput runs a shell command like RunCommand and also returns its combined output
+func (r *CommandRunnerImpl) RunCommandOutput(ctx context.Context, name, command string, timeoutSeconds int, env CommandEnv) (string, error) {
+	timeout := defaultCommandTimeout
+	if timeoutSeconds > 0 {
+		timeout = time.Duration(timeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = env.RepoDir
+	cmd.Env = append(os.Environ(),
+		"TICKET_KEY="+env.TicketKey,
+		"REPO_DIR="+env.RepoDir,
+		"COMPONENT="+env.Component,
+		"BRANCH="+env.Branch,
+		"PR_URL="+env.PRURL,
+		"CHANGED_FILES="+strings.Join(env.ChangedFiles, " "),
+	)
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	// Don't wait for children of a killed command that still hold the output open
+	cmd.WaitDelay = time.Second
+
+	r.logger.Info("Running command",
+		zap.String("ticket", env.TicketKey),
+		zap.String("name", name),
+		zap.String("command", command))
+
+	start := time.Now()
+	err := cmd.Run()
+	duration := time.Since(start)
+
+	exitCode := 0
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("%s timed out after %s", name, timeout)
+	} else if err != nil {
+		err = fmt.Errorf("%s failed: %w, output: %s", name, err, tailString(output.String(), maxCommandErrorOutput))
+	}
+
+	entry := models.AuditEntry{
+		Ticket:     env.TicketKey,
+		Action:     "command",
+		Name:       name,
+		Command:    command,
+		ExitCode:   exitCode,
+		DurationMS: duration.Milliseconds(),
+		Output:     tailString(output.String(), maxCommandAuditOutput),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if auditErr := r.auditLog.Record(entry); auditErr != nil {
+		r.logger.Warn("Failed to record command in audit log", zap.String("ticket", env.TicketKey), zap.Error(auditErr))
+	}
+
+	if err != nil {
+		return output.String(), err
+	}
+
+	r.logger.Debug("Command finished",
+		zap.String("ticket", env.TicketKey),
+		zap.String("name", name),
+		zap.Duration("duration", duration))
+	return output.String(), nil
+}
+
+// tailString returns at most the last n bytes of s
+func tailString(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return "..." + s[len(s)-n:]
+}
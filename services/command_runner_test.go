@@ -0,0 +1,86 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestCommandRunner_RunHooks(t *testing.T) {
+	repoDir := t.TempDir()
+	config := &models.Config{}
+	config.AuditLog = filepath.Join(t.TempDir(), "audit.log")
+	config.Hooks = models.PipelineHooks{
+		models.HookPrePush: {
+			{Name: "env", Command: `echo "$TICKET_KEY $BRANCH $PR_URL" > env.txt`},
+			{Command: "echo checking; exit 2"},
+			{Name: "never", Command: "touch never.txt"},
+		},
+		models.HookPreGenerate: {{Command: "touch global.txt"}},
+	}
+	config.Components = map[string]models.ComponentConfig{
+		"backend": {Hooks: models.PipelineHooks{models.HookPreGenerate: {{Command: "touch backend.txt"}}}},
+	}
+	runner := NewCommandRunner(config, zap.NewNop())
+
+	env := CommandEnv{TicketKey: "TEST-1", RepoDir: repoDir, Component: "frontend", Branch: "TEST-1", PRURL: "https://github.com/example/repo/pull/1"}
+	err := runner.RunHooks(context.Background(), models.HookPrePush, env)
+	if err == nil || !strings.Contains(err.Error(), "pre_push hook failed: pre_push[2] failed") || !strings.Contains(err.Error(), "checking") {
+		t.Fatalf("Expected the second hook to fail with its output, got %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(repoDir, "env.txt"))
+	if err != nil || strings.TrimSpace(string(data)) != "TEST-1 TEST-1 https://github.com/example/repo/pull/1" {
+		t.Errorf("Expected the hook to see the ticket environment, got %q (%v)", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(repoDir, "never.txt")); err == nil {
+		t.Error("Expected the hooks after the failing hook not to run")
+	}
+
+	// Components replace the global commands of the hook points they configure
+	env.Component = "backend"
+	if err := runner.RunHooks(context.Background(), models.HookPreGenerate, env); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(repoDir, "backend.txt")); err != nil {
+		t.Error("Expected the component hook to run")
+	}
+	if _, err := os.Stat(filepath.Join(repoDir, "global.txt")); err == nil {
+		t.Error("Expected the global hook to be replaced by the component hook")
+	}
+
+	file, err := os.Open(config.AuditLog)
+	if err != nil {
+		t.Fatalf("Failed to open audit log: %v", err)
+	}
+	defer file.Close()
+
+	var entries []models.AuditEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry models.AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Invalid audit log line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 audit entries, got %d", len(entries))
+	}
+	failed := entries[1]
+	if failed.Ticket != "TEST-1" || failed.Action != "command" || failed.Name != "pre_push[2]" || failed.ExitCode != 2 ||
+		strings.TrimSpace(failed.Output) != "checking" || failed.Error == "" {
+		t.Errorf("Unexpected audit entry for the failing hook: %+v", failed)
+	}
+	if entries[0].ExitCode != 0 || entries[0].Error != "" {
+		t.Errorf("Expected a successful audit entry for the first hook, got %+v", entries[0])
+	}
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// SetCommitAuthor sets the author of later commits in a local repository; nil authors them as the bot.
+// The bot stays the committer, since it is configured as the git user of the checkout.
+func (s *GitHubServiceImpl) SetCommitAuthor(directory string, author *models.GitIdentity) {
+	if author == nil {
+		s.authors.Delete(directory)
+		return
+	}
+	s.authors.Store(directory, *author)
+}
+
+// resolveCommitAuthor returns the author of the ticket's commits, or nil to author them as the bot.
+// In reporter mode, reporters without a mapping fall back to the configured identity, then to the bot.
+func resolveCommitAuthor(config *models.Config, logger *zap.Logger, ticket *models.JiraTicketResponse, component string) *models.GitIdentity {
+	settings := config.GetCommitAuthor(component)
+
+	switch settings.Mode {
+	case models.CommitAuthorIdentity:
+		return &settings.Identity
+	case models.CommitAuthorReporter:
+		reporter := ticket.Fields.Reporter
+		for _, key := range []string{reporter.EmailAddress, reporter.Name, reporter.ID} {
+			if identity, ok := settings.Reporters[key]; ok && key != "" {
+				return &identity
+			}
+		}
+		logger.Info("Reporter has no git identity mapped",
+			zap.String("ticket", ticket.Key),
+			zap.String("reporter", reporter.DisplayName))
+		if settings.Identity.IsSet() {
+			return &settings.Identity
+		}
+	}
+	return nil
+}
+
+// feedbackCommitAuthor returns the author of feedback commits of the ticket, or nil to author them as the bot.
+// The ticket is only looked up when its reporter is needed; if the lookup fails the reporter is treated as unmapped.
+func (p *PRReviewProcessorImpl) feedbackCommitAuthor(ctx context.Context, ticketKey, component string) *models.GitIdentity {
+	ticket := &models.JiraTicketResponse{Key: ticketKey}
+	if p.config.GetCommitAuthor(component).Mode == models.CommitAuthorReporter {
+		fetched, err := p.jiraService.GetTicket(ctx, ticketKey)
+		if err != nil {
+			p.logger.Warn("Failed to get ticket reporter for the commit author", zap.String("ticket", ticketKey), zap.Error(err))
+		} else {
+			ticket = fetched
+		}
+	}
+	return resolveCommitAuthor(p.config, p.logger, ticket, component)
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestResolveCommitAuthor(t *testing.T) {
+	service := models.GitIdentity{Name: "Payments Team", Email: "payments@example.com"}
+	jane := models.GitIdentity{Name: "Jane Doe", Email: "jane@users.noreply.github.com"}
+
+	tests := []struct {
+		name     string
+		settings models.CommitAuthorConfig
+		reporter models.JiraUser
+		want     *models.GitIdentity
+	}{
+		{
+			name:     "bot",
+			settings: models.CommitAuthorConfig{},
+			want:     nil,
+		},
+		{
+			name:     "identity",
+			settings: models.CommitAuthorConfig{Mode: models.CommitAuthorIdentity, Identity: service},
+			want:     &service,
+		},
+		{
+			name:     "reporter by email",
+			settings: models.CommitAuthorConfig{Mode: models.CommitAuthorReporter, Reporters: map[string]models.GitIdentity{"jane@example.com": jane}},
+			reporter: models.JiraUser{EmailAddress: "jane@example.com"},
+			want:     &jane,
+		},
+		{
+			name:     "reporter by account ID",
+			settings: models.CommitAuthorConfig{Mode: models.CommitAuthorReporter, Reporters: map[string]models.GitIdentity{"5b10ac8d82e05b22cc7d4ef5": jane}},
+			reporter: models.JiraUser{ID: "5b10ac8d82e05b22cc7d4ef5"},
+			want:     &jane,
+		},
+		{
+			name:     "unmapped reporter falls back to the identity",
+			settings: models.CommitAuthorConfig{Mode: models.CommitAuthorReporter, Identity: service, Reporters: map[string]models.GitIdentity{"jane@example.com": jane}},
+			reporter: models.JiraUser{EmailAddress: "john@example.com"},
+			want:     &service,
+		},
+		{
+			name:     "unmapped reporter without identity falls back to the bot",
+			settings: models.CommitAuthorConfig{Mode: models.CommitAuthorReporter},
+			reporter: models.JiraUser{EmailAddress: "john@example.com"},
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &models.Config{}
+			config.Components = map[string]models.ComponentConfig{"payments": {CommitAuthor: &tt.settings}}
+			ticket := &models.JiraTicketResponse{Key: "PAY-1", Fields: models.JiraFields{Reporter: tt.reporter}}
+
+			got := resolveCommitAuthor(config, zap.NewNop(), ticket, "payments")
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTicketProcessor_CommitAuthor(t *testing.T) {
+	var authors []*models.GitIdentity
+	var committedBy *models.GitIdentity
+	githubService := &mocks.MockGitHubService{
+		SetCommitAuthorFunc: func(directory string, author *models.GitIdentity) {
+			authors = append(authors, author)
+		},
+		CommitChangesFunc: func(directory, message string) error {
+			committedBy = authors[len(authors)-1]
+			return nil
+		},
+		CreatePullRequestFunc: func(owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error) {
+			return &models.GitHubCreatePRResponse{Number: 1}, nil
+		},
+	}
+	processor, _, _ := newPipelineTestProcessor(t, githubService, &mocks.MockJiraService{}, nil)
+	processor.(*TicketProcessorImpl).config.GitHub.CommitAuthor = models.CommitAuthorConfig{
+		Mode:     models.CommitAuthorIdentity,
+		Identity: models.GitIdentity{Name: "Frontend Team", Email: "frontend@example.com"},
+	}
+
+	if err := processor.ProcessTicket(context.Background(), "TEST-1"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if committedBy == nil || committedBy.Name != "Frontend Team" {
+		t.Errorf("Expected the commit to be authored by the service identity, got %v", committedBy)
+	}
+	if len(authors) != 2 || authors[1] != nil {
+		t.Errorf("Expected the author to be reset once the ticket leaves the pipeline, got %v", authors)
+	}
+}
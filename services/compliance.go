@@ -0,0 +1,113 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// dcoMarkerFiles indicate that a repository enforces the Developer Certificate of Origin
+var dcoMarkerFiles = []string{".github/dco.yml", ".dco.yml", "DCO", "DCO.md"}
+
+// claMarkerFiles indicate that a repository requires a signed Contributor License Agreement
+var claMarkerFiles = []string{".clabot", ".github/cla.yml", ".cla.yml", "CLA.md", "CLA"}
+
+// contributingFiles are the locations of contribution guidelines scanned for CLA/DCO requirements
+var contributingFiles = []string{"CONTRIBUTING.md", "CONTRIBUTING", ".github/CONTRIBUTING.md", "docs/CONTRIBUTING.md"}
+
+// ComplianceResult describes how a contribution to a repository has to be made
+type ComplianceResult struct {
+	// SignOff is true when commits need a DCO Signed-off-by trailer
+	SignOff bool
+}
+
+// ComplianceChecker defines the interface for checking the contribution requirements of a repository
+type ComplianceChecker interface {
+	// CheckCompliance inspects a cloned repository for CLA/DCO requirements.
+	// It returns an error explaining why when the bot must not contribute to the repository.
+	CheckCompliance(owner, repo, repoDir string) (*ComplianceResult, error)
+}
+
+// ComplianceCheckerImpl implements the ComplianceChecker interface
+type ComplianceCheckerImpl struct {
+	config *models.Config
+	logger *zap.Logger
+}
+
+// NewComplianceChecker creates a new ComplianceChecker
+func NewComplianceChecker(config *models.Config, logger *zap.Logger) ComplianceChecker {
+	return &ComplianceCheckerImpl{
+		config: config,
+		logger: logger,
+	}
+}
+
+// CheckCompliance inspects a cloned repository for CLA/DCO requirements
+func (c *ComplianceCheckerImpl) CheckCompliance(owner, repo, repoDir string) (*ComplianceResult, error) {
+	mode := c.config.GetComplianceMode(owner, repo)
+	switch mode {
+	case models.ComplianceModeSkip:
+		return &ComplianceResult{}, nil
+	case models.ComplianceModeSignOff:
+		return &ComplianceResult{SignOff: true}, nil
+	case models.ComplianceModeBlock:
+		return nil, fmt.Errorf("automatic contributions to %s/%s are blocked by configuration", owner, repo)
+	}
+
+	requiresDCO, requiresCLA := detectContributionRequirements(repoDir)
+	c.logger.Info("Detected contribution requirements",
+		zap.String("owner", owner),
+		zap.String("repo", repo),
+		zap.Bool("dco", requiresDCO),
+		zap.Bool("cla", requiresCLA))
+
+	if requiresCLA && !c.config.IsCLASigned(owner, repo) {
+		return nil, fmt.Errorf("%s/%s requires a signed Contributor License Agreement. "+
+			"Sign the CLA with the bot account (%s) and add %s/%s to github.compliance.cla_signed to allow contributions",
+			owner, repo, c.config.GitHub.BotUsername, owner, repo)
+	}
+
+	return &ComplianceResult{SignOff: requiresDCO}, nil
+}
+
+// detectContributionRequirements looks for DCO and CLA markers in a cloned repository
+func detectContributionRequirements(repoDir string) (requiresDCO, requiresCLA bool) {
+	requiresDCO = anyFileExists(repoDir, dcoMarkerFiles)
+	requiresCLA = anyFileExists(repoDir, claMarkerFiles)
+
+	for _, name := range contributingFiles {
+		data, err := os.ReadFile(filepath.Join(repoDir, name))
+		if err != nil {
+			continue
+		}
+		content := strings.ToLower(string(data))
+		if strings.Contains(content, "developer certificate of origin") || strings.Contains(content, "signed-off-by") {
+			requiresDCO = true
+		}
+		if strings.Contains(content, "contributor license agreement") {
+			requiresCLA = true
+		}
+	}
+
+	return requiresDCO, requiresCLA
+}
+
+// anyFileExists reports whether any of the given paths exists in a directory
+func anyFileExists(dir string, names []string) bool {
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// withSignOff appends a DCO Signed-off-by trailer for the bot to a commit message
+func withSignOff(message string, config *models.Config) string {
+	return fmt.Sprintf("%s\n\nSigned-off-by: %s <%s>", message, config.GitHub.BotUsername, config.GitHub.BotEmail)
+}
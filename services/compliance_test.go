@@ -0,0 +1,131 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func writeRepoFile(t *testing.T, repoDir, name, content string) {
+	t.Helper()
+	path := filepath.Join(repoDir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+}
+
+func TestComplianceChecker_CheckCompliance(t *testing.T) {
+	tests := []struct {
+		name            string
+		files           map[string]string
+		mode            models.ComplianceMode
+		claSigned       []string
+		expectedSignOff bool
+		expectError     bool
+	}{
+		{
+			name:  "skip mode ignores requirements",
+			files: map[string]string{".clabot": "{}"},
+			mode:  models.ComplianceModeSkip,
+		},
+		{
+			name:            "dco app config requires sign-off",
+			files:           map[string]string{".github/dco.yml": "require:\n  members: false\n"},
+			mode:            models.ComplianceModeAuto,
+			expectedSignOff: true,
+		},
+		{
+			name:            "contributing guide mentioning DCO requires sign-off",
+			files:           map[string]string{"CONTRIBUTING.md": "All commits must include a Signed-off-by line."},
+			mode:            models.ComplianceModeAuto,
+			expectedSignOff: true,
+		},
+		{
+			name:        "unsigned CLA blocks contribution",
+			files:       map[string]string{"CONTRIBUTING.md": "You must sign our Contributor License Agreement."},
+			mode:        models.ComplianceModeAuto,
+			expectError: true,
+		},
+		{
+			name:      "signed CLA allows contribution",
+			files:     map[string]string{".clabot": "{}"},
+			mode:      models.ComplianceModeAuto,
+			claSigned: []string{"upstream/project"},
+		},
+		{
+			name:            "signoff mode always signs off",
+			mode:            models.ComplianceModeSignOff,
+			expectedSignOff: true,
+		},
+		{
+			name:        "block mode blocks contribution",
+			mode:        models.ComplianceModeBlock,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoDir := t.TempDir()
+			for name, content := range tt.files {
+				writeRepoFile(t, repoDir, name, content)
+			}
+
+			config := &models.Config{}
+			config.GitHub.BotUsername = "ai-bot"
+			config.GitHub.Compliance.Mode = tt.mode
+			config.GitHub.Compliance.CLASigned = tt.claSigned
+
+			result, err := NewComplianceChecker(config, zap.NewNop()).CheckCompliance("upstream", "project", repoDir)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if result.SignOff != tt.expectedSignOff {
+				t.Errorf("Expected SignOff %v, got %v", tt.expectedSignOff, result.SignOff)
+			}
+		})
+	}
+}
+
+func TestComplianceChecker_CLAErrorExplainsFix(t *testing.T) {
+	repoDir := t.TempDir()
+	writeRepoFile(t, repoDir, ".clabot", "{}")
+
+	config := &models.Config{}
+	config.GitHub.BotUsername = "ai-bot"
+	config.GitHub.Compliance.Mode = models.ComplianceModeAuto
+
+	_, err := NewComplianceChecker(config, zap.NewNop()).CheckCompliance("upstream", "project", repoDir)
+	if err == nil {
+		t.Fatal("Expected error for unsigned CLA")
+	}
+	if !strings.Contains(err.Error(), "cla_signed") || !strings.Contains(err.Error(), "ai-bot") {
+		t.Errorf("Expected error to explain how to allow contributions, got: %v", err)
+	}
+}
+
+func TestWithSignOff(t *testing.T) {
+	config := &models.Config{}
+	config.GitHub.BotUsername = "ai-bot"
+	config.GitHub.BotEmail = "ai-bot@example.com"
+
+	message := withSignOff("TEST-1: Fix bug", config)
+	expected := "TEST-1: Fix bug\n\nSigned-off-by: ai-bot <ai-bot@example.com>"
+	if message != expected {
+		t.Errorf("Expected %q, got %q", expected, message)
+	}
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// ConfigReloader applies changes of the configuration file while the application runs
+type ConfigReloader interface {
+	// Reload loads the configuration file and applies its changes. A file changing settings that require a restart
+	// is rejected as a whole.
+	Reload() error
+	// Start starts watching the configuration file for changes
+	Start()
+	// Stop stops watching the configuration file
+	Stop()
+}
+
+// ConfigReloaderImpl implements the ConfigReloader interface by updating the configuration shared by the services
+type ConfigReloaderImpl struct {
+	configPath  string
+	config      *models.Config // Shared with the services; reloaded settings are updated in place
+	maintenance MaintenanceService
+	metrics     Metrics
+	logger      *zap.Logger
+
+	reloadMu sync.Mutex
+	loaded   *models.Config // The configuration as last loaded, without startup changes such as bootstrapped CLI paths
+	modTime  time.Time      // Modification time of the configuration file when it was last checked
+
+	mu        sync.Mutex
+	stopChan  chan struct{} // Closed to stop the current watch loop
+	doneChan  chan struct{} // Closed once the current watch loop returned
+	isRunning bool
+}
+
+// NewConfigReloader creates a new ConfigReloader for the configuration file. The loaded configuration is compared to
+// the file to find its changes; the shared one receives them.
+func NewConfigReloader(
+	configPath string,
+	config *models.Config,
+	loaded *models.Config,
+	maintenance MaintenanceService,
+	metrics Metrics,
+	logger *zap.Logger,
+) ConfigReloader {
+	r := &ConfigReloaderImpl{
+		configPath:  configPath,
+		config:      config,
+		maintenance: maintenance,
+		metrics:     metrics,
+		logger:      logger,
+		loaded:      loaded,
+	}
+	if info, err := os.Stat(configPath); err == nil {
+		r.modTime = info.ModTime()
+	}
+	return r
+}
+
+// Reload loads the configuration file and applies its changes. A file changing settings that require a restart
+// is rejected as a whole.
+func (r *ConfigReloaderImpl) Reload() error {
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+
+	err := r.reload()
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	r.metrics.IncCounter("config_reloads_total", map[string]string{"result": result})
+	return err
+}
+
+// reload validates the configuration file and applies its changes if all of them can be applied
+func (r *ConfigReloaderImpl) reload() error {
+	next, err := models.LoadConfig(r.configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if !next.HasRepoMappings() {
+		return errors.New("at least one component_to_repo mapping, repo_mappings entry, repo_project_property or default_repo is required")
+	}
+	if unsafe := r.loaded.UnsafeChanges(next); len(unsafe) > 0 {
+		return fmt.Errorf("changed settings require a restart: %s", strings.Join(unsafe, ", "))
+	}
+
+	changes := r.loaded.ReloadableChanges(next)
+	if len(changes) == 0 {
+		r.logger.Info("Configuration is unchanged", zap.String("path", r.configPath))
+		return nil
+	}
+
+	// The scanners and the janitor read their intervals when they start; tickets being processed read the other
+	// settings under the configuration's lock
+	r.maintenance.Paused(func() {
+		r.config.ApplyReloadable(next)
+	})
+	r.loaded = next
+	r.logger.Info("Reloaded configuration", zap.String("path", r.configPath), zap.Strings("changed", changes))
+	return nil
+}
+
+// Start starts watching the configuration file for changes
+func (r *ConfigReloaderImpl) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.isRunning {
+		r.logger.Info("Configuration watcher is already running")
+		return
+	}
+
+	r.isRunning = true
+	r.stopChan = make(chan struct{})
+	r.doneChan = make(chan struct{})
+	r.logger.Info("Watching configuration file for changes", zap.String("path", r.configPath))
+
+	stopChan, doneChan := r.stopChan, r.doneChan
+	go func() {
+		defer close(doneChan)
+		ticker := time.NewTicker(time.Duration(r.config.ConfigReload.IntervalSeconds) * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				r.reloadIfChanged()
+			case <-stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops watching the configuration file
+func (r *ConfigReloaderImpl) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.isRunning {
+		return
+	}
+
+	r.isRunning = false
+	close(r.stopChan)
+	<-r.doneChan
+}
+
+// reloadIfChanged reloads the configuration file if it was modified since it was last checked. A rejected file
+// isn't retried until it's modified again.
+func (r *ConfigReloaderImpl) reloadIfChanged() {
+	info, err := os.Stat(r.configPath)
+	if err != nil {
+		r.logger.Warn("Failed to check configuration file", zap.String("path", r.configPath), zap.Error(err))
+		return
+	}
+	if info.ModTime().Equal(r.modTime) {
+		return
+	}
+	r.modTime = info.ModTime()
+
+	if err := r.Reload(); err != nil {
+		r.logger.Error("Rejected configuration change", zap.String("path", r.configPath), zap.Error(err))
+	}
+}
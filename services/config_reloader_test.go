@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// reloaderTestConfig is a valid configuration file with placeholders for the repository and target branch
+const reloaderTestConfig = `
+logging:
+  level: info
+  format: console
+ai_provider: "claude"
+jira:
+  interval_seconds: %INTERVAL%
+  status_transitions:
+    todo: "To Do"
+    in_progress: "In Progress"
+    in_review: "In Review"
+github:
+  target_branch: %BRANCH%
+component_to_repo:
+  backend: %REPO%
+`
+
+// writeReloaderTestConfig writes the configuration file with the given values
+func writeReloaderTestConfig(t *testing.T, path, interval, branch, repo string) {
+	t.Helper()
+	content := strings.NewReplacer("%INTERVAL%", interval, "%BRANCH%", branch, "%REPO%", repo).Replace(reloaderTestConfig)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTestConfigReloader(t *testing.T) (*ConfigReloaderImpl, *models.Config, *fakePausable, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeReloaderTestConfig(t, path, "300", "main", "https://github.com/example/backend.git")
+	config, err := models.LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := *config
+
+	scanner := &fakePausable{}
+	maintenance := NewMaintenanceService([]Pausable{scanner}, zap.NewNop())
+	reloader := NewConfigReloader(path, config, &loaded, maintenance, NewMetrics(), zap.NewNop()).(*ConfigReloaderImpl)
+	return reloader, config, scanner, path
+}
+
+func TestConfigReloader_AppliesReloadableChanges(t *testing.T) {
+	reloader, config, scanner, path := newTestConfigReloader(t)
+	writeReloaderTestConfig(t, path, "60", "main", "https://github.com/example/api.git")
+
+	if err := reloader.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if config.Jira.IntervalSeconds != 60 || config.ComponentToRepo["backend"] != "https://github.com/example/api.git" {
+		t.Errorf("Expected the changes to be applied, got interval %d and mapping %v", config.Jira.IntervalSeconds, config.ComponentToRepo)
+	}
+	if scanner.stops != 1 || scanner.starts != 1 {
+		t.Errorf("Expected the services to be restarted once, got %d stops and %d starts", scanner.stops, scanner.starts)
+	}
+}
+
+func TestConfigReloader_RejectsUnsafeChanges(t *testing.T) {
+	reloader, config, scanner, path := newTestConfigReloader(t)
+	writeReloaderTestConfig(t, path, "60", "develop", "https://github.com/example/api.git")
+
+	err := reloader.Reload()
+	if err == nil || !strings.Contains(err.Error(), "github.target_branch") {
+		t.Fatalf("Reload() error = %v, want an error naming github.target_branch", err)
+	}
+	if config.Jira.IntervalSeconds != 300 || config.ComponentToRepo["backend"] != "https://github.com/example/backend.git" {
+		t.Errorf("Expected no change to be applied, got interval %d and mapping %v", config.Jira.IntervalSeconds, config.ComponentToRepo)
+	}
+	if scanner.stops != 0 {
+		t.Errorf("Expected the services to keep running, got %d stops", scanner.stops)
+	}
+}
+
+func TestConfigReloader_RejectsInvalidFile(t *testing.T) {
+	reloader, config, _, path := newTestConfigReloader(t)
+	if err := os.WriteFile(path, []byte("logging: [\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := reloader.Reload(); err == nil {
+		t.Fatal("Reload() error = nil, want an error")
+	}
+	if config.Jira.IntervalSeconds != 300 {
+		t.Errorf("Expected no change to be applied, got interval %d", config.Jira.IntervalSeconds)
+	}
+}
+
+func TestConfigReloader_IgnoresStartupChanges(t *testing.T) {
+	reloader, config, scanner, _ := newTestConfigReloader(t)
+	// Bootstrapping the AI CLIs changes the shared configuration after it was loaded
+	config.Claude.CLIPath = "/cache/claude"
+
+	if err := reloader.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if config.Claude.CLIPath != "/cache/claude" || scanner.stops != 0 {
+		t.Errorf("Expected the unchanged file to change nothing, got CLI path %s and %d stops", config.Claude.CLIPath, scanner.stops)
+	}
+}
+
+func TestConfigReloader_ReloadWhileProcessing(t *testing.T) {
+	reloader, config, _, path := newTestConfigReloader(t)
+	dir := t.TempDir()
+	config.TempDir = dir
+	config.StateFile = filepath.Join(dir, "ticket-states.json")
+	config.FeedbackWatermarkFile = filepath.Join(dir, "feedback-watermarks.json")
+	config.UsageHistoryFile = filepath.Join(dir, "ai-usage.log")
+	config.AuditLog = filepath.Join(dir, "audit.log")
+	config.AI.DocsBootstrapFile = filepath.Join(dir, "docs-bootstrap.json")
+	config.AI.Precedents.IndexFile = filepath.Join(dir, "bot-changes.log")
+	config.AI.SessionSummaries.File = filepath.Join(dir, "ai-sessions.log")
+	config.Jira.DisableErrorComments = true
+
+	mockJira := &mocks.MockJiraService{
+		GetTicketFunc: func(key string) (*models.JiraTicketResponse, error) {
+			return &models.JiraTicketResponse{
+				Key: key,
+				Fields: models.JiraFields{
+					Summary:    "Test ticket",
+					Components: []models.JiraComponent{{ID: "1", Name: "backend"}},
+				},
+			}, nil
+		},
+	}
+	var mu sync.Mutex
+	prRepos := make(map[string]string) // Head branch -> repository the pull request was opened in
+	mockGitHub := &mocks.MockGitHubService{
+		CheckForkExistsFunc: func(owner, repo string) (bool, string, error) {
+			return true, "https://github.com/test-bot/" + repo + ".git", nil
+		},
+		CreatePullRequestFunc: func(owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			prRepos[head] = repo
+			return &models.GitHubCreatePRResponse{Number: len(prRepos), HTMLURL: fmt.Sprintf("https://github.com/example/%s/pull/%d", repo, len(prRepos))}, nil
+		},
+	}
+	stateMachine := newTestStateMachine(config)
+	processor := NewTicketProcessor(mockJira, mockGitHub, &mocks.MockClaudeService{}, stateMachine, config, zap.NewNop())
+
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = processor.ProcessTicket(context.Background(), fmt.Sprintf("TEST-%d", i))
+		}()
+	}
+	for i := 0; i < 5; i++ {
+		writeReloaderTestConfig(t, path, fmt.Sprint(60+i), "main", fmt.Sprintf("https://github.com/example/api%d.git", i))
+		if err := reloader.Reload(); err != nil {
+			t.Errorf("Reload() error = %v", err)
+		}
+	}
+	wg.Wait()
+
+	if config.Jira.IntervalSeconds != 64 || config.ComponentToRepo["backend"] != "https://github.com/example/api4.git" {
+		t.Errorf("Expected the last reload to be applied, got interval %d and mapping %v", config.Jira.IntervalSeconds, config.ComponentToRepo)
+	}
+
+	// Every ticket opened its pull request in a repository of the original or a reloaded mapping
+	repos := map[string]bool{"backend": true, "api0": true, "api1": true, "api2": true, "api3": true, "api4": true}
+	for i, err := range errs {
+		ticketKey := fmt.Sprintf("TEST-%d", i)
+		if err != nil {
+			t.Errorf("Expected %s to be processed, got %v", ticketKey, err)
+		}
+		if state, _ := stateMachine.State(ticketKey); state != models.TicketStatePROpen {
+			t.Errorf("Expected %s to have an open pull request, got state %s", ticketKey, state)
+		}
+	}
+	if len(prRepos) != 5 {
+		t.Errorf("Expected a pull request per ticket, got %v", prRepos)
+	}
+	for head, repo := range prRepos {
+		if !repos[repo] {
+			t.Errorf("Expected the pull request of %s in a configured repository, got %s", head, repo)
+		}
+	}
+}
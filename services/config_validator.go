@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// ConfigValidator checks a configuration before it's deployed
+type ConfigValidator interface {
+	// Validate checks the required settings and repository mappings, and resolves the configured field names against
+	// Jira unless the Jira service is nil
+	Validate(ctx context.Context) *models.ValidationReport
+}
+
+// ConfigValidatorImpl implements the ConfigValidator interface
+type ConfigValidatorImpl struct {
+	jiraService JiraService // nil to skip the checks against Jira
+	config      *models.Config
+	logger      *zap.Logger
+}
+
+// NewConfigValidator creates a new ConfigValidator. The Jira service may be nil to validate without contacting Jira.
+func NewConfigValidator(jiraService JiraService, config *models.Config, logger *zap.Logger) ConfigValidator {
+	return &ConfigValidatorImpl{
+		jiraService: jiraService,
+		config:      config,
+		logger:      logger,
+	}
+}
+
+// Validate checks the required settings and repository mappings, and resolves the configured field names against
+// Jira unless the Jira service is nil
+func (v *ConfigValidatorImpl) Validate(ctx context.Context) *models.ValidationReport {
+	report := &models.ValidationReport{}
+	report.Checks = append(report.Checks, v.checkRequired()...)
+	report.Checks = append(report.Checks, v.checkKeys()...)
+	report.Checks = append(report.Checks, v.checkRepositories()...)
+	report.Checks = append(report.Checks, v.checkJiraFields(ctx)...)
+	return report
+}
+
+// checkRequired checks that the settings the application needs to start are set
+func (v *ConfigValidatorImpl) checkRequired() []models.ValidationCheck {
+	var checks []models.ValidationCheck
+	for _, setting := range v.config.MissingSettings() {
+		checks = append(checks, models.ValidationCheck{
+			Setting: setting,
+			Result:  models.ValidationResultFailed,
+			Detail:  fmt.Sprintf("required; set it in the file or as %s", models.EnvVarName(setting)),
+		})
+	}
+	return checks
+}
+
+// checkKeys checks that the GitHub App private key and the commit signing key can be used
+func (v *ConfigValidatorImpl) checkKeys() []models.ValidationCheck {
+	var checks []models.ValidationCheck
+	if v.config.GitHub.AuthMode == models.GitHubAuthModeApp {
+		check := models.ValidationCheck{Setting: "github.private_key_path", Result: models.ValidationResultOK}
+		if _, err := NewGitHubAppService(v.config, v.logger); err != nil {
+			check.Result = models.ValidationResultFailed
+			check.Detail = err.Error()
+		}
+		checks = append(checks, check)
+	}
+	if v.config.GitHub.Signing.Format != models.CommitSigningNone {
+		check := models.ValidationCheck{Setting: "github.signing.key_file", Result: models.ValidationResultOK}
+		if _, err := os.Stat(v.config.GitHub.Signing.KeyFile); err != nil {
+			check.Result = models.ValidationResultFailed
+			check.Detail = err.Error()
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// checkRepositories checks that the repository of every mapping is a GitHub repository URL
+func (v *ConfigValidatorImpl) checkRepositories() []models.ValidationCheck {
+	components := make([]string, 0, len(v.config.ComponentToRepo))
+	for component := range v.config.ComponentToRepo {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+
+	var checks []models.ValidationCheck
+	for _, component := range components {
+		checks = append(checks, checkRepository("component_to_repo."+component, v.config.ComponentToRepo[component]))
+	}
+	for i, mapping := range v.config.RepoMappings {
+		checks = append(checks, checkRepository(fmt.Sprintf("repo_mappings[%d].repo", i), mapping.Repo))
+	}
+	if v.config.DefaultRepo != "" {
+		checks = append(checks, checkRepository("default_repo", v.config.DefaultRepo))
+	}
+	return checks
+}
+
+// checkRepository checks that a repository URL is a GitHub repository URL
+func checkRepository(setting, repoURL string) models.ValidationCheck {
+	check := models.ValidationCheck{Setting: setting, Result: models.ValidationResultOK}
+	if owner, repo, err := ExtractRepoInfo(repoURL); err != nil {
+		check.Result = models.ValidationResultFailed
+		check.Detail = err.Error()
+	} else {
+		check.Detail = owner + "/" + repo
+	}
+	return check
+}
+
+// checkJiraFields resolves the configured field names to the IDs of Jira fields
+func (v *ConfigValidatorImpl) checkJiraFields(ctx context.Context) []models.ValidationCheck {
+	type field struct{ setting, name string }
+	var fields []field
+	if v.config.Jira.GitPullRequestFieldName != "" {
+		fields = append(fields, field{"jira.git_pull_request_field_name", v.config.Jira.GitPullRequestFieldName})
+	}
+	if v.config.Jira.TargetBranchFieldName != "" {
+		fields = append(fields, field{"jira.target_branch_field_name", v.config.Jira.TargetBranchFieldName})
+	}
+	for _, name := range v.config.Jira.PromptFields {
+		fields = append(fields, field{"jira.prompt_fields." + name, name})
+	}
+
+	checks := make([]models.ValidationCheck, 0, len(fields))
+	for _, field := range fields {
+		check := models.ValidationCheck{Setting: field.setting, Result: models.ValidationResultOK}
+		if v.jiraService == nil {
+			check.Result = models.ValidationResultSkipped
+			check.Detail = "Jira wasn't contacted"
+			checks = append(checks, check)
+			continue
+		}
+
+		fieldID, err := v.jiraService.GetFieldIDByName(ctx, field.name)
+		if err != nil {
+			v.logger.Warn("Failed to resolve Jira field", zap.String("field", field.name), zap.Error(err))
+			check.Result = models.ValidationResultFailed
+			check.Detail = err.Error()
+		} else {
+			check.Detail = fieldID
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// WriteValidationReport writes a validation report as a human readable table
+func WriteValidationReport(w io.Writer, report *models.ValidationReport) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "SETTING\tRESULT\tDETAIL")
+	for _, check := range report.Checks {
+		fmt.Fprintf(table, "%s\t%s\t%s\n", check.Setting, check.Result, check.Detail)
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	if report.Valid() {
+		fmt.Fprintln(w, "\nThe configuration is valid.")
+	} else {
+		fmt.Fprintln(w, "\nThe configuration is invalid.")
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// newValidatorTestConfig creates a configuration with all required settings
+func newValidatorTestConfig() *models.Config {
+	config := &models.Config{}
+	config.Jira.AuthMode = models.JiraAuthModeToken
+	config.Jira.BaseURL = "https://example.atlassian.net"
+	config.Jira.Username = "bot"
+	config.Jira.APIToken = "token"
+	config.Jira.GitPullRequestFieldName = "Git Pull Request"
+	config.GitHub.AuthMode = models.GitHubAuthModePAT
+	config.GitHub.PersonalAccessToken = "token"
+	config.GitHub.BotUsername = "ai-bot"
+	config.GitHub.BotEmail = "ai-bot@example.com"
+	config.GitHub.Signing.Format = models.CommitSigningNone
+	config.ComponentToRepo = map[string]string{
+		"backend":  "https://github.com/example/backend.git",
+		"frontend": "git@github.com:example/frontend.git",
+	}
+	return config
+}
+
+func TestConfigValidator_Validate(t *testing.T) {
+	jiraService := &mocks.MockJiraService{
+		GetFieldIDByNameFunc: func(fieldName string) (string, error) {
+			return "customfield_10010", nil
+		},
+	}
+
+	report := NewConfigValidator(jiraService, newValidatorTestConfig(), zap.NewNop()).Validate(context.Background())
+	if !report.Valid() {
+		t.Fatalf("Expected a valid configuration, got %+v", report.Checks)
+	}
+
+	want := []models.ValidationCheck{
+		{Setting: "component_to_repo.backend", Result: models.ValidationResultOK, Detail: "example/backend"},
+		{Setting: "component_to_repo.frontend", Result: models.ValidationResultOK, Detail: "example/frontend"},
+		{Setting: "jira.git_pull_request_field_name", Result: models.ValidationResultOK, Detail: "customfield_10010"},
+	}
+	if fmt.Sprint(report.Checks) != fmt.Sprint(want) {
+		t.Errorf("Checks = %+v, want %+v", report.Checks, want)
+	}
+}
+
+func TestConfigValidator_ReportsProblems(t *testing.T) {
+	config := newValidatorTestConfig()
+	config.Jira.APIToken = ""
+	config.ComponentToRepo["api"] = "https://gitlab.com/example/api.git"
+	config.Jira.PromptFields = []string{"Acceptance Criteria"}
+	jiraService := &mocks.MockJiraService{
+		GetFieldIDByNameFunc: func(fieldName string) (string, error) {
+			return "", fmt.Errorf("%w: no field with name '%s'", ErrJiraFieldNotFound, fieldName)
+		},
+	}
+
+	report := NewConfigValidator(jiraService, config, zap.NewNop()).Validate(context.Background())
+	if report.Valid() {
+		t.Fatal("Expected an invalid configuration")
+	}
+
+	failed := map[string]bool{}
+	for _, check := range report.Checks {
+		if check.Result == models.ValidationResultFailed {
+			failed[check.Setting] = true
+		}
+	}
+	for _, setting := range []string{"jira.api_token", "component_to_repo.api", "jira.git_pull_request_field_name", "jira.prompt_fields.Acceptance Criteria"} {
+		if !failed[setting] {
+			t.Errorf("Expected %s to fail, got %+v", setting, report.Checks)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := WriteValidationReport(&out, report); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "JIRA_API_TOKEN") || !strings.Contains(out.String(), "The configuration is invalid.") {
+		t.Errorf("Unexpected report:\n%s", out.String())
+	}
+}
+
+func TestConfigValidator_Offline(t *testing.T) {
+	report := NewConfigValidator(nil, newValidatorTestConfig(), zap.NewNop()).Validate(context.Background())
+	last := report.Checks[len(report.Checks)-1]
+	if last.Setting != "jira.git_pull_request_field_name" || last.Result != models.ValidationResultSkipped {
+		t.Errorf("Expected the field check to be skipped, got %+v", last)
+	}
+	if !report.Valid() {
+		t.Error("Expected skipped checks not to fail the validation")
+	}
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// conflictResolutionMarker prefixes the PR comment recording a conflict resolution attempt for a base commit
+const conflictResolutionMarker = "🤖 AI Conflict Resolution Attempt: "
+
+// maxConflictResolutionSteps bounds the number of conflicted commits resolved in one rebase
+const maxConflictResolutionSteps = 20
+
+var conflictResolutionPattern = regexp.MustCompile(regexp.QuoteMeta(conflictResolutionMarker) + `([0-9a-f]{7,40})`)
+
+// resolveMergeConflicts rebases a conflicted PR onto its target branch, lets the AI resolve the conflicts
+// and force-pushes the branch. Each target branch commit is attempted once, so a failed resolution is
+// retried only after the target branch moves again. It reports whether a resolution was attempted.
+func (p *PRReviewProcessorImpl) resolveMergeConflicts(ctx context.Context, ticketKey, component, owner, repo string, pr *models.GitHubPRDetails) (bool, error) {
+	baseSHA := pr.Base.SHA
+	if baseSHA == "" {
+		return false, nil
+	}
+
+	comments, err := p.githubService.ListPRComments(ctx, owner, repo, pr.Number)
+	if err != nil {
+		return false, fmt.Errorf("failed to get PR comments: %w", err)
+	}
+	if p.findBotMarkers(comments, conflictResolutionPattern)[baseSHA] {
+		p.logger.Debug("Merge conflicts with target branch commit already attempted",
+			zap.String("ticket", ticketKey),
+			zap.String("base_sha", baseSHA))
+		return false, nil
+	}
+
+	forkURL, err := p.getRepositoryURLFromPR(pr)
+	if err != nil {
+		return false, fmt.Errorf("failed to get repository URL from PR: %w", err)
+	}
+
+	// Record the attempt first so a resolution that keeps failing is not retried on every scan
+	commentBody := fmt.Sprintf(`%s%s
+
+This PR conflicts with %s. AI is rebasing it onto the latest %s and resolving the conflicts for ticket %s.`,
+		conflictResolutionMarker, baseSHA, pr.Base.Ref, pr.Base.Ref, ticketKey)
+	if err := p.githubService.AddPRComment(ctx, owner, repo, pr.Number, commentBody); err != nil {
+		return false, fmt.Errorf("failed to record conflict resolution attempt: %w", err)
+	}
+
+	p.logger.Info("Resolving merge conflicts",
+		zap.String("ticket", ticketKey),
+		zap.Int("pr_number", pr.Number),
+		zap.String("base", pr.Base.Ref),
+		zap.String("base_sha", baseSHA))
+
+	var resolved []string
+	err = p.inFeedbackState(ctx, ticketKey, func() error {
+		var err error
+		resolved, err = p.rebaseAndResolve(ctx, ticketKey, component, forkURL, pr)
+		return err
+	})
+	if err != nil {
+		return true, fmt.Errorf("failed to resolve merge conflicts: %w", err)
+	}
+
+	summary := fmt.Sprintf("🤖 Rebased onto the latest %s.", pr.Base.Ref)
+	if len(resolved) > 0 {
+		summary += " AI resolved conflicts in:\n"
+		for _, file := range resolved {
+			summary += fmt.Sprintf("\n- `%s`", file)
+		}
+	}
+	if err := p.githubService.AddPRComment(ctx, owner, repo, pr.Number, summary); err != nil {
+		p.logger.Warn("Failed to comment on conflict resolution", zap.String("ticket", ticketKey), zap.Error(err))
+	}
+
+	p.logger.Info("Successfully resolved merge conflicts",
+		zap.String("ticket", ticketKey),
+		zap.Int("pr_number", pr.Number),
+		zap.Strings("files", resolved))
+	return true, nil
+}
+
+// rebaseAndResolve rebases the PR branch onto the target branch, resolving each conflicted commit with the AI,
+// and force-pushes the result. It returns the files whose conflicts were resolved.
+func (p *PRReviewProcessorImpl) rebaseAndResolve(ctx context.Context, ticketKey, component, forkURL string, pr *models.GitHubPRDetails) ([]string, error) {
+	repoDir, _, cleanup, err := p.checkoutPRBranch(ctx, ticketKey, component, forkURL, pr)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	// Leave the checkout clean if the resolution is given up halfway
+	abort := func(cause error) error {
+		if err := p.githubService.AbortRebase(ctx, repoDir); err != nil {
+			p.logger.Warn("Failed to abort rebase", zap.String("ticket", ticketKey), zap.Error(err))
+		}
+		return cause
+	}
+
+	conflicts, err := p.githubService.RebaseOnto(ctx, repoDir, pr.Base.Repo.Owner.Login, pr.Base.Repo.Name, pr.Base.Ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rebase onto %s: %w", pr.Base.Ref, err)
+	}
+
+	var resolved []string
+	for step := 0; len(conflicts) > 0; step++ {
+		if step >= maxConflictResolutionSteps {
+			return nil, abort(fmt.Errorf("more than %d commits conflict with %s", maxConflictResolutionSteps, pr.Base.Ref))
+		}
+
+		p.logger.Info("Asking AI to resolve conflicts",
+			zap.String("ticket", ticketKey),
+			zap.Strings("files", conflicts))
+
+		if _, err := p.aiService.GenerateCode(ctx, p.generateConflictPrompt(pr, conflicts), repoDir); err != nil {
+			return nil, abort(fmt.Errorf("failed to generate conflict resolution: %w", err))
+		}
+
+		remaining, err := filesWithConflictMarkers(repoDir, conflicts)
+		if err != nil {
+			return nil, abort(err)
+		}
+		if len(remaining) > 0 {
+			return nil, abort(fmt.Errorf("conflict markers left in %s", strings.Join(remaining, ", ")))
+		}
+		resolved = append(resolved, conflicts...)
+
+		conflicts, err = p.githubService.ContinueRebase(ctx, repoDir)
+		if err != nil {
+			return nil, abort(fmt.Errorf("failed to continue rebase: %w", err))
+		}
+	}
+
+	env := CommandEnv{TicketKey: ticketKey, RepoDir: repoDir, Component: component, Branch: pr.Head.Ref, PRURL: pr.HTMLURL}
+	if err := p.commandRunner.RunHooks(ctx, models.HookPrePush, env); err != nil {
+		return nil, err
+	}
+
+	if err := p.githubService.ForcePushChanges(ctx, repoDir, pr.Head.Ref); err != nil {
+		return nil, err
+	}
+
+	return resolved, nil
+}
+
+// generateConflictPrompt generates a prompt for the AI service to resolve rebase conflicts
+func (p *PRReviewProcessorImpl) generateConflictPrompt(pr *models.GitHubPRDetails, conflicts []string) string {
+	var prompt strings.Builder
+
+	prompt.WriteString("You are a developer rebasing a pull request onto the latest target branch. The rebase stopped because of merge conflicts.\n\n")
+	prompt.WriteString("## Pull Request\n")
+	prompt.WriteString(fmt.Sprintf("**Title:** %s\n", pr.Title))
+	prompt.WriteString(fmt.Sprintf("**Description:** %s\n", pr.Body))
+	prompt.WriteString(fmt.Sprintf("**Target branch:** %s\n\n", pr.Base.Ref))
+
+	prompt.WriteString("## Conflicted Files\n")
+	for _, file := range conflicts {
+		prompt.WriteString(fmt.Sprintf("- %s\n", file))
+	}
+	prompt.WriteString("\n")
+
+	prompt.WriteString("## Instructions\n")
+	prompt.WriteString("1. In each conflicted file, the section between `<<<<<<<` and `=======` is the target branch, the section between `=======` and `>>>>>>>` is this PR's change\n")
+	prompt.WriteString("2. Resolve every conflict so the file keeps the intent of this PR on top of the latest target branch\n")
+	prompt.WriteString("3. Remove all conflict markers\n")
+	prompt.WriteString("4. Do not run git commands that commit, continue, skip or abort the rebase; the changes are staged and the rebase is continued for you\n")
+
+	return prompt.String()
+}
+
+// filesWithConflictMarkers returns the files that still contain conflict markers.
+// Files removed while resolving have no conflicts left.
+func filesWithConflictMarkers(repoDir string, files []string) ([]string, error) {
+	var remaining []string
+	for _, file := range files {
+		f, err := os.Open(filepath.Join(repoDir, file))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if strings.HasPrefix(line, "<<<<<<< ") || strings.HasPrefix(line, ">>>>>>> ") {
+				remaining = append(remaining, file)
+				break
+			}
+		}
+		err = scanner.Err()
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+	}
+	return remaining, nil
+}
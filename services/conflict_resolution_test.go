@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// newConflictedPR returns a PR from the bot's fork whose target branch moved to baseSHA
+func newConflictedPR(baseSHA string) *models.GitHubPRDetails {
+	pr := &models.GitHubPRDetails{Number: 7, Title: "TEST-1: Fix", MergeableState: "dirty"}
+	pr.Head.Ref = "TEST-1"
+	pr.Head.Repo.CloneURL = "https://github.com/ai-bot/repo.git"
+	pr.Base.Ref = "main"
+	pr.Base.SHA = baseSHA
+	pr.Base.Repo.Name = "repo"
+	pr.Base.Repo.Owner.Login = "example"
+	return pr
+}
+
+func TestPRReviewProcessor_ResolveMergeConflicts(t *testing.T) {
+	config := newBotConfig("ai-bot")
+	config.TempDir = t.TempDir()
+	config.GitHub.ConflictResolution.Enabled = true
+	repoDir := filepath.Join(config.TempDir, "TEST-1-feedback")
+	if err := os.MkdirAll(repoDir, 0755); err != nil {
+		t.Fatalf("Failed to create repo dir: %v", err)
+	}
+
+	var comments []string
+	var continued, forcePushed bool
+	processor := &PRReviewProcessorImpl{
+		githubService: &mocks.MockGitHubService{
+			AddPRCommentFunc: func(owner, repo string, prNumber int, body string) error {
+				comments = append(comments, body)
+				return nil
+			},
+			RebaseOntoFunc: func(directory, owner, repo, branch string) ([]string, error) {
+				if owner != "example" || repo != "repo" || branch != "main" {
+					t.Errorf("Unexpected rebase target %s/%s:%s", owner, repo, branch)
+				}
+				return []string{"main.go"}, os.WriteFile(filepath.Join(directory, "main.go"), []byte("<<<<<<< HEAD\nconst version = 3\n=======\nconst version = 2\n>>>>>>> Bump\n"), 0644)
+			},
+			ContinueRebaseFunc: func(directory string) ([]string, error) {
+				continued = true
+				return nil, nil
+			},
+			ForcePushChangesFunc: func(directory, branchName string) error {
+				forcePushed = branchName == "TEST-1"
+				return nil
+			},
+		},
+		aiService: &mocks.MockClaudeService{
+			GenerateCodeFunc: func(prompt, repoDir string) (*models.ClaudeResponse, error) {
+				if !strings.Contains(prompt, "- main.go") {
+					t.Errorf("Expected the conflicted file in the prompt, got:\n%s", prompt)
+				}
+				return nil, os.WriteFile(filepath.Join(repoDir, "main.go"), []byte("const version = 4\n"), 0644)
+			},
+		},
+		commandRunner: NewCommandRunner(config, zap.NewNop()),
+		stateMachine:  newTestStateMachine(config),
+		config:        config,
+		logger:        zap.NewNop(),
+	}
+
+	attempted, err := processor.resolveMergeConflicts(context.Background(), "TEST-1", "", "example", "repo", newConflictedPR("abc1234"))
+	if err != nil || !attempted {
+		t.Fatalf("Expected the conflicts to be resolved, got attempted=%v, err=%v", attempted, err)
+	}
+	if !continued || !forcePushed {
+		t.Errorf("Expected the rebase to be continued and force-pushed, continued=%v, force pushed=%v", continued, forcePushed)
+	}
+	if len(comments) != 2 || !strings.HasPrefix(comments[0], conflictResolutionMarker+"abc1234") || !strings.Contains(comments[1], "`main.go`") {
+		t.Errorf("Unexpected PR comments: %q", comments)
+	}
+}
+
+func TestPRReviewProcessor_ResolveMergeConflicts_MarkersLeft(t *testing.T) {
+	config := newBotConfig("ai-bot")
+	config.TempDir = t.TempDir()
+	repoDir := filepath.Join(config.TempDir, "TEST-1-feedback")
+	if err := os.MkdirAll(repoDir, 0755); err != nil {
+		t.Fatalf("Failed to create repo dir: %v", err)
+	}
+
+	var aborted, forcePushed bool
+	processor := &PRReviewProcessorImpl{
+		githubService: &mocks.MockGitHubService{
+			RebaseOntoFunc: func(directory, owner, repo, branch string) ([]string, error) {
+				return []string{"main.go"}, os.WriteFile(filepath.Join(directory, "main.go"), []byte("<<<<<<< HEAD\na\n=======\nb\n>>>>>>> Bump\n"), 0644)
+			},
+			AbortRebaseFunc: func(directory string) error {
+				aborted = true
+				return nil
+			},
+			ForcePushChangesFunc: func(directory, branchName string) error {
+				forcePushed = true
+				return nil
+			},
+		},
+		aiService:    &mocks.MockClaudeService{},
+		stateMachine: newTestStateMachine(config),
+		config:       config,
+		logger:       zap.NewNop(),
+	}
+
+	_, err := processor.resolveMergeConflicts(context.Background(), "TEST-1", "", "example", "repo", newConflictedPR("abc1234"))
+	if err == nil || !strings.Contains(err.Error(), "conflict markers left in main.go") {
+		t.Errorf("Expected an error about leftover conflict markers, got: %v", err)
+	}
+	if !aborted || forcePushed {
+		t.Errorf("Expected the rebase to be aborted without pushing, aborted=%v, force pushed=%v", aborted, forcePushed)
+	}
+}
+
+func TestPRReviewProcessor_ResolveMergeConflicts_AlreadyAttempted(t *testing.T) {
+	processor := &PRReviewProcessorImpl{
+		githubService: &mocks.MockGitHubService{
+			ListPRCommentsFunc: func(owner, repo string, prNumber int) ([]models.GitHubPRComment, error) {
+				return []models.GitHubPRComment{{User: models.GitHubUser{Login: "ai-bot"}, Body: conflictResolutionMarker + "abc1234"}}, nil
+			},
+			RebaseOntoFunc: func(directory, owner, repo, branch string) ([]string, error) {
+				t.Error("Expected no rebase for an already attempted target branch commit")
+				return nil, nil
+			},
+		},
+		config: newBotConfig("ai-bot"),
+		logger: zap.NewNop(),
+	}
+
+	attempted, err := processor.resolveMergeConflicts(context.Background(), "TEST-1", "", "example", "repo", newConflictedPR("abc1234"))
+	if err != nil || attempted {
+		t.Errorf("Expected the resolution to be skipped, got attempted=%v, err=%v", attempted, err)
+	}
+}
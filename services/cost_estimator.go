@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// charsPerToken approximates how many prompt characters make up a token
+const charsPerToken = 4
+
+// CostEstimator projects the AI usage of processing tickets without running the AI
+type CostEstimator interface {
+	// Estimate projects the AI usage of processing the tickets matching the JQL query
+	Estimate(ctx context.Context, jql string) (*models.CostEstimate, error)
+}
+
+// CostEstimatorImpl implements the CostEstimator interface by building the prompts of the tickets and scaling the
+// averages of the AI usage history by their size
+type CostEstimatorImpl struct {
+	jiraService  JiraService
+	repoResolver RepositoryResolver
+	usageHistory UsageHistory
+	config       *models.Config
+	logger       *zap.Logger
+}
+
+// NewCostEstimator creates a new CostEstimator based on the usage history configured in usage_history_file
+func NewCostEstimator(jiraService JiraService, config *models.Config, logger *zap.Logger) CostEstimator {
+	return &CostEstimatorImpl{
+		jiraService:  jiraService,
+		repoResolver: NewRepositoryResolver(jiraService, config, logger),
+		usageHistory: NewUsageHistory(config.UsageHistoryFile),
+		config:       config,
+		logger:       logger,
+	}
+}
+
+// Estimate projects the AI usage of processing the tickets matching the JQL query. Without a usage history only the
+// prompt sizes can be estimated.
+func (e *CostEstimatorImpl) Estimate(ctx context.Context, jql string) (*models.CostEstimate, error) {
+	history, err := e.usageHistory.Load()
+	if err != nil {
+		return nil, err
+	}
+	estimate := &models.CostEstimate{JQL: jql, HistoryRuns: len(history)}
+	if len(history) > 0 {
+		var promptTokens, inputTokens, outputTokens int
+		var costUSD float64
+		for _, record := range history {
+			promptTokens += promptTokenCount(record.PromptChars)
+			inputTokens += record.InputTokens
+			outputTokens += record.OutputTokens
+			costUSD += record.CostUSD
+		}
+		estimate.AveragePromptTokens = promptTokens / len(history)
+		estimate.AverageInputTokens = inputTokens / len(history)
+		estimate.AverageOutputTokens = outputTokens / len(history)
+		estimate.AverageCostUSD = costUSD / float64(len(history))
+	}
+
+	searchResponse, err := e.jiraService.SearchTickets(ctx, jql)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search tickets: %w", err)
+	}
+
+	for _, issue := range searchResponse.Issues {
+		// Search results don't include the comments the prompt is made of
+		ticket, err := e.jiraService.GetTicket(ctx, issue.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ticket %s: %w", issue.Key, err)
+		}
+		ticketEstimate := e.estimateTicket(ctx, estimate, ticket)
+		e.logger.Debug("Estimated ticket",
+			zap.String("ticket", issue.Key),
+			zap.Int("input_tokens", ticketEstimate.InputTokens),
+			zap.Float64("cost_usd", ticketEstimate.CostUSD))
+		estimate.Tickets = append(estimate.Tickets, ticketEstimate)
+		estimate.TotalInputTokens += ticketEstimate.InputTokens
+		estimate.TotalOutputTokens += ticketEstimate.OutputTokens
+		estimate.TotalCostUSD += ticketEstimate.CostUSD
+	}
+	return estimate, nil
+}
+
+// estimateTicket projects the AI usage of a ticket. A run consumes about the input of an average run, plus or minus
+// the difference of its prompt from an average prompt; its cost grows with its tokens.
+func (e *CostEstimatorImpl) estimateTicket(ctx context.Context, estimate *models.CostEstimate, ticket *models.JiraTicketResponse) models.TicketEstimate {
+	ticketEstimate := models.TicketEstimate{Ticket: ticket.Key, Summary: ticket.Fields.Summary}
+
+	// Tickets the processor fails before the AI runs cost nothing
+	component, _, ok := e.repoResolver.Resolve(ctx, ticket)
+	if !ok && component == "" {
+		ticketEstimate.Skipped = "no components"
+		return ticketEstimate
+	}
+	if !ok {
+		ticketEstimate.Skipped = fmt.Sprintf("no repository mapping for component %s", component)
+		return ticketEstimate
+	}
+
+	cloneOptions := e.config.GetCloneOptions(component)
+	prompt := generatePrompt(e.config, ticket) + shallowCloneInstructions(cloneOptions)
+	ticketEstimate.PromptChars = len(prompt)
+	ticketEstimate.PromptTokens = promptTokenCount(len(prompt))
+
+	if estimate.HistoryRuns == 0 {
+		ticketEstimate.InputTokens = ticketEstimate.PromptTokens
+		return ticketEstimate
+	}
+	ticketEstimate.InputTokens = max(estimate.AverageInputTokens+ticketEstimate.PromptTokens-estimate.AveragePromptTokens, ticketEstimate.PromptTokens)
+	ticketEstimate.OutputTokens = estimate.AverageOutputTokens
+	if averageTokens := estimate.AverageInputTokens + estimate.AverageOutputTokens; averageTokens > 0 {
+		ticketTokens := ticketEstimate.InputTokens + ticketEstimate.OutputTokens
+		ticketEstimate.CostUSD = estimate.AverageCostUSD * float64(ticketTokens) / float64(averageTokens)
+	}
+	return ticketEstimate
+}
+
+// promptTokenCount approximates the number of tokens of a prompt with the given number of characters
+func promptTokenCount(chars int) int {
+	return (chars + charsPerToken - 1) / charsPerToken
+}
+
+// WriteCostEstimate writes a cost estimate as a human readable report
+func WriteCostEstimate(w io.Writer, estimate *models.CostEstimate) error {
+	fmt.Fprintf(w, "Query: %s\n", estimate.JQL)
+	if estimate.HistoryRuns == 0 {
+		fmt.Fprintln(w, "History: no AI runs recorded yet, only prompt sizes are estimated")
+	} else {
+		fmt.Fprintf(w, "History: %d AI runs, averaging %d input and %d output tokens for $%.2f per run\n",
+			estimate.HistoryRuns, estimate.AverageInputTokens, estimate.AverageOutputTokens, estimate.AverageCostUSD)
+	}
+	fmt.Fprintln(w)
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "TICKET\tPROMPT TOKENS\tINPUT TOKENS\tOUTPUT TOKENS\tCOST (USD)\tSUMMARY")
+	for _, ticket := range estimate.Tickets {
+		if ticket.Skipped != "" {
+			fmt.Fprintf(table, "%s\t-\t-\t-\t-\tskipped: %s\n", ticket.Ticket, ticket.Skipped)
+			continue
+		}
+		fmt.Fprintf(table, "%s\t%d\t%d\t%d\t%.2f\t%s\n", ticket.Ticket, ticket.PromptTokens, ticket.InputTokens,
+			ticket.OutputTokens, ticket.CostUSD, ticket.Summary)
+	}
+	fmt.Fprintf(table, "TOTAL (%d tickets)\t\t%d\t%d\t%.2f\t\n", len(estimate.Tickets), estimate.TotalInputTokens,
+		estimate.TotalOutputTokens, estimate.TotalCostUSD)
+	return table.Flush()
+}
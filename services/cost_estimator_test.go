@@ -0,0 +1,112 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// newEstimatorTestService creates a Jira service returning the tickets, which have no comments
+func newEstimatorTestService(tickets map[string]models.JiraFields) *mocks.MockJiraService {
+	return &mocks.MockJiraService{
+		SearchTicketsFunc: func(jql string) (*models.JiraSearchResponse, error) {
+			response := &models.JiraSearchResponse{}
+			for _, key := range []string{"TEST-1", "TEST-2", "TEST-3"} {
+				if _, ok := tickets[key]; ok {
+					response.Issues = append(response.Issues, models.JiraIssue{Key: key})
+				}
+			}
+			return response, nil
+		},
+		GetTicketFunc: func(key string) (*models.JiraTicketResponse, error) {
+			return &models.JiraTicketResponse{Key: key, Fields: tickets[key]}, nil
+		},
+	}
+}
+
+func TestCostEstimator_Estimate(t *testing.T) {
+	config := &models.Config{}
+	config.ComponentToRepo = map[string]string{"frontend": "https://github.com/example/frontend.git"}
+	config.UsageHistoryFile = filepath.Join(t.TempDir(), "ai-usage.log")
+
+	// Two past runs on prompts of 400 tokens averaging 10000 input and 1000 output tokens for $2
+	history := NewUsageHistory(config.UsageHistoryFile)
+	for _, record := range []models.AIUsageRecord{
+		{Ticket: "OLD-1", PromptChars: 1600, InputTokens: 8000, OutputTokens: 500, CostUSD: 1},
+		{Ticket: "OLD-2", PromptChars: 1600, InputTokens: 12000, OutputTokens: 1500, CostUSD: 3},
+	} {
+		if err := history.Record(record); err != nil {
+			t.Fatalf("Failed to record usage: %v", err)
+		}
+	}
+
+	components := []models.JiraComponent{{Name: "frontend"}}
+	jiraService := newEstimatorTestService(map[string]models.JiraFields{
+		"TEST-1": {Summary: "Short", Components: components},
+		"TEST-2": {Summary: "Long", Description: strings.Repeat("x", 8000), Components: components},
+		"TEST-3": {Summary: "Unmapped", Components: []models.JiraComponent{{Name: "backend"}}},
+	})
+
+	estimate, err := NewCostEstimator(jiraService, config, zap.NewNop()).Estimate(context.Background(), "project = TEST")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if estimate.HistoryRuns != 2 || estimate.AverageInputTokens != 10000 || estimate.AverageOutputTokens != 1000 || estimate.AverageCostUSD != 2 {
+		t.Fatalf("Expected the averages of the history, got %+v", estimate)
+	}
+	if len(estimate.Tickets) != 3 {
+		t.Fatalf("Expected an estimate per ticket, got %+v", estimate.Tickets)
+	}
+
+	short, long, unmapped := estimate.Tickets[0], estimate.Tickets[1], estimate.Tickets[2]
+	if short.PromptTokens == 0 || short.OutputTokens != 1000 {
+		t.Errorf("Expected the prompt size and the average output, got %+v", short)
+	}
+	// The long description adds its 2000 tokens to the input of the run
+	if long.InputTokens-short.InputTokens != 2000 {
+		t.Errorf("Expected the longer prompt to add its tokens to the input, got %d and %d", short.InputTokens, long.InputTokens)
+	}
+	if long.CostUSD <= short.CostUSD {
+		t.Errorf("Expected the longer ticket to cost more, got $%.2f and $%.2f", short.CostUSD, long.CostUSD)
+	}
+	if unmapped.Skipped == "" || unmapped.CostUSD != 0 {
+		t.Errorf("Expected the unmapped ticket to be skipped, got %+v", unmapped)
+	}
+	if total := short.CostUSD + long.CostUSD; estimate.TotalCostUSD != total {
+		t.Errorf("Expected a total of $%.2f, got $%.2f", total, estimate.TotalCostUSD)
+	}
+
+	var report bytes.Buffer
+	if err := WriteCostEstimate(&report, estimate); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	for _, want := range []string{"History: 2 AI runs", "TEST-2", "skipped: no repository mapping for component backend", "TOTAL (3 tickets)"} {
+		if !strings.Contains(report.String(), want) {
+			t.Errorf("Expected the report to contain %q, got:\n%s", want, report.String())
+		}
+	}
+}
+
+func TestCostEstimator_EstimateWithoutHistory(t *testing.T) {
+	config := &models.Config{}
+	config.ComponentToRepo = map[string]string{"frontend": "https://github.com/example/frontend.git"}
+	jiraService := newEstimatorTestService(map[string]models.JiraFields{
+		"TEST-1": {Summary: "Short", Components: []models.JiraComponent{{Name: "frontend"}}},
+	})
+
+	estimate, err := NewCostEstimator(jiraService, config, zap.NewNop()).Estimate(context.Background(), "project = TEST")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	ticket := estimate.Tickets[0]
+	if ticket.InputTokens != ticket.PromptTokens || ticket.OutputTokens != 0 || ticket.CostUSD != 0 {
+		t.Errorf("Expected only the prompt to be estimated without a history, got %+v", ticket)
+	}
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// costReportExportInterval is how often the exporter checks whether a period closed
+const costReportExportInterval = time.Hour
+
+// CostReporter aggregates the AI usage history per Jira project and component, for chargeback
+type CostReporter interface {
+	// Report aggregates the AI runs recorded from from until to by period, project and component
+	Report(period models.CostReportPeriod, from, to time.Time) (*models.CostReport, error)
+	// Start starts writing the CSV report of every closed period to cost_reports.export_dir
+	Start()
+	// Stop stops the exports, waiting for a running one to finish. The exports can be started again.
+	Stop()
+}
+
+// CostReporterImpl implements the CostReporter interface based on the usage history configured in
+// usage_history_file
+type CostReporterImpl struct {
+	usageHistory UsageHistory
+	config       *models.Config
+	logger       *zap.Logger
+	now          func() time.Time
+
+	mu        sync.Mutex
+	stopChan  chan struct{} // Closed to stop the current export loop
+	doneChan  chan struct{} // Closed once the current export loop returned
+	isRunning bool
+}
+
+// NewCostReporter creates a new CostReporter
+func NewCostReporter(config *models.Config, logger *zap.Logger) CostReporter {
+	return &CostReporterImpl{
+		usageHistory: NewUsageHistory(config.UsageHistoryFile),
+		config:       config,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// Report aggregates the AI runs recorded from from until to by period, project and component. Rows are sorted by
+// period, project and component.
+func (r *CostReporterImpl) Report(period models.CostReportPeriod, from, to time.Time) (*models.CostReport, error) {
+	history, err := r.usageHistory.Load()
+	if err != nil {
+		return nil, err
+	}
+	return buildCostReport(history, period, from, to), nil
+}
+
+// buildCostReport aggregates the records from from until to by period, project and component
+func buildCostReport(records []models.AIUsageRecord, period models.CostReportPeriod, from, to time.Time) *models.CostReport {
+	type rowKey struct{ period, project, component string }
+	report := &models.CostReport{Period: period, From: from, To: to, Rows: []models.CostReportRow{}}
+	rows := make(map[rowKey]*models.CostReportRow)
+	for _, record := range records {
+		if record.Time.Before(from) || !record.Time.Before(to) {
+			continue
+		}
+		project := record.Project
+		if project == "" {
+			// Records written before projects were recorded only have the ticket key
+			project, _, _ = strings.Cut(record.Ticket, "-")
+		}
+		key := rowKey{period.Label(record.Time), project, record.Component}
+		row, ok := rows[key]
+		if !ok {
+			row = &models.CostReportRow{Period: key.period, Project: key.project, Component: key.component}
+			rows[key] = row
+		}
+		row.Runs++
+		row.InputTokens += record.InputTokens
+		row.OutputTokens += record.OutputTokens
+		row.CostUSD += record.CostUSD
+		row.ProcessingSeconds += record.DurationSeconds
+
+		report.TotalRuns++
+		report.TotalCostUSD += record.CostUSD
+		report.TotalProcessingSeconds += record.DurationSeconds
+	}
+
+	for _, row := range rows {
+		report.Rows = append(report.Rows, *row)
+	}
+	sort.Slice(report.Rows, func(i, j int) bool {
+		a, b := report.Rows[i], report.Rows[j]
+		if a.Period != b.Period {
+			return a.Period < b.Period
+		}
+		if a.Project != b.Project {
+			return a.Project < b.Project
+		}
+		return a.Component < b.Component
+	})
+	return report
+}
+
+// WriteCostReportCSV writes a cost report as CSV, one row per period, project and component
+func WriteCostReportCSV(w io.Writer, report *models.CostReport) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"period", "project", "component", "runs", "input_tokens", "output_tokens", "cost_usd", "processing_seconds"}); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	for _, row := range report.Rows {
+		record := []string{
+			row.Period,
+			row.Project,
+			row.Component,
+			strconv.Itoa(row.Runs),
+			strconv.Itoa(row.InputTokens),
+			strconv.Itoa(row.OutputTokens),
+			strconv.FormatFloat(row.CostUSD, 'f', 4, 64),
+			strconv.FormatFloat(row.ProcessingSeconds, 'f', 0, 64),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Start starts writing the CSV report of every closed period to cost_reports.export_dir, checking for closed periods
+// every hour
+func (r *CostReporterImpl) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.isRunning {
+		return
+	}
+
+	r.isRunning = true
+	r.stopChan = make(chan struct{})
+	r.doneChan = make(chan struct{})
+	r.logger.Info("Starting cost report exports...", zap.String("export_dir", r.config.CostReports.ExportDir))
+
+	stopChan, doneChan := r.stopChan, r.doneChan
+	go func() {
+		defer close(doneChan)
+		ticker := time.NewTicker(costReportExportInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := r.exportClosedPeriod(); err != nil {
+				r.logger.Error("Failed to export cost report", zap.Error(err))
+			}
+			select {
+			case <-ticker.C:
+			case <-stopChan:
+				r.logger.Info("Stopping cost report exports...")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the exports, waiting for a running one to finish. The exports can be started again.
+func (r *CostReporterImpl) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.isRunning {
+		return
+	}
+
+	r.isRunning = false
+	close(r.stopChan)
+	<-r.doneChan
+}
+
+// exportClosedPeriod writes the report of the last closed period, unless it was already written. Periods closed while
+// the application wasn't running are skipped, except the last one.
+func (r *CostReporterImpl) exportClosedPeriod() error {
+	period := r.config.CostReports.Period
+	to := period.Start(r.now())
+	from := period.Start(to.Add(-time.Nanosecond))
+
+	path := filepath.Join(r.config.CostReports.ExportDir, fmt.Sprintf("costs-%s.csv", period.Label(from)))
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to check for exported report: %w", err)
+	}
+
+	report, err := r.Report(period, from, to)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(r.config.CostReports.ExportDir, 0755); err != nil {
+		return fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	// Written aside and renamed, so a crash never leaves a partial report that is then never rewritten
+	tmp, err := os.CreateTemp(r.config.CostReports.ExportDir, ".costs-*.csv")
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := WriteCostReportCSV(tmp, report); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write report file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save report file: %w", err)
+	}
+
+	r.logger.Info("Exported cost report",
+		zap.String("path", path),
+		zap.Int("runs", report.TotalRuns),
+		zap.Float64("cost_usd", report.TotalCostUSD))
+	return nil
+}
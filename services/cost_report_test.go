@@ -0,0 +1,98 @@
+package services
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestBuildCostReport(t *testing.T) {
+	records := []models.AIUsageRecord{
+		{Time: time.Date(2024, 2, 28, 10, 0, 0, 0, time.UTC), Ticket: "API-1", Project: "API", Component: "backend", CostUSD: 5},
+		{Time: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC), Ticket: "API-2", Project: "API", Component: "backend", InputTokens: 1000, OutputTokens: 100, CostUSD: 1.5, DurationSeconds: 120},
+		{Time: time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC), Ticket: "API-3", Project: "API", Component: "backend", InputTokens: 2000, OutputTokens: 200, CostUSD: 2.5, DurationSeconds: 180},
+		{Time: time.Date(2024, 3, 20, 10, 0, 0, 0, time.UTC), Ticket: "WEB-1", Component: "frontend", CostUSD: 0.5, DurationSeconds: 60},
+		{Time: time.Date(2024, 4, 2, 10, 0, 0, 0, time.UTC), Ticket: "API-4", Project: "API", Component: "backend", CostUSD: 3, DurationSeconds: 90},
+	}
+
+	report := buildCostReport(records, models.CostReportMonthly, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+	want := []models.CostReportRow{
+		{Period: "2024-03", Project: "API", Component: "backend", Runs: 2, InputTokens: 3000, OutputTokens: 300, CostUSD: 4, ProcessingSeconds: 300},
+		{Period: "2024-03", Project: "WEB", Component: "frontend", Runs: 1, CostUSD: 0.5, ProcessingSeconds: 60},
+		{Period: "2024-04", Project: "API", Component: "backend", Runs: 1, CostUSD: 3, ProcessingSeconds: 90},
+	}
+	if len(report.Rows) != len(want) {
+		t.Fatalf("Expected %d rows, got %+v", len(want), report.Rows)
+	}
+	for i := range want {
+		if report.Rows[i] != want[i] {
+			t.Errorf("Row %d = %+v, want %+v", i, report.Rows[i], want[i])
+		}
+	}
+	if report.TotalRuns != 4 || report.TotalCostUSD != 7.5 || report.TotalProcessingSeconds != 450 {
+		t.Errorf("Unexpected totals: %d runs, $%g, %gs", report.TotalRuns, report.TotalCostUSD, report.TotalProcessingSeconds)
+	}
+}
+
+func TestWriteCostReportCSV(t *testing.T) {
+	report := &models.CostReport{Rows: []models.CostReportRow{
+		{Period: "2024-03", Project: "API", Component: "backend, api", Runs: 2, InputTokens: 3000, OutputTokens: 300, CostUSD: 4, ProcessingSeconds: 300},
+	}}
+	var out bytes.Buffer
+	if err := WriteCostReportCSV(&out, report); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	want := "period,project,component,runs,input_tokens,output_tokens,cost_usd,processing_seconds\n" +
+		"2024-03,API,\"backend, api\",2,3000,300,4.0000,300\n"
+	if out.String() != want {
+		t.Errorf("Unexpected CSV:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestCostReporter_ExportClosedPeriod(t *testing.T) {
+	dir := t.TempDir()
+	config := &models.Config{UsageHistoryFile: filepath.Join(dir, "ai-usage.log")}
+	config.CostReports.Period = models.CostReportMonthly
+	config.CostReports.ExportDir = filepath.Join(dir, "reports")
+
+	reporter := NewCostReporter(config, zap.NewNop()).(*CostReporterImpl)
+	reporter.now = func() time.Time { return time.Date(2024, 4, 2, 8, 0, 0, 0, time.UTC) }
+	history := NewUsageHistory(config.UsageHistoryFile)
+	for _, record := range []models.AIUsageRecord{
+		{Time: time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC), Ticket: "API-1", Project: "API", CostUSD: 2},
+		{Time: time.Date(2024, 4, 1, 10, 0, 0, 0, time.UTC), Ticket: "API-2", Project: "API", CostUSD: 3},
+	} {
+		if err := history.Record(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := reporter.exportClosedPeriod(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	path := filepath.Join(config.CostReports.ExportDir, "costs-2024-03.csv")
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected the report of March to be exported: %v", err)
+	}
+	if !bytes.Contains(content, []byte("2024-03,API,,1,0,0,2.0000,0\n")) || bytes.Contains(content, []byte("2024-04")) {
+		t.Errorf("Expected only the runs of March, got:\n%s", content)
+	}
+
+	// An exported report is never rewritten
+	if err := os.WriteFile(path, []byte("edited"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := reporter.exportClosedPeriod(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != "edited" {
+		t.Errorf("Expected the exported report to be kept, got:\n%s", content)
+	}
+}
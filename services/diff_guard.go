@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// maxDiffStatFiles bounds how many of the largest changed files are listed in the diff size report
+const maxDiffStatFiles = 10
+
+// guardDiffSize checks the AI's changes against the configured diff size limits. A change that is too large is sent
+// back to the AI to narrow it, up to the configured retries, after which the ticket fails with the diff stats so a
+// human can narrow its scope; nothing is pushed.
+func (p *TicketProcessorImpl) guardDiffSize(ctx context.Context, run *TicketRun) error {
+	guard := p.config.DiffGuard
+	if guard.MaxFiles == 0 && guard.MaxLines == 0 {
+		return nil
+	}
+
+	for attempt := 0; ; attempt++ {
+		stats, err := p.githubService.DiffStats(ctx, run.RepoDir)
+		if err != nil {
+			p.logger.Error("Failed to get diff stats",
+				zap.String("ticket", run.Key),
+				zap.String("repo_dir", run.RepoDir),
+				zap.Error(err))
+			p.handleFailure(ctx, run.Key, fmt.Sprintf("Failed to get diff stats: %v", err))
+			return err
+		}
+		exceeded := p.diffLimitsExceeded(stats)
+		if exceeded == "" {
+			return nil
+		}
+
+		if attempt == guard.Retries {
+			p.logger.Warn("AI changes exceed the diff size limits",
+				zap.String("ticket", run.Key),
+				zap.Int("files", len(stats.Files)),
+				zap.Int("lines", stats.ChangedLines()),
+				zap.Int("retries", attempt))
+			p.handleFailure(ctx, run.Key, fmt.Sprintf("The changes exceed the diff size limits (%s), so they weren't pushed.\n\n%s\n\n"+
+				"Please narrow the ticket's scope, e.g. by splitting it into smaller tickets or pointing out the files to change, "+
+				"then move it back to the todo status.", exceeded, formatDiffStats(stats)))
+			return fmt.Errorf("changes exceed the diff size limits: %s", exceeded)
+		}
+
+		p.logger.Warn("AI changes exceed the diff size limits, asking the AI to narrow them",
+			zap.String("ticket", run.Key),
+			zap.Int("files", len(stats.Files)),
+			zap.Int("lines", stats.ChangedLines()),
+			zap.Int("attempt", attempt+1))
+		prompt := narrowDiffPrompt(exceeded, stats)
+		started := time.Now()
+		response, err := p.aiService.GenerateCode(ctx, prompt, run.RepoDir)
+		if err != nil {
+			p.logger.Error("Failed to narrow the changes",
+				zap.String("ticket", run.Key),
+				zap.String("repo_dir", run.RepoDir),
+				zap.Error(err))
+			p.handleFailure(ctx, run.Key, fmt.Sprintf("Failed to narrow the changes: %v", err))
+			return err
+		}
+		p.recordUsage(run, prompt, time.Since(started), response)
+		if err := p.revertProtectedPaths(ctx, run); err != nil {
+			return err
+		}
+	}
+}
+
+// diffLimitsExceeded describes the diff size limits the changes exceed, or returns an empty string if they don't
+func (p *TicketProcessorImpl) diffLimitsExceeded(stats *models.DiffStats) string {
+	guard := p.config.DiffGuard
+	var exceeded []string
+	if guard.MaxFiles > 0 && len(stats.Files) > guard.MaxFiles {
+		exceeded = append(exceeded, fmt.Sprintf("%d changed files, the limit is %d", len(stats.Files), guard.MaxFiles))
+	}
+	if lines := stats.ChangedLines(); guard.MaxLines > 0 && lines > guard.MaxLines {
+		exceeded = append(exceeded, fmt.Sprintf("%d changed lines, the limit is %d", lines, guard.MaxLines))
+	}
+	return strings.Join(exceeded, "; ")
+}
+
+// formatDiffStats summarizes the changes: their totals and the largest changed files
+func formatDiffStats(stats *models.DiffStats) string {
+	files := append([]models.FileDiffStat(nil), stats.Files...)
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].Added+files[i].Removed > files[j].Added+files[j].Removed
+	})
+
+	var summary strings.Builder
+	summary.WriteString(fmt.Sprintf("Diff stats: %d files, %d lines changed", len(files), stats.ChangedLines()))
+	for i, file := range files {
+		if i == maxDiffStatFiles {
+			summary.WriteString(fmt.Sprintf("\n- ... and %d more files", len(files)-maxDiffStatFiles))
+			break
+		}
+		summary.WriteString(fmt.Sprintf("\n- %s (+%d -%d)", file.Path, file.Added, file.Removed))
+	}
+	return summary.String()
+}
+
+// narrowDiffPrompt generates a prompt for the AI service to reduce changes that are too large
+func narrowDiffPrompt(exceeded string, stats *models.DiffStats) string {
+	var prompt strings.Builder
+
+	prompt.WriteString(fmt.Sprintf("Your changes to this repository are too large to be reviewed: %s.\n\n", exceeded))
+	prompt.WriteString("## Current Changes\n")
+	prompt.WriteString(formatDiffStats(stats))
+	prompt.WriteString("\n\n")
+
+	prompt.WriteString("## Instructions\n")
+	prompt.WriteString("1. Keep only the changes the ticket requires; revert refactorings, reformatting and unrelated improvements\n")
+	prompt.WriteString("2. Use `git checkout HEAD -- <file>` to revert files that don't need to change, and delete new files that aren't required\n")
+	prompt.WriteString("3. Make sure the remaining changes still implement the ticket\n")
+	prompt.WriteString("4. Do not commit; the changes are committed for you\n")
+
+	return prompt.String()
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+)
+
+func newTestDiffStats(files, linesPerFile int) *models.DiffStats {
+	stats := &models.DiffStats{}
+	for i := 0; i < files; i++ {
+		stats.Files = append(stats.Files, models.FileDiffStat{Path: fmt.Sprintf("pkg/file%d.go", i), Added: linesPerFile})
+	}
+	return stats
+}
+
+func TestTicketProcessor_GuardDiffSize_Narrowed(t *testing.T) {
+	config := &models.Config{}
+	config.DiffGuard.MaxFiles = 5
+	config.DiffGuard.Retries = 1
+
+	stats := newTestDiffStats(20, 10)
+	githubService := &mocks.MockGitHubService{
+		DiffStatsFunc: func(directory string) (*models.DiffStats, error) {
+			return stats, nil
+		},
+	}
+	var prompts []string
+	aiService := &mocks.MockClaudeService{
+		GenerateCodeFunc: func(prompt, dir string) (*models.ClaudeResponse, error) {
+			prompts = append(prompts, prompt)
+			stats = newTestDiffStats(3, 10)
+			return &models.ClaudeResponse{}, nil
+		},
+	}
+	processor := newCheckTestProcessor(t, config, aiService, &mocks.MockJiraService{}, githubService)
+
+	if err := processor.guardDiffSize(context.Background(), &TicketRun{Key: "TEST-1", RepoDir: t.TempDir()}); err != nil {
+		t.Fatalf("Expected the narrowed changes to pass but got: %v", err)
+	}
+	if len(prompts) != 1 || !strings.Contains(prompts[0], "20 changed files, the limit is 5") {
+		t.Errorf("Expected one prompt to narrow the changes, got %q", prompts)
+	}
+}
+
+func TestTicketProcessor_GuardDiffSize_Exceeded(t *testing.T) {
+	config := &models.Config{}
+	config.DiffGuard.MaxLines = 100
+
+	githubService := &mocks.MockGitHubService{
+		DiffStatsFunc: func(directory string) (*models.DiffStats, error) {
+			return newTestDiffStats(12, 50), nil
+		},
+	}
+	aiService := &mocks.MockClaudeService{
+		GenerateCodeFunc: func(prompt, dir string) (*models.ClaudeResponse, error) {
+			t.Error("Expected the AI not to run without retries")
+			return nil, nil
+		},
+	}
+	var comment string
+	jiraService := &mocks.MockJiraService{
+		AddCommentFunc: func(key, body string) error {
+			comment = body
+			return nil
+		},
+	}
+	processor := newCheckTestProcessor(t, config, aiService, jiraService, githubService)
+
+	if err := processor.guardDiffSize(context.Background(), &TicketRun{Key: "TEST-1", RepoDir: t.TempDir()}); err == nil {
+		t.Fatal("Expected an error for changes exceeding the limits")
+	}
+	for _, want := range []string{"600 changed lines, the limit is 100", "Diff stats: 12 files, 600 lines changed", "pkg/file0.go (+50 -0)", "and 2 more files", "narrow the ticket's scope"} {
+		if !strings.Contains(comment, want) {
+			t.Errorf("Expected %q in the Jira comment, got %q", want, comment)
+		}
+	}
+}
+
+func TestTicketProcessor_GuardDiffSize_Disabled(t *testing.T) {
+	githubService := &mocks.MockGitHubService{
+		DiffStatsFunc: func(directory string) (*models.DiffStats, error) {
+			t.Error("Expected no diff stats without limits")
+			return nil, nil
+		},
+	}
+	processor := newCheckTestProcessor(t, &models.Config{}, &mocks.MockClaudeService{}, &mocks.MockJiraService{}, githubService)
+
+	if err := processor.guardDiffSize(context.Background(), &TicketRun{Key: "TEST-1"}); err != nil {
+		t.Errorf("Expected no error without limits, got: %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// DocsBootstrapStore persists the repositories the AI's documentation file was generated for
+type DocsBootstrapStore interface {
+	// IsBootstrapped returns whether documentation was generated for the repository
+	IsBootstrapped(repo string) (bool, error)
+	// MarkBootstrapped records that documentation was generated for the repository
+	MarkBootstrapped(repo string) error
+}
+
+// DocsBootstrapStoreImpl implements the DocsBootstrapStore interface as a JSON file of generation times keyed by
+// repository
+type DocsBootstrapStoreImpl struct {
+	path string
+
+	mu    sync.Mutex
+	repos map[string]time.Time
+}
+
+// NewDocsBootstrapStore creates a new DocsBootstrapStore persisted to the given file. The file is read on first use.
+// Repositories are only kept in memory when the path is empty.
+func NewDocsBootstrapStore(path string) DocsBootstrapStore {
+	return &DocsBootstrapStoreImpl{path: path}
+}
+
+// IsBootstrapped returns whether documentation was generated for the repository
+func (s *DocsBootstrapStoreImpl) IsBootstrapped(repo string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return false, err
+	}
+	_, ok := s.repos[repo]
+	return ok, nil
+}
+
+// MarkBootstrapped records that documentation was generated for the repository
+func (s *DocsBootstrapStoreImpl) MarkBootstrapped(repo string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return err
+	}
+	s.repos[repo] = time.Now().UTC()
+	return s.save()
+}
+
+// load reads the bootstrap file once. The caller must hold the lock.
+func (s *DocsBootstrapStoreImpl) load() error {
+	if s.repos != nil {
+		return nil
+	}
+
+	repos := make(map[string]time.Time)
+	if s.path != "" {
+		data, err := os.ReadFile(s.path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to read docs bootstrap file: %w", err)
+		}
+		if err == nil {
+			if err := json.Unmarshal(data, &repos); err != nil {
+				return fmt.Errorf("failed to parse docs bootstrap file: %w", err)
+			}
+		}
+	}
+	s.repos = repos
+	return nil
+}
+
+// save persists the repositories. The caller must hold the lock.
+func (s *DocsBootstrapStoreImpl) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s.repos, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal docs bootstrap file: %w", err)
+	}
+
+	if err := writeFileAtomic(s.path, data); err != nil {
+		return fmt.Errorf("failed to save docs bootstrap file: %w", err)
+	}
+	return nil
+}
+
+// generateDocsStep generates the AI's documentation file (CLAUDE.md or GEMINI.md) if it doesn't exist. Depending on
+// the component's generate_docs mode this happens for every ticket, for the first ticket of each repository only,
+// or never.
+func (p *TicketProcessorImpl) generateDocsStep(ctx context.Context, run *TicketRun) error {
+	mode := p.config.GetGenerateDocs(run.Component)
+	repo := run.Owner + "/" + run.Repo
+	switch mode {
+	case models.GenerateDocsOff:
+		p.logger.Info("Skipping documentation generation", zap.String("ticket", run.Key), zap.String("mode", string(mode)))
+		return nil
+	case models.GenerateDocsOnce:
+		bootstrapped, err := p.docsBootstrap.IsBootstrapped(repo)
+		if err != nil {
+			// Generating again is only a cost, skipping could leave the repository without documentation
+			p.logger.Warn("Failed to check whether documentation was generated", zap.String("ticket", run.Key), zap.Error(err))
+		} else if bootstrapped {
+			p.logger.Info("Skipping documentation generation, already done for the repository",
+				zap.String("ticket", run.Key),
+				zap.String("repo", repo))
+			return nil
+		}
+	}
+
+	err := p.aiService.GenerateDocumentation(ctx, run.RepoDir)
+	if err != nil {
+		p.logger.Warn("Failed to generate documentation",
+			zap.String("ticket", run.Key),
+			zap.String("repo_dir", run.RepoDir),
+			zap.Error(err))
+		// Continue processing even if documentation generation fails
+		return nil
+	}
+
+	if mode == models.GenerateDocsOnce {
+		if err := p.docsBootstrap.MarkBootstrapped(repo); err != nil {
+			p.logger.Warn("Failed to record documentation generation", zap.String("ticket", run.Key), zap.Error(err))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestDocsBootstrapStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "docs-bootstrap.json")
+	store := NewDocsBootstrapStore(path)
+
+	if ok, err := store.IsBootstrapped("example/repo"); err != nil || ok {
+		t.Fatalf("Expected no repository in a missing file, got %v, %v", ok, err)
+	}
+	if err := store.MarkBootstrapped("example/repo"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	// A new store reads the persisted repositories
+	if ok, err := NewDocsBootstrapStore(path).IsBootstrapped("example/repo"); err != nil || !ok {
+		t.Errorf("Expected the persisted repository, got %v, %v", ok, err)
+	}
+}
+
+func TestTicketProcessor_GenerateDocsStep(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     models.GenerateDocsMode
+		expected int
+	}{
+		{name: "off", mode: models.GenerateDocsOff, expected: 0},
+		{name: "once", mode: models.GenerateDocsOnce, expected: 1},
+		{name: "always", mode: models.GenerateDocsAlways, expected: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &models.Config{}
+			config.AI.GenerateDocs = models.GenerateDocsAlways
+			config.Components = map[string]models.ComponentConfig{"payments": {GenerateDocs: tt.mode}}
+
+			processor := &TicketProcessorImpl{
+				aiService:     &mocks.MockClaudeService{},
+				docsBootstrap: NewDocsBootstrapStore(""),
+				config:        config,
+				logger:        zap.NewNop(),
+			}
+
+			// Two tickets of the same repository; the mock writes CLAUDE.md to the checkout
+			generated := 0
+			for _, key := range []string{"TEST-1", "TEST-2"} {
+				run := &TicketRun{Key: key, Component: "payments", Owner: "example", Repo: "repo", RepoDir: t.TempDir()}
+				if err := processor.generateDocsStep(context.Background(), run); err != nil {
+					t.Fatalf("Expected no error but got: %v", err)
+				}
+				if _, err := os.Stat(filepath.Join(run.RepoDir, "CLAUDE.md")); err == nil {
+					generated++
+				}
+			}
+			if generated != tt.expected {
+				t.Errorf("Expected documentation to be generated %d times, got %d", tt.expected, generated)
+			}
+		})
+	}
+}
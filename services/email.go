@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// defaultEmailSubjects are the subjects of the events whose route has no subject
+var defaultEmailSubjects = map[models.NotificationEvent]string{
+	models.NotificationPRCreated:        "[{{.Ticket}}] AI opened a pull request",
+	models.NotificationProcessingFailed: "[{{.Ticket}}] AI failed to process the ticket",
+	models.NotificationBudgetExceeded:   "[{{.Ticket}}] AI ran out of time processing the ticket",
+	models.NotificationFeedbackApplied:  "[{{.Ticket}}] AI applied the pull request feedback",
+}
+
+// emailNotification is the data of the email templates: the notification and the ticket's summary
+type emailNotification struct {
+	models.Notification
+	Summary string
+}
+
+// sendMailFunc sends an email through an SMTP server, like smtp.SendMail
+type sendMailFunc func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+
+// EmailSink implements the NotificationSink interface by mailing the reporter and assignee of the ticket
+type EmailSink struct {
+	jiraService JiraService
+	config      *models.Config
+	sendMail    sendMailFunc
+	logger      *zap.Logger
+}
+
+// NewEmailSink creates a new NotificationSink mailing through the SMTP server in the configuration
+func NewEmailSink(jiraService JiraService, config *models.Config, logger *zap.Logger) NotificationSink {
+	return &EmailSink{
+		jiraService: jiraService,
+		config:      config,
+		sendMail:    smtp.SendMail,
+		logger:      logger,
+	}
+}
+
+// Name returns the name of the sink in logs
+func (s *EmailSink) Name() string {
+	return "email"
+}
+
+// Send mails the notification to the reporter and assignee of the ticket who didn't opt out, unless the event isn't
+// mailed
+func (s *EmailSink) Send(ctx context.Context, notification models.Notification) error {
+	email := s.config.Notifications.Email
+	route, ok := email.Route(notification.Event)
+	if !ok {
+		return nil
+	}
+
+	ticket, err := s.jiraService.GetTicket(ctx, notification.Ticket)
+	if err != nil {
+		return fmt.Errorf("failed to get ticket: %w", err)
+	}
+	recipients := s.recipients(ticket)
+	if len(recipients) == 0 {
+		s.logger.Debug("No email recipients for notification", zap.String("ticket", notification.Ticket), zap.String("event", string(notification.Event)))
+		return nil
+	}
+
+	data := emailNotification{Notification: notification, Summary: ticket.Fields.Summary}
+	subjectTemplate, bodyTemplate := route.Subject, route.Template
+	if subjectTemplate == "" {
+		subjectTemplate = defaultEmailSubjects[notification.Event]
+	}
+	if bodyTemplate == "" {
+		bodyTemplate = defaultNotificationTemplates[notification.Event]
+	}
+	subject, err := renderTemplate("subject", subjectTemplate, data)
+	if err != nil {
+		return err
+	}
+	body, err := renderTemplate("body", bodyTemplate, data)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if email.Username != "" {
+		auth = smtp.PlainAuth("", email.Username, email.Password, email.Host)
+	}
+	addr := net.JoinHostPort(email.Host, strconv.Itoa(email.Port))
+	if err := s.sendMail(addr, auth, email.From, recipients, buildEmail(email.From, recipients, subject, body)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	s.logger.Debug("Sent email notification",
+		zap.String("event", string(notification.Event)),
+		zap.String("ticket", notification.Ticket),
+		zap.Strings("recipients", recipients))
+	return nil
+}
+
+// recipients returns the email addresses of the ticket's reporter and assignee who didn't opt out. Users whose
+// address Jira doesn't reveal aren't mailed.
+func (s *EmailSink) recipients(ticket *models.JiraTicketResponse) []string {
+	users := []models.JiraUser{ticket.Fields.Reporter}
+	if ticket.Fields.Assignee != nil {
+		users = append(users, *ticket.Fields.Assignee)
+	}
+
+	var recipients []string
+	for _, user := range users {
+		if user.EmailAddress == "" || s.config.Notifications.Email.IsOptedOut(user) {
+			continue
+		}
+		duplicate := false
+		for _, recipient := range recipients {
+			duplicate = duplicate || strings.EqualFold(recipient, user.EmailAddress)
+		}
+		if !duplicate {
+			recipients = append(recipients, user.EmailAddress)
+		}
+	}
+	return recipients
+}
+
+// buildEmail returns a plain text email message with its headers
+func buildEmail(from string, to []string, subject, body string) []byte {
+	var message strings.Builder
+	message.WriteString("From: " + from + "\r\n")
+	message.WriteString("To: " + strings.Join(to, ", ") + "\r\n")
+	// A header spanning lines would inject headers, so rendered subjects are kept on one line
+	subject = strings.NewReplacer("\r", " ", "\n", " ").Replace(subject)
+	message.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n")
+	message.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	message.WriteString("\r\n")
+	message.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return []byte(message.String())
+}
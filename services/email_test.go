@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"net/smtp"
+	"reflect"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+type sentEmail struct {
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+	msg  string
+}
+
+func newTestEmailSink(config *models.Config, ticket *models.JiraTicketResponse) (*EmailSink, *[]sentEmail) {
+	jiraService := &mocks.MockJiraService{
+		GetTicketFunc: func(key string) (*models.JiraTicketResponse, error) {
+			return ticket, nil
+		},
+	}
+	var sent []sentEmail
+	sink := NewEmailSink(jiraService, config, zap.NewNop()).(*EmailSink)
+	sink.sendMail = func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+		sent = append(sent, sentEmail{addr: addr, auth: auth, from: from, to: to, msg: string(msg)})
+		return nil
+	}
+	return sink, &sent
+}
+
+func newEmailConfig() *models.Config {
+	config := &models.Config{}
+	config.Notifications.Email.Host = "smtp.example.com"
+	config.Notifications.Email.Port = 587
+	config.Notifications.Email.From = "ai-bot@example.com"
+	return config
+}
+
+func newEmailTicket() *models.JiraTicketResponse {
+	ticket := &models.JiraTicketResponse{Key: "TEST-1"}
+	ticket.Fields.Summary = "Fix the login page"
+	ticket.Fields.Reporter = models.JiraUser{Name: "reporter", EmailAddress: "reporter@example.com"}
+	ticket.Fields.Assignee = &models.JiraUser{ID: "557058:assignee", EmailAddress: "assignee@example.com"}
+	return ticket
+}
+
+func TestEmailSink_Send(t *testing.T) {
+	config := newEmailConfig()
+	config.Notifications.Email.Username = "ai-bot"
+	config.Notifications.Email.Password = "secret"
+	sink, sent := newTestEmailSink(config, newEmailTicket())
+
+	notification := models.Notification{
+		Event:     models.NotificationPRCreated,
+		Ticket:    "TEST-1",
+		TicketURL: "https://jira/browse/TEST-1",
+		PRURL:     "https://github.com/example/repo/pull/7",
+	}
+	if err := sink.Send(context.Background(), notification); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(*sent) != 1 {
+		t.Fatalf("Expected one email, got %d", len(*sent))
+	}
+	email := (*sent)[0]
+	if email.addr != "smtp.example.com:587" || email.from != "ai-bot@example.com" || email.auth == nil {
+		t.Errorf("Unexpected SMTP settings: %+v", email)
+	}
+	if want := []string{"reporter@example.com", "assignee@example.com"}; !reflect.DeepEqual(email.to, want) {
+		t.Errorf("Expected the reporter and assignee, got %v", email.to)
+	}
+	if !strings.Contains(email.msg, "Subject: [TEST-1] AI opened a pull request\r\n") {
+		t.Errorf("Expected the default subject, got %q", email.msg)
+	}
+	if !strings.Contains(email.msg, "https://github.com/example/repo/pull/7") {
+		t.Errorf("Expected the default body with the PR URL, got %q", email.msg)
+	}
+}
+
+func TestEmailSink_Send_Routing(t *testing.T) {
+	tests := []struct {
+		name      string
+		events    map[models.NotificationEvent]models.EmailRoute
+		event     models.NotificationEvent
+		wantSent  bool
+		wantInMsg string
+	}{
+		{name: "default failure", event: models.NotificationProcessingFailed, wantSent: true, wantInMsg: "AI failed to process TEST-1"},
+		{name: "default skips feedback", event: models.NotificationFeedbackApplied},
+		{
+			name:      "templates",
+			events:    map[models.NotificationEvent]models.EmailRoute{models.NotificationProcessingFailed: {Subject: "{{.Summary}} failed", Template: "Reason: {{.Message}}"}},
+			event:     models.NotificationProcessingFailed,
+			wantSent:  true,
+			wantInMsg: "Subject: Fix the login page failed\r\n",
+		},
+		{name: "unlisted event", events: map[models.NotificationEvent]models.EmailRoute{models.NotificationPRCreated: {}}, event: models.NotificationProcessingFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := newEmailConfig()
+			config.Notifications.Email.Events = tt.events
+			sink, sent := newTestEmailSink(config, newEmailTicket())
+
+			notification := models.Notification{Event: tt.event, Ticket: "TEST-1", Message: "AI made no changes to the repository"}
+			if err := sink.Send(context.Background(), notification); err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if (len(*sent) == 1) != tt.wantSent {
+				t.Fatalf("Expected sent = %v, got %d emails", tt.wantSent, len(*sent))
+			}
+			if tt.wantSent && !strings.Contains((*sent)[0].msg, tt.wantInMsg) {
+				t.Errorf("Expected the email to contain %q, got %q", tt.wantInMsg, (*sent)[0].msg)
+			}
+		})
+	}
+}
+
+func TestEmailSink_Send_OptOut(t *testing.T) {
+	config := newEmailConfig()
+	config.Notifications.Email.OptOut = []string{"REPORTER@example.com", "557058:assignee"}
+	sink, sent := newTestEmailSink(config, newEmailTicket())
+
+	if err := sink.Send(context.Background(), models.Notification{Event: models.NotificationPRCreated, Ticket: "TEST-1"}); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(*sent) != 0 {
+		t.Errorf("Expected no email to users who opted out, got %+v", *sent)
+	}
+
+	config.Notifications.Email.OptOut = []string{"reporter"}
+	if err := sink.Send(context.Background(), models.Notification{Event: models.NotificationPRCreated, Ticket: "TEST-1"}); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(*sent) != 1 || !reflect.DeepEqual((*sent)[0].to, []string{"assignee@example.com"}) {
+		t.Errorf("Expected only the assignee to be mailed, got %+v", *sent)
+	}
+}
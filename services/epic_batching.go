@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// batchedTicketsPattern matches the line of a batched pull request's body listing the other tickets it resolves
+var batchedTicketsPattern = regexp.MustCompile(`(?m)^\*\*Also resolves:\*\* (.+)$`)
+
+// epicBatches groups the tickets of the same epic that map to the same repository into batches of at most
+// max_tickets, in search order. Tickets without an epic, or without the batching label if one is configured, are
+// batches of their own.
+func epicBatches(ctx context.Context, repoResolver RepositoryResolver, config *models.Config, issues []models.JiraIssue) [][]string {
+	settings := config.Jira.EpicBatching
+	var batches [][]string
+	open := make(map[string]int) // batch being filled by epic and repository
+	for _, issue := range issues {
+		ticket := &models.JiraTicketResponse{ID: issue.ID, Key: issue.Key, Fields: issue.Fields}
+		_, repoURL, ok := repoResolver.Resolve(ctx, ticket)
+		batchable := settings.Enabled && ok && issue.Fields.Parent != nil &&
+			(settings.Label == "" || slices.Contains(issue.Fields.Labels, settings.Label))
+		if !batchable {
+			batches = append(batches, []string{issue.Key})
+			continue
+		}
+
+		group := issue.Fields.Parent.Key + " " + repoURL
+		if i, ok := open[group]; ok && len(batches[i]) < settings.MaxTickets {
+			batches[i] = append(batches[i], issue.Key)
+			continue
+		}
+		open[group] = len(batches)
+		batches = append(batches, []string{issue.Key})
+	}
+	return batches
+}
+
+// prepareBatch loads the tickets batched with the run's ticket and moves them along with it. Tickets that no longer
+// belong to the batch, such as ones moved to another repository since the scan, are left for a later scan.
+func (p *TicketProcessorImpl) prepareBatch(ctx context.Context, run *TicketRun, keys []string) {
+	for _, key := range keys {
+		ticket, err := p.jiraService.GetTicket(ctx, key)
+		if err != nil {
+			p.logger.Warn("Failed to get batched ticket, leaving it out of the batch",
+				zap.String("ticket", run.Key),
+				zap.String("batched_ticket", key),
+				zap.Error(err))
+			continue
+		}
+		_, repoURL, ok := p.repoResolver.Resolve(ctx, ticket)
+		if !ok {
+			p.logger.Warn("Batched ticket has no repository, leaving it out of the batch",
+				zap.String("ticket", run.Key),
+				zap.String("batched_ticket", key))
+			continue
+		}
+		if owner, repo, err := ExtractRepoInfo(repoURL); err != nil || owner != run.Owner || repo != run.Repo {
+			p.logger.Warn("Batched ticket maps to another repository, leaving it out of the batch",
+				zap.String("ticket", run.Key),
+				zap.String("batched_ticket", key),
+				zap.String("repo_url", repoURL))
+			continue
+		}
+		// Like a single ticket, it's retried by the next scan without reporting the failure
+		if err := p.consumeTriggerLabel(ctx, key); err != nil {
+			p.logger.Warn("Failed to take up batched ticket, leaving it out of the batch",
+				zap.String("ticket", run.Key),
+				zap.String("batched_ticket", key),
+				zap.Error(err))
+			continue
+		}
+		if err := p.stateMachine.Transition(key, models.TicketStateQueued, nil); err != nil {
+			p.logger.Warn("Failed to change batched ticket state, leaving it out of the batch",
+				zap.String("ticket", run.Key),
+				zap.String("batched_ticket", key),
+				zap.Error(err))
+			continue
+		}
+		if err := p.jiraService.UpdateTicketStatus(ctx, key, p.config.Jira.StatusTransitions.InProgress); err != nil {
+			p.logger.Error("Failed to update batched ticket status",
+				zap.String("ticket", run.Key),
+				zap.String("batched_ticket", key),
+				zap.Error(err))
+			// Continue processing even if status update fails
+		}
+		run.Batch = append(run.Batch, ticket)
+	}
+
+	if len(run.Batch) > 0 {
+		p.logger.Info("Processing ticket together with related tickets of its epic",
+			zap.String("ticket", run.Key),
+			zap.Strings("batched_tickets", batchKeys(run)))
+	}
+}
+
+// batchKeys returns the keys of the tickets batched with the run's ticket
+func batchKeys(run *TicketRun) []string {
+	keys := make([]string, 0, len(run.Batch))
+	for _, ticket := range run.Batch {
+		keys = append(keys, ticket.Key)
+	}
+	return keys
+}
+
+// transition changes the state of the run's ticket and of the tickets batched with it
+func (p *TicketProcessorImpl) transition(run *TicketRun, state models.TicketState, cause error) error {
+	for _, ticket := range run.Batch {
+		if err := p.stateMachine.Transition(ticket.Key, state, cause); err != nil {
+			p.logger.Warn("Failed to change batched ticket state",
+				zap.String("ticket", run.Key),
+				zap.String("batched_ticket", ticket.Key),
+				zap.Error(err))
+		}
+	}
+	return p.stateMachine.Transition(run.Key, state, cause)
+}
+
+// failBatch tells the batched tickets that the ticket they were processed with failed
+func (p *TicketProcessorImpl) failBatch(ctx context.Context, run *TicketRun, cause error) {
+	if p.config.Jira.DisableErrorComments {
+		return
+	}
+	for _, ticket := range run.Batch {
+		comment := failureComment(p.config, fmt.Sprintf("AI failed to process this ticket together with %s", run.Key), RedactSecrets(p.config, cause.Error()))
+		if err := upsertJiraComment(ctx, p.jiraService, p.logger, ticket.Key, commentKeyFailure, comment); err != nil {
+			p.logger.Error("Failed to add error comment", zap.String("ticket", ticket.Key), zap.Error(err))
+		}
+	}
+}
+
+// ticketTitle returns the commit message and pull request title of the run: the keys of its tickets and the summary
+// of the first one
+func ticketTitle(run *TicketRun) string {
+	keys := append([]string{run.Key}, batchKeys(run)...)
+	return fmt.Sprintf("%s: %s", strings.Join(keys, ", "), run.Ticket.Fields.Summary)
+}
+
+// batchPrompt describes the batched tickets the change must also resolve, or returns an empty string if there are
+// none
+func batchPrompt(run *TicketRun) string {
+	if len(run.Batch) == 0 {
+		return ""
+	}
+	var prompt strings.Builder
+	prompt.WriteString("\n\nThe same change must also resolve the following related tickets of the same epic; implement all of them:\n")
+	for _, ticket := range run.Batch {
+		prompt.WriteString(fmt.Sprintf("\nJira ticket %s\nSummary: %s\nDescription: %s\n", ticket.Key, ticket.Fields.Summary, ticket.Fields.Description))
+	}
+	return prompt.String()
+}
+
+// batchNote lists the batched tickets in the pull request body, or returns an empty string if there are none
+func batchNote(run *TicketRun) string {
+	if len(run.Batch) == 0 {
+		return ""
+	}
+	note := fmt.Sprintf("\n\n**Also resolves:** %s", strings.Join(batchKeys(run), ", "))
+	for _, ticket := range run.Batch {
+		note += fmt.Sprintf("\n- %s: %s", ticket.Key, ticket.Fields.Summary)
+	}
+	return note
+}
+
+// notifyBatch links the batched tickets with the run's ticket, comments the pull request on them and moves them to
+// review. Only the run's ticket tracks the pull request, so its feedback is processed once.
+func (p *TicketProcessorImpl) notifyBatch(ctx context.Context, run *TicketRun, pr *models.GitHubCreatePRResponse) {
+	for _, ticket := range run.Batch {
+		if err := p.jiraService.LinkTickets(ctx, p.config.Jira.EpicBatching.LinkType, run.Key, ticket.Key); err != nil {
+			p.logger.Error("Failed to link batched ticket",
+				zap.String("ticket", run.Key),
+				zap.String("batched_ticket", ticket.Key),
+				zap.Error(err))
+			// Continue processing even if linking fails
+		}
+
+		linkPullRequest(ctx, p.jiraService, p.config, p.logger, ticket.Key, pr.HTMLURL, pr.Title, false)
+
+		comment := successComment(p.config, "Pull request opened",
+			fmt.Sprintf("AI-generated pull request created: %s\n\nThis ticket is resolved together with %s, which tracks the pull request.", pr.HTMLURL, run.Key), "")
+		if err := upsertJiraComment(ctx, p.jiraService, p.logger, ticket.Key, commentKeyPRCreated, comment); err != nil {
+			p.logger.Error("Failed to add comment", zap.String("ticket", ticket.Key), zap.Error(err))
+			// Continue processing even if comment fails
+		}
+
+		if err := p.jiraService.UpdateTicketStatus(ctx, ticket.Key, p.config.Jira.StatusTransitions.InReview); err != nil {
+			p.logger.Error("Failed to update ticket status", zap.String("ticket", ticket.Key), zap.Error(err))
+			// Continue processing even if status update fails
+		}
+	}
+}
+
+// batchedTickets returns the other tickets a batched pull request resolves, from its body
+func batchedTickets(body string) []string {
+	match := batchedTicketsPattern.FindStringSubmatch(body)
+	if match == nil {
+		return nil
+	}
+	var keys []string
+	for _, key := range strings.Split(match[1], ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// epicIssue returns a ticket of the component in the epic, or without an epic if it is empty
+func epicIssue(key, component, epic string, labels ...string) models.JiraIssue {
+	issue := models.JiraIssue{Key: key}
+	issue.Fields.Components = []models.JiraComponent{{Name: component}}
+	issue.Fields.Labels = labels
+	if epic != "" {
+		issue.Fields.Parent = &models.JiraParent{Key: epic}
+	}
+	return issue
+}
+
+func TestEpicBatches(t *testing.T) {
+	config := &models.Config{}
+	config.ComponentToRepo = map[string]string{
+		"frontend": "https://github.com/example/frontend.git",
+		"web":      "https://github.com/example/frontend.git",
+		"backend":  "https://github.com/example/backend.git",
+	}
+	config.Jira.EpicBatching.MaxTickets = 2
+	resolver := NewRepositoryResolver(&mocks.MockJiraService{}, config, zap.NewNop())
+	issues := []models.JiraIssue{
+		epicIssue("PROJ-1", "frontend", "PROJ-100"),
+		epicIssue("PROJ-2", "backend", "PROJ-100"),
+		epicIssue("PROJ-3", "web", "PROJ-100"),
+		epicIssue("PROJ-4", "frontend", ""),
+		epicIssue("PROJ-5", "frontend", "PROJ-100"),
+		epicIssue("PROJ-6", "frontend", "PROJ-200"),
+		epicIssue("PROJ-7", "unmapped", "PROJ-100"),
+	}
+
+	if got := fmt.Sprint(epicBatches(context.Background(), resolver, config, issues)); got != "[[PROJ-1] [PROJ-2] [PROJ-3] [PROJ-4] [PROJ-5] [PROJ-6] [PROJ-7]]" {
+		t.Errorf("Expected no batches when disabled, got %s", got)
+	}
+
+	config.Jira.EpicBatching.Enabled = true
+	if got := fmt.Sprint(epicBatches(context.Background(), resolver, config, issues)); got != "[[PROJ-1 PROJ-3] [PROJ-2] [PROJ-4] [PROJ-5] [PROJ-6] [PROJ-7]]" {
+		t.Errorf("Unexpected batches %s", got)
+	}
+
+	config.Jira.EpicBatching.Label = "batch"
+	issues[0].Fields.Labels = []string{"batch"}
+	issues[4].Fields.Labels = []string{"batch"}
+	if got := fmt.Sprint(epicBatches(context.Background(), resolver, config, issues)); got != "[[PROJ-1 PROJ-5] [PROJ-2] [PROJ-3] [PROJ-4] [PROJ-6] [PROJ-7]]" {
+		t.Errorf("Expected only labeled tickets to be batched, got %s", got)
+	}
+}
+
+func TestTicketProcessor_ProcessTicketBatch(t *testing.T) {
+	var prTitle, prBody, prompt, commitMessage string
+	githubService := &mocks.MockGitHubService{
+		CommitChangesFunc: func(directory, message string) error {
+			commitMessage = message
+			return nil
+		},
+		CreatePullRequestFunc: func(owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error) {
+			prTitle, prBody = title, body
+			return &models.GitHubCreatePRResponse{Number: 1, HTMLURL: "https://github.com/example/frontend/pull/1"}, nil
+		},
+	}
+	var mu sync.Mutex
+	statuses := map[string][]string{}
+	comments := map[string][]string{}
+	var links []string
+	jiraService := &mocks.MockJiraService{
+		GetTicketFunc: func(key string) (*models.JiraTicketResponse, error) {
+			component := "frontend"
+			if key == "TEST-3" {
+				component = "backend"
+			}
+			return &models.JiraTicketResponse{
+				Key: key,
+				Fields: models.JiraFields{
+					Summary:     "Summary of " + key,
+					Description: "Description of " + key,
+					Components:  []models.JiraComponent{{Name: component}},
+				},
+			}, nil
+		},
+		UpdateTicketStatusFunc: func(key, status string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			statuses[key] = append(statuses[key], status)
+			return nil
+		},
+		AddCommentFunc: func(key, comment string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			comments[key] = append(comments[key], comment)
+			return nil
+		},
+		LinkTicketsFunc: func(linkType, inwardKey, outwardKey string) error {
+			links = append(links, fmt.Sprintf("%s %s %s", inwardKey, linkType, outwardKey))
+			return nil
+		},
+	}
+	processor, stateMachine, _ := newPipelineTestProcessor(t, githubService, jiraService, nil)
+	impl := processor.(*TicketProcessorImpl)
+	impl.config.ComponentToRepo["backend"] = "https://github.com/example/backend.git"
+	impl.config.Jira.EpicBatching.LinkType = "Relates"
+	impl.aiService = &mocks.MockClaudeService{
+		GenerateCodeFunc: func(p, dir string) (*models.ClaudeResponse, error) {
+			prompt = p
+			return &models.ClaudeResponse{}, nil
+		},
+	}
+
+	if err := processor.ProcessTicketBatch(context.Background(), []string{"TEST-1", "TEST-2", "TEST-3"}); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if !strings.Contains(prompt, "Jira ticket TEST-2\nSummary: Summary of TEST-2\nDescription: Description of TEST-2") {
+		t.Errorf("Expected the batched ticket in the prompt, got %q", prompt)
+	}
+	if strings.Contains(prompt, "TEST-3") {
+		t.Errorf("Expected the ticket of another repository to be left out, got %q", prompt)
+	}
+	if commitMessage != "TEST-1, TEST-2: Summary of TEST-1" || prTitle != commitMessage {
+		t.Errorf("Expected the commit and PR to name both tickets, got %q and %q", commitMessage, prTitle)
+	}
+	if !strings.Contains(prBody, "**Also resolves:** TEST-2\n- TEST-2: Summary of TEST-2") {
+		t.Errorf("Expected the PR body to list the batched ticket, got %q", prBody)
+	}
+	if fmt.Sprint(links) != "[TEST-1 Relates TEST-2]" {
+		t.Errorf("Expected the tickets to be linked, got %v", links)
+	}
+	if fmt.Sprint(statuses["TEST-2"]) != "[In Progress In Review]" {
+		t.Errorf("Expected the batched ticket to move to review, got %v", statuses["TEST-2"])
+	}
+	if len(comments["TEST-2"]) == 0 || !strings.Contains(comments["TEST-2"][len(comments["TEST-2"])-1], "resolved together with TEST-1") {
+		t.Errorf("Expected the PR comment on the batched ticket, got %v", comments["TEST-2"])
+	}
+	if len(statuses["TEST-3"]) != 0 {
+		t.Errorf("Expected the ticket of another repository to be left for a later scan, got %v", statuses["TEST-3"])
+	}
+	for _, key := range []string{"TEST-1", "TEST-2"} {
+		if state, _ := stateMachine.State(key); state != models.TicketStatePROpen {
+			t.Errorf("Expected %s to have its PR open, got %s", key, state)
+		}
+	}
+	if _, tracked := stateMachine.State("TEST-3"); tracked {
+		t.Error("Expected the ticket of another repository not to be tracked")
+	}
+}
+
+func TestPRReviewProcessor_CloseMergedTicket_Batch(t *testing.T) {
+	config := newAutoMergeConfig()
+	var closed []string
+	jiraService := &mocks.MockJiraService{
+		UpdateTicketStatusFunc: func(key, newStatus string) error {
+			closed = append(closed, key+" "+newStatus)
+			return nil
+		},
+	}
+	processor := &PRReviewProcessorImpl{
+		jiraService:   jiraService,
+		githubService: &mocks.MockGitHubService{},
+		stateMachine:  newTestStateMachine(config),
+		config:        config,
+		logger:        zap.NewNop(),
+	}
+
+	pr := newApprovedPR()
+	pr.Body = "This PR addresses the issue described in TEST-1.\n\n**Also resolves:** TEST-2, TEST-3\n- TEST-2: a\n- TEST-3: b"
+	if err := processor.closeMergedTicket(context.Background(), "TEST-1", pr, "def5678"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if fmt.Sprint(closed) != "[TEST-1 Closed TEST-2 Closed TEST-3 Closed]" {
+		t.Errorf("Expected the batched tickets to be closed with the ticket, got %v", closed)
+	}
+}
+
+func TestJiraIssueScanner_ProcessBatchAsync(t *testing.T) {
+	batches := make(chan []string, 1)
+	scanner := &JiraIssueScannerServiceImpl{
+		queue: newTicketQueue(t.Context(), 1),
+		ticketProcessor: &mocks.MockTicketProcessor{
+			ProcessTicketBatchFunc: func(keys []string) error {
+				batches <- keys
+				return nil
+			},
+		},
+		logger: zap.NewNop(),
+	}
+	scanner.inFlight.Store("TEST-2", struct{}{})
+
+	scanner.processBatchAsync(context.Background(), []string{"TEST-1", "TEST-2", "TEST-3"})
+	select {
+	case keys := <-batches:
+		if fmt.Sprint(keys) != "[TEST-1 TEST-3]" {
+			t.Errorf("Expected the ticket in flight to be left out, got %v", keys)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the batch to be processed")
+	}
+}
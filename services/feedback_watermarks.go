@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// FeedbackWatermarkStore persists how far the feedback of each pull request has been processed
+type FeedbackWatermarkStore interface {
+	// Get returns the watermark of a PR and whether one was stored
+	Get(pr string) (models.FeedbackWatermark, bool, error)
+	// Set stores the watermark of a PR
+	Set(pr string, watermark models.FeedbackWatermark) error
+}
+
+// FeedbackWatermarkStoreImpl implements the FeedbackWatermarkStore interface as a JSON file keyed by PR
+type FeedbackWatermarkStoreImpl struct {
+	path string
+
+	mu         sync.Mutex
+	watermarks map[string]models.FeedbackWatermark
+}
+
+// NewFeedbackWatermarkStore creates a new FeedbackWatermarkStore persisted to the given file. The file is read on
+// first use. Watermarks are only kept in memory when the path is empty.
+func NewFeedbackWatermarkStore(path string) FeedbackWatermarkStore {
+	return &FeedbackWatermarkStoreImpl{path: path}
+}
+
+// feedbackWatermarkKey returns the key a PR's watermark is stored under
+func feedbackWatermarkKey(owner, repo string, prNumber int) string {
+	return fmt.Sprintf("%s/%s#%d", owner, repo, prNumber)
+}
+
+// Get returns the watermark of a PR and whether one was stored
+func (s *FeedbackWatermarkStoreImpl) Get(pr string) (models.FeedbackWatermark, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return models.FeedbackWatermark{}, false, err
+	}
+	watermark, ok := s.watermarks[pr]
+	return watermark, ok, nil
+}
+
+// Set stores the watermark of a PR
+func (s *FeedbackWatermarkStoreImpl) Set(pr string, watermark models.FeedbackWatermark) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return err
+	}
+	s.watermarks[pr] = watermark
+	return s.save()
+}
+
+// load reads the watermarks file once. The caller must hold the lock.
+func (s *FeedbackWatermarkStoreImpl) load() error {
+	if s.watermarks != nil {
+		return nil
+	}
+
+	watermarks := make(map[string]models.FeedbackWatermark)
+	if s.path != "" {
+		data, err := os.ReadFile(s.path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to read feedback watermarks: %w", err)
+		}
+		if err == nil {
+			if err := json.Unmarshal(data, &watermarks); err != nil {
+				return fmt.Errorf("failed to parse feedback watermarks: %w", err)
+			}
+		}
+	}
+	s.watermarks = watermarks
+	return nil
+}
+
+// save persists the watermarks. The caller must hold the lock.
+func (s *FeedbackWatermarkStoreImpl) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s.watermarks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal feedback watermarks: %w", err)
+	}
+
+	if err := writeFileAtomic(s.path, data); err != nil {
+		return fmt.Errorf("failed to save feedback watermarks: %w", err)
+	}
+	return nil
+}
+
+// loadFeedbackWatermark returns how far the PR's feedback was processed. PRs without a stored watermark are
+// migrated from the timestamp comments earlier versions posted; a PR without either has all its feedback new.
+func (p *PRReviewProcessorImpl) loadFeedbackWatermark(ctx context.Context, ticketKey, owner, repo string, prNumber int) models.FeedbackWatermark {
+	key := feedbackWatermarkKey(owner, repo, prNumber)
+	if p.watermarks != nil {
+		watermark, ok, err := p.watermarks.Get(key)
+		if err != nil {
+			p.logger.Error("Failed to get feedback watermark", zap.String("ticket", ticketKey), zap.Error(err))
+		} else if ok {
+			return watermark
+		}
+	}
+
+	lastProcessedTime, err := p.getLastProcessingTimestamp(ctx, owner, repo, prNumber)
+	if err != nil {
+		p.logger.Error("Failed to get last processing timestamp", zap.String("ticket", ticketKey), zap.Error(err))
+		// Continue with processing, all feedback is new
+		return models.FeedbackWatermark{}
+	}
+
+	watermark := models.FeedbackWatermark{ProcessedAt: lastProcessedTime}
+	if !lastProcessedTime.IsZero() {
+		p.logger.Info("Migrating feedback watermark from PR timestamp comments",
+			zap.String("ticket", ticketKey),
+			zap.String("pr", key),
+			zap.Time("last_processed", lastProcessedTime))
+		p.saveFeedbackWatermark(ticketKey, owner, repo, prNumber, watermark)
+	}
+	return watermark
+}
+
+// saveFeedbackWatermark stores how far the PR's feedback was processed. A failure only means feedback may be
+// processed again, so it is logged.
+func (p *PRReviewProcessorImpl) saveFeedbackWatermark(ticketKey, owner, repo string, prNumber int, watermark models.FeedbackWatermark) {
+	if p.watermarks == nil {
+		return
+	}
+	if err := p.watermarks.Set(feedbackWatermarkKey(owner, repo, prNumber), watermark); err != nil {
+		p.logger.Error("Failed to save feedback watermark", zap.String("ticket", ticketKey), zap.Error(err))
+	}
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestFeedbackWatermarkStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feedback-watermarks.json")
+	store := NewFeedbackWatermarkStore(path)
+
+	if _, ok, err := store.Get("example/repo#7"); err != nil || ok {
+		t.Fatalf("Expected no watermark in a missing file, got %v, %v", ok, err)
+	}
+
+	watermark := models.FeedbackWatermark{ReviewID: 3, CommentID: 5, ProcessedAt: time.Date(2024, 7, 10, 12, 0, 0, 0, time.UTC)}
+	if err := store.Set("example/repo#7", watermark); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	// A new store reads the persisted watermarks
+	loaded, ok, err := NewFeedbackWatermarkStore(path).Get("example/repo#7")
+	if err != nil || !ok {
+		t.Fatalf("Expected the persisted watermark, got %v, %v", ok, err)
+	}
+	if loaded != watermark {
+		t.Errorf("Expected %+v, got %+v", watermark, loaded)
+	}
+}
+
+func TestFeedbackWatermark(t *testing.T) {
+	processedAt := time.Date(2024, 7, 10, 12, 0, 0, 0, time.UTC)
+	watermark := models.FeedbackWatermark{ProcessedAt: processedAt}
+
+	// Migrated watermarks compare times
+	old := models.GitHubPRComment{ID: 100, CreatedAt: processedAt.Add(-time.Minute)}
+	recent := models.GitHubPRComment{ID: 101, CreatedAt: processedAt.Add(time.Minute)}
+	if watermark.IsNewComment(old) || !watermark.IsNewComment(recent) {
+		t.Error("Expected comments to be compared by time without a processed ID")
+	}
+
+	// Advanced watermarks compare IDs, so feedback posted while a run was busy stays new
+	pr := &models.GitHubPRDetails{
+		Reviews:        []models.GitHubReview{{ID: 40}, {ID: 42}},
+		Comments:       []models.GitHubPRComment{old, recent},
+		ReviewComments: []models.GitHubPRComment{{ID: 7}},
+	}
+	advanced := watermark.Advance(pr, processedAt.Add(time.Hour))
+	if advanced.ReviewID != 42 || advanced.CommentID != 101 || advanced.ReviewCommentID != 7 {
+		t.Errorf("Expected the newest IDs to be recorded, got %+v", advanced)
+	}
+	if advanced.IsNewComment(recent) || !advanced.IsNewComment(models.GitHubPRComment{ID: 102, CreatedAt: processedAt}) {
+		t.Error("Expected comments to be compared by ID once one was processed")
+	}
+	if advanced.IsNewReviewComment(models.GitHubPRComment{ID: 7}) || !advanced.IsNewReviewComment(models.GitHubPRComment{ID: 8}) {
+		t.Error("Expected review comments to be compared by their own ID")
+	}
+}
+
+func TestPRReviewProcessor_LoadFeedbackWatermark_Migration(t *testing.T) {
+	listCalls := 0
+	githubService := &mocks.MockGitHubService{
+		ListPRCommentsFunc: func(owner, repo string, prNumber int) ([]models.GitHubPRComment, error) {
+			listCalls++
+			return []models.GitHubPRComment{{
+				User: models.GitHubUser{Login: "ai-bot"},
+				Body: "🤖 AI Processing Timestamp: 2024-07-10T12:00:00Z\n\nAI has processed feedback for ticket TEST-1 at this time.",
+			}}, nil
+		},
+	}
+	store := NewFeedbackWatermarkStore(filepath.Join(t.TempDir(), "feedback-watermarks.json"))
+	processor := &PRReviewProcessorImpl{
+		githubService: githubService,
+		watermarks:    store,
+		config:        newBotConfig("ai-bot"),
+		logger:        zap.NewNop(),
+	}
+
+	expected := time.Date(2024, 7, 10, 12, 0, 0, 0, time.UTC)
+	if watermark := processor.loadFeedbackWatermark(context.Background(), "TEST-1", "example", "repo", 7); !watermark.ProcessedAt.Equal(expected) {
+		t.Errorf("Expected the watermark to be migrated from the timestamp comment, got %+v", watermark)
+	}
+	stored, ok, err := store.Get("example/repo#7")
+	if err != nil || !ok || !stored.ProcessedAt.Equal(expected) {
+		t.Errorf("Expected the migrated watermark to be stored, got %+v, %v, %v", stored, ok, err)
+	}
+
+	// Once stored, the PR comments aren't read again
+	processor.loadFeedbackWatermark(context.Background(), "TEST-1", "example", "repo", 7)
+	if listCalls != 1 {
+		t.Errorf("Expected the timestamp comments to be read once, got %d", listCalls)
+	}
+}
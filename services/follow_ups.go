@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// maxFollowUpTitleLength bounds the summary of follow-up tickets
+const maxFollowUpTitleLength = 120
+
+// followUpsHeadingPattern matches the heading the AI puts above its follow-ups, e.g. "## Follow-ups" or "**Follow-ups:**"
+var followUpsHeadingPattern = regexp.MustCompile(`(?i)^(#+\s*)?\**\s*follow[- ]?ups?\s*:?\s*\**\s*:?$`)
+
+// followUpItemPattern matches a list item, capturing its text
+var followUpItemPattern = regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s+(.+)$`)
+
+// FollowUp is follow-up work the AI noted but didn't complete
+type FollowUp struct {
+	Title   string
+	Details string
+}
+
+// followUpsPrompt asks the AI to report follow-up work in a form parseFollowUps understands
+func followUpsPrompt() string {
+	return "\n\nIf you leave TODOs or notice follow-up work that is out of scope or that you couldn't complete, " +
+		"end your final summary with a section headed \"Follow-ups:\" listing one item per line as \"- <short title>: <details>\". " +
+		"Leave the section out if there is nothing to follow up on."
+}
+
+// aiResultText returns the final summary of an AI response
+func aiResultText(response interface{}) string {
+	switch r := response.(type) {
+	case *AIFailoverResponse:
+		return aiResultText(r.Response)
+	case *models.ClaudeResponse:
+		if r != nil {
+			return r.Result
+		}
+	case *models.GeminiResponse:
+		if r != nil {
+			return r.Result
+		}
+	}
+	return ""
+}
+
+// parseFollowUps parses the list items of the follow-ups section of the AI's summary
+func parseFollowUps(summary string) []FollowUp {
+	var followUps []FollowUp
+	inSection := false
+	for _, line := range strings.Split(summary, "\n") {
+		line = strings.TrimSpace(line)
+		if followUpsHeadingPattern.MatchString(line) {
+			inSection = true
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		if line == "" {
+			if len(followUps) > 0 {
+				break
+			}
+			continue
+		}
+
+		match := followUpItemPattern.FindStringSubmatch(line)
+		if match == nil {
+			// Anything but a list item ends the section
+			break
+		}
+
+		item := strings.TrimSpace(match[1])
+		followUp := FollowUp{Title: item}
+		if title, details, ok := strings.Cut(item, ": "); ok {
+			followUp = FollowUp{Title: strings.TrimSpace(title), Details: strings.TrimSpace(details)}
+		}
+		followUp.Title = strings.Trim(followUp.Title, "*` ")
+		if len(followUp.Title) > maxFollowUpTitleLength {
+			followUp.Title = followUp.Title[:maxFollowUpTitleLength-3] + "..."
+		}
+		if followUp.Title != "" {
+			followUps = append(followUps, followUp)
+		}
+	}
+	return followUps
+}
+
+// createFollowUpTickets creates a ticket per follow-up, linked to the ticket, and lists them in a ticket comment.
+// Follow-ups are created once per ticket; failures are logged and don't fail the ticket.
+func (p *TicketProcessorImpl) createFollowUpTickets(ctx context.Context, run *TicketRun) {
+	ticketKey, ticket := run.Key, run.Ticket
+	followUps := run.FollowUps
+	if len(followUps) == 0 {
+		return
+	}
+
+	comments, err := p.jiraService.GetComments(ctx, ticketKey)
+	if err != nil {
+		p.logger.Error("Failed to get comments, not creating follow-up tickets", zap.String("ticket", ticketKey), zap.Error(err))
+		return
+	}
+	marker := jiraCommentMarker(commentKeyFollowUps)
+	for _, comment := range comments {
+		if strings.Contains(comment.Body, marker) {
+			p.logger.Debug("Follow-up tickets already created", zap.String("ticket", ticketKey))
+			return
+		}
+	}
+
+	followUpConfig := p.config.Jira.FollowUps
+	if followUpConfig.MaxIssues > 0 && len(followUps) > followUpConfig.MaxIssues {
+		p.logger.Warn("Too many follow-ups, creating tickets for the first ones only",
+			zap.String("ticket", ticketKey),
+			zap.Int("follow_ups", len(followUps)),
+			zap.Int("max_issues", followUpConfig.MaxIssues))
+		followUps = followUps[:followUpConfig.MaxIssues]
+	}
+
+	project := ticketProjectKey(ticket)
+	var components []models.JiraNameRef
+	for _, component := range ticket.Fields.Components {
+		components = append(components, models.JiraNameRef{Name: component.Name})
+	}
+	source := ticketKey
+	if len(run.PRs) > 0 {
+		source = fmt.Sprintf("%s (%s)", ticketKey, run.PRs[0].HTMLURL)
+	}
+
+	var created []string
+	for _, followUp := range followUps {
+		description := fmt.Sprintf("Follow-up work noted by the AI while implementing %s.", source)
+		if followUp.Details != "" {
+			description += "\n\n" + followUp.Details
+		}
+
+		key, err := p.jiraService.CreateTicket(ctx, models.JiraCreateIssueFields{
+			Project:     models.JiraKeyRef{Key: project},
+			IssueType:   models.JiraNameRef{Name: followUpConfig.IssueType},
+			Summary:     followUp.Title,
+			Description: description,
+			Labels:      followUpConfig.Labels,
+			Components:  components,
+		})
+		if err != nil {
+			p.logger.Error("Failed to create follow-up ticket",
+				zap.String("ticket", ticketKey),
+				zap.String("follow_up", followUp.Title),
+				zap.Error(err))
+			continue
+		}
+
+		if err := p.jiraService.LinkTickets(ctx, followUpConfig.LinkType, ticketKey, key); err != nil {
+			p.logger.Error("Failed to link follow-up ticket",
+				zap.String("ticket", ticketKey),
+				zap.String("follow_up_ticket", key),
+				zap.Error(err))
+		}
+
+		p.logger.Info("Created follow-up ticket", zap.String("ticket", ticketKey), zap.String("follow_up_ticket", key))
+		created = append(created, fmt.Sprintf("- %s: %s", key, followUp.Title))
+	}
+
+	if len(created) == 0 {
+		return
+	}
+	comment := "AI noted follow-up work it didn't complete and created these tickets:\n" + strings.Join(created, "\n")
+	if err := upsertJiraComment(ctx, p.jiraService, p.logger, ticketKey, commentKeyFollowUps, comment); err != nil {
+		p.logger.Error("Failed to add follow-up comment", zap.String("ticket", ticketKey), zap.Error(err))
+	}
+}
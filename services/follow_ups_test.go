@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestParseFollowUps(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary string
+		want    []FollowUp
+	}{
+		{
+			name:    "no follow-ups",
+			summary: "Fixed the login redirect.",
+		},
+		{
+			name: "plain heading",
+			summary: "Fixed the login redirect.\n\nFollow-ups:\n" +
+				"- Add retries: the token refresh has no retry on 503\n" +
+				"- Remove the legacy redirect flag\n",
+			want: []FollowUp{
+				{Title: "Add retries", Details: "the token refresh has no retry on 503"},
+				{Title: "Remove the legacy redirect flag"},
+			},
+		},
+		{
+			name:    "markdown heading and numbered items",
+			summary: "## Follow-ups\n\n1. **Migrate the tests**: they still use the old client\n2) Update docs\n\nAll done.",
+			want: []FollowUp{
+				{Title: "Migrate the tests", Details: "they still use the old client"},
+				{Title: "Update docs"},
+			},
+		},
+		{
+			name:    "section ends at prose",
+			summary: "**Follow-ups:**\n* Bump the SDK\nLet me know if anything else is needed.\n- Not a follow-up",
+			want:    []FollowUp{{Title: "Bump the SDK"}},
+		},
+		{
+			name:    "long title",
+			summary: "Follow-ups:\n- " + strings.Repeat("a", 200),
+			want:    []FollowUp{{Title: strings.Repeat("a", maxFollowUpTitleLength-3) + "..."}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseFollowUps(tt.summary)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAIResultText(t *testing.T) {
+	claude := &models.ClaudeResponse{Result: "claude summary"}
+	if got := aiResultText(&AIFailoverResponse{Response: claude}); got != "claude summary" {
+		t.Errorf("Expected the failover response to be unwrapped, got %q", got)
+	}
+	if got := aiResultText(&models.GeminiResponse{Result: "gemini summary"}); got != "gemini summary" {
+		t.Errorf("Expected the Gemini result, got %q", got)
+	}
+	if got := aiResultText(nil); got != "" {
+		t.Errorf("Expected no text without a response, got %q", got)
+	}
+}
+
+// newFollowUpTestProcessor creates a processor that creates follow-up tickets
+func newFollowUpTestProcessor(jiraService *mocks.MockJiraService) *TicketProcessorImpl {
+	config := &models.Config{}
+	config.Jira.FollowUps.Enabled = true
+	config.Jira.FollowUps.IssueType = "Task"
+	config.Jira.FollowUps.LinkType = "Relates"
+	config.Jira.FollowUps.Labels = []string{"ai-follow-up"}
+	config.Jira.FollowUps.MaxIssues = 2
+	return &TicketProcessorImpl{
+		jiraService: jiraService,
+		config:      config,
+		logger:      zap.NewNop(),
+	}
+}
+
+func TestTicketProcessor_CreateFollowUpTickets(t *testing.T) {
+	var created []models.JiraCreateIssueFields
+	var links []string
+	var comments []string
+	jiraService := &mocks.MockJiraService{
+		CreateTicketFunc: func(fields models.JiraCreateIssueFields) (string, error) {
+			created = append(created, fields)
+			if fields.Summary == "Broken" {
+				return "", fmt.Errorf("issue type not allowed")
+			}
+			return fmt.Sprintf("PROJ-%d", 100+len(created)), nil
+		},
+		LinkTicketsFunc: func(linkType, inwardKey, outwardKey string) error {
+			links = append(links, fmt.Sprintf("%s %s %s", inwardKey, linkType, outwardKey))
+			return nil
+		},
+		AddCommentFunc: func(key, comment string) error {
+			comments = append(comments, comment)
+			return nil
+		},
+	}
+	processor := newFollowUpTestProcessor(jiraService)
+
+	run := &TicketRun{
+		Key: "PROJ-1",
+		Ticket: &models.JiraTicketResponse{
+			Key: "PROJ-1",
+			Fields: models.JiraFields{
+				Project:    models.JiraProject{Key: "PROJ"},
+				Components: []models.JiraComponent{{ID: "1", Name: "frontend"}},
+			},
+		},
+		PRs: []*models.GitHubCreatePRResponse{{HTMLURL: "https://github.com/example/frontend/pull/7"}},
+		FollowUps: []FollowUp{
+			{Title: "Add retries", Details: "no retry on 503"},
+			{Title: "Broken"},
+			{Title: "Over the limit"},
+		},
+	}
+	processor.createFollowUpTickets(context.Background(), run)
+
+	if len(created) != 2 {
+		t.Fatalf("Expected tickets for the first 2 follow-ups, got %d", len(created))
+	}
+	first := created[0]
+	if first.Project.Key != "PROJ" || first.IssueType.Name != "Task" || first.Summary != "Add retries" {
+		t.Errorf("Unexpected follow-up ticket fields: %+v", first)
+	}
+	if !strings.Contains(first.Description, "pull/7") || !strings.Contains(first.Description, "no retry on 503") {
+		t.Errorf("Expected the description to reference the PR and details, got %q", first.Description)
+	}
+	if fmt.Sprint(first.Labels) != "[ai-follow-up]" || len(first.Components) != 1 || first.Components[0].Name != "frontend" {
+		t.Errorf("Expected the configured labels and the ticket's components, got %+v", first)
+	}
+	if fmt.Sprint(links) != "[PROJ-1 Relates PROJ-101]" {
+		t.Errorf("Expected only the created ticket to be linked, got %v", links)
+	}
+	if len(comments) != 1 || !strings.Contains(comments[0], "PROJ-101: Add retries") || strings.Contains(comments[0], "Broken") {
+		t.Errorf("Expected a comment listing the created tickets, got %v", comments)
+	}
+}
+
+func TestTicketProcessor_CreateFollowUpTicketsOnce(t *testing.T) {
+	jiraService := &mocks.MockJiraService{
+		GetCommentsFunc: func(key string) ([]models.JiraComment, error) {
+			return []models.JiraComment{{ID: "1", Body: "Tickets created\n" + jiraCommentMarker(commentKeyFollowUps)}}, nil
+		},
+		CreateTicketFunc: func(fields models.JiraCreateIssueFields) (string, error) {
+			t.Error("Expected no follow-up tickets to be created again")
+			return "", nil
+		},
+	}
+	processor := newFollowUpTestProcessor(jiraService)
+
+	processor.createFollowUpTickets(context.Background(), &TicketRun{
+		Key:       "PROJ-1",
+		Ticket:    &models.JiraTicketResponse{Key: "PROJ-1"},
+		FollowUps: []FollowUp{{Title: "Add retries"}},
+	})
+}
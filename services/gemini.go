@@ -21,7 +21,7 @@ import (
 type GeminiService interface {
 	AIService
 	// GenerateCodeGemini generates code using Gemini CLI and returns GeminiResponse
-	GenerateCodeGemini(prompt string, repoDir string) (*models.GeminiResponse, error)
+	GenerateCodeGemini(ctx context.Context, prompt string, repoDir string) (*models.GeminiResponse, error)
 }
 
 // GeminiServiceImpl implements the GeminiService interface
@@ -33,7 +33,7 @@ type GeminiServiceImpl struct {
 
 // NewGeminiService creates a new GeminiService
 func NewGeminiService(config *models.Config, logger *zap.Logger, executor ...models.CommandExecutor) GeminiService {
-	commandExecutor := exec.Command
+	commandExecutor := exec.CommandContext
 	if len(executor) > 0 {
 		commandExecutor = executor[0]
 	}
@@ -45,12 +45,12 @@ func NewGeminiService(config *models.Config, logger *zap.Logger, executor ...mod
 }
 
 // GenerateCode implements the AIService interface
-func (s *GeminiServiceImpl) GenerateCode(prompt string, repoDir string) (interface{}, error) {
-	return s.GenerateCodeGemini(prompt, repoDir)
+func (s *GeminiServiceImpl) GenerateCode(ctx context.Context, prompt string, repoDir string) (interface{}, error) {
+	return s.GenerateCodeGemini(ctx, prompt, repoDir)
 }
 
 // GenerateDocumentation implements the AIService interface
-func (s *GeminiServiceImpl) GenerateDocumentation(repoDir string) error {
+func (s *GeminiServiceImpl) GenerateDocumentation(ctx context.Context, repoDir string) error {
 	// Check if GEMINI.md already exists
 	geminiPath := filepath.Join(repoDir, "GEMINI.md")
 	if _, err := os.Stat(geminiPath); err == nil {
@@ -105,7 +105,7 @@ Search the entire repository for all .md files and create a comprehensive index
 IMPORTANT: Verify that you actually created and wrote GEMINI.md at the root of the project!`
 
 	// Generate the documentation using Gemini
-	response, err := s.GenerateCodeGemini(prompt, repoDir)
+	response, err := s.GenerateCodeGemini(ctx, prompt, repoDir)
 	if err != nil {
 		return fmt.Errorf("failed to generate GEMINI.md: %w", err)
 	}
@@ -145,9 +145,14 @@ IMPORTANT: Verify that you actually created and wrote GEMINI.md at the root of t
 }
 
 // GenerateCodeGemini generates code using Gemini CLI
-func (s *GeminiServiceImpl) GenerateCodeGemini(prompt string, repoDir string) (*models.GeminiResponse, error) {
+func (s *GeminiServiceImpl) GenerateCodeGemini(ctx context.Context, prompt string, repoDir string) (*models.GeminiResponse, error) {
 	// Build command arguments based on configuration
-	s.logger.Info("Generating code with Gemini", zap.String("repo_dir", repoDir), zap.String("prompt", prompt))
+	s.logger.Info("Generating code with Gemini", zap.String("repo_dir", repoDir))
+
+	// The raw output goes to the ticket's AI log, the main log only gets the high-level events
+	streamLogger, closeStreamLog := newAIStreamLogger(s.config, s.logger, repoDir)
+	defer closeStreamLog()
+	streamLogger.Debug("Gemini prompt", zap.String("prompt", prompt))
 
 	args := []string{"--debug", "--y"}
 	// Add model if configured
@@ -167,27 +172,24 @@ func (s *GeminiServiceImpl) GenerateCodeGemini(prompt string, repoDir string) (*
 
 	// Set up a context with timeout
 	timeout := time.Duration(s.config.Gemini.Timeout) * time.Second
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	// Create the command with context
-	cmd := exec.CommandContext(ctx, s.config.Gemini.CLIPath, args...)
-	cmd.Dir = repoDir
-
-	// Print the actual command being executed
-	s.logger.Debug("Executing Gemini CLI",
-		zap.String("command", s.config.Gemini.CLIPath),
-		zap.Strings("args", args),
-		zap.String("directory", repoDir))
-
-	// Set environment variables
-	cmd.Env = os.Environ()
-
 	// Set Gemini API key if configured
+	var env []string
 	if s.config.Gemini.APIKey != "" {
-		cmd.Env = append(cmd.Env, fmt.Sprintf("GEMINI_API_KEY=%s", s.config.Gemini.APIKey))
+		env = append(env, fmt.Sprintf("GEMINI_API_KEY=%s", s.config.Gemini.APIKey))
 	}
 
+	// Create the command with context, in the configured sandbox
+	cmd := aiCommand(ctx, s.config, repoDir, s.config.Gemini.CLIPath, env, args...)
+
+	// Print the actual command being executed
+	streamLogger.Debug("Executing Gemini CLI",
+		zap.String("command", cmd.Path),
+		zap.Strings("args", cmd.Args[1:]),
+		zap.String("directory", repoDir))
+
 	// Create pipes for stdout and stderr
 	stdoutPipe, err := cmd.StdoutPipe()
 	if err != nil {
@@ -207,10 +209,13 @@ func (s *GeminiServiceImpl) GenerateCodeGemini(prompt string, repoDir string) (*
 	var wg sync.WaitGroup
 	wg.Add(2) // We have two goroutines for logging (stdout and stderr)
 
+	// Keep the last stderr line so failures like exhausted quota can be told apart
+	var lastStderrLine string
+
 	// Log stdout concurrently
 	go func() {
 		defer func() {
-			s.logger.Debug("Gemini stdout logging goroutine finished")
+			streamLogger.Debug("Gemini stdout logging goroutine finished")
 			wg.Done()
 		}()
 		scanner := bufio.NewScanner(stdoutPipe)
@@ -224,7 +229,7 @@ func (s *GeminiServiceImpl) GenerateCodeGemini(prompt string, repoDir string) (*
 			cleaned := strings.ReplaceAll(line, "Flushing log events to Clearcut.", "")
 			cleaned = strings.TrimSpace(cleaned)
 			if cleaned != "" {
-				s.logger.Debug(cleaned)
+				streamLogger.Debug(cleaned)
 			}
 		}
 	}()
@@ -232,12 +237,15 @@ func (s *GeminiServiceImpl) GenerateCodeGemini(prompt string, repoDir string) (*
 	// Log stderr concurrently
 	go func() {
 		defer func() {
-			s.logger.Debug("Gemini stderr logging goroutine finished")
+			streamLogger.Debug("Gemini stderr logging goroutine finished")
 			wg.Done()
 		}()
 		scanner := bufio.NewScanner(stderrPipe)
 		for scanner.Scan() {
-			s.logger.Debug("=== Gemini stderr ===\n" + scanner.Text() + "\n===================")
+			streamLogger.Debug("=== Gemini stderr ===\n" + scanner.Text() + "\n===================")
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				lastStderrLine = line
+			}
 		}
 	}()
 
@@ -268,7 +276,7 @@ func (s *GeminiServiceImpl) GenerateCodeGemini(prompt string, repoDir string) (*
 		if ctx.Err() == context.DeadlineExceeded {
 			return nil, fmt.Errorf("gemini CLI timed out after %d seconds", s.config.Gemini.Timeout)
 		}
-		return nil, fmt.Errorf("gemini CLI failed: %w", err)
+		return nil, fmt.Errorf("gemini CLI failed: %w, stderr: %s", err, lastStderrLine)
 	}
 
 	// Create response indicating completion
@@ -296,8 +304,14 @@ func PreparePromptForGemini(ticket *models.JiraTicketResponse) string {
 	sb.WriteString("# Task\n\n")
 	sb.WriteString(fmt.Sprintf("## %s\n\n", ticket.Fields.Summary))
 	sb.WriteString(fmt.Sprintf("%s\n\n", ticket.Fields.Description))
+	for _, field := range ticket.PromptFields {
+		sb.WriteString(fmt.Sprintf("## %s\n\n%s\n\n", field.Name, field.Value))
+	}
 
 	// Add comments if available
+	if ticket.CommentSummary != "" {
+		sb.WriteString(fmt.Sprintf("## Earlier Comments\n\n%s\n\n", ticket.CommentSummary))
+	}
 	if len(ticket.Fields.Comment.Comments) > 0 {
 		sb.WriteString("## Comments\n\n")
 		for _, comment := range ticket.Fields.Comment.Comments {
@@ -324,7 +338,7 @@ func PreparePromptForGemini(ticket *models.JiraTicketResponse) string {
 }
 
 // PreparePromptForPRFeedbackGemini prepares a prompt for Gemini CLI based on PR feedback
-func PreparePromptForPRFeedbackGemini(pr *models.GitHubPullRequest, review *models.GitHubReview, repoDir string) (string, error) {
+func PreparePromptForPRFeedbackGemini(ctx context.Context, pr *models.GitHubPullRequest, review *models.GitHubReview, repoDir string) (string, error) {
 	var sb strings.Builder
 
 	sb.WriteString("# Pull Request Feedback\n\n")
@@ -335,7 +349,7 @@ func PreparePromptForPRFeedbackGemini(pr *models.GitHubPullRequest, review *mode
 	sb.WriteString(fmt.Sprintf("**%s**:\n%s\n\n", review.User.Login, review.Body))
 
 	// Get the diff of the PR
-	cmd := exec.Command("git", "diff", "origin/main...HEAD")
+	cmd := exec.CommandContext(ctx, "git", "diff", "origin/main...HEAD")
 	cmd.Dir = repoDir
 
 	var stdout bytes.Buffer
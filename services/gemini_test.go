@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -50,7 +51,7 @@ Please read CONTRIBUTING.md for details.
 	mockService := &mockGeminiServiceForTest{}
 
 	// Generate documentation
-	err = mockService.GenerateDocumentation(tempDir)
+	err = mockService.GenerateDocumentation(context.Background(), tempDir)
 	if err != nil {
 		t.Fatalf("GenerateDocumentation failed: %v", err)
 	}
@@ -61,7 +62,7 @@ Please read CONTRIBUTING.md for details.
 	}
 
 	// Test that calling GenerateDocumentation again doesn't fail (should skip)
-	err = mockService.GenerateDocumentation(tempDir)
+	err = mockService.GenerateDocumentation(context.Background(), tempDir)
 	if err != nil {
 		t.Fatalf("Second call to GenerateDocumentation failed: %v", err)
 	}
@@ -116,7 +117,7 @@ Please read CONTRIBUTING.md for details.
 	}
 
 	// Generate documentation - this should print the CLI output
-	err = service.GenerateDocumentation(tempDir)
+	err = service.GenerateDocumentation(context.Background(), tempDir)
 	if err != nil {
 		t.Logf("GenerateDocumentation failed (expected with echo): %v", err)
 		// This is expected to fail with echo, but we want to see the output
@@ -132,7 +133,7 @@ Please read CONTRIBUTING.md for details.
 // mockGeminiServiceForTest is a simple mock for testing
 type mockGeminiServiceForTest struct{}
 
-func (m *mockGeminiServiceForTest) GenerateDocumentation(repoDir string) error {
+func (m *mockGeminiServiceForTest) GenerateDocumentation(ctx context.Context, repoDir string) error {
 	// Check if GEMINI.md already exists
 	geminiPath := filepath.Join(repoDir, "GEMINI.md")
 	if _, err := os.Stat(geminiPath); err == nil {
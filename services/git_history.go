@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// maxHistoryKeywords is the number of keywords of the ticket summary the history is searched for
+const maxHistoryKeywords = 6
+
+// historyStopWords are words of ticket summaries too common to find related commits by; shorter words are skipped
+// anyway
+var historyStopWords = map[string]bool{
+	"about": true, "after": true, "again": true, "also": true, "broken": true, "cannot": true, "does": true,
+	"doesn": true, "error": true, "fail": true, "failing": true, "fails": true, "from": true, "have": true,
+	"incorrect": true, "into": true, "issue": true, "make": true, "should": true, "some": true, "that": true,
+	"then": true, "this": true, "update": true, "when": true, "while": true, "with": true, "without": true,
+	"work": true, "working": true, "wrong": true,
+}
+
+// historyKeywords returns the words of a ticket summary that are specific enough to find related commits by, such
+// as identifiers and domain terms
+func historyKeywords(summary string) []string {
+	var keywords []string
+	seen := make(map[string]bool)
+	words := strings.FieldsFunc(summary, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-'
+	})
+	for _, word := range words {
+		word = strings.Trim(word, "-_")
+		lower := strings.ToLower(word)
+		if len(word) < 4 || historyStopWords[lower] || seen[lower] {
+			continue
+		}
+		seen[lower] = true
+		keywords = append(keywords, word)
+		if len(keywords) == maxHistoryKeywords {
+			break
+		}
+	}
+	return keywords
+}
+
+// gitHistoryStep finds recent commits related to the ticket, by keywords of its summary in commit messages and file
+// paths, and keeps them for the generation prompt so regressions can be traced to the change that caused them.
+// Failing to read the history doesn't fail the ticket.
+func (p *TicketProcessorImpl) gitHistoryStep(ctx context.Context, run *TicketRun) error {
+	settings := p.config.AI.GitHistory
+	if !settings.Enabled {
+		return nil
+	}
+	keywords := historyKeywords(run.Ticket.Fields.Summary)
+	if len(keywords) == 0 {
+		p.logger.Info("No keywords to search the history for", zap.String("ticket", run.Key))
+		return nil
+	}
+
+	commits, err := p.githubService.RelatedCommits(ctx, run.RepoDir, keywords, settings.MaxCommits, settings.MaxDiffLines)
+	if err != nil {
+		p.logger.Warn("Failed to search the history for related commits",
+			zap.String("ticket", run.Key),
+			zap.String("repo_dir", run.RepoDir),
+			zap.Error(err))
+		return nil
+	}
+	p.logger.Info("Found related commits",
+		zap.String("ticket", run.Key),
+		zap.Strings("keywords", keywords),
+		zap.Int("commits", len(commits)))
+	run.GitHistory = gitHistoryPrompt(keywords, commits)
+	return nil
+}
+
+// gitHistoryPrompt formats the related commits for the generation prompt, or returns an empty string if there are none
+func gitHistoryPrompt(keywords []string, commits []models.GitCommit) string {
+	if len(commits) == 0 {
+		return ""
+	}
+
+	var prompt strings.Builder
+	prompt.WriteString(fmt.Sprintf("\n\nRecent commits that may be related to the ticket, found by %s in their messages "+
+		"or changed files. If the ticket describes a regression, check whether one of them caused it:\n",
+		strings.Join(keywords, ", ")))
+	for _, commit := range commits {
+		sha := commit.SHA
+		if len(sha) > 12 {
+			sha = sha[:12]
+		}
+		prompt.WriteString(fmt.Sprintf("\n%s %s %s: %s\n", sha, commit.Date, commit.Author, commit.Subject))
+		if commit.Diff != "" {
+			prompt.WriteString("```diff\n" + commit.Diff + "\n```\n")
+		}
+	}
+	return prompt.String()
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+)
+
+func TestHistoryKeywords(t *testing.T) {
+	tests := []struct {
+		summary string
+		want    []string
+	}{
+		{summary: "Login fails when the session_id cookie is missing", want: []string{"Login", "session_id", "cookie", "missing"}},
+		{summary: "Fix the bug in the API", want: nil},
+		{summary: "Invoice totals wrong; invoice PDF export broken", want: []string{"Invoice", "totals", "export"}},
+	}
+	for _, tt := range tests {
+		got := historyKeywords(tt.summary)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("historyKeywords(%q) = %q, want %q", tt.summary, got, tt.want)
+		}
+	}
+}
+
+func newGitHistoryTestRun() *TicketRun {
+	ticket := &models.JiraTicketResponse{Key: "TEST-1"}
+	ticket.Fields.Summary = "Login redirect loops"
+	return &TicketRun{Key: "TEST-1", Ticket: ticket, RepoDir: "/tmp/TEST-1"}
+}
+
+func TestTicketProcessor_GitHistoryStep(t *testing.T) {
+	config := &models.Config{}
+	config.AI.GitHistory.Enabled = true
+	config.AI.GitHistory.MaxCommits = 5
+	config.AI.GitHistory.MaxDiffLines = 40
+
+	var keywords []string
+	githubService := &mocks.MockGitHubService{
+		RelatedCommitsFunc: func(directory string, k []string, maxCommits, maxDiffLines int) ([]models.GitCommit, error) {
+			keywords = k
+			return []models.GitCommit{{
+				SHA:     "0123456789abcdef",
+				Author:  "Jane",
+				Date:    "2024-01-04",
+				Subject: "Redirect after login",
+				Diff:    "+return redirect(next)",
+			}}, nil
+		},
+	}
+	processor := newCheckTestProcessor(t, config, &mocks.MockClaudeService{}, &mocks.MockJiraService{}, githubService)
+
+	run := newGitHistoryTestRun()
+	if err := processor.gitHistoryStep(context.Background(), run); err != nil {
+		t.Fatalf("gitHistoryStep() error = %v", err)
+	}
+	if strings.Join(keywords, ",") != "Login,redirect,loops" {
+		t.Errorf("Expected the keywords of the summary, got %q", keywords)
+	}
+	for _, want := range []string{"found by Login, redirect, loops", "0123456789ab 2024-01-04 Jane: Redirect after login", "```diff\n+return redirect(next)\n```"} {
+		if !strings.Contains(run.GitHistory, want) {
+			t.Errorf("Expected the prompt section to contain %q, got:\n%s", want, run.GitHistory)
+		}
+	}
+}
+
+func TestTicketProcessor_GitHistoryStep_Failure(t *testing.T) {
+	config := &models.Config{}
+	config.AI.GitHistory.Enabled = true
+	githubService := &mocks.MockGitHubService{
+		RelatedCommitsFunc: func(directory string, keywords []string, maxCommits, maxDiffLines int) ([]models.GitCommit, error) {
+			return nil, errors.New("git log failed")
+		},
+	}
+	processor := newCheckTestProcessor(t, config, &mocks.MockClaudeService{}, &mocks.MockJiraService{}, githubService)
+
+	run := newGitHistoryTestRun()
+	if err := processor.gitHistoryStep(context.Background(), run); err != nil {
+		t.Fatalf("Expected a history failure not to fail the ticket, got %v", err)
+	}
+	if run.GitHistory != "" {
+		t.Errorf("Expected no prompt section, got %q", run.GitHistory)
+	}
+}
+
+func TestTicketProcessor_GitHistoryStep_Disabled(t *testing.T) {
+	githubService := &mocks.MockGitHubService{
+		RelatedCommitsFunc: func(directory string, keywords []string, maxCommits, maxDiffLines int) ([]models.GitCommit, error) {
+			t.Error("Expected the history not to be searched when disabled")
+			return nil, nil
+		},
+	}
+	processor := newCheckTestProcessor(t, &models.Config{}, &mocks.MockClaudeService{}, &mocks.MockJiraService{}, githubService)
+
+	if err := processor.gitHistoryStep(context.Background(), newGitHistoryTestRun()); err != nil {
+		t.Fatalf("gitHistoryStep() error = %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+)
+
+// sshRemoteURL builds the SSH remote URL of a GitHub repository
+func sshRemoteURL(owner, repo string) string {
+	return fmt.Sprintf("git@github.com:%s/%s.git", owner, repo)
+}
+
+// remoteURL returns the origin URL of a repository for its git protocol: the SSH URL for SSH repositories,
+// the HTTPS URL with a fresh token embedded otherwise
+func (s *GitHubServiceImpl) remoteURL(owner, repo string) (string, error) {
+	if s.config.GetGitProtocol(owner, repo) == models.GitProtocolSSH {
+		return sshRemoteURL(owner, repo), nil
+	}
+
+	token, err := s.getAuthToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to get auth token: %w", err)
+	}
+	return s.authenticatedRemoteURL(token, owner, repo), nil
+}
+
+// transportCloneArgs builds the git clone arguments for the repository's git protocol. SSH repositories are
+// cloned from their SSH URL, and the SSH command is kept in the new repository's config for later fetches and pushes.
+func (s *GitHubServiceImpl) transportCloneArgs(repoURL, directory string, opts models.CloneOptions) ([]string, error) {
+	owner, repo, err := ExtractRepoInfo(repoURL)
+	if err != nil || s.config.GetGitProtocol(owner, repo) != models.GitProtocolSSH {
+		return cloneArgs(repoURL, directory, opts), nil
+	}
+
+	sshCommand, err := s.sshCommand()
+	if err != nil {
+		return nil, err
+	}
+	args := cloneArgs(sshRemoteURL(owner, repo), directory, opts)
+	return append([]string{"clone", "--config", "core.sshCommand=" + sshCommand}, args[1:]...), nil
+}
+
+// configureSSH makes git in a repository use the configured SSH key and known hosts
+func (s *GitHubServiceImpl) configureSSH(ctx context.Context, directory string) error {
+	sshCommand, err := s.sshCommand()
+	if err != nil {
+		return err
+	}
+	if err := s.runGit(ctx, directory, "config", "core.sshCommand", sshCommand); err != nil {
+		return fmt.Errorf("failed to configure SSH command: %w", err)
+	}
+	return nil
+}
+
+// sshCommand returns the SSH command git connects with, after making sure the configured host keys are trusted
+func (s *GitHubServiceImpl) sshCommand() (string, error) {
+	sshConfig := s.config.GitHub.Git.SSH
+	knownHostsFile := s.knownHostsFile()
+
+	s.knownHostsOnce.Do(func() {
+		s.knownHostsErr = addKnownHosts(knownHostsFile, sshConfig.KnownHosts)
+	})
+	if s.knownHostsErr != nil {
+		return "", s.knownHostsErr
+	}
+
+	args := []string{"ssh"}
+	if sshConfig.KeyFile != "" {
+		// Only offer the configured key, not whatever the agent holds
+		args = append(args, "-i", shellQuote(sshConfig.KeyFile), "-o", "IdentitiesOnly=yes")
+	}
+	hostKeyChecking := "yes"
+	if sshConfig.AcceptNewHostKeys {
+		hostKeyChecking = "accept-new"
+	}
+	args = append(args,
+		"-o", "UserKnownHostsFile="+shellQuote(knownHostsFile),
+		"-o", "StrictHostKeyChecking="+hostKeyChecking,
+		"-o", "BatchMode=yes")
+	return strings.Join(args, " "), nil
+}
+
+// knownHostsFile returns the known hosts file SSH uses
+func (s *GitHubServiceImpl) knownHostsFile() string {
+	if s.config.GitHub.Git.SSH.KnownHostsFile != "" {
+		return s.config.GitHub.Git.SSH.KnownHostsFile
+	}
+	return filepath.Join(s.config.TempDir, "known_hosts")
+}
+
+// addKnownHosts appends the host key lines missing from a known hosts file, creating it if needed
+func addKnownHosts(path string, hostKeys []string) error {
+	if len(hostKeys) == 0 {
+		return nil
+	}
+
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read known hosts file: %w", err)
+	}
+	known := make(map[string]bool)
+	for _, line := range strings.Split(string(existing), "\n") {
+		known[strings.TrimSpace(line)] = true
+	}
+
+	var missing strings.Builder
+	if len(existing) > 0 && !strings.HasSuffix(string(existing), "\n") {
+		missing.WriteString("\n")
+	}
+	added := false
+	for _, hostKey := range hostKeys {
+		hostKey = strings.TrimSpace(hostKey)
+		if hostKey == "" || known[hostKey] {
+			continue
+		}
+		known[hostKey] = true
+		missing.WriteString(hostKey + "\n")
+		added = true
+	}
+	if !added {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create known hosts directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open known hosts file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(missing.String()); err != nil {
+		return fmt.Errorf("failed to write known hosts file: %w", err)
+	}
+	return nil
+}
+
+// shellQuote quotes a value for sh, which git runs core.sshCommand with
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+)
+
+// newSSHTestService creates a GitHub service cloning example/private over SSH and everything else over HTTPS
+func newSSHTestService(t *testing.T) (*GitHubServiceImpl, string) {
+	knownHosts := filepath.Join(t.TempDir(), "ssh", "known_hosts")
+	config := &models.Config{}
+	config.GitHub.PersonalAccessToken = "token"
+	config.GitHub.Git.Protocol = models.GitProtocolHTTPS
+	config.GitHub.Git.Repositories = map[string]models.GitProtocol{"example/private": models.GitProtocolSSH}
+	config.GitHub.Git.SSH.KeyFile = "/keys/bot key"
+	config.GitHub.Git.SSH.KnownHosts = []string{"github.com ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl"}
+	config.GitHub.Git.SSH.KnownHostsFile = knownHosts
+	return newTestGitHubService(config).(*GitHubServiceImpl), knownHosts
+}
+
+func TestTransportCloneArgs(t *testing.T) {
+	service, knownHosts := newSSHTestService(t)
+
+	args, err := service.transportCloneArgs("https://github.com/example/public.git", "/tmp/public", models.CloneOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(args, []string{"clone", "https://github.com/example/public.git", "/tmp/public"}) {
+		t.Errorf("Expected an HTTPS clone, got %v", args)
+	}
+
+	args, err = service.transportCloneArgs("https://github.com/example/private.git", "/tmp/private", models.CloneOptions{Depth: 1, SingleBranch: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	wantCommand := "core.sshCommand=ssh -i '/keys/bot key' -o IdentitiesOnly=yes -o UserKnownHostsFile='" + knownHosts +
+		"' -o StrictHostKeyChecking=yes -o BatchMode=yes"
+	want := []string{"clone", "--config", wantCommand, "--depth", "1", "--single-branch", "git@github.com:example/private.git", "/tmp/private"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("Expected an SSH clone\n  want: %v\n   got: %v", want, args)
+	}
+
+	data, err := os.ReadFile(knownHosts)
+	if err != nil || !strings.HasPrefix(string(data), "github.com ssh-ed25519 ") {
+		t.Errorf("Expected the configured host key to be trusted, got %q (%v)", data, err)
+	}
+}
+
+func TestRemoteURL(t *testing.T) {
+	service, _ := newSSHTestService(t)
+
+	if url, err := service.remoteURL("example", "private"); err != nil || url != "git@github.com:example/private.git" {
+		t.Errorf("Expected the SSH URL, got %q (%v)", url, err)
+	}
+	if url, err := service.remoteURL("example", "public"); err != nil || url != "https://token@github.com/example/public.git" {
+		t.Errorf("Expected the HTTPS URL with the token, got %q (%v)", url, err)
+	}
+}
+
+func TestSSHCommand_Agent(t *testing.T) {
+	service, _ := newSSHTestService(t)
+	service.config.GitHub.Git.SSH.KeyFile = ""
+	service.config.GitHub.Git.SSH.AcceptNewHostKeys = true
+
+	command, err := service.sshCommand()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if strings.Contains(command, "-i ") || !strings.Contains(command, "StrictHostKeyChecking=accept-new") {
+		t.Errorf("Expected the agent's keys and new host keys to be accepted, got %s", command)
+	}
+}
+
+func TestAddKnownHosts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	if err := os.WriteFile(path, []byte("ghe.example.com ssh-rsa AAAA"), 0644); err != nil {
+		t.Fatalf("Failed to write known hosts: %v", err)
+	}
+
+	hostKeys := []string{"github.com ssh-ed25519 AAAA", "ghe.example.com ssh-rsa AAAA"}
+	for i := 0; i < 2; i++ {
+		if err := addKnownHosts(path, hostKeys); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "ghe.example.com ssh-rsa AAAA\ngithub.com ssh-ed25519 AAAA\n" {
+		t.Errorf("Expected only missing host keys to be added once, got %q", data)
+	}
+}
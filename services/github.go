@@ -2,93 +2,244 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
 	"jira-ai-issue-solver/models"
 
 	"go.uber.org/zap"
 )
 
+// ErrNoChanges is returned when committing a working tree without changes
+var ErrNoChanges = errors.New("no changes to commit")
+
 // GitHubService defines the interface for interacting with GitHub
 type GitHubService interface {
 	// CloneRepository clones a repository to a local directory
-	CloneRepository(repoURL, directory string) error
+	CloneRepository(ctx context.Context, repoURL, directory string, opts models.CloneOptions) error
+
+	// CreateWorktree checks out branch, starting at origin/startPoint, in a worktree of the cached clone of a repository
+	CreateWorktree(ctx context.Context, repoURL, directory, branch, startPoint string, opts models.CloneOptions) error
+
+	// RemoveWorktree removes a worktree created by CreateWorktree
+	RemoveWorktree(ctx context.Context, repoURL, directory string) error
+
+	// CreateBranch creates a new branch in a local repository from the latest base branch
+	CreateBranch(ctx context.Context, directory, branchName, baseBranch string) error
+
+	// CommitChanges commits changes to a local repository, returning ErrNoChanges if there are none
+	CommitChanges(ctx context.Context, directory, message string) error
+
+	// CommitFiles commits only the given paths of the working tree
+	CommitFiles(ctx context.Context, directory, message string, files []string) error
+
+	// SetCommitAuthor sets the author of later commits in a local repository; nil authors them as the bot.
+	// The bot stays the committer.
+	SetCommitAuthor(directory string, author *models.GitIdentity)
 
-	// CreateBranch creates a new branch in a local repository
-	CreateBranch(directory, branchName string) error
+	// CountChangedLines counts the lines added and removed in the working tree
+	CountChangedLines(ctx context.Context, directory string) (int, error)
 
-	// CommitChanges commits changes to a local repository
-	CommitChanges(directory, message string) error
+	// DiffStats returns the lines added and removed per file in the working tree, including untracked files
+	DiffStats(ctx context.Context, directory string) (*models.DiffStats, error)
+
+	// OutgoingPatch returns the patch of the commits on the branch that no remote has yet, i.e. what pushing it adds
+	OutgoingPatch(ctx context.Context, directory, branchName string) (string, error)
+
+	// RelatedCommits returns the most recent commits whose message mentions one of the keywords or that changed files
+	// whose path contains one, newest first, with up to maxDiffLines lines of each diff
+	RelatedCommits(ctx context.Context, directory string, keywords []string, maxCommits, maxDiffLines int) ([]models.GitCommit, error)
+
+	// RevertProtectedPaths reverts the working tree changes to paths matching the CODEOWNERS-style patterns, returning
+	// the reverted paths. Everything else is left staged.
+	RevertProtectedPaths(ctx context.Context, directory string, patterns []string) ([]string, error)
+
+	// HasChanges reports whether the working tree has uncommitted changes, including untracked files
+	HasChanges(ctx context.Context, directory string) (bool, error)
+
+	// ChangedFiles lists the files added or modified in the working tree, including untracked files
+	ChangedFiles(ctx context.Context, directory string) ([]string, error)
+
+	// CreateBranchFromHead creates (or resets) a branch at the current HEAD and switches to it
+	CreateBranchFromHead(ctx context.Context, directory, branchName string) error
 
 	// PushChanges pushes changes to a remote repository
-	PushChanges(directory, branchName string) error
+	PushChanges(ctx context.Context, directory, branchName string) error
 
 	// CreatePullRequest creates a pull request
-	CreatePullRequest(owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error)
+	CreatePullRequest(ctx context.Context, owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error)
+
+	// CreateDraftPullRequest creates a pull request in draft state
+	CreateDraftPullRequest(ctx context.Context, owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error)
+
+	// MarkPullRequestReady promotes a draft pull request to ready for review
+	MarkPullRequestReady(ctx context.Context, owner, repo string, prNumber int) error
 
 	// ForkRepository forks a repository and returns the clone URL of the fork
-	ForkRepository(owner, repo string) (string, error)
+	ForkRepository(ctx context.Context, owner, repo string) (string, error)
 
 	// CheckForkExists checks if a fork already exists for the given repository
-	CheckForkExists(owner, repo string) (exists bool, cloneURL string, err error)
+	CheckForkExists(ctx context.Context, owner, repo string) (exists bool, cloneURL string, err error)
 
 	// ResetFork resets a fork to match the original repository
-	ResetFork(forkCloneURL, directory string) error
+	ResetFork(ctx context.Context, forkCloneURL, directory string) error
+
+	// SyncForkWithUpstream brings a branch of the bot's fork up to date with its upstream repository, creating it if
+	// the fork doesn't have it; an empty branch syncs the upstream's default branch
+	SyncForkWithUpstream(ctx context.Context, owner, repo, branch string) error
 
-	// SyncForkWithUpstream syncs a fork with its upstream repository
-	SyncForkWithUpstream(owner, repo string) error
+	// GetDefaultBranch returns the default branch of a repository
+	GetDefaultBranch(ctx context.Context, owner, repo string) (string, error)
 
-	// SwitchToTargetBranch switches to the configured target branch after cloning
-	SwitchToTargetBranch(directory string) error
+	// SwitchToTargetBranch switches to the latest target branch after cloning
+	SwitchToTargetBranch(ctx context.Context, directory, targetBranch string) error
 
 	// SwitchToBranch switches to a specific branch
-	SwitchToBranch(directory, branchName string) error
+	SwitchToBranch(ctx context.Context, directory, branchName string) error
 
 	// PullChanges pulls the latest changes from the remote branch
-	PullChanges(directory, branchName string) error
+	PullChanges(ctx context.Context, directory, branchName string) error
+
+	// ApplyPatch applies a unified diff to the working tree
+	ApplyPatch(ctx context.Context, directory, patch string) error
+
+	AddPRComment(ctx context.Context, owner, repo string, prNumber int, body string) error
+	ListPRComments(ctx context.Context, owner, repo string, prNumber int) ([]models.GitHubPRComment, error)
+
+	// ListPRFiles lists the files changed in a PR
+	ListPRFiles(ctx context.Context, owner, repo string, prNumber int) ([]models.GitHubPRFile, error)
+
+	// ListPRReviewComments lists the inline review comments on a PR's diff
+	ListPRReviewComments(ctx context.Context, owner, repo string, prNumber int) ([]models.GitHubPRComment, error)
+
+	// ReplyToReviewComment replies in the thread of an inline review comment
+	ReplyToReviewComment(ctx context.Context, owner, repo string, prNumber int, commentID int64, body string) error
+
+	// RebaseOnto rebases the current branch onto a branch of the base repository and returns the conflicted
+	// files when the rebase stops on a conflict
+	RebaseOnto(ctx context.Context, directory, owner, repo, branch string) ([]string, error)
+
+	// ContinueRebase stages the resolved files, continues the rebase and returns the next conflicted files
+	ContinueRebase(ctx context.Context, directory string) ([]string, error)
+
+	// AbortRebase aborts the rebase in progress
+	AbortRebase(ctx context.Context, directory string) error
+
+	// ForcePushChanges pushes a rewritten branch with --force-with-lease
+	ForcePushChanges(ctx context.Context, directory, branchName string) error
+
+	// ListCheckRuns lists the check runs reported on a commit
+	ListCheckRuns(ctx context.Context, owner, repo, ref string) ([]models.GitHubCheckRun, error)
+
+	// GetCombinedStatus gets the combined commit status of a commit
+	GetCombinedStatus(ctx context.Context, owner, repo, ref string) (*models.GitHubCombinedStatus, error)
 
-	AddPRComment(owner, repo string, prNumber int, body string) error
-	ListPRComments(owner, repo string, prNumber int) ([]models.GitHubPRComment, error)
+	// GetJobLogs downloads the logs of a GitHub Actions job
+	GetJobLogs(ctx context.Context, owner, repo string, jobID int64) (string, error)
+
+	// MergePullRequest merges a pull request with the given method, provided its head still points at sha
+	MergePullRequest(ctx context.Context, owner, repo string, prNumber int, sha string, method models.MergeMethod) (*models.GitHubMergePRResponse, error)
 
 	// GetPRDetails gets detailed PR information including reviews, comments, and files
-	GetPRDetails(owner, repo string, prNumber int) (*models.GitHubPRDetails, error)
+	GetPRDetails(ctx context.Context, owner, repo string, prNumber int) (*models.GitHubPRDetails, error)
 
 	// ListPRReviews lists all reviews on a PR
-	ListPRReviews(owner, repo string, prNumber int) ([]models.GitHubReview, error)
+	ListPRReviews(ctx context.Context, owner, repo string, prNumber int) ([]models.GitHubReview, error)
+
+	// RequestReviewers requests reviews on a PR from users and teams
+	RequestReviewers(ctx context.Context, owner, repo string, prNumber int, reviewers, teamReviewers []string) error
+
+	// AddLabels adds labels to a PR, creating labels the repository doesn't have yet
+	AddLabels(ctx context.Context, owner, repo string, prNumber int, labels []string) error
+
+	// SetMilestone sets the milestone of a PR to the repository's open milestone with the given title
+	SetMilestone(ctx context.Context, owner, repo string, prNumber int, title string) error
+
+	// AddToProject adds a PR, by its node ID, to a Projects board in the given option of its single-select field
+	AddToProject(ctx context.Context, projectOwner string, projectNumber int, contentID, field, column string) error
+
+	// ListBranches lists the branches of a repository
+	ListBranches(ctx context.Context, owner, repo string) ([]models.GitHubBranch, error)
+
+	// ListPullRequestsByHead lists the open and closed PRs of a repository from the head, given as owner:branch
+	ListPullRequestsByHead(ctx context.Context, owner, repo, head string) ([]models.GitHubPRDetails, error)
+
+	// DeleteBranch deletes a branch of a repository
+	DeleteBranch(ctx context.Context, owner, repo, branch string) error
+
+	// CountOpenReviewRequests counts the open pull requests awaiting a review from the given user
+	CountOpenReviewRequests(ctx context.Context, username string) (int, error)
+
+	// GetTokenScopes returns the OAuth scopes of the token the application authenticates with; none for tokens
+	// without scopes, such as fine-grained tokens and GitHub App installation tokens
+	GetTokenScopes(ctx context.Context) ([]string, error)
 }
 
 // GitHubServiceImpl implements the GitHubService interface
 type GitHubServiceImpl struct {
-	config   *models.Config
-	client   *http.Client
-	executor models.CommandExecutor
-	logger   *zap.Logger
+	config     *models.Config
+	client     *http.Client
+	executor   models.CommandExecutor
+	logger     *zap.Logger
+	appService GitHubAppService
+	repoLocks  sync.Map // cached clone directory -> *sync.Mutex
+	authors    sync.Map // checkout directory -> models.GitIdentity
+
+	defaultBranches sync.Map // repository URL -> default branch of its origin
+
+	gpgImportOnce sync.Once
+	gpgImportErr  error
+
+	knownHostsOnce sync.Once
+	knownHostsErr  error
 }
 
-// NewGitHubService creates a new GitHubService
-func NewGitHubService(config *models.Config, logger *zap.Logger, executor ...models.CommandExecutor) GitHubService {
-	commandExecutor := exec.Command
+// NewGitHubService creates a new GitHubService. API requests are retried on rate limits and transient failures.
+// A GitHub App whose credentials can't be loaded is an error, so a misconfigured deployment fails at startup.
+func NewGitHubService(config *models.Config, metrics Metrics, logger *zap.Logger, executor ...models.CommandExecutor) (GitHubService, error) {
+	commandExecutor := exec.CommandContext
 	if len(executor) > 0 {
 		commandExecutor = executor[0]
 	}
 
-	return &GitHubServiceImpl{
+	service := &GitHubServiceImpl{
 		config:   config,
-		client:   &http.Client{},
+		client:   &http.Client{Transport: newGitHubRetryTransport(newTimeoutTransport(http.DefaultTransport, config.HTTPTimeout()), config, metrics, logger)},
 		executor: commandExecutor,
 		logger:   logger,
 	}
+
+	// Authenticate with short-lived installation tokens when running as a GitHub App
+	if config.GitHub.AuthMode == models.GitHubAuthModeApp {
+		appService, err := NewGitHubAppService(config, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize GitHub App authentication: %w", err)
+		}
+		service.appService = appService
+	}
+
+	return service, nil
 }
 
-// CloneRepository clones a repository to a local directory
-func (s *GitHubServiceImpl) CloneRepository(repoURL, directory string) error {
+// CloneRepository clones a repository to a local directory.
+// opts allows shallow, partial and single-branch clones of large repositories; the zero value clones everything.
+func (s *GitHubServiceImpl) CloneRepository(ctx context.Context, repoURL, directory string, opts models.CloneOptions) error {
+	ctx, cancel := s.gitContext(ctx)
+	defer cancel()
+
 	// Ensure the directory exists
 	if err := os.MkdirAll(directory, 0755); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
@@ -97,7 +248,12 @@ func (s *GitHubServiceImpl) CloneRepository(repoURL, directory string) error {
 	// Check if the directory is already a git repository
 	if _, err := os.Stat(filepath.Join(directory, ".git")); err == nil {
 		// Directory is already a git repository, fetch the latest changes
-		cmd := s.executor("git", "fetch", "origin")
+		if err := s.refreshRemoteAuth(ctx, directory); err != nil {
+			return err
+		}
+
+		// Prune, so a renamed default branch doesn't linger as a stale remote branch
+		cmd := s.executor(ctx, "git", "fetch", "--prune", "origin")
 		cmd.Dir = directory
 
 		var stderr bytes.Buffer
@@ -107,28 +263,13 @@ func (s *GitHubServiceImpl) CloneRepository(repoURL, directory string) error {
 			return fmt.Errorf("failed to fetch repository: %w, stderr: %s", err, stderr.String())
 		}
 
-		// Reset to origin/main or origin/master to ensure we're up to date
-		cmd = s.executor("git", "reset", "--hard", "origin/main")
-		cmd.Dir = directory
-
-		stderr.Reset()
-		cmd.Stderr = &stderr
-
-		if err := cmd.Run(); err != nil {
-			// Try with master branch
-			cmd = s.executor("git", "reset", "--hard", "origin/master")
-			cmd.Dir = directory
-
-			stderr.Reset()
-			cmd.Stderr = &stderr
-
-			if err := cmd.Run(); err != nil {
-				return fmt.Errorf("failed to reset to origin/main or origin/master: %w, stderr: %s", err, stderr.String())
-			}
+		// Reset to the default branch to ensure we're up to date
+		if err := s.resetToDefaultBranch(ctx, repoURL, directory); err != nil {
+			return err
 		}
 
 		// Clean the repository
-		cmd = s.executor("git", "clean", "-fdx")
+		cmd = s.executor(ctx, "git", "clean", "-fdx")
 		cmd.Dir = directory
 
 		stderr.Reset()
@@ -139,7 +280,11 @@ func (s *GitHubServiceImpl) CloneRepository(repoURL, directory string) error {
 		}
 	} else {
 		// Clone the repository
-		cmd := s.executor("git", "clone", repoURL, directory)
+		args, err := s.transportCloneArgs(repoURL, directory, opts)
+		if err != nil {
+			return err
+		}
+		cmd := s.executor(ctx, "git", args...)
 
 		var stderr bytes.Buffer
 		cmd.Stderr = &stderr
@@ -149,15 +294,97 @@ func (s *GitHubServiceImpl) CloneRepository(repoURL, directory string) error {
 		}
 	}
 
+	return s.configureRepository(ctx, repoURL, directory)
+}
+
+// resetToDefaultBranch hard-resets the checkout to the default branch of its origin. When the reset fails because the
+// cached default branch no longer exists, e.g. after upstream renamed master to main, the default branch is detected
+// again.
+func (s *GitHubServiceImpl) resetToDefaultBranch(ctx context.Context, repoURL, directory string) error {
+	branch, err := s.defaultBranch(ctx, repoURL, directory, false)
+	if err != nil {
+		return err
+	}
+
+	cmd := s.executor(ctx, "git", "reset", "--hard", "origin/"+branch)
+	cmd.Dir = directory
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err == nil {
+		return nil
+	}
+
+	detected, detectErr := s.defaultBranch(ctx, repoURL, directory, true)
+	if detectErr != nil || detected == branch {
+		return fmt.Errorf("failed to reset to origin/%s: %w, stderr: %s", branch, err, stderr.String())
+	}
+	s.logger.Info("Default branch changed",
+		zap.String("repository", repoURL),
+		zap.String("from", branch),
+		zap.String("to", detected))
+
+	cmd = s.executor(ctx, "git", "reset", "--hard", "origin/"+detected)
+	cmd.Dir = directory
+
+	stderr.Reset()
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to reset to origin/%s: %w, stderr: %s", detected, err, stderr.String())
+	}
+	return nil
+}
+
+// defaultBranch returns the default branch of the checkout's origin, which its HEAD symbolic ref points to. It's
+// cached per repository, so the remote is only asked again on refresh.
+func (s *GitHubServiceImpl) defaultBranch(ctx context.Context, repoURL, directory string, refresh bool) (string, error) {
+	if branch, ok := s.defaultBranches.Load(repoURL); ok && !refresh {
+		return branch.(string), nil
+	}
+
+	// Clones record the remote HEAD only when they're made, so ask the remote where it points now
+	cmd := s.executor(ctx, "git", "remote", "set-head", "origin", "--auto")
+	cmd.Dir = directory
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to detect default branch: %w, stderr: %s", err, stderr.String())
+	}
+
+	cmd = s.executor(ctx, "git", "symbolic-ref", "--short", "refs/remotes/origin/HEAD")
+	cmd.Dir = directory
+
+	stderr.Reset()
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read default branch: %w, stderr: %s", err, stderr.String())
+	}
+	branch := strings.TrimPrefix(strings.TrimSpace(string(output)), "origin/")
+	if branch == "" {
+		return "", fmt.Errorf("origin of %s has no default branch", directory)
+	}
+
+	s.defaultBranches.Store(repoURL, branch)
+	return branch, nil
+}
+
+// configureRepository sets the bot identity and an authenticated origin URL on a fresh or reused clone
+func (s *GitHubServiceImpl) configureRepository(ctx context.Context, repoURL, directory string) error {
 	// Configure git user for GitHub App
-	cmd := s.executor("git", "config", "user.name", s.config.GitHub.BotUsername)
+	cmd := s.executor(ctx, "git", "config", "user.name", s.config.GitHub.BotUsername)
 	cmd.Dir = directory
 
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to configure git user name: %w", err)
 	}
 
-	cmd = s.executor("git", "config", "user.email", s.config.GitHub.BotEmail)
+	cmd = s.executor(ctx, "git", "config", "user.email", s.config.GitHub.BotEmail)
 	cmd.Dir = directory
 
 	if err := cmd.Run(); err != nil {
@@ -166,50 +393,164 @@ func (s *GitHubServiceImpl) CloneRepository(repoURL, directory string) error {
 
 	// Configure git to use the GitHub token for authentication
 	// This prevents credential prompts during push operations
-	cmd = s.executor("git", "config", "credential.helper", "store")
+	cmd = s.executor(ctx, "git", "config", "credential.helper", "store")
 	cmd.Dir = directory
 
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to configure git credential helper: %w", err)
 	}
 
-	// Set up the credential URL with token
-	token, err := s.getAuthToken()
-	if err != nil {
-		return fmt.Errorf("failed to get auth token: %w", err)
+	// Sign commits so they pass branch protection rules requiring signed commits
+	if err := s.configureSigning(ctx, directory); err != nil {
+		return err
 	}
 
-	// Configure the remote URL to include the token
 	// Extract owner and repo from the URL
 	owner, repo, err := ExtractRepoInfo(repoURL)
 	if err != nil {
 		return fmt.Errorf("failed to extract repo info: %w", err)
 	}
 
-	// Set the remote URL with embedded token
-	authURL := fmt.Sprintf("https://%s@github.com/%s/%s.git", token, owner, repo)
-	cmd = s.executor("git", "remote", "set-url", "origin", authURL)
+	// SSH repositories authenticate with the SSH key, the others with a token embedded in the remote URL
+	if s.config.GetGitProtocol(owner, repo) == models.GitProtocolSSH {
+		if err := s.configureSSH(ctx, directory); err != nil {
+			return err
+		}
+	}
+	authURL, err := s.remoteURL(owner, repo)
+	if err != nil {
+		return err
+	}
+
+	cmd = s.executor(ctx, "git", "remote", "set-url", "origin", authURL)
 	cmd.Dir = directory
 
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to set remote URL with token: %w", err)
+		return fmt.Errorf("failed to set remote URL: %w", err)
 	}
 
 	return nil
 }
 
-// getAuthToken returns the GitHub Personal Access Token for API calls
+// cloneArgs builds the git clone arguments for the given options
+func cloneArgs(repoURL, directory string, opts models.CloneOptions) []string {
+	args := []string{"clone"}
+	if opts.Depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(opts.Depth))
+		if !opts.SingleBranch {
+			// --depth implies --single-branch, keep other branches reachable unless asked otherwise
+			args = append(args, "--no-single-branch")
+		}
+	}
+	if opts.SingleBranch {
+		args = append(args, "--single-branch")
+	}
+	if opts.Filter != "" {
+		args = append(args, "--filter="+opts.Filter)
+	}
+	if opts.Branch != "" {
+		args = append(args, "--branch", opts.Branch)
+	}
+	if opts.Reference != "" {
+		// Dissociate, so the clone keeps working when the reference is pruned or deleted
+		args = append(args, "--reference-if-able", opts.Reference, "--dissociate")
+	}
+	return append(args, repoURL, directory)
+}
+
+// shallowCloneInstructions tells the AI how to get more history when the repository was cloned partially
+func shallowCloneInstructions(opts models.CloneOptions) string {
+	if !opts.IsShallow() && opts.Filter == "" {
+		return ""
+	}
+	instructions := "\n\nNote: this repository is a partial clone to save time and disk space."
+	if opts.IsShallow() {
+		instructions += fmt.Sprintf(" Only the last %d commit(s) of history are available; "+
+			"if you need older history (e.g. for git log or git blame), run `git fetch --deepen=<n>` or `git fetch --unshallow`.", opts.Depth)
+	}
+	if opts.Filter != "" {
+		instructions += " File contents are downloaded on demand, so avoid commands that read every file in the history."
+	}
+	return instructions
+}
+
+// getAuthToken returns the token used for API calls and git operations:
+// a GitHub App installation token in app mode, the Personal Access Token otherwise
 func (s *GitHubServiceImpl) getAuthToken() (string, error) {
+	if s.config.GitHub.AuthMode == models.GitHubAuthModeApp {
+		if s.appService == nil {
+			return "", fmt.Errorf("GitHub App authentication not configured")
+		}
+		return s.appService.GetInstallationToken()
+	}
 	if s.config.GitHub.PersonalAccessToken == "" {
 		return "", fmt.Errorf("Personal Access Token not configured")
 	}
 	return s.config.GitHub.PersonalAccessToken, nil
 }
 
-// CreateBranch creates a new branch in a local repository based on the latest target branch
-func (s *GitHubServiceImpl) CreateBranch(directory, branchName string) error {
+// authenticatedRemoteURL builds an HTTPS remote URL with the token embedded
+func (s *GitHubServiceImpl) authenticatedRemoteURL(token, owner, repo string) string {
+	if s.config.GitHub.AuthMode == models.GitHubAuthModeApp {
+		// Installation tokens must be passed with the x-access-token user
+		return fmt.Sprintf("https://x-access-token:%s@github.com/%s/%s.git", token, owner, repo)
+	}
+	return fmt.Sprintf("https://%s@github.com/%s/%s.git", token, owner, repo)
+}
+
+// remoteRepoPattern matches owner and repo in a GitHub remote URL, with or without embedded credentials
+var remoteRepoPattern = regexp.MustCompile(`github\.com[:/]([^/]+)/([^/]+?)(?:\.git)?/?$`)
+
+// refreshRemoteAuth re-embeds a fresh token in the origin remote URL.
+// Installation tokens expire after an hour, so this runs before every network
+// git operation in app mode. In PAT mode the token never changes and this is a no-op,
+// and SSH repositories keep their SSH URL.
+func (s *GitHubServiceImpl) refreshRemoteAuth(ctx context.Context, directory string) error {
+	if s.config.GitHub.AuthMode != models.GitHubAuthModeApp {
+		return nil
+	}
+
+	cmd := s.executor(ctx, "git", "remote", "get-url", "origin")
+	cmd.Dir = directory
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to get origin URL: %w", err)
+	}
+
+	matches := remoteRepoPattern.FindStringSubmatch(strings.TrimSpace(stdout.String()))
+	if len(matches) != 3 {
+		return fmt.Errorf("failed to parse origin URL for %s", directory)
+	}
+
+	authURL, err := s.remoteURL(matches[1], matches[2])
+	if err != nil {
+		return err
+	}
+
+	cmd = s.executor(ctx, "git", "remote", "set-url", "origin", authURL)
+	cmd.Dir = directory
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to refresh remote URL with token: %w", err)
+	}
+
+	return nil
+}
+
+// CreateBranch creates a new branch in a local repository based on the latest base branch
+func (s *GitHubServiceImpl) CreateBranch(ctx context.Context, directory, branchName, baseBranch string) error {
+	ctx, cancel := s.gitContext(ctx)
+	defer cancel()
+
+	if err := s.refreshRemoteAuth(ctx, directory); err != nil {
+		return err
+	}
+
 	// Fetch the latest changes from origin
-	cmd := s.executor("git", "fetch", "origin")
+	cmd := s.executor(ctx, "git", "fetch", "origin")
 	cmd.Dir = directory
 
 	var stderr bytes.Buffer
@@ -219,36 +560,36 @@ func (s *GitHubServiceImpl) CreateBranch(directory, branchName string) error {
 		return fmt.Errorf("failed to fetch origin: %w, stderr: %s", err, stderr.String())
 	}
 
-	// Checkout the target branch
-	cmd = s.executor("git", "checkout", s.config.GitHub.TargetBranch)
+	// Checkout the base branch
+	cmd = s.executor(ctx, "git", "checkout", baseBranch)
 	cmd.Dir = directory
 
 	stderr.Reset()
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to checkout target branch %s: %w, stderr: %s", s.config.GitHub.TargetBranch, err, stderr.String())
+		return fmt.Errorf("failed to checkout base branch %s: %w, stderr: %s", baseBranch, err, stderr.String())
 	}
 
-	// Reset to the latest commit on the target branch to ensure we're up to date
-	cmd = s.executor("git", "reset", "--hard", "origin/"+s.config.GitHub.TargetBranch)
+	// Reset to the latest commit on the base branch to ensure we're up to date
+	cmd = s.executor(ctx, "git", "reset", "--hard", "origin/"+baseBranch)
 	cmd.Dir = directory
 
 	stderr.Reset()
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to reset to latest commit on target branch %s: %w, stderr: %s", s.config.GitHub.TargetBranch, err, stderr.String())
+		return fmt.Errorf("failed to reset to latest commit on base branch %s: %w, stderr: %s", baseBranch, err, stderr.String())
 	}
 
 	// Check if the branch already exists locally
-	cmd = s.executor("git", "show-ref", "--verify", "--quiet", "refs/heads/"+branchName)
+	cmd = s.executor(ctx, "git", "show-ref", "--verify", "--quiet", "refs/heads/"+branchName)
 	cmd.Dir = directory
 
 	if err := cmd.Run(); err == nil {
 		// Branch exists locally, delete it first
 		s.logger.Info("Branch %s already exists locally, deleting it", zap.String("branchName", branchName))
-		cmd = s.executor("git", "branch", "-D", branchName)
+		cmd = s.executor(ctx, "git", "branch", "-D", branchName)
 		cmd.Dir = directory
 
 		stderr.Reset()
@@ -260,7 +601,7 @@ func (s *GitHubServiceImpl) CreateBranch(directory, branchName string) error {
 	}
 
 	// Create a new branch from the current state
-	cmd = s.executor("git", "checkout", "-b", branchName)
+	cmd = s.executor(ctx, "git", "checkout", "-b", branchName)
 	cmd.Dir = directory
 
 	stderr.Reset()
@@ -273,10 +614,13 @@ func (s *GitHubServiceImpl) CreateBranch(directory, branchName string) error {
 	return nil
 }
 
-// CommitChanges commits changes to a local repository
-func (s *GitHubServiceImpl) CommitChanges(directory, message string) error {
+// CommitChanges commits changes to a local repository, returning ErrNoChanges if there are none
+func (s *GitHubServiceImpl) CommitChanges(ctx context.Context, directory, message string) error {
+	ctx, cancel := s.gitContext(ctx)
+	defer cancel()
+
 	// Add all changes
-	cmd := s.executor("git", "add", ".")
+	cmd := s.executor(ctx, "git", "add", ".")
 	cmd.Dir = directory
 
 	var stderr bytes.Buffer
@@ -287,7 +631,7 @@ func (s *GitHubServiceImpl) CommitChanges(directory, message string) error {
 	}
 
 	// Check if there are changes to commit
-	cmd = s.executor("git", "status", "--porcelain")
+	cmd = s.executor(ctx, "git", "status", "--porcelain")
 	cmd.Dir = directory
 
 	var stdout bytes.Buffer
@@ -298,12 +642,11 @@ func (s *GitHubServiceImpl) CommitChanges(directory, message string) error {
 	}
 
 	if stdout.Len() == 0 {
-		// No changes to commit
-		return nil
+		return ErrNoChanges
 	}
 
 	// Commit changes
-	cmd = s.executor("git", "commit", "-m", message)
+	cmd = s.executor(ctx, "git", s.commitArgs(directory, message)...)
 	cmd.Dir = directory
 
 	stderr.Reset()
@@ -316,18 +659,393 @@ func (s *GitHubServiceImpl) CommitChanges(directory, message string) error {
 	return nil
 }
 
+// CommitFiles commits only the given paths of the working tree, leaving other changes uncommitted
+func (s *GitHubServiceImpl) CommitFiles(ctx context.Context, directory, message string, files []string) error {
+	ctx, cancel := s.gitContext(ctx)
+	defer cancel()
+
+	if len(files) == 0 {
+		return fmt.Errorf("no files to commit")
+	}
+
+	// Unstage everything so only the requested paths end up in the commit
+	cmd := s.executor(ctx, "git", "reset", "-q")
+	cmd.Dir = directory
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to reset index: %w, stderr: %s", err, stderr.String())
+	}
+
+	args := append([]string{"add", "-A", "--"}, files...)
+	cmd = s.executor(ctx, "git", args...)
+	cmd.Dir = directory
+
+	stderr.Reset()
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to add files: %w, stderr: %s", err, stderr.String())
+	}
+
+	cmd = s.executor(ctx, "git", s.commitArgs(directory, message)...)
+	cmd.Dir = directory
+
+	stderr.Reset()
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to commit files: %w, stderr: %s", err, stderr.String())
+	}
+
+	return nil
+}
+
+// CountChangedLines counts the lines added and removed in the working tree, including untracked files
+func (s *GitHubServiceImpl) CountChangedLines(ctx context.Context, directory string) (int, error) {
+	stats, err := s.DiffStats(ctx, directory)
+	if err != nil {
+		return 0, err
+	}
+	return stats.ChangedLines(), nil
+}
+
+// DiffStats returns the lines added and removed per file in the working tree, including untracked files
+func (s *GitHubServiceImpl) DiffStats(ctx context.Context, directory string) (*models.DiffStats, error) {
+	ctx, cancel := s.gitContext(ctx)
+	defer cancel()
+
+	// Stage everything so untracked files are part of the diff
+	cmd := s.executor(ctx, "git", "add", "-A")
+	cmd.Dir = directory
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to add changes: %w, stderr: %s", err, stderr.String())
+	}
+
+	cmd = s.executor(ctx, "git", "diff", "--cached", "--numstat")
+	cmd.Dir = directory
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	stderr.Reset()
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to diff changes: %w, stderr: %s", err, stderr.String())
+	}
+
+	stats := &models.DiffStats{}
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) < 3 {
+			continue
+		}
+		// Binary files are reported as "-" and don't count towards the size
+		added, _ := strconv.Atoi(fields[0])
+		removed, _ := strconv.Atoi(fields[1])
+		stats.Files = append(stats.Files, models.FileDiffStat{Path: fields[2], Added: added, Removed: removed})
+	}
+
+	return stats, nil
+}
+
+// HasChanges reports whether the working tree has uncommitted changes, including untracked files
+func (s *GitHubServiceImpl) HasChanges(ctx context.Context, directory string) (bool, error) {
+	ctx, cancel := s.gitContext(ctx)
+	defer cancel()
+
+	cmd := s.executor(ctx, "git", "status", "--porcelain")
+	cmd.Dir = directory
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return false, fmt.Errorf("failed to get status: %w, stderr: %s", err, stderr.String())
+	}
+
+	return strings.TrimSpace(stdout.String()) != "", nil
+}
+
+// OutgoingPatch returns the patch of the commits on the branch that no remote has yet, i.e. what pushing it adds
+func (s *GitHubServiceImpl) OutgoingPatch(ctx context.Context, directory, branchName string) (string, error) {
+	ctx, cancel := s.gitContext(ctx)
+	defer cancel()
+
+	cmd := s.executor(ctx, "git", "log", "--patch", "--format=commit %H", "--no-color", "--no-ext-diff", branchName, "--not", "--remotes")
+	cmd.Dir = directory
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to get outgoing commits: %w, stderr: %s", err, stderr.String())
+	}
+	return stdout.String(), nil
+}
+
+// maxRelatedFiles is the number of files whose history RelatedCommits looks into
+const maxRelatedFiles = 20
+
+// RelatedCommits returns the most recent commits whose message mentions one of the keywords or that changed files
+// whose path contains one, newest first, with up to maxDiffLines lines of each diff
+func (s *GitHubServiceImpl) RelatedCommits(ctx context.Context, directory string, keywords []string, maxCommits, maxDiffLines int) ([]models.GitCommit, error) {
+	if len(keywords) == 0 || maxCommits <= 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := s.gitContext(ctx)
+	defer cancel()
+
+	run := func(args ...string) (string, error) {
+		cmd := s.executor(ctx, "git", args...)
+		cmd.Dir = directory
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return "", fmt.Errorf("git %s failed: %w, stderr: %s", args[0], err, stderr.String())
+		}
+		return stdout.String(), nil
+	}
+
+	// Files whose path mentions a keyword
+	output, err := run("ls-files", "-z")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, file := range strings.Split(output, "\x00") {
+		lower := strings.ToLower(file)
+		for _, keyword := range keywords {
+			if file != "" && strings.Contains(lower, strings.ToLower(keyword)) {
+				files = append(files, file)
+				break
+			}
+		}
+		if len(files) == maxRelatedFiles {
+			break
+		}
+	}
+
+	logArgs := []string{"log", "-n", strconv.Itoa(maxCommits), "--no-merges", "--date=short", "--format=%H%x1f%an%x1f%ad%x1f%s"}
+	parseLog := func(output string, byFiles bool, commits map[string]*models.GitCommit, fileCommits map[string]bool) {
+		for _, line := range strings.Split(output, "\n") {
+			fields := strings.Split(line, "\x1f")
+			if len(fields) != 4 {
+				continue
+			}
+			if _, ok := commits[fields[0]]; !ok {
+				commits[fields[0]] = &models.GitCommit{SHA: fields[0], Author: fields[1], Date: fields[2], Subject: fields[3]}
+			}
+			if byFiles {
+				fileCommits[fields[0]] = true
+			}
+		}
+	}
+
+	commits := make(map[string]*models.GitCommit)
+	fileCommits := make(map[string]bool)
+	grepArgs := append([]string{}, logArgs...)
+	grepArgs = append(grepArgs, "--regexp-ignore-case", "--fixed-strings")
+	for _, keyword := range keywords {
+		grepArgs = append(grepArgs, "--grep="+keyword)
+	}
+	if output, err = run(grepArgs...); err != nil {
+		return nil, err
+	}
+	parseLog(output, false, commits, fileCommits)
+	if len(files) > 0 {
+		if output, err = run(append(append(logArgs, "--"), files...)...); err != nil {
+			return nil, err
+		}
+		parseLog(output, true, commits, fileCommits)
+	}
+
+	related := make([]models.GitCommit, 0, len(commits))
+	for _, commit := range commits {
+		related = append(related, *commit)
+	}
+	// Commit dates only have a day's precision, so commits of a day are ordered by SHA to stay stable
+	sort.Slice(related, func(i, j int) bool {
+		if related[i].Date != related[j].Date {
+			return related[i].Date > related[j].Date
+		}
+		return related[i].SHA < related[j].SHA
+	})
+	if len(related) > maxCommits {
+		related = related[:maxCommits]
+	}
+
+	for i := range related {
+		showArgs := []string{"show", "--format=", "--no-color", "--unified=2", related[i].SHA}
+		if fileCommits[related[i].SHA] {
+			showArgs = append(append(showArgs, "--"), files...)
+		}
+		diff, err := run(showArgs...)
+		if err != nil {
+			return nil, err
+		}
+		lines := strings.Split(strings.TrimRight(diff, "\n"), "\n")
+		if len(lines) > maxDiffLines {
+			lines = append(lines[:maxDiffLines], fmt.Sprintf("... (%d more lines)", len(lines)-maxDiffLines))
+		}
+		related[i].Diff = strings.Join(lines, "\n")
+	}
+	return related, nil
+}
+
+// RevertProtectedPaths reverts the working tree changes to paths matching the CODEOWNERS-style patterns, returning
+// the reverted paths. New files are removed, and changed or deleted files restored. Everything else is left staged.
+func (s *GitHubServiceImpl) RevertProtectedPaths(ctx context.Context, directory string, patterns []string) ([]string, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	regexes := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		regexes = append(regexes, pathPatternToRegex(pattern))
+	}
+	protected := func(path string) bool {
+		for _, regex := range regexes {
+			if regex.MatchString(path) {
+				return true
+			}
+		}
+		return false
+	}
+
+	ctx, cancel := s.gitContext(ctx)
+	defer cancel()
+
+	run := func(args ...string) (string, error) {
+		cmd := s.executor(ctx, "git", args...)
+		cmd.Dir = directory
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return "", fmt.Errorf("git %s failed: %w, stderr: %s", args[0], err, stderr.String())
+		}
+		return stdout.String(), nil
+	}
+
+	// Stage everything, so new and deleted files show up in the diff; renames are listed as a deletion and an addition
+	if _, err := run("add", "-A"); err != nil {
+		return nil, err
+	}
+	output, err := run("diff", "--cached", "--name-status", "--no-renames", "-z", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	var added, restored []string
+	fields := strings.Split(output, "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		status, path := fields[i], fields[i+1]
+		if !protected(path) {
+			continue
+		}
+		if status == "A" {
+			added = append(added, path)
+		} else {
+			restored = append(restored, path)
+		}
+	}
+	if len(added) > 0 {
+		if _, err := run(append([]string{"rm", "--force", "--quiet", "--"}, added...)...); err != nil {
+			return nil, err
+		}
+	}
+	if len(restored) > 0 {
+		if _, err := run(append([]string{"checkout", "HEAD", "--"}, restored...)...); err != nil {
+			return nil, err
+		}
+	}
+	return append(restored, added...), nil
+}
+
+// ChangedFiles lists the files added or modified in the working tree, including untracked files. Deleted files aren't
+// listed, since commands run on the list can't open them.
+func (s *GitHubServiceImpl) ChangedFiles(ctx context.Context, directory string) ([]string, error) {
+	ctx, cancel := s.gitContext(ctx)
+	defer cancel()
+
+	cmd := s.executor(ctx, "git", "status", "--porcelain", "-z", "--untracked-files=all")
+	cmd.Dir = directory
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to get status: %w, stderr: %s", err, stderr.String())
+	}
+
+	var files []string
+	entries := strings.Split(stdout.String(), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		status, path := entry[:2], entry[3:]
+		if status[0] == 'R' || status[0] == 'C' {
+			// The source of a rename or copy follows its destination
+			i++
+		}
+		if strings.Contains(status, "D") {
+			continue
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+// CreateBranchFromHead creates (or resets) a branch at the current HEAD and switches to it
+func (s *GitHubServiceImpl) CreateBranchFromHead(ctx context.Context, directory, branchName string) error {
+	ctx, cancel := s.gitContext(ctx)
+	defer cancel()
+
+	cmd := s.executor(ctx, "git", "checkout", "-B", branchName)
+	cmd.Dir = directory
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to create branch %s: %w, stderr: %s", branchName, err, stderr.String())
+	}
+
+	return nil
+}
+
 // PushChanges pushes changes to a remote repository
-func (s *GitHubServiceImpl) PushChanges(directory, branchName string) error {
+func (s *GitHubServiceImpl) PushChanges(ctx context.Context, directory, branchName string) error {
+	ctx, cancel := s.gitContext(ctx)
+	defer cancel()
+
 	// Ensure git is configured to not prompt for credentials
-	cmd := s.executor("git", "config", "credential.helper", "store")
+	cmd := s.executor(ctx, "git", "config", "credential.helper", "store")
 	cmd.Dir = directory
 
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to configure git credential helper: %w", err)
 	}
 
+	if err := s.refreshRemoteAuth(ctx, directory); err != nil {
+		return err
+	}
+
 	// Push the changes
-	cmd = s.executor("git", "push", "-u", "origin", branchName)
+	cmd = s.executor(ctx, "git", "push", "-u", "origin", branchName)
 	cmd.Dir = directory
 
 	var stderr bytes.Buffer
@@ -341,15 +1059,25 @@ func (s *GitHubServiceImpl) PushChanges(directory, branchName string) error {
 }
 
 // CreatePullRequest creates a pull request
-func (s *GitHubServiceImpl) CreatePullRequest(owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error) {
+func (s *GitHubServiceImpl) CreatePullRequest(ctx context.Context, owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error) {
+	return s.createPullRequest(ctx, owner, repo, title, body, head, base, false)
+}
+
+// CreateDraftPullRequest creates a pull request in draft state, so CI runs without notifying reviewers
+func (s *GitHubServiceImpl) CreateDraftPullRequest(ctx context.Context, owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error) {
+	return s.createPullRequest(ctx, owner, repo, title, body, head, base, true)
+}
+
+// createPullRequest creates a pull request, optionally as a draft
+func (s *GitHubServiceImpl) createPullRequest(ctx context.Context, owner, repo, title, body, head, base string, draft bool) (*models.GitHubCreatePRResponse, error) {
 	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls", owner, repo)
 
 	payload := models.GitHubCreatePRRequest{
-		Title:  title,
-		Body:   body,
-		Head:   head,
-		Base:   base,
-		Labels: []string{s.config.GitHub.PRLabel},
+		Title: title,
+		Body:  body,
+		Head:  head,
+		Base:  base,
+		Draft: draft,
 	}
 
 	jsonPayload, err := json.Marshal(payload)
@@ -357,7 +1085,7 @@ func (s *GitHubServiceImpl) CreatePullRequest(owner, repo, title, body, head, ba
 		return nil, fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonPayload))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonPayload))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -391,18 +1119,107 @@ func (s *GitHubServiceImpl) CreatePullRequest(owner, repo, title, body, head, ba
 	return &prResponse, nil
 }
 
+// MarkPullRequestReady promotes a draft pull request to ready for review.
+// The REST API cannot change the draft state, so this uses the GraphQL markPullRequestReadyForReview mutation.
+func (s *GitHubServiceImpl) MarkPullRequestReady(ctx context.Context, owner, repo string, prNumber int) error {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d", owner, repo, prNumber)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	token, err := s.getAuthToken()
+	if err != nil {
+		return fmt.Errorf("failed to get auth token: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to get PR details: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	var pr models.GitHubPRDetails
+	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
+		return fmt.Errorf("failed to decode PR details: %w", err)
+	}
+
+	if !pr.Draft {
+		s.logger.Info("Pull request is already ready for review", zap.String("repo", fmt.Sprintf("%s/%s", owner, repo)), zap.Int("pr_number", prNumber))
+		return nil
+	}
+
+	mutation := map[string]interface{}{
+		"query": `mutation($id: ID!) { markPullRequestReadyForReview(input: {pullRequestId: $id}) { pullRequest { isDraft } } }`,
+		"variables": map[string]string{
+			"id": pr.NodeID,
+		},
+	}
+
+	jsonPayload, err := json.Marshal(mutation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal GraphQL request: %w", err)
+	}
+
+	req, err = http.NewRequestWithContext(ctx, "POST", "https://api.github.com/graphql", bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err = s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to mark pull request ready: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	// GraphQL reports failures in the body with a 200 status
+	var graphQLResponse struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&graphQLResponse); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(graphQLResponse.Errors) > 0 {
+		return fmt.Errorf("failed to mark pull request ready: %s", graphQLResponse.Errors[0].Message)
+	}
+
+	return nil
+}
+
 // CheckForkExists checks if a fork already exists for the given repository
-func (s *GitHubServiceImpl) CheckForkExists(owner, repo string) (exists bool, cloneURL string, err error) {
+func (s *GitHubServiceImpl) CheckForkExists(ctx context.Context, owner, repo string) (exists bool, cloneURL string, err error) {
 	// Get authentication token
 	token, err := s.getAuthToken()
 	if err != nil {
 		return false, "", fmt.Errorf("failed to get auth token: %w", err)
 	}
 
+	if s.config.GitHub.ForkOrganization != "" {
+		return s.checkOrganizationFork(ctx, token, owner, repo)
+	}
+
 	// Check if the fork already exists by listing the bot's repositories
 	url := fmt.Sprintf("https://api.github.com/users/%s/repos", s.config.GitHub.BotUsername)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return false, "", fmt.Errorf("failed to create request: %w", err)
 	}
@@ -454,12 +1271,65 @@ func (s *GitHubServiceImpl) CheckForkExists(owner, repo string) (exists bool, cl
 		}
 	}
 
-	s.logger.Info("No fork found for", zap.String("targetFullName", targetFullName))
-	return false, "", nil
+	s.logger.Info("No fork found for", zap.String("targetFullName", targetFullName))
+	return false, "", nil
+}
+
+// checkOrganizationFork checks whether the fork organization has a fork of the repository. Organizations hold many
+// repositories, so the fork is looked up by name instead of listing them.
+func (s *GitHubServiceImpl) checkOrganizationFork(ctx context.Context, token, owner, repo string) (bool, string, error) {
+	org := s.config.GitHub.ForkOrganization
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s", org, repo)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		s.logger.Info("No fork found in organization", zap.String("organization", org), zap.String("repo", repo))
+		return false, "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return false, "", fmt.Errorf("failed to get repository %s/%s: %s, status code: %d", org, repo, string(body), resp.StatusCode)
+	}
+
+	var details struct {
+		CloneURL string `json:"clone_url"`
+		Fork     bool   `json:"fork"`
+		Source   struct {
+			FullName string `json:"full_name"`
+		} `json:"source"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&details); err != nil {
+		return false, "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	// A repository of the same name that isn't a fork of the target can't be used, and GitHub can't fork into its name
+	targetFullName := fmt.Sprintf("%s/%s", owner, repo)
+	if !details.Fork || !strings.EqualFold(details.Source.FullName, targetFullName) {
+		return false, "", fmt.Errorf("%s/%s exists but is not a fork of %s", org, repo, targetFullName)
+	}
+
+	s.logger.Info("Found fork in organization", zap.String("organization", org), zap.String("cloneURL", details.CloneURL))
+	return true, details.CloneURL, nil
 }
 
 // ResetFork resets a fork to match the original repository and sets up upstream
-func (s *GitHubServiceImpl) ResetFork(forkCloneURL, directory string) error {
+func (s *GitHubServiceImpl) ResetFork(ctx context.Context, forkCloneURL, directory string) error {
+	ctx, cancel := s.gitContext(ctx)
+	defer cancel()
+
 	// Ensure the directory exists
 	if err := os.MkdirAll(directory, 0755); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
@@ -469,7 +1339,11 @@ func (s *GitHubServiceImpl) ResetFork(forkCloneURL, directory string) error {
 	if _, err := os.Stat(filepath.Join(directory, ".git")); err == nil {
 		// Directory is already a git repository, fetch and reset
 		// Fetch the upstream repository
-		cmd := s.executor("git", "fetch", "origin")
+		if err := s.refreshRemoteAuth(ctx, directory); err != nil {
+			return err
+		}
+
+		cmd := s.executor(ctx, "git", "fetch", "--prune", "origin")
 		cmd.Dir = directory
 
 		var stderr bytes.Buffer
@@ -479,28 +1353,17 @@ func (s *GitHubServiceImpl) ResetFork(forkCloneURL, directory string) error {
 			return fmt.Errorf("failed to fetch origin: %w, stderr: %s", err, stderr.String())
 		}
 
-		// Reset to origin/main or origin/master
-		cmd = s.executor("git", "reset", "--hard", "origin/main")
-		cmd.Dir = directory
-
-		stderr.Reset()
-		cmd.Stderr = &stderr
-
-		if err := cmd.Run(); err != nil {
-			// Try with master branch
-			cmd = s.executor("git", "reset", "--hard", "origin/master")
-			cmd.Dir = directory
-
-			stderr.Reset()
-			cmd.Stderr = &stderr
-
-			if err := cmd.Run(); err != nil {
-				return fmt.Errorf("failed to reset to origin/main or origin/master: %w, stderr: %s", err, stderr.String())
+		// Reset to the target branch, or the default branch if none is configured
+		if branch := s.config.GitHub.TargetBranch; branch != "" {
+			if err := s.checkoutRemoteBranch(ctx, directory, branch); err != nil {
+				return err
 			}
+		} else if err := s.resetToDefaultBranch(ctx, forkCloneURL, directory); err != nil {
+			return err
 		}
 
 		// Clean the repository
-		cmd = s.executor("git", "clean", "-fdx")
+		cmd = s.executor(ctx, "git", "clean", "-fdx")
 		cmd.Dir = directory
 
 		stderr.Reset()
@@ -514,21 +1377,32 @@ func (s *GitHubServiceImpl) ResetFork(forkCloneURL, directory string) error {
 	}
 
 	// Clone the repository
-	return s.CloneRepository(forkCloneURL, directory)
+	opts := s.config.GitHub.Clone
+	opts.Branch = s.config.GitHub.TargetBranch
+	return s.CloneRepository(ctx, forkCloneURL, directory, opts)
 }
 
 // ForkRepository forks a repository and returns the clone URL of the fork
-func (s *GitHubServiceImpl) ForkRepository(owner, repo string) (string, error) {
+func (s *GitHubServiceImpl) ForkRepository(ctx context.Context, owner, repo string) (string, error) {
 	// Get authentication token
 	token, err := s.getAuthToken()
 	if err != nil {
 		return "", fmt.Errorf("failed to get auth token: %w", err)
 	}
 
-	// Create a new fork
+	// Create a new fork, in the fork organization if one is configured
 	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/forks", owner, repo)
 
-	req, err := http.NewRequest("POST", url, nil)
+	var body io.Reader
+	if org := s.config.GitHub.ForkOrganization; org != "" {
+		jsonBody, err := json.Marshal(map[string]string{"organization": org})
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal fork request: %w", err)
+		}
+		body = bytes.NewReader(jsonBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, body)
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
@@ -560,125 +1434,54 @@ func (s *GitHubServiceImpl) ForkRepository(owner, repo string) (string, error) {
 	return forkResponse.CloneURL, nil
 }
 
-// SyncForkWithUpstream syncs a fork with its upstream repository
-func (s *GitHubServiceImpl) SyncForkWithUpstream(owner, repo string) error {
-	// Get authentication token
-	token, err := s.getAuthToken()
-	if err != nil {
-		return fmt.Errorf("failed to get auth token: %w", err)
-	}
-
-	// Get the fork details to sync with upstream
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s", s.config.GitHub.BotUsername, repo)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to get fork details: %s, status code: %d", string(body), resp.StatusCode)
-	}
-
-	var forkDetails struct {
-		Source struct {
-			Owner struct {
-				Login string `json:"login"`
-			} `json:"owner"`
-			Name string `json:"name"`
-		} `json:"source"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&forkDetails); err != nil {
-		return fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	// Sync the fork with upstream
-	syncURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/merge-upstream", s.config.GitHub.BotUsername, repo)
-	syncBody := map[string]string{
-		"branch": "main",
-	}
-
-	jsonBody, err := json.Marshal(syncBody)
-	if err != nil {
-		return fmt.Errorf("failed to marshal sync request: %w", err)
-	}
-
-	req, err = http.NewRequest("POST", syncURL, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return fmt.Errorf("failed to create sync request: %w", err)
-	}
-
-	req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err = s.client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send sync request: %w", err)
-	}
-	defer resp.Body.Close()
+// SwitchToTargetBranch switches to the latest target branch after cloning
+func (s *GitHubServiceImpl) SwitchToTargetBranch(ctx context.Context, directory, targetBranch string) error {
+	ctx, cancel := s.gitContext(ctx)
+	defer cancel()
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to sync fork: %s, status code: %d", string(body), resp.StatusCode)
+	if err := s.refreshRemoteAuth(ctx, directory); err != nil {
+		return err
 	}
-
-	return nil
+	return s.checkoutRemoteBranch(ctx, directory, targetBranch)
 }
 
-// SwitchToTargetBranch switches to the configured target branch after cloning
-func (s *GitHubServiceImpl) SwitchToTargetBranch(directory string) error {
-	// Fetch the latest changes from origin
-	cmd := s.executor("git", "fetch", "origin")
+// checkoutRemoteBranch checks a branch out at the latest commit of its origin, discarding local changes. The branch is
+// fetched explicitly, since single-branch clones only fetch the branch they were cloned on.
+func (s *GitHubServiceImpl) checkoutRemoteBranch(ctx context.Context, directory, branch string) error {
+	cmd := s.executor(ctx, "git", "fetch", "origin", fmt.Sprintf("+refs/heads/%s:refs/remotes/origin/%s", branch, branch))
 	cmd.Dir = directory
 
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to fetch origin: %w, stderr: %s", err, stderr.String())
-	}
-
-	// Checkout the target branch
-	cmd = s.executor("git", "checkout", s.config.GitHub.TargetBranch)
-	cmd.Dir = directory
-
-	stderr.Reset()
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to checkout target branch %s: %w, stderr: %s", s.config.GitHub.TargetBranch, err, stderr.String())
+		return fmt.Errorf("failed to fetch target branch %s: %w, stderr: %s", branch, err, stderr.String())
 	}
 
-	// Reset to the latest commit on the target branch to ensure we're up to date
-	cmd = s.executor("git", "reset", "--hard", "origin/"+s.config.GitHub.TargetBranch)
+	// Creates the local branch, or resets it to the latest commit of the target branch
+	cmd = s.executor(ctx, "git", "checkout", "-f", "-B", branch, "origin/"+branch)
 	cmd.Dir = directory
 
 	stderr.Reset()
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to reset to latest commit on target branch %s: %w, stderr: %s", s.config.GitHub.TargetBranch, err, stderr.String())
+		return fmt.Errorf("failed to checkout target branch %s: %w, stderr: %s", branch, err, stderr.String())
 	}
-
 	return nil
 }
 
 // SwitchToBranch switches to a specific branch
-func (s *GitHubServiceImpl) SwitchToBranch(directory, branchName string) error {
+func (s *GitHubServiceImpl) SwitchToBranch(ctx context.Context, directory, branchName string) error {
+	ctx, cancel := s.gitContext(ctx)
+	defer cancel()
+
+	if err := s.refreshRemoteAuth(ctx, directory); err != nil {
+		return err
+	}
+
 	// Fetch the latest changes from origin
-	cmd := s.executor("git", "fetch", "origin")
+	cmd := s.executor(ctx, "git", "fetch", "origin")
 	cmd.Dir = directory
 
 	var stderr bytes.Buffer
@@ -689,7 +1492,7 @@ func (s *GitHubServiceImpl) SwitchToBranch(directory, branchName string) error {
 	}
 
 	// Checkout the specified branch
-	cmd = s.executor("git", "checkout", branchName)
+	cmd = s.executor(ctx, "git", "checkout", branchName)
 	cmd.Dir = directory
 
 	stderr.Reset()
@@ -703,9 +1506,16 @@ func (s *GitHubServiceImpl) SwitchToBranch(directory, branchName string) error {
 }
 
 // PullChanges pulls the latest changes from the remote branch
-func (s *GitHubServiceImpl) PullChanges(directory, branchName string) error {
+func (s *GitHubServiceImpl) PullChanges(ctx context.Context, directory, branchName string) error {
+	ctx, cancel := s.gitContext(ctx)
+	defer cancel()
+
+	if err := s.refreshRemoteAuth(ctx, directory); err != nil {
+		return err
+	}
+
 	// Pull the latest changes from the remote branch
-	cmd := s.executor("git", "pull", "origin", branchName)
+	cmd := s.executor(ctx, "git", "pull", "origin", branchName)
 	cmd.Dir = directory
 
 	var stderr bytes.Buffer
@@ -718,8 +1528,27 @@ func (s *GitHubServiceImpl) PullChanges(directory, branchName string) error {
 	return nil
 }
 
+// ApplyPatch applies a unified diff to the working tree
+func (s *GitHubServiceImpl) ApplyPatch(ctx context.Context, directory, patch string) error {
+	ctx, cancel := s.gitContext(ctx)
+	defer cancel()
+
+	cmd := s.executor(ctx, "git", "apply", "--whitespace=nowarn", "-")
+	cmd.Dir = directory
+	cmd.Stdin = strings.NewReader(patch)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to apply patch: %w, stderr: %s", err, stderr.String())
+	}
+
+	return nil
+}
+
 // AddPRComment posts a comment to a PR (issue) on GitHub
-func (s *GitHubServiceImpl) AddPRComment(owner, repo string, prNumber int, body string) error {
+func (s *GitHubServiceImpl) AddPRComment(ctx context.Context, owner, repo string, prNumber int, body string) error {
 	commentRequest := struct {
 		Body string `json:"body"`
 	}{Body: body}
@@ -730,7 +1559,7 @@ func (s *GitHubServiceImpl) AddPRComment(owner, repo string, prNumber int, body
 	}
 
 	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues/%d/comments", owner, repo, prNumber)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonPayload))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonPayload))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -759,9 +1588,9 @@ func (s *GitHubServiceImpl) AddPRComment(owner, repo string, prNumber int, body
 }
 
 // ListPRComments lists all comments on a PR (issue) on GitHub
-func (s *GitHubServiceImpl) ListPRComments(owner, repo string, prNumber int) ([]models.GitHubPRComment, error) {
+func (s *GitHubServiceImpl) ListPRComments(ctx context.Context, owner, repo string, prNumber int) ([]models.GitHubPRComment, error) {
 	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues/%d/comments", owner, repo, prNumber)
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -793,6 +1622,136 @@ func (s *GitHubServiceImpl) ListPRComments(owner, repo string, prNumber int) ([]
 	return comments, nil
 }
 
+// ListPRFiles lists the files changed in a PR, following pagination
+func (s *GitHubServiceImpl) ListPRFiles(ctx context.Context, owner, repo string, prNumber int) ([]models.GitHubPRFile, error) {
+	token, err := s.getAuthToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auth token: %w", err)
+	}
+
+	var files []models.GitHubPRFile
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/files?per_page=100&page=%d", owner, repo, prNumber, page)
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to send request: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to list PR files: %s, status: %d", string(body), resp.StatusCode)
+		}
+
+		var pageFiles []models.GitHubPRFile
+		err = json.NewDecoder(resp.Body).Decode(&pageFiles)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode PR files: %w", err)
+		}
+
+		files = append(files, pageFiles...)
+		if len(pageFiles) < 100 {
+			break
+		}
+	}
+
+	return files, nil
+}
+
+// ListPRReviewComments lists the inline review comments on a PR's diff, following pagination
+func (s *GitHubServiceImpl) ListPRReviewComments(ctx context.Context, owner, repo string, prNumber int) ([]models.GitHubPRComment, error) {
+	token, err := s.getAuthToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auth token: %w", err)
+	}
+
+	var comments []models.GitHubPRComment
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/comments?per_page=100&page=%d", owner, repo, prNumber, page)
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to send request: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to list PR review comments: %s, status: %d", string(body), resp.StatusCode)
+		}
+
+		var pageComments []models.GitHubPRComment
+		err = json.NewDecoder(resp.Body).Decode(&pageComments)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode PR review comments: %w", err)
+		}
+
+		comments = append(comments, pageComments...)
+		if len(pageComments) < 100 {
+			break
+		}
+	}
+
+	return comments, nil
+}
+
+// ReplyToReviewComment replies in the thread of an inline review comment
+func (s *GitHubServiceImpl) ReplyToReviewComment(ctx context.Context, owner, repo string, prNumber int, commentID int64, body string) error {
+	replyRequest := struct {
+		Body string `json:"body"`
+	}{Body: body}
+
+	jsonPayload, err := json.Marshal(replyRequest)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reply request: %w", err)
+	}
+
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/comments/%d/replies", owner, repo, prNumber, commentID)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	token, err := s.getAuthToken()
+	if err != nil {
+		return fmt.Errorf("failed to get auth token: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to reply to review comment %d: %s, status: %d", commentID, string(body), resp.StatusCode)
+	}
+
+	return nil
+}
+
 // ExtractRepoInfo extracts owner and repo from a repository URL
 func ExtractRepoInfo(repoURL string) (owner, repo string, err error) {
 	// Handle SSH URLs: git@github.com:owner/repo.git
@@ -821,9 +1780,9 @@ func ExtractRepoInfo(repoURL string) (owner, repo string, err error) {
 }
 
 // GetPRDetails gets detailed PR information including reviews, comments, and files
-func (s *GitHubServiceImpl) GetPRDetails(owner, repo string, prNumber int) (*models.GitHubPRDetails, error) {
+func (s *GitHubServiceImpl) GetPRDetails(ctx context.Context, owner, repo string, prNumber int) (*models.GitHubPRDetails, error) {
 	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d", owner, repo, prNumber)
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -853,26 +1812,33 @@ func (s *GitHubServiceImpl) GetPRDetails(owner, repo string, prNumber int) (*mod
 	}
 
 	// Get reviews
-	reviews, err := s.ListPRReviews(owner, repo, prNumber)
+	reviews, err := s.ListPRReviews(ctx, owner, repo, prNumber)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get PR reviews: %w", err)
 	}
 	prDetails.Reviews = reviews
 
 	// Get comments
-	comments, err := s.ListPRComments(owner, repo, prNumber)
+	comments, err := s.ListPRComments(ctx, owner, repo, prNumber)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get PR comments: %w", err)
 	}
 	prDetails.Comments = comments
 
+	// Get inline review comments
+	reviewComments, err := s.ListPRReviewComments(ctx, owner, repo, prNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get PR review comments: %w", err)
+	}
+	prDetails.ReviewComments = reviewComments
+
 	return &prDetails, nil
 }
 
 // ListPRReviews lists all reviews on a PR
-func (s *GitHubServiceImpl) ListPRReviews(owner, repo string, prNumber int) ([]models.GitHubReview, error) {
+func (s *GitHubServiceImpl) ListPRReviews(ctx context.Context, owner, repo string, prNumber int) ([]models.GitHubReview, error) {
 	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/reviews", owner, repo, prNumber)
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -903,3 +1869,123 @@ func (s *GitHubServiceImpl) ListPRReviews(owner, repo string, prNumber int) ([]m
 
 	return reviews, nil
 }
+
+// RequestReviewers requests reviews on a PR from users and teams
+func (s *GitHubServiceImpl) RequestReviewers(ctx context.Context, owner, repo string, prNumber int, reviewers, teamReviewers []string) error {
+	payload := struct {
+		Reviewers     []string `json:"reviewers,omitempty"`
+		TeamReviewers []string `json:"team_reviewers,omitempty"`
+	}{Reviewers: reviewers, TeamReviewers: teamReviewers}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reviewers request: %w", err)
+	}
+
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/requested_reviewers", owner, repo, prNumber)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	token, err := s.getAuthToken()
+	if err != nil {
+		return fmt.Errorf("failed to get auth token: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to request reviewers: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	return nil
+}
+
+// AddLabels adds labels to a PR through the issues API, since the pulls API ignores labels. GitHub creates labels
+// the repository doesn't have yet.
+func (s *GitHubServiceImpl) AddLabels(ctx context.Context, owner, repo string, prNumber int, labels []string) error {
+	payload := struct {
+		Labels []string `json:"labels"`
+	}{Labels: labels}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal labels request: %w", err)
+	}
+
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues/%d/labels", owner, repo, prNumber)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	token, err := s.getAuthToken()
+	if err != nil {
+		return fmt.Errorf("failed to get auth token: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to add labels: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	return nil
+}
+
+// CountOpenReviewRequests counts the open pull requests awaiting a review from the given user
+func (s *GitHubServiceImpl) CountOpenReviewRequests(ctx context.Context, username string) (int, error) {
+	query := url.QueryEscape(fmt.Sprintf("type:pr state:open review-requested:%s", username))
+	searchURL := fmt.Sprintf("https://api.github.com/search/issues?q=%s&per_page=1", query)
+	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	token, err := s.getAuthToken()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get auth token: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return 0, fmt.Errorf("failed to search review requests: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	var searchResponse struct {
+		TotalCount int `json:"total_count"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&searchResponse); err != nil {
+		return 0, fmt.Errorf("failed to decode search response: %w", err)
+	}
+
+	return searchResponse.TotalCount, nil
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// installationTokenRefreshMargin is how long before expiry a cached installation token is refreshed
+const installationTokenRefreshMargin = 5 * time.Minute
+
+// GitHubAppService defines the interface for authenticating as a GitHub App
+type GitHubAppService interface {
+	// GetInstallationToken returns a valid installation access token, refreshing it when needed
+	GetInstallationToken() (string, error)
+
+	// GetAppToken returns a signed JWT that authenticates as the GitHub App itself
+	GetAppToken() (string, error)
+}
+
+// GitHubAppServiceImpl implements the GitHubAppService interface
+type GitHubAppServiceImpl struct {
+	config     *models.Config
+	client     *http.Client
+	logger     *zap.Logger
+	privateKey *rsa.PrivateKey
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+	now         func() time.Time
+}
+
+// NewGitHubAppService creates a new GitHubAppService
+func NewGitHubAppService(config *models.Config, logger *zap.Logger) (GitHubAppService, error) {
+	keyData := []byte(config.GitHub.PrivateKey)
+	if len(keyData) == 0 {
+		var err error
+		keyData, err = os.ReadFile(config.GitHub.PrivateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read GitHub App private key: %w", err)
+		}
+	}
+
+	privateKey, err := parseRSAPrivateKey(keyData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GitHubAppServiceImpl{
+		config:     config,
+		client:     &http.Client{Timeout: config.HTTPTimeout()},
+		logger:     logger,
+		privateKey: privateKey,
+		now:        time.Now,
+	}, nil
+}
+
+// parseRSAPrivateKey parses a PEM encoded RSA private key in PKCS#1 or PKCS#8 form
+func parseRSAPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("failed to decode GitHub App private key: no PEM block found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub App private key: %w", err)
+	}
+
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("GitHub App private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// GetAppToken returns a signed JWT that authenticates as the GitHub App itself
+func (s *GitHubAppServiceImpl) GetAppToken() (string, error) {
+	now := s.now()
+
+	header := map[string]string{
+		"alg": "RS256",
+		"typ": "JWT",
+	}
+	// Backdate issued-at to allow for clock drift, GitHub allows a maximum lifetime of 10 minutes
+	claims := map[string]interface{}{
+		"iat": now.Add(-60 * time.Second).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": fmt.Sprintf("%d", s.config.GitHub.AppID),
+	}
+
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JWT header: %w", err)
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JWT claims: %w", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+
+	hash := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.privateKey, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign JWT: %w", err)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// GetInstallationToken returns a valid installation access token, refreshing it when needed
+func (s *GitHubAppServiceImpl) GetInstallationToken() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && s.now().Add(installationTokenRefreshMargin).Before(s.tokenExpiry) {
+		return s.token, nil
+	}
+
+	appToken, err := s.GetAppToken()
+	if err != nil {
+		return "", err
+	}
+
+	url := fmt.Sprintf("https://api.github.com/app/installations/%d/access_tokens", s.config.GitHub.InstallationID)
+	req, err := http.NewRequest("POST", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", appToken))
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed to create installation token: %s, status code: %d", string(body), resp.StatusCode)
+	}
+
+	var tokenResponse struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	s.token = tokenResponse.Token
+	s.tokenExpiry = tokenResponse.ExpiresAt
+	s.logger.Info("Refreshed GitHub App installation token", zap.Time("expires_at", tokenResponse.ExpiresAt))
+
+	return s.token, nil
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestParseRSAPrivateKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	pkcs8Bytes, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal PKCS8 key: %v", err)
+	}
+	pkcs8 := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Bytes})
+
+	for name, data := range map[string][]byte{"PKCS1": pkcs1, "PKCS8": pkcs8} {
+		t.Run(name, func(t *testing.T) {
+			parsed, err := parseRSAPrivateKey(data)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if !parsed.Equal(key) {
+				t.Error("Parsed key does not match generated key")
+			}
+		})
+	}
+
+	if _, err := parseRSAPrivateKey([]byte("not a key")); err == nil {
+		t.Error("Expected error for invalid PEM data")
+	}
+}
+
+func TestGitHubAppService_GetAppToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	config := &models.Config{}
+	config.GitHub.AppID = 12345
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	service := &GitHubAppServiceImpl{
+		config:     config,
+		logger:     zap.NewNop(),
+		privateKey: key,
+		now:        func() time.Time { return now },
+	}
+
+	token, err := service.GetAppToken()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("Expected JWT with 3 parts, got %d", len(parts))
+	}
+
+	// Verify the signature with the public key
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatalf("Failed to decode signature: %v", err)
+	}
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, hash[:], signature); err != nil {
+		t.Errorf("JWT signature verification failed: %v", err)
+	}
+
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("Failed to decode claims: %v", err)
+	}
+	var claims struct {
+		Iat int64  `json:"iat"`
+		Exp int64  `json:"exp"`
+		Iss string `json:"iss"`
+	}
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil {
+		t.Fatalf("Failed to unmarshal claims: %v", err)
+	}
+	if claims.Iss != "12345" {
+		t.Errorf("Expected issuer '12345', got '%s'", claims.Iss)
+	}
+	if claims.Exp-claims.Iat > 600 {
+		t.Errorf("JWT lifetime exceeds GitHub's 10 minute maximum: %d seconds", claims.Exp-claims.Iat)
+	}
+}
+
+func TestGitHubAppService_GetInstallationToken_Caching(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	config := &models.Config{}
+	config.GitHub.AppID = 12345
+	config.GitHub.InstallationID = 67890
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	var requests int
+	client := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		requests++
+		if req.URL.Path != "/app/installations/67890/access_tokens" {
+			t.Errorf("Unexpected request path: %s", req.URL.Path)
+		}
+		if !strings.HasPrefix(req.Header.Get("Authorization"), "Bearer ") {
+			t.Errorf("Expected Bearer authorization, got '%s'", req.Header.Get("Authorization"))
+		}
+		body, _ := json.Marshal(map[string]interface{}{
+			"token":      "installation-token",
+			"expires_at": now.Add(time.Hour),
+		})
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Body:       io.NopCloser(bytes.NewReader(body)),
+		}, nil
+	})
+
+	service := &GitHubAppServiceImpl{
+		config:     config,
+		client:     client,
+		logger:     zap.NewNop(),
+		privateKey: key,
+		now:        func() time.Time { return now },
+	}
+
+	for i := 0; i < 3; i++ {
+		token, err := service.GetInstallationToken()
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if token != "installation-token" {
+			t.Errorf("Expected 'installation-token', got '%s'", token)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Expected token to be fetched once and cached, got %d requests", requests)
+	}
+
+	// Within the refresh margin the token must be refreshed
+	now = now.Add(56 * time.Minute)
+	if _, err := service.GetInstallationToken(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected token to be refreshed near expiry, got %d requests", requests)
+	}
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// auditedGitHubService is a GitHubService that records every change it makes on GitHub, pushes included, in the
+// audit log. Actions are attributed to the ticket of the context they're performed with.
+type auditedGitHubService struct {
+	GitHubService
+
+	auditLog AuditLog
+	actor    string
+	logger   *zap.Logger
+}
+
+// NewAuditedGitHubService creates a GitHubService recording the changes the given service makes in the audit log
+func NewAuditedGitHubService(githubService GitHubService, config *models.Config, logger *zap.Logger) GitHubService {
+	return &auditedGitHubService{
+		GitHubService: githubService,
+		auditLog:      NewAuditLog(config.AuditLog),
+		actor:         config.GitHub.BotUsername,
+		logger:        logger,
+	}
+}
+
+// record records a change on GitHub that was attempted
+func (s *auditedGitHubService) record(ctx context.Context, action, target, payload string, started time.Time, err error) {
+	recordExternalAction(s.auditLog, s.logger, models.AuditEntry{
+		Ticket:  auditTicket(ctx),
+		Action:  action,
+		Actor:   s.actor,
+		Target:  target,
+		Payload: payload,
+	}, started, err)
+}
+
+// pullRequestTarget returns the audit target of a pull request
+func pullRequestTarget(owner, repo string, prNumber int) string {
+	return fmt.Sprintf("%s/%s#%d", owner, repo, prNumber)
+}
+
+// PushChanges pushes changes to the remote repository
+func (s *auditedGitHubService) PushChanges(ctx context.Context, directory, branchName string) error {
+	started := time.Now()
+	err := s.GitHubService.PushChanges(ctx, directory, branchName)
+	s.record(ctx, "git_push", branchName, "directory="+directory, started, err)
+	return err
+}
+
+// ForcePushChanges force-pushes a rewritten branch
+func (s *auditedGitHubService) ForcePushChanges(ctx context.Context, directory, branchName string) error {
+	started := time.Now()
+	err := s.GitHubService.ForcePushChanges(ctx, directory, branchName)
+	s.record(ctx, "git_force_push", branchName, "directory="+directory, started, err)
+	return err
+}
+
+// CreatePullRequest creates a pull request
+func (s *auditedGitHubService) CreatePullRequest(ctx context.Context, owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error) {
+	started := time.Now()
+	pr, err := s.GitHubService.CreatePullRequest(ctx, owner, repo, title, body, head, base)
+	s.record(ctx, "github_create_pr", createdPRTarget(owner, repo, pr), fmt.Sprintf("head=%s base=%s title=%s", head, base, title), started, err)
+	return pr, err
+}
+
+// CreateDraftPullRequest creates a draft pull request
+func (s *auditedGitHubService) CreateDraftPullRequest(ctx context.Context, owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error) {
+	started := time.Now()
+	pr, err := s.GitHubService.CreateDraftPullRequest(ctx, owner, repo, title, body, head, base)
+	s.record(ctx, "github_create_pr", createdPRTarget(owner, repo, pr), fmt.Sprintf("draft head=%s base=%s title=%s", head, base, title), started, err)
+	return pr, err
+}
+
+// createdPRTarget returns the audit target of a pull request that may not have been created
+func createdPRTarget(owner, repo string, pr *models.GitHubCreatePRResponse) string {
+	if pr == nil {
+		return owner + "/" + repo
+	}
+	return pullRequestTarget(owner, repo, pr.Number)
+}
+
+// MarkPullRequestReady marks a draft pull request as ready for review
+func (s *auditedGitHubService) MarkPullRequestReady(ctx context.Context, owner, repo string, prNumber int) error {
+	started := time.Now()
+	err := s.GitHubService.MarkPullRequestReady(ctx, owner, repo, prNumber)
+	s.record(ctx, "github_mark_ready", pullRequestTarget(owner, repo, prNumber), "", started, err)
+	return err
+}
+
+// ForkRepository forks a repository
+func (s *auditedGitHubService) ForkRepository(ctx context.Context, owner, repo string) (string, error) {
+	started := time.Now()
+	cloneURL, err := s.GitHubService.ForkRepository(ctx, owner, repo)
+	s.record(ctx, "github_fork", owner+"/"+repo, "", started, err)
+	return cloneURL, err
+}
+
+// SyncForkWithUpstream brings a branch of the bot's fork up to date with its upstream repository
+func (s *auditedGitHubService) SyncForkWithUpstream(ctx context.Context, owner, repo, branch string) error {
+	started := time.Now()
+	err := s.GitHubService.SyncForkWithUpstream(ctx, owner, repo, branch)
+	s.record(ctx, "github_sync_fork", owner+"/"+repo, branch, started, err)
+	return err
+}
+
+// AddPRComment adds a comment to a pull request
+func (s *auditedGitHubService) AddPRComment(ctx context.Context, owner, repo string, prNumber int, body string) error {
+	started := time.Now()
+	err := s.GitHubService.AddPRComment(ctx, owner, repo, prNumber, body)
+	s.record(ctx, "github_pr_comment", pullRequestTarget(owner, repo, prNumber), body, started, err)
+	return err
+}
+
+// ReplyToReviewComment replies to a review comment of a pull request
+func (s *auditedGitHubService) ReplyToReviewComment(ctx context.Context, owner, repo string, prNumber int, commentID int64, body string) error {
+	started := time.Now()
+	err := s.GitHubService.ReplyToReviewComment(ctx, owner, repo, prNumber, commentID, body)
+	s.record(ctx, "github_review_reply", pullRequestTarget(owner, repo, prNumber), fmt.Sprintf("comment %d: %s", commentID, body), started, err)
+	return err
+}
+
+// MergePullRequest merges a pull request
+func (s *auditedGitHubService) MergePullRequest(ctx context.Context, owner, repo string, prNumber int, sha string, method models.MergeMethod) (*models.GitHubMergePRResponse, error) {
+	started := time.Now()
+	result, err := s.GitHubService.MergePullRequest(ctx, owner, repo, prNumber, sha, method)
+	s.record(ctx, "github_merge", pullRequestTarget(owner, repo, prNumber), fmt.Sprintf("method=%s sha=%s", method, sha), started, err)
+	return result, err
+}
+
+// RequestReviewers requests reviews of a pull request from users and teams
+func (s *auditedGitHubService) RequestReviewers(ctx context.Context, owner, repo string, prNumber int, reviewers, teamReviewers []string) error {
+	started := time.Now()
+	err := s.GitHubService.RequestReviewers(ctx, owner, repo, prNumber, reviewers, teamReviewers)
+	s.record(ctx, "github_request_reviewers", pullRequestTarget(owner, repo, prNumber),
+		fmt.Sprintf("reviewers=%s teams=%s", strings.Join(reviewers, ","), strings.Join(teamReviewers, ",")), started, err)
+	return err
+}
+
+// AddLabels adds labels to a pull request
+func (s *auditedGitHubService) AddLabels(ctx context.Context, owner, repo string, prNumber int, labels []string) error {
+	started := time.Now()
+	err := s.GitHubService.AddLabels(ctx, owner, repo, prNumber, labels)
+	s.record(ctx, "github_add_labels", pullRequestTarget(owner, repo, prNumber), "labels="+strings.Join(labels, ","), started, err)
+	return err
+}
+
+// SetMilestone sets the milestone of a pull request
+func (s *auditedGitHubService) SetMilestone(ctx context.Context, owner, repo string, prNumber int, title string) error {
+	started := time.Now()
+	err := s.GitHubService.SetMilestone(ctx, owner, repo, prNumber, title)
+	s.record(ctx, "github_set_milestone", pullRequestTarget(owner, repo, prNumber), "milestone="+title, started, err)
+	return err
+}
+
+// DeleteBranch deletes a branch of a repository
+func (s *auditedGitHubService) DeleteBranch(ctx context.Context, owner, repo, branch string) error {
+	started := time.Now()
+	err := s.GitHubService.DeleteBranch(ctx, owner, repo, branch)
+	s.record(ctx, "github_delete_branch", owner+"/"+repo, "branch="+branch, started, err)
+	return err
+}
+
+// AddToProject adds a pull request to a Projects board
+func (s *auditedGitHubService) AddToProject(ctx context.Context, projectOwner string, projectNumber int, contentID, field, column string) error {
+	started := time.Now()
+	err := s.GitHubService.AddToProject(ctx, projectOwner, projectNumber, contentID, field, column)
+	s.record(ctx, "github_add_to_project", fmt.Sprintf("%s/projects/%d", projectOwner, projectNumber),
+		fmt.Sprintf("content=%s %s=%s", contentID, field, column), started, err)
+	return err
+}
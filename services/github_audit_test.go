@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestAuditedGitHubService(t *testing.T) {
+	config := &models.Config{AuditLog: filepath.Join(t.TempDir(), "audit.log")}
+	config.GitHub.BotUsername = "ai-bot"
+	githubService := &mocks.MockGitHubService{
+		CreatePullRequestFunc: func(owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error) {
+			return &models.GitHubCreatePRResponse{Number: 12}, nil
+		},
+	}
+	audited := NewAuditedGitHubService(githubService, config, zap.NewNop())
+
+	ctx := withAuditTicket(context.Background(), "TEST-1")
+	if err := audited.PushChanges(ctx, "/tmp/TEST-1", "feature/TEST-1"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if _, err := audited.CreatePullRequest(ctx, "example", "repo", "TEST-1: Fix the bug", "body", "ai-bot:feature/TEST-1", "main"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if err := audited.CommitChanges(ctx, "/tmp/TEST-1", "TEST-1: Fix the bug"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	entries := readAuditEntries(t, config.AuditLog)
+	if len(entries) != 2 {
+		t.Fatalf("Expected an entry per change on GitHub and none for local commits, got %+v", entries)
+	}
+	push, pr := entries[0], entries[1]
+	if push.Action != "git_push" || push.Ticket != "TEST-1" || push.Actor != "ai-bot" || push.Target != "feature/TEST-1" {
+		t.Errorf("Unexpected push entry: %+v", push)
+	}
+	if pr.Action != "github_create_pr" || pr.Target != "example/repo#12" || pr.Payload != "head=ai-bot:feature/TEST-1 base=main title=TEST-1: Fix the bug" {
+		t.Errorf("Unexpected pull request entry: %+v", pr)
+	}
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"jira-ai-issue-solver/models"
+)
+
+// ListBranches lists the branches of a repository, following pagination
+func (s *GitHubServiceImpl) ListBranches(ctx context.Context, owner, repo string) ([]models.GitHubBranch, error) {
+	token, err := s.getAuthToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auth token: %w", err)
+	}
+
+	var branches []models.GitHubBranch
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("https://api.github.com/repos/%s/%s/branches?per_page=100&page=%d", owner, repo, page)
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to send request: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to list branches: %s, status: %d", string(body), resp.StatusCode)
+		}
+
+		var pageBranches []models.GitHubBranch
+		err = json.NewDecoder(resp.Body).Decode(&pageBranches)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode branches: %w", err)
+		}
+
+		branches = append(branches, pageBranches...)
+		if len(pageBranches) < 100 {
+			break
+		}
+	}
+	return branches, nil
+}
+
+// ListPullRequestsByHead lists the open and closed pull requests of a repository from the head, given as
+// owner:branch, newest first
+func (s *GitHubServiceImpl) ListPullRequestsByHead(ctx context.Context, owner, repo, head string) ([]models.GitHubPRDetails, error) {
+	token, err := s.getAuthToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auth token: %w", err)
+	}
+
+	pullsURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls?state=all&head=%s&per_page=100", owner, repo, url.QueryEscape(head))
+	req, err := http.NewRequestWithContext(ctx, "GET", pullsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to list pull requests: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	var prs []models.GitHubPRDetails
+	if err := json.NewDecoder(resp.Body).Decode(&prs); err != nil {
+		return nil, fmt.Errorf("failed to decode pull requests: %w", err)
+	}
+	return prs, nil
+}
+
+// DeleteBranch deletes a branch of a repository. A branch that no longer exists is not an error.
+func (s *GitHubServiceImpl) DeleteBranch(ctx context.Context, owner, repo, branch string) error {
+	token, err := s.getAuthToken()
+	if err != nil {
+		return fmt.Errorf("failed to get auth token: %w", err)
+	}
+
+	refURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/git/refs/heads/%s", owner, repo, branch)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", refURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// GitHub answers 422 for a reference that doesn't exist
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound && resp.StatusCode != http.StatusUnprocessableEntity {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete branch %s: %s, status: %d", branch, string(body), resp.StatusCode)
+	}
+	return nil
+}
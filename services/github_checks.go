@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"jira-ai-issue-solver/models"
+)
+
+// maxJobLogSize bounds how much of a job log is downloaded
+const maxJobLogSize = 5 << 20
+
+// ListCheckRuns lists the check runs reported on a commit, following pagination
+func (s *GitHubServiceImpl) ListCheckRuns(ctx context.Context, owner, repo, ref string) ([]models.GitHubCheckRun, error) {
+	token, err := s.getAuthToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auth token: %w", err)
+	}
+
+	var checkRuns []models.GitHubCheckRun
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("https://api.github.com/repos/%s/%s/commits/%s/check-runs?per_page=100&page=%d", owner, repo, ref, page)
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to send request: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to list check runs: %s, status: %d", string(body), resp.StatusCode)
+		}
+
+		var pageRuns models.GitHubCheckRunsResponse
+		err = json.NewDecoder(resp.Body).Decode(&pageRuns)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode check runs: %w", err)
+		}
+
+		checkRuns = append(checkRuns, pageRuns.CheckRuns...)
+		if len(pageRuns.CheckRuns) < 100 {
+			break
+		}
+	}
+
+	return checkRuns, nil
+}
+
+// GetCombinedStatus gets the combined commit status of a commit
+func (s *GitHubServiceImpl) GetCombinedStatus(ctx context.Context, owner, repo, ref string) (*models.GitHubCombinedStatus, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/commits/%s/status?per_page=100", owner, repo, ref)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	token, err := s.getAuthToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auth token: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get combined status: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	var status models.GitHubCombinedStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("failed to decode combined status: %w", err)
+	}
+
+	return &status, nil
+}
+
+// GetJobLogs downloads the plain text logs of a GitHub Actions job.
+// The API redirects to a short-lived download URL, which the HTTP client follows.
+func (s *GitHubServiceImpl) GetJobLogs(ctx context.Context, owner, repo string, jobID int64) (string, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/actions/jobs/%d/logs", owner, repo, jobID)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	token, err := s.getAuthToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to get auth token: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed to get job logs: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	logs, err := io.ReadAll(io.LimitReader(resp.Body, maxJobLogSize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read job logs: %w", err)
+	}
+
+	return string(logs), nil
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestListCheckRuns(t *testing.T) {
+	var requestedPath string
+	mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		requestedPath = req.URL.Path
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(bytes.NewReader([]byte(`{"total_count": 2, "check_runs": [
+				{"id": 101, "name": "test", "status": "completed", "conclusion": "failure", "app": {"slug": "github-actions"}},
+				{"id": 102, "name": "lint", "status": "in_progress"}
+			]}`))),
+		}, nil
+	})
+
+	config := &models.Config{}
+	config.GitHub.PersonalAccessToken = "test-token"
+	service := &GitHubServiceImpl{config: config, client: mockClient, executor: execCommand, logger: zap.NewNop()}
+
+	checkRuns, err := service.ListCheckRuns(context.Background(), "example", "repo", "abc123")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if requestedPath != "/repos/example/repo/commits/abc123/check-runs" {
+		t.Errorf("Unexpected request path %q", requestedPath)
+	}
+	if len(checkRuns) != 2 || checkRuns[0].Conclusion != "failure" || checkRuns[0].App.Slug != "github-actions" {
+		t.Errorf("Unexpected check runs: %+v", checkRuns)
+	}
+}
+
+func TestGetJobLogs(t *testing.T) {
+	mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path != "/repos/example/repo/actions/jobs/101/logs" {
+			return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(bytes.NewReader(nil))}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte("--- FAIL: TestSomething\n"))),
+		}, nil
+	})
+
+	config := &models.Config{}
+	config.GitHub.PersonalAccessToken = "test-token"
+	service := &GitHubServiceImpl{config: config, client: mockClient, executor: execCommand, logger: zap.NewNop()}
+
+	logs, err := service.GetJobLogs(context.Background(), "example", "repo", 101)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if logs != "--- FAIL: TestSomething\n" {
+		t.Errorf("Unexpected logs %q", logs)
+	}
+
+	if _, err := service.GetJobLogs(context.Background(), "example", "repo", 999); err == nil {
+		t.Error("Expected an error for a missing job")
+	}
+}
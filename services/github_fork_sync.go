@@ -0,0 +1,175 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// GetDefaultBranch returns the default branch of a repository
+func (s *GitHubServiceImpl) GetDefaultBranch(ctx context.Context, owner, repo string) (string, error) {
+	token, err := s.getAuthToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to get auth token: %w", err)
+	}
+
+	repoURL := fmt.Sprintf("https://api.github.com/repos/%s/%s", owner, repo)
+	req, err := http.NewRequestWithContext(ctx, "GET", repoURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed to get repository %s/%s: %s, status code: %d", owner, repo, string(body), resp.StatusCode)
+	}
+
+	var details struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&details); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if details.DefaultBranch == "" {
+		return "", fmt.Errorf("repository %s/%s has no default branch", owner, repo)
+	}
+	return details.DefaultBranch, nil
+}
+
+// SyncForkWithUpstream brings a branch of the bot's fork up to date with the same branch of its upstream repository,
+// or the upstream's default branch if branch is empty. A branch the fork doesn't have yet, such as a release branch
+// created after the fork, is created at the upstream's head.
+func (s *GitHubServiceImpl) SyncForkWithUpstream(ctx context.Context, owner, repo, branch string) error {
+	token, err := s.getAuthToken()
+	if err != nil {
+		return fmt.Errorf("failed to get auth token: %w", err)
+	}
+
+	if branch == "" {
+		if branch, err = s.GetDefaultBranch(ctx, owner, repo); err != nil {
+			return err
+		}
+	}
+
+	forkOwner := s.config.ForkOwner()
+	forkSHA, err := s.branchSHA(ctx, token, forkOwner, repo, branch)
+	if err != nil {
+		return err
+	}
+	if forkSHA == "" {
+		upstreamSHA, err := s.branchSHA(ctx, token, owner, repo, branch)
+		if err != nil {
+			return err
+		}
+		if upstreamSHA == "" {
+			return fmt.Errorf("branch %s not found in %s/%s", branch, owner, repo)
+		}
+		return s.createBranchRef(ctx, token, forkOwner, repo, branch, upstreamSHA)
+	}
+
+	jsonBody, err := json.Marshal(map[string]string{"branch": branch})
+	if err != nil {
+		return fmt.Errorf("failed to marshal sync request: %w", err)
+	}
+
+	syncURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/merge-upstream", forkOwner, repo)
+	req, err := http.NewRequestWithContext(ctx, "POST", syncURL, bytes.NewReader(jsonBody))
+	if err != nil {
+		return fmt.Errorf("failed to create sync request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send sync request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusConflict {
+		return fmt.Errorf("failed to sync fork: branch %s of %s/%s diverged from upstream", branch, forkOwner, repo)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to sync fork: %s, status code: %d", string(body), resp.StatusCode)
+	}
+	return nil
+}
+
+// branchSHA returns the head commit of a branch, or an empty SHA if the repository has no such branch
+func (s *GitHubServiceImpl) branchSHA(ctx context.Context, token, owner, repo, branch string) (string, error) {
+	refURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/git/ref/heads/%s", owner, repo, (&url.URL{Path: branch}).EscapedPath())
+	req, err := http.NewRequestWithContext(ctx, "GET", refURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed to get branch %s of %s/%s: %s, status code: %d", branch, owner, repo, string(body), resp.StatusCode)
+	}
+
+	var ref struct {
+		Object struct {
+			SHA string `json:"sha"`
+		} `json:"object"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&ref); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	return ref.Object.SHA, nil
+}
+
+// createBranchRef creates a branch at the given commit. Forks share their upstream's objects, so the commit may be
+// one of the upstream repository.
+func (s *GitHubServiceImpl) createBranchRef(ctx context.Context, token, owner, repo, branch, sha string) error {
+	jsonBody, err := json.Marshal(map[string]string{"ref": "refs/heads/" + branch, "sha": sha})
+	if err != nil {
+		return fmt.Errorf("failed to marshal ref request: %w", err)
+	}
+
+	refsURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/git/refs", owner, repo)
+	req, err := http.NewRequestWithContext(ctx, "POST", refsURL, bytes.NewReader(jsonBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to create branch %s in %s/%s: %s, status code: %d", branch, owner, repo, string(body), resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestSyncForkWithUpstream(t *testing.T) {
+	tests := []struct {
+		name        string
+		branch      string
+		forkHasRef  bool
+		syncStatus  int
+		wantSynced  string
+		wantCreated string
+		wantErr     bool
+	}{
+		{name: "existing branch is synced", branch: "release/2.x", forkHasRef: true, syncStatus: http.StatusOK, wantSynced: "release/2.x"},
+		{name: "missing branch is created from upstream", branch: "release/2.x", wantCreated: "refs/heads/release/2.x@upstream-sha"},
+		{name: "default branch is synced without a branch", forkHasRef: true, syncStatus: http.StatusOK, wantSynced: "trunk"},
+		{name: "diverged branch fails", branch: "main", forkHasRef: true, syncStatus: http.StatusConflict, wantSynced: "main", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var synced, created string
+			mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+				respond := func(status int, body string) (*http.Response, error) {
+					return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}, nil
+				}
+				var payload map[string]string
+				if req.Body != nil {
+					_ = json.NewDecoder(req.Body).Decode(&payload)
+				}
+				switch {
+				case req.Method == "GET" && req.URL.Path == "/repos/example/repo":
+					return respond(http.StatusOK, `{"default_branch": "trunk"}`)
+				case req.Method == "GET" && strings.HasPrefix(req.URL.Path, "/repos/ai-bot/repo/git/ref/heads/"):
+					if !tt.forkHasRef {
+						return respond(http.StatusNotFound, `{"message": "Not Found"}`)
+					}
+					return respond(http.StatusOK, `{"object": {"sha": "fork-sha"}}`)
+				case req.Method == "GET" && strings.HasPrefix(req.URL.Path, "/repos/example/repo/git/ref/heads/"):
+					return respond(http.StatusOK, `{"object": {"sha": "upstream-sha"}}`)
+				case req.Method == "POST" && req.URL.Path == "/repos/ai-bot/repo/merge-upstream":
+					synced = payload["branch"]
+					return respond(tt.syncStatus, `{}`)
+				case req.Method == "POST" && req.URL.Path == "/repos/ai-bot/repo/git/refs":
+					created = payload["ref"] + "@" + payload["sha"]
+					return respond(http.StatusCreated, `{}`)
+				}
+				t.Errorf("Unexpected request %s %s", req.Method, req.URL.Path)
+				return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(bytes.NewReader(nil))}, nil
+			})
+			config := &models.Config{}
+			config.GitHub.PersonalAccessToken = "test-token"
+			config.GitHub.BotUsername = "ai-bot"
+			service := &GitHubServiceImpl{config: config, client: mockClient, executor: execCommand, logger: zap.NewNop()}
+
+			err := service.SyncForkWithUpstream(context.Background(), "example", "repo", tt.branch)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got: %v", tt.wantErr, err)
+			}
+			if synced != tt.wantSynced {
+				t.Errorf("Expected %q to be synced, got %q", tt.wantSynced, synced)
+			}
+			if created != tt.wantCreated {
+				t.Errorf("Expected %q to be created, got %q", tt.wantCreated, created)
+			}
+		})
+	}
+}
+
+// TestSwitchToTargetBranch checks out a target branch a single-branch clone doesn't have yet
+func TestSwitchToTargetBranch(t *testing.T) {
+	upstreamDir := t.TempDir()
+	cloneDir := filepath.Join(t.TempDir(), "clone")
+	git := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+
+	git(upstreamDir, "init", "-b", "main")
+	git(upstreamDir, "config", "user.name", "test")
+	git(upstreamDir, "config", "user.email", "test@example.com")
+	git(upstreamDir, "commit", "--allow-empty", "-m", "Initial commit")
+	git(upstreamDir, "checkout", "-b", "release/2.x")
+	git(upstreamDir, "commit", "--allow-empty", "-m", "Release commit")
+	git(upstreamDir, "checkout", "main")
+	git(filepath.Dir(cloneDir), "clone", "--single-branch", "--branch", "main", "file://"+upstreamDir, cloneDir)
+
+	service := &GitHubServiceImpl{config: &models.Config{}, executor: execCommand, logger: zap.NewNop()}
+	for i := 0; i < 2; i++ {
+		if err := service.checkoutRemoteBranch(context.Background(), cloneDir, "release/2.x"); err != nil {
+			t.Fatalf("Expected the target branch to be checked out, got: %v", err)
+		}
+	}
+
+	if branch := git(cloneDir, "rev-parse", "--abbrev-ref", "HEAD"); branch != "release/2.x" {
+		t.Errorf("Expected release/2.x to be checked out, got %s", branch)
+	}
+	if head, upstream := git(cloneDir, "rev-parse", "HEAD"), git(upstreamDir, "rev-parse", "release/2.x"); head != upstream {
+		t.Errorf("Expected the clone at the upstream release/2.x %s, got %s", upstream, head)
+	}
+}
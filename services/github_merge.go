@@ -0,0 +1,59 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"jira-ai-issue-solver/models"
+)
+
+// MergePullRequest merges a pull request with the given method, provided its head still points at sha
+func (s *GitHubServiceImpl) MergePullRequest(ctx context.Context, owner, repo string, prNumber int, sha string, method models.MergeMethod) (*models.GitHubMergePRResponse, error) {
+	payload := models.GitHubMergePRRequest{
+		SHA:         sha,
+		MergeMethod: string(method),
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal merge request: %w", err)
+	}
+
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/merge", owner, repo, prNumber)
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	token, err := s.getAuthToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auth token: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// 405 means the PR isn't mergeable (e.g. required checks or reviews), 409 that its head moved
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to merge pull request: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	var mergeResponse models.GitHubMergePRResponse
+	if err := json.NewDecoder(resp.Body).Decode(&mergeResponse); err != nil {
+		return nil, fmt.Errorf("failed to decode merge response: %w", err)
+	}
+
+	return &mergeResponse, nil
+}
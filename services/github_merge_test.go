@@ -0,0 +1,62 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestMergePullRequest(t *testing.T) {
+	var request models.GitHubMergePRRequest
+	mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		if req.Method != "PUT" || req.URL.Path != "/repos/example/repo/pulls/7/merge" {
+			t.Errorf("Unexpected request %s %s", req.Method, req.URL.Path)
+		}
+		if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`{"sha": "def5678", "merged": true, "message": "Pull Request successfully merged"}`))),
+		}, nil
+	})
+
+	config := &models.Config{}
+	config.GitHub.PersonalAccessToken = "test-token"
+	service := &GitHubServiceImpl{config: config, client: mockClient, executor: execCommand, logger: zap.NewNop()}
+
+	merge, err := service.MergePullRequest(context.Background(), "example", "repo", 7, "abc1234", models.MergeMethodSquash)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if request.SHA != "abc1234" || request.MergeMethod != "squash" {
+		t.Errorf("Unexpected merge request: %+v", request)
+	}
+	if !merge.Merged || merge.SHA != "def5678" {
+		t.Errorf("Unexpected merge response: %+v", merge)
+	}
+}
+
+func TestMergePullRequest_HeadMoved(t *testing.T) {
+	mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusConflict,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`{"message": "Head branch was modified. Review and try the merge again."}`))),
+		}, nil
+	})
+
+	config := &models.Config{}
+	config.GitHub.PersonalAccessToken = "test-token"
+	service := &GitHubServiceImpl{config: config, client: mockClient, executor: execCommand, logger: zap.NewNop()}
+
+	if _, err := service.MergePullRequest(context.Background(), "example", "repo", 7, "abc1234", models.MergeMethodMerge); err == nil {
+		t.Error("Expected an error when the head moved")
+	}
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// projectQuery looks a Projects board up by its owner and number, with the options of its column field
+const projectQuery = `query($owner: String!, $number: Int!, $field: String!) {
+  repositoryOwner(login: $owner) {
+    ... on ProjectV2Owner {
+      projectV2(number: $number) {
+        id
+        field(name: $field) {
+          ... on ProjectV2SingleSelectField { id options { id name } }
+        }
+      }
+    }
+  }
+}`
+
+// addProjectItemMutation adds a pull request to a Projects board
+const addProjectItemMutation = `mutation($project: ID!, $content: ID!) {
+  addProjectV2ItemById(input: {projectId: $project, contentId: $content}) { item { id } }
+}`
+
+// setProjectColumnMutation moves an item of a Projects board to an option of a single-select field
+const setProjectColumnMutation = `mutation($project: ID!, $item: ID!, $field: ID!, $option: String!) {
+  updateProjectV2ItemFieldValue(input: {projectId: $project, itemId: $item, fieldId: $field, value: {singleSelectOptionId: $option}}) {
+    projectV2Item { id }
+  }
+}`
+
+// SetMilestone sets the milestone of a PR to the repository's open milestone with the given title
+func (s *GitHubServiceImpl) SetMilestone(ctx context.Context, owner, repo string, prNumber int, title string) error {
+	token, err := s.getAuthToken()
+	if err != nil {
+		return fmt.Errorf("failed to get auth token: %w", err)
+	}
+
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/milestones?state=open&per_page=100", owner, repo)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to list milestones: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	var milestones []struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&milestones); err != nil {
+		return fmt.Errorf("failed to decode milestones: %w", err)
+	}
+	number := 0
+	for _, milestone := range milestones {
+		if strings.EqualFold(milestone.Title, title) {
+			number = milestone.Number
+			break
+		}
+	}
+	if number == 0 {
+		return fmt.Errorf("no open milestone titled %q in %s/%s", title, owner, repo)
+	}
+
+	// Pull requests are issues, whose milestone the issues API sets
+	jsonPayload, err := json.Marshal(map[string]int{"milestone": number})
+	if err != nil {
+		return fmt.Errorf("failed to marshal milestone request: %w", err)
+	}
+	url = fmt.Sprintf("https://api.github.com/repos/%s/%s/issues/%d", owner, repo, prNumber)
+	req, err = http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err = s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to set milestone: %s, status: %d", string(body), resp.StatusCode)
+	}
+	return nil
+}
+
+// AddToProject adds the PR with the given node ID to a Projects board of an organization or user, in the option of
+// the single-select field named field, or in the board's default if column is empty
+func (s *GitHubServiceImpl) AddToProject(ctx context.Context, projectOwner string, projectNumber int, contentID, field, column string) error {
+	var project struct {
+		RepositoryOwner *struct {
+			ProjectV2 *struct {
+				ID    string `json:"id"`
+				Field *struct {
+					ID      string `json:"id"`
+					Options []struct {
+						ID   string `json:"id"`
+						Name string `json:"name"`
+					} `json:"options"`
+				} `json:"field"`
+			} `json:"projectV2"`
+		} `json:"repositoryOwner"`
+	}
+	err := s.graphQL(ctx, projectQuery, map[string]interface{}{"owner": projectOwner, "number": projectNumber, "field": field}, &project)
+	if err != nil {
+		return fmt.Errorf("failed to look up project: %w", err)
+	}
+	if project.RepositoryOwner == nil || project.RepositoryOwner.ProjectV2 == nil {
+		return fmt.Errorf("no project %d owned by %s", projectNumber, projectOwner)
+	}
+	board := project.RepositoryOwner.ProjectV2
+
+	optionID := ""
+	if column != "" {
+		if board.Field == nil {
+			return fmt.Errorf("project %d has no single-select field %q", projectNumber, field)
+		}
+		for _, option := range board.Field.Options {
+			if strings.EqualFold(option.Name, column) {
+				optionID = option.ID
+				break
+			}
+		}
+		if optionID == "" {
+			return fmt.Errorf("field %q of project %d has no option %q", field, projectNumber, column)
+		}
+	}
+
+	var added struct {
+		AddProjectV2ItemByID struct {
+			Item struct {
+				ID string `json:"id"`
+			} `json:"item"`
+		} `json:"addProjectV2ItemById"`
+	}
+	err = s.graphQL(ctx, addProjectItemMutation, map[string]interface{}{"project": board.ID, "content": contentID}, &added)
+	if err != nil {
+		return fmt.Errorf("failed to add pull request to project: %w", err)
+	}
+	if optionID == "" {
+		return nil
+	}
+
+	err = s.graphQL(ctx, setProjectColumnMutation, map[string]interface{}{
+		"project": board.ID,
+		"item":    added.AddProjectV2ItemByID.Item.ID,
+		"field":   board.Field.ID,
+		"option":  optionID,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to move pull request to column %q: %w", column, err)
+	}
+	return nil
+}
+
+// graphQL sends a GraphQL request and decodes its data into result, unless result is nil
+func (s *GitHubServiceImpl) graphQL(ctx context.Context, query string, variables map[string]interface{}, result interface{}) error {
+	jsonPayload, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		return fmt.Errorf("failed to marshal GraphQL request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.github.com/graphql", bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	token, err := s.getAuthToken()
+	if err != nil {
+		return fmt.Errorf("failed to get auth token: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("GraphQL request failed: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	// GraphQL reports failures in the body with a 200 status
+	var graphQLResponse struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&graphQLResponse); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(graphQLResponse.Errors) > 0 {
+		return fmt.Errorf("GraphQL request failed: %s", graphQLResponse.Errors[0].Message)
+	}
+	if result == nil || len(graphQLResponse.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(graphQLResponse.Data, result); err != nil {
+		return fmt.Errorf("failed to decode response data: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestSetMilestone(t *testing.T) {
+	var milestone map[string]int
+	mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		body := `[{"number": 3, "title": "Sprint 41"}, {"number": 4, "title": "Sprint 42"}]`
+		switch {
+		case req.Method == "GET" && req.URL.Path == "/repos/example/repo/milestones":
+		case req.Method == "PATCH" && req.URL.Path == "/repos/example/repo/issues/7":
+			if err := json.NewDecoder(req.Body).Decode(&milestone); err != nil {
+				t.Errorf("Failed to decode request: %v", err)
+			}
+			body = `{}`
+		default:
+			t.Errorf("Unexpected request %s %s", req.Method, req.URL.Path)
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader([]byte(body)))}, nil
+	})
+
+	config := &models.Config{}
+	config.GitHub.PersonalAccessToken = "test-token"
+	service := &GitHubServiceImpl{config: config, client: mockClient, executor: execCommand, logger: zap.NewNop()}
+
+	if err := service.SetMilestone(context.Background(), "example", "repo", 7, "sprint 42"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if milestone["milestone"] != 4 {
+		t.Errorf("Expected milestone 4 to be set, got %v", milestone)
+	}
+
+	if err := service.SetMilestone(context.Background(), "example", "repo", 7, "Backlog"); err == nil {
+		t.Error("Expected an error for an unknown milestone")
+	}
+}
+
+func TestAddToProject(t *testing.T) {
+	var mutations []map[string]interface{}
+	mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		var request struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		body := `{"data": {}}`
+		switch {
+		case strings.HasPrefix(request.Query, "query"):
+			if request.Variables["owner"] != "my-org" || request.Variables["number"] != float64(5) || request.Variables["field"] != "Status" {
+				t.Errorf("Unexpected project lookup %v", request.Variables)
+			}
+			body = `{"data": {"repositoryOwner": {"projectV2": {"id": "PVT_1", "field": {"id": "FIELD_1", "options": [{"id": "OPT_TODO", "name": "Todo"}, {"id": "OPT_REVIEW", "name": "In Review"}]}}}}}`
+		case strings.Contains(request.Query, "addProjectV2ItemById"):
+			mutations = append(mutations, request.Variables)
+			body = `{"data": {"addProjectV2ItemById": {"item": {"id": "ITEM_1"}}}}`
+		default:
+			mutations = append(mutations, request.Variables)
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader([]byte(body)))}, nil
+	})
+
+	config := &models.Config{}
+	config.GitHub.PersonalAccessToken = "test-token"
+	service := &GitHubServiceImpl{config: config, client: mockClient, executor: execCommand, logger: zap.NewNop()}
+
+	if err := service.AddToProject(context.Background(), "my-org", 5, "PR_kwDOABC", "Status", "in review"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(mutations) != 2 {
+		t.Fatalf("Expected the PR to be added and moved, got %v", mutations)
+	}
+	if mutations[0]["project"] != "PVT_1" || mutations[0]["content"] != "PR_kwDOABC" {
+		t.Errorf("Unexpected add mutation %v", mutations[0])
+	}
+	if mutations[1]["item"] != "ITEM_1" || mutations[1]["field"] != "FIELD_1" || mutations[1]["option"] != "OPT_REVIEW" {
+		t.Errorf("Unexpected column mutation %v", mutations[1])
+	}
+
+	mutations = nil
+	if err := service.AddToProject(context.Background(), "my-org", 5, "PR_kwDOABC", "Status", "Done"); err == nil {
+		t.Error("Expected an error for an unknown column")
+	}
+	if len(mutations) != 0 {
+		t.Errorf("Expected the PR not to be added for an unknown column, got %v", mutations)
+	}
+}
+
+func TestTicketProcessor_AddToPlanning(t *testing.T) {
+	config := &models.Config{}
+	config.GitHub.Planning.Milestone = "Sprint 42"
+	backend := &models.PRPlanningConfig{}
+	backend.Project.Owner = "my-org"
+	backend.Project.Number = 5
+	backend.Project.Column = "In Review"
+	config.Components = map[string]models.ComponentConfig{"backend": {Planning: backend}}
+
+	var milestones []string
+	var projects []string
+	githubService := &mocks.MockGitHubService{
+		SetMilestoneFunc: func(owner, repo string, prNumber int, title string) error {
+			milestones = append(milestones, title)
+			return nil
+		},
+		AddToProjectFunc: func(projectOwner string, projectNumber int, contentID, field, column string) error {
+			projects = append(projects, strings.Join([]string{projectOwner, contentID, field, column}, " "))
+			return nil
+		},
+	}
+	processor := newCheckTestProcessor(t, config, &mocks.MockClaudeService{}, &mocks.MockJiraService{}, githubService)
+	pr := &models.GitHubCreatePRResponse{Number: 7, NodeID: "PR_kwDOABC"}
+
+	processor.addToPlanning(context.Background(), &TicketRun{Key: "TEST-1", Component: "frontend", Owner: "example", Repo: "repo"}, pr)
+	if len(milestones) != 1 || milestones[0] != "Sprint 42" || len(projects) != 0 {
+		t.Errorf("Expected only the global milestone, got milestones %q and projects %q", milestones, projects)
+	}
+
+	milestones = nil
+	processor.addToPlanning(context.Background(), &TicketRun{Key: "TEST-2", Component: "backend", Owner: "example", Repo: "repo"}, pr)
+	if len(milestones) != 0 || len(projects) != 1 || projects[0] != "my-org PR_kwDOABC Status In Review" {
+		t.Errorf("Expected only the component's project, got milestones %q and projects %q", milestones, projects)
+	}
+}
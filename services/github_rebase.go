@@ -0,0 +1,149 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// upstreamRemote is the remote the PR's base repository is fetched from when rebasing a fork branch
+const upstreamRemote = "upstream"
+
+// RebaseOnto fetches a branch of the base repository and rebases the current branch onto it.
+// When the rebase stops on a conflict, it returns the conflicted files and leaves the rebase in progress.
+func (s *GitHubServiceImpl) RebaseOnto(ctx context.Context, directory, owner, repo, branch string) ([]string, error) {
+	ctx, cancel := s.gitContext(ctx)
+	defer cancel()
+
+	// Re-point the remote on every call, the token may have been rotated since the last rebase
+	upstreamURL, err := s.remoteURL(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.runGit(ctx, directory, "remote", "add", upstreamRemote, upstreamURL); err != nil {
+		if err := s.runGit(ctx, directory, "remote", "set-url", upstreamRemote, upstreamURL); err != nil {
+			return nil, fmt.Errorf("failed to configure upstream remote: %w", err)
+		}
+	}
+
+	// Rebasing needs the merge base, which a shallow clone may not contain
+	if shallow, err := s.isShallowRepository(ctx, directory); err != nil {
+		return nil, err
+	} else if shallow {
+		if err := s.refreshRemoteAuth(ctx, directory); err != nil {
+			return nil, err
+		}
+		if err := s.runGit(ctx, directory, "fetch", "--unshallow", "origin"); err != nil {
+			return nil, fmt.Errorf("failed to fetch the full history: %w", err)
+		}
+	}
+
+	upstreamRef := fmt.Sprintf("%s/%s", upstreamRemote, branch)
+	if err := s.runGit(ctx, directory, "fetch", upstreamRemote, fmt.Sprintf("+refs/heads/%s:refs/remotes/%s", branch, upstreamRef)); err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", upstreamRef, err)
+	}
+
+	return s.runRebaseStep(ctx, directory, "rebase", upstreamRef)
+}
+
+// ContinueRebase stages the resolved files and continues the rebase in progress.
+// It returns the conflicted files of the next commit that stops the rebase, if any.
+func (s *GitHubServiceImpl) ContinueRebase(ctx context.Context, directory string) ([]string, error) {
+	ctx, cancel := s.gitContext(ctx)
+	defer cancel()
+
+	if err := s.runGit(ctx, directory, "add", "-A"); err != nil {
+		return nil, fmt.Errorf("failed to stage resolved files: %w", err)
+	}
+	return s.runRebaseStep(ctx, directory, "rebase", "--continue")
+}
+
+// AbortRebase aborts the rebase in progress and restores the original branch
+func (s *GitHubServiceImpl) AbortRebase(ctx context.Context, directory string) error {
+	ctx, cancel := s.gitContext(ctx)
+	defer cancel()
+
+	if err := s.runGit(ctx, directory, "rebase", "--abort"); err != nil {
+		return fmt.Errorf("failed to abort rebase: %w", err)
+	}
+	return nil
+}
+
+// ForcePushChanges pushes a rewritten branch, refusing to overwrite commits pushed by someone else meanwhile
+func (s *GitHubServiceImpl) ForcePushChanges(ctx context.Context, directory, branchName string) error {
+	ctx, cancel := s.gitContext(ctx)
+	defer cancel()
+
+	if err := s.refreshRemoteAuth(ctx, directory); err != nil {
+		return err
+	}
+
+	if err := s.runGit(ctx, directory, "push", "--force-with-lease", "origin", branchName); err != nil {
+		return fmt.Errorf("failed to force push changes: %w", err)
+	}
+	return nil
+}
+
+// runRebaseStep runs a rebase command. A failure caused by conflicts returns the conflicted files instead of an error.
+func (s *GitHubServiceImpl) runRebaseStep(ctx context.Context, directory string, args ...string) ([]string, error) {
+	cmd := s.executor(ctx, "git", args...)
+	cmd.Dir = directory
+	// Keep the original commit messages instead of opening an editor
+	cmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	runErr := cmd.Run()
+	if runErr == nil {
+		return nil, nil
+	}
+
+	conflicts, err := s.conflictedFiles(ctx, directory)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) == 0 {
+		return nil, fmt.Errorf("failed to %s: %w, stderr: %s", strings.Join(args, " "), runErr, stderr.String())
+	}
+	return conflicts, nil
+}
+
+// isShallowRepository reports whether the repository was cloned with a limited history
+func (s *GitHubServiceImpl) isShallowRepository(ctx context.Context, directory string) (bool, error) {
+	cmd := s.executor(ctx, "git", "rev-parse", "--is-shallow-repository")
+	cmd.Dir = directory
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return false, fmt.Errorf("failed to check for a shallow repository: %w, stderr: %s", err, stderr.String())
+	}
+	return strings.TrimSpace(stdout.String()) == "true", nil
+}
+
+// conflictedFiles lists the files with unresolved merge conflicts
+func (s *GitHubServiceImpl) conflictedFiles(ctx context.Context, directory string) ([]string, error) {
+	cmd := s.executor(ctx, "git", "diff", "--name-only", "--diff-filter=U")
+	cmd.Dir = directory
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to list conflicted files: %w, stderr: %s", err, stderr.String())
+	}
+
+	var files []string
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// TestRebaseConflicts rebases a branch onto a conflicting one in a real repository
+func TestRebaseConflicts(t *testing.T) {
+	repoDir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	writeFile := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repoDir, "main.go"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	git("init", "-b", "main")
+	git("config", "user.name", "test")
+	git("config", "user.email", "test@example.com")
+	writeFile("package main\n\nconst version = 1\n")
+	git("add", "-A")
+	git("commit", "-m", "Initial commit")
+	git("checkout", "-b", "TEST-1")
+	writeFile("package main\n\nconst version = 2\n")
+	git("commit", "-am", "Bump version to 2")
+	git("checkout", "main")
+	writeFile("package main\n\nconst version = 3\n")
+	git("commit", "-am", "Bump version to 3")
+	git("checkout", "TEST-1")
+
+	service := &GitHubServiceImpl{config: &models.Config{}, executor: execCommand, logger: zap.NewNop()}
+
+	conflicts, err := service.runRebaseStep(context.Background(), repoDir, "rebase", "main")
+	if err != nil {
+		t.Fatalf("Expected conflicts instead of an error, got: %v", err)
+	}
+	if len(conflicts) != 1 || conflicts[0] != "main.go" {
+		t.Fatalf("Expected main.go to conflict, got %v", conflicts)
+	}
+
+	remaining, err := filesWithConflictMarkers(repoDir, conflicts)
+	if err != nil || len(remaining) != 1 {
+		t.Fatalf("Expected conflict markers in main.go, got %v, %v", remaining, err)
+	}
+
+	writeFile("package main\n\nconst version = 4\n")
+	conflicts, err = service.ContinueRebase(context.Background(), repoDir)
+	if err != nil {
+		t.Fatalf("Expected the rebase to continue, got: %v", err)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("Expected no further conflicts, got %v", conflicts)
+	}
+
+	// A failure that isn't a conflict is reported as an error
+	if _, err := service.runRebaseStep(context.Background(), repoDir, "rebase", "no-such-branch"); err == nil {
+		t.Error("Expected an error when rebasing onto a missing branch")
+	}
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+)
+
+// GetTokenScopes returns the OAuth scopes of the personal access token. Fine-grained tokens and GitHub App
+// installation tokens have no scopes, so none are returned for them once the token is known to work.
+func (s *GitHubServiceImpl) GetTokenScopes(ctx context.Context) ([]string, error) {
+	token, err := s.getAuthToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auth token: %w", err)
+	}
+
+	// Installation tokens can't read /user, but can always list the installation's repositories
+	url := "https://api.github.com/user"
+	if s.config.GitHub.AuthMode == models.GitHubAuthModeApp {
+		url = "https://api.github.com/installation/repositories?per_page=1"
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to check token: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	var scopes []string
+	for _, scope := range strings.Split(resp.Header.Get("X-OAuth-Scopes"), ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
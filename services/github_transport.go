@@ -0,0 +1,229 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// githubRetryBaseDelay is the first backoff delay of a transient failure; it doubles with every retry
+const githubRetryBaseDelay = time.Second
+
+// githubRateLimit is the rate limit state GitHub last reported for a resource
+type githubRateLimit struct {
+	remaining int
+	reset     time.Time
+}
+
+// githubRetryTransport retries GitHub API requests that failed with a rate limit, a server error or a network
+// error, honoring Retry-After and X-RateLimit-Reset, and waits for the reset before sending requests to an
+// exhausted resource. The reported rate limits are exposed as gauges.
+type githubRetryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	maxWait    time.Duration
+	metrics    Metrics
+	logger     *zap.Logger
+	now        func() time.Time
+	sleep      func(req *http.Request, d time.Duration) error
+
+	mu         sync.Mutex
+	rateLimits map[string]githubRateLimit // keyed by resource, e.g. core or search
+}
+
+// newGitHubRetryTransport creates a transport retrying requests as configured in github.api_retry
+func newGitHubRetryTransport(base http.RoundTripper, config *models.Config, metrics Metrics, logger *zap.Logger) *githubRetryTransport {
+	return &githubRetryTransport{
+		base:       base,
+		maxRetries: config.GitHub.APIRetry.MaxRetries,
+		maxWait:    time.Duration(config.GitHub.APIRetry.MaxWaitSeconds) * time.Second,
+		metrics:    metrics,
+		logger:     logger,
+		now:        time.Now,
+		sleep:      sleepContext,
+		rateLimits: make(map[string]githubRateLimit),
+	}
+}
+
+// sleepContext waits for the duration unless the request is canceled first
+func sleepContext(req *http.Request, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
+
+// githubResource returns the rate limit resource a request counts against
+func githubResource(req *http.Request) string {
+	switch {
+	case strings.HasPrefix(req.URL.Path, "/search/"):
+		return "search"
+	case strings.HasPrefix(req.URL.Path, "/graphql"):
+		return "graphql"
+	default:
+		return "core"
+	}
+}
+
+// RoundTrip sends the request, retrying it while it fails transiently
+func (t *githubRetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resource := githubResource(req)
+	if err := t.waitForReset(req, resource); err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to replay request body: %w", err)
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if resp != nil {
+			t.recordRateLimit(resource, resp)
+		}
+
+		wait, reason := t.retryDelay(resp, err, attempt)
+		// Requests without a replayable body can't be sent again, and a request GitHub may have carried out
+		// before failing is only sent again if repeating it does no harm
+		canReplay := req.Body == nil || req.GetBody != nil
+		if reason != "rate_limited" && !isIdempotent(req.Method) {
+			canReplay = false
+		}
+		if reason == "" || attempt >= t.maxRetries || wait > t.maxWait || !canReplay || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		t.logger.Warn("Retrying GitHub API request",
+			zap.String("method", req.Method),
+			zap.String("path", req.URL.Path),
+			zap.String("reason", reason),
+			zap.Int("attempt", attempt+1),
+			zap.Duration("wait", wait))
+		t.metrics.IncCounter("github_api_retries_total", map[string]string{"reason": reason})
+		if err := t.sleep(req, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// isIdempotent reports whether sending a request with the method twice has the same effect as sending it once
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// retryDelay returns how long to wait before retrying and why, or an empty reason if the request shouldn't be retried
+func (t *githubRetryTransport) retryDelay(resp *http.Response, err error, attempt int) (time.Duration, string) {
+	backoff := githubRetryBaseDelay << attempt
+	if err != nil {
+		return backoff, "network_error"
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode == http.StatusForbidden && (resp.Header.Get("X-RateLimit-Remaining") == "0" || resp.Header.Get("Retry-After") != ""):
+		// Secondary rate limits send Retry-After, primary ones the time their window resets
+		if wait, ok := parseRetryAfter(resp); ok {
+			return wait, "rate_limited"
+		}
+		if reset, ok := parseRateLimitReset(resp); ok {
+			return max(reset.Sub(t.now())+time.Second, 0), "rate_limited"
+		}
+		return backoff, "rate_limited"
+	case resp.StatusCode == http.StatusInternalServerError, resp.StatusCode == http.StatusBadGateway,
+		resp.StatusCode == http.StatusServiceUnavailable, resp.StatusCode == http.StatusGatewayTimeout:
+		if wait, ok := parseRetryAfter(resp); ok {
+			return wait, "server_error"
+		}
+		return backoff, "server_error"
+	}
+	return 0, ""
+}
+
+// waitForReset holds a request back until the rate limit of its resource resets, if GitHub reported it exhausted.
+// Resets further away than the maximum wait are left to GitHub to refuse.
+func (t *githubRetryTransport) waitForReset(req *http.Request, resource string) error {
+	t.mu.Lock()
+	limit, ok := t.rateLimits[resource]
+	t.mu.Unlock()
+	if !ok || limit.remaining > 0 {
+		return nil
+	}
+
+	wait := limit.reset.Sub(t.now()) + time.Second
+	if wait <= 0 || wait > t.maxWait {
+		return nil
+	}
+	t.logger.Warn("GitHub API rate limit exhausted, waiting for reset",
+		zap.String("resource", resource),
+		zap.Duration("wait", wait))
+	return t.sleep(req, wait)
+}
+
+// recordRateLimit keeps the rate limit GitHub reported with a response and exposes it as gauges
+func (t *githubRetryTransport) recordRateLimit(resource string, resp *http.Response) {
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	if reported := resp.Header.Get("X-RateLimit-Resource"); reported != "" {
+		resource = reported
+	}
+	reset, _ := parseRateLimitReset(resp)
+
+	t.mu.Lock()
+	t.rateLimits[resource] = githubRateLimit{remaining: remaining, reset: reset}
+	t.mu.Unlock()
+
+	labels := map[string]string{"resource": resource}
+	t.metrics.SetGauge("github_rate_limit_remaining", labels, float64(remaining))
+	if limit, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit")); err == nil {
+		t.metrics.SetGauge("github_rate_limit_limit", labels, float64(limit))
+	}
+	if !reset.IsZero() {
+		t.metrics.SetGauge("github_rate_limit_reset_timestamp_seconds", labels, float64(reset.Unix()))
+	}
+}
+
+// parseRetryAfter returns the wait a Retry-After header in seconds asks for
+func parseRetryAfter(resp *http.Response) (time.Duration, bool) {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// parseRateLimitReset returns the time the X-RateLimit-Reset header says the rate limit window resets
+func parseRateLimitReset(resp *http.Response) (time.Time, bool) {
+	seconds, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0), true
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// newTestRetryTransport creates a transport that records its waits instead of sleeping
+func newTestRetryTransport(now time.Time) (*githubRetryTransport, *[]time.Duration, Metrics) {
+	config := &models.Config{}
+	config.GitHub.APIRetry.MaxRetries = 3
+	config.GitHub.APIRetry.MaxWaitSeconds = 300
+	metrics := NewMetrics()
+
+	var waits []time.Duration
+	transport := newGitHubRetryTransport(http.DefaultTransport, config, metrics, zap.NewNop())
+	transport.now = func() time.Time { return now }
+	transport.sleep = func(req *http.Request, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	return transport, &waits, metrics
+}
+
+func TestGitHubRetryTransport(t *testing.T) {
+	now := time.Unix(1720612800, 0)
+	tests := []struct {
+		name           string
+		method         string
+		responses      []func(w http.ResponseWriter)
+		expectedStatus int
+		expectedCalls  int
+		expectedWaits  []time.Duration
+	}{
+		{
+			name:   "server errors are retried with backoff",
+			method: http.MethodGet,
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) },
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) },
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusOK) },
+			},
+			expectedStatus: http.StatusOK,
+			expectedCalls:  3,
+			expectedWaits:  []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name:   "primary rate limit waits for the reset",
+			method: http.MethodPost,
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("X-RateLimit-Remaining", "0")
+					w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(now.Add(30*time.Second).Unix(), 10))
+					w.WriteHeader(http.StatusForbidden)
+				},
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusCreated) },
+			},
+			expectedStatus: http.StatusCreated,
+			expectedCalls:  2,
+			expectedWaits:  []time.Duration{31 * time.Second},
+		},
+		{
+			name:   "secondary rate limit honors Retry-After",
+			method: http.MethodGet,
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("Retry-After", "60")
+					w.WriteHeader(http.StatusForbidden)
+				},
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusOK) },
+			},
+			expectedStatus: http.StatusOK,
+			expectedCalls:  2,
+			expectedWaits:  []time.Duration{time.Minute},
+		},
+		{
+			name:   "rate limit resetting after the maximum wait fails",
+			method: http.MethodGet,
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("Retry-After", "3600")
+					w.WriteHeader(http.StatusTooManyRequests)
+				},
+			},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedCalls:  1,
+		},
+		{
+			name:   "server errors of non-idempotent requests aren't retried",
+			method: http.MethodPost,
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusInternalServerError) },
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedCalls:  1,
+		},
+		{
+			name:   "client errors aren't retried",
+			method: http.MethodGet,
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusNotFound) },
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedCalls:  1,
+		},
+		{
+			name:   "retries are limited",
+			method: http.MethodGet,
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) },
+			},
+			expectedStatus: http.StatusBadGateway,
+			expectedCalls:  4,
+			expectedWaits:  []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			var bodies []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				bodies = append(bodies, string(body))
+				tt.responses[min(calls, len(tt.responses)-1)](w)
+				calls++
+			}))
+			defer server.Close()
+
+			transport, waits, _ := newTestRetryTransport(now)
+			req, _ := http.NewRequest(tt.method, server.URL+"/repos/example/repo/pulls", bytes.NewBufferString(`{"title":"x"}`))
+			resp, err := (&http.Client{Transport: transport}).Do(req)
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus || calls != tt.expectedCalls {
+				t.Errorf("Expected status %d after %d calls, got %d after %d", tt.expectedStatus, tt.expectedCalls, resp.StatusCode, calls)
+			}
+			if len(*waits) != len(tt.expectedWaits) {
+				t.Fatalf("Expected waits %v, got %v", tt.expectedWaits, *waits)
+			}
+			for i, wait := range tt.expectedWaits {
+				if (*waits)[i] != wait {
+					t.Errorf("Expected waits %v, got %v", tt.expectedWaits, *waits)
+				}
+			}
+			for _, body := range bodies {
+				if body != `{"title":"x"}` {
+					t.Errorf("Expected every attempt to send the body, got %q", body)
+				}
+			}
+		})
+	}
+}
+
+func TestGitHubRetryTransport_RateLimitGauges(t *testing.T) {
+	now := time.Unix(1720612800, 0)
+	reset := now.Add(10 * time.Second)
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		w.Header().Set("X-RateLimit-Resource", "core")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	transport, waits, metrics := newTestRetryTransport(now)
+	client := &http.Client{Transport: transport}
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL + "/repos/example/repo")
+		if err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	// The second request waits for the exhausted limit to reset
+	if len(*waits) != 1 || (*waits)[0] != 11*time.Second {
+		t.Errorf("Expected to wait for the reset before the second request, got %v", *waits)
+	}
+
+	var out strings.Builder
+	if err := metrics.WritePrometheus(&out); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`github_rate_limit_remaining{resource="core"} 0`,
+		`github_rate_limit_limit{resource="core"} 5000`,
+		`github_rate_limit_reset_timestamp_seconds{resource="core"} 1.72061281e+09`,
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected %s in metrics, got:\n%s", expected, out.String())
+		}
+	}
+}
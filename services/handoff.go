@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// commentKeyHandedOff identifies the Jira comment posted when a human takes a PR over
+const commentKeyHandedOff = "handed-off"
+
+// handedOff reports whether the ticket was already handed over to a human; such tickets are skipped for good
+func (p *PRReviewProcessorImpl) handedOff(ticketKey string) bool {
+	if p.stateMachine == nil {
+		return false
+	}
+	state, ok := p.stateMachine.State(ticketKey)
+	return ok && state == models.TicketStateHandedOff
+}
+
+// handoffReason returns why the PR was taken over by a human: the configured stop label, or a comment or
+// review containing the stop command. An empty reason means the bot keeps processing the PR.
+func (p *PRReviewProcessorImpl) handoffReason(pr *models.GitHubPRDetails) string {
+	handoff := p.config.GitHub.Handoff
+	if handoff.Label != "" {
+		for _, label := range pr.Labels {
+			if strings.EqualFold(label.Name, handoff.Label) {
+				return fmt.Sprintf("the %q label was added", label.Name)
+			}
+		}
+	}
+
+	if handoff.Command == "" {
+		return ""
+	}
+	command := strings.ToLower(handoff.Command)
+	requested := func(user, body string) bool {
+		return user != p.config.GitHub.BotUsername && strings.Contains(strings.ToLower(body), command)
+	}
+	for _, review := range pr.Reviews {
+		if requested(review.User.Login, review.Body) {
+			return fmt.Sprintf("@%s asked to stop with %q", review.User.Login, handoff.Command)
+		}
+	}
+	for _, comments := range [][]models.GitHubPRComment{pr.Comments, pr.ReviewComments} {
+		for _, comment := range comments {
+			if requested(comment.User.Login, comment.Body) {
+				return fmt.Sprintf("@%s asked to stop with %q", comment.User.Login, handoff.Command)
+			}
+		}
+	}
+	return ""
+}
+
+// handOff takes the ticket out of the automated flow once a human took its PR over: the ticket moves to the
+// handed_off state, so later scans skip it, and the PR and the Jira ticket are told why
+func (p *PRReviewProcessorImpl) handOff(ctx context.Context, ticketKey, owner, repo string, pr *models.GitHubPRDetails, reason string) error {
+	p.logger.Info("Handing pull request over to a human",
+		zap.String("ticket", ticketKey),
+		zap.Int("pr_number", pr.Number),
+		zap.String("reason", reason))
+
+	if err := p.stateMachine.Transition(ticketKey, models.TicketStateHandedOff, nil); err != nil {
+		p.logger.Warn("Failed to change ticket state", zap.String("ticket", ticketKey), zap.Error(err))
+	}
+
+	prComment := fmt.Sprintf("🤖 Stopping: %s. I won't push further changes to this pull request.", reason)
+	if err := p.githubService.AddPRComment(ctx, owner, repo, pr.Number, prComment); err != nil {
+		p.logger.Error("Failed to add handoff comment to PR", zap.String("ticket", ticketKey), zap.Error(err))
+		// Continue handing the ticket over even if the comment fails
+	}
+
+	jiraComment := fmt.Sprintf("AI processing stopped for pull request %s: %s. A human has taken over.", pr.HTMLURL, reason)
+	if err := upsertJiraComment(ctx, p.jiraService, p.logger, ticketKey, commentKeyHandedOff, jiraComment); err != nil {
+		p.logger.Error("Failed to add handoff comment", zap.String("ticket", ticketKey), zap.Error(err))
+	}
+
+	if status := p.config.Jira.StatusTransitions.HandedOff; status != "" {
+		if err := p.jiraService.UpdateTicketStatus(ctx, ticketKey, status); err != nil {
+			return fmt.Errorf("failed to update ticket status: %w", err)
+		}
+	}
+	return nil
+}
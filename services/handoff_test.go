@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// newHandoffConfig returns a bot config with the default handoff controls
+func newHandoffConfig() *models.Config {
+	config := newBotConfig("ai-bot")
+	config.GitHub.Handoff.Command = "ai:stop"
+	config.GitHub.Handoff.Label = "ai-stop"
+	return config
+}
+
+func TestPRReviewProcessor_HandoffReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		pr       *models.GitHubPRDetails
+		expected string
+	}{
+		{
+			name:     "no stop request",
+			pr:       &models.GitHubPRDetails{Comments: []models.GitHubPRComment{{Body: "Looks good", User: models.GitHubUser{Login: "alice"}}}},
+			expected: "",
+		},
+		{
+			name:     "stop label",
+			pr:       &models.GitHubPRDetails{Labels: []models.GitHubLabel{{Name: "ai-pr"}, {Name: "AI-Stop"}}},
+			expected: `the "AI-Stop" label was added`,
+		},
+		{
+			name:     "stop command in a comment",
+			pr:       &models.GitHubPRDetails{Comments: []models.GitHubPRComment{{Body: "I'll finish this one. AI:STOP", User: models.GitHubUser{Login: "alice"}}}},
+			expected: `@alice asked to stop with "ai:stop"`,
+		},
+		{
+			name:     "stop command in a review",
+			pr:       &models.GitHubPRDetails{Reviews: []models.GitHubReview{{Body: "ai:stop", User: models.GitHubUser{Login: "bob"}}}},
+			expected: `@bob asked to stop with "ai:stop"`,
+		},
+		{
+			name:     "stop command in a review comment",
+			pr:       &models.GitHubPRDetails{ReviewComments: []models.GitHubPRComment{{Body: "ai:stop", User: models.GitHubUser{Login: "carol"}}}},
+			expected: `@carol asked to stop with "ai:stop"`,
+		},
+		{
+			name:     "bot quoting the command",
+			pr:       &models.GitHubPRDetails{Comments: []models.GitHubPRComment{{Body: "Comment ai:stop to take over", User: models.GitHubUser{Login: "ai-bot"}}}},
+			expected: "",
+		},
+	}
+
+	processor := &PRReviewProcessorImpl{config: newHandoffConfig(), logger: zap.NewNop()}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if reason := processor.handoffReason(tt.pr); reason != tt.expected {
+				t.Errorf("Expected reason %q, got %q", tt.expected, reason)
+			}
+		})
+	}
+}
+
+func TestPRReviewProcessor_ProcessPRReviewFeedback_HandOff(t *testing.T) {
+	config := newHandoffConfig()
+	config.Jira.GitPullRequestFieldName = "Git Pull Request"
+	config.Jira.StatusTransitions.HandedOff = "In Progress"
+
+	var status string
+	var jiraComments []string
+	jiraService := &mocks.MockJiraService{
+		GetTicketFunc: func(key string) (*models.JiraTicketResponse, error) {
+			return &models.JiraTicketResponse{Key: key}, nil
+		},
+		GetFieldIDByNameFunc: func(fieldName string) (string, error) {
+			return "customfield_10001", nil
+		},
+		GetTicketWithExpandedFieldsFunc: func(key string) (map[string]interface{}, map[string]string, error) {
+			return map[string]interface{}{"customfield_10001": "https://github.com/example/repo/pull/7"}, nil, nil
+		},
+		UpdateTicketStatusFunc: func(key, newStatus string) error {
+			status = newStatus
+			return nil
+		},
+		AddCommentFunc: func(key, comment string) error {
+			jiraComments = append(jiraComments, comment)
+			return nil
+		},
+	}
+	prDetailsCalls := 0
+	var prComments []string
+	githubService := &mocks.MockGitHubService{
+		GetPRDetailsFunc: func(owner, repo string, prNumber int) (*models.GitHubPRDetails, error) {
+			prDetailsCalls++
+			pr := &models.GitHubPRDetails{Number: 7, State: "open", HTMLURL: "https://github.com/example/repo/pull/7"}
+			pr.Comments = []models.GitHubPRComment{{Body: "ai:stop, I'll take it from here", User: models.GitHubUser{Login: "alice"}}}
+			return pr, nil
+		},
+		AddPRCommentFunc: func(owner, repo string, prNumber int, body string) error {
+			prComments = append(prComments, body)
+			return nil
+		},
+		CloneRepositoryFunc: func(repoURL, directory string, opts models.CloneOptions) error {
+			t.Error("Expected no checkout for a handed off PR")
+			return nil
+		},
+	}
+	stateMachine := newTestStateMachine(config)
+	processor := NewPRReviewProcessor(jiraService, githubService, &mocks.MockClaudeService{}, stateMachine, config, zap.NewNop())
+
+	if err := processor.ProcessPRReviewFeedback(context.Background(), "TEST-1"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if state, _ := stateMachine.State("TEST-1"); state != models.TicketStateHandedOff {
+		t.Errorf("Expected the ticket to be handed off, got %s", state)
+	}
+	if status != "In Progress" {
+		t.Errorf("Expected the ticket to move to the handed off status, got %q", status)
+	}
+	if len(prComments) != 1 || !strings.Contains(prComments[0], "@alice asked to stop") {
+		t.Errorf("Expected a PR comment acknowledging the handoff, got %v", prComments)
+	}
+	if len(jiraComments) != 1 || !strings.Contains(jiraComments[0], "A human has taken over") {
+		t.Errorf("Expected a Jira comment about the handoff, got %v", jiraComments)
+	}
+
+	// Later scans skip the ticket without looking at the PR again
+	if err := processor.ProcessPRReviewFeedback(context.Background(), "TEST-1"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if prDetailsCalls != 1 {
+		t.Errorf("Expected a handed off ticket to be skipped, got %d PR lookups", prDetailsCalls)
+	}
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// requiredGitHubScopes are the OAuth scopes a classic personal access token needs to fork, push and open PRs
+var requiredGitHubScopes = []string{"repo"}
+
+// HealthChecker checks that the application can reach and use its dependencies
+type HealthChecker interface {
+	// Check returns the result of checking every dependency. Results are reused for health.cache_seconds.
+	Check(ctx context.Context) models.HealthReport
+}
+
+// healthCheck checks one dependency, returning what it found
+type healthCheck struct {
+	name  string
+	check func(ctx context.Context) (string, error)
+}
+
+// HealthCheckerImpl implements the HealthChecker interface
+type HealthCheckerImpl struct {
+	checks []healthCheck
+	config *models.Config
+	logger *zap.Logger
+	now    func() time.Time
+
+	mu     sync.Mutex
+	report *models.HealthReport
+}
+
+// NewHealthChecker creates a new HealthChecker verifying the Jira credentials, the GitHub token and the AI CLIs
+func NewHealthChecker(jiraService JiraService, githubService GitHubService, config *models.Config, logger *zap.Logger) HealthChecker {
+	checks := []healthCheck{
+		{name: "jira", check: func(ctx context.Context) (string, error) { return checkJira(ctx, jiraService) }},
+		{name: "github", check: func(ctx context.Context) (string, error) { return checkGitHub(ctx, githubService) }},
+	}
+	for _, provider := range []string{config.AIProvider, config.AIFallbackProvider} {
+		if provider == "" {
+			continue
+		}
+		cliPath := config.Claude.CLIPath
+		if provider == "gemini" {
+			cliPath = config.Gemini.CLIPath
+		}
+		checks = append(checks, healthCheck{
+			name:  provider + "_cli",
+			check: func(ctx context.Context) (string, error) { return aiCLIVersion(ctx, config, cliPath) },
+		})
+	}
+
+	return &HealthCheckerImpl{
+		checks: checks,
+		config: config,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Check returns the result of checking every dependency, running the checks concurrently unless the last results
+// are recent enough to be reused
+func (h *HealthCheckerImpl) Check(ctx context.Context) models.HealthReport {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.now()
+	cacheFor := time.Duration(h.config.Health.CacheSeconds) * time.Second
+	if h.report != nil && now.Sub(h.report.CheckedAt) < cacheFor {
+		return *h.report
+	}
+
+	report := models.HealthReport{Healthy: true, CheckedAt: now, Checks: make([]models.HealthCheck, len(h.checks))}
+	var wg sync.WaitGroup
+	for i, check := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Checks[i] = h.run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if !result.Healthy {
+			report.Healthy = false
+			h.logger.Warn("Health check failed", zap.String("check", result.Name), zap.String("detail", result.Detail))
+		}
+	}
+	h.report = &report
+	return report
+}
+
+// run runs one check, timing it
+func (h *HealthCheckerImpl) run(ctx context.Context, check healthCheck) models.HealthCheck {
+	started := time.Now()
+	detail, err := check.check(ctx)
+	result := models.HealthCheck{Name: check.name, Healthy: err == nil, Detail: detail, LatencyMS: time.Since(started).Milliseconds()}
+	if err != nil {
+		result.Detail = err.Error()
+	}
+	return result
+}
+
+// checkJira verifies the Jira credentials by fetching the user they authenticate as
+func checkJira(ctx context.Context, jiraService JiraService) (string, error) {
+	user, err := jiraService.GetCurrentUser(ctx)
+	if err != nil {
+		return "", err
+	}
+	name := user.Name
+	if name == "" {
+		name = user.DisplayName
+	}
+	return "authenticated as " + name, nil
+}
+
+// checkGitHub verifies the GitHub token works and, for classic tokens, has the scopes the application needs
+func checkGitHub(ctx context.Context, githubService GitHubService) (string, error) {
+	scopes, err := githubService.GetTokenScopes(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(scopes) == 0 {
+		return "token accepted (no OAuth scopes to check)", nil
+	}
+	var missing []string
+	for _, scope := range requiredGitHubScopes {
+		if !slices.Contains(scopes, scope) {
+			missing = append(missing, scope)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("token is missing scopes %s (has %s)", strings.Join(missing, ", "), strings.Join(scopes, ", "))
+	}
+	return "token scopes: " + strings.Join(scopes, ", "), nil
+}
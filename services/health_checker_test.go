@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestHealthChecker_Check(t *testing.T) {
+	cliPath := filepath.Join(t.TempDir(), "claude")
+	if err := os.WriteFile(cliPath, []byte("#!/bin/sh\necho '1.0.0 (Claude Code)'\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		jiraErr     error
+		scopes      []string
+		wantHealthy bool
+	}{
+		{name: "healthy", scopes: []string{"repo", "read:org"}, wantHealthy: true},
+		{name: "fine-grained token", wantHealthy: true},
+		{name: "bad Jira credentials", jiraErr: errors.New("status code: 401"), scopes: []string{"repo"}},
+		{name: "missing scope", scopes: []string{"public_repo"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jiraService := &mocks.MockJiraService{
+				GetCurrentUserFunc: func() (*models.JiraUser, error) {
+					if tt.jiraErr != nil {
+						return nil, tt.jiraErr
+					}
+					return &models.JiraUser{Name: "ai-bot"}, nil
+				},
+			}
+			githubService := &mocks.MockGitHubService{
+				GetTokenScopesFunc: func() ([]string, error) { return tt.scopes, nil },
+			}
+			config := &models.Config{AIProvider: "claude"}
+			config.Claude.CLIPath = cliPath
+
+			report := NewHealthChecker(jiraService, githubService, config, zap.NewNop()).Check(context.Background())
+			if report.Healthy != tt.wantHealthy {
+				t.Errorf("Healthy = %v, want %v: %+v", report.Healthy, tt.wantHealthy, report.Checks)
+			}
+			if len(report.Checks) != 3 {
+				t.Fatalf("Expected the jira, github and claude_cli checks, got %+v", report.Checks)
+			}
+			if cli := report.Checks[2]; cli.Name != "claude_cli" || !cli.Healthy || cli.Detail != "1.0.0 (Claude Code)" {
+				t.Errorf("Unexpected CLI check: %+v", cli)
+			}
+		})
+	}
+}
+
+func TestHealthChecker_Check_MissingCLI(t *testing.T) {
+	config := &models.Config{AIProvider: "gemini"}
+	config.Gemini.CLIPath = filepath.Join(t.TempDir(), "gemini")
+
+	report := NewHealthChecker(&mocks.MockJiraService{}, &mocks.MockGitHubService{}, config, zap.NewNop()).Check(context.Background())
+	if report.Healthy {
+		t.Errorf("Expected a missing CLI to fail the checks: %+v", report.Checks)
+	}
+}
+
+func TestHealthChecker_Check_Cache(t *testing.T) {
+	calls := 0
+	jiraService := &mocks.MockJiraService{
+		GetCurrentUserFunc: func() (*models.JiraUser, error) {
+			calls++
+			return &models.JiraUser{Name: "ai-bot"}, nil
+		},
+	}
+	config := &models.Config{}
+	config.Health.CacheSeconds = 60
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	checker := NewHealthChecker(jiraService, &mocks.MockGitHubService{}, config, zap.NewNop()).(*HealthCheckerImpl)
+	checker.now = func() time.Time { return now }
+
+	checker.Check(context.Background())
+	now = now.Add(30 * time.Second)
+	checker.Check(context.Background())
+	if calls != 1 {
+		t.Errorf("Expected the cached report to be reused, Jira was checked %d times", calls)
+	}
+
+	now = now.Add(time.Minute)
+	checker.Check(context.Background())
+	if calls != 2 {
+		t.Errorf("Expected an expired report to be refreshed, Jira was checked %d times", calls)
+	}
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+)
+
+// defaultIssueTypeInstructions are the built-in instructions by Jira issue type name
+var defaultIssueTypeInstructions = map[string]string{
+	"Bug": "This ticket is a bug. Before changing code, find the cause: reproduce the failure from the description, " +
+		"e.g. with a failing test, and fix the cause rather than the symptom. Add a regression test that fails without " +
+		"the fix, and keep the change to what the fix needs.",
+	"Story": "This ticket is a story. Implement every acceptance criterion of the ticket and cover each with a test. " +
+		"If a criterion is ambiguous, choose the simplest reading consistent with the rest of the ticket.",
+	"Task": "This ticket is a task. Do what it describes and nothing more, and keep the existing behavior unless " +
+		"the ticket asks to change it.",
+	"Tech Debt": "This ticket is technical debt. Keep the behavior unchanged: refactor behind the existing tests, " +
+		"adding tests first where the changed code isn't covered, and don't mix in functional changes.",
+}
+
+// issueTypeInstructions returns the instructions by issue type name: the built-in ones replaced or removed by the
+// configured ones
+func issueTypeInstructions(configured map[string]string) map[string]string {
+	instructions := make(map[string]string, len(defaultIssueTypeInstructions)+len(configured))
+	for name, text := range defaultIssueTypeInstructions {
+		instructions[name] = text
+	}
+	for name, text := range configured {
+		if strings.TrimSpace(text) == "" {
+			delete(instructions, name)
+			continue
+		}
+		instructions[name] = strings.TrimSpace(text)
+	}
+	return instructions
+}
+
+// issueTypePrompt returns the instructions for the ticket's issue type for the generation prompt, or an empty string
+// if there are none. Issue type names are matched case-insensitively.
+func issueTypePrompt(config *models.Config, ticket *models.JiraTicketResponse) string {
+	issueType := ticket.Fields.IssueType.Name
+	if issueType == "" {
+		return ""
+	}
+	for name, text := range issueTypeInstructions(config.IssueTypeInstructions()) {
+		if strings.EqualFold(name, issueType) {
+			return text + "\n\n"
+		}
+	}
+	return ""
+}
+
+// skipsIssueType reports whether tickets of the issue type are left to humans
+func skipsIssueType(config *models.Config, issueType string) bool {
+	return issueType != "" && slices.ContainsFunc(config.Jira.IssueTypes.Skip, func(skipped string) bool {
+		return strings.EqualFold(skipped, issueType)
+	})
+}
+
+// skippedIssueTypesJQL returns the JQL clause excluding the skipped issue types from a search, or an empty string if
+// none are skipped
+func skippedIssueTypesJQL(config *models.Config) string {
+	if len(config.Jira.IssueTypes.Skip) == 0 {
+		return ""
+	}
+	quoted := make([]string, 0, len(config.Jira.IssueTypes.Skip))
+	for _, issueType := range config.Jira.IssueTypes.Skip {
+		quoted = append(quoted, fmt.Sprintf("%q", issueType))
+	}
+	return fmt.Sprintf(" AND issuetype NOT IN (%s)", strings.Join(quoted, ", "))
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+)
+
+func TestIssueTypePrompt(t *testing.T) {
+	config := &models.Config{}
+	config.Jira.IssueTypes.Instructions = map[string]string{
+		"Story": "",
+		"Spike": "  Write your findings to docs/spikes/.\n",
+	}
+
+	tests := []struct {
+		issueType string
+		want      string
+	}{
+		{issueType: "Bug", want: defaultIssueTypeInstructions["Bug"] + "\n\n"},
+		{issueType: "tech debt", want: defaultIssueTypeInstructions["Tech Debt"] + "\n\n"},
+		{issueType: "Spike", want: "Write your findings to docs/spikes/.\n\n"},
+		{issueType: "Story", want: ""},
+		{issueType: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.issueType, func(t *testing.T) {
+			ticket := &models.JiraTicketResponse{Key: "TEST-1"}
+			ticket.Fields.IssueType.Name = tt.issueType
+			if got := issueTypePrompt(config, ticket); got != tt.want {
+				t.Errorf("issueTypePrompt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	ticket := &models.JiraTicketResponse{Key: "TEST-1"}
+	ticket.Fields.IssueType.Name = "Bug"
+	if prompt := generatePrompt(config, ticket); !strings.Contains(prompt, "regression test") {
+		t.Errorf("Expected the bug instructions in the prompt, got %q", prompt)
+	}
+}
+
+func TestSkippedIssueTypesJQL(t *testing.T) {
+	config := &models.Config{}
+	if got := skippedIssueTypesJQL(config); got != "" {
+		t.Errorf("skippedIssueTypesJQL() = %q, want none", got)
+	}
+
+	config.Jira.IssueTypes.Skip = []string{"Epic", "Sub-task"}
+	if got, want := skippedIssueTypesJQL(config), ` AND issuetype NOT IN ("Epic", "Sub-task")`; got != want {
+		t.Errorf("skippedIssueTypesJQL() = %q, want %q", got, want)
+	}
+	if !skipsIssueType(config, "epic") || skipsIssueType(config, "Bug") || skipsIssueType(config, "") {
+		t.Error("Expected only the configured issue types to be skipped")
+	}
+}
+
+func TestTicketProcessor_PrepareTicketSkipsIssueType(t *testing.T) {
+	config := &models.Config{}
+	config.Jira.IssueTypes.Skip = []string{"Epic"}
+	config.ComponentToRepo = map[string]string{"frontend": "https://github.com/example/frontend.git"}
+
+	var comments []string
+	jiraService := &mocks.MockJiraService{
+		GetTicketFunc: func(key string) (*models.JiraTicketResponse, error) {
+			ticket := &models.JiraTicketResponse{Key: key}
+			ticket.Fields.IssueType.Name = "Epic"
+			ticket.Fields.Components = []models.JiraComponent{{Name: "frontend"}}
+			return ticket, nil
+		},
+		AddCommentFunc: func(key, comment string) error {
+			comments = append(comments, comment)
+			return nil
+		},
+		UpdateTicketStatusFunc: func(key, status string) error {
+			t.Errorf("Expected the ticket's status to be left unchanged, got %q", status)
+			return nil
+		},
+	}
+	processor := newCheckTestProcessor(t, config, &mocks.MockClaudeService{}, jiraService, &mocks.MockGitHubService{})
+
+	if err := processor.prepareTicket(context.Background(), &TicketRun{Key: "TEST-1"}); err == nil {
+		t.Fatal("Expected tickets of a skipped issue type to fail")
+	}
+	if len(comments) != 1 || !strings.Contains(comments[0], "Tickets of type Epic are not processed") {
+		t.Errorf("Expected a comment explaining the skip, got %q", comments)
+	}
+}
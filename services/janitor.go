@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// commentKeyStuck is the idempotency key of the Jira comment on tickets the janitor reset
+const commentKeyStuck = "stuck"
+
+// JanitorService defines the interface for the service resetting stuck tickets
+type JanitorService interface {
+	// Start starts the periodic search for stuck tickets
+	Start()
+	// Stop stops the periodic search, waiting for a running sweep to finish. The janitor can be started again.
+	Stop()
+}
+
+// JanitorServiceImpl implements the JanitorService interface
+type JanitorServiceImpl struct {
+	ctx          context.Context // Bounds the work of the service; canceling it aborts work in progress
+	jiraService  JiraService
+	stateMachine TicketStateMachine
+	config       *models.Config
+	logger       *zap.Logger
+
+	mu        sync.Mutex
+	stopChan  chan struct{} // Closed to stop the current sweep loop
+	doneChan  chan struct{} // Closed once the current sweep loop returned
+	isRunning bool
+}
+
+// NewJanitorService creates a new JanitorService
+func NewJanitorService(
+	ctx context.Context,
+	jiraService JiraService,
+	stateMachine TicketStateMachine,
+	config *models.Config,
+	logger *zap.Logger,
+) JanitorService {
+	return &JanitorServiceImpl{
+		ctx:          ctx,
+		jiraService:  jiraService,
+		stateMachine: stateMachine,
+		config:       config,
+		logger:       logger,
+	}
+}
+
+// Start starts the periodic search for stuck tickets
+func (s *JanitorServiceImpl) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.isRunning {
+		s.logger.Info("Janitor is already running")
+		return
+	}
+
+	s.isRunning = true
+	s.stopChan = make(chan struct{})
+	s.doneChan = make(chan struct{})
+	s.logger.Info("Starting janitor...")
+
+	stopChan, doneChan := s.stopChan, s.doneChan
+	go func() {
+		defer close(doneChan)
+		ticker := time.NewTicker(time.Duration(s.config.Janitor.IntervalSeconds) * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.resetStuckTickets(s.ctx)
+			case <-stopChan:
+				s.logger.Info("Stopping janitor...")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the periodic search, waiting for a running sweep to finish. The janitor can be started again.
+func (s *JanitorServiceImpl) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.isRunning {
+		return
+	}
+
+	s.isRunning = false
+	close(s.stopChan)
+	<-s.doneChan
+}
+
+// resetStuckTickets searches for tickets in the in progress status that have no pull request and made no progress
+// within the configured time, and resets them
+func (s *JanitorServiceImpl) resetStuckTickets(ctx context.Context) {
+	stuckAfter := time.Duration(s.config.Janitor.StuckAfterMinutes) * time.Minute
+
+	// Tickets requeued earlier are back in todo, tickets labelled earlier wait for a human
+	jql := fmt.Sprintf(`Contributors = currentUser() AND status = "%s" AND "%s" IS EMPTY AND updated <= "-%dm" AND (labels IS EMPTY OR labels != "%s") ORDER BY updated ASC`,
+		s.config.Jira.StatusTransitions.InProgress, s.config.Jira.GitPullRequestFieldName,
+		s.config.Janitor.StuckAfterMinutes, s.config.Janitor.Label)
+
+	searchResponse, err := s.jiraService.SearchTickets(ctx, jql)
+	if err != nil {
+		s.logger.Error("Failed to search for stuck tickets", zap.Error(err))
+		return
+	}
+
+	// The AI doesn't touch the Jira ticket while it works, so the local pipeline state tells whether it's progressing
+	lastProgress := make(map[string]time.Time)
+	for _, record := range s.stateMachine.Records() {
+		if !record.State.IsTerminal() {
+			lastProgress[record.Ticket] = record.UpdatedAt
+		}
+	}
+
+	for _, issue := range searchResponse.Issues {
+		if at, ok := lastProgress[issue.Key]; ok && time.Since(at) < stuckAfter {
+			s.logger.Debug("Ticket is still being processed", zap.String("ticket", issue.Key))
+			continue
+		}
+		s.resetTicket(ctx, issue.Key, stuckAfter)
+	}
+}
+
+// resetTicket fails the ticket's pipeline, removes its checkouts and either moves it back to the todo status or
+// labels it for a human
+func (s *JanitorServiceImpl) resetTicket(ctx context.Context, ticketKey string, stuckAfter time.Duration) {
+	s.logger.Warn("Resetting stuck ticket", zap.String("ticket", ticketKey), zap.Bool("requeue", s.config.Janitor.Requeue))
+
+	if state, ok := s.stateMachine.State(ticketKey); ok && !state.IsTerminal() {
+		cause := fmt.Errorf("stuck in %s for more than %s", state, stuckAfter)
+		if err := s.stateMachine.Transition(ticketKey, models.TicketStateFailed, cause); err != nil {
+			s.logger.Error("Failed to change ticket state", zap.String("ticket", ticketKey), zap.Error(err))
+		}
+	}
+
+	removeCheckouts(s.config, s.logger, ticketKey)
+
+	var comment string
+	if s.config.Janitor.Requeue {
+		if err := s.jiraService.UpdateTicketStatus(ctx, ticketKey, s.config.Jira.StatusTransitions.Todo); err != nil {
+			s.logger.Error("Failed to move stuck ticket back to todo", zap.String("ticket", ticketKey), zap.Error(err))
+			return
+		}
+		comment = fmt.Sprintf("AI made no progress on this ticket for more than %s without opening a pull request. It was moved back to %s and will be retried.",
+			stuckAfter, s.config.Jira.StatusTransitions.Todo)
+	} else {
+		if err := s.jiraService.UpdateTicketLabels(ctx, ticketKey, []string{s.config.Janitor.Label}, nil); err != nil {
+			s.logger.Error("Failed to label stuck ticket", zap.String("ticket", ticketKey), zap.Error(err))
+			return
+		}
+		comment = fmt.Sprintf("AI made no progress on this ticket for more than %s without opening a pull request and stopped working on it. Remove the %s label and move the ticket back to %s to retry.",
+			stuckAfter, s.config.Janitor.Label, s.config.Jira.StatusTransitions.Todo)
+	}
+
+	if err := upsertJiraComment(ctx, s.jiraService, s.logger, ticketKey, commentKeyStuck, comment); err != nil {
+		s.logger.Error("Failed to add stuck comment", zap.String("ticket", ticketKey), zap.Error(err))
+	}
+}
+
+// removeCheckouts deletes the ticket's checkout and the checkouts of its feedback runs
+func removeCheckouts(config *models.Config, logger *zap.Logger, ticketKey string) {
+	if config.TempDir == "" {
+		return
+	}
+	dirs, err := filepath.Glob(filepath.Join(config.TempDir, ticketKey+"-*"))
+	if err != nil {
+		logger.Warn("Failed to list checkouts", zap.String("ticket", ticketKey), zap.Error(err))
+	}
+	for _, dir := range append(dirs, filepath.Join(config.TempDir, ticketKey)) {
+		if err := os.RemoveAll(dir); err != nil {
+			logger.Warn("Failed to remove checkout", zap.String("ticket", ticketKey), zap.String("dir", dir), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestJanitorService_ResetStuckTickets(t *testing.T) {
+	tests := []struct {
+		name           string
+		requeue        bool
+		expectedStatus string
+		expectedLabels []string
+	}{
+		{name: "label", expectedLabels: []string{"ai-stuck"}},
+		{name: "requeue", requeue: true, expectedStatus: "To Do"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &models.Config{}
+			config.TempDir = t.TempDir()
+			config.StateFile = filepath.Join(t.TempDir(), "ticket-states.json")
+			config.Jira.StatusTransitions.Todo = "To Do"
+			config.Jira.StatusTransitions.InProgress = "In Progress"
+			config.Jira.GitPullRequestFieldName = "Git Pull Request"
+			config.Janitor.StuckAfterMinutes = 120
+			config.Janitor.Requeue = tt.requeue
+			config.Janitor.Label = "ai-stuck"
+
+			// TEST-1 is still being worked on, TEST-2 stopped progressing a long time ago
+			states := `{"TEST-2": {"ticket": "TEST-2", "state": "generating", "updated_at": "2024-07-10T12:00:00Z"}}`
+			if err := os.WriteFile(config.StateFile, []byte(states), 0600); err != nil {
+				t.Fatal(err)
+			}
+			stateMachine := newTestStateMachine(config)
+			if err := stateMachine.Transition("TEST-1", models.TicketStateQueued, nil); err != nil {
+				t.Fatal(err)
+			}
+			for _, dir := range []string{"TEST-2", "TEST-2-feedback", "TEST-20"} {
+				if err := os.MkdirAll(filepath.Join(config.TempDir, dir), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var jql, status string
+			var labels, comments []string
+			jiraService := &mocks.MockJiraService{
+				SearchTicketsFunc: func(query string) (*models.JiraSearchResponse, error) {
+					jql = query
+					return &models.JiraSearchResponse{Total: 2, Issues: []models.JiraIssue{{Key: "TEST-1"}, {Key: "TEST-2"}}}, nil
+				},
+				UpdateTicketStatusFunc: func(key, newStatus string) error {
+					if key != "TEST-2" {
+						t.Errorf("Expected only TEST-2 to be reset, got %s", key)
+					}
+					status = newStatus
+					return nil
+				},
+				UpdateTicketLabelsFunc: func(key string, addLabels, removeLabels []string) error {
+					if key != "TEST-2" {
+						t.Errorf("Expected only TEST-2 to be reset, got %s", key)
+					}
+					labels = addLabels
+					return nil
+				},
+				AddCommentFunc: func(key, comment string) error {
+					comments = append(comments, comment)
+					return nil
+				},
+			}
+
+			janitor := NewJanitorService(context.Background(), jiraService, stateMachine, config, zap.NewNop()).(*JanitorServiceImpl)
+			janitor.resetStuckTickets(context.Background())
+
+			if !strings.Contains(jql, `status = "In Progress" AND "Git Pull Request" IS EMPTY AND updated <= "-120m"`) {
+				t.Errorf("Unexpected JQL: %s", jql)
+			}
+			if status != tt.expectedStatus || !reflect.DeepEqual(labels, tt.expectedLabels) {
+				t.Errorf("Expected status %q and labels %v, got %q and %v", tt.expectedStatus, tt.expectedLabels, status, labels)
+			}
+			if len(comments) != 1 {
+				t.Errorf("Expected one comment on the reset ticket, got %v", comments)
+			}
+			if state, _ := stateMachine.State("TEST-2"); state != models.TicketStateFailed {
+				t.Errorf("Expected the stuck ticket to fail, got %s", state)
+			}
+			if state, _ := stateMachine.State("TEST-1"); state != models.TicketStateQueued {
+				t.Errorf("Expected the progressing ticket to be left alone, got %s", state)
+			}
+			for dir, exists := range map[string]bool{"TEST-2": false, "TEST-2-feedback": false, "TEST-20": true} {
+				if _, err := os.Stat(filepath.Join(config.TempDir, dir)); (err == nil) != exists {
+					t.Errorf("Expected %s to exist: %v, got %v", dir, exists, err)
+				}
+			}
+		})
+	}
+}
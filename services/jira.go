@@ -2,76 +2,147 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os/exec"
 	"strings"
+	"sync/atomic"
 
 	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
 )
 
+// ErrJiraFieldNotFound is returned when no Jira field has the name a field is looked up by
+var ErrJiraFieldNotFound = errors.New("field not found")
+
 // JiraService defines the interface for interacting with Jira
 type JiraService interface {
 	// GetTicket fetches a ticket from Jira
-	GetTicket(key string) (*models.JiraTicketResponse, error)
+	GetTicket(ctx context.Context, key string) (*models.JiraTicketResponse, error)
 
 	// GetTicketWithExpandedFields fetches a ticket from Jira with expanded fields for custom field access
-	GetTicketWithExpandedFields(key string) (map[string]interface{}, map[string]string, error)
+	GetTicketWithExpandedFields(ctx context.Context, key string) (map[string]interface{}, map[string]string, error)
 
 	// UpdateTicketLabels updates the labels of a ticket
-	UpdateTicketLabels(key string, addLabels, removeLabels []string) error
+	UpdateTicketLabels(ctx context.Context, key string, addLabels, removeLabels []string) error
 
 	// UpdateTicketStatus updates the status of a ticket
-	UpdateTicketStatus(key string, status string) error
+	UpdateTicketStatus(ctx context.Context, key string, status string) error
 
 	// UpdateTicketField updates a specific field of a ticket
-	UpdateTicketField(key string, fieldID string, value interface{}) error
+	UpdateTicketField(ctx context.Context, key string, fieldID string, value interface{}) error
 
 	// UpdateTicketFieldByName updates a specific field of a ticket by field name
-	UpdateTicketFieldByName(key string, fieldName string, value interface{}) error
+	UpdateTicketFieldByName(ctx context.Context, key string, fieldName string, value interface{}) error
 
 	// GetFieldIDByName resolves a field name to its ID
-	GetFieldIDByName(fieldName string) (string, error)
+	GetFieldIDByName(ctx context.Context, fieldName string) (string, error)
 
 	// AddComment adds a comment to a ticket
-	AddComment(key string, comment string) error
+	AddComment(ctx context.Context, key string, comment string) error
+
+	// GetComments fetches all comments of a ticket
+	GetComments(ctx context.Context, key string) ([]models.JiraComment, error)
+
+	// UpdateComment replaces the body of an existing comment
+	UpdateComment(ctx context.Context, key, commentID, comment string) error
 
 	// SearchTickets searches for tickets using JQL
-	SearchTickets(jql string) (*models.JiraSearchResponse, error)
+	SearchTickets(ctx context.Context, jql string) (*models.JiraSearchResponse, error)
+
+	// CreateTicket creates a Jira issue and returns its key
+	CreateTicket(ctx context.Context, fields models.JiraCreateIssueFields) (string, error)
+
+	// LinkTickets links two Jira issues with the given link type, e.g. "Relates"
+	LinkTickets(ctx context.Context, linkType, inwardKey, outwardKey string) error
+
+	// AddRemoteLink links a ticket to an object outside Jira, replacing an earlier link with the same global ID
+	AddRemoteLink(ctx context.Context, key string, link models.JiraRemoteLink) error
+
+	// AddWorklog logs time spent on a ticket, as the worklog account if one is configured
+	AddWorklog(ctx context.Context, key string, worklog models.JiraWorklog) error
+
+	// GetActiveSprints returns the active sprints of a Jira Software board
+	GetActiveSprints(ctx context.Context, boardID int) ([]models.JiraSprint, error)
+
+	// GetProjectProperty returns the value of a project's entity property, or nil if the project doesn't have it
+	GetProjectProperty(ctx context.Context, projectKey, propertyKey string) (json.RawMessage, error)
+
+	// GetProjectStatuses returns the names of the statuses the workflows of a project's issue types use
+	GetProjectStatuses(ctx context.Context, projectKey string) ([]string, error)
+
+	// CreateCustomField creates a global custom field of the given type and returns its ID
+	CreateCustomField(ctx context.Context, name, fieldType string) (string, error)
+
+	// DetectTokenType checks the API token and returns the token type Jira accepts it with, used from then on
+	DetectTokenType(ctx context.Context) (models.JiraTokenType, error)
+
+	// GetCurrentUser returns the user the application is authenticated as
+	GetCurrentUser(ctx context.Context) (*models.JiraUser, error)
+
+	// AddAttachment attaches a file to a ticket
+	AddAttachment(ctx context.Context, key, filename string, content []byte) error
 }
 
 // JiraServiceImpl implements the JiraService interface
 type JiraServiceImpl struct {
-	config   *models.Config
-	client   *http.Client
-	executor models.CommandExecutor
+	config         *models.Config
+	client         *http.Client
+	executor       models.CommandExecutor
+	connectService JiraConnectService
+
+	detectedTokenType atomic.Value // models.JiraTokenType Jira accepted the API token with at startup
 }
 
-// NewJiraService creates a new JiraService
-func NewJiraService(config *models.Config, executor ...models.CommandExecutor) JiraService {
-	commandExecutor := exec.Command
+// NewJiraService creates a new JiraService. API requests are throttled and retried when Jira rate limits them.
+func NewJiraService(config *models.Config, metrics Metrics, logger *zap.Logger, executor ...models.CommandExecutor) JiraService {
+	commandExecutor := exec.CommandContext
 	if len(executor) > 0 {
 		commandExecutor = executor[0]
 	}
 	return &JiraServiceImpl{
 		config:   config,
-		client:   &http.Client{},
+		client:   &http.Client{Transport: newJiraThrottleTransport(newTimeoutTransport(http.DefaultTransport, config.HTTPTimeout()), config, metrics, logger)},
 		executor: commandExecutor,
 	}
 }
 
+// NewConnectJiraService creates a JiraService that authenticates as an installed Atlassian Connect app
+func NewConnectJiraService(config *models.Config, connectService JiraConnectService, metrics Metrics, logger *zap.Logger) JiraService {
+	return &JiraServiceImpl{
+		config:         config,
+		client:         &http.Client{Transport: newJiraThrottleTransport(newTimeoutTransport(http.DefaultTransport, config.HTTPTimeout()), config, metrics, logger)},
+		executor:       exec.CommandContext,
+		connectService: connectService,
+	}
+}
+
+// setAuthHeader authenticates a request with the API token, or with a Connect JWT when running as a Connect app
+func (s *JiraServiceImpl) setAuthHeader(req *http.Request) error {
+	if s.connectService != nil {
+		return s.connectService.SignRequest(req)
+	}
+	s.setTokenAuth(req, s.tokenType())
+	return nil
+}
+
 // GetTicket fetches a ticket from Jira
-func (s *JiraServiceImpl) GetTicket(key string) (*models.JiraTicketResponse, error) {
+func (s *JiraServiceImpl) GetTicket(ctx context.Context, key string) (*models.JiraTicketResponse, error) {
 	url := fmt.Sprintf("%s/rest/api/2/issue/%s", s.config.Jira.BaseURL, key)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.config.Jira.APIToken))
+	if err := s.setAuthHeader(req); err != nil {
+		return nil, fmt.Errorf("failed to authenticate request: %w", err)
+	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := s.client.Do(req)
@@ -94,15 +165,17 @@ func (s *JiraServiceImpl) GetTicket(key string) (*models.JiraTicketResponse, err
 }
 
 // GetTicketWithExpandedFields fetches a ticket from Jira with expanded fields for custom field access
-func (s *JiraServiceImpl) GetTicketWithExpandedFields(key string) (map[string]interface{}, map[string]string, error) {
+func (s *JiraServiceImpl) GetTicketWithExpandedFields(ctx context.Context, key string) (map[string]interface{}, map[string]string, error) {
 	url := fmt.Sprintf("%s/rest/api/2/issue/%s?expand=names", s.config.Jira.BaseURL, key)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.config.Jira.APIToken))
+	if err := s.setAuthHeader(req); err != nil {
+		return nil, nil, fmt.Errorf("failed to authenticate request: %w", err)
+	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := s.client.Do(req)
@@ -129,9 +202,9 @@ func (s *JiraServiceImpl) GetTicketWithExpandedFields(key string) (map[string]in
 }
 
 // UpdateTicketLabels updates the labels of a ticket
-func (s *JiraServiceImpl) UpdateTicketLabels(key string, addLabels, removeLabels []string) error {
+func (s *JiraServiceImpl) UpdateTicketLabels(ctx context.Context, key string, addLabels, removeLabels []string) error {
 	// First, get the current labels
-	ticket, err := s.GetTicket(key)
+	ticket, err := s.GetTicket(ctx, key)
 	if err != nil {
 		return fmt.Errorf("failed to get ticket: %w", err)
 	}
@@ -172,12 +245,14 @@ func (s *JiraServiceImpl) UpdateTicketLabels(key string, addLabels, removeLabels
 		return fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(jsonPayload))
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(jsonPayload))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.config.Jira.APIToken))
+	if err := s.setAuthHeader(req); err != nil {
+		return fmt.Errorf("failed to authenticate request: %w", err)
+	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := s.client.Do(req)
@@ -195,16 +270,18 @@ func (s *JiraServiceImpl) UpdateTicketLabels(key string, addLabels, removeLabels
 }
 
 // UpdateTicketStatus updates the status of a ticket
-func (s *JiraServiceImpl) UpdateTicketStatus(key string, status string) error {
-	// Get available transitions
+func (s *JiraServiceImpl) UpdateTicketStatus(ctx context.Context, key string, status string) error {
+	// Get available transitions, with the fields of their screens
 	url := fmt.Sprintf("%s/rest/api/2/issue/%s/transitions", s.config.Jira.BaseURL, key)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url+"?expand=transitions.fields", nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.config.Jira.APIToken))
+	if err := s.setAuthHeader(req); err != nil {
+		return fmt.Errorf("failed to authenticate request: %w", err)
+	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := s.client.Do(req)
@@ -225,6 +302,7 @@ func (s *JiraServiceImpl) UpdateTicketStatus(key string, status string) error {
 			To   struct {
 				Name string `json:"name"`
 			} `json:"to"`
+			Fields map[string]jiraTransitionField `json:"fields"`
 		} `json:"transitions"`
 	}
 
@@ -234,9 +312,11 @@ func (s *JiraServiceImpl) UpdateTicketStatus(key string, status string) error {
 
 	// Find the transition ID for the target status
 	var transitionID string
+	var screen map[string]jiraTransitionField
 	for _, transition := range transitions.Transitions {
 		if strings.EqualFold(transition.To.Name, status) {
 			transitionID = transition.ID
+			screen = transition.Fields
 			break
 		}
 	}
@@ -245,24 +325,35 @@ func (s *JiraServiceImpl) UpdateTicketStatus(key string, status string) error {
 		return fmt.Errorf("no transition found for status: %s", status)
 	}
 
+	// Set the resolution and screen fields configured for the status
+	fields, err := transitionFields(s.config, status, screen)
+	if err != nil {
+		return err
+	}
+
 	// Perform the transition
 	payload := map[string]interface{}{
 		"transition": map[string]string{
 			"id": transitionID,
 		},
 	}
+	if len(fields) > 0 {
+		payload["fields"] = fields
+	}
 
 	jsonPayload, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	req, err = http.NewRequest("POST", url, bytes.NewBuffer(jsonPayload))
+	req, err = http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonPayload))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.config.Jira.APIToken))
+	if err := s.setAuthHeader(req); err != nil {
+		return fmt.Errorf("failed to authenticate request: %w", err)
+	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err = s.client.Do(req)
@@ -273,6 +364,10 @@ func (s *JiraServiceImpl) UpdateTicketStatus(key string, status string) error {
 
 	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
+		if missing := missingRequiredFields(screen, fields); len(missing) > 0 {
+			return fmt.Errorf("failed to update ticket status: %s, status code: %d; the transition to %s requires %s, set them in jira.transition_fields",
+				string(body), resp.StatusCode, status, strings.Join(missing, ", "))
+		}
 		return fmt.Errorf("failed to update ticket status: %s, status code: %d", string(body), resp.StatusCode)
 	}
 
@@ -280,7 +375,7 @@ func (s *JiraServiceImpl) UpdateTicketStatus(key string, status string) error {
 }
 
 // AddComment adds a comment to a ticket
-func (s *JiraServiceImpl) AddComment(key string, comment string) error {
+func (s *JiraServiceImpl) AddComment(ctx context.Context, key string, comment string) error {
 	url := fmt.Sprintf("%s/rest/api/2/issue/%s/comment", s.config.Jira.BaseURL, key)
 
 	payload := map[string]string{
@@ -292,12 +387,14 @@ func (s *JiraServiceImpl) AddComment(key string, comment string) error {
 		return fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonPayload))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonPayload))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.config.Jira.APIToken))
+	if err := s.setAuthHeader(req); err != nil {
+		return fmt.Errorf("failed to authenticate request: %w", err)
+	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := s.client.Do(req)
@@ -314,8 +411,89 @@ func (s *JiraServiceImpl) AddComment(key string, comment string) error {
 	return nil
 }
 
+// GetComments fetches all comments of a ticket, following pagination
+func (s *JiraServiceImpl) GetComments(ctx context.Context, key string) ([]models.JiraComment, error) {
+	var comments []models.JiraComment
+	startAt := 0
+
+	for {
+		url := fmt.Sprintf("%s/rest/api/2/issue/%s/comment?startAt=%d&maxResults=100", s.config.Jira.BaseURL, key, startAt)
+
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		if err := s.setAuthHeader(req); err != nil {
+			return nil, fmt.Errorf("failed to authenticate request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to send request: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to get comments: %s, status code: %d", string(body), resp.StatusCode)
+		}
+
+		var page models.JiraComments
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		comments = append(comments, page.Comments...)
+		startAt += len(page.Comments)
+		if len(page.Comments) == 0 || startAt >= page.Total {
+			return comments, nil
+		}
+	}
+}
+
+// UpdateComment replaces the body of an existing comment
+func (s *JiraServiceImpl) UpdateComment(ctx context.Context, key, commentID, comment string) error {
+	url := fmt.Sprintf("%s/rest/api/2/issue/%s/comment/%s", s.config.Jira.BaseURL, key, commentID)
+
+	payload := map[string]string{
+		"body": comment,
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if err := s.setAuthHeader(req); err != nil {
+		return fmt.Errorf("failed to authenticate request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to update comment %s: %s, status code: %d", commentID, string(body), resp.StatusCode)
+	}
+
+	return nil
+}
+
 // UpdateTicketField updates a specific field of a ticket
-func (s *JiraServiceImpl) UpdateTicketField(key string, fieldID string, value interface{}) error {
+func (s *JiraServiceImpl) UpdateTicketField(ctx context.Context, key string, fieldID string, value interface{}) error {
 	url := fmt.Sprintf("%s/rest/api/2/issue/%s", s.config.Jira.BaseURL, key)
 
 	payload := map[string]interface{}{
@@ -329,12 +507,14 @@ func (s *JiraServiceImpl) UpdateTicketField(key string, fieldID string, value in
 		return fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(jsonPayload))
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(jsonPayload))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.config.Jira.APIToken))
+	if err := s.setAuthHeader(req); err != nil {
+		return fmt.Errorf("failed to authenticate request: %w", err)
+	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := s.client.Do(req)
@@ -352,24 +532,26 @@ func (s *JiraServiceImpl) UpdateTicketField(key string, fieldID string, value in
 }
 
 // UpdateTicketFieldByName updates a specific field of a ticket by field name
-func (s *JiraServiceImpl) UpdateTicketFieldByName(key string, fieldName string, value interface{}) error {
-	fieldID, err := s.GetFieldIDByName(fieldName)
+func (s *JiraServiceImpl) UpdateTicketFieldByName(ctx context.Context, key string, fieldName string, value interface{}) error {
+	fieldID, err := s.GetFieldIDByName(ctx, fieldName)
 	if err != nil {
 		return fmt.Errorf("failed to resolve field name '%s' to ID: %w", fieldName, err)
 	}
-	return s.UpdateTicketField(key, fieldID, value)
+	return s.UpdateTicketField(ctx, key, fieldID, value)
 }
 
 // GetFieldIDByName resolves a field name to its ID
-func (s *JiraServiceImpl) GetFieldIDByName(fieldName string) (string, error) {
+func (s *JiraServiceImpl) GetFieldIDByName(ctx context.Context, fieldName string) (string, error) {
 	url := fmt.Sprintf("%s/rest/api/2/field", s.config.Jira.BaseURL)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.config.Jira.APIToken))
+	if err := s.setAuthHeader(req); err != nil {
+		return "", fmt.Errorf("failed to authenticate request: %w", err)
+	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := s.client.Do(req)
@@ -399,18 +581,18 @@ func (s *JiraServiceImpl) GetFieldIDByName(fieldName string) (string, error) {
 		}
 	}
 
-	return "", fmt.Errorf("field with name '%s' not found", fieldName)
+	return "", fmt.Errorf("%w: no field with name '%s'", ErrJiraFieldNotFound, fieldName)
 }
 
 // SearchTickets searches for tickets using JQL
-func (s *JiraServiceImpl) SearchTickets(jql string) (*models.JiraSearchResponse, error) {
+func (s *JiraServiceImpl) SearchTickets(ctx context.Context, jql string) (*models.JiraSearchResponse, error) {
 	url := fmt.Sprintf("%s/rest/api/2/search", s.config.Jira.BaseURL)
 
 	payload := map[string]interface{}{
 		"jql":        jql,
 		"startAt":    0,
 		"maxResults": 100,
-		"fields":     []string{"summary", "description", "status", "project", "components", "labels", "created", "updated", "creator", "reporter"},
+		"fields":     []string{"summary", "description", "status", "project", "components", "labels", "created", "updated", "creator", "reporter", "parent", "issuetype"},
 	}
 
 	jsonPayload, err := json.Marshal(payload)
@@ -418,12 +600,14 @@ func (s *JiraServiceImpl) SearchTickets(jql string) (*models.JiraSearchResponse,
 		return nil, fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonPayload))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonPayload))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.config.Jira.APIToken))
+	if err := s.setAuthHeader(req); err != nil {
+		return nil, fmt.Errorf("failed to authenticate request: %w", err)
+	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := s.client.Do(req)
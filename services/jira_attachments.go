@@ -0,0 +1,52 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+// AddAttachment attaches a file to a ticket
+func (s *JiraServiceImpl) AddAttachment(ctx context.Context, key, filename string, content []byte) error {
+	url := fmt.Sprintf("%s/rest/api/2/issue/%s/attachments", s.config.Jira.BaseURL, key)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := part.Write(content); err != nil {
+		return fmt.Errorf("failed to write form file: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to close form: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, &body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if err := s.setAuthHeader(req); err != nil {
+		return fmt.Errorf("failed to authenticate request: %w", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	// Jira rejects attachments without it as cross-site requests
+	req.Header.Set("X-Atlassian-Token", "no-check")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to add attachment: %s, status code: %d", string(respBody), resp.StatusCode)
+	}
+	return nil
+}
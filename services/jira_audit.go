@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// auditedJiraService is a JiraService that records every change it makes in Jira in the audit log
+type auditedJiraService struct {
+	JiraService
+
+	auditLog AuditLog
+	actor    string
+	logger   *zap.Logger
+}
+
+// NewAuditedJiraService creates a JiraService recording the changes the given service makes in the audit log
+func NewAuditedJiraService(jiraService JiraService, config *models.Config, logger *zap.Logger) JiraService {
+	actor := config.Jira.Username
+	if config.Jira.AuthMode == models.JiraAuthModeConnect {
+		actor = "connect:" + config.Jira.Connect.AppKey
+	}
+	return &auditedJiraService{
+		JiraService: jiraService,
+		auditLog:    NewAuditLog(config.AuditLog),
+		actor:       actor,
+		logger:      logger,
+	}
+}
+
+// record records a change of a ticket that was attempted
+func (s *auditedJiraService) record(ticketKey, action, payload string, started time.Time, err error) {
+	recordExternalAction(s.auditLog, s.logger, models.AuditEntry{
+		Ticket:  ticketKey,
+		Action:  action,
+		Actor:   s.actor,
+		Target:  ticketKey,
+		Payload: payload,
+	}, started, err)
+}
+
+// UpdateTicketLabels updates the labels of a ticket
+func (s *auditedJiraService) UpdateTicketLabels(ctx context.Context, key string, addLabels, removeLabels []string) error {
+	started := time.Now()
+	err := s.JiraService.UpdateTicketLabels(ctx, key, addLabels, removeLabels)
+	s.record(key, "jira_labels", fmt.Sprintf("add=%s remove=%s", strings.Join(addLabels, ","), strings.Join(removeLabels, ",")), started, err)
+	return err
+}
+
+// UpdateTicketStatus updates the status of a ticket
+func (s *auditedJiraService) UpdateTicketStatus(ctx context.Context, key string, status string) error {
+	started := time.Now()
+	err := s.JiraService.UpdateTicketStatus(ctx, key, status)
+	s.record(key, "jira_transition", "status="+status, started, err)
+	return err
+}
+
+// UpdateTicketField updates a specific field of a ticket
+func (s *auditedJiraService) UpdateTicketField(ctx context.Context, key string, fieldID string, value interface{}) error {
+	started := time.Now()
+	err := s.JiraService.UpdateTicketField(ctx, key, fieldID, value)
+	s.record(key, "jira_field", fmt.Sprintf("%s=%v", fieldID, value), started, err)
+	return err
+}
+
+// UpdateTicketFieldByName updates a specific field of a ticket by field name
+func (s *auditedJiraService) UpdateTicketFieldByName(ctx context.Context, key string, fieldName string, value interface{}) error {
+	started := time.Now()
+	err := s.JiraService.UpdateTicketFieldByName(ctx, key, fieldName, value)
+	s.record(key, "jira_field", fmt.Sprintf("%s=%v", fieldName, value), started, err)
+	return err
+}
+
+// AddComment adds a comment to a ticket
+func (s *auditedJiraService) AddComment(ctx context.Context, key string, comment string) error {
+	started := time.Now()
+	err := s.JiraService.AddComment(ctx, key, comment)
+	s.record(key, "jira_comment", comment, started, err)
+	return err
+}
+
+// UpdateComment replaces the body of an existing comment
+func (s *auditedJiraService) UpdateComment(ctx context.Context, key, commentID, comment string) error {
+	started := time.Now()
+	err := s.JiraService.UpdateComment(ctx, key, commentID, comment)
+	s.record(key, "jira_comment_edit", fmt.Sprintf("comment %s: %s", commentID, comment), started, err)
+	return err
+}
+
+// CreateTicket creates a Jira issue and returns its key
+func (s *auditedJiraService) CreateTicket(ctx context.Context, fields models.JiraCreateIssueFields) (string, error) {
+	started := time.Now()
+	key, err := s.JiraService.CreateTicket(ctx, fields)
+	recordExternalAction(s.auditLog, s.logger, models.AuditEntry{
+		Ticket:  auditTicket(ctx),
+		Action:  "jira_create_issue",
+		Actor:   s.actor,
+		Target:  key,
+		Payload: fmt.Sprintf("project=%s type=%s summary=%s", fields.Project.Key, fields.IssueType.Name, fields.Summary),
+	}, started, err)
+	return key, err
+}
+
+// LinkTickets links two Jira issues with the given link type, e.g. "Relates"
+func (s *auditedJiraService) LinkTickets(ctx context.Context, linkType, inwardKey, outwardKey string) error {
+	started := time.Now()
+	err := s.JiraService.LinkTickets(ctx, linkType, inwardKey, outwardKey)
+	s.record(inwardKey, "jira_link", fmt.Sprintf("%s %s", linkType, outwardKey), started, err)
+	return err
+}
+
+// AddRemoteLink links a ticket to an object outside Jira
+func (s *auditedJiraService) AddRemoteLink(ctx context.Context, key string, link models.JiraRemoteLink) error {
+	started := time.Now()
+	err := s.JiraService.AddRemoteLink(ctx, key, link)
+	s.record(key, "jira_remote_link", link.Object.URL, started, err)
+	return err
+}
+
+// AddWorklog logs time spent on a ticket
+func (s *auditedJiraService) AddWorklog(ctx context.Context, key string, worklog models.JiraWorklog) error {
+	started := time.Now()
+	err := s.JiraService.AddWorklog(ctx, key, worklog)
+	s.record(key, "jira_worklog", fmt.Sprintf("%ds", worklog.TimeSpentSeconds), started, err)
+	return err
+}
+
+// CreateCustomField creates a global custom field of the given type and returns its ID
+func (s *auditedJiraService) CreateCustomField(ctx context.Context, name, fieldType string) (string, error) {
+	started := time.Now()
+	fieldID, err := s.JiraService.CreateCustomField(ctx, name, fieldType)
+	recordExternalAction(s.auditLog, s.logger, models.AuditEntry{
+		Action:  "jira_create_field",
+		Actor:   s.actor,
+		Target:  fieldID,
+		Payload: fmt.Sprintf("name=%s type=%s", name, fieldType),
+	}, started, err)
+	return fieldID, err
+}
+
+// AddAttachment attaches a file to a ticket
+func (s *auditedJiraService) AddAttachment(ctx context.Context, key, filename string, content []byte) error {
+	started := time.Now()
+	err := s.JiraService.AddAttachment(ctx, key, filename, content)
+	s.record(key, "jira_attachment", fmt.Sprintf("file=%s size=%d", filename, len(content)), started, err)
+	return err
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// readAuditEntries returns the entries of an audit log file
+func readAuditEntries(t *testing.T, path string) []models.AuditEntry {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read audit log: %v", err)
+	}
+	var entries []models.AuditEntry
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var entry models.AuditEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Failed to decode audit entry %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestAuditedJiraService(t *testing.T) {
+	config := &models.Config{AuditLog: filepath.Join(t.TempDir(), "audit.log")}
+	config.Jira.Username = "ai-bot@example.com"
+	jiraService := &mocks.MockJiraService{
+		UpdateTicketStatusFunc: func(key, status string) error {
+			return errors.New("transition not allowed")
+		},
+	}
+	audited := NewAuditedJiraService(jiraService, config, zap.NewNop())
+
+	ctx := context.Background()
+	if err := audited.AddComment(ctx, "TEST-1", "AI is working on this ticket"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if err := audited.UpdateTicketStatus(ctx, "TEST-1", "In Review"); err == nil {
+		t.Fatal("Expected the error of the wrapped service")
+	}
+	if err := audited.UpdateTicketLabels(ctx, "TEST-1", []string{"ai-pr"}, nil); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if _, err := audited.GetTicket(ctx, "TEST-1"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	entries := readAuditEntries(t, config.AuditLog)
+	if len(entries) != 3 {
+		t.Fatalf("Expected an entry per change and none for reads, got %+v", entries)
+	}
+	comment, transition, labels := entries[0], entries[1], entries[2]
+	if comment.Action != "jira_comment" || comment.Ticket != "TEST-1" || comment.Actor != "ai-bot@example.com" || comment.Payload != "AI is working on this ticket" {
+		t.Errorf("Unexpected comment entry: %+v", comment)
+	}
+	if transition.Action != "jira_transition" || transition.Payload != "status=In Review" || transition.Error != "transition not allowed" {
+		t.Errorf("Expected the failed transition to be recorded with its error, got %+v", transition)
+	}
+	if labels.Action != "jira_labels" || labels.Payload != "add=ai-pr remove=" {
+		t.Errorf("Unexpected labels entry: %+v", labels)
+	}
+}
+
+func TestAuditedJiraService_TruncatesPayload(t *testing.T) {
+	config := &models.Config{AuditLog: filepath.Join(t.TempDir(), "audit.log")}
+	audited := NewAuditedJiraService(&mocks.MockJiraService{}, config, zap.NewNop())
+
+	if err := audited.AddComment(context.Background(), "TEST-1", strings.Repeat("x", 2*maxAuditPayload)); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	entries := readAuditEntries(t, config.AuditLog)
+	if len(entries) != 1 || len(entries[0].Payload) != maxAuditPayload+len("...") {
+		t.Errorf("Expected the payload to be truncated, got %d bytes", len(entries[0].Payload))
+	}
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+)
+
+// ErrJiraAuthRejected is returned when Jira rejects the API token with every token type tried
+var ErrJiraAuthRejected = errors.New("jira rejected the API token")
+
+// DetectTokenType checks the API token against Jira and returns the token type Jira accepts it with, which is used
+// for the following requests. With the auto token type, the type the base URL suggests is tried first: basic for
+// Jira Cloud sites, bearer for Server and Data Center. Connect apps sign their requests, so there is nothing to detect.
+func (s *JiraServiceImpl) DetectTokenType(ctx context.Context) (models.JiraTokenType, error) {
+	if s.connectService != nil {
+		return "", nil
+	}
+
+	var rejections []string
+	for _, tokenType := range tokenTypeCandidates(s.config) {
+		status, err := s.probeTokenType(ctx, tokenType)
+		if err != nil {
+			return "", err
+		}
+		if status == http.StatusOK {
+			s.detectedTokenType.Store(tokenType)
+			return tokenType, nil
+		}
+		if status != http.StatusUnauthorized && status != http.StatusForbidden {
+			return "", fmt.Errorf("failed to check the API token: status code: %d", status)
+		}
+		rejections = append(rejections, fmt.Sprintf("as a %s token (status code: %d)", tokenType, status))
+	}
+	return "", fmt.Errorf("%w %s. Jira Cloud needs jira.username to be the account's email address and an API token; "+
+		"Jira Server and Data Center need a personal access token", ErrJiraAuthRejected, strings.Join(rejections, " and "))
+}
+
+// tokenTypeCandidates returns the token types to try the API token with, in order
+func tokenTypeCandidates(config *models.Config) []models.JiraTokenType {
+	switch config.Jira.TokenType {
+	case models.JiraTokenTypeBearer, models.JiraTokenTypeBasic:
+		return []models.JiraTokenType{config.Jira.TokenType}
+	}
+	if isJiraCloud(config.Jira.BaseURL) {
+		return []models.JiraTokenType{models.JiraTokenTypeBasic, models.JiraTokenTypeBearer}
+	}
+	return []models.JiraTokenType{models.JiraTokenTypeBearer, models.JiraTokenTypeBasic}
+}
+
+// isJiraCloud reports whether the base URL is of a Jira Cloud site
+func isJiraCloud(baseURL string) bool {
+	parsed, err := url.Parse(baseURL)
+	return err == nil && strings.HasSuffix(strings.ToLower(parsed.Hostname()), ".atlassian.net")
+}
+
+// tokenType returns the token type requests are authenticated with: the configured one, the one detected at startup,
+// or the one the base URL suggests before the detection
+func (s *JiraServiceImpl) tokenType() models.JiraTokenType {
+	if detected, ok := s.detectedTokenType.Load().(models.JiraTokenType); ok && s.config.Jira.TokenType == models.JiraTokenTypeAuto {
+		return detected
+	}
+	return tokenTypeCandidates(s.config)[0]
+}
+
+// setTokenAuth authenticates a request with the API token of the given type
+func (s *JiraServiceImpl) setTokenAuth(req *http.Request, tokenType models.JiraTokenType) {
+	if tokenType == models.JiraTokenTypeBasic {
+		req.SetBasicAuth(s.config.Jira.Username, s.config.Jira.APIToken)
+		return
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.config.Jira.APIToken))
+}
+
+// probeTokenType fetches the current user with the API token of the given type, returning the response's status code
+func (s *JiraServiceImpl) probeTokenType(ctx context.Context, tokenType models.JiraTokenType) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/rest/api/2/myself", s.config.Jira.BaseURL), nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	s.setTokenAuth(req, tokenType)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+)
+
+func TestDetectTokenType(t *testing.T) {
+	tests := []struct {
+		name          string
+		baseURL       string
+		tokenType     models.JiraTokenType
+		accepted      models.JiraTokenType // Token type Jira accepts; none rejects every type
+		wantTokenType models.JiraTokenType
+		wantTries     int
+		wantRejected  bool
+	}{
+		{name: "data center PAT", baseURL: "https://jira.example.com", tokenType: models.JiraTokenTypeAuto, accepted: models.JiraTokenTypeBearer, wantTokenType: models.JiraTokenTypeBearer, wantTries: 1},
+		{name: "cloud API token", baseURL: "https://example.atlassian.net", tokenType: models.JiraTokenTypeAuto, accepted: models.JiraTokenTypeBasic, wantTokenType: models.JiraTokenTypeBasic, wantTries: 1},
+		{name: "cloud token on a custom domain", baseURL: "https://jira.example.com", tokenType: models.JiraTokenTypeAuto, accepted: models.JiraTokenTypeBasic, wantTokenType: models.JiraTokenTypeBasic, wantTries: 2},
+		{name: "rejected token", baseURL: "https://jira.example.com", tokenType: models.JiraTokenTypeAuto, wantTries: 2, wantRejected: true},
+		{name: "configured token type only", baseURL: "https://jira.example.com", tokenType: models.JiraTokenTypeBearer, accepted: models.JiraTokenTypeBasic, wantTries: 1, wantRejected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tries := 0
+			var lastAuth string
+			mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+				tries++
+				lastAuth = req.Header.Get("Authorization")
+				_, _, basic := req.BasicAuth()
+				status := http.StatusUnauthorized
+				if (basic && tt.accepted == models.JiraTokenTypeBasic) || (!basic && tt.accepted == models.JiraTokenTypeBearer) {
+					status = http.StatusOK
+				}
+				return &http.Response{StatusCode: status, Body: io.NopCloser(bytes.NewBufferString(`{}`))}, nil
+			})
+			config := &models.Config{}
+			config.Jira.BaseURL = tt.baseURL
+			config.Jira.Username = "bot@example.com"
+			config.Jira.APIToken = "secret"
+			config.Jira.TokenType = tt.tokenType
+			service := &JiraServiceImpl{config: config, client: mockClient, executor: execCommand}
+
+			tokenType, err := service.DetectTokenType(context.Background())
+			if errors.Is(err, ErrJiraAuthRejected) != tt.wantRejected {
+				t.Fatalf("Expected rejection %v, got: %v", tt.wantRejected, err)
+			}
+			if tokenType != tt.wantTokenType || tries != tt.wantTries {
+				t.Errorf("Expected %q after %d tries, got %q after %d", tt.wantTokenType, tt.wantTries, tokenType, tries)
+			}
+			if tt.wantRejected {
+				return
+			}
+
+			// Later requests use the detected token type
+			if _, err := service.GetCurrentUser(context.Background()); err != nil {
+				t.Fatalf("Expected the detected token type to be accepted, got: %v", err)
+			}
+			if tries != tt.wantTries+1 || (tokenType == models.JiraTokenTypeBearer) != (lastAuth == "Bearer secret") {
+				t.Errorf("Expected a single request with the %s token type, got %q", tokenType, lastAuth)
+			}
+		})
+	}
+}
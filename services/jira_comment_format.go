@@ -0,0 +1,73 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+)
+
+// Panel styles of rich comments, in the colors of Jira's own success and error messages
+const (
+	successPanelStyle = "borderColor=#36B37E|titleBGColor=#E3FCEF|bgColor=#F3FCF7"
+	errorPanelStyle   = "borderColor=#DE350B|titleBGColor=#FFEBE6|bgColor=#FFF7F5"
+)
+
+// Limits of the text shown in rich comments, so long AI summaries and command outputs don't bury the ticket
+const (
+	commentDetailMaxLines = 30
+	commentDetailMaxChars = 3000
+)
+
+// successComment returns a comment reporting a success: a panel with the message, followed by the AI's summary of
+// its work in a collapsed section. Without rich comments, only the message is returned.
+func successComment(config *models.Config, title, message, aiSummary string) string {
+	if !config.Jira.RichComments {
+		return message
+	}
+	comment := wikiPanel(title, successPanelStyle, message)
+	if summary := truncateCommentDetail(RedactSecrets(config, aiSummary)); summary != "" {
+		comment += "\n" + fmt.Sprintf("{expand:AI summary}\n%s\n{expand}", wikiNoformat(summary))
+	}
+	return comment
+}
+
+// failureComment returns a comment reporting a failure: an error panel with the title and the truncated error
+// message, kept preformatted since it often holds command output. Without rich comments, the title is followed by the
+// full message.
+func failureComment(config *models.Config, title, message string) string {
+	if !config.Jira.RichComments {
+		return fmt.Sprintf("%s: %s", title, message)
+	}
+	return wikiPanel(title, errorPanelStyle, wikiNoformat(truncateCommentDetail(message)))
+}
+
+// wikiPanel returns a wiki markup panel with the title and body
+func wikiPanel(title, style, body string) string {
+	title = strings.NewReplacer("|", "/", "}", ")", "{", "(").Replace(title)
+	return fmt.Sprintf("{panel:title=%s|%s}\n%s\n{panel}", title, style, body)
+}
+
+// wikiNoformat returns the text as a preformatted block, in which Jira renders no markup. The text can't end the
+// block early.
+func wikiNoformat(text string) string {
+	return fmt.Sprintf("{noformat}\n%s\n{noformat}", strings.ReplaceAll(text, "{noformat}", "(noformat)"))
+}
+
+// truncateCommentDetail trims the text to the first lines and characters shown in comments, noting what was cut
+func truncateCommentDetail(text string) string {
+	text = strings.TrimSpace(text)
+	truncated := false
+	if lines := strings.Split(text, "\n"); len(lines) > commentDetailMaxLines {
+		text = strings.Join(lines[:commentDetailMaxLines], "\n")
+		truncated = true
+	}
+	if runes := []rune(text); len(runes) > commentDetailMaxChars {
+		text = string(runes[:commentDetailMaxChars])
+		truncated = true
+	}
+	if truncated {
+		text += "\n… (truncated)"
+	}
+	return text
+}
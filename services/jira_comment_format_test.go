@@ -0,0 +1,49 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+)
+
+func TestSuccessComment(t *testing.T) {
+	config := &models.Config{}
+	message := "AI-generated pull request created: https://github.com/example/repo/pull/1"
+	if got := successComment(config, "Pull request opened", message, "Fixed the bug"); got != message {
+		t.Errorf("Expected the plain message without rich comments, got %q", got)
+	}
+
+	config.Jira.RichComments = true
+	want := "{panel:title=Pull request opened|" + successPanelStyle + "}\n" + message + "\n{panel}\n" +
+		"{expand:AI summary}\n{noformat}\nFixed the bug in (noformat) parsing\n{noformat}\n{expand}"
+	if got := successComment(config, "Pull request opened", message, "Fixed the bug in {noformat} parsing\n"); got != want {
+		t.Errorf("successComment() = %q, want %q", got, want)
+	}
+	if got := successComment(config, "Pull request opened", message, ""); strings.Contains(got, "{expand") {
+		t.Errorf("Expected no summary section without a summary, got %q", got)
+	}
+}
+
+func TestFailureComment(t *testing.T) {
+	config := &models.Config{}
+	if got := failureComment(config, "AI failed to process this ticket", "tests failed"); got != "AI failed to process this ticket: tests failed" {
+		t.Errorf("Expected the plain message without rich comments, got %q", got)
+	}
+
+	config.Jira.RichComments = true
+	lines := make([]string, 50)
+	for i := range lines {
+		lines[i] = "--- FAIL: TestParser"
+	}
+	got := failureComment(config, "AI failed to process this ticket", strings.Join(lines, "\n"))
+	if !strings.HasPrefix(got, "{panel:title=AI failed to process this ticket|"+errorPanelStyle+"}\n{noformat}\n") {
+		t.Errorf("Expected an error panel, got %q", got)
+	}
+	if n := strings.Count(got, "--- FAIL"); n != commentDetailMaxLines {
+		t.Errorf("Expected the error to be truncated to %d lines, got %d", commentDetailMaxLines, n)
+	}
+	if !strings.Contains(got, "… (truncated)\n{noformat}\n{panel}") {
+		t.Errorf("Expected the truncation to be noted, got %q", got)
+	}
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Idempotency keys of the comments the solver posts on tickets. Each key identifies one logical
+// message, which appears at most once per ticket.
+const (
+	commentKeyPRCreated = "pr-created"
+	commentKeyFailure   = "failure"
+	commentKeyMerged    = "merged"
+	commentKeyFollowUps = "follow-ups"
+)
+
+// jiraCommentMarker returns the hidden marker tagging a solver comment with its idempotency key.
+// Jira renders the anchor macro invisibly, but keeps it in the comment body returned by the API.
+func jiraCommentMarker(idempotencyKey string) string {
+	return fmt.Sprintf("{anchor:ai-solver-%s}", idempotencyKey)
+}
+
+// upsertJiraComment posts a comment tagged with an idempotency key, or updates the ticket's existing
+// comment with that key in place, so retries don't post the same message again
+func upsertJiraComment(ctx context.Context, jiraService JiraService, logger *zap.Logger, ticketKey, idempotencyKey, comment string) error {
+	marker := jiraCommentMarker(idempotencyKey)
+	body := marker + comment
+	if strings.HasPrefix(comment, "{panel") {
+		// Block macros only render at the start of a line
+		body = marker + "\n" + comment
+	}
+
+	comments, err := jiraService.GetComments(ctx, ticketKey)
+	if err != nil {
+		// Better a duplicate than a lost message
+		logger.Warn("Failed to check for an existing comment, posting a new one",
+			zap.String("ticket", ticketKey),
+			zap.String("idempotency_key", idempotencyKey),
+			zap.Error(err))
+		return jiraService.AddComment(ctx, ticketKey, body)
+	}
+
+	for _, existing := range comments {
+		if !strings.Contains(existing.Body, marker) {
+			continue
+		}
+		if existing.Body == body {
+			logger.Debug("Comment already posted",
+				zap.String("ticket", ticketKey),
+				zap.String("idempotency_key", idempotencyKey))
+			return nil
+		}
+		return jiraService.UpdateComment(ctx, ticketKey, existing.ID, body)
+	}
+
+	return jiraService.AddComment(ctx, ticketKey, body)
+}
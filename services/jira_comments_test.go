@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestUpsertJiraComment(t *testing.T) {
+	marker := jiraCommentMarker(commentKeyPRCreated)
+
+	tests := []struct {
+		name        string
+		existing    []models.JiraComment
+		getErr      error
+		wantAdded   bool
+		wantUpdated string
+	}{
+		{
+			name:      "no existing comment",
+			existing:  []models.JiraComment{{ID: "1", Body: "Looks good"}},
+			wantAdded: true,
+		},
+		{
+			name:     "identical comment already posted",
+			existing: []models.JiraComment{{ID: "1", Body: marker + "PR created: https://github.com/o/r/pull/1"}},
+		},
+		{
+			name:        "outdated comment is updated in place",
+			existing:    []models.JiraComment{{ID: "7", Body: marker + "PR created: https://github.com/o/r/pull/0"}},
+			wantUpdated: "7",
+		},
+		{
+			name:      "comments can't be listed",
+			getErr:    errors.New("boom"),
+			wantAdded: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var added, updatedID string
+			jiraService := &mocks.MockJiraService{
+				GetCommentsFunc: func(key string) ([]models.JiraComment, error) {
+					return tt.existing, tt.getErr
+				},
+				AddCommentFunc: func(key string, comment string) error {
+					added = comment
+					return nil
+				},
+				UpdateCommentFunc: func(key, commentID, comment string) error {
+					updatedID = commentID
+					return nil
+				},
+			}
+
+			err := upsertJiraComment(context.Background(), jiraService, zap.NewNop(), "TEST-1", commentKeyPRCreated, "PR created: https://github.com/o/r/pull/1")
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			if tt.wantAdded != (added != "") {
+				t.Errorf("Expected comment added = %v, got '%s'", tt.wantAdded, added)
+			}
+			if added != "" && !strings.HasPrefix(added, marker) {
+				t.Errorf("Expected new comment to carry the marker, got '%s'", added)
+			}
+			if updatedID != tt.wantUpdated {
+				t.Errorf("Expected updated comment '%s', got '%s'", tt.wantUpdated, updatedID)
+			}
+		})
+	}
+}
+
+// TestUpsertJiraComment_Panel puts panels on their own line after the marker, where Jira renders them
+func TestUpsertJiraComment_Panel(t *testing.T) {
+	var added string
+	jiraService := &mocks.MockJiraService{
+		AddCommentFunc: func(key string, comment string) error {
+			added = comment
+			return nil
+		},
+	}
+	comment := "{panel:title=Pull request opened}\nhttps://github.com/o/r/pull/1\n{panel}"
+
+	if err := upsertJiraComment(context.Background(), jiraService, zap.NewNop(), "TEST-1", commentKeyPRCreated, comment); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if want := jiraCommentMarker(commentKeyPRCreated) + "\n" + comment; added != want {
+		t.Errorf("Expected %q, got %q", want, added)
+	}
+}
@@ -0,0 +1,512 @@
+package services
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// Paths of the endpoints Jira calls on a Connect app, relative to jira.connect.base_url
+const (
+	JiraConnectDescriptorPath  = "/atlassian-connect.json"
+	JiraConnectInstalledPath   = "/jira/installed"
+	JiraConnectUninstalledPath = "/jira/uninstalled"
+	JiraConnectWebhookPath     = "/jira/webhook"
+)
+
+// connectInstallKeysURL serves the public keys Atlassian signs install lifecycle callbacks with
+const connectInstallKeysURL = "https://connect-install-keys.atlassian.com"
+
+// connectJWTLifetime is how long a JWT for an outgoing Jira request is valid
+const connectJWTLifetime = 3 * time.Minute
+
+// connectClockSkew is the leeway allowed when checking the expiry of incoming JWTs
+const connectClockSkew = 30 * time.Second
+
+// JiraConnectService defines the interface for operating as an Atlassian Connect app
+type JiraConnectService interface {
+	// Descriptor returns the app descriptor Jira reads when the app is installed
+	Descriptor() map[string]interface{}
+
+	// HandleInstalled verifies an installed lifecycle callback and stores the installation
+	HandleInstalled(r *http.Request, payload *models.JiraConnectLifecycle) error
+
+	// HandleUninstalled verifies an uninstalled lifecycle callback and removes the installation
+	HandleUninstalled(r *http.Request, payload *models.JiraConnectLifecycle) error
+
+	// VerifyRequest verifies the JWT of a request sent by an installed Jira site, e.g. a webhook
+	VerifyRequest(r *http.Request) (*models.JiraConnectInstallation, error)
+
+	// SignRequest adds a JWT authorization header to an outgoing Jira API request
+	SignRequest(req *http.Request) error
+}
+
+// JiraConnectServiceImpl implements the JiraConnectService interface
+type JiraConnectServiceImpl struct {
+	config         *models.Config
+	client         *http.Client
+	logger         *zap.Logger
+	installKeysURL string
+	now            func() time.Time
+
+	mu            sync.RWMutex
+	installations map[string]*models.JiraConnectInstallation
+}
+
+// connectClaims are the JWT claims used by Atlassian Connect
+type connectClaims struct {
+	Iss string          `json:"iss"`
+	Iat int64           `json:"iat"`
+	Exp int64           `json:"exp"`
+	Qsh string          `json:"qsh"`
+	Aud json.RawMessage `json:"aud,omitempty"`
+}
+
+// connectJWTHeader is the header of a Connect JWT
+type connectJWTHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid,omitempty"`
+	Typ string `json:"typ,omitempty"`
+}
+
+// NewJiraConnectService creates a new JiraConnectService and loads the stored installations
+func NewJiraConnectService(config *models.Config, logger *zap.Logger) (JiraConnectService, error) {
+	service := &JiraConnectServiceImpl{
+		config:         config,
+		client:         &http.Client{Timeout: 10 * time.Second},
+		logger:         logger,
+		installKeysURL: connectInstallKeysURL,
+		now:            time.Now,
+		installations:  make(map[string]*models.JiraConnectInstallation),
+	}
+
+	data, err := os.ReadFile(config.Jira.Connect.InstallationsFile)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read Jira Connect installations: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &service.installations); err != nil {
+			return nil, fmt.Errorf("failed to parse Jira Connect installations: %w", err)
+		}
+	}
+
+	return service, nil
+}
+
+// Descriptor returns the app descriptor Jira reads when the app is installed
+func (s *JiraConnectServiceImpl) Descriptor() map[string]interface{} {
+	connect := s.config.Jira.Connect
+	return map[string]interface{}{
+		"key":         connect.AppKey,
+		"name":        connect.AppName,
+		"description": "Resolves Jira tickets with AI generated pull requests",
+		"baseUrl":     strings.TrimSuffix(connect.BaseURL, "/"),
+		"authentication": map[string]string{
+			"type": "jwt",
+		},
+		"apiMigrations": map[string]bool{
+			"signed-install": true,
+		},
+		"lifecycle": map[string]string{
+			"installed":   JiraConnectInstalledPath,
+			"uninstalled": JiraConnectUninstalledPath,
+		},
+		"scopes": connect.Scopes,
+		"modules": map[string]interface{}{
+			"webhooks": []map[string]string{
+				{"event": "jira:issue_created", "url": JiraConnectWebhookPath},
+				{"event": "jira:issue_updated", "url": JiraConnectWebhookPath},
+			},
+		},
+	}
+}
+
+// HandleInstalled verifies an installed lifecycle callback and stores the installation
+func (s *JiraConnectServiceImpl) HandleInstalled(r *http.Request, payload *models.JiraConnectLifecycle) error {
+	if payload.Key != s.config.Jira.Connect.AppKey {
+		return fmt.Errorf("installation is for app %s, expected %s", payload.Key, s.config.Jira.Connect.AppKey)
+	}
+	if payload.ClientKey == "" || payload.SharedSecret == "" {
+		return errors.New("installation payload is missing the client key or shared secret")
+	}
+	if err := s.verifyLifecycleRequest(r, payload.ClientKey); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.installations[payload.ClientKey] = &models.JiraConnectInstallation{
+		ClientKey:    payload.ClientKey,
+		SharedSecret: payload.SharedSecret,
+		BaseURL:      strings.TrimSuffix(payload.BaseURL, "/"),
+		InstalledAt:  s.now(),
+	}
+	if err := s.saveInstallations(); err != nil {
+		return err
+	}
+
+	s.logger.Info("Jira Connect app installed", zap.String("client_key", payload.ClientKey), zap.String("base_url", payload.BaseURL))
+	return nil
+}
+
+// HandleUninstalled verifies an uninstalled lifecycle callback and removes the installation
+func (s *JiraConnectServiceImpl) HandleUninstalled(r *http.Request, payload *models.JiraConnectLifecycle) error {
+	if err := s.verifyLifecycleRequest(r, payload.ClientKey); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.installations, payload.ClientKey)
+	if err := s.saveInstallations(); err != nil {
+		return err
+	}
+
+	s.logger.Info("Jira Connect app uninstalled", zap.String("client_key", payload.ClientKey))
+	return nil
+}
+
+// VerifyRequest verifies the JWT of a request sent by an installed Jira site
+func (s *JiraConnectServiceImpl) VerifyRequest(r *http.Request) (*models.JiraConnectInstallation, error) {
+	token, err := connectTokenFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	header, claims, signingInput, signature, err := parseConnectJWT(token)
+	if err != nil {
+		return nil, err
+	}
+	if header.Alg != "HS256" {
+		return nil, fmt.Errorf("unsupported JWT algorithm: %s", header.Alg)
+	}
+
+	installation := s.getInstallation(claims.Iss)
+	if installation == nil {
+		return nil, fmt.Errorf("no installation found for client key %s", claims.Iss)
+	}
+
+	if !hmac.Equal(signature, signHS256(signingInput, installation.SharedSecret)) {
+		return nil, errors.New("invalid JWT signature")
+	}
+	if err := s.verifyClaims(claims, r); err != nil {
+		return nil, err
+	}
+
+	return installation, nil
+}
+
+// SignRequest adds a JWT authorization header to an outgoing Jira API request
+func (s *JiraConnectServiceImpl) SignRequest(req *http.Request) error {
+	installation := s.installationForBaseURL(s.config.Jira.BaseURL)
+	if installation == nil {
+		return fmt.Errorf("the Jira Connect app is not installed on %s", s.config.Jira.BaseURL)
+	}
+
+	jiraBaseURL, err := url.Parse(installation.BaseURL)
+	if err != nil {
+		return fmt.Errorf("invalid Jira base URL: %w", err)
+	}
+
+	now := s.now()
+	claims := connectClaims{
+		Iss: s.config.Jira.Connect.AppKey,
+		Iat: now.Unix(),
+		Exp: now.Add(connectJWTLifetime).Unix(),
+		Qsh: connectQSH(req.Method, req.URL, jiraBaseURL.Path),
+	}
+
+	token, err := createHS256JWT(claims, installation.SharedSecret)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "JWT "+token)
+	return nil
+}
+
+// verifyLifecycleRequest verifies the JWT of an installed/uninstalled callback.
+// Signed installs use RS256 with a key published by Atlassian; older sites sign with the
+// shared secret of the existing installation, which only works for re-installs.
+func (s *JiraConnectServiceImpl) verifyLifecycleRequest(r *http.Request, clientKey string) error {
+	token, err := connectTokenFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	header, claims, signingInput, signature, err := parseConnectJWT(token)
+	if err != nil {
+		return err
+	}
+	if claims.Iss != clientKey {
+		return fmt.Errorf("JWT issuer %s does not match client key %s", claims.Iss, clientKey)
+	}
+
+	switch header.Alg {
+	case "RS256":
+		publicKey, err := s.fetchInstallKey(header.Kid)
+		if err != nil {
+			return err
+		}
+		hash := sha256.Sum256([]byte(signingInput))
+		if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, hash[:], signature); err != nil {
+			return errors.New("invalid JWT signature")
+		}
+		if !connectAudienceContains(claims.Aud, strings.TrimSuffix(s.config.Jira.Connect.BaseURL, "/")) {
+			return errors.New("JWT audience does not match the app base URL")
+		}
+	case "HS256":
+		installation := s.getInstallation(clientKey)
+		if installation == nil {
+			return fmt.Errorf("unsigned installation for unknown client key %s", clientKey)
+		}
+		if !hmac.Equal(signature, signHS256(signingInput, installation.SharedSecret)) {
+			return errors.New("invalid JWT signature")
+		}
+	default:
+		return fmt.Errorf("unsupported JWT algorithm: %s", header.Alg)
+	}
+
+	return s.verifyClaims(claims, r)
+}
+
+// verifyClaims checks the expiry and query string hash of an incoming JWT
+func (s *JiraConnectServiceImpl) verifyClaims(claims *connectClaims, r *http.Request) error {
+	if s.now().After(time.Unix(claims.Exp, 0).Add(connectClockSkew)) {
+		return errors.New("JWT has expired")
+	}
+
+	appBaseURL, err := url.Parse(s.config.Jira.Connect.BaseURL)
+	if err != nil {
+		return fmt.Errorf("invalid app base URL: %w", err)
+	}
+	if claims.Qsh != connectQSH(r.Method, r.URL, appBaseURL.Path) {
+		return errors.New("JWT query string hash does not match the request")
+	}
+
+	return nil
+}
+
+// fetchInstallKey downloads the public key Atlassian signed an install callback with
+func (s *JiraConnectServiceImpl) fetchInstallKey(kid string) (*rsa.PublicKey, error) {
+	if kid == "" || strings.ContainsAny(kid, "/?#") {
+		return nil, fmt.Errorf("invalid JWT key ID: %q", kid)
+	}
+
+	resp, err := s.client.Get(fmt.Sprintf("%s/%s", s.installKeysURL, kid))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch install key: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read install key: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch install key: %s, status: %d", string(body), resp.StatusCode)
+	}
+
+	block, _ := pem.Decode(body)
+	if block == nil {
+		return nil, errors.New("install key is not PEM encoded")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse install key: %w", err)
+	}
+	publicKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("install key is not an RSA key")
+	}
+	return publicKey, nil
+}
+
+// getInstallation returns the installation for a client key, or nil
+func (s *JiraConnectServiceImpl) getInstallation(clientKey string) *models.JiraConnectInstallation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.installations[clientKey]
+}
+
+// installationForBaseURL returns the installation of a Jira site. When no base URL is
+// configured and the app is installed on exactly one site, that site is used.
+func (s *JiraConnectServiceImpl) installationForBaseURL(baseURL string) *models.JiraConnectInstallation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	for _, installation := range s.installations {
+		if installation.BaseURL == baseURL {
+			return installation
+		}
+	}
+	if baseURL == "" && len(s.installations) == 1 {
+		for _, installation := range s.installations {
+			return installation
+		}
+	}
+	return nil
+}
+
+// saveInstallations persists the installations. The caller must hold the write lock.
+func (s *JiraConnectServiceImpl) saveInstallations() error {
+	data, err := json.MarshalIndent(s.installations, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal installations: %w", err)
+	}
+
+	if err := writeFileAtomic(s.config.Jira.Connect.InstallationsFile, data); err != nil {
+		return fmt.Errorf("failed to save installations: %w", err)
+	}
+	return nil
+}
+
+// connectTokenFromRequest extracts the JWT from the Authorization header or jwt query parameter
+func connectTokenFromRequest(r *http.Request) (string, error) {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "JWT ") {
+		return strings.TrimPrefix(auth, "JWT "), nil
+	}
+	if token := r.URL.Query().Get("jwt"); token != "" {
+		return token, nil
+	}
+	return "", errors.New("request has no JWT")
+}
+
+// parseConnectJWT splits a JWT into its decoded parts without verifying it
+func parseConnectJWT(token string) (*connectJWTHeader, *connectClaims, string, []byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, nil, "", nil, errors.New("malformed JWT")
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, nil, "", nil, fmt.Errorf("failed to decode JWT header: %w", err)
+	}
+	var header connectJWTHeader
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, nil, "", nil, fmt.Errorf("failed to parse JWT header: %w", err)
+	}
+
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, nil, "", nil, fmt.Errorf("failed to decode JWT claims: %w", err)
+	}
+	var claims connectClaims
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil {
+		return nil, nil, "", nil, fmt.Errorf("failed to parse JWT claims: %w", err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, nil, "", nil, fmt.Errorf("failed to decode JWT signature: %w", err)
+	}
+
+	return &header, &claims, parts[0] + "." + parts[1], signature, nil
+}
+
+// createHS256JWT creates a JWT signed with a shared secret
+func createHS256JWT(claims connectClaims, secret string) (string, error) {
+	headerJSON, err := json.Marshal(connectJWTHeader{Alg: "HS256", Typ: "JWT"})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JWT header: %w", err)
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JWT claims: %w", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signHS256(signingInput, secret)), nil
+}
+
+// signHS256 computes the HMAC-SHA256 signature of a JWT signing input
+func signHS256(signingInput, secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
+
+// connectAudienceContains reports whether a JWT aud claim (a string or a list) contains the expected audience
+func connectAudienceContains(aud json.RawMessage, expected string) bool {
+	var single string
+	if err := json.Unmarshal(aud, &single); err == nil {
+		return strings.TrimSuffix(single, "/") == expected
+	}
+	var list []string
+	if err := json.Unmarshal(aud, &list); err == nil {
+		for _, value := range list {
+			if strings.TrimSuffix(value, "/") == expected {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// connectQSH computes the Atlassian Connect query string hash of a request.
+// basePath is the path of the base URL the request path is relative to.
+func connectQSH(method string, u *url.URL, basePath string) string {
+	path := strings.TrimPrefix(u.EscapedPath(), strings.TrimSuffix(basePath, "/"))
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	path = strings.ReplaceAll(path, "&", "%26")
+
+	query := u.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		if key != "jwt" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	params := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values := make([]string, len(query[key]))
+		for i, value := range query[key] {
+			values[i] = connectPercentEncode(value)
+		}
+		sort.Strings(values)
+		params = append(params, connectPercentEncode(key)+"="+strings.Join(values, ","))
+	}
+
+	canonical := strings.ToUpper(method) + "&" + path + "&" + strings.Join(params, "&")
+	hash := sha256.Sum256([]byte(canonical))
+	return hex.EncodeToString(hash[:])
+}
+
+// connectPercentEncode encodes a query component as required by the Connect canonical request (RFC 3986)
+func connectPercentEncode(value string) string {
+	encoded := url.QueryEscape(value)
+	encoded = strings.ReplaceAll(encoded, "+", "%20")
+	encoded = strings.ReplaceAll(encoded, "*", "%2A")
+	return strings.ReplaceAll(encoded, "%7E", "~")
+}
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func newTestConnectConfig(t *testing.T) *models.Config {
+	config := &models.Config{}
+	config.Jira.AuthMode = models.JiraAuthModeConnect
+	config.Jira.BaseURL = "https://example.atlassian.net"
+	config.Jira.Connect.AppKey = "ai-solver"
+	config.Jira.Connect.BaseURL = "https://solver.example.com"
+	config.Jira.Connect.InstallationsFile = filepath.Join(t.TempDir(), "installations.json")
+	return config
+}
+
+func newTestConnectService(t *testing.T, config *models.Config, now time.Time) *JiraConnectServiceImpl {
+	service, err := NewJiraConnectService(config, zap.NewNop())
+	if err != nil {
+		t.Fatalf("Failed to create Jira Connect service: %v", err)
+	}
+	impl := service.(*JiraConnectServiceImpl)
+	impl.now = func() time.Time { return now }
+	return impl
+}
+
+// signRS256 creates an RS256 JWT the way Atlassian signs install callbacks
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims connectClaims) string {
+	headerJSON, _ := json.Marshal(connectJWTHeader{Alg: "RS256", Kid: kid, Typ: "JWT"})
+	claimsJSON, _ := json.Marshal(claims)
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+	hash := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+	if err != nil {
+		t.Fatalf("Failed to sign JWT: %v", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestJiraConnectService_VerifyRequest(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	config := newTestConnectConfig(t)
+	service := newTestConnectService(t, config, now)
+	service.installations["client-1"] = &models.JiraConnectInstallation{
+		ClientKey:    "client-1",
+		SharedSecret: "shared-secret",
+		BaseURL:      "https://example.atlassian.net",
+	}
+
+	newRequest := func(target string, claims connectClaims, secret string) *http.Request {
+		token, err := createHS256JWT(claims, secret)
+		if err != nil {
+			t.Fatalf("Failed to create JWT: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodPost, target, nil)
+		req.Header.Set("Authorization", "JWT "+token)
+		return req
+	}
+
+	webhookURL, _ := url.Parse("/jira/webhook?user_id=abc&issue=TEST-1")
+	validClaims := connectClaims{
+		Iss: "client-1",
+		Iat: now.Unix(),
+		Exp: now.Add(time.Minute).Unix(),
+		Qsh: connectQSH(http.MethodPost, webhookURL, ""),
+	}
+
+	installation, err := service.VerifyRequest(newRequest("/jira/webhook?user_id=abc&issue=TEST-1", validClaims, "shared-secret"))
+	if err != nil {
+		t.Fatalf("Expected valid request, got: %v", err)
+	}
+	if installation.ClientKey != "client-1" {
+		t.Errorf("Expected installation 'client-1', got '%s'", installation.ClientKey)
+	}
+
+	// Query parameter order doesn't matter
+	if _, err := service.VerifyRequest(newRequest("/jira/webhook?issue=TEST-1&user_id=abc", validClaims, "shared-secret")); err != nil {
+		t.Errorf("Expected reordered query to verify, got: %v", err)
+	}
+
+	if _, err := service.VerifyRequest(newRequest("/jira/webhook?issue=TEST-2&user_id=abc", validClaims, "shared-secret")); err == nil {
+		t.Error("Expected error for a request that doesn't match the query string hash")
+	}
+
+	if _, err := service.VerifyRequest(newRequest("/jira/webhook?user_id=abc&issue=TEST-1", validClaims, "wrong-secret")); err == nil {
+		t.Error("Expected error for a JWT signed with the wrong secret")
+	}
+
+	expiredClaims := validClaims
+	expiredClaims.Exp = now.Add(-time.Hour).Unix()
+	if _, err := service.VerifyRequest(newRequest("/jira/webhook?user_id=abc&issue=TEST-1", expiredClaims, "shared-secret")); err == nil {
+		t.Error("Expected error for an expired JWT")
+	}
+
+	unknownClaims := validClaims
+	unknownClaims.Iss = "client-2"
+	if _, err := service.VerifyRequest(newRequest("/jira/webhook?user_id=abc&issue=TEST-1", unknownClaims, "shared-secret")); err == nil {
+		t.Error("Expected error for an unknown client key")
+	}
+
+	if _, err := service.VerifyRequest(httptest.NewRequest(http.MethodPost, "/jira/webhook", nil)); err == nil {
+		t.Error("Expected error for a request without a JWT")
+	}
+}
+
+func TestJiraConnectService_HandleInstalled_SignedInstall(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal public key: %v", err)
+	}
+	keyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/key-1" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = pem.Encode(w, &pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyBytes})
+	}))
+	defer keyServer.Close()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	config := newTestConnectConfig(t)
+	service := newTestConnectService(t, config, now)
+	service.installKeysURL = keyServer.URL
+
+	installedURL, _ := url.Parse(JiraConnectInstalledPath)
+	claims := connectClaims{
+		Iss: "client-1",
+		Iat: now.Unix(),
+		Exp: now.Add(time.Minute).Unix(),
+		Qsh: connectQSH(http.MethodPost, installedURL, ""),
+		Aud: json.RawMessage(`["https://solver.example.com"]`),
+	}
+	payload := &models.JiraConnectLifecycle{
+		Key:          "ai-solver",
+		ClientKey:    "client-1",
+		SharedSecret: "shared-secret",
+		BaseURL:      "https://example.atlassian.net/",
+	}
+
+	newInstallRequest := func(kid string, claims connectClaims) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, JiraConnectInstalledPath, nil)
+		req.Header.Set("Authorization", "JWT "+signRS256(t, key, kid, claims))
+		return req
+	}
+
+	wrongAudience := claims
+	wrongAudience.Aud = json.RawMessage(`"https://attacker.example.com"`)
+	if err := service.HandleInstalled(newInstallRequest("key-1", wrongAudience), payload); err == nil {
+		t.Error("Expected error for a JWT issued for another app")
+	}
+	if err := service.HandleInstalled(newInstallRequest("unknown-key", claims), payload); err == nil {
+		t.Error("Expected error for an unknown signing key")
+	}
+
+	if err := service.HandleInstalled(newInstallRequest("key-1", claims), payload); err != nil {
+		t.Fatalf("Expected installation to succeed, got: %v", err)
+	}
+
+	// The installation survives a restart
+	reloaded := newTestConnectService(t, config, now)
+	installation := reloaded.getInstallation("client-1")
+	if installation == nil || installation.SharedSecret != "shared-secret" || installation.BaseURL != "https://example.atlassian.net" {
+		t.Fatalf("Expected installation to be persisted, got %+v", installation)
+	}
+
+	// Uninstalling with a JWT signed by the shared secret removes it
+	uninstalledURL, _ := url.Parse(JiraConnectUninstalledPath)
+	uninstallClaims := connectClaims{
+		Iss: "client-1",
+		Iat: now.Unix(),
+		Exp: now.Add(time.Minute).Unix(),
+		Qsh: connectQSH(http.MethodPost, uninstalledURL, ""),
+	}
+	token, err := createHS256JWT(uninstallClaims, "shared-secret")
+	if err != nil {
+		t.Fatalf("Failed to create JWT: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, JiraConnectUninstalledPath, nil)
+	req.Header.Set("Authorization", "JWT "+token)
+	if err := reloaded.HandleUninstalled(req, &models.JiraConnectLifecycle{ClientKey: "client-1"}); err != nil {
+		t.Fatalf("Expected uninstallation to succeed, got: %v", err)
+	}
+	if reloaded.getInstallation("client-1") != nil {
+		t.Error("Expected installation to be removed")
+	}
+}
+
+func TestJiraConnectService_SignRequest(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	config := newTestConnectConfig(t)
+	config.Jira.BaseURL = "https://jira.example.com/jira"
+	service := newTestConnectService(t, config, now)
+	service.installations["client-1"] = &models.JiraConnectInstallation{
+		ClientKey:    "client-1",
+		SharedSecret: "shared-secret",
+		BaseURL:      "https://jira.example.com/jira",
+	}
+
+	var authorization string
+	client := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		authorization = req.Header.Get("Authorization")
+		return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody}, nil
+	})
+	jiraService := NewConnectJiraService(config, service, NewMetrics(), zap.NewNop()).(*JiraServiceImpl)
+	jiraService.client = client
+
+	if err := jiraService.AddComment(context.Background(), "TEST-1", "hello"); err != nil && !strings.Contains(err.Error(), "status") {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(authorization, "JWT ") {
+		t.Fatalf("Expected JWT authorization, got '%s'", authorization)
+	}
+	_, claims, _, _, err := parseConnectJWT(strings.TrimPrefix(authorization, "JWT "))
+	if err != nil {
+		t.Fatalf("Failed to parse JWT: %v", err)
+	}
+	if claims.Iss != "ai-solver" {
+		t.Errorf("Expected issuer 'ai-solver', got '%s'", claims.Iss)
+	}
+
+	// The query string hash is relative to the Jira context path
+	expectedURL, _ := url.Parse("/rest/api/2/issue/TEST-1/comment")
+	if claims.Qsh != connectQSH(http.MethodPost, expectedURL, "") {
+		t.Error("Expected query string hash to be computed relative to the Jira context path")
+	}
+}
+
+func TestJiraConnectService_SignRequest_NotInstalled(t *testing.T) {
+	config := newTestConnectConfig(t)
+	service := newTestConnectService(t, config, time.Now())
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.atlassian.net/rest/api/2/myself", nil)
+	if err := service.SignRequest(req); err == nil {
+		t.Error("Expected error when the app is not installed")
+	}
+}
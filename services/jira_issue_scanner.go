@@ -1,7 +1,10 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"jira-ai-issue-solver/models"
@@ -13,66 +16,98 @@ import (
 type JiraIssueScannerService interface {
 	// Start starts the periodic scanning
 	Start()
-	// Stop stops the periodic scanning
+	// Stop stops the periodic scanning, waiting for a running scan to finish and dropping the queued tickets that
+	// didn't start. The scanner can be started again.
 	Stop()
+	// IsRunning returns whether the scanner is started
+	IsRunning() bool
+	// TriggerScan requests a scan before the next interval, e.g. when a webhook reports a ticket change
+	TriggerScan()
+	// Status returns the live state of the scanner
+	Status() ScannerStatus
 }
 
 // JiraIssueScannerServiceImpl implements the JiraIssueScannerService interface
 type JiraIssueScannerServiceImpl struct {
+	ctx             context.Context // Bounds the work of the service; canceling it aborts work in progress
 	jiraService     JiraService
 	githubService   GitHubService
 	aiService       AIService
 	ticketProcessor TicketProcessor
+	repoResolver    RepositoryResolver
+	stateMachine    TicketStateMachine
 	config          *models.Config
 	logger          *zap.Logger
-	stopChan        chan struct{}
-	isRunning       bool
+	triggerChan     chan struct{}
+	inFlight        sync.Map     // Keys of the tickets queued or being processed
+	queue           *ticketQueue // Starts the found tickets in order, at most jira.max_concurrent_tickets at once
+	scans           scanClock
+
+	mu        sync.Mutex
+	stopChan  chan struct{} // Closed to stop the current scan loop
+	doneChan  chan struct{} // Closed once the current scan loop returned
+	isRunning bool
 }
 
 // NewJiraIssueScannerService creates a new JiraIssueScannerService
 func NewJiraIssueScannerService(
+	ctx context.Context,
 	jiraService JiraService,
 	githubService GitHubService,
 	aiService AIService,
+	stateMachine TicketStateMachine,
 	config *models.Config,
 	logger *zap.Logger,
 ) JiraIssueScannerService {
-	ticketProcessor := NewTicketProcessor(jiraService, githubService, aiService, config, logger)
+	ticketProcessor := NewTicketProcessor(jiraService, githubService, aiService, stateMachine, config, logger)
 
 	return &JiraIssueScannerServiceImpl{
+		ctx:             ctx,
 		jiraService:     jiraService,
 		githubService:   githubService,
 		aiService:       aiService,
 		ticketProcessor: ticketProcessor,
+		repoResolver:    NewRepositoryResolver(jiraService, config, logger),
+		stateMachine:    stateMachine,
 		config:          config,
 		logger:          logger,
-		stopChan:        make(chan struct{}),
-		isRunning:       false,
+		triggerChan:     make(chan struct{}, 1),
+		queue:           newTicketQueue(ctx, config.Jira.MaxConcurrentTickets),
 	}
 }
 
 // Start starts the periodic scanning
 func (s *JiraIssueScannerServiceImpl) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.isRunning {
 		s.logger.Info("Jira issue scanner is already running")
 		return
 	}
 
 	s.isRunning = true
+	s.stopChan = make(chan struct{})
+	s.doneChan = make(chan struct{})
 	s.logger.Info("Starting Jira issue scanner...")
 
+	// Each loop gets its own channels, so a loop still finishing after Stop can't see the next Start's
+	stopChan, doneChan := s.stopChan, s.doneChan
 	go func() {
+		defer close(doneChan)
 		ticker := time.NewTicker(time.Duration(s.config.Jira.IntervalSeconds) * time.Second)
 		defer ticker.Stop()
 
-		// Run initial scan immediately
-		s.scanForTickets()
+		// Restart tickets a previous run left mid-pipeline, then run the initial scan immediately
+		s.resumeInterruptedTickets(s.ctx)
+		s.scanForTickets(s.ctx)
 
 		for {
 			select {
 			case <-ticker.C:
-				s.scanForTickets()
-			case <-s.stopChan:
+				s.scanForTickets(s.ctx)
+			case <-s.triggerChan:
+				s.scanForTickets(s.ctx)
+			case <-stopChan:
 				s.logger.Info("Stopping Jira issue scanner...")
 				return
 			}
@@ -80,26 +115,84 @@ func (s *JiraIssueScannerServiceImpl) Start() {
 	}()
 }
 
-// Stop stops the periodic scanning
+// Stop stops the periodic scanning, waiting for a running scan to finish. Queued tickets that didn't start are
+// dropped, to be found again by the next scan; tickets being processed keep running. The scanner can be started again.
 func (s *JiraIssueScannerServiceImpl) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if !s.isRunning {
 		return
 	}
 
 	s.isRunning = false
 	close(s.stopChan)
+	<-s.doneChan
+	if dropped := s.queue.clear(); dropped > 0 {
+		s.logger.Info("Dropped queued tickets", zap.Int("count", dropped))
+	}
+}
+
+// IsRunning returns whether the scanner is started
+func (s *JiraIssueScannerServiceImpl) IsRunning() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.isRunning
+}
+
+// Status returns the live state of the scanner. In-flight tickets are queued until their processing moves them past
+// the queued pipeline state.
+func (s *JiraIssueScannerServiceImpl) Status() ScannerStatus {
+	status := ScannerStatus{
+		Running:    s.IsRunning(),
+		LastScanAt: s.scans.last(),
+		InFlight:   inFlightKeys(&s.inFlight),
+	}
+	for _, ticketKey := range status.InFlight {
+		if state, ok := s.stateMachine.State(ticketKey); !ok || state == models.TicketStateQueued {
+			status.Queued++
+		}
+	}
+	return status
+}
+
+// TriggerScan requests a scan before the next interval.
+// Requests arriving while a scan is already pending are coalesced into that scan.
+func (s *JiraIssueScannerServiceImpl) TriggerScan() {
+	select {
+	case s.triggerChan <- struct{}{}:
+	default:
+	}
+}
+
+// resumeInterruptedTickets restarts the tickets a previous run was processing when it stopped.
+// Their status already left the todo status, so the regular scan wouldn't pick them up again.
+func (s *JiraIssueScannerServiceImpl) resumeInterruptedTickets(ctx context.Context) {
+	for _, ticketKey := range s.stateMachine.Interrupted() {
+		state, _ := s.stateMachine.State(ticketKey)
+		s.logger.Info("Resuming interrupted ticket", zap.String("ticket", ticketKey), zap.String("state", state.String()))
+		s.processTicketAsync(ctx, ticketKey)
+	}
 }
 
 // scanForTickets searches for tickets that need AI processing
-func (s *JiraIssueScannerServiceImpl) scanForTickets() {
+func (s *JiraIssueScannerServiceImpl) scanForTickets(ctx context.Context) {
 	s.logger.Info("Scanning for tickets that need AI processing...")
+	defer s.scans.finished()
 
-	todoStatus := s.config.Jira.StatusTransitions.Todo
+	sprintJQL, err := activeSprintJQL(ctx, s.jiraService, s.config)
+	if errors.Is(err, errNoActiveSprint) {
+		s.logger.Info("No active sprint, skipping the scan", zap.Int("board_id", s.config.Jira.Sprint.BoardID))
+		return
+	}
+	if err != nil {
+		s.logger.Error("Failed to get the active sprints", zap.Int("board_id", s.config.Jira.Sprint.BoardID), zap.Error(err))
+		return
+	}
 
-	// Build JQL query to find tickets assigned to current user in TODO status
-	jql := fmt.Sprintf(`Contributors = currentUser() AND status = "%s" ORDER BY updated DESC`, todoStatus)
+	// Build JQL query to find the tickets marked for processing, in the order they're processed in
+	jql := fmt.Sprintf(`%s%s%s %s`, triggerJQL(s.config), skippedIssueTypesJQL(s.config), sprintJQL, s.config.Jira.ScanOrder.OrderBy())
 
-	searchResponse, err := s.jiraService.SearchTickets(jql)
+	searchResponse, err := s.jiraService.SearchTickets(ctx, jql)
 	if err != nil {
 		s.logger.Error("Failed to search for tickets", zap.Error(err))
 		return
@@ -112,13 +205,55 @@ func (s *JiraIssueScannerServiceImpl) scanForTickets() {
 
 	s.logger.Info("Found tickets that need AI processing", zap.Int("count", searchResponse.Total))
 
-	// Process each ticket
-	for _, issue := range searchResponse.Issues {
-		s.logger.Info("Found ticket", zap.String("ticket", issue.Key))
+	// Process each ticket, or each batch of related tickets of an epic
+	for _, batch := range epicBatches(ctx, s.repoResolver, s.config, searchResponse.Issues) {
+		s.logger.Info("Found ticket", zap.String("ticket", batch[0]), zap.Strings("batched_tickets", batch[1:]))
 
-		// Process all tickets returned by the search
+		// Queue the ticket, so the tickets start in the scan's order
+		if len(batch) == 1 {
+			s.processTicketAsync(ctx, batch[0])
+		} else {
+			s.processBatchAsync(ctx, batch)
+		}
+	}
+}
+
+// processTicketAsync queues the ticket for processing in the background unless it is already queued or being
+// processed. A slow ticket stays in the todo status across scans, and two runs would fight over the same checkout.
+func (s *JiraIssueScannerServiceImpl) processTicketAsync(ctx context.Context, ticketKey string) {
+	if _, busy := s.inFlight.LoadOrStore(ticketKey, struct{}{}); busy {
+		s.logger.Info("Ticket is already being processed, skipping", zap.String("ticket", ticketKey))
+		return
+	}
 
-		// Process the ticket asynchronously
-		go s.ticketProcessor.ProcessTicket(issue.Key)
+	release := func() { s.inFlight.Delete(ticketKey) }
+	s.queue.push(func() {
+		defer release()
+		s.ticketProcessor.ProcessTicket(ctx, ticketKey)
+	}, release)
+}
+
+// processBatchAsync queues a batch of tickets for processing in the background, leaving out the tickets already being processed
+func (s *JiraIssueScannerServiceImpl) processBatchAsync(ctx context.Context, ticketKeys []string) {
+	var claimed []string
+	for _, ticketKey := range ticketKeys {
+		if _, busy := s.inFlight.LoadOrStore(ticketKey, struct{}{}); busy {
+			s.logger.Info("Ticket is already being processed, leaving it out of the batch", zap.String("ticket", ticketKey))
+			continue
+		}
+		claimed = append(claimed, ticketKey)
+	}
+	if len(claimed) == 0 {
+		return
+	}
+
+	release := func() {
+		for _, ticketKey := range claimed {
+			s.inFlight.Delete(ticketKey)
+		}
 	}
+	s.queue.push(func() {
+		defer release()
+		s.ticketProcessor.ProcessTicketBatch(ctx, claimed)
+	}, release)
 }
@@ -1,6 +1,10 @@
 package services
 
 import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -32,7 +36,7 @@ func TestJiraIssueScannerService_StartStop(t *testing.T) {
 	config.TempDir = "/tmp/test"
 
 	// Create scanner service
-	scanner := NewJiraIssueScannerService(mockJiraService, mockGitHubService, mockClaudeService, config, logger)
+	scanner := NewJiraIssueScannerService(context.Background(), mockJiraService, mockGitHubService, mockClaudeService, newTestStateMachine(config), config, logger)
 
 	// Start the scanner
 	scanner.Start()
@@ -121,16 +125,260 @@ func TestJiraIssueScannerService_ScanForTickets(t *testing.T) {
 
 	// Create scanner service with injected mock ticket processor
 	scanner := &JiraIssueScannerServiceImpl{
+		queue:           newTicketQueue(t.Context(), 1),
 		jiraService:     mockJiraService,
 		githubService:   mockGitHubService,
 		aiService:       mockClaudeService,
 		ticketProcessor: mockTicketProcessor,
+		repoResolver:    NewRepositoryResolver(mockJiraService, config, logger),
 		config:          config,
 		logger:          logger,
 	}
 
 	// Test scanning for tickets
-	scanner.scanForTickets()
+	scanner.scanForTickets(context.Background())
 }
 
 // Note: The JQL query now only filters by assignee and status for simpler logic.
+
+func TestJiraIssueScannerService_Restart(t *testing.T) {
+	var scans atomic.Int32
+	mockJiraService := &mocks.MockJiraService{
+		SearchTicketsFunc: func(jql string) (*models.JiraSearchResponse, error) {
+			scans.Add(1)
+			return &models.JiraSearchResponse{}, nil
+		},
+	}
+	config := &models.Config{}
+	config.Jira.IntervalSeconds = 60
+	scanner := NewJiraIssueScannerService(context.Background(), mockJiraService, &mocks.MockGitHubService{}, &mocks.MockClaudeService{}, newTestStateMachine(config), config, zap.NewNop())
+
+	// Stop waits for the loop, so each cycle has run its initial scan once Stop returns
+	for cycle := 1; cycle <= 3; cycle++ {
+		scanner.Start()
+		if !scanner.IsRunning() {
+			t.Fatalf("Expected the scanner to run in cycle %d", cycle)
+		}
+		scanner.Stop()
+		if scanner.IsRunning() {
+			t.Fatalf("Expected the scanner to be stopped in cycle %d", cycle)
+		}
+		if got := scans.Load(); got != int32(cycle) {
+			t.Errorf("Expected %d scans after cycle %d, got %d", cycle, cycle, got)
+		}
+	}
+
+	// Concurrent calls must neither panic nor race
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() { defer wg.Done(); scanner.Start() }()
+		go func() { defer wg.Done(); scanner.Stop() }()
+	}
+	wg.Wait()
+	scanner.Stop()
+}
+
+func TestJiraIssueScannerService_SkipsTicketsInFlight(t *testing.T) {
+	mockJiraService := &mocks.MockJiraService{
+		SearchTicketsFunc: func(jql string) (*models.JiraSearchResponse, error) {
+			return &models.JiraSearchResponse{Total: 1, Issues: []models.JiraIssue{{Key: "TEST-1"}}}, nil
+		},
+	}
+
+	var runs atomic.Int32
+	release := make(chan struct{})
+	done := make(chan struct{}, 2)
+	scanner := &JiraIssueScannerServiceImpl{
+		queue:       newTicketQueue(t.Context(), 1),
+		jiraService: mockJiraService,
+		ticketProcessor: &mocks.MockTicketProcessor{
+			ProcessTicketFunc: func(key string) error {
+				runs.Add(1)
+				<-release
+				done <- struct{}{}
+				return nil
+			},
+		},
+		repoResolver: NewRepositoryResolver(mockJiraService, &models.Config{}, zap.NewNop()),
+		config:       &models.Config{},
+		logger:       zap.NewNop(),
+	}
+
+	// The ticket is still in todo while the first run is busy
+	scanner.scanForTickets(context.Background())
+	scanner.scanForTickets(context.Background())
+	close(release)
+	<-done
+	if got := runs.Load(); got != 1 {
+		t.Fatalf("Expected one run while the ticket is in flight, got %d", got)
+	}
+
+	// Once the run finished, the ticket can be processed again
+	for {
+		if _, busy := scanner.inFlight.Load("TEST-1"); !busy {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	scanner.scanForTickets(context.Background())
+	<-done
+	if got := runs.Load(); got != 2 {
+		t.Errorf("Expected the ticket to be processed again after its run, got %d runs", got)
+	}
+}
+
+func TestJiraIssueScannerService_Status(t *testing.T) {
+	stateMachine := newTestStateMachine(&models.Config{})
+	scanner := &JiraIssueScannerServiceImpl{
+		queue: newTicketQueue(t.Context(), 1),
+		jiraService: &mocks.MockJiraService{
+			SearchTicketsFunc: func(jql string) (*models.JiraSearchResponse, error) {
+				return &models.JiraSearchResponse{}, nil
+			},
+		},
+		stateMachine: stateMachine,
+		config:       &models.Config{},
+		logger:       zap.NewNop(),
+	}
+
+	if status := scanner.Status(); status.Running || status.LastScanAt != nil || len(status.InFlight) != 0 {
+		t.Errorf("Expected a scanner that never scanned, got %+v", status)
+	}
+
+	scanner.scanForTickets(context.Background())
+	scanner.inFlight.Store("TEST-2", struct{}{})
+	scanner.inFlight.Store("TEST-1", struct{}{})
+	if err := stateMachine.Transition("TEST-1", models.TicketStateQueued, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := stateMachine.Transition("TEST-2", models.TicketStateQueued, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := stateMachine.Transition("TEST-2", models.TicketStateGenerating, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	status := scanner.Status()
+	if status.LastScanAt == nil {
+		t.Error("Expected the time of the last scan")
+	}
+	if !reflect.DeepEqual(status.InFlight, []string{"TEST-1", "TEST-2"}) {
+		t.Errorf("Expected the in-flight tickets in order, got %v", status.InFlight)
+	}
+	if status.Queued != 1 {
+		t.Errorf("Expected only the ticket still queued to count, got %d", status.Queued)
+	}
+}
+
+func TestJiraIssueScannerService_ProcessesTicketsInScanOrder(t *testing.T) {
+	mockJiraService := &mocks.MockJiraService{
+		SearchTicketsFunc: func(jql string) (*models.JiraSearchResponse, error) {
+			return &models.JiraSearchResponse{Total: 4, Issues: []models.JiraIssue{
+				{Key: "TEST-3"}, {Key: "TEST-1"}, {Key: "TEST-4"}, {Key: "TEST-2"},
+			}}, nil
+		},
+	}
+
+	var mu sync.Mutex
+	var started []string
+	var running, maxRunning int
+	release := make(chan struct{})
+	done := make(chan struct{}, 4)
+	scanner := &JiraIssueScannerServiceImpl{
+		jiraService: mockJiraService,
+		ticketProcessor: &mocks.MockTicketProcessor{
+			ProcessTicketFunc: func(key string) error {
+				mu.Lock()
+				started = append(started, key)
+				running++
+				maxRunning = max(maxRunning, running)
+				mu.Unlock()
+				<-release
+				mu.Lock()
+				running--
+				mu.Unlock()
+				done <- struct{}{}
+				return nil
+			},
+		},
+		repoResolver: NewRepositoryResolver(mockJiraService, &models.Config{}, zap.NewNop()),
+		config:       &models.Config{},
+		logger:       zap.NewNop(),
+		queue:        newTicketQueue(t.Context(), 1),
+	}
+
+	scanner.scanForTickets(context.Background())
+	close(release)
+	for i := 0; i < 4; i++ {
+		<-done
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(started, []string{"TEST-3", "TEST-1", "TEST-4", "TEST-2"}) {
+		t.Errorf("Expected the tickets to start in the scan's order, got %v", started)
+	}
+	if maxRunning != 1 {
+		t.Errorf("Expected one ticket processed at once, got %d", maxRunning)
+	}
+}
+
+func TestJiraIssueScannerService_StopDropsQueuedTickets(t *testing.T) {
+	mockJiraService := &mocks.MockJiraService{
+		SearchTicketsFunc: func(jql string) (*models.JiraSearchResponse, error) {
+			return &models.JiraSearchResponse{Total: 3, Issues: []models.JiraIssue{{Key: "TEST-1"}, {Key: "TEST-2"}, {Key: "TEST-3"}}}, nil
+		},
+	}
+
+	var mu sync.Mutex
+	var started []string
+	firstStarted := make(chan struct{})
+	release := make(chan struct{})
+	config := &models.Config{}
+	config.Jira.IntervalSeconds = 60
+	scanner := &JiraIssueScannerServiceImpl{
+		ctx:         context.Background(),
+		jiraService: mockJiraService,
+		ticketProcessor: &mocks.MockTicketProcessor{
+			ProcessTicketFunc: func(key string) error {
+				mu.Lock()
+				started = append(started, key)
+				mu.Unlock()
+				if key == "TEST-1" {
+					close(firstStarted)
+					<-release
+				}
+				return nil
+			},
+		},
+		repoResolver: NewRepositoryResolver(mockJiraService, config, zap.NewNop()),
+		stateMachine: newTestStateMachine(config),
+		config:       config,
+		logger:       zap.NewNop(),
+		triggerChan:  make(chan struct{}, 1),
+		queue:        newTicketQueue(t.Context(), 1),
+	}
+
+	// The first ticket occupies the only worker, the others wait in the queue
+	scanner.Start()
+	<-firstStarted
+	scanner.Stop()
+	close(release)
+
+	// Tickets queued after the stop still run, so the dropped ones would have started by now
+	done := make(chan struct{})
+	scanner.queue.push(func() { close(done) }, func() {})
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(started, []string{"TEST-1"}) {
+		t.Errorf("Expected no queued ticket to start after Stop, got %v", started)
+	}
+	for _, ticketKey := range []string{"TEST-1", "TEST-2", "TEST-3"} {
+		if _, busy := scanner.inFlight.Load(ticketKey); busy {
+			t.Errorf("Expected %s to be released for the next scan", ticketKey)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"jira-ai-issue-solver/models"
+)
+
+// CreateTicket creates a Jira issue and returns its key
+func (s *JiraServiceImpl) CreateTicket(ctx context.Context, fields models.JiraCreateIssueFields) (string, error) {
+	url := fmt.Sprintf("%s/rest/api/2/issue", s.config.Jira.BaseURL)
+
+	jsonPayload, err := json.Marshal(models.JiraCreateIssueRequest{Fields: fields})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if err := s.setAuthHeader(req); err != nil {
+		return "", fmt.Errorf("failed to authenticate request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed to create ticket: %s, status code: %d", string(body), resp.StatusCode)
+	}
+
+	var created models.JiraCreateIssueResponse
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return created.Key, nil
+}
+
+// LinkTickets links two Jira issues with the given link type, e.g. "Relates"
+func (s *JiraServiceImpl) LinkTickets(ctx context.Context, linkType, inwardKey, outwardKey string) error {
+	url := fmt.Sprintf("%s/rest/api/2/issueLink", s.config.Jira.BaseURL)
+
+	payload := models.JiraIssueLinkRequest{
+		Type:         models.JiraNameRef{Name: linkType},
+		InwardIssue:  models.JiraKeyRef{Key: inwardKey},
+		OutwardIssue: models.JiraKeyRef{Key: outwardKey},
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if err := s.setAuthHeader(req); err != nil {
+		return fmt.Errorf("failed to authenticate request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to link tickets: %s, status code: %d", string(body), resp.StatusCode)
+	}
+
+	return nil
+}
+
+// AddRemoteLink links a ticket to an object outside Jira, replacing an earlier link with the same global ID
+func (s *JiraServiceImpl) AddRemoteLink(ctx context.Context, key string, link models.JiraRemoteLink) error {
+	url := fmt.Sprintf("%s/rest/api/2/issue/%s/remotelink", s.config.Jira.BaseURL, key)
+
+	jsonPayload, err := json.Marshal(link)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if err := s.setAuthHeader(req); err != nil {
+		return fmt.Errorf("failed to authenticate request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to add remote link: %s, status code: %d", string(body), resp.StatusCode)
+	}
+
+	return nil
+}
+
+// AddWorklog logs time spent on a ticket, as the worklog account if one is configured
+func (s *JiraServiceImpl) AddWorklog(ctx context.Context, key string, worklog models.JiraWorklog) error {
+	url := fmt.Sprintf("%s/rest/api/2/issue/%s/worklog", s.config.Jira.BaseURL, key)
+
+	jsonPayload, err := json.Marshal(worklog)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Jira logs the work as the account making the request
+	if token := s.config.Jira.Worklog.APIToken; token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	} else if err := s.setAuthHeader(req); err != nil {
+		return fmt.Errorf("failed to authenticate request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to add worklog: %s, status code: %d", string(body), resp.StatusCode)
+	}
+
+	return nil
+}
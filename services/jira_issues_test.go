@@ -0,0 +1,166 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/models"
+)
+
+func TestCreateTicket(t *testing.T) {
+	var request models.JiraCreateIssueRequest
+	mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		if req.Method != "POST" || req.URL.Path != "/rest/api/2/issue" {
+			t.Errorf("Unexpected request: %s %s", req.Method, req.URL.Path)
+		}
+		if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		body := `{"id": "10001", "key": "TEST-124", "self": "https://jira.example.com/rest/api/2/issue/10001"}`
+		return &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewBufferString(body))}, nil
+	})
+
+	config := &models.Config{}
+	config.Jira.BaseURL = "https://jira.example.com"
+
+	service := &JiraServiceImpl{
+		config:   config,
+		client:   mockClient,
+		executor: execCommand,
+	}
+
+	key, err := service.CreateTicket(context.Background(), models.JiraCreateIssueFields{
+		Project:   models.JiraKeyRef{Key: "TEST"},
+		IssueType: models.JiraNameRef{Name: "Task"},
+		Summary:   "Add retries",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if key != "TEST-124" {
+		t.Errorf("Expected key TEST-124, got %s", key)
+	}
+	if request.Fields.Project.Key != "TEST" || request.Fields.IssueType.Name != "Task" || request.Fields.Summary != "Add retries" {
+		t.Errorf("Unexpected request fields: %+v", request.Fields)
+	}
+}
+
+func TestLinkTickets(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		wantErr    bool
+	}{
+		{name: "linked", statusCode: http.StatusCreated},
+		{name: "unknown link type", statusCode: http.StatusNotFound, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var request models.JiraIssueLinkRequest
+			mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+				if req.URL.Path != "/rest/api/2/issueLink" {
+					t.Errorf("Unexpected request path: %s", req.URL.Path)
+				}
+				if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+					t.Errorf("Failed to decode request: %v", err)
+				}
+				return &http.Response{StatusCode: tt.statusCode, Body: io.NopCloser(bytes.NewBufferString(""))}, nil
+			})
+
+			config := &models.Config{}
+			config.Jira.BaseURL = "https://jira.example.com"
+
+			service := &JiraServiceImpl{
+				config:   config,
+				client:   mockClient,
+				executor: execCommand,
+			}
+
+			err := service.LinkTickets(context.Background(), "Relates", "TEST-123", "TEST-124")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got: %v", tt.wantErr, err)
+			}
+			if request.Type.Name != "Relates" || request.InwardIssue.Key != "TEST-123" || request.OutwardIssue.Key != "TEST-124" {
+				t.Errorf("Unexpected link request: %+v", request)
+			}
+		})
+	}
+}
+
+func TestAddRemoteLink(t *testing.T) {
+	var link models.JiraRemoteLink
+	mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		if req.Method != "POST" || req.URL.Path != "/rest/api/2/issue/TEST-1/remotelink" {
+			t.Errorf("Unexpected request: %s %s", req.Method, req.URL.Path)
+		}
+		if err := json.NewDecoder(req.Body).Decode(&link); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		return &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewBufferString(`{"id": 10000}`))}, nil
+	})
+
+	config := &models.Config{}
+	config.Jira.BaseURL = "https://jira.example.com"
+	service := &JiraServiceImpl{config: config, client: mockClient, executor: execCommand}
+
+	prURL := "https://github.com/example/repo/pull/7"
+	if err := service.AddRemoteLink(context.Background(), "TEST-1", pullRequestRemoteLink(prURL, "TEST-1: Add CSV export", true)); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if link.GlobalID != "github-pr="+prURL || link.Object.URL != prURL || link.Object.Title != "TEST-1: Add CSV export" {
+		t.Errorf("Unexpected remote link: %+v", link)
+	}
+	if link.Object.Status == nil || !link.Object.Status.Resolved || link.Object.Status.Icon.URL16x16 != prMergedIconURL {
+		t.Errorf("Expected a resolved status with the merged icon, got %+v", link.Object.Status)
+	}
+}
+
+func TestAddWorklog(t *testing.T) {
+	var worklog models.JiraWorklog
+	var authorization string
+	mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		if req.Method != "POST" || req.URL.Path != "/rest/api/2/issue/TEST-1/worklog" {
+			t.Errorf("Unexpected request: %s %s", req.Method, req.URL.Path)
+		}
+		authorization = req.Header.Get("Authorization")
+		if err := json.NewDecoder(req.Body).Decode(&worklog); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		return &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewBufferString(`{"id": "10000"}`))}, nil
+	})
+
+	config := &models.Config{}
+	config.Jira.BaseURL = "https://jira.example.com"
+	config.Jira.APIToken = "bot-token"
+	service := &JiraServiceImpl{config: config, client: mockClient, executor: execCommand}
+
+	started := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	entry := ticketWorklog("Automated work", started, started.Add(150*time.Second))
+	if err := service.AddWorklog(context.Background(), "TEST-1", entry); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if worklog.TimeSpentSeconds != 180 || worklog.Started != "2024-01-01T09:00:00.000+0000" || worklog.Comment != "Automated work" {
+		t.Errorf("Expected 2.5 minutes rounded up to 3, got %+v", worklog)
+	}
+	if authorization != "Bearer bot-token" {
+		t.Errorf("Expected the bot's token, got %q", authorization)
+	}
+
+	// A worklog account logs the work under its own name
+	config.Jira.Worklog.APIToken = "worklog-token"
+	if err := service.AddWorklog(context.Background(), "TEST-1", ticketWorklog("", started, started)); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if authorization != "Bearer worklog-token" {
+		t.Errorf("Expected the worklog account's token, got %q", authorization)
+	}
+	if worklog.TimeSpentSeconds != 60 {
+		t.Errorf("Expected at least a minute to be logged, got %d seconds", worklog.TimeSpentSeconds)
+	}
+}
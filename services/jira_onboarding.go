@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// JiraOnboarder checks that a Jira project has the statuses, labels and fields the configuration refers to
+type JiraOnboarder interface {
+	// Onboard checks the project's setup, creating the pull request field if asked to and missing
+	Onboard(ctx context.Context, projectKey string, createField bool) (*models.OnboardingReport, error)
+}
+
+// JiraOnboarderImpl implements the JiraOnboarder interface
+type JiraOnboarderImpl struct {
+	jiraService JiraService
+	config      *models.Config
+	logger      *zap.Logger
+}
+
+// NewJiraOnboarder creates a new JiraOnboarder
+func NewJiraOnboarder(jiraService JiraService, config *models.Config, logger *zap.Logger) JiraOnboarder {
+	return &JiraOnboarderImpl{
+		jiraService: jiraService,
+		config:      config,
+		logger:      logger,
+	}
+}
+
+// onboardingItem is a status or label the configuration refers to
+type onboardingItem struct {
+	source string
+	name   string
+}
+
+// Onboard checks the project's setup, creating the pull request field if asked to and missing. Missing workflow
+// statuses are only reported, since adding them to a workflow needs a Jira administrator.
+func (o *JiraOnboarderImpl) Onboard(ctx context.Context, projectKey string, createField bool) (*models.OnboardingReport, error) {
+	report := &models.OnboardingReport{Project: projectKey}
+
+	statusChecks, err := o.checkStatuses(ctx, projectKey)
+	if err != nil {
+		return nil, err
+	}
+	report.Checks = append(report.Checks, statusChecks...)
+
+	labelChecks, err := o.checkLabels(ctx, projectKey)
+	if err != nil {
+		return nil, err
+	}
+	report.Checks = append(report.Checks, labelChecks...)
+
+	if o.config.Jira.GitPullRequestFieldName != "" {
+		fieldCheck, err := o.checkPullRequestField(ctx, createField)
+		if err != nil {
+			return nil, err
+		}
+		report.Checks = append(report.Checks, fieldCheck)
+	}
+	return report, nil
+}
+
+// checkStatuses checks that the project's workflows have the configured statuses
+func (o *JiraOnboarderImpl) checkStatuses(ctx context.Context, projectKey string) ([]models.OnboardingCheck, error) {
+	transitions := o.config.Jira.StatusTransitions
+	items := []onboardingItem{
+		{"jira.status_transitions.todo", transitions.Todo},
+		{"jira.status_transitions.in_progress", transitions.InProgress},
+		{"jira.status_transitions.in_review", transitions.InReview},
+	}
+	if transitions.Done != "" {
+		items = append(items, onboardingItem{"jira.status_transitions.done", transitions.Done})
+	}
+	if transitions.HandedOff != "" {
+		items = append(items, onboardingItem{"jira.status_transitions.handed_off", transitions.HandedOff})
+	}
+	if transitions.Closed != "" {
+		items = append(items, onboardingItem{"jira.status_transitions.closed", transitions.Closed})
+	}
+
+	statuses, err := o.jiraService.GetProjectStatuses(ctx, projectKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statuses of project %s: %w", projectKey, err)
+	}
+	existing := make(map[string]bool, len(statuses))
+	for _, status := range statuses {
+		existing[status] = true
+	}
+
+	var checks []models.OnboardingCheck
+	for _, item := range items {
+		check := models.OnboardingCheck{Kind: "status", Name: item.name, Source: item.source, Result: models.OnboardingResultOK}
+		if !existing[item.name] {
+			check.Result = models.OnboardingResultMissing
+			check.Detail = "add the status to the project's workflow"
+			o.logger.Warn("Status missing from project workflow", zap.String("project", projectKey), zap.String("status", item.name))
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// checkLabels checks whether the labels the application adds to tickets are used in the project. Jira has no labels
+// of their own; a label comes into existence when first added to a ticket, so unused labels need no action.
+func (o *JiraOnboarderImpl) checkLabels(ctx context.Context, projectKey string) ([]models.OnboardingCheck, error) {
+	var items []onboardingItem
+	if o.config.Jira.FollowUps.Enabled {
+		for _, label := range o.config.Jira.FollowUps.Labels {
+			items = append(items, onboardingItem{"jira.follow_ups.labels", label})
+		}
+	}
+	if o.config.Janitor.Enabled && !o.config.Janitor.Requeue {
+		items = append(items, onboardingItem{"janitor.label", o.config.Janitor.Label})
+	}
+
+	var checks []models.OnboardingCheck
+	for _, item := range items {
+		jql := fmt.Sprintf(`project = "%s" AND labels = "%s"`, projectKey, item.name)
+		searchResponse, err := o.jiraService.SearchTickets(ctx, jql)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search for label %s: %w", item.name, err)
+		}
+		check := models.OnboardingCheck{Kind: "label", Name: item.name, Source: item.source, Result: models.OnboardingResultOK}
+		if len(searchResponse.Issues) == 0 {
+			check.Result = models.OnboardingResultUnused
+			check.Detail = "created when first added to a ticket"
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// checkPullRequestField checks that the field pull requests are linked in exists, creating it as a URL field if asked to
+func (o *JiraOnboarderImpl) checkPullRequestField(ctx context.Context, create bool) (models.OnboardingCheck, error) {
+	name := o.config.Jira.GitPullRequestFieldName
+	check := models.OnboardingCheck{Kind: "field", Name: name, Source: "jira.git_pull_request_field_name", Result: models.OnboardingResultOK}
+
+	fieldID, err := o.jiraService.GetFieldIDByName(ctx, name)
+	if err == nil {
+		check.Detail = fieldID
+		return check, nil
+	}
+	if !errors.Is(err, ErrJiraFieldNotFound) {
+		return check, fmt.Errorf("failed to look up field %s: %w", name, err)
+	}
+
+	if !create {
+		check.Result = models.OnboardingResultMissing
+		check.Detail = "create a URL custom field, or rerun with -create-field"
+		return check, nil
+	}
+	fieldID, err = o.jiraService.CreateCustomField(ctx, name, JiraURLFieldType)
+	if err != nil {
+		return check, fmt.Errorf("failed to create field %s: %w", name, err)
+	}
+	o.logger.Info("Created pull request field", zap.String("field", name), zap.String("field_id", fieldID))
+	check.Result = models.OnboardingResultCreated
+	check.Detail = fmt.Sprintf("%s; add it to the project's screens", fieldID)
+	return check, nil
+}
+
+// WriteOnboardingReport writes an onboarding report as a human readable table
+func WriteOnboardingReport(w io.Writer, report *models.OnboardingReport) error {
+	fmt.Fprintf(w, "Project: %s\n\n", report.Project)
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "KIND\tNAME\tCONFIGURED IN\tRESULT\tDETAIL")
+	for _, check := range report.Checks {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", check.Kind, check.Name, check.Source, check.Result, check.Detail)
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	if report.Ready() {
+		fmt.Fprintln(w, "\nThe project is ready.")
+	} else {
+		fmt.Fprintln(w, "\nThe project is missing settings the application depends on.")
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// newOnboardingTestConfig creates a configuration referring to statuses, labels and the pull request field
+func newOnboardingTestConfig() *models.Config {
+	config := &models.Config{}
+	config.Jira.StatusTransitions.Todo = "To Do"
+	config.Jira.StatusTransitions.InProgress = "In Progress"
+	config.Jira.StatusTransitions.InReview = "Code Review"
+	config.Jira.StatusTransitions.Done = "Done"
+	config.Jira.GitPullRequestFieldName = "Git Pull Request"
+	config.Jira.FollowUps.Enabled = true
+	config.Jira.FollowUps.Labels = []string{"ai-follow-up"}
+	config.Janitor.Enabled = true
+	config.Janitor.Label = "ai-stuck"
+	return config
+}
+
+func TestJiraOnboarder_Onboard(t *testing.T) {
+	tests := []struct {
+		name        string
+		createField bool
+		wantField   models.OnboardingResult
+		wantCreated bool
+	}{
+		{name: "missing field is reported", wantField: models.OnboardingResultMissing},
+		{name: "missing field is created", createField: true, wantField: models.OnboardingResultCreated, wantCreated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created []string
+			jiraService := &mocks.MockJiraService{
+				GetProjectStatusesFunc: func(projectKey string) ([]string, error) {
+					return []string{"To Do", "In Progress", "Done"}, nil
+				},
+				SearchTicketsFunc: func(jql string) (*models.JiraSearchResponse, error) {
+					if strings.Contains(jql, `labels = "ai-follow-up"`) {
+						return &models.JiraSearchResponse{Issues: []models.JiraIssue{{Key: "TEST-1"}}}, nil
+					}
+					return &models.JiraSearchResponse{}, nil
+				},
+				GetFieldIDByNameFunc: func(fieldName string) (string, error) {
+					return "", fmt.Errorf("%w: no field with name '%s'", ErrJiraFieldNotFound, fieldName)
+				},
+				CreateCustomFieldFunc: func(name, fieldType string) (string, error) {
+					created = append(created, name+"="+fieldType)
+					return "customfield_10100", nil
+				},
+			}
+
+			onboarder := NewJiraOnboarder(jiraService, newOnboardingTestConfig(), zap.NewNop())
+			report, err := onboarder.Onboard(context.Background(), "TEST", tt.createField)
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+
+			results := make(map[string]models.OnboardingResult)
+			for _, check := range report.Checks {
+				results[check.Kind+":"+check.Name] = check.Result
+			}
+			expected := map[string]models.OnboardingResult{
+				"status:To Do":           models.OnboardingResultOK,
+				"status:In Progress":     models.OnboardingResultOK,
+				"status:Code Review":     models.OnboardingResultMissing,
+				"status:Done":            models.OnboardingResultOK,
+				"label:ai-follow-up":     models.OnboardingResultOK,
+				"label:ai-stuck":         models.OnboardingResultUnused,
+				"field:Git Pull Request": tt.wantField,
+			}
+			for key, want := range expected {
+				if results[key] != want {
+					t.Errorf("Expected %s to be %s, got %s", key, want, results[key])
+				}
+			}
+			if len(report.Checks) != len(expected) {
+				t.Errorf("Expected %d checks, got %+v", len(expected), report.Checks)
+			}
+			if report.Ready() {
+				t.Error("Expected the project with a missing status not to be ready")
+			}
+			if (len(created) > 0) != tt.wantCreated {
+				t.Errorf("Expected field creation %v, got %v", tt.wantCreated, created)
+			}
+			if tt.wantCreated && created[0] != "Git Pull Request="+JiraURLFieldType {
+				t.Errorf("Expected a URL field to be created, got %v", created)
+			}
+
+			var output bytes.Buffer
+			if err := WriteOnboardingReport(&output, report); err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if !strings.Contains(output.String(), "jira.status_transitions.in_review") || !strings.Contains(output.String(), "missing settings") {
+				t.Errorf("Expected the report to point at the missing status, got:\n%s", output.String())
+			}
+		})
+	}
+}
+
+func TestJiraOnboarder_FieldLookupFails(t *testing.T) {
+	jiraService := &mocks.MockJiraService{
+		GetFieldIDByNameFunc: func(fieldName string) (string, error) {
+			return "", errors.New("status code: 500")
+		},
+		CreateCustomFieldFunc: func(name, fieldType string) (string, error) {
+			t.Error("Expected no field to be created when the lookup failed")
+			return "", nil
+		},
+	}
+	config := newOnboardingTestConfig()
+	config.Jira.FollowUps.Enabled = false
+	config.Janitor.Enabled = false
+
+	if _, err := NewJiraOnboarder(jiraService, config, zap.NewNop()).Onboard(context.Background(), "TEST", true); err == nil {
+		t.Error("Expected the failed lookup to be returned")
+	}
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Custom field type and searcher of a URL field, such as the field pull requests are linked in
+const (
+	JiraURLFieldType     = "com.atlassian.jira.plugin.system.customfieldtypes:url"
+	jiraURLFieldSearcher = "com.atlassian.jira.plugin.system.customfieldtypes:exacttextsearcher"
+)
+
+// GetProjectProperty returns the value of a project's entity property, or nil if the project doesn't have the property
+func (s *JiraServiceImpl) GetProjectProperty(ctx context.Context, projectKey, propertyKey string) (json.RawMessage, error) {
+	propertyURL := fmt.Sprintf("%s/rest/api/2/project/%s/properties/%s", s.config.Jira.BaseURL, url.PathEscape(projectKey), url.PathEscape(propertyKey))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", propertyURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if err := s.setAuthHeader(req); err != nil {
+		return nil, fmt.Errorf("failed to authenticate request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get property %s of project %s: %s, status code: %d", propertyKey, projectKey, string(body), resp.StatusCode)
+	}
+
+	var property struct {
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&property); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return property.Value, nil
+}
+
+// GetProjectStatuses returns the names of the statuses the workflows of a project's issue types use
+func (s *JiraServiceImpl) GetProjectStatuses(ctx context.Context, projectKey string) ([]string, error) {
+	statusesURL := fmt.Sprintf("%s/rest/api/2/project/%s/statuses", s.config.Jira.BaseURL, url.PathEscape(projectKey))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", statusesURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if err := s.setAuthHeader(req); err != nil {
+		return nil, fmt.Errorf("failed to authenticate request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get project statuses: %s, status code: %d", string(body), resp.StatusCode)
+	}
+
+	var issueTypes []struct {
+		Name     string `json:"name"`
+		Statuses []struct {
+			Name string `json:"name"`
+		} `json:"statuses"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&issueTypes); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	// Issue types often share a workflow, so their statuses repeat
+	var statuses []string
+	seen := make(map[string]bool)
+	for _, issueType := range issueTypes {
+		for _, status := range issueType.Statuses {
+			if !seen[status.Name] {
+				seen[status.Name] = true
+				statuses = append(statuses, status.Name)
+			}
+		}
+	}
+	return statuses, nil
+}
+
+// CreateCustomField creates a global custom field of the given type and returns its ID
+func (s *JiraServiceImpl) CreateCustomField(ctx context.Context, name, fieldType string) (string, error) {
+	fieldURL := fmt.Sprintf("%s/rest/api/2/field", s.config.Jira.BaseURL)
+
+	payload := map[string]string{
+		"name":        name,
+		"description": "Pull request opened for the ticket by the AI issue solver",
+		"type":        fieldType,
+	}
+	if fieldType == JiraURLFieldType {
+		payload["searcherKey"] = jiraURLFieldSearcher
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fieldURL, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if err := s.setAuthHeader(req); err != nil {
+		return "", fmt.Errorf("failed to authenticate request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed to create custom field: %s, status code: %d", string(body), resp.StatusCode)
+	}
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	return created.ID, nil
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+)
+
+func TestGetProjectStatuses(t *testing.T) {
+	mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		if req.Method != "GET" || req.URL.Path != "/rest/api/2/project/TEST/statuses" {
+			t.Errorf("Unexpected request: %s %s", req.Method, req.URL.Path)
+		}
+		body := `[
+			{"name": "Bug", "statuses": [{"name": "To Do"}, {"name": "In Progress"}, {"name": "Done"}]},
+			{"name": "Task", "statuses": [{"name": "To Do"}, {"name": "In Review"}]}
+		]`
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(body))}, nil
+	})
+
+	config := &models.Config{}
+	config.Jira.BaseURL = "https://jira.example.com"
+	service := &JiraServiceImpl{config: config, client: mockClient, executor: execCommand}
+
+	statuses, err := service.GetProjectStatuses(context.Background(), "TEST")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	expected := []string{"To Do", "In Progress", "Done", "In Review"}
+	if !reflect.DeepEqual(statuses, expected) {
+		t.Errorf("Expected the statuses of all issue types once, got %v", statuses)
+	}
+}
+
+func TestGetProjectProperty(t *testing.T) {
+	mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		switch req.URL.Path {
+		case "/rest/api/2/project/PAY/properties/ai.bot.github.repo":
+			body := `{"key": "ai.bot.github.repo", "value": "https://github.com/example/billing.git"}`
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(body))}, nil
+		case "/rest/api/2/project/OPS/properties/ai.bot.github.repo":
+			body := `{"errorMessages": ["The property with key 'ai.bot.github.repo' does not exist."]}`
+			return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(bytes.NewBufferString(body))}, nil
+		}
+		t.Errorf("Unexpected request: %s %s", req.Method, req.URL.Path)
+		return &http.Response{StatusCode: http.StatusBadRequest, Body: io.NopCloser(bytes.NewBufferString(""))}, nil
+	})
+
+	config := &models.Config{}
+	config.Jira.BaseURL = "https://jira.example.com"
+	service := &JiraServiceImpl{config: config, client: mockClient, executor: execCommand}
+
+	value, err := service.GetProjectProperty(context.Background(), "PAY", "ai.bot.github.repo")
+	if err != nil || string(value) != `"https://github.com/example/billing.git"` {
+		t.Errorf("Expected the property's value, got %s, %v", value, err)
+	}
+	value, err = service.GetProjectProperty(context.Background(), "OPS", "ai.bot.github.repo")
+	if err != nil || value != nil {
+		t.Errorf("Expected no value for a project without the property, got %s, %v", value, err)
+	}
+}
+
+func TestCreateCustomField(t *testing.T) {
+	var payload map[string]string
+	mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		if req.Method != "POST" || req.URL.Path != "/rest/api/2/field" {
+			t.Errorf("Unexpected request: %s %s", req.Method, req.URL.Path)
+		}
+		if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		body := `{"id": "customfield_10100", "name": "Git Pull Request"}`
+		return &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(bytes.NewBufferString(body))}, nil
+	})
+
+	config := &models.Config{}
+	config.Jira.BaseURL = "https://jira.example.com"
+	service := &JiraServiceImpl{config: config, client: mockClient, executor: execCommand}
+
+	fieldID, err := service.CreateCustomField(context.Background(), "Git Pull Request", JiraURLFieldType)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if fieldID != "customfield_10100" {
+		t.Errorf("Expected field ID customfield_10100, got %s", fieldID)
+	}
+	if payload["name"] != "Git Pull Request" || payload["type"] != JiraURLFieldType || payload["searcherKey"] != jiraURLFieldSearcher {
+		t.Errorf("Unexpected request payload: %v", payload)
+	}
+}
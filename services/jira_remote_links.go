@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// Icons of pull request remote links: GitHub's logo for the link and its pull request octicons for the status
+const (
+	githubIconURL   = "https://github.githubassets.com/favicons/favicon.png"
+	prOpenIconURL   = "https://raw.githubusercontent.com/primer/octicons/main/icons/git-pull-request-16.svg"
+	prMergedIconURL = "https://raw.githubusercontent.com/primer/octicons/main/icons/git-merge-16.svg"
+)
+
+// pullRequestRemoteLink returns the remote link of a pull request, open or merged. The pull request's URL is its
+// global ID, so linking it again updates the link's status.
+func pullRequestRemoteLink(prURL, title string, merged bool) models.JiraRemoteLink {
+	status := &models.JiraRemoteLinkStatus{Icon: &models.JiraRemoteLinkIcon{URL16x16: prOpenIconURL, Title: "Open"}}
+	if merged {
+		status = &models.JiraRemoteLinkStatus{Resolved: true, Icon: &models.JiraRemoteLinkIcon{URL16x16: prMergedIconURL, Title: "Merged"}}
+	}
+	return models.JiraRemoteLink{
+		GlobalID:     "github-pr=" + prURL,
+		Application:  models.JiraRemoteLinkApp{Type: "com.github", Name: "GitHub"},
+		Relationship: "pull request",
+		Object: models.JiraRemoteLinkObject{
+			URL:    prURL,
+			Title:  title,
+			Icon:   &models.JiraRemoteLinkIcon{URL16x16: githubIconURL, Title: "GitHub"},
+			Status: status,
+		},
+	}
+}
+
+// linkPullRequest adds the pull request to the ticket's links, or updates its status there, unless remote links are
+// disabled. Failures are only logged, since the comment and the pull request field also name the pull request.
+func linkPullRequest(ctx context.Context, jiraService JiraService, config *models.Config, logger *zap.Logger, ticketKey, prURL, title string, merged bool) {
+	if config.Jira.DisableRemoteLinks {
+		return
+	}
+	if err := jiraService.AddRemoteLink(ctx, ticketKey, pullRequestRemoteLink(prURL, title, merged)); err != nil {
+		logger.Error("Failed to link pull request on ticket",
+			zap.String("ticket", ticketKey),
+			zap.String("pr_url", prURL),
+			zap.Error(err))
+	}
+}
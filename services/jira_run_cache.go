@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"jira-ai-issue-solver/models"
+)
+
+// expandedTicketFields is a cached GetTicketWithExpandedFields result
+type expandedTicketFields struct {
+	fields map[string]interface{}
+	names  map[string]string
+}
+
+// jiraRunCache is a JiraService that remembers ticket and field lookups for the duration of one processing run,
+// so every step of the run that needs the ticket shares a single Jira request. Failed lookups aren't cached, and
+// updating a ticket drops its cached lookups.
+type jiraRunCache struct {
+	JiraService
+
+	mu       sync.Mutex
+	tickets  map[string]*models.JiraTicketResponse
+	expanded map[string]expandedTicketFields
+	fieldIDs map[string]string
+}
+
+// newJiraRunCache creates a Jira lookup cache for one run in front of the given service
+func newJiraRunCache(jiraService JiraService) *jiraRunCache {
+	return &jiraRunCache{
+		JiraService: jiraService,
+		tickets:     make(map[string]*models.JiraTicketResponse),
+		expanded:    make(map[string]expandedTicketFields),
+		fieldIDs:    make(map[string]string),
+	}
+}
+
+// GetTicket fetches a ticket from Jira once per run
+func (c *jiraRunCache) GetTicket(ctx context.Context, key string) (*models.JiraTicketResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ticket, ok := c.tickets[key]; ok {
+		return ticket, nil
+	}
+	ticket, err := c.JiraService.GetTicket(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	c.tickets[key] = ticket
+	return ticket, nil
+}
+
+// GetTicketWithExpandedFields fetches a ticket with expanded fields from Jira once per run
+func (c *jiraRunCache) GetTicketWithExpandedFields(ctx context.Context, key string) (map[string]interface{}, map[string]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.expanded[key]; ok {
+		return cached.fields, cached.names, nil
+	}
+	fields, names, err := c.JiraService.GetTicketWithExpandedFields(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	c.expanded[key] = expandedTicketFields{fields: fields, names: names}
+	return fields, names, nil
+}
+
+// GetFieldIDByName resolves a field name to its ID once per run
+func (c *jiraRunCache) GetFieldIDByName(ctx context.Context, fieldName string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if fieldID, ok := c.fieldIDs[fieldName]; ok {
+		return fieldID, nil
+	}
+	fieldID, err := c.JiraService.GetFieldIDByName(ctx, fieldName)
+	if err != nil {
+		return "", err
+	}
+	c.fieldIDs[fieldName] = fieldID
+	return fieldID, nil
+}
+
+// UpdateTicketLabels updates the labels of a ticket and drops its cached lookups
+func (c *jiraRunCache) UpdateTicketLabels(ctx context.Context, key string, addLabels, removeLabels []string) error {
+	defer c.invalidate(key)
+	return c.JiraService.UpdateTicketLabels(ctx, key, addLabels, removeLabels)
+}
+
+// UpdateTicketStatus updates the status of a ticket and drops its cached lookups
+func (c *jiraRunCache) UpdateTicketStatus(ctx context.Context, key string, status string) error {
+	defer c.invalidate(key)
+	return c.JiraService.UpdateTicketStatus(ctx, key, status)
+}
+
+// UpdateTicketField updates a field of a ticket and drops its cached lookups
+func (c *jiraRunCache) UpdateTicketField(ctx context.Context, key string, fieldID string, value interface{}) error {
+	defer c.invalidate(key)
+	return c.JiraService.UpdateTicketField(ctx, key, fieldID, value)
+}
+
+// UpdateTicketFieldByName updates a field of a ticket by field name and drops its cached lookups
+func (c *jiraRunCache) UpdateTicketFieldByName(ctx context.Context, key string, fieldName string, value interface{}) error {
+	defer c.invalidate(key)
+	return c.JiraService.UpdateTicketFieldByName(ctx, key, fieldName, value)
+}
+
+// invalidate drops the cached lookups of a ticket
+func (c *jiraRunCache) invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.tickets, key)
+	delete(c.expanded, key)
+}
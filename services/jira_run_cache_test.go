@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+)
+
+func TestJiraRunCache(t *testing.T) {
+	calls := map[string]int{}
+	failTicket := true
+	jiraService := &mocks.MockJiraService{
+		GetTicketFunc: func(key string) (*models.JiraTicketResponse, error) {
+			calls["GetTicket"]++
+			if failTicket {
+				failTicket = false
+				return nil, errors.New("temporary failure")
+			}
+			return &models.JiraTicketResponse{Key: key}, nil
+		},
+		GetTicketWithExpandedFieldsFunc: func(key string) (map[string]interface{}, map[string]string, error) {
+			calls["GetTicketWithExpandedFields"]++
+			return map[string]interface{}{"customfield_10001": "https://github.com/example/repo/pull/7"}, nil, nil
+		},
+		GetFieldIDByNameFunc: func(fieldName string) (string, error) {
+			calls["GetFieldIDByName"]++
+			return "customfield_10001", nil
+		},
+		UpdateTicketStatusFunc: func(key, status string) error {
+			return nil
+		},
+	}
+	cache := newJiraRunCache(jiraService)
+
+	// Failed lookups are retried
+	if _, err := cache.GetTicket(context.Background(), "TEST-1"); err == nil {
+		t.Fatal("Expected the first lookup to fail")
+	}
+	for i := 0; i < 3; i++ {
+		if ticket, err := cache.GetTicket(context.Background(), "TEST-1"); err != nil || ticket.Key != "TEST-1" {
+			t.Fatalf("Expected the ticket, got %v, %v", ticket, err)
+		}
+		if fields, _, err := cache.GetTicketWithExpandedFields(context.Background(), "TEST-1"); err != nil || fields["customfield_10001"] == nil {
+			t.Fatalf("Expected the expanded fields, got %v, %v", fields, err)
+		}
+		if fieldID, err := cache.GetFieldIDByName(context.Background(), "Git Pull Request"); err != nil || fieldID != "customfield_10001" {
+			t.Fatalf("Expected the field ID, got %q, %v", fieldID, err)
+		}
+	}
+	if calls["GetTicket"] != 2 || calls["GetTicketWithExpandedFields"] != 1 || calls["GetFieldIDByName"] != 1 {
+		t.Errorf("Expected each lookup to be made once, got %v", calls)
+	}
+
+	// Updating the ticket drops its cached lookups, but not the field IDs
+	if err := cache.UpdateTicketStatus(context.Background(), "TEST-1", "In Review"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	cache.GetTicket(context.Background(), "TEST-1")
+	cache.GetTicketWithExpandedFields(context.Background(), "TEST-1")
+	cache.GetFieldIDByName(context.Background(), "Git Pull Request")
+	if calls["GetTicket"] != 3 || calls["GetTicketWithExpandedFields"] != 2 || calls["GetFieldIDByName"] != 1 {
+		t.Errorf("Expected the ticket to be fetched again after the update, got %v", calls)
+	}
+}
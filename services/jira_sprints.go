@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"jira-ai-issue-solver/models"
+)
+
+// GetActiveSprints returns the active sprints of a Jira Software board. A board has few active sprints, so a single
+// page of the Agile API holds them all.
+func (s *JiraServiceImpl) GetActiveSprints(ctx context.Context, boardID int) ([]models.JiraSprint, error) {
+	sprintsURL := fmt.Sprintf("%s/rest/agile/1.0/board/%d/sprint?state=active&maxResults=50", s.config.Jira.BaseURL, boardID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", sprintsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if err := s.setAuthHeader(req); err != nil {
+		return nil, fmt.Errorf("failed to authenticate request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get active sprints of board %d: %s, status code: %d", boardID, string(body), resp.StatusCode)
+	}
+
+	var page struct {
+		Values []models.JiraSprint `json:"values"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return page.Values, nil
+}
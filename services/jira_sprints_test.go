@@ -0,0 +1,32 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+)
+
+func TestGetActiveSprints(t *testing.T) {
+	mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path != "/rest/agile/1.0/board/7/sprint" || req.URL.Query().Get("state") != "active" {
+			t.Errorf("Unexpected request: %s %s", req.Method, req.URL)
+		}
+		body := `{"isLast": true, "values": [{"id": 37, "name": "Sprint 12", "state": "active"}]}`
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(body))}, nil
+	})
+	config := &models.Config{}
+	config.Jira.BaseURL = "https://jira.example.com"
+	service := &JiraServiceImpl{config: config, client: mockClient, executor: execCommand}
+
+	sprints, err := service.GetActiveSprints(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(sprints) != 1 || sprints[0].ID != 37 || sprints[0].Name != "Sprint 12" {
+		t.Errorf("Expected sprint 37, got %+v", sprints)
+	}
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"net/http"
 	"testing"
@@ -123,7 +124,7 @@ func TestGetTicket(t *testing.T) {
 			}
 
 			// Call the method being tested
-			ticket, err := service.GetTicket(tc.key)
+			ticket, err := service.GetTicket(context.Background(), tc.key)
 
 			// Check the results
 			if tc.expectedError && err == nil {
@@ -254,7 +255,7 @@ func TestUpdateTicketLabels(t *testing.T) {
 			}
 
 			// Call the method being tested
-			err := service.UpdateTicketLabels(tc.key, tc.addLabels, tc.removeLabels)
+			err := service.UpdateTicketLabels(context.Background(), tc.key, tc.addLabels, tc.removeLabels)
 
 			// Check the results
 			if tc.expectedError && err == nil {
@@ -266,3 +267,100 @@ func TestUpdateTicketLabels(t *testing.T) {
 		})
 	}
 }
+
+// TestGetComments tests that GetComments follows pagination
+func TestGetComments(t *testing.T) {
+	var requestedURLs []string
+	mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		requestedURLs = append(requestedURLs, req.URL.String())
+		body := `{"startAt": 0, "total": 3, "comments": [{"id": "1", "body": "first"}, {"id": "2", "body": "second"}]}`
+		if req.URL.Query().Get("startAt") == "2" {
+			body = `{"startAt": 2, "total": 3, "comments": [{"id": "3", "body": "third"}]}`
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(body))}, nil
+	})
+
+	config := &models.Config{}
+	config.Jira.BaseURL = "https://jira.example.com"
+
+	service := &JiraServiceImpl{
+		config:   config,
+		client:   mockClient,
+		executor: execCommand,
+	}
+
+	comments, err := service.GetComments(context.Background(), "TEST-123")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(comments) != 3 || comments[2].ID != "3" {
+		t.Errorf("Expected 3 comments across both pages, got %+v", comments)
+	}
+	if len(requestedURLs) != 2 {
+		t.Errorf("Expected 2 requests, got %v", requestedURLs)
+	}
+}
+
+func TestGetCurrentUser(t *testing.T) {
+	mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		if req.Method != "GET" || req.URL.Path != "/rest/api/2/myself" {
+			t.Errorf("Unexpected request: %s %s", req.Method, req.URL.Path)
+		}
+		if req.Header.Get("Authorization") == "" {
+			t.Error("Expected the request to be authenticated")
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"name": "ai-bot", "displayName": "AI Bot"}`)),
+		}, nil
+	})
+
+	config := &models.Config{}
+	config.Jira.BaseURL = "https://jira.example.com"
+	config.Jira.Username = "ai-bot"
+	config.Jira.APIToken = "token"
+	service := &JiraServiceImpl{config: config, client: mockClient, executor: execCommand}
+
+	user, err := service.GetCurrentUser(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if user.Name != "ai-bot" {
+		t.Errorf("Expected user ai-bot, got %+v", user)
+	}
+
+	service.client = NewTestClient(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusUnauthorized, Body: io.NopCloser(bytes.NewBufferString("Unauthorized"))}, nil
+	})
+	if _, err := service.GetCurrentUser(context.Background()); err == nil {
+		t.Error("Expected an error for rejected credentials")
+	}
+}
+
+func TestAddAttachment(t *testing.T) {
+	mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+		if req.Method != "POST" || req.URL.Path != "/rest/api/2/issue/TEST-1/attachments" {
+			t.Errorf("Unexpected request: %s %s", req.Method, req.URL.Path)
+		}
+		if req.Header.Get("X-Atlassian-Token") != "no-check" {
+			t.Error("Expected the X-Atlassian-Token header Jira requires for attachments")
+		}
+		file, header, err := req.FormFile("file")
+		if err != nil {
+			t.Fatalf("Expected a file in the form: %v", err)
+		}
+		content, _ := io.ReadAll(file)
+		if header.Filename != "run.log" || string(content) != "log content" {
+			t.Errorf("Unexpected attachment %s: %q", header.Filename, content)
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(`[{"id": "10001"}]`))}, nil
+	})
+
+	config := &models.Config{}
+	config.Jira.BaseURL = "https://jira.example.com"
+	service := &JiraServiceImpl{config: config, client: mockClient, executor: execCommand}
+
+	if err := service.AddAttachment(context.Background(), "TEST-1", "run.log", []byte("log content")); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+}
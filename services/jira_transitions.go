@@ -0,0 +1,69 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+)
+
+// jiraTransitionField is a field of a transition's screen, as returned with expand=transitions.fields
+type jiraTransitionField struct {
+	Name            string `json:"name"`
+	Required        bool   `json:"required"`
+	HasDefaultValue bool   `json:"hasDefaultValue"`
+}
+
+// transitionFields returns the fields to set with a transition to the status, keyed by field ID. Configured fields
+// may be named by ID or by their name on the transition's screen; fields that aren't on the screen are an error, since
+// Jira refuses to set them.
+func transitionFields(config *models.Config, status string, screen map[string]jiraTransitionField) (map[string]interface{}, error) {
+	settings, ok := config.GetTransitionFields(status)
+	if !ok {
+		return nil, nil
+	}
+
+	fields := make(map[string]interface{})
+	if settings.Resolution != "" {
+		fields["resolution"] = map[string]string{"name": settings.Resolution}
+	}
+	for key, value := range settings.Fields {
+		id, ok := screenFieldID(screen, key)
+		if !ok {
+			return nil, fmt.Errorf("field %s is not on the screen of the transition to %s", key, status)
+		}
+		fields[id] = value
+	}
+	return fields, nil
+}
+
+// screenFieldID returns the ID of the screen field with the given ID or name
+func screenFieldID(screen map[string]jiraTransitionField, key string) (string, bool) {
+	if _, ok := screen[key]; ok {
+		return key, true
+	}
+	for id, field := range screen {
+		if strings.EqualFold(field.Name, key) {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// missingRequiredFields returns the names of the screen's required fields without a default that aren't set, sorted
+func missingRequiredFields(screen map[string]jiraTransitionField, fields map[string]interface{}) []string {
+	var missing []string
+	for id, field := range screen {
+		if _, set := fields[id]; set || !field.Required || field.HasDefaultValue {
+			continue
+		}
+		name := field.Name
+		if name == "" {
+			name = id
+		}
+		missing = append(missing, name)
+	}
+	sort.Strings(missing)
+	return missing
+}
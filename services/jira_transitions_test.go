@@ -0,0 +1,110 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+)
+
+// doneTransitions lists a transition to Done whose screen requires a resolution and fix versions
+const doneTransitions = `{"transitions": [
+	{"id": "21", "name": "Start", "to": {"name": "In Progress"}},
+	{"id": "31", "name": "Resolve", "to": {"name": "Done"}, "fields": {
+		"resolution": {"name": "Resolution", "required": true, "hasDefaultValue": true},
+		"fixVersions": {"name": "Fix Version/s", "required": true},
+		"customfield_10050": {"name": "Release Notes", "required": false}
+	}}
+]}`
+
+func TestUpdateTicketStatus(t *testing.T) {
+	tests := []struct {
+		name            string
+		status          string
+		settings        map[string]models.TransitionFieldsConfig
+		transitionCode  int
+		expectedPayload string
+		expectedError   string
+	}{
+		{
+			name:            "transition without fields",
+			status:          "In Progress",
+			transitionCode:  http.StatusNoContent,
+			expectedPayload: `{"transition":{"id":"21"}}`,
+		},
+		{
+			name:   "resolution and fields by ID and screen name",
+			status: "done",
+			settings: map[string]models.TransitionFieldsConfig{"Done": {
+				Resolution: "Fixed",
+				Fields: map[string]interface{}{
+					"fixVersions":   []interface{}{map[string]interface{}{"name": "2.3"}},
+					"release notes": "Automated fix",
+				},
+			}},
+			transitionCode:  http.StatusNoContent,
+			expectedPayload: `{"fields":{"customfield_10050":"Automated fix","fixVersions":[{"name":"2.3"}],"resolution":{"name":"Fixed"}},"transition":{"id":"31"}}`,
+		},
+		{
+			name:   "field not on the screen",
+			status: "Done",
+			settings: map[string]models.TransitionFieldsConfig{"Done": {
+				Fields: map[string]interface{}{"Story Points": 3},
+			}},
+			expectedError: "field Story Points is not on the screen of the transition to Done",
+		},
+		{
+			name:            "missing required field is named",
+			status:          "Done",
+			settings:        map[string]models.TransitionFieldsConfig{"Done": {Resolution: "Fixed"}},
+			transitionCode:  http.StatusBadRequest,
+			expectedPayload: `{"fields":{"resolution":{"name":"Fixed"}},"transition":{"id":"31"}}`,
+			expectedError:   "the transition to Done requires Fix Version/s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload string
+			mockClient := NewTestClient(func(req *http.Request) (*http.Response, error) {
+				if req.URL.Path != "/rest/api/2/issue/TEST-1/transitions" {
+					t.Errorf("Unexpected request: %s %s", req.Method, req.URL.Path)
+				}
+				if req.Method == "GET" {
+					if req.URL.Query().Get("expand") != "transitions.fields" {
+						t.Errorf("Expected the transitions' fields to be expanded, got %s", req.URL.RawQuery)
+					}
+					return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(doneTransitions))}, nil
+				}
+				var body interface{}
+				if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+					t.Errorf("Failed to decode request: %v", err)
+				}
+				encoded, _ := json.Marshal(body)
+				payload = string(encoded)
+				return &http.Response{StatusCode: tt.transitionCode, Body: io.NopCloser(bytes.NewBufferString(`{"errorMessages": []}`))}, nil
+			})
+
+			config := &models.Config{}
+			config.Jira.BaseURL = "https://jira.example.com"
+			config.Jira.TransitionFields = tt.settings
+			service := &JiraServiceImpl{config: config, client: mockClient, executor: execCommand}
+
+			err := service.UpdateTicketStatus(context.Background(), "TEST-1", tt.status)
+			if tt.expectedError == "" && err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if tt.expectedError != "" && (err == nil || !strings.Contains(err.Error(), tt.expectedError)) {
+				t.Fatalf("Expected error containing %q, got: %v", tt.expectedError, err)
+			}
+			if payload != tt.expectedPayload {
+				t.Errorf("Expected payload %s, got %s", tt.expectedPayload, payload)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// jiraRetryBaseDelay is the first backoff delay of a transient failure; it doubles with every retry
+const jiraRetryBaseDelay = time.Second
+
+// jiraThrottleTransport spaces out Jira API requests to the configured rate and retries requests Jira refused with
+// 429 or failed with 503, honoring Retry-After. While Jira asks to back off, all requests wait, not just the refused one.
+type jiraThrottleTransport struct {
+	base       http.RoundTripper
+	interval   time.Duration // Minimum time between two requests, 0 for no throttling
+	maxRetries int
+	maxWait    time.Duration
+	metrics    Metrics
+	logger     *zap.Logger
+	now        func() time.Time
+	sleep      func(req *http.Request, d time.Duration) error
+
+	mu   sync.Mutex
+	next time.Time // Earliest time the next request may be sent
+}
+
+// newJiraThrottleTransport creates a transport throttling and retrying requests as configured in jira.rate_limit
+func newJiraThrottleTransport(base http.RoundTripper, config *models.Config, metrics Metrics, logger *zap.Logger) *jiraThrottleTransport {
+	var interval time.Duration
+	if rps := config.Jira.RateLimit.RequestsPerSecond; rps > 0 {
+		interval = time.Duration(float64(time.Second) / rps)
+	}
+	return &jiraThrottleTransport{
+		base:       base,
+		interval:   interval,
+		maxRetries: config.Jira.RateLimit.MaxRetries,
+		maxWait:    time.Duration(config.Jira.RateLimit.MaxWaitSeconds) * time.Second,
+		metrics:    metrics,
+		logger:     logger,
+		now:        time.Now,
+		sleep:      sleepContext,
+	}
+}
+
+// RoundTrip sends the request once the rate allows it, retrying it while Jira refuses it or is unavailable. The wait
+// before a retry is taken by throttle, as the back-off moves the next free slot.
+func (t *jiraThrottleTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := t.throttle(req); err != nil {
+			return nil, err
+		}
+
+		attemptReq := req
+		if attempt > 0 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to replay request body: %w", err)
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if err != nil {
+			return nil, err
+		}
+
+		wait, reason := t.retryDelay(resp, attempt)
+		if reason != "" && wait <= t.maxWait {
+			// Jira rate limits per user, so the following requests wait as well
+			t.backOff(wait)
+		}
+
+		// Jira doesn't carry out rate-limited requests, but may have carried out requests it failed with 503
+		canReplay := req.Body == nil || req.GetBody != nil
+		if reason != "rate_limited" && !isIdempotent(req.Method) {
+			canReplay = false
+		}
+		if reason == "" || attempt >= t.maxRetries || wait > t.maxWait || !canReplay || req.Context().Err() != nil {
+			return resp, nil
+		}
+
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		t.logger.Warn("Retrying Jira API request",
+			zap.String("method", req.Method),
+			zap.String("path", req.URL.Path),
+			zap.String("reason", reason),
+			zap.Int("attempt", attempt+1),
+			zap.Duration("wait", wait))
+		t.metrics.IncCounter("jira_api_retries_total", map[string]string{"reason": reason})
+	}
+}
+
+// retryDelay returns how long to wait before retrying and why, or an empty reason if the request shouldn't be retried
+func (t *jiraThrottleTransport) retryDelay(resp *http.Response, attempt int) (time.Duration, string) {
+	var reason string
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		reason = "rate_limited"
+	case http.StatusServiceUnavailable:
+		reason = "unavailable"
+	default:
+		return 0, ""
+	}
+	if wait, ok := parseRetryAfter(resp); ok {
+		return wait, reason
+	}
+	return jiraRetryBaseDelay << attempt, reason
+}
+
+// throttle waits until the request may be sent and reserves the following slot
+func (t *jiraThrottleTransport) throttle(req *http.Request) error {
+	t.mu.Lock()
+	now := t.now()
+	start := t.next
+	if start.Before(now) {
+		start = now
+	}
+	t.next = start.Add(t.interval)
+	t.mu.Unlock()
+
+	if wait := start.Sub(now); wait > 0 {
+		return t.sleep(req, wait)
+	}
+	return nil
+}
+
+// backOff holds all requests back for the wait
+func (t *jiraThrottleTransport) backOff(wait time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if resume := t.now().Add(wait); resume.After(t.next) {
+		t.next = resume
+	}
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// newTestThrottleTransport creates a transport on a fake clock that advances when it sleeps
+func newTestThrottleTransport(requestsPerSecond float64, base RoundTripFunc) (*jiraThrottleTransport, *[]time.Duration, Metrics) {
+	config := &models.Config{}
+	config.Jira.RateLimit.RequestsPerSecond = requestsPerSecond
+	config.Jira.RateLimit.MaxRetries = 3
+	config.Jira.RateLimit.MaxWaitSeconds = 300
+	metrics := NewMetrics()
+
+	now := time.Unix(1720612800, 0)
+	var waits []time.Duration
+	transport := newJiraThrottleTransport(base, config, metrics, zap.NewNop())
+	transport.now = func() time.Time { return now }
+	transport.sleep = func(req *http.Request, d time.Duration) error {
+		waits = append(waits, d)
+		now = now.Add(d)
+		return nil
+	}
+	return transport, &waits, metrics
+}
+
+func TestJiraThrottleTransport_Retries(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		statuses       []int
+		retryAfter     string
+		expectedStatus int
+		expectedCalls  int
+		expectedWaits  []time.Duration
+	}{
+		{
+			name:           "rate limited requests honor Retry-After",
+			method:         http.MethodPost,
+			statuses:       []int{http.StatusTooManyRequests, http.StatusCreated},
+			retryAfter:     "5",
+			expectedStatus: http.StatusCreated,
+			expectedCalls:  2,
+			expectedWaits:  []time.Duration{5 * time.Second},
+		},
+		{
+			name:           "rate limited requests without Retry-After back off",
+			method:         http.MethodGet,
+			statuses:       []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK},
+			expectedStatus: http.StatusOK,
+			expectedCalls:  3,
+			expectedWaits:  []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name:           "unavailable idempotent requests are retried",
+			method:         http.MethodPut,
+			statuses:       []int{http.StatusServiceUnavailable, http.StatusNoContent},
+			expectedStatus: http.StatusNoContent,
+			expectedCalls:  2,
+			expectedWaits:  []time.Duration{time.Second},
+		},
+		{
+			name:           "unavailable non-idempotent requests aren't retried",
+			method:         http.MethodPost,
+			statuses:       []int{http.StatusServiceUnavailable},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedCalls:  1,
+		},
+		{
+			name:           "Retry-After beyond the maximum wait fails",
+			method:         http.MethodGet,
+			statuses:       []int{http.StatusTooManyRequests},
+			retryAfter:     "3600",
+			expectedStatus: http.StatusTooManyRequests,
+			expectedCalls:  1,
+		},
+		{
+			name:           "retries are limited",
+			method:         http.MethodGet,
+			statuses:       []int{http.StatusTooManyRequests},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedCalls:  4,
+			expectedWaits:  []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			transport, waits, metrics := newTestThrottleTransport(-1, func(req *http.Request) (*http.Response, error) {
+				if req.Body != nil {
+					if body, _ := io.ReadAll(req.Body); string(body) != `{"body":"x"}` {
+						t.Errorf("Expected every attempt to send the body, got %q", body)
+					}
+				}
+				status := tt.statuses[min(calls, len(tt.statuses)-1)]
+				calls++
+				header := make(http.Header)
+				if status == http.StatusTooManyRequests && tt.retryAfter != "" {
+					header.Set("Retry-After", tt.retryAfter)
+				}
+				return &http.Response{StatusCode: status, Header: header, Body: http.NoBody}, nil
+			})
+
+			req, _ := http.NewRequest(tt.method, "https://jira.example.com/rest/api/2/issue/TEST-1", bytes.NewBufferString(`{"body":"x"}`))
+			resp, err := (&http.Client{Transport: transport}).Do(req)
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus || calls != tt.expectedCalls {
+				t.Errorf("Expected status %d after %d calls, got %d after %d", tt.expectedStatus, tt.expectedCalls, resp.StatusCode, calls)
+			}
+			if len(*waits) != len(tt.expectedWaits) {
+				t.Fatalf("Expected waits %v, got %v", tt.expectedWaits, *waits)
+			}
+			for i, wait := range tt.expectedWaits {
+				if (*waits)[i] != wait {
+					t.Errorf("Expected waits %v, got %v", tt.expectedWaits, *waits)
+				}
+			}
+
+			var out strings.Builder
+			metrics.WritePrometheus(&out)
+			if retries := len(tt.expectedWaits); retries > 0 && !strings.Contains(out.String(), "jira_api_retries_total") {
+				t.Errorf("Expected retries to be counted, got:\n%s", out.String())
+			}
+		})
+	}
+}
+
+func TestJiraThrottleTransport_Throttle(t *testing.T) {
+	transport, waits, _ := newTestThrottleTransport(4, func(req *http.Request) (*http.Response, error) {
+		header := make(http.Header)
+		if req.URL.Path == "/limited" {
+			header.Set("Retry-After", "10")
+			return &http.Response{StatusCode: http.StatusTooManyRequests, Header: header, Body: http.NoBody}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Header: header, Body: http.NoBody}, nil
+	})
+	transport.maxRetries = 0
+	client := &http.Client{Transport: transport}
+
+	get := func(path string) {
+		t.Helper()
+		resp, err := client.Get("https://jira.example.com" + path)
+		if err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	// Requests are spaced out to four per second
+	get("/a")
+	get("/b")
+	get("/c")
+	if len(*waits) != 2 || (*waits)[0] != 250*time.Millisecond || (*waits)[1] != 250*time.Millisecond {
+		t.Fatalf("Expected requests to be spaced 250ms apart, got %v", *waits)
+	}
+
+	// A Retry-After holds back every following request, even when the refused one isn't retried
+	*waits = nil
+	get("/limited")
+	get("/d")
+	if len(*waits) != 2 || (*waits)[0] != 250*time.Millisecond || (*waits)[1] != 10*time.Second {
+		t.Errorf("Expected the next request to wait for the Retry-After, got %v", *waits)
+	}
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// ticketWorklog returns the worklog of work started at the given time and finished now. Jira tracks whole minutes,
+// so the time spent is rounded up to a minute.
+func ticketWorklog(comment string, started, finished time.Time) models.JiraWorklog {
+	minutes := int((finished.Sub(started) + time.Minute - 1) / time.Minute)
+	if minutes < 1 {
+		minutes = 1
+	}
+	return models.JiraWorklog{
+		Started:          started.Format(models.JiraWorklogTimeFormat),
+		TimeSpentSeconds: minutes * 60,
+		Comment:          comment,
+	}
+}
+
+// logWork logs the time since started on the ticket when worklogs are enabled. Failures are only logged, since the
+// work itself is done.
+func logWork(ctx context.Context, jiraService JiraService, config *models.Config, logger *zap.Logger, ticketKey string, started time.Time) {
+	if !config.Jira.Worklog.Enabled {
+		return
+	}
+	worklog := ticketWorklog(config.Jira.Worklog.Comment, started, time.Now())
+	if err := jiraService.AddWorklog(ctx, ticketKey, worklog); err != nil {
+		logger.Error("Failed to log work on ticket",
+			zap.String("ticket", ticketKey),
+			zap.Int("time_spent_seconds", worklog.TimeSpentSeconds),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestPRReviewProcessor_FeedbackLogsWork(t *testing.T) {
+	config := newBotConfig("ai-bot")
+	config.Jira.Worklog.Comment = "Automated work"
+
+	var worklogs []models.JiraWorklog
+	jiraService := &mocks.MockJiraService{
+		AddWorklogFunc: func(key string, worklog models.JiraWorklog) error {
+			if key != "TEST-1" {
+				t.Errorf("Expected work logged on TEST-1, got %s", key)
+			}
+			worklogs = append(worklogs, worklog)
+			return nil
+		},
+	}
+	processor := &PRReviewProcessorImpl{
+		jiraService:  jiraService,
+		stateMachine: newTestStateMachine(config),
+		config:       config,
+		logger:       zap.NewNop(),
+	}
+
+	apply := func() error { return errors.New("push rejected") }
+	if err := processor.inFeedbackState(context.Background(), "TEST-1", apply); err == nil {
+		t.Fatal("Expected the error of apply")
+	}
+	if len(worklogs) != 0 {
+		t.Errorf("Expected no worklog while worklogs are disabled, got %+v", worklogs)
+	}
+
+	// Failed runs took time as well
+	config.Jira.Worklog.Enabled = true
+	_ = processor.inFeedbackState(context.Background(), "TEST-1", apply)
+	if len(worklogs) != 1 || worklogs[0].TimeSpentSeconds != 60 || worklogs[0].Comment != "Automated work" {
+		t.Errorf("Expected a minute of work logged, got %+v", worklogs)
+	}
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// defaultLanguageInstructions are the built-in instruction blocks by language or framework name, as detected for the
+// repository map
+var defaultLanguageInstructions = map[string]string{
+	"Go": "Handle every error; wrap it with context using fmt.Errorf(\"...: %w\", err) instead of discarding it or " +
+		"panicking. Accept a context.Context as the first parameter where the surrounding code does. Keep code gofmt " +
+		"formatted and follow the package's naming, without stutter such as user.UserService.",
+	"Python": "Follow PEP 8 and the project's formatter. Add type hints to new functions. Raise specific exceptions " +
+		"instead of bare except clauses, and use context managers for files and connections.",
+	"JavaScript": "Use const and let, never var, and strict equality. Handle rejected promises; prefer async/await " +
+		"over callbacks. Follow the module style (ES modules or CommonJS) the surrounding files use.",
+	"TypeScript": "Keep the code type-safe: don't introduce any, non-null assertions or @ts-ignore to silence the " +
+		"compiler. Prefer narrowing and the project's existing types over new ad-hoc ones.",
+	"Java": "Don't swallow exceptions; rethrow them wrapped with context. Use try-with-resources for closeable " +
+		"resources and Optional instead of returning null from new methods.",
+	"Rust": "Propagate errors with ? and the crate's error types instead of unwrap or expect outside of tests. " +
+		"Keep code rustfmt formatted and free of clippy warnings.",
+	"React": "Follow the rules of hooks: call hooks only at the top level of components and custom hooks, never in " +
+		"conditions or loops, and list every value a useEffect, useMemo or useCallback uses in its dependencies. Give " +
+		"list items stable keys, not array indexes.",
+	"Spring Boot": "Use constructor injection rather than field injection, and keep controllers thin by putting " +
+		"logic in services.",
+	"Django": "Change models only together with a migration, and use the ORM instead of raw SQL.",
+}
+
+// languageInstructions returns the instruction blocks by language or framework name: the built-in ones replaced or
+// removed by the configured ones
+func languageInstructions(configured map[string]string) map[string]string {
+	blocks := make(map[string]string, len(defaultLanguageInstructions)+len(configured))
+	for name, block := range defaultLanguageInstructions {
+		blocks[name] = block
+	}
+	for name, block := range configured {
+		if strings.TrimSpace(block) == "" {
+			delete(blocks, name)
+			continue
+		}
+		blocks[name] = strings.TrimSpace(block)
+	}
+	return blocks
+}
+
+// dominantLanguages returns the languages with at least minShare percent of the source files, the most files first
+func dominantLanguages(languages []LanguageCount, minShare int) []string {
+	total := 0
+	for _, language := range languages {
+		total += language.Files
+	}
+	var dominant []string
+	for _, language := range languages {
+		if language.Files*100 >= minShare*total {
+			dominant = append(dominant, language.Language)
+		}
+	}
+	return dominant
+}
+
+// languageInstructionsPrompt returns the instruction blocks of the dominant languages and the frameworks of the
+// ticket's checkout for the generation prompt, or an empty string if disabled or none apply
+func (p *TicketProcessorImpl) languageInstructionsPrompt(run *TicketRun) string {
+	settings := p.config.LanguageInstructionSettings()
+	if !settings.Enabled {
+		return ""
+	}
+	repoMap, err := buildRepoMap(run.RepoDir, 0, 0)
+	if err != nil {
+		p.logger.Warn("Failed to detect the repository's languages, generating without their instructions",
+			zap.String("ticket", run.Key),
+			zap.String("repo_dir", run.RepoDir),
+			zap.Error(err))
+		return ""
+	}
+
+	blocks := languageInstructions(settings.Blocks)
+	var prompt strings.Builder
+	for _, name := range append(dominantLanguages(repoMap.Languages, settings.MinShare), repoMap.Frameworks...) {
+		if block, ok := blocks[name]; ok {
+			prompt.WriteString("- " + name + ": " + block + "\n")
+		}
+	}
+	if prompt.Len() == 0 {
+		return ""
+	}
+	return "\n\nConventions for the languages and frameworks of this repository:\n" + prompt.String()
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+)
+
+func TestDominantLanguages(t *testing.T) {
+	languages := []LanguageCount{{Language: "TypeScript", Files: 70}, {Language: "Go", Files: 20}, {Language: "Shell", Files: 10}}
+	if got := strings.Join(dominantLanguages(languages, 20), ","); got != "TypeScript,Go" {
+		t.Errorf("dominantLanguages() = %q, want TypeScript,Go", got)
+	}
+	if got := dominantLanguages(nil, 20); len(got) != 0 {
+		t.Errorf("Expected no dominant languages without source files, got %q", got)
+	}
+}
+
+func TestTicketProcessor_LanguageInstructionsPrompt(t *testing.T) {
+	repoDir := t.TempDir()
+	writeRepoMapFiles(t, repoDir, map[string]string{
+		"package.json":  `{"dependencies": {"react": "^18.0.0"}}`,
+		"src/App.tsx":   "export default function App() {}",
+		"src/index.tsx": "import App from './App'",
+		"src/api.ts":    "export {}",
+		"deploy.sh":     "#!/bin/sh",
+		"main.go":       "package main",
+		"lib/a.go":      "package lib",
+		"lib/b.go":      "package lib",
+		"lib/c.go":      "package lib",
+		"lib/d.go":      "package lib",
+		"lib/e.go":      "package lib",
+		"tools/x.py":    "print()",
+	})
+	config := &models.Config{}
+	config.AI.LanguageInstructions.Enabled = true
+	config.AI.LanguageInstructions.MinShare = 20
+	config.AI.LanguageInstructions.Blocks = map[string]string{
+		"TypeScript": "  Use the shared types in src/types.  \n",
+		"Go":         "",
+	}
+	processor := newCheckTestProcessor(t, config, &mocks.MockClaudeService{}, &mocks.MockJiraService{}, &mocks.MockGitHubService{})
+
+	prompt := processor.languageInstructionsPrompt(&TicketRun{Key: "TEST-1", RepoDir: repoDir})
+	if !strings.Contains(prompt, "- TypeScript: Use the shared types in src/types.\n") {
+		t.Errorf("Expected the configured TypeScript block, got %q", prompt)
+	}
+	if !strings.Contains(prompt, "- React: "+defaultLanguageInstructions["React"]) {
+		t.Errorf("Expected the built-in React block, got %q", prompt)
+	}
+	if strings.Contains(prompt, "- Go:") {
+		t.Errorf("Expected the removed Go block to be left out, got %q", prompt)
+	}
+	if strings.Contains(prompt, "- Python:") {
+		t.Errorf("Expected the minor Python files to be left out, got %q", prompt)
+	}
+
+	config.AI.LanguageInstructions.Enabled = false
+	if prompt := processor.languageInstructionsPrompt(&TicketRun{Key: "TEST-1", RepoDir: repoDir}); prompt != "" {
+		t.Errorf("Expected no prompt when disabled, got %q", prompt)
+	}
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Pausable is a background service that can be stopped and started again, such as a scanner
+type Pausable interface {
+	// Start starts the service
+	Start()
+	// Stop stops the service, waiting for its current iteration to finish
+	Stop()
+}
+
+// MaintenanceStatus describes whether the application is in maintenance mode
+type MaintenanceStatus struct {
+	Enabled bool      `json:"enabled"`
+	Reason  string    `json:"reason,omitempty"`
+	Since   time.Time `json:"since,omitempty"`
+}
+
+// MaintenanceService switches the application in and out of maintenance mode. In maintenance mode no new work is
+// picked up, while tickets already being processed finish, so the application can be upgraded safely.
+type MaintenanceService interface {
+	// Enable stops the background services from picking up new work
+	Enable(reason string)
+	// Disable starts the background services again
+	Disable()
+	// IsEnabled returns whether the application is in maintenance mode
+	IsEnabled() bool
+	// Status returns the maintenance mode status
+	Status() MaintenanceStatus
+	// Paused runs the function with the background services stopped, starting them again afterwards unless in
+	// maintenance mode
+	Paused(fn func())
+}
+
+// MaintenanceServiceImpl implements the MaintenanceService interface by stopping and starting the background services
+type MaintenanceServiceImpl struct {
+	services []Pausable
+	logger   *zap.Logger
+
+	mu     sync.Mutex
+	status MaintenanceStatus
+}
+
+// NewMaintenanceService creates a new MaintenanceService pausing the given services. The services are expected to
+// be started by the caller unless maintenance mode is enabled right away.
+func NewMaintenanceService(services []Pausable, logger *zap.Logger) MaintenanceService {
+	return &MaintenanceServiceImpl{
+		services: services,
+		logger:   logger,
+	}
+}
+
+// Enable stops the background services from picking up new work
+func (s *MaintenanceServiceImpl) Enable(reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status.Enabled {
+		return
+	}
+
+	s.logger.Info("Entering maintenance mode", zap.String("reason", reason))
+	for _, service := range s.services {
+		service.Stop()
+	}
+	s.status = MaintenanceStatus{Enabled: true, Reason: reason, Since: time.Now().UTC()}
+}
+
+// Disable starts the background services again
+func (s *MaintenanceServiceImpl) Disable() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.status.Enabled {
+		return
+	}
+
+	s.logger.Info("Leaving maintenance mode")
+	for _, service := range s.services {
+		service.Start()
+	}
+	s.status = MaintenanceStatus{}
+}
+
+// IsEnabled returns whether the application is in maintenance mode
+func (s *MaintenanceServiceImpl) IsEnabled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status.Enabled
+}
+
+// Status returns the maintenance mode status
+func (s *MaintenanceServiceImpl) Status() MaintenanceStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// Paused runs the function with the background services stopped, starting them again afterwards unless in
+// maintenance mode
+func (s *MaintenanceServiceImpl) Paused(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status.Enabled {
+		fn()
+		return
+	}
+
+	for _, service := range s.services {
+		service.Stop()
+	}
+	fn()
+	for _, service := range s.services {
+		service.Start()
+	}
+}
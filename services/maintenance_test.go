@@ -0,0 +1,55 @@
+package services
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// fakePausable records how often it was started and stopped
+type fakePausable struct {
+	starts, stops int
+}
+
+func (f *fakePausable) Start() { f.starts++ }
+func (f *fakePausable) Stop()  { f.stops++ }
+
+func TestMaintenanceService(t *testing.T) {
+	scanner := &fakePausable{}
+	maintenance := NewMaintenanceService([]Pausable{scanner}, zap.NewNop())
+
+	maintenance.Enable("upgrade")
+	maintenance.Enable("again")
+	if !maintenance.IsEnabled() || scanner.stops != 1 {
+		t.Errorf("Expected the services to be stopped once, got %d stops", scanner.stops)
+	}
+	if status := maintenance.Status(); status.Reason != "upgrade" || status.Since.IsZero() {
+		t.Errorf("Expected the first reason to be kept, got %+v", status)
+	}
+
+	maintenance.Disable()
+	maintenance.Disable()
+	if maintenance.IsEnabled() || scanner.starts != 1 {
+		t.Errorf("Expected the services to be started once, got %d starts", scanner.starts)
+	}
+}
+
+func TestMaintenanceService_Paused(t *testing.T) {
+	scanner := &fakePausable{}
+	maintenance := NewMaintenanceService([]Pausable{scanner}, zap.NewNop())
+
+	maintenance.Paused(func() {
+		if scanner.stops != 1 || scanner.starts != 0 {
+			t.Errorf("Expected the services to be stopped while paused, got %d stops and %d starts", scanner.stops, scanner.starts)
+		}
+	})
+	if scanner.starts != 1 {
+		t.Errorf("Expected the services to be started again, got %d starts", scanner.starts)
+	}
+
+	maintenance.Enable("upgrade")
+	maintenance.Paused(func() {})
+	if scanner.stops != 2 || scanner.starts != 1 {
+		t.Errorf("Expected the services to stay stopped in maintenance mode, got %d stops and %d starts", scanner.stops, scanner.starts)
+	}
+}
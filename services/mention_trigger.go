@@ -0,0 +1,39 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+)
+
+// mentionsUser checks if text @mentions the user. GitHub App bots are mentioned without their "[bot]" suffix.
+func mentionsUser(text, username string) bool {
+	username = strings.TrimSuffix(username, "[bot]")
+	if username == "" {
+		return false
+	}
+	pattern := regexp.MustCompile(`(?i)(?:^|[^\w-])@` + regexp.QuoteMeta(username) + `(?:[^\w-]|$)`)
+	return pattern.MatchString(text)
+}
+
+// triggersFeedback checks if a comment or review body may trigger feedback processing.
+// In mention mode only bodies that @mention the bot do; otherwise any body does.
+func (p *PRReviewProcessorImpl) triggersFeedback(body string) bool {
+	if p.config.GitHub.FeedbackTrigger != models.FeedbackTriggerMention {
+		return true
+	}
+	return mentionsUser(body, p.config.GitHub.BotUsername)
+}
+
+// feedbackStatus labels feedback for the AI as new, handled, or context that doesn't address the bot
+func (p *PRReviewProcessorImpl) feedbackStatus(body string, isNew bool) string {
+	switch {
+	case !isNew:
+		return "✅ HANDLED"
+	case !p.triggersFeedback(body):
+		return "💬 CONTEXT ONLY"
+	default:
+		return "🔄 NEW"
+	}
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/models"
+)
+
+func TestMentionsUser(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		username string
+		want     bool
+	}{
+		{name: "mention", text: "@ai-bot please fix", username: "ai-bot", want: true},
+		{name: "mention mid-sentence", text: "Could you (@AI-Bot) rename this?", username: "ai-bot", want: true},
+		{name: "app bot", text: "@my-app fix the typo", username: "my-app[bot]", want: true},
+		{name: "no mention", text: "ai-bot please fix", username: "ai-bot", want: false},
+		{name: "longer username", text: "@ai-bot-2 please fix", username: "ai-bot", want: false},
+		{name: "email address", text: "mail me at me@ai-bot.com", username: "ai-bot", want: false},
+		{name: "empty username", text: "@ please fix", username: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mentionsUser(tt.text, tt.username); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPRReviewProcessor_MentionTrigger(t *testing.T) {
+	config := newBotConfig("ai-bot")
+	config.GitHub.FeedbackTrigger = models.FeedbackTriggerMention
+	processor := &PRReviewProcessorImpl{config: config}
+
+	lastProcessed := time.Date(2024, 7, 10, 12, 0, 0, 0, time.UTC)
+	watermark := models.FeedbackWatermark{ProcessedAt: lastProcessed}
+	newTime := lastProcessed.Add(time.Hour)
+
+	reviews := []models.GitHubReview{
+		{User: models.GitHubUser{Login: "reviewer1"}, Body: "Looks off", State: "CHANGES_REQUESTED", SubmittedAt: newTime},
+		{User: models.GitHubUser{Login: "reviewer2"}, Body: "@ai-bot please rename the helper", State: "COMMENTED", SubmittedAt: newTime},
+	}
+	comments := []models.GitHubPRComment{
+		{User: models.GitHubUser{Login: "commenter1"}, Body: "Nice work", CreatedAt: newTime},
+		{User: models.GitHubUser{Login: "commenter2"}, Body: "@ai-bot add a test", CreatedAt: newTime},
+	}
+
+	filteredReviews := processor.filterReviews(reviews, watermark)
+	if len(filteredReviews) != 1 || filteredReviews[0].User.Login != "reviewer2" {
+		t.Errorf("Expected only the review mentioning the bot to trigger, got %+v", filteredReviews)
+	}
+	if processor.hasRequestChangesReviews(filteredReviews) {
+		t.Error("Expected a change request without a mention not to trigger")
+	}
+	filteredComments := processor.filterComments(comments, watermark.IsNewComment)
+	if len(filteredComments) != 1 || filteredComments[0].User.Login != "commenter2" {
+		t.Errorf("Expected only the comment mentioning the bot to trigger, got %+v", filteredComments)
+	}
+
+	feedback := processor.collectFeedback(reviews, comments, watermark)
+	if !strings.Contains(feedback, "reviewer1 (CHANGES_REQUESTED) - 💬 CONTEXT ONLY") {
+		t.Errorf("Expected feedback not addressed to the bot to be marked as context, got:\n%s", feedback)
+	}
+	if !strings.Contains(feedback, "reviewer2 (COMMENTED) - 🔄 NEW") {
+		t.Errorf("Expected feedback addressed to the bot to be marked as new, got:\n%s", feedback)
+	}
+
+	prompt := processor.generateFeedbackPrompt(&models.GitHubPRDetails{}, feedback)
+	if !strings.Contains(prompt, "CONTEXT ONLY") {
+		t.Error("Expected the prompt to explain context-only feedback")
+	}
+}
+
+func TestPRReviewProcessor_AnyTrigger(t *testing.T) {
+	processor := &PRReviewProcessorImpl{config: newBotConfig("ai-bot")}
+
+	lastProcessed := time.Date(2024, 7, 10, 12, 0, 0, 0, time.UTC)
+	comments := []models.GitHubPRComment{
+		{User: models.GitHubUser{Login: "commenter1"}, Body: "Nice work", CreatedAt: lastProcessed.Add(time.Hour)},
+	}
+	watermark := models.FeedbackWatermark{ProcessedAt: lastProcessed}
+
+	if filtered := processor.filterComments(comments, watermark.IsNewComment); len(filtered) != 1 {
+		t.Errorf("Expected any comment to trigger by default, got %+v", filtered)
+	}
+	if feedback := processor.collectFeedback(nil, comments, watermark); !strings.Contains(feedback, "🔄 NEW") {
+		t.Errorf("Expected the comment to be marked as new, got:\n%s", feedback)
+	}
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Metrics records operational counters and gauges
+type Metrics interface {
+	// IncCounter increments a counter by one
+	IncCounter(name string, labels map[string]string)
+	// AddCounter increases a counter by the given value, such as seconds spent waiting
+	AddCounter(name string, labels map[string]string, value float64)
+	// SetGauge sets a gauge to the given value
+	SetGauge(name string, labels map[string]string, value float64)
+	// WritePrometheus writes all metrics in the Prometheus text exposition format
+	WritePrometheus(w io.Writer) error
+}
+
+// MetricsImpl is an in-memory Metrics implementation
+type MetricsImpl struct {
+	mu       sync.Mutex
+	counters map[string]map[string]float64 // name -> serialized labels -> value
+	gauges   map[string]map[string]float64
+}
+
+// NewMetrics creates a new in-memory Metrics registry
+func NewMetrics() Metrics {
+	return &MetricsImpl{
+		counters: make(map[string]map[string]float64),
+		gauges:   make(map[string]map[string]float64),
+	}
+}
+
+// IncCounter increments a counter by one
+func (m *MetricsImpl) IncCounter(name string, labels map[string]string) {
+	m.AddCounter(name, labels, 1)
+}
+
+// AddCounter increases a counter by the given value
+func (m *MetricsImpl) AddCounter(name string, labels map[string]string, value float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	series, ok := m.counters[name]
+	if !ok {
+		series = make(map[string]float64)
+		m.counters[name] = series
+	}
+	series[formatLabels(labels)] += value
+}
+
+// SetGauge sets a gauge to the given value
+func (m *MetricsImpl) SetGauge(name string, labels map[string]string, value float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	series, ok := m.gauges[name]
+	if !ok {
+		series = make(map[string]float64)
+		m.gauges[name] = series
+	}
+	series[formatLabels(labels)] = value
+}
+
+// WritePrometheus writes all metrics in the Prometheus text exposition format
+func (m *MetricsImpl) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := writeMetricFamily(w, "counter", m.counters); err != nil {
+		return err
+	}
+	return writeMetricFamily(w, "gauge", m.gauges)
+}
+
+// writeMetricFamily writes metrics of one type sorted by name and labels so the output is stable
+func writeMetricFamily(w io.Writer, metricType string, families map[string]map[string]float64) error {
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := fmt.Fprintf(w, "# TYPE %s %s\n", name, metricType); err != nil {
+			return err
+		}
+		series := families[name]
+		labelSets := make([]string, 0, len(series))
+		for labels := range series {
+			labelSets = append(labelSets, labels)
+		}
+		sort.Strings(labelSets)
+		for _, labels := range labelSets {
+			value := strconv.FormatFloat(series[labels], 'g', -1, 64)
+			if _, err := fmt.Fprintf(w, "%s%s %s\n", name, labels, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// formatLabels serializes labels as {key="value",...} with keys in sorted order
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", key, strconv.Quote(labels[key])))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMetrics_WritePrometheus(t *testing.T) {
+	metrics := NewMetrics()
+	metrics.IncCounter("tickets_processed_total", map[string]string{"result": "success"})
+	metrics.IncCounter("tickets_processed_total", map[string]string{"result": "success"})
+	metrics.IncCounter("tickets_processed_total", map[string]string{"result": "failure"})
+	metrics.SetGauge("queue_depth", nil, 3)
+	metrics.SetGauge("queue_depth", nil, 5)
+
+	var output bytes.Buffer
+	if err := metrics.WritePrometheus(&output); err != nil {
+		t.Fatalf("WritePrometheus() error = %v", err)
+	}
+
+	expected := `# TYPE tickets_processed_total counter
+tickets_processed_total{result="failure"} 1
+tickets_processed_total{result="success"} 2
+# TYPE queue_depth gauge
+queue_depth 5
+`
+	if output.String() != expected {
+		t.Errorf("Unexpected output:\n%s\nwant:\n%s", output.String(), expected)
+	}
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ensureChanges checks that the AI changed the repository. A run without changes is retried with a stronger prompt
+// up to the configured number of times before the ticket fails, so no empty pull request is opened.
+func (p *TicketProcessorImpl) ensureChanges(ctx context.Context, run *TicketRun) error {
+	retries := p.config.GetNoChangeRetries()
+	for attempt := 0; ; attempt++ {
+		changed, err := p.githubService.HasChanges(ctx, run.RepoDir)
+		if err != nil {
+			p.logger.Error("Failed to check for changes",
+				zap.String("ticket", run.Key),
+				zap.String("repo_dir", run.RepoDir),
+				zap.Error(err))
+			p.handleFailure(ctx, run.Key, fmt.Sprintf("Failed to check for changes: %v", err))
+			return err
+		}
+		if changed {
+			return nil
+		}
+
+		if attempt >= retries {
+			p.logger.Warn("AI made no changes", zap.String("ticket", run.Key), zap.Int("attempts", attempt+1))
+			p.handleFailure(ctx, run.Key, fmt.Sprintf("The AI made no changes to the repository in %d attempt(s), so no pull "+
+				"request was created. Please add details to the ticket, such as the affected files, the expected "+
+				"behavior and how to reproduce the issue, then move it back to the todo status.", attempt+1))
+			return fmt.Errorf("AI made no changes to the repository")
+		}
+
+		p.logger.Warn("AI made no changes, retrying with a stronger prompt",
+			zap.String("ticket", run.Key),
+			zap.Int("attempt", attempt+1))
+		prompt := noChangesPrompt(p.generationPrompt(run))
+		started := time.Now()
+		response, err := p.aiService.GenerateCode(ctx, prompt, run.RepoDir)
+		if err != nil {
+			p.logger.Error("Failed to generate code changes",
+				zap.String("ticket", run.Key),
+				zap.String("repo_dir", run.RepoDir),
+				zap.Error(err))
+			p.handleFailure(ctx, run.Key, fmt.Sprintf("Failed to generate code changes: %v", err))
+			return err
+		}
+		p.recordUsage(run, prompt, time.Since(started), response)
+		run.AIResponse = response
+		if err := p.revertProtectedPaths(ctx, run); err != nil {
+			return err
+		}
+	}
+}
+
+// noChangesPrompt generates a prompt for the AI service to retry a ticket it made no changes for
+func noChangesPrompt(ticketPrompt string) string {
+	var prompt strings.Builder
+	prompt.WriteString("A previous attempt at this ticket finished without changing any file in the repository, ")
+	prompt.WriteString("so nothing could be submitted for review.\n\n")
+	prompt.WriteString(ticketPrompt)
+	prompt.WriteString("\n\nIMPORTANT:\n")
+	prompt.WriteString("1. You must edit the files in the repository to implement the ticket; describing the change is not enough\n")
+	prompt.WriteString("2. If the ticket is ambiguous, implement the most reasonable interpretation of it\n")
+	prompt.WriteString("3. Save every edit to disk before finishing, and do not commit; the changes are committed for you\n")
+	return prompt.String()
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+)
+
+func newNoChangesTestTicket() *models.JiraTicketResponse {
+	ticket := &models.JiraTicketResponse{Key: "TEST-1"}
+	ticket.Fields.Summary = "Fix the bug"
+	return ticket
+}
+
+func TestTicketProcessor_EnsureChanges_Retried(t *testing.T) {
+	config := &models.Config{}
+	retries := 1
+	config.AI.NoChangeRetries = &retries
+
+	changed := false
+	githubService := &mocks.MockGitHubService{
+		HasChangesFunc: func(directory string) (bool, error) {
+			return changed, nil
+		},
+	}
+	var prompts []string
+	aiService := &mocks.MockClaudeService{
+		GenerateCodeFunc: func(prompt, dir string) (*models.ClaudeResponse, error) {
+			prompts = append(prompts, prompt)
+			changed = true
+			return &models.ClaudeResponse{}, nil
+		},
+	}
+	processor := newCheckTestProcessor(t, config, aiService, &mocks.MockJiraService{}, githubService)
+
+	run := &TicketRun{Key: "TEST-1", Ticket: newNoChangesTestTicket(), RepoDir: t.TempDir(), GitHistory: "Related commits:\n- abc1234 Fix the parser\n"}
+	if err := processor.ensureChanges(context.Background(), run); err != nil {
+		t.Fatalf("Expected the retried run to pass but got: %v", err)
+	}
+	if len(prompts) != 1 || !strings.Contains(prompts[0], "finished without changing any file") || !strings.Contains(prompts[0], "Fix the bug") {
+		t.Errorf("Expected one stronger prompt for the ticket, got %q", prompts)
+	}
+	if len(prompts) == 1 && !strings.Contains(prompts[0], "abc1234 Fix the parser") {
+		t.Errorf("Expected the retry to keep the context of the generation prompt, got %q", prompts[0])
+	}
+}
+
+func TestTicketProcessor_EnsureChanges_StillEmpty(t *testing.T) {
+	config := &models.Config{}
+	retries := 2
+	config.AI.NoChangeRetries = &retries
+
+	runs := 0
+	aiService := &mocks.MockClaudeService{
+		GenerateCodeFunc: func(prompt, dir string) (*models.ClaudeResponse, error) {
+			runs++
+			return &models.ClaudeResponse{}, nil
+		},
+	}
+	var comments []string
+	jiraService := &mocks.MockJiraService{
+		AddCommentFunc: func(key, comment string) error {
+			comments = append(comments, comment)
+			return nil
+		},
+	}
+	githubService := &mocks.MockGitHubService{
+		HasChangesFunc: func(directory string) (bool, error) {
+			return false, nil
+		},
+	}
+	processor := newCheckTestProcessor(t, config, aiService, jiraService, githubService)
+
+	run := &TicketRun{Key: "TEST-1", Ticket: newNoChangesTestTicket(), RepoDir: t.TempDir()}
+	if err := processor.ensureChanges(context.Background(), run); err == nil {
+		t.Fatal("Expected an error when the AI never changes anything")
+	}
+	if runs != 2 {
+		t.Errorf("Expected 2 retries, got %d", runs)
+	}
+	if len(comments) != 1 || !strings.Contains(comments[0], "no changes to the repository in 3 attempt(s)") {
+		t.Errorf("Expected a failure comment asking for details, got %q", comments)
+	}
+}
+
+func TestTicketProcessor_EnsureChanges_WithoutRetries(t *testing.T) {
+	config := &models.Config{}
+	retries := 0
+	config.AI.NoChangeRetries = &retries
+
+	runs := 0
+	aiService := &mocks.MockClaudeService{
+		GenerateCodeFunc: func(prompt, dir string) (*models.ClaudeResponse, error) {
+			runs++
+			return &models.ClaudeResponse{}, nil
+		},
+	}
+	githubService := &mocks.MockGitHubService{
+		HasChangesFunc: func(directory string) (bool, error) {
+			return false, nil
+		},
+	}
+	processor := newCheckTestProcessor(t, config, aiService, &mocks.MockJiraService{}, githubService)
+
+	run := &TicketRun{Key: "TEST-1", Ticket: newNoChangesTestTicket(), RepoDir: t.TempDir()}
+	if err := processor.ensureChanges(context.Background(), run); err == nil {
+		t.Fatal("Expected an error when the AI changed nothing")
+	}
+	if runs != 0 {
+		t.Errorf("Expected no retries, got %d", runs)
+	}
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// defaultNotificationTemplates are the messages of the events whose route has no template
+var defaultNotificationTemplates = map[models.NotificationEvent]string{
+	models.NotificationPRCreated:        "AI opened pull request {{.PRURL}} for {{.Ticket}} ({{.TicketURL}})",
+	models.NotificationProcessingFailed: "AI failed to process {{.Ticket}} ({{.TicketURL}}): {{.Message}}",
+	models.NotificationBudgetExceeded:   "AI stopped processing {{.Ticket}} ({{.TicketURL}}): {{.Message}}",
+	models.NotificationFeedbackApplied:  "AI pushed fixes for the feedback on {{.PRURL}} ({{.Ticket}})",
+}
+
+// Notifier sends notifications of bot events to chat tools
+type Notifier interface {
+	// Notify sends the notification to every sink routing its event. Failures are logged rather than returned, since
+	// a notification never fails processing.
+	Notify(ctx context.Context, notification models.Notification)
+}
+
+// NotificationSink sends notifications to one chat tool
+type NotificationSink interface {
+	// Name returns the name of the sink in logs
+	Name() string
+	// Send sends the notification, unless the sink doesn't route its event
+	Send(ctx context.Context, notification models.Notification) error
+}
+
+// NotifierImpl implements the Notifier interface by sending to the sinks in the configuration
+type NotifierImpl struct {
+	sinks  []NotificationSink
+	logger *zap.Logger
+}
+
+// NewNotifier creates a new Notifier sending to the configured sinks. Without any, notifications are dropped.
+func NewNotifier(jiraService JiraService, config *models.Config, logger *zap.Logger) Notifier {
+	var sinks []NotificationSink
+	if config.Notifications.Slack.WebhookURL != "" {
+		sinks = append(sinks, NewSlackSink(config, logger))
+	}
+	if config.Notifications.Teams.WebhookURL != "" {
+		sinks = append(sinks, NewTeamsSink(config, logger))
+	}
+	if config.Notifications.Email.Host != "" {
+		sinks = append(sinks, NewEmailSink(jiraService, config, logger))
+	}
+	return &NotifierImpl{sinks: sinks, logger: logger}
+}
+
+// Notify sends the notification to every sink routing its event. Failures are logged rather than returned, since
+// a notification never fails processing.
+func (n *NotifierImpl) Notify(ctx context.Context, notification models.Notification) {
+	for _, sink := range n.sinks {
+		if err := sink.Send(ctx, notification); err != nil {
+			n.logger.Error("Failed to send notification",
+				zap.String("sink", sink.Name()),
+				zap.String("event", string(notification.Event)),
+				zap.String("ticket", notification.Ticket),
+				zap.Error(err))
+		}
+	}
+}
+
+// newNotification creates a notification of an event about a ticket, linking the ticket in Jira
+func newNotification(config *models.Config, event models.NotificationEvent, ticketKey string) models.Notification {
+	return models.Notification{
+		Event:     event,
+		Ticket:    ticketKey,
+		TicketURL: strings.TrimSuffix(config.Jira.BaseURL, "/") + "/browse/" + ticketKey,
+	}
+}
+
+// renderNotification renders the message of a notification with the route's template, or the event's default one
+func renderNotification(route models.NotificationRoute, notification models.Notification) (string, error) {
+	text := route.Template
+	if text == "" {
+		text = defaultNotificationTemplates[notification.Event]
+	}
+	return renderTemplate(string(notification.Event), text, notification)
+}
+
+// renderTemplate renders a Go template of a message with the data
+func renderTemplate(name, text string, data interface{}) (string, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+	var message bytes.Buffer
+	if err := tmpl.Execute(&message, data); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return message.String(), nil
+}
+
+// postWebhook posts a JSON payload to a chat tool's incoming webhook
+func postWebhook(ctx context.Context, client *http.Client, url string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("webhook returned status %d, body: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
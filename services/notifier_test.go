@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// recordingNotifier records the notifications it's asked to send
+type recordingNotifier struct {
+	notifications []models.Notification
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification models.Notification) {
+	n.notifications = append(n.notifications, notification)
+}
+
+// fakeSink records the notifications sent to it and fails with err
+type fakeSink struct {
+	sent []models.Notification
+	err  error
+}
+
+func (s *fakeSink) Name() string { return "fake" }
+
+func (s *fakeSink) Send(ctx context.Context, notification models.Notification) error {
+	s.sent = append(s.sent, notification)
+	return s.err
+}
+
+func TestNotifier_Notify(t *testing.T) {
+	failing := &fakeSink{err: errors.New("webhook is down")}
+	working := &fakeSink{}
+	notifier := &NotifierImpl{sinks: []NotificationSink{failing, working}, logger: zap.NewNop()}
+
+	notifier.Notify(context.Background(), models.Notification{Event: models.NotificationPRCreated, Ticket: "TEST-1"})
+
+	if len(failing.sent) != 1 || len(working.sent) != 1 {
+		t.Errorf("Expected every sink to be sent the notification despite failures, got %d and %d", len(failing.sent), len(working.sent))
+	}
+}
+
+func TestNewNotifier_Sinks(t *testing.T) {
+	config := &models.Config{}
+	if sinks := NewNotifier(nil, config, zap.NewNop()).(*NotifierImpl).sinks; len(sinks) != 0 {
+		t.Errorf("Expected no sinks without webhooks, got %d", len(sinks))
+	}
+
+	config.Notifications.Slack.WebhookURL = "https://hooks.slack.com/services/T/B/X"
+	if sinks := NewNotifier(nil, config, zap.NewNop()).(*NotifierImpl).sinks; len(sinks) != 1 || sinks[0].Name() != "slack" {
+		t.Errorf("Expected the Slack sink, got %v", sinks)
+	}
+
+	config.Notifications.Teams.WebhookURL = "https://example.webhook.office.com/webhook"
+	if sinks := NewNotifier(nil, config, zap.NewNop()).(*NotifierImpl).sinks; len(sinks) != 2 || sinks[1].Name() != "teams" {
+		t.Errorf("Expected the Slack and Teams sinks, got %v", sinks)
+	}
+}
+
+func TestRenderNotification(t *testing.T) {
+	config := &models.Config{}
+	config.Jira.BaseURL = "https://example.atlassian.net"
+	notification := newNotification(config, models.NotificationProcessingFailed, "TEST-1")
+	notification.Message = "AI made no changes to the repository"
+
+	message, err := renderNotification(models.NotificationRoute{}, notification)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if want := "AI failed to process TEST-1 (https://example.atlassian.net/browse/TEST-1): AI made no changes to the repository"; message != want {
+		t.Errorf("Expected the default template, got %q", message)
+	}
+
+	message, err = renderNotification(models.NotificationRoute{Template: ":x: {{.Ticket}}: {{.Message}}"}, notification)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if want := ":x: TEST-1: AI made no changes to the repository"; message != want {
+		t.Errorf("Expected the route's template, got %q", message)
+	}
+}
+
+func TestTicketProcessor_ReportFailure_Notifies(t *testing.T) {
+	config := &models.Config{}
+	config.Jira.BaseURL = "https://example.atlassian.net/"
+	config.Jira.DisableErrorComments = true
+	notifier := &recordingNotifier{}
+	processor := &TicketProcessorImpl{config: config, notifier: notifier, logger: zap.NewNop()}
+
+	processor.handleFailure(context.Background(), "TEST-1", "Failed to clone repository")
+	processor.reportFailure(context.Background(), "TEST-2", "exceeded the ticket timeout of 30m0s", models.NotificationBudgetExceeded)
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	processor.handleFailure(canceled, "TEST-3", "context canceled")
+
+	if len(notifier.notifications) != 2 {
+		t.Fatalf("Expected two notifications, got %+v", notifier.notifications)
+	}
+	failed, exceeded := notifier.notifications[0], notifier.notifications[1]
+	if failed.Event != models.NotificationProcessingFailed || failed.Ticket != "TEST-1" || failed.Message != "Failed to clone repository" {
+		t.Errorf("Unexpected failure notification: %+v", failed)
+	}
+	if failed.TicketURL != "https://example.atlassian.net/browse/TEST-1" {
+		t.Errorf("Expected the ticket URL, got %q", failed.TicketURL)
+	}
+	if exceeded.Event != models.NotificationBudgetExceeded || exceeded.Ticket != "TEST-2" {
+		t.Errorf("Unexpected budget notification: %+v", exceeded)
+	}
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// PipelineStep is a step of the ticket pipeline
+type PipelineStep interface {
+	// Name returns the name the step is configured by
+	Name() string
+	// State returns the pipeline state the ticket is in while the step runs
+	State() models.TicketState
+	// Run runs the step; an error fails the ticket
+	Run(ctx context.Context, run *TicketRun) error
+}
+
+// TicketRun carries what a ticket accumulates while it moves through the pipeline steps
+type TicketRun struct {
+	Key           string
+	Ticket        *models.JiraTicketResponse
+	Component     string
+	Owner         string
+	Repo          string
+	ForkURL       string // clone URL of the repository the branches are pushed to: the fork, or the repository itself
+	HeadOwner     string // owner of the repository the branches are pushed to
+	RepoDir       string
+	BranchName    string
+	TargetBranch  string // branch the pull requests target
+	CloneOptions  models.CloneOptions
+	SignOff       bool
+	AIResponse    interface{}
+	StackPlan     *StackPlan
+	FollowUps     []FollowUp
+	Branches      []string // committed branches, in merge order
+	PRs           []*models.GitHubCreatePRResponse
+	Checks        []CheckResult                // build, lint and test checks the changes passed
+	RevertedPaths []string                     // protected paths whose changes were reverted
+	GitHistory    string                       // prompt section of the recent commits related to the ticket
+	ChangedFiles  []string                     // files of the change, kept for the bot change index
+	RepoConfig    *models.RepoConfig           // configuration checked in to the repository, if any
+	Batch         []*models.JiraTicketResponse // related tickets of the same epic resolved by the same change
+
+	ProgressCommented bool      // Whether the progress comment was posted on the ticket
+	ProgressStep      string    // Pipeline step shown in the progress comment
+	ProgressStepSince time.Time // When the ticket entered the step
+
+	cleanups []func()
+}
+
+// AddCleanup registers a function that runs once the ticket leaves the pipeline
+func (r *TicketRun) AddCleanup(cleanup func()) {
+	r.cleanups = append(r.cleanups, cleanup)
+}
+
+// cleanup runs the registered cleanup functions in reverse order
+func (r *TicketRun) cleanup() {
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		r.cleanups[i]()
+	}
+}
+
+// pipelineStepFunc adapts a function to the PipelineStep interface
+type pipelineStepFunc struct {
+	name  string
+	state models.TicketState
+	run   func(ctx context.Context, run *TicketRun) error
+}
+
+// Name returns the name the step is configured by
+func (s *pipelineStepFunc) Name() string {
+	return s.name
+}
+
+// State returns the pipeline state the ticket is in while the step runs
+func (s *pipelineStepFunc) State() models.TicketState {
+	return s.state
+}
+
+// Run runs the step
+func (s *pipelineStepFunc) Run(ctx context.Context, run *TicketRun) error {
+	return s.run(ctx, run)
+}
+
+// runCommandStep runs a custom step's shell command in the repository checkout. A failing command fails the ticket.
+func (p *TicketProcessorImpl) runCommandStep(ctx context.Context, step models.PipelineStepConfig, run *TicketRun) error {
+	err := p.commandRunner.RunCommand(ctx, string(step.Name), step.Command, step.TimeoutSeconds, p.commandEnv(run))
+	if err != nil {
+		p.logger.Error("Pipeline step failed",
+			zap.String("ticket", run.Key),
+			zap.String("step", string(step.Name)),
+			zap.Error(err))
+		p.handleFailure(ctx, run.Key, fmt.Sprintf("Pipeline step %s", err))
+		return err
+	}
+	return nil
+}
+
+// runHooks runs the commands configured for the hook point. A failing command fails the ticket.
+func (p *TicketProcessorImpl) runHooks(ctx context.Context, point models.HookPoint, run *TicketRun) error {
+	err := p.commandRunner.RunHooks(ctx, point, p.commandEnv(run))
+	if err != nil {
+		p.logger.Error("Pipeline hook failed",
+			zap.String("ticket", run.Key),
+			zap.String("hook_point", string(point)),
+			zap.Error(err))
+		p.handleFailure(ctx, run.Key, err.Error())
+		return err
+	}
+	return nil
+}
+
+// commandEnv returns the environment of configured commands run for the ticket
+func (p *TicketProcessorImpl) commandEnv(run *TicketRun) CommandEnv {
+	env := CommandEnv{
+		TicketKey: run.Key,
+		RepoDir:   run.RepoDir,
+		Component: run.Component,
+		Branch:    run.BranchName,
+	}
+	if len(run.PRs) > 0 {
+		env.PRURL = run.PRs[0].HTMLURL
+	}
+	return env
+}
+
+// pipelineSteps builds the configured pipeline of a component. Custom steps run in the state of the step before them.
+func (p *TicketProcessorImpl) pipelineSteps(component string) ([]PipelineStep, error) {
+	builtin := map[models.PipelineStepName]PipelineStep{
+		models.PipelineStepClone:        &pipelineStepFunc{string(models.PipelineStepClone), models.TicketStateCloning, p.cloneStep},
+		models.PipelineStepGenerateDocs: &pipelineStepFunc{string(models.PipelineStepGenerateDocs), models.TicketStateGenerating, p.generateDocsStep},
+		models.PipelineStepGitHistory:   &pipelineStepFunc{string(models.PipelineStepGitHistory), models.TicketStateGenerating, p.gitHistoryStep},
+		models.PipelineStepGenerate:     &pipelineStepFunc{string(models.PipelineStepGenerate), models.TicketStateGenerating, p.generateStep},
+		models.PipelineStepVerify:       &pipelineStepFunc{string(models.PipelineStepVerify), models.TicketStateVerifying, p.verifyStep},
+		models.PipelineStepCommit:       &pipelineStepFunc{string(models.PipelineStepCommit), models.TicketStatePushing, p.commitStep},
+		models.PipelineStepPush:         &pipelineStepFunc{string(models.PipelineStepPush), models.TicketStatePushing, p.pushStep},
+		models.PipelineStepCreatePR:     &pipelineStepFunc{string(models.PipelineStepCreatePR), models.TicketStatePushing, p.createPRStep},
+		models.PipelineStepNotify:       &pipelineStepFunc{string(models.PipelineStepNotify), models.TicketStatePushing, p.notifyStep},
+	}
+
+	var steps []PipelineStep
+	state := models.TicketStateCloning
+	for _, config := range p.config.GetPipelineSteps(component) {
+		if config.IsCustom() {
+			steps = append(steps, &pipelineStepFunc{string(config.Name), state, func(ctx context.Context, run *TicketRun) error {
+				return p.runCommandStep(ctx, config, run)
+			}})
+			continue
+		}
+
+		step, ok := builtin[config.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown pipeline step: %s", config.Name)
+		}
+		steps = append(steps, step)
+		state = step.State()
+	}
+	return steps, nil
+}
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// newPipelineTestProcessor creates a processor for a frontend ticket whose repository checkout exists on disk
+func newPipelineTestProcessor(t *testing.T, githubService *mocks.MockGitHubService, jiraService *mocks.MockJiraService, steps []models.PipelineStepConfig) (TicketProcessor, TicketStateMachine, string) {
+	if jiraService.GetTicketFunc == nil {
+		jiraService.GetTicketFunc = func(key string) (*models.JiraTicketResponse, error) {
+			return &models.JiraTicketResponse{
+				Key: key,
+				Fields: models.JiraFields{
+					Summary:    "Test ticket",
+					Components: []models.JiraComponent{{ID: "1", Name: "frontend"}},
+				},
+			}, nil
+		}
+	}
+	githubService.CheckForkExistsFunc = func(owner, repo string) (bool, string, error) {
+		return true, "https://github.com/mockuser/frontend.git", nil
+	}
+
+	config := &models.Config{}
+	config.ComponentToRepo = map[string]string{"frontend": "https://github.com/example/frontend.git"}
+	config.Components = map[string]models.ComponentConfig{"frontend": {Pipeline: steps}}
+	config.Jira.StatusTransitions.InProgress = "In Progress"
+	config.Jira.StatusTransitions.InReview = "In Review"
+	config.TempDir = t.TempDir()
+
+	repoDir := filepath.Join(config.TempDir, "TEST-1")
+	if err := os.MkdirAll(repoDir, 0755); err != nil {
+		t.Fatalf("Failed to create repository directory: %v", err)
+	}
+
+	stateMachine := newTestStateMachine(config)
+	processor := NewTicketProcessor(jiraService, githubService, &mocks.MockClaudeService{}, stateMachine, config, zap.NewNop())
+	return processor, stateMachine, repoDir
+}
+
+func TestTicketProcessor_CustomPipeline(t *testing.T) {
+	disabled := false
+	var pushed []string
+	var prCreated bool
+	githubService := &mocks.MockGitHubService{
+		PushChangesFunc: func(directory, branchName string) error {
+			pushed = append(pushed, branchName)
+			return nil
+		},
+		CreatePullRequestFunc: func(owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error) {
+			prCreated = true
+			return &models.GitHubCreatePRResponse{Number: 1}, nil
+		},
+	}
+	var statuses []string
+	jiraService := &mocks.MockJiraService{
+		UpdateTicketStatusFunc: func(key, status string) error {
+			statuses = append(statuses, status)
+			return nil
+		},
+	}
+
+	// Push the change for a human to pick up instead of opening a PR, after a site-specific step
+	processor, stateMachine, repoDir := newPipelineTestProcessor(t, githubService, jiraService, []models.PipelineStepConfig{
+		{Name: models.PipelineStepClone},
+		{Name: models.PipelineStepGenerate},
+		{Name: "stamp", Command: `echo "$TICKET_KEY $COMPONENT" > "$REPO_DIR/stamp.txt"`},
+		{Name: models.PipelineStepVerify, Enabled: &disabled},
+		{Name: models.PipelineStepCommit},
+		{Name: models.PipelineStepPush},
+	})
+
+	if err := processor.ProcessTicket(context.Background(), "TEST-1"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	stamp, err := os.ReadFile(filepath.Join(repoDir, "stamp.txt"))
+	if err != nil || strings.TrimSpace(string(stamp)) != "TEST-1 frontend" {
+		t.Errorf("Expected the custom step to run with the ticket environment, got %q (%v)", stamp, err)
+	}
+	if fmt.Sprint(pushed) != "[TEST-1]" {
+		t.Errorf("Expected the ticket branch to be pushed, got %v", pushed)
+	}
+	if prCreated {
+		t.Error("Expected no pull request without the create_pr step")
+	}
+	if fmt.Sprint(statuses) != "[In Progress]" {
+		t.Errorf("Expected the ticket not to move to review without the notify step, got %v", statuses)
+	}
+
+	record := stateMachine.Records()[0]
+	var path []models.TicketState
+	for _, transition := range record.Transitions {
+		path = append(path, transition.To)
+	}
+	want := []models.TicketState{
+		models.TicketStateQueued, models.TicketStateCloning, models.TicketStateGenerating,
+		models.TicketStatePushing, models.TicketStateDone,
+	}
+	if fmt.Sprint(path) != fmt.Sprint(want) {
+		t.Errorf("Expected the ticket to pass through %v, got %v", want, path)
+	}
+}
+
+func TestTicketProcessor_CustomPipelineStepFailure(t *testing.T) {
+	tests := []struct {
+		name    string
+		step    models.PipelineStepConfig
+		wantErr string
+	}{
+		{
+			name:    "non-zero exit status",
+			step:    models.PipelineStepConfig{Name: "lint", Command: "echo 'lint: unused import'; exit 3"},
+			wantErr: "lint: unused import",
+		},
+		{
+			name:    "timeout",
+			step:    models.PipelineStepConfig{Name: "lint", Command: "sleep 5", TimeoutSeconds: 1},
+			wantErr: "timed out",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var committed bool
+			githubService := &mocks.MockGitHubService{
+				CommitChangesFunc: func(directory, message string) error {
+					committed = true
+					return nil
+				},
+			}
+			var comments []string
+			jiraService := &mocks.MockJiraService{
+				AddCommentFunc: func(key, comment string) error {
+					comments = append(comments, comment)
+					return nil
+				},
+			}
+
+			processor, stateMachine, _ := newPipelineTestProcessor(t, githubService, jiraService, []models.PipelineStepConfig{
+				{Name: models.PipelineStepClone},
+				{Name: models.PipelineStepGenerate},
+				tt.step,
+				{Name: models.PipelineStepCommit},
+			})
+
+			err := processor.ProcessTicket(context.Background(), "TEST-1")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected an error containing %q, got %v", tt.wantErr, err)
+			}
+			if committed {
+				t.Error("Expected the steps after the failing step not to run")
+			}
+			var failures []string
+			for _, comment := range comments {
+				if strings.Contains(comment, jiraCommentMarker(commentKeyFailure)) {
+					failures = append(failures, comment)
+				}
+			}
+			if len(failures) != 1 || !strings.Contains(failures[0], tt.wantErr) {
+				t.Errorf("Expected the failure to be reported on the ticket, got %v", comments)
+			}
+			if last := comments[len(comments)-1]; !strings.Contains(last, "stopped working on this ticket") {
+				t.Errorf("Expected the progress comment to be closed, got %q", last)
+			}
+			if state, _ := stateMachine.State("TEST-1"); state != models.TicketStateFailed {
+				t.Errorf("Expected the ticket to fail, got %s", state)
+			}
+		})
+	}
+}
+
+func TestTicketProcessor_Hooks(t *testing.T) {
+	var pushed bool
+	githubService := &mocks.MockGitHubService{
+		PushChangesFunc: func(directory, branchName string) error {
+			pushed = true
+			return nil
+		},
+	}
+	processor, stateMachine, repoDir := newPipelineTestProcessor(t, githubService, &mocks.MockJiraService{}, nil)
+
+	impl := processor.(*TicketProcessorImpl)
+	impl.config.Hooks = models.PipelineHooks{
+		models.HookPreGenerate: {{Name: "prepare", Command: "touch prepared"}},
+		models.HookPrePush:     {{Name: "check", Command: "test -f generated"}},
+	}
+	impl.aiService = &mocks.MockClaudeService{
+		GenerateCodeFunc: func(prompt, repoDir string) (*models.ClaudeResponse, error) {
+			if _, err := os.Stat(filepath.Join(repoDir, "prepared")); err != nil {
+				t.Error("Expected the pre-generate hook to run before the AI")
+			}
+			return &models.ClaudeResponse{}, nil
+		},
+	}
+
+	err := processor.ProcessTicket(context.Background(), "TEST-1")
+	if err == nil || !strings.Contains(err.Error(), "pre_push hook failed: check failed") {
+		t.Fatalf("Expected the pre-push hook to fail, got %v", err)
+	}
+	if pushed {
+		t.Error("Expected nothing to be pushed after a failing pre-push hook")
+	}
+	if state, _ := stateMachine.State("TEST-1"); state != models.TicketStateFailed {
+		t.Errorf("Expected the ticket to fail, got %s", state)
+	}
+	if _, err := os.Stat(filepath.Join(repoDir, "prepared")); err != nil {
+		t.Error("Expected the pre-generate hook to run in the repository checkout")
+	}
+}
+
+func TestTicketProcessor_Canceled(t *testing.T) {
+	var comments []string
+	jiraService := &mocks.MockJiraService{
+		AddCommentFunc: func(key, comment string) error {
+			comments = append(comments, comment)
+			return nil
+		},
+	}
+	processor, stateMachine, _ := newPipelineTestProcessor(t, &mocks.MockGitHubService{}, jiraService, []models.PipelineStepConfig{
+		{Name: models.PipelineStepClone},
+		{Name: "slow", Command: "exec sleep 30"},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := processor.ProcessTicket(ctx, "TEST-1"); err == nil {
+		t.Fatal("Expected the canceled ticket to return an error")
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Expected canceling to stop the running step, took %s", elapsed)
+	}
+
+	// The ticket resumes after a restart instead of being failed
+	if state, _ := stateMachine.State("TEST-1"); state.IsTerminal() {
+		t.Errorf("Expected the canceled ticket to stay in a non-terminal state, got %s", state)
+	}
+	for _, comment := range comments {
+		if strings.Contains(comment, "AI failed") {
+			t.Errorf("Expected no failure comment on the canceled ticket, got %q", comment)
+		}
+	}
+}
+
+func TestTicketProcessor_TicketTimeout(t *testing.T) {
+	var comments []string
+	jiraService := &mocks.MockJiraService{
+		AddCommentFunc: func(key, comment string) error {
+			comments = append(comments, comment)
+			return nil
+		},
+	}
+	processor, stateMachine, _ := newPipelineTestProcessor(t, &mocks.MockGitHubService{}, jiraService, []models.PipelineStepConfig{
+		{Name: models.PipelineStepClone},
+		{Name: "slow", Command: "exec sleep 30"},
+	})
+	processor.(*TicketProcessorImpl).ticketTimeout = 200 * time.Millisecond
+
+	start := time.Now()
+	if err := processor.ProcessTicket(context.Background(), "TEST-1"); err == nil {
+		t.Fatal("Expected the timed out ticket to return an error")
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Expected the timeout to stop the running step, took %s", elapsed)
+	}
+
+	// Unlike a shutdown, running out of time fails the ticket
+	if state, _ := stateMachine.State("TEST-1"); state != models.TicketStateFailed {
+		t.Errorf("Expected the timed out ticket to fail, got %s", state)
+	}
+	var reported bool
+	for _, comment := range comments {
+		if strings.Contains(comment, "exceeded the ticket timeout") {
+			reported = true
+		}
+	}
+	if !reported {
+		t.Errorf("Expected a comment reporting the timeout, got %q", comments)
+	}
+}
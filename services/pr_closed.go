@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// commentKeyClosed identifies the Jira comment posted when the ticket's PR is closed without merging
+const commentKeyClosed = "closed"
+
+// errPRClosed is the cause recorded on tickets whose PR was closed without merging
+var errPRClosed = errors.New("pull request closed without merging")
+
+// closeUnmergedTicket takes the ticket of a PR closed without merging, and the tickets batched into it, out of the
+// automated flow, then cleans up after the PR
+func (p *PRReviewProcessorImpl) closeUnmergedTicket(ctx context.Context, ticketKey string, pr *models.GitHubPRDetails) error {
+	// The ticket was already closed by an earlier scan and stays in review until a human moves it
+	if state, ok := p.stateMachine.State(ticketKey); ok && state == models.TicketStateFailed {
+		p.logger.Info("Skipping ticket of closed pull request", zap.String("ticket", ticketKey), zap.Int("pr_number", pr.Number))
+		return nil
+	}
+
+	if err := p.abandonTicket(ctx, ticketKey, pr); err != nil {
+		return err
+	}
+	for _, batchedKey := range batchedTickets(pr.Body) {
+		if err := p.abandonTicket(ctx, batchedKey, pr); err != nil {
+			p.logger.Error("Failed to close batched ticket",
+				zap.String("ticket", ticketKey),
+				zap.String("batched_ticket", batchedKey),
+				zap.Error(err))
+		}
+	}
+
+	p.cleanupClosedPR(ctx, ticketKey, pr)
+	return nil
+}
+
+// abandonTicket tells the ticket its PR was closed without merging, moves it to the configured status and fails it,
+// so later scans skip it
+func (p *PRReviewProcessorImpl) abandonTicket(ctx context.Context, ticketKey string, pr *models.GitHubPRDetails) error {
+	comment := fmt.Sprintf("AI-generated pull request %s was closed without merging. AI stopped working on this ticket; move it back to %s to retry.",
+		pr.HTMLURL, p.config.Jira.StatusTransitions.Todo)
+	if err := upsertJiraComment(ctx, p.jiraService, p.logger, ticketKey, commentKeyClosed, comment); err != nil {
+		p.logger.Error("Failed to add closed comment", zap.String("ticket", ticketKey), zap.Error(err))
+		// Continue closing the ticket even if the comment fails
+	}
+
+	if status := p.config.Jira.StatusTransitions.Closed; status != "" {
+		if err := p.jiraService.UpdateTicketStatus(ctx, ticketKey, status); err != nil {
+			return fmt.Errorf("failed to update ticket status: %w", err)
+		}
+	}
+
+	if err := p.stateMachine.Transition(ticketKey, models.TicketStateFailed, errPRClosed); err != nil {
+		p.logger.Warn("Failed to change ticket state", zap.String("ticket", ticketKey), zap.Error(err))
+	}
+
+	p.logger.Info("Closed ticket of pull request closed without merging",
+		zap.String("ticket", ticketKey),
+		zap.Int("pr_number", pr.Number))
+	return nil
+}
+
+// cleanupClosedPR deletes the branch of the bot's merged or closed PR and the local checkouts of its tickets.
+// Failures are only logged, since the stale branch cleanup and the janitor catch what is left behind.
+func (p *PRReviewProcessorImpl) cleanupClosedPR(ctx context.Context, ticketKey string, pr *models.GitHubPRDetails) {
+	for _, key := range append([]string{ticketKey}, batchedTickets(pr.Body)...) {
+		removeCheckouts(p.config, p.logger, key)
+	}
+
+	if p.config.GitHub.DisableBranchDeletion || !strings.EqualFold(pr.User.Login, p.config.GitHub.BotUsername) {
+		return
+	}
+	// The head repository is gone once the fork was deleted
+	owner, repo, branch := pr.Head.Repo.Owner.Login, pr.Head.Repo.Name, pr.Head.Ref
+	if owner == "" || repo == "" || branch == "" {
+		return
+	}
+	// Never delete the branch the PR targets, should a PR be opened from the base repository's own target branch
+	if pr.Head.Repo.FullName == pr.Base.Repo.FullName && branch == pr.Base.Ref {
+		return
+	}
+
+	if err := p.githubService.DeleteBranch(ctx, owner, repo, branch); err != nil {
+		p.logger.Error("Failed to delete pull request branch",
+			zap.String("ticket", ticketKey),
+			zap.String("owner", owner),
+			zap.String("repo", repo),
+			zap.String("branch", branch),
+			zap.Error(err))
+		return
+	}
+	p.logger.Info("Deleted pull request branch",
+		zap.String("ticket", ticketKey),
+		zap.String("owner", owner),
+		zap.String("repo", repo),
+		zap.String("branch", branch))
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// newClosedPRJiraService returns a Jira service whose ticket points to PR 7 and which records the ticket's status and
+// comments
+func newClosedPRJiraService(statuses, comments *[]string) *mocks.MockJiraService {
+	return &mocks.MockJiraService{
+		GetTicketFunc: func(key string) (*models.JiraTicketResponse, error) {
+			return &models.JiraTicketResponse{Key: key}, nil
+		},
+		GetFieldIDByNameFunc: func(fieldName string) (string, error) {
+			return "customfield_10001", nil
+		},
+		GetTicketWithExpandedFieldsFunc: func(key string) (map[string]interface{}, map[string]string, error) {
+			return map[string]interface{}{"customfield_10001": "https://github.com/example/repo/pull/7"}, nil, nil
+		},
+		UpdateTicketStatusFunc: func(key, newStatus string) error {
+			*statuses = append(*statuses, newStatus)
+			return nil
+		},
+		AddCommentFunc: func(key, comment string) error {
+			*comments = append(*comments, comment)
+			return nil
+		},
+	}
+}
+
+// newBotPR returns a PR of the bot from its fork's TEST-1 branch in the given state
+func newBotPR(state string, merged bool) *models.GitHubPRDetails {
+	pr := newApprovedPR()
+	pr.State = state
+	pr.Merged = merged
+	pr.User = models.GitHubUser{Login: "ai-bot"}
+	pr.Head.Ref = "TEST-1"
+	pr.Head.Repo = models.GitHubRepository{Name: "repo", FullName: "ai-bot/repo", Owner: models.GitHubUser{Login: "ai-bot"}}
+	pr.Base.Ref = "main"
+	pr.Base.Repo = models.GitHubRepository{Name: "repo", FullName: "example/repo", Owner: models.GitHubUser{Login: "example"}}
+	return pr
+}
+
+func TestPRReviewProcessor_MergedPRCleanup(t *testing.T) {
+	config := newBotConfig("ai-bot")
+	config.Jira.GitPullRequestFieldName = "Git Pull Request"
+	config.Jira.StatusTransitions.Done = "Done"
+	config.TempDir = t.TempDir()
+	for _, dir := range []string{"TEST-1", "TEST-1-feedback-1", "TEST-2"} {
+		if err := os.MkdirAll(filepath.Join(config.TempDir, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var statuses, comments, deleted []string
+	githubService := &mocks.MockGitHubService{
+		GetPRDetailsFunc: func(owner, repo string, prNumber int) (*models.GitHubPRDetails, error) {
+			return newBotPR("closed", true), nil
+		},
+		DeleteBranchFunc: func(owner, repo, branch string) error {
+			deleted = append(deleted, owner+"/"+repo+":"+branch)
+			return nil
+		},
+	}
+	processor := NewPRReviewProcessor(newClosedPRJiraService(&statuses, &comments), githubService, &mocks.MockClaudeService{},
+		newTestStateMachine(config), config, zap.NewNop())
+
+	// Merged PRs close their tickets without auto-merge too
+	if err := processor.ProcessPRReviewFeedback(context.Background(), "TEST-1"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0] != "Done" {
+		t.Errorf("Expected the ticket to move to Done, got %v", statuses)
+	}
+	if len(deleted) != 1 || deleted[0] != "ai-bot/repo:TEST-1" {
+		t.Errorf("Expected the PR's branch to be deleted from the fork, got %v", deleted)
+	}
+	for dir, exists := range map[string]bool{"TEST-1": false, "TEST-1-feedback-1": false, "TEST-2": true} {
+		if _, err := os.Stat(filepath.Join(config.TempDir, dir)); (err == nil) != exists {
+			t.Errorf("Expected checkout %s to exist: %v, got error %v", dir, exists, err)
+		}
+	}
+}
+
+func TestPRReviewProcessor_ClosedPRClosesTicket(t *testing.T) {
+	config := newBotConfig("ai-bot")
+	config.Jira.GitPullRequestFieldName = "Git Pull Request"
+	config.Jira.StatusTransitions.Todo = "To Do"
+	config.Jira.StatusTransitions.Closed = "Won't Do"
+
+	var statuses, comments, deleted []string
+	githubService := &mocks.MockGitHubService{
+		GetPRDetailsFunc: func(owner, repo string, prNumber int) (*models.GitHubPRDetails, error) {
+			pr := newBotPR("closed", false)
+			// Feedback on a closed PR must not be applied
+			pr.Comments = []models.GitHubPRComment{{Body: "Not needed", User: models.GitHubUser{Login: "alice"}}}
+			return pr, nil
+		},
+		DeleteBranchFunc: func(owner, repo, branch string) error {
+			deleted = append(deleted, owner+"/"+repo+":"+branch)
+			return nil
+		},
+		CloneRepositoryFunc: func(repoURL, directory string, opts models.CloneOptions) error {
+			t.Error("Expected no checkout for a closed PR")
+			return nil
+		},
+	}
+	stateMachine := newTestStateMachine(config)
+	processor := NewPRReviewProcessor(newClosedPRJiraService(&statuses, &comments), githubService, &mocks.MockClaudeService{},
+		stateMachine, config, zap.NewNop())
+
+	for i := 0; i < 2; i++ {
+		if err := processor.ProcessPRReviewFeedback(context.Background(), "TEST-1"); err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+	}
+
+	// The second scan skips the ticket already closed
+	if len(statuses) != 1 || statuses[0] != "Won't Do" {
+		t.Errorf("Expected the ticket to move to the closed status once, got %v", statuses)
+	}
+	if len(comments) != 1 || !strings.Contains(comments[0], "closed without merging") {
+		t.Errorf("Expected a comment that the PR was closed, got %v", comments)
+	}
+	if len(deleted) != 1 || deleted[0] != "ai-bot/repo:TEST-1" {
+		t.Errorf("Expected the PR's branch to be deleted once, got %v", deleted)
+	}
+	if state, _ := stateMachine.State("TEST-1"); state != models.TicketStateFailed {
+		t.Errorf("Expected the ticket to fail, got %s", state)
+	}
+}
+
+func TestPRReviewProcessor_CleanupClosedPRKeepsBranch(t *testing.T) {
+	tests := []struct {
+		name    string
+		disable bool
+		pr      func() *models.GitHubPRDetails
+	}{
+		{
+			name:    "branch deletion disabled",
+			disable: true,
+			pr:      func() *models.GitHubPRDetails { return newBotPR("closed", true) },
+		},
+		{
+			name: "PR opened by a human",
+			pr: func() *models.GitHubPRDetails {
+				pr := newBotPR("closed", true)
+				pr.User.Login = "alice"
+				return pr
+			},
+		},
+		{
+			name: "head repository deleted",
+			pr: func() *models.GitHubPRDetails {
+				pr := newBotPR("closed", true)
+				pr.Head.Repo = models.GitHubRepository{}
+				return pr
+			},
+		},
+		{
+			name: "PR from the target branch",
+			pr: func() *models.GitHubPRDetails {
+				pr := newBotPR("closed", false)
+				pr.Head.Ref = "main"
+				pr.Head.Repo = pr.Base.Repo
+				return pr
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := newBotConfig("ai-bot")
+			config.GitHub.DisableBranchDeletion = tt.disable
+			githubService := &mocks.MockGitHubService{
+				DeleteBranchFunc: func(owner, repo, branch string) error {
+					t.Errorf("Expected branch %s of %s/%s to be kept", branch, owner, repo)
+					return nil
+				},
+			}
+			processor := &PRReviewProcessorImpl{githubService: githubService, config: config, logger: zap.NewNop()}
+
+			processor.cleanupClosedPR(context.Background(), "TEST-1", tt.pr())
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"jira-ai-issue-solver/models"
@@ -13,66 +16,87 @@ import (
 type PRFeedbackScannerService interface {
 	// Start starts the periodic scanning for PR feedback
 	Start()
-	// Stop stops the periodic scanning
+	// Stop stops the periodic scanning, waiting for a running scan to finish. The scanner can be started again.
 	Stop()
+	// IsRunning returns whether the scanner is started
+	IsRunning() bool
+	// Status returns the live state of the scanner
+	Status() ScannerStatus
 }
 
 // PRFeedbackScannerServiceImpl implements the PRFeedbackScannerService interface
 type PRFeedbackScannerServiceImpl struct {
+	ctx               context.Context // Bounds the work of the service; canceling it aborts work in progress
 	jiraService       JiraService
 	githubService     GitHubService
 	aiService         AIService
 	prReviewProcessor PRReviewProcessor
 	config            *models.Config
 	logger            *zap.Logger
-	stopChan          chan struct{}
-	isRunning         bool
+	slots             chan struct{} // Holds a token per ticket whose feedback is being processed
+	inFlight          sync.Map      // Keys of the tickets whose feedback is being processed or waits for a slot
+	waiting           atomic.Int32  // Number of in-flight tickets waiting for a slot
+	scans             scanClock
+
+	mu        sync.Mutex
+	stopChan  chan struct{} // Closed to stop the current scan loop
+	doneChan  chan struct{} // Closed once the current scan loop returned
+	isRunning bool
 }
 
 // NewPRFeedbackScannerService creates a new PRFeedbackScannerService
 func NewPRFeedbackScannerService(
+	ctx context.Context,
 	jiraService JiraService,
 	githubService GitHubService,
 	aiService AIService,
+	stateMachine TicketStateMachine,
 	config *models.Config,
 	logger *zap.Logger,
 ) PRFeedbackScannerService {
-	prReviewProcessor := NewPRReviewProcessor(jiraService, githubService, aiService, config, logger)
+	prReviewProcessor := NewPRReviewProcessor(jiraService, githubService, aiService, stateMachine, config, logger)
 
 	return &PRFeedbackScannerServiceImpl{
+		ctx:               ctx,
 		jiraService:       jiraService,
 		githubService:     githubService,
 		aiService:         aiService,
 		prReviewProcessor: prReviewProcessor,
 		config:            config,
 		logger:            logger,
-		stopChan:          make(chan struct{}),
-		isRunning:         false,
+		slots:             make(chan struct{}, max(config.GitHub.FeedbackConcurrency, 1)),
 	}
 }
 
 // Start starts the periodic scanning for PR feedback
 func (s *PRFeedbackScannerServiceImpl) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.isRunning {
 		s.logger.Info("PR feedback scanner is already running")
 		return
 	}
 
 	s.isRunning = true
+	s.stopChan = make(chan struct{})
+	s.doneChan = make(chan struct{})
 	s.logger.Info("Starting PR feedback scanner...")
 
+	// Each loop gets its own channels, so a loop still finishing after Stop can't see the next Start's
+	stopChan, doneChan := s.stopChan, s.doneChan
 	go func() {
+		defer close(doneChan)
 		ticker := time.NewTicker(time.Duration(s.config.Jira.IntervalSeconds) * time.Second)
 		defer ticker.Stop()
 
 		// Run initial scan immediately
-		s.scanForPRFeedback()
+		s.scanForPRFeedback(s.ctx)
 
 		for {
 			select {
 			case <-ticker.C:
-				s.scanForPRFeedback()
-			case <-s.stopChan:
+				s.scanForPRFeedback(s.ctx)
+			case <-stopChan:
 				s.logger.Info("Stopping PR feedback scanner...")
 				return
 			}
@@ -80,19 +104,40 @@ func (s *PRFeedbackScannerServiceImpl) Start() {
 	}()
 }
 
-// Stop stops the periodic scanning
+// Stop stops the periodic scanning, waiting for a running scan to finish. The scanner can be started again.
 func (s *PRFeedbackScannerServiceImpl) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if !s.isRunning {
 		return
 	}
 
 	s.isRunning = false
 	close(s.stopChan)
+	<-s.doneChan
+}
+
+// IsRunning returns whether the scanner is started
+func (s *PRFeedbackScannerServiceImpl) IsRunning() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.isRunning
+}
+
+// Status returns the live state of the scanner. In-flight tickets are queued while they wait for a slot.
+func (s *PRFeedbackScannerServiceImpl) Status() ScannerStatus {
+	return ScannerStatus{
+		Running:    s.IsRunning(),
+		LastScanAt: s.scans.last(),
+		InFlight:   inFlightKeys(&s.inFlight),
+		Queued:     int(s.waiting.Load()),
+	}
 }
 
 // scanForPRFeedback searches for tickets in "In Review" status that need PR feedback processing
-func (s *PRFeedbackScannerServiceImpl) scanForPRFeedback() {
+func (s *PRFeedbackScannerServiceImpl) scanForPRFeedback(ctx context.Context) {
 	s.logger.Info("Scanning for tickets in 'In Review' status that need PR feedback processing...")
+	defer s.scans.finished()
 
 	inReviewStatus := s.config.Jira.StatusTransitions.InReview
 
@@ -101,7 +146,7 @@ func (s *PRFeedbackScannerServiceImpl) scanForPRFeedback() {
 	jql := fmt.Sprintf(`Contributors = currentUser() AND status = "%s" AND "%s" IS NOT EMPTY ORDER BY updated DESC`,
 		inReviewStatus, s.config.Jira.GitPullRequestFieldName)
 
-	searchResponse, err := s.jiraService.SearchTickets(jql)
+	searchResponse, err := s.jiraService.SearchTickets(ctx, jql)
 	if err != nil {
 		s.logger.Error("Failed to search for tickets in 'In Review' status", zap.Error(err))
 		return
@@ -119,10 +164,34 @@ func (s *PRFeedbackScannerServiceImpl) scanForPRFeedback() {
 		s.logger.Info("Found ticket in 'In Review' status", zap.String("ticket", issue.Key))
 
 		// Process the ticket asynchronously
-		go func(ticketKey string) {
-			if err := s.prReviewProcessor.ProcessPRReviewFeedback(ticketKey); err != nil {
-				s.logger.Error("Failed to process PR feedback for ticket", zap.String("ticket", ticketKey), zap.Error(err))
-			}
-		}(issue.Key)
+		s.processFeedbackAsync(ctx, issue.Key)
+	}
+}
+
+// processFeedbackAsync processes the feedback of the ticket's pull requests in the background, unless it is already
+// being processed. Processing a PR can take longer than the scan interval, and two runs would push conflicting
+// changes to the same branch. At most feedback_concurrency tickets are processed at once; the others wait for a slot.
+func (s *PRFeedbackScannerServiceImpl) processFeedbackAsync(ctx context.Context, ticketKey string) {
+	if _, busy := s.inFlight.LoadOrStore(ticketKey, struct{}{}); busy {
+		s.logger.Info("PR feedback of ticket is already being processed, skipping", zap.String("ticket", ticketKey))
+		return
 	}
+
+	go func() {
+		defer s.inFlight.Delete(ticketKey)
+
+		s.waiting.Add(1)
+		select {
+		case s.slots <- struct{}{}:
+			s.waiting.Add(-1)
+		case <-ctx.Done():
+			s.waiting.Add(-1)
+			return
+		}
+		defer func() { <-s.slots }()
+
+		if err := s.prReviewProcessor.ProcessPRReviewFeedback(ctx, ticketKey); err != nil {
+			s.logger.Error("Failed to process PR feedback for ticket", zap.String("ticket", ticketKey), zap.Error(err))
+		}
+	}()
 }
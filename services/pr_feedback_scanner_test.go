@@ -1,6 +1,9 @@
 package services
 
 import (
+	"context"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -116,7 +119,7 @@ func TestPRFeedbackScannerService_StartStop(t *testing.T) {
 	config.TempDir = "/tmp/test"
 
 	// Create scanner service
-	scanner := NewPRFeedbackScannerService(mockJiraService, mockGitHubService, mockAIService, config, logger)
+	scanner := NewPRFeedbackScannerService(context.Background(), mockJiraService, mockGitHubService, mockAIService, newTestStateMachine(config), config, logger)
 
 	// Start the scanner
 	scanner.Start()
@@ -241,11 +244,117 @@ func TestPRFeedbackScannerService_ScanForPRFeedback(t *testing.T) {
 		jiraService:       mockJiraService,
 		githubService:     mockGitHubService,
 		aiService:         mockAIService,
-		prReviewProcessor: NewPRReviewProcessor(mockJiraService, mockGitHubService, mockAIService, config, logger),
+		prReviewProcessor: NewPRReviewProcessor(mockJiraService, mockGitHubService, mockAIService, newTestStateMachine(config), config, logger),
 		config:            config,
 		logger:            logger,
+		slots:             make(chan struct{}, 1),
 	}
 
 	// Test scanning for PR feedback
-	scanner.scanForPRFeedback()
+	scanner.scanForPRFeedback(context.Background())
+}
+
+func TestPRFeedbackScannerService_Restart(t *testing.T) {
+	var scans atomic.Int32
+	mockJiraService := &mocks.MockJiraService{
+		SearchTicketsFunc: func(jql string) (*models.JiraSearchResponse, error) {
+			scans.Add(1)
+			return &models.JiraSearchResponse{}, nil
+		},
+	}
+	config := &models.Config{}
+	config.Jira.IntervalSeconds = 60
+	scanner := NewPRFeedbackScannerService(context.Background(), mockJiraService, &mocks.MockGitHubService{}, &mocks.MockClaudeService{}, newTestStateMachine(config), config, zap.NewNop())
+
+	// Stop waits for the loop, so each cycle has run its initial scan once Stop returns
+	for cycle := 1; cycle <= 3; cycle++ {
+		scanner.Start()
+		scanner.Start()
+		if !scanner.IsRunning() {
+			t.Fatalf("Expected the scanner to run in cycle %d", cycle)
+		}
+		scanner.Stop()
+		scanner.Stop()
+		if scanner.IsRunning() {
+			t.Fatalf("Expected the scanner to be stopped in cycle %d", cycle)
+		}
+		if got := scans.Load(); got != int32(cycle) {
+			t.Errorf("Expected %d scans after cycle %d, got %d", cycle, cycle, got)
+		}
+	}
+}
+
+func TestPRFeedbackScannerService_BoundedConcurrency(t *testing.T) {
+	mockJiraService := &mocks.MockJiraService{
+		SearchTicketsFunc: func(jql string) (*models.JiraSearchResponse, error) {
+			response := &models.JiraSearchResponse{Total: 5}
+			for _, key := range []string{"TEST-1", "TEST-2", "TEST-3", "TEST-4", "TEST-5"} {
+				response.Issues = append(response.Issues, models.JiraIssue{Key: key})
+			}
+			return response, nil
+		},
+	}
+
+	release := make(chan struct{})
+	var active, maxActive, runs atomic.Int32
+	var runsMu sync.Mutex
+	runsPerTicket := make(map[string]int)
+	processor := &mocks.MockPRReviewProcessor{
+		ProcessPRReviewFeedbackFunc: func(ticketKey string) error {
+			runs.Add(1)
+			runsMu.Lock()
+			runsPerTicket[ticketKey]++
+			runsMu.Unlock()
+			current := active.Add(1)
+			for {
+				previous := maxActive.Load()
+				if current <= previous || maxActive.CompareAndSwap(previous, current) {
+					break
+				}
+			}
+			<-release
+			active.Add(-1)
+			return nil
+		},
+	}
+
+	config := &models.Config{}
+	config.GitHub.FeedbackConcurrency = 2
+	scanner := &PRFeedbackScannerServiceImpl{
+		jiraService:       mockJiraService,
+		prReviewProcessor: processor,
+		config:            config,
+		logger:            zap.NewNop(),
+		slots:             make(chan struct{}, config.GitHub.FeedbackConcurrency),
+	}
+
+	// A scan while the first one's tickets are still processed or queued must not start them again
+	scanner.scanForPRFeedback(context.Background())
+	waitFor(t, func() bool { return active.Load() == 2 })
+	scanner.scanForPRFeedback(context.Background())
+
+	close(release)
+	waitFor(t, func() bool { return runs.Load() == 5 && active.Load() == 0 })
+	if got := maxActive.Load(); got != 2 {
+		t.Errorf("Expected at most 2 tickets processed at once, got %d", got)
+	}
+	runsMu.Lock()
+	defer runsMu.Unlock()
+	for ticket, count := range runsPerTicket {
+		if count != 1 {
+			t.Errorf("Expected the feedback of %s to be processed once, got %d", ticket, count)
+		}
+	}
+}
+
+// waitFor polls the condition until it holds, failing the test after a few seconds
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the condition")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
 }
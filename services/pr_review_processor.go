@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
@@ -14,16 +16,23 @@ import (
 // PRReviewProcessor defines the interface for processing PR review feedback
 type PRReviewProcessor interface {
 	// ProcessPRReviewFeedback processes feedback for tickets in "In Review" status
-	ProcessPRReviewFeedback(ticketKey string) error
+	ProcessPRReviewFeedback(ctx context.Context, ticketKey string) error
 }
 
 // PRReviewProcessorImpl implements the PRReviewProcessor interface
 type PRReviewProcessorImpl struct {
-	jiraService   JiraService
-	githubService GitHubService
-	aiService     AIService
-	config        *models.Config
-	logger        *zap.Logger
+	jiraService       JiraService
+	githubService     GitHubService
+	aiService         AIService
+	complianceChecker ComplianceChecker
+	commandRunner     CommandRunner
+	stateMachine      TicketStateMachine
+	watermarks        FeedbackWatermarkStore
+	botChanges        BotChangeIndex
+	sessions          SessionSummaryLog
+	notifier          Notifier
+	config            *models.Config
+	logger            *zap.Logger
 }
 
 // NewPRReviewProcessor creates a new PRReviewProcessor
@@ -31,31 +40,53 @@ func NewPRReviewProcessor(
 	jiraService JiraService,
 	githubService GitHubService,
 	aiService AIService,
+	stateMachine TicketStateMachine,
 	config *models.Config,
 	logger *zap.Logger,
 ) PRReviewProcessor {
 	return &PRReviewProcessorImpl{
-		jiraService:   jiraService,
-		githubService: githubService,
-		aiService:     aiService,
-		config:        config,
-		logger:        logger,
+		jiraService:       jiraService,
+		githubService:     githubService,
+		aiService:         aiService,
+		complianceChecker: NewComplianceChecker(config, logger),
+		commandRunner:     NewCommandRunner(config, logger),
+		stateMachine:      stateMachine,
+		watermarks:        NewFeedbackWatermarkStore(config.FeedbackWatermarkFile),
+		botChanges:        NewBotChangeIndex(config.AI.Precedents.IndexFile),
+		sessions:          NewSessionSummaryLog(config.AI.SessionSummaries.File),
+		notifier:          NewNotifier(jiraService, config, logger),
+		config:            config,
+		logger:            logger,
 	}
 }
 
 // ProcessPRReviewFeedback processes feedback for a ticket that has PR review feedback
-func (p *PRReviewProcessorImpl) ProcessPRReviewFeedback(ticketKey string) error {
+func (p *PRReviewProcessorImpl) ProcessPRReviewFeedback(ctx context.Context, ticketKey string) error {
+	// Each run works on its own copy of the processor, so the Jira lookups its steps share are made once per run
+	run := *p
+	run.jiraService = newJiraRunCache(p.jiraService)
+	return run.processPRReviewFeedback(withAuditTicket(ctx, ticketKey), ticketKey)
+}
+
+// processPRReviewFeedback processes the PR review feedback of a ticket within a single run
+func (p *PRReviewProcessorImpl) processPRReviewFeedback(ctx context.Context, ticketKey string) error {
 	p.logger.Info("Processing PR review feedback for ticket", zap.String("ticket", ticketKey))
 
+	// A human took the PR over; the bot stays out of it for good
+	if p.handedOff(ticketKey) {
+		p.logger.Info("Skipping ticket handed over to a human", zap.String("ticket", ticketKey))
+		return nil
+	}
+
 	// Get the ticket details
-	ticket, err := p.jiraService.GetTicket(ticketKey)
+	ticket, err := p.jiraService.GetTicket(ctx, ticketKey)
 	if err != nil {
 		p.logger.Error("Failed to get ticket details", zap.String("ticket", ticketKey), zap.Error(err))
 		return err
 	}
 
 	// Get the PR URL from the custom field
-	prURL, err := p.getPRURLFromTicket(ticket)
+	prURL, err := p.getPRURLFromTicket(ctx, ticket)
 	if err != nil {
 		p.logger.Error("Failed to get PR URL from ticket", zap.String("ticket", ticketKey), zap.Error(err))
 		return err
@@ -74,33 +105,76 @@ func (p *PRReviewProcessorImpl) ProcessPRReviewFeedback(ticketKey string) error
 	}
 
 	// Get detailed PR information including reviews
-	prDetails, err := p.githubService.GetPRDetails(owner, repo, prNumber)
+	fetchedAt := time.Now().UTC()
+	prDetails, err := p.githubService.GetPRDetails(ctx, owner, repo, prNumber)
 	if err != nil {
 		p.logger.Error("Failed to get PR details", zap.String("ticket", ticketKey), zap.String("owner", owner), zap.String("repo", repo), zap.Int("pr_number", prNumber), zap.Error(err))
 		return err
 	}
 
-	// Get the last processing timestamp from PR comments
-	lastProcessedTime, err := p.getLastProcessingTimestamp(owner, repo, prNumber)
-	if err != nil {
-		p.logger.Error("Failed to get last processing timestamp", zap.String("ticket", ticketKey), zap.Error(err))
-		// Continue with processing, will use a default time
-		lastProcessedTime = time.Time{}
+	// A merged or closed PR has no feedback left to apply; this also closes tickets whose PR a human merged
+	if prDetails.Merged {
+		return p.closeMergedTicket(ctx, ticketKey, prDetails, prDetails.MergeCommitSHA)
+	}
+	if prDetails.State == "closed" {
+		return p.closeUnmergedTicket(ctx, ticketKey, prDetails)
 	}
 
-	// Filter reviews and comments by timestamp and bot user
-	filteredReviews := p.filterReviewsByTimestamp(prDetails.Reviews, lastProcessedTime)
-	filteredComments := p.filterCommentsByTimestamp(prDetails.Comments, lastProcessedTime)
+	// A stop label or command hands the PR over to a human before any more work is done on it
+	if reason := p.handoffReason(prDetails); reason != "" {
+		return p.handOff(ctx, ticketKey, owner, repo, prDetails, reason)
+	}
+
+	// Get how far the PR's feedback was already processed
+	watermark := p.loadFeedbackWatermark(ctx, ticketKey, owner, repo, prNumber)
+
+	// Filter reviews and comments by watermark and bot user
+	filteredReviews := p.filterReviews(prDetails.Reviews, watermark)
+	filteredComments := p.filterComments(prDetails.Comments, watermark.IsNewComment)
+	filteredReviewComments := p.filterComments(prDetails.ReviewComments, watermark.IsNewReviewComment)
+
+	component := ""
+	if len(ticket.Fields.Components) > 0 {
+		component = ticket.Fields.Components[0].Name
+	}
+
+	// A conflicted PR can't be merged, so resolve conflicts before looking at feedback; the feedback is
+	// applied to the rebased branch in a later scan
+	if p.config.GitHub.ConflictResolution.Enabled && prDetails.MergeableState == "dirty" {
+		attempted, err := p.resolveMergeConflicts(ctx, ticketKey, component, owner, repo, prDetails)
+		if err != nil {
+			p.logger.Error("Failed to resolve merge conflicts", zap.String("ticket", ticketKey), zap.Int("pr_number", prNumber), zap.Error(err))
+			return err
+		}
+		if attempted {
+			return nil
+		}
+	}
 
 	// Check if there are any "request changes" reviews in the filtered set
 	hasRequestChanges := p.hasRequestChangesReviews(filteredReviews)
-	if !hasRequestChanges && len(filteredComments) == 0 {
-		p.logger.Info("No new 'request changes' reviews or comments found for PR", zap.String("ticket", ticketKey), zap.Int("pr_number", prNumber), zap.Time("last_processed", lastProcessedTime))
+	if !hasRequestChanges && len(filteredComments) == 0 && len(filteredReviewComments) == 0 {
+		p.logger.Info("No new 'request changes' reviews or comments found for PR", zap.String("ticket", ticketKey), zap.Int("pr_number", prNumber), zap.Time("last_processed", watermark.ProcessedAt))
+		if p.config.GitHub.CIFeedback.Enabled {
+			// Without review feedback to act on, look for failing CI checks instead
+			if err := p.processCIFailures(ctx, ticketKey, component, owner, repo, prDetails); err != nil {
+				return err
+			}
+		}
+		if p.config.GitHub.AutoMerge.Enabled {
+			return p.processApprovedPR(ctx, ticketKey, owner, repo, prDetails)
+		}
 		return nil
 	}
 
+	// Suggested changes are applied as is; the AI is only needed for free-form feedback
+	suggestions, freeFormReviewComments := p.splitSuggestions(filteredReviewComments)
+	suggestionsOnly := len(suggestions) > 0 && len(freeFormReviewComments) == 0 && len(filteredComments) == 0 &&
+		!p.hasReviewBodies(filteredReviews)
+
 	// 2. Collect all feedback from reviews and comments (including handled ones for context)
-	feedback := p.collectFeedback(prDetails.Reviews, prDetails.Comments, lastProcessedTime)
+	feedback := p.collectFeedback(prDetails.Reviews, prDetails.Comments, watermark) +
+		p.collectInlineFeedback(prDetails.ReviewComments, watermark)
 
 	// Get the repository URL from the PR details (our fork)
 	repoURL, err := p.getRepositoryURLFromPR(prDetails)
@@ -110,31 +184,53 @@ func (p *PRReviewProcessorImpl) ProcessPRReviewFeedback(ticketKey string) error
 	}
 
 	// Clone the repository and apply fixes
-	err = p.applyFeedbackFixes(ticketKey, repoURL, prDetails, feedback)
+	var replies map[int64]ReviewReply
+	err = p.inFeedbackState(ctx, ticketKey, func() error {
+		var err error
+		replies, err = p.applyFeedbackFixes(ctx, ticketKey, component, repoURL, prDetails, feedback, suggestions, suggestionsOnly)
+		return err
+	})
 	if err != nil {
 		p.logger.Error("Failed to apply feedback fixes", zap.String("ticket", ticketKey), zap.Error(err))
 		return err
 	}
 
-	// Update the processing timestamp in PR comments
-	err = p.updateProcessingTimestamp(owner, repo, prNumber, ticketKey)
-	if err != nil {
-		p.logger.Error("Failed to update processing timestamp", zap.String("ticket", ticketKey), zap.Error(err))
-		// Continue even if timestamp update fails
-	}
+	// Tell reviewers how each inline comment was handled
+	p.replyToReviewComments(ctx, ticketKey, owner, repo, prNumber, filteredReviewComments, replies)
+
+	// Feedback that arrived while the fixes were applied stays new for the next scan
+	p.saveFeedbackWatermark(ticketKey, owner, repo, prNumber, watermark.Advance(prDetails, fetchedAt))
 
 	p.logger.Info("Successfully processed PR review feedback for ticket", zap.String("ticket", ticketKey))
 	return nil
 }
 
+// inFeedbackState keeps the ticket in the feedback state while apply runs and returns it to the PR open state
+// afterwards, logging the time apply took as work on the ticket. The state is only tracked, so bookkeeping failures
+// don't stop feedback from being applied.
+func (p *PRReviewProcessorImpl) inFeedbackState(ctx context.Context, ticketKey string, apply func() error) error {
+	if err := p.stateMachine.Transition(ticketKey, models.TicketStateFeedback, nil); err != nil {
+		p.logger.Warn("Failed to change ticket state", zap.String("ticket", ticketKey), zap.Error(err))
+	}
+
+	started := time.Now()
+	err := apply()
+	logWork(context.WithoutCancel(ctx), p.jiraService, p.config, p.logger, ticketKey, started)
+
+	if transitionErr := p.stateMachine.Transition(ticketKey, models.TicketStatePROpen, err); transitionErr != nil {
+		p.logger.Warn("Failed to change ticket state", zap.String("ticket", ticketKey), zap.Error(transitionErr))
+	}
+	return err
+}
+
 // getPRURLFromTicket extracts the PR URL from the ticket's custom field
-func (p *PRReviewProcessorImpl) getPRURLFromTicket(ticket *models.JiraTicketResponse) (string, error) {
+func (p *PRReviewProcessorImpl) getPRURLFromTicket(ctx context.Context, ticket *models.JiraTicketResponse) (string, error) {
 	if p.config.Jira.GitPullRequestFieldName == "" {
 		return "", fmt.Errorf("GitPullRequestFieldName not configured")
 	}
 
 	// Get the field ID for the field name
-	fieldID, err := p.jiraService.GetFieldIDByName(p.config.Jira.GitPullRequestFieldName)
+	fieldID, err := p.jiraService.GetFieldIDByName(ctx, p.config.Jira.GitPullRequestFieldName)
 	if err != nil {
 		return "", fmt.Errorf("failed to resolve field name '%s' to ID: %w", p.config.Jira.GitPullRequestFieldName, err)
 	}
@@ -142,7 +238,7 @@ func (p *PRReviewProcessorImpl) getPRURLFromTicket(ticket *models.JiraTicketResp
 	p.logger.Debug("Resolved field name to field ID", zap.String("field_name", p.config.Jira.GitPullRequestFieldName), zap.String("field_id", fieldID))
 
 	// Get the ticket with expanded fields to access custom fields
-	fields, _, err := p.jiraService.GetTicketWithExpandedFields(ticket.Key)
+	fields, _, err := p.jiraService.GetTicketWithExpandedFields(ctx, ticket.Key)
 	if err != nil {
 		return "", fmt.Errorf("failed to get ticket with expanded fields: %w", err)
 	}
@@ -191,6 +287,16 @@ func (p *PRReviewProcessorImpl) extractPRInfoFromURL(prURL string) (owner, repo
 	return owner, repo, prNumber, nil
 }
 
+// hasReviewBodies checks if any review has a summary the AI has to act on
+func (p *PRReviewProcessorImpl) hasReviewBodies(reviews []models.GitHubReview) bool {
+	for _, review := range reviews {
+		if strings.TrimSpace(review.Body) != "" {
+			return true
+		}
+	}
+	return false
+}
+
 // hasRequestChangesReviews checks if there are any "request changes" reviews
 func (p *PRReviewProcessorImpl) hasRequestChangesReviews(reviews []models.GitHubReview) bool {
 	for _, review := range reviews {
@@ -202,7 +308,7 @@ func (p *PRReviewProcessorImpl) hasRequestChangesReviews(reviews []models.GitHub
 }
 
 // collectFeedback collects all feedback from reviews and comments, marking them as handled or new
-func (p *PRReviewProcessorImpl) collectFeedback(reviews []models.GitHubReview, comments []models.GitHubPRComment, lastProcessedTime time.Time) string {
+func (p *PRReviewProcessorImpl) collectFeedback(reviews []models.GitHubReview, comments []models.GitHubPRComment, watermark models.FeedbackWatermark) string {
 	var feedback strings.Builder
 
 	feedback.WriteString("## PR Review Feedback\n\n")
@@ -216,10 +322,7 @@ func (p *PRReviewProcessorImpl) collectFeedback(reviews []models.GitHubReview, c
 				continue
 			}
 
-			status := "🔄 NEW"
-			if !review.SubmittedAt.After(lastProcessedTime) {
-				status = "✅ HANDLED"
-			}
+			status := p.feedbackStatus(review.Body, watermark.IsNewReview(review))
 
 			feedback.WriteString(fmt.Sprintf("**Review by %s (%s) - %s:**\n", review.User.Login, review.State, status))
 			feedback.WriteString(review.Body)
@@ -236,10 +339,7 @@ func (p *PRReviewProcessorImpl) collectFeedback(reviews []models.GitHubReview, c
 				continue
 			}
 
-			status := "🔄 NEW"
-			if !comment.CreatedAt.After(lastProcessedTime) {
-				status = "✅ HANDLED"
-			}
+			status := p.feedbackStatus(comment.Body, watermark.IsNewComment(comment))
 
 			feedback.WriteString(fmt.Sprintf("**Comment by %s on %s:%d - %s:**\n", comment.User.Login, comment.Path, comment.Line, status))
 			feedback.WriteString(comment.Body)
@@ -262,54 +362,140 @@ func (p *PRReviewProcessorImpl) getRepositoryURLFromPR(pr *models.GitHubPRDetail
 	return pr.Head.Repo.CloneURL, nil
 }
 
-// applyFeedbackFixes applies the feedback fixes to the code
-func (p *PRReviewProcessorImpl) applyFeedbackFixes(ticketKey, forkURL string, pr *models.GitHubPRDetails, feedback string) error {
+// applyFeedbackFixes applies the feedback fixes to the code and returns the summaries of how each inline review
+// comment was handled. Suggested changes are applied as is; when suggestionsOnly is set and all of them apply,
+// the AI isn't run.
+func (p *PRReviewProcessorImpl) applyFeedbackFixes(ctx context.Context, ticketKey, component, forkURL string, pr *models.GitHubPRDetails, feedback string, suggestions []*Suggestion, suggestionsOnly bool) (map[int64]ReviewReply, error) {
 	p.logger.Info("Applying feedback fixes for ticket", zap.String("ticket", ticketKey))
 
-	// Clone the repository
-	repoDir := fmt.Sprintf("%s/%s-feedback", p.config.TempDir, ticketKey)
-	err := p.githubService.CloneRepository(forkURL, repoDir)
-	if err != nil {
-		return fmt.Errorf("failed to clone repository: %w", err)
-	}
-
-	// Switch to the existing PR branch
 	branchName := pr.Head.Ref
-	err = p.githubService.SwitchToBranch(repoDir, branchName)
+	repoDir, cloneOptions, cleanup, err := p.checkoutPRBranch(ctx, ticketKey, component, forkURL, pr)
 	if err != nil {
-		return fmt.Errorf("failed to switch to PR branch: %w", err)
+		return nil, err
 	}
+	defer cleanup()
 
-	// Pull the latest changes from the remote branch
-	err = p.githubService.PullChanges(repoDir, branchName)
+	// Follow-up commits have the same author as the original contribution
+	p.githubService.SetCommitAuthor(repoDir, p.feedbackCommitAuthor(ctx, ticketKey, component))
+	defer p.githubService.SetCommitAuthor(repoDir, nil)
+
+	// Follow-up commits have to meet the same CLA/DCO requirements as the original contribution
+	compliance, err := p.complianceChecker.CheckCompliance(pr.Base.Repo.Owner.Login, pr.Base.Repo.Name, repoDir)
 	if err != nil {
-		return fmt.Errorf("failed to pull latest changes: %w", err)
+		return nil, fmt.Errorf("repository contribution requirements not met: %w", err)
 	}
 
-	// Generate a prompt for the AI service to fix the code based on feedback
-	prompt := p.generateFeedbackPrompt(pr, feedback)
+	replies, failed := p.applySuggestions(ctx, ticketKey, repoDir, suggestions)
 
-	// Run AI service to generate code fixes
-	_, err = p.aiService.GenerateCode(prompt, repoDir)
-	if err != nil {
-		return fmt.Errorf("failed to generate code fixes: %w", err)
+	env := CommandEnv{TicketKey: ticketKey, RepoDir: repoDir, Component: component, Branch: branchName, PRURL: pr.HTMLURL}
+	commitMessage := fmt.Sprintf("%s: Apply suggested changes from PR review", ticketKey)
+	if !suggestionsOnly || len(failed) > 0 {
+		// Generate a prompt for the AI service to fix the code based on feedback, after what earlier sessions did
+		prompt := sessionSummariesPrompt(p.config, p.sessions, p.logger, ticketKey) + p.generateFeedbackPrompt(pr, feedback) +
+			appliedSuggestionsPrompt(suggestions, replies) + sessionSummaryInstructions(p.config) + shallowCloneInstructions(cloneOptions)
+		if len(pr.ReviewComments) > 0 {
+			prompt += reviewRepliesPrompt()
+		}
+
+		if err := p.commandRunner.RunHooks(ctx, models.HookPreGenerate, env); err != nil {
+			return nil, err
+		}
+
+		// Run AI service to generate code fixes
+		response, err := p.aiService.GenerateCode(ctx, prompt, repoDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate code fixes: %w", err)
+		}
+		recordSessionSummary(p.config, p.sessions, p.logger, ticketKey, models.AISessionFeedback, response)
+
+		// Replies to applied suggestions stay as they are
+		for id, reply := range p.loadReviewReplies(ticketKey, repoDir) {
+			if _, ok := replies[id]; !ok {
+				replies[id] = reply
+			}
+		}
+		if err := p.commandRunner.RunHooks(ctx, models.HookPostGenerate, env); err != nil {
+			return nil, err
+		}
+		commitMessage = fmt.Sprintf("%s: Apply PR feedback fixes", ticketKey)
 	}
 
 	// Commit the changes
-	commitMessage := fmt.Sprintf("%s: Apply PR feedback fixes", ticketKey)
-	err = p.githubService.CommitChanges(repoDir, commitMessage)
+	if compliance.SignOff {
+		commitMessage = withSignOff(commitMessage, p.config)
+	}
+	err = p.githubService.CommitChanges(ctx, repoDir, commitMessage)
+	if errors.Is(err, ErrNoChanges) {
+		p.logger.Warn("AI made no changes for the PR feedback, nothing to push", zap.String("ticket", ticketKey), zap.Int("pr_number", pr.Number))
+		return replies, nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to commit changes: %w", err)
+		return nil, fmt.Errorf("failed to commit changes: %w", err)
+	}
+
+	if err := p.commandRunner.RunHooks(ctx, models.HookPrePush, env); err != nil {
+		return nil, err
 	}
 
 	// Push the changes to update the original PR
-	err = p.githubService.PushChanges(repoDir, branchName)
+	err = p.githubService.PushChanges(ctx, repoDir, branchName)
 	if err != nil {
-		return fmt.Errorf("failed to push changes: %w", err)
+		return nil, fmt.Errorf("failed to push changes: %w", err)
 	}
 
 	p.logger.Info("Successfully updated PR #%d with feedback fixes for ticket %s", zap.Int("pr_number", pr.Number), zap.String("ticket", ticketKey))
-	return nil
+
+	notification := newNotification(p.config, models.NotificationFeedbackApplied, ticketKey)
+	notification.PRURL = pr.HTMLURL
+	p.notifier.Notify(ctx, notification)
+	return replies, nil
+}
+
+// checkoutPRBranch checks the PR branch of the fork out at its latest remote state.
+// The returned cleanup function removes the checkout when worktrees are used.
+func (p *PRReviewProcessorImpl) checkoutPRBranch(ctx context.Context, ticketKey, component, forkURL string, pr *models.GitHubPRDetails) (string, models.CloneOptions, func(), error) {
+	repoDir := fmt.Sprintf("%s/%s-feedback", p.config.TempDir, ticketKey)
+	branchName := pr.Head.Ref
+	cloneOptions := p.config.GetCloneOptions(component)
+	cloneOptions.Branch = branchName
+
+	if p.config.GitHub.Worktrees.Enabled {
+		// Check the PR branch out at its latest remote state in a worktree of the cached clone
+		err := p.githubService.CreateWorktree(ctx, forkURL, repoDir, branchName, branchName, cloneOptions)
+		if err != nil {
+			return "", cloneOptions, nil, fmt.Errorf("failed to create worktree: %w", err)
+		}
+		cleanup := func() {
+			// The worktree is removed even when processing was canceled
+			if err := p.githubService.RemoveWorktree(context.WithoutCancel(ctx), forkURL, repoDir); err != nil {
+				p.logger.Warn("Failed to remove worktree",
+					zap.String("ticket", ticketKey),
+					zap.String("repo_dir", repoDir),
+					zap.Error(err))
+			}
+		}
+		return repoDir, cloneOptions, cleanup, nil
+	}
+
+	// Clone the repository
+	err := p.githubService.CloneRepository(ctx, forkURL, repoDir, cloneOptions)
+	if err != nil {
+		return "", cloneOptions, nil, fmt.Errorf("failed to clone repository: %w", err)
+	}
+
+	// Switch to the existing PR branch
+	err = p.githubService.SwitchToBranch(ctx, repoDir, branchName)
+	if err != nil {
+		return "", cloneOptions, nil, fmt.Errorf("failed to switch to PR branch: %w", err)
+	}
+
+	// Pull the latest changes from the remote branch
+	err = p.githubService.PullChanges(ctx, repoDir, branchName)
+	if err != nil {
+		return "", cloneOptions, nil, fmt.Errorf("failed to pull latest changes: %w", err)
+	}
+
+	return repoDir, cloneOptions, func() {}, nil
 }
 
 // generateFeedbackPrompt generates a prompt for the AI service to fix code based on feedback
@@ -323,7 +509,7 @@ func (p *PRReviewProcessorImpl) generateFeedbackPrompt(pr *models.GitHubPRDetail
 	prompt.WriteString(fmt.Sprintf("**PR URL:** %s\n\n", pr.HTMLURL))
 
 	prompt.WriteString("## Changed Files\n")
-	for _, file := range pr.Files {
+	for _, file := range condenseDiffs(pr.Files, p.config.PromptLimits().MaxDiffTokens) {
 		prompt.WriteString(fmt.Sprintf("- %s (%s): +%d -%d\n", file.Filename, file.Status, file.Additions, file.Deletions))
 		if file.Patch != "" {
 			prompt.WriteString("```diff\n")
@@ -343,16 +529,21 @@ func (p *PRReviewProcessorImpl) generateFeedbackPrompt(pr *models.GitHubPRDetail
 	prompt.WriteString("3. Apply the necessary fixes to the code\n")
 	prompt.WriteString("4. Ensure the code quality is improved based on the feedback\n")
 	prompt.WriteString("5. Make sure all requested changes are addressed\n")
-	prompt.WriteString("6. Test your changes to ensure they work correctly\n\n")
+	prompt.WriteString("6. Test your changes to ensure they work correctly\n")
+	if p.config.GitHub.FeedbackTrigger == models.FeedbackTriggerMention {
+		prompt.WriteString("7. Only act on feedback marked NEW; feedback marked CONTEXT ONLY wasn't addressed to you and is for context\n")
+	}
+	prompt.WriteString("\n")
 
 	prompt.WriteString("Please apply the feedback and fix the code accordingly.")
 
 	return prompt.String()
 }
 
-// getLastProcessingTimestamp retrieves the last processing timestamp from PR comments
-func (p *PRReviewProcessorImpl) getLastProcessingTimestamp(owner, repo string, prNumber int) (time.Time, error) {
-	comments, err := p.githubService.ListPRComments(owner, repo, prNumber)
+// getLastProcessingTimestamp retrieves the last processing timestamp from the timestamp comments earlier versions
+// posted on PRs
+func (p *PRReviewProcessorImpl) getLastProcessingTimestamp(ctx context.Context, owner, repo string, prNumber int) (time.Time, error) {
+	comments, err := p.githubService.ListPRComments(ctx, owner, repo, prNumber)
 	if err != nil {
 		return time.Time{}, fmt.Errorf("failed to get PR comments: %w", err)
 	}
@@ -375,18 +566,22 @@ func (p *PRReviewProcessorImpl) getLastProcessingTimestamp(owner, repo string, p
 	return latestTimestamp, nil
 }
 
-// updateProcessingTimestamp adds a comment with the current processing timestamp
-func (p *PRReviewProcessorImpl) updateProcessingTimestamp(owner, repo string, prNumber int, ticketKey string) error {
-	currentTime := time.Now().UTC()
-	commentBody := fmt.Sprintf(`🤖 AI Processing Timestamp: %s
-
-AI has processed feedback for ticket %s at this time. Future processing will only consider feedback submitted after this timestamp.`,
-		currentTime.Format(time.RFC3339), ticketKey)
-	return p.githubService.AddPRComment(owner, repo, prNumber, commentBody)
+// findBotMarkers returns the values the pattern's first group captures in the bot's comments
+func (p *PRReviewProcessorImpl) findBotMarkers(comments []models.GitHubPRComment, pattern *regexp.Regexp) map[string]bool {
+	markers := make(map[string]bool)
+	for _, comment := range comments {
+		if comment.User.Login != p.config.GitHub.BotUsername {
+			continue
+		}
+		if matches := pattern.FindStringSubmatch(comment.Body); len(matches) == 2 {
+			markers[matches[1]] = true
+		}
+	}
+	return markers
 }
 
-// filterReviewsByTimestamp filters reviews by timestamp and bot user
-func (p *PRReviewProcessorImpl) filterReviewsByTimestamp(reviews []models.GitHubReview, lastProcessedTime time.Time) []models.GitHubReview {
+// filterReviews filters reviews by watermark and bot user, and by mention of the bot in mention mode
+func (p *PRReviewProcessorImpl) filterReviews(reviews []models.GitHubReview, watermark models.FeedbackWatermark) []models.GitHubReview {
 	var filtered []models.GitHubReview
 
 	for _, review := range reviews {
@@ -395,8 +590,13 @@ func (p *PRReviewProcessorImpl) filterReviewsByTimestamp(reviews []models.GitHub
 			continue
 		}
 
-		// Skip reviews submitted before or at the last processed time
-		if !review.SubmittedAt.After(lastProcessedTime) {
+		// Skip reviews that were already processed
+		if !watermark.IsNewReview(review) {
+			continue
+		}
+
+		// Skip reviews that don't address the bot in mention mode
+		if !p.triggersFeedback(review.Body) {
 			continue
 		}
 
@@ -406,8 +606,8 @@ func (p *PRReviewProcessorImpl) filterReviewsByTimestamp(reviews []models.GitHub
 	return filtered
 }
 
-// filterCommentsByTimestamp filters comments by timestamp and bot user
-func (p *PRReviewProcessorImpl) filterCommentsByTimestamp(comments []models.GitHubPRComment, lastProcessedTime time.Time) []models.GitHubPRComment {
+// filterComments filters comments by the isNew watermark check and bot user, and by mention of the bot in mention mode
+func (p *PRReviewProcessorImpl) filterComments(comments []models.GitHubPRComment, isNew func(models.GitHubPRComment) bool) []models.GitHubPRComment {
 	var filtered []models.GitHubPRComment
 
 	for _, comment := range comments {
@@ -416,8 +616,13 @@ func (p *PRReviewProcessorImpl) filterCommentsByTimestamp(comments []models.GitH
 			continue
 		}
 
-		// Skip comments created before or at the last processed time
-		if !comment.CreatedAt.After(lastProcessedTime) {
+		// Skip comments that were already processed
+		if !isNew(comment) {
+			continue
+		}
+
+		// Skip comments that don't address the bot in mention mode
+		if !p.triggersFeedback(comment.Body) {
 			continue
 		}
 
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"strings"
 	"testing"
 	"time"
@@ -119,15 +120,7 @@ func TestPRReviewProcessor_HasRequestChangesReviews(t *testing.T) {
 
 func TestPRReviewProcessor_CollectFeedback(t *testing.T) {
 	processor := &PRReviewProcessorImpl{
-		config: &models.Config{
-			GitHub: struct {
-				PersonalAccessToken string `yaml:"personal_access_token"`
-				BotUsername         string `yaml:"bot_username"`
-				BotEmail            string `yaml:"bot_email"`
-				TargetBranch        string `yaml:"target_branch" default:"main"`
-				PRLabel             string `yaml:"pr_label" default:"ai-pr"`
-			}{BotUsername: "ai-bot"},
-		},
+		config: newBotConfig("ai-bot"),
 	}
 
 	pr := &models.GitHubPRDetails{
@@ -164,7 +157,7 @@ func TestPRReviewProcessor_CollectFeedback(t *testing.T) {
 		},
 	}
 
-	feedback := processor.collectFeedback(pr.Reviews, pr.Comments, time.Time{})
+	feedback := processor.collectFeedback(pr.Reviews, pr.Comments, models.FeedbackWatermark{})
 
 	// Check that feedback contains expected content
 	if !strings.Contains(feedback, "PR Review Feedback") {
@@ -188,7 +181,7 @@ func TestPRReviewProcessor_CollectFeedback(t *testing.T) {
 }
 
 func TestPRReviewProcessor_GenerateFeedbackPrompt(t *testing.T) {
-	processor := &PRReviewProcessorImpl{}
+	processor := &PRReviewProcessorImpl{config: &models.Config{}}
 
 	pr := &models.GitHubPRDetails{
 		Number:  123,
@@ -233,17 +226,7 @@ func TestPRReviewProcessor_GenerateFeedbackPrompt(t *testing.T) {
 }
 
 func TestPRReviewProcessor_GetRepositoryURLFromPR(t *testing.T) {
-	config := &models.Config{
-		GitHub: struct {
-			PersonalAccessToken string `yaml:"personal_access_token"`
-			BotUsername         string `yaml:"bot_username"`
-			BotEmail            string `yaml:"bot_email"`
-			TargetBranch        string `yaml:"target_branch" default:"main"`
-			PRLabel             string `yaml:"pr_label" default:"ai-pr"`
-		}{
-			BotUsername: "test-bot",
-		},
-	}
+	config := newBotConfig("test-bot")
 
 	processor := &PRReviewProcessorImpl{
 		config: config,
@@ -303,17 +286,9 @@ func TestPRReviewProcessor_GetLastProcessingTimestamp(t *testing.T) {
 	}
 	processor := &PRReviewProcessorImpl{
 		githubService: mockGitHub,
-		config: &models.Config{
-			GitHub: struct {
-				PersonalAccessToken string `yaml:"personal_access_token"`
-				BotUsername         string `yaml:"bot_username"`
-				BotEmail            string `yaml:"bot_email"`
-				TargetBranch        string `yaml:"target_branch" default:"main"`
-				PRLabel             string `yaml:"pr_label" default:"ai-pr"`
-			}{BotUsername: "ai-bot"},
-		},
+		config:        newBotConfig("ai-bot"),
 	}
-	ts, err := processor.getLastProcessingTimestamp("owner", "repo", 1)
+	ts, err := processor.getLastProcessingTimestamp(context.Background(), "owner", "repo", 1)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -351,17 +326,9 @@ func TestPRReviewProcessor_GetLastProcessingTimestamp_MultipleTimestamps(t *test
 	}
 	processor := &PRReviewProcessorImpl{
 		githubService: mockGitHub,
-		config: &models.Config{
-			GitHub: struct {
-				PersonalAccessToken string `yaml:"personal_access_token"`
-				BotUsername         string `yaml:"bot_username"`
-				BotEmail            string `yaml:"bot_email"`
-				TargetBranch        string `yaml:"target_branch" default:"main"`
-				PRLabel             string `yaml:"pr_label" default:"ai-pr"`
-			}{BotUsername: "ai-bot"},
-		},
+		config:        newBotConfig("ai-bot"),
 	}
-	ts, err := processor.getLastProcessingTimestamp("owner", "repo", 1)
+	ts, err := processor.getLastProcessingTimestamp(context.Background(), "owner", "repo", 1)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -371,49 +338,9 @@ func TestPRReviewProcessor_GetLastProcessingTimestamp_MultipleTimestamps(t *test
 	}
 }
 
-func TestPRReviewProcessor_UpdateProcessingTimestamp(t *testing.T) {
-	var called bool
-	mockGitHub := &mocks.MockGitHubService{
-		AddPRCommentFunc: func(owner, repo string, prNumber int, body string) error {
-			called = true
-			if !strings.Contains(body, "🤖 AI Processing Timestamp:") {
-				t.Errorf("body should contain timestamp")
-			}
-			return nil
-		},
-	}
-	processor := &PRReviewProcessorImpl{
-		githubService: mockGitHub,
-		config: &models.Config{
-			GitHub: struct {
-				PersonalAccessToken string `yaml:"personal_access_token"`
-				BotUsername         string `yaml:"bot_username"`
-				BotEmail            string `yaml:"bot_email"`
-				TargetBranch        string `yaml:"target_branch" default:"main"`
-				PRLabel             string `yaml:"pr_label" default:"ai-pr"`
-			}{BotUsername: "ai-bot"},
-		},
-	}
-	err := processor.updateProcessingTimestamp("owner", "repo", 1, "TEST-123")
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if !called {
-		t.Error("AddPRComment was not called")
-	}
-}
-
 func TestPRReviewProcessor_CollectFeedbackWithHandlingStatus(t *testing.T) {
 	processor := &PRReviewProcessorImpl{
-		config: &models.Config{
-			GitHub: struct {
-				PersonalAccessToken string `yaml:"personal_access_token"`
-				BotUsername         string `yaml:"bot_username"`
-				BotEmail            string `yaml:"bot_email"`
-				TargetBranch        string `yaml:"target_branch" default:"main"`
-				PRLabel             string `yaml:"pr_label" default:"ai-pr"`
-			}{BotUsername: "ai-bot"},
-		},
+		config: newBotConfig("ai-bot"),
 	}
 
 	baseTime := time.Date(2024, 7, 10, 12, 0, 0, 0, time.UTC)
@@ -465,7 +392,7 @@ func TestPRReviewProcessor_CollectFeedbackWithHandlingStatus(t *testing.T) {
 		},
 	}
 
-	feedback := processor.collectFeedback(reviews, comments, baseTime)
+	feedback := processor.collectFeedback(reviews, comments, models.FeedbackWatermark{ProcessedAt: baseTime})
 
 	// Check that feedback contains handling status
 	if !strings.Contains(feedback, "✅ HANDLED") {
@@ -493,3 +420,10 @@ func TestPRReviewProcessor_CollectFeedbackWithHandlingStatus(t *testing.T) {
 		t.Error("Feedback should not contain bot comment")
 	}
 }
+
+// newBotConfig returns a config with only the GitHub bot username set
+func newBotConfig(botUsername string) *models.Config {
+	config := &models.Config{}
+	config.GitHub.BotUsername = botUsername
+	return config
+}
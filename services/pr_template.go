@@ -0,0 +1,161 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// prTemplatePaths are the locations GitHub reads a repository's pull request template from, in order
+var prTemplatePaths = []string{
+	".github/pull_request_template.md",
+	".github/PULL_REQUEST_TEMPLATE.md",
+	"pull_request_template.md",
+	"PULL_REQUEST_TEMPLATE.md",
+	"docs/pull_request_template.md",
+	"docs/PULL_REQUEST_TEMPLATE.md",
+}
+
+// prTemplateHeadingPattern matches a Markdown heading of a pull request template, capturing its text
+var prTemplateHeadingPattern = regexp.MustCompile(`^#{1,6}\s+(.+?)\s*#*\s*$`)
+
+// prTemplateCheckboxPattern matches a checklist item, whose section is left for humans to tick
+var prTemplateCheckboxPattern = regexp.MustCompile(`(?m)^\s*[-*]\s+\[[ xX]\]`)
+
+// Kinds of pull request template sections filled in by the bot
+const (
+	prSectionDescription = "description"
+	prSectionTicket      = "ticket"
+	prSectionTesting     = "testing"
+)
+
+// prTemplateSectionKeywords are the words of a section's heading telling its kind, checked in this order since e.g.
+// "What testing was done?" is about testing
+var prTemplateSectionKeywords = []struct {
+	kind     string
+	keywords []string
+}{
+	{kind: prSectionTesting, keywords: []string{"test", "verif", "qa"}},
+	{kind: prSectionTicket, keywords: []string{"issue", "ticket", "jira", "related", "fixes", "closes"}},
+	{kind: prSectionDescription, keywords: []string{"description", "summary", "what", "change", "overview", "context", "motivation", "why"}},
+}
+
+// prTemplateContent is what the bot fills into the sections of a pull request template, by section kind
+type prTemplateContent map[string]string
+
+// pullRequestBody returns the body of the run's pull request: the target repository's pull request template filled
+// in with the ticket and the AI's report, or the generic body if the repository has none
+func (p *TicketProcessorImpl) pullRequestBody(run *TicketRun) string {
+	ticket := run.Ticket
+	notes := batchNote(run) + aiFailoverNote(run.AIResponse) + protectedPathsNote(run.RevertedPaths)
+	checks := checksNote(p.config, run.Checks)
+
+	template := ""
+	if !p.config.GitHub.DisablePRTemplate {
+		var err error
+		template, err = findPRTemplate(run.RepoDir)
+		if err != nil {
+			p.logger.Warn("Failed to read the repository's pull request template, using the default body",
+				zap.String("ticket", run.Key),
+				zap.Error(err))
+		}
+	}
+	if template == "" {
+		return fmt.Sprintf("This PR addresses the issue described in %s.\n\n**Summary:** %s\n\n**Description:** %s",
+			run.Key, ticket.Fields.Summary, ticket.Fields.Description) + notes + checks
+	}
+
+	description := strings.TrimSpace(aiResultText(run.AIResponse))
+	if description == "" {
+		description = strings.TrimSpace(ticket.Fields.Description)
+	}
+	ticketRef := run.Key
+	if p.config.Jira.BaseURL != "" {
+		ticketRef = fmt.Sprintf("[%s](%s/browse/%s)", run.Key, strings.TrimSuffix(p.config.Jira.BaseURL, "/"), run.Key)
+	}
+	body, filled := fillPRTemplate(template, prTemplateContent{
+		prSectionDescription: strings.TrimSpace(ticket.Fields.Summary + "\n\n" + description),
+		prSectionTicket:      fmt.Sprintf("Resolves %s: %s", ticketRef, ticket.Fields.Summary),
+		prSectionTesting:     strings.TrimPrefix(checks, "\n\n**Checks:**\n"),
+	})
+	if !filled[prSectionTesting] {
+		body += checks
+	}
+	p.logger.Info("Filled in the repository's pull request template", zap.String("ticket", run.Key))
+	return body + notes
+}
+
+// findPRTemplate returns the pull request template of the checkout, or an empty string if it has none
+func findPRTemplate(repoDir string) (string, error) {
+	for _, path := range prTemplatePaths {
+		data, err := os.ReadFile(filepath.Join(repoDir, path))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read pull request template %s: %w", path, err)
+		}
+		if template := strings.TrimSpace(string(data)); template != "" {
+			return template, nil
+		}
+	}
+	return "", nil
+}
+
+// fillPRTemplate replaces the bodies of the template's description, ticket and testing sections, the first of each
+// kind found by its heading, with the content of that kind, and returns which kinds were filled. Checklists and other
+// sections are kept for humans to complete. Without a description section the description goes above the template.
+func fillPRTemplate(template string, content prTemplateContent) (string, map[string]bool) {
+	lines := strings.Split(strings.ReplaceAll(template, "\r\n", "\n"), "\n")
+	filled := make(map[string]bool)
+	var out []string
+	for i := 0; i < len(lines); {
+		match := prTemplateHeadingPattern.FindStringSubmatch(lines[i])
+		out = append(out, lines[i])
+		i++
+		if match == nil {
+			continue
+		}
+
+		start := i
+		for i < len(lines) && !prTemplateHeadingPattern.MatchString(lines[i]) {
+			i++
+		}
+		section := lines[start:i]
+		kind := prTemplateSectionKind(match[1], strings.Join(section, "\n"))
+		if kind == "" || filled[kind] || content[kind] == "" {
+			out = append(out, section...)
+			continue
+		}
+		filled[kind] = true
+		out = append(out, "", content[kind], "")
+	}
+
+	body := strings.TrimSpace(strings.Join(out, "\n"))
+	if !filled[prSectionDescription] && content[prSectionDescription] != "" {
+		body = content[prSectionDescription] + "\n\n" + body
+	}
+	return body, filled
+}
+
+// prTemplateSectionKind returns the kind of a template section by its heading, or an empty string for sections the
+// bot doesn't fill, such as checklists
+func prTemplateSectionKind(heading, body string) string {
+	if prTemplateCheckboxPattern.MatchString(body) {
+		return ""
+	}
+	heading = strings.ToLower(heading)
+	for _, section := range prTemplateSectionKeywords {
+		for _, keyword := range section.keywords {
+			if strings.Contains(heading, keyword) {
+				return section.kind
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+)
+
+func TestFillPRTemplate(t *testing.T) {
+	template := "## Description\r\n<!-- What does this PR change? -->\r\n\r\n" +
+		"## Related Issue\n<!-- Link the issue -->\n\n" +
+		"## Types of changes\n- [ ] Bug fix\n- [ ] New feature\n\n" +
+		"## How Has This Been Tested?\n<!-- Describe your tests -->\n\n" +
+		"## Checklist\n- [ ] I have added tests\n"
+	content := prTemplateContent{
+		prSectionDescription: "Add CSV export\n\nAdded an ExportInvoices handler.",
+		prSectionTicket:      "Resolves TEST-1: Add CSV export",
+		prSectionTesting:     "- test (`go test ./...`): passed",
+	}
+
+	body, filled := fillPRTemplate(template, content)
+
+	want := "## Description\n\nAdd CSV export\n\nAdded an ExportInvoices handler.\n\n" +
+		"## Related Issue\n\nResolves TEST-1: Add CSV export\n\n" +
+		"## Types of changes\n- [ ] Bug fix\n- [ ] New feature\n\n" +
+		"## How Has This Been Tested?\n\n- test (`go test ./...`): passed\n\n" +
+		"## Checklist\n- [ ] I have added tests"
+	if body != want {
+		t.Errorf("fillPRTemplate() =\n%s\nwant:\n%s", body, want)
+	}
+	if !filled[prSectionDescription] || !filled[prSectionTicket] || !filled[prSectionTesting] {
+		t.Errorf("Expected all sections to be filled, got %v", filled)
+	}
+}
+
+func TestFillPRTemplate_WithoutDescriptionSection(t *testing.T) {
+	body, filled := fillPRTemplate("Thanks for contributing!\n\n## Testing\n", prTemplateContent{prSectionDescription: "Add CSV export"})
+	if body != "Add CSV export\n\nThanks for contributing!\n\n## Testing" {
+		t.Errorf("Expected the description above the template and the empty testing section kept, got %q", body)
+	}
+	if filled[prSectionTesting] {
+		t.Error("Expected the testing section not to be filled without checks")
+	}
+}
+
+func TestTicketProcessor_PullRequestBody(t *testing.T) {
+	config := &models.Config{}
+	config.Jira.BaseURL = "https://jira.example.com/"
+	processor := newCheckTestProcessor(t, config, &mocks.MockClaudeService{}, &mocks.MockJiraService{}, &mocks.MockGitHubService{})
+
+	repoDir := t.TempDir()
+	ticket := &models.JiraTicketResponse{Key: "TEST-1"}
+	ticket.Fields.Summary = "Add CSV export"
+	ticket.Fields.Description = "Users need CSV exports."
+	run := &TicketRun{
+		Key:        "TEST-1",
+		Ticket:     ticket,
+		RepoDir:    repoDir,
+		AIResponse: &models.ClaudeResponse{Result: "Added an ExportInvoices handler."},
+		Checks:     []CheckResult{{Name: "test", Command: "go test ./..."}},
+	}
+
+	if body := processor.pullRequestBody(run); !strings.HasPrefix(body, "This PR addresses the issue described in TEST-1.") {
+		t.Errorf("Expected the default body without a template, got:\n%s", body)
+	}
+
+	writeRepoMapFiles(t, repoDir, map[string]string{
+		".github/PULL_REQUEST_TEMPLATE.md": "## Summary\n\n## Jira Ticket\n\n## Testing\n",
+	})
+	body := processor.pullRequestBody(run)
+	for _, want := range []string{
+		"## Summary\n\nAdd CSV export\n\nAdded an ExportInvoices handler.\n\n",
+		"## Jira Ticket\n\nResolves [TEST-1](https://jira.example.com/browse/TEST-1): Add CSV export\n\n",
+		"## Testing\n\n- test (`go test ./...`): passed",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected the body to contain %q, got:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "**Checks:**") {
+		t.Errorf("Expected the checks only in the testing section, got:\n%s", body)
+	}
+
+	config.GitHub.DisablePRTemplate = true
+	if body := processor.pullRequestBody(run); !strings.HasPrefix(body, "This PR addresses the issue described in TEST-1.") {
+		t.Errorf("Expected the default body with the template disabled, got:\n%s", body)
+	}
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// processingLogs collects the log entries of the tickets being processed, so each run's log can be attached to its
+// ticket. Entries are assigned to tickets by their ticket field.
+type processingLogs struct {
+	maxSize int
+
+	mu   sync.Mutex
+	logs map[string]*strings.Builder // Log of each ticket being captured, by ticket key
+}
+
+// newProcessingLogs creates processing logs keeping at most maxSize bytes of each ticket's log
+func newProcessingLogs(maxSize int) *processingLogs {
+	return &processingLogs{maxSize: maxSize, logs: make(map[string]*strings.Builder)}
+}
+
+// wrap returns a logger that also writes the entries naming a captured ticket to its processing log, at every level
+func (l *processingLogs) wrap(logger *zap.Logger) *zap.Logger {
+	encoderConfig := zap.NewDevelopmentEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	capture := &processingLogCore{logs: l, encoder: zapcore.NewConsoleEncoder(encoderConfig)}
+	return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(core, capture)
+	}))
+}
+
+// start starts capturing the log of a ticket
+func (l *processingLogs) start(ticketKey string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.logs[ticketKey] = &strings.Builder{}
+}
+
+// finish stops capturing the log of a ticket and returns it
+func (l *processingLogs) finish(ticketKey string) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	log, ok := l.logs[ticketKey]
+	if !ok {
+		return ""
+	}
+	delete(l.logs, ticketKey)
+	return log.String()
+}
+
+// write appends a line to the log of a ticket being captured. Once the log outgrows the maximum size its oldest
+// lines are dropped, since the end of a run tells how it went.
+func (l *processingLogs) write(ticketKey, line string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	log, ok := l.logs[ticketKey]
+	if !ok {
+		return
+	}
+	log.WriteString(line)
+	if log.Len() <= l.maxSize {
+		return
+	}
+
+	content := log.String()
+	content = content[len(content)-l.maxSize*3/4:]
+	if newline := strings.IndexByte(content, '\n'); newline >= 0 {
+		content = content[newline+1:]
+	}
+	log.Reset()
+	log.WriteString("[earlier entries dropped]\n")
+	log.WriteString(content)
+}
+
+// processingLogCore is a zapcore.Core writing entries naming a captured ticket to its processing log
+type processingLogCore struct {
+	logs    *processingLogs
+	encoder zapcore.Encoder
+	fields  []zapcore.Field // Fields added with With
+}
+
+func (c *processingLogCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+func (c *processingLogCore) With(fields []zapcore.Field) zapcore.Core {
+	encoder := c.encoder.Clone()
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+	return &processingLogCore{logs: c.logs, encoder: encoder, fields: append(append([]zapcore.Field{}, c.fields...), fields...)}
+}
+
+func (c *processingLogCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return checked.AddCore(entry, c)
+}
+
+func (c *processingLogCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	ticketKey := ticketField(c.fields)
+	if key := ticketField(fields); key != "" {
+		ticketKey = key
+	}
+	if ticketKey == "" {
+		return nil
+	}
+	line, err := c.encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return fmt.Errorf("failed to encode log entry: %w", err)
+	}
+	c.logs.write(ticketKey, line.String())
+	line.Free()
+	return nil
+}
+
+func (c *processingLogCore) Sync() error {
+	return nil
+}
+
+// ticketField returns the value of the ticket field among the fields, if any
+func ticketField(fields []zapcore.Field) string {
+	for _, field := range fields {
+		if field.Key == "ticket" && field.Type == zapcore.StringType {
+			return field.String
+		}
+	}
+	return ""
+}
+
+// attachProcessingLog attaches the captured log of a ticket's run to the ticket, with a summary of the run: its
+// outcome, timing and what the AI reported. The log is masked like the application log.
+func (p *TicketProcessorImpl) attachProcessingLog(ctx context.Context, run *TicketRun, started time.Time, runErr error) {
+	log := p.processingLogs.finish(run.Key)
+
+	outcome := "completed"
+	if runErr != nil {
+		outcome = "failed: " + runErr.Error()
+	}
+	finished := time.Now()
+	var content strings.Builder
+	fmt.Fprintf(&content, "Ticket:   %s\n", run.Key)
+	fmt.Fprintf(&content, "Started:  %s\n", started.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&content, "Finished: %s\n", finished.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&content, "Duration: %s\n", finished.Sub(started).Round(time.Second))
+	fmt.Fprintf(&content, "Outcome:  %s\n", outcome)
+	for _, pr := range run.PRs {
+		fmt.Fprintf(&content, "PR:       %s\n", pr.HTMLURL)
+	}
+	if summary := aiResultText(run.AIResponse); summary != "" {
+		fmt.Fprintf(&content, "\n== AI summary ==\n%s\n", summary)
+	}
+	fmt.Fprintf(&content, "\n== Log ==\n%s", log)
+
+	filename := fmt.Sprintf("ai-processing-%s-%s.log", run.Key, started.UTC().Format("20060102-150405"))
+	if err := p.jiraService.AddAttachment(ctx, run.Key, filename, []byte(RedactSecrets(p.config, content.String()))); err != nil {
+		p.logger.Error("Failed to attach processing log", zap.String("ticket", run.Key), zap.Error(err))
+		return
+	}
+	p.logger.Info("Attached processing log", zap.String("ticket", run.Key), zap.String("file", filename))
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestTicketProcessor_AttachProcessingLog(t *testing.T) {
+	attachments := make(map[string]string)
+	var filenames []string
+	mockJiraService := &mocks.MockJiraService{
+		GetTicketFunc: func(key string) (*models.JiraTicketResponse, error) {
+			return &models.JiraTicketResponse{
+				Key: key,
+				Fields: models.JiraFields{
+					Summary:    "Test ticket",
+					Components: []models.JiraComponent{{ID: "1", Name: "frontend"}},
+				},
+			}, nil
+		},
+		AddAttachmentFunc: func(key, filename string, content []byte) error {
+			attachments[key] = string(content)
+			filenames = append(filenames, filename)
+			return nil
+		},
+	}
+	mockGitHubService := &mocks.MockGitHubService{
+		CheckForkExistsFunc: func(owner, repo string) (exists bool, cloneURL string, err error) {
+			return true, "https://github.com/mockuser/frontend.git", nil
+		},
+		CreatePullRequestFunc: func(owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error) {
+			return &models.GitHubCreatePRResponse{Number: 1, HTMLURL: "https://github.com/example/frontend/pull/1"}, nil
+		},
+	}
+	mockClaudeService := &mocks.MockClaudeService{
+		GenerateCodeFunc: func(prompt string, repoDir string) (*models.ClaudeResponse, error) {
+			if strings.Contains(prompt, "TEST-2") {
+				return nil, fmt.Errorf("AI timed out using token pat-secret-value")
+			}
+			return &models.ClaudeResponse{Result: "Fixed the null check in the login form"}, nil
+		},
+	}
+
+	config := &models.Config{}
+	config.ComponentToRepo = map[string]string{"frontend": "https://github.com/example/frontend.git"}
+	config.TempDir = t.TempDir()
+	config.GitHub.PersonalAccessToken = "pat-secret-value"
+	config.ProcessingLogs.Attach = true
+	config.ProcessingLogs.MaxSizeKB = 1024
+
+	processor := NewTicketProcessor(mockJiraService, mockGitHubService, mockClaudeService, newTestStateMachine(config), config, zap.NewNop())
+	if err := processor.ProcessTicket(context.Background(), "TEST-1"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if err := processor.ProcessTicket(context.Background(), "TEST-2"); err == nil {
+		t.Fatal("Expected the AI error to be returned")
+	}
+
+	if len(filenames) != 2 || !strings.HasPrefix(filenames[0], "ai-processing-TEST-1-") || !strings.HasSuffix(filenames[0], ".log") {
+		t.Fatalf("Expected a log file attached to each ticket, got %v", filenames)
+	}
+
+	success := attachments["TEST-1"]
+	for _, want := range []string{
+		"Outcome:  completed",
+		"PR:       https://github.com/example/frontend/pull/1",
+		"== AI summary ==\nFixed the null check in the login form",
+		"Finished pipeline step",
+		"Successfully processed ticket",
+	} {
+		if !strings.Contains(success, want) {
+			t.Errorf("Expected the log of TEST-1 to contain %q, got:\n%s", want, success)
+		}
+	}
+	if strings.Contains(success, "TEST-2") {
+		t.Errorf("Expected the log of TEST-1 not to contain entries of TEST-2, got:\n%s", success)
+	}
+
+	failure := attachments["TEST-2"]
+	if !strings.Contains(failure, "Outcome:  failed: AI timed out using token [REDACTED]") || !strings.Contains(failure, "Failed to generate code changes") {
+		t.Errorf("Expected the log of TEST-2 to report the masked failure, got:\n%s", failure)
+	}
+	if strings.Contains(failure, "pat-secret-value") {
+		t.Errorf("Expected the token to be masked, got:\n%s", failure)
+	}
+}
+
+func TestProcessingLogs_Truncate(t *testing.T) {
+	logs := newProcessingLogs(100)
+	logs.start("TEST-1")
+	for i := 0; i < 20; i++ {
+		logs.write("TEST-1", fmt.Sprintf("entry %02d\n", i))
+	}
+	logs.write("TEST-2", "not captured\n")
+
+	log := logs.finish("TEST-1")
+	if !strings.HasPrefix(log, "[earlier entries dropped]\n") || !strings.HasSuffix(log, "entry 19\n") {
+		t.Errorf("Expected the oldest entries to be dropped, got:\n%s", log)
+	}
+	if len(log) > 100+len("[earlier entries dropped]\n") {
+		t.Errorf("Expected the log to stay within the maximum size, got %d bytes", len(log))
+	}
+	if logs.finish("TEST-2") != "" {
+		t.Error("Expected tickets that aren't captured to have no log")
+	}
+}
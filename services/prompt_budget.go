@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// maxCommentExcerpt is the length of the excerpt of an older comment listed instead of the comment
+const maxCommentExcerpt = 200
+
+// maxSummaryInputTokens is the estimated size of the older comments given to the AI to summarize; the oldest are left
+// out beyond it
+const maxSummaryInputTokens = 20000
+
+// maxDiffSummaryHunks is the number of hunk headers listed for a file whose diff is left out
+const maxDiffSummaryHunks = 30
+
+// diffHunkHeaderPattern matches the header of a diff hunk
+var diffHunkHeaderPattern = regexp.MustCompile(`^@@ [^@]+ @@`)
+
+// condenseComments keeps the ticket's newest comments within the prompt's budget and replaces the older ones by a
+// summary, so long comment threads don't exceed the AI's context or the CLI's argument limit. Failing to summarize
+// with the AI falls back to excerpts of the older comments.
+func (p *TicketProcessorImpl) condenseComments(ctx context.Context, run *TicketRun) {
+	settings := p.config.PromptLimits()
+	var comments []models.JiraComment
+	for _, comment := range run.Ticket.Fields.Comment.Comments {
+		if comment.Author.Name != p.config.Jira.Username {
+			comments = append(comments, comment)
+		}
+	}
+	kept, older, truncated := splitComments(comments, settings.RecentComments, settings.MaxCommentTokens)
+	if len(older) == 0 && !truncated {
+		return
+	}
+
+	summary := ""
+	if len(older) > 0 && settings.Summarize {
+		var err error
+		summary, err = p.summarizeComments(ctx, run, older)
+		if err != nil {
+			p.logger.Warn("Failed to summarize the older comments, using excerpts",
+				zap.String("ticket", run.Key),
+				zap.Error(err))
+		}
+	}
+	if len(older) > 0 && summary == "" {
+		summary = excerptComments(older)
+	}
+	p.logger.Info("Condensed the ticket's comments for the prompt",
+		zap.String("ticket", run.Key),
+		zap.Int("kept", len(kept)),
+		zap.Int("summarized", len(older)))
+
+	// The ticket may be shared with the run's Jira cache, so the condensed comments go to a copy
+	condensed := *run.Ticket
+	condensed.Fields.Comment.Comments = kept
+	condensed.CommentSummary = summary
+	run.Ticket = &condensed
+}
+
+// splitComments splits comments, oldest first, into the newest ones fitting in maxTokens, always at least the recent
+// ones, and the older ones. Kept comments longer than their share of the budget are truncated.
+func splitComments(comments []models.JiraComment, recent, maxTokens int) (kept, older []models.JiraComment, truncated bool) {
+	total := 0
+	for _, comment := range comments {
+		total += promptTokenCount(len(comment.Body))
+	}
+	if total <= maxTokens {
+		return comments, nil, false
+	}
+
+	maxCommentTokens := maxTokens / max(recent, 1)
+	start, used := len(comments), 0
+	for start > 0 {
+		tokens := min(promptTokenCount(len(comments[start-1].Body)), maxCommentTokens)
+		if len(comments)-start >= recent && used+tokens > maxTokens {
+			break
+		}
+		used += tokens
+		start--
+	}
+
+	for _, comment := range comments[start:] {
+		if promptTokenCount(len(comment.Body)) > maxCommentTokens {
+			comment.Body = truncateAtRune(comment.Body, maxCommentTokens*charsPerToken) + "\n[comment truncated]"
+			truncated = true
+		}
+		kept = append(kept, comment)
+	}
+	return kept, comments[:start], truncated
+}
+
+// truncateAtRune cuts s to at most n bytes, backing off to the start of a character so multi-byte text stays valid
+func truncateAtRune(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// excerptComments lists comments by their author, date and the start of their text
+func excerptComments(comments []models.JiraComment) string {
+	var sb strings.Builder
+	for _, comment := range comments {
+		text := strings.Join(strings.Fields(comment.Body), " ")
+		if len(text) > maxCommentExcerpt {
+			text = truncateAtRune(text, maxCommentExcerpt) + "..."
+		}
+		sb.WriteString(fmt.Sprintf("- %s (%s): %s\n", comment.Author.DisplayName, comment.Created.Format("2006-01-02"), text))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// summarizeComments asks the AI to summarize comments, in an empty directory so the run only sees the comments
+func (p *TicketProcessorImpl) summarizeComments(ctx context.Context, run *TicketRun, comments []models.JiraComment) (string, error) {
+	dir := filepath.Join(p.config.TempDir, run.Key+"-summary")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create summary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	prompt := commentSummaryPrompt(comments)
+	started := time.Now()
+	response, err := p.aiService.GenerateCode(ctx, prompt, dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize comments: %w", err)
+	}
+	p.recordUsage(run, prompt, time.Since(started), response)
+
+	summary := strings.TrimSpace(aiResultText(response))
+	if summary == "" {
+		return "", errors.New("the AI returned an empty summary")
+	}
+	return summary, nil
+}
+
+// commentSummaryPrompt generates a prompt for the AI service to summarize comments, the newest ones if they are too
+// long to pass at once
+func commentSummaryPrompt(comments []models.JiraComment) string {
+	var entries []string
+	used := 0
+	for i := len(comments) - 1; i >= 0; i-- {
+		entry := fmt.Sprintf("%s (%s):\n%s\n", comments[i].Author.DisplayName, comments[i].Created.Format("2006-01-02"), comments[i].Body)
+		if used+promptTokenCount(len(entry)) > maxSummaryInputTokens {
+			if len(entries) > 0 {
+				break
+			}
+			entry = truncateAtRune(entry, maxSummaryInputTokens*charsPerToken) + "\n[comment truncated]\n"
+		}
+		used += promptTokenCount(len(entry))
+		entries = append([]string{entry}, entries...)
+	}
+
+	var prompt strings.Builder
+	prompt.WriteString("Summarize the following comments of a Jira ticket for a developer who will implement the ticket. ")
+	prompt.WriteString("Keep requirements, decisions, constraints, reproduction steps and names of files, functions and ")
+	prompt.WriteString("errors; leave out greetings and status updates. Answer with at most 10 bullet points and nothing else. ")
+	prompt.WriteString("Do not read or change any files.\n\n")
+	prompt.WriteString(strings.Join(entries, "\n"))
+	return prompt.String()
+}
+
+// condenseDiffs replaces the patches of the largest files by their hunk headers until the patches fit in maxTokens,
+// since the AI can read the files in the checkout
+func condenseDiffs(files []models.GitHubPRFile, maxTokens int) []models.GitHubPRFile {
+	total := 0
+	for _, file := range files {
+		total += promptTokenCount(len(file.Patch))
+	}
+	if total <= maxTokens {
+		return files
+	}
+
+	condensed := make([]models.GitHubPRFile, len(files))
+	copy(condensed, files)
+	bySize := make([]int, len(condensed))
+	for i := range bySize {
+		bySize[i] = i
+	}
+	sort.SliceStable(bySize, func(i, j int) bool {
+		return len(condensed[bySize[i]].Patch) > len(condensed[bySize[j]].Patch)
+	})
+	for _, i := range bySize {
+		if total <= maxTokens {
+			break
+		}
+		summary := diffSummary(condensed[i].Patch)
+		total -= promptTokenCount(len(condensed[i].Patch)) - promptTokenCount(len(summary))
+		condensed[i].Patch = summary
+	}
+	return condensed
+}
+
+// diffSummary shortens a patch to the headers of its hunks, which name the changed lines and their enclosing
+// function
+func diffSummary(patch string) string {
+	var headers []string
+	hunks := 0
+	for _, line := range strings.Split(patch, "\n") {
+		if !diffHunkHeaderPattern.MatchString(line) {
+			continue
+		}
+		hunks++
+		if hunks <= maxDiffSummaryHunks {
+			headers = append(headers, line)
+		}
+	}
+	if hunks > maxDiffSummaryHunks {
+		headers = append(headers, fmt.Sprintf("... and %d more hunks", hunks-maxDiffSummaryHunks))
+	}
+	return fmt.Sprintf("[diff of %d lines left out; read the file in the checkout. Changed hunks:]\n%s",
+		strings.Count(patch, "\n")+1, strings.Join(headers, "\n"))
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+)
+
+func newBudgetTestComments(count, length int) []models.JiraComment {
+	comments := make([]models.JiraComment, count)
+	for i := range comments {
+		comments[i] = models.JiraComment{
+			ID:     fmt.Sprint(i),
+			Body:   fmt.Sprintf("comment %d ", i) + strings.Repeat("x", length),
+			Author: models.JiraUser{Name: "jane", DisplayName: "Jane"},
+		}
+	}
+	return comments
+}
+
+func TestSplitComments(t *testing.T) {
+	comments := newBudgetTestComments(10, 400)
+
+	kept, older, truncated := splitComments(comments, 2, 10000)
+	if len(kept) != 10 || len(older) != 0 || truncated {
+		t.Errorf("Expected all comments within the budget to be kept, got %d kept, %d older", len(kept), len(older))
+	}
+
+	kept, older, truncated = splitComments(comments, 2, 400)
+	if len(kept) != 3 || len(older) != 7 || truncated {
+		t.Fatalf("Expected the newest comments fitting the budget to be kept, got %d kept, %d older", len(kept), len(older))
+	}
+	if kept[0].ID != "7" || older[len(older)-1].ID != "6" {
+		t.Errorf("Expected the split to keep the order of the comments, got kept from %s and older to %s", kept[0].ID, older[len(older)-1].ID)
+	}
+
+	kept, older, truncated = splitComments(comments, 4, 200)
+	if len(kept) != 4 || len(older) != 6 || !truncated {
+		t.Fatalf("Expected the recent comments to be kept truncated, got %d kept, %d older, truncated %t", len(kept), len(older), truncated)
+	}
+	if !strings.HasSuffix(kept[0].Body, "[comment truncated]") || len(kept[0].Body) > 50*4+len("\n[comment truncated]") {
+		t.Errorf("Expected the comment to be truncated to its share of the budget, got %d characters", len(kept[0].Body))
+	}
+	if strings.HasSuffix(comments[6].Body, "[comment truncated]") {
+		t.Error("Expected the original comments to be left unchanged")
+	}
+}
+
+func TestTicketProcessor_CondenseComments(t *testing.T) {
+	newRun := func() *TicketRun {
+		ticket := &models.JiraTicketResponse{Key: "TEST-1"}
+		ticket.Fields.Comment.Comments = append(newBudgetTestComments(6, 400),
+			models.JiraComment{Body: strings.Repeat("progress ", 200), Author: models.JiraUser{Name: "bot"}})
+		return &TicketRun{Key: "TEST-1", Ticket: ticket}
+	}
+
+	t.Run("excerpts", func(t *testing.T) {
+		config := &models.Config{}
+		config.Jira.Username = "bot"
+		config.AI.Prompt.MaxCommentTokens = 250
+		config.AI.Prompt.RecentComments = 2
+		processor := newCheckTestProcessor(t, config, &mocks.MockClaudeService{}, &mocks.MockJiraService{}, &mocks.MockGitHubService{})
+
+		run := newRun()
+		original := run.Ticket
+		processor.condenseComments(context.Background(), run)
+
+		if len(run.Ticket.Fields.Comment.Comments) != 2 {
+			t.Errorf("Expected the 2 newest comments to be kept, got %d", len(run.Ticket.Fields.Comment.Comments))
+		}
+		if !strings.HasPrefix(run.Ticket.CommentSummary, "- Jane (") || strings.Count(run.Ticket.CommentSummary, "\n") != 3 {
+			t.Errorf("Expected excerpts of the 4 older comments, got %q", run.Ticket.CommentSummary)
+		}
+		if len(original.Fields.Comment.Comments) != 7 || original.CommentSummary != "" {
+			t.Error("Expected the fetched ticket to be left unchanged")
+		}
+		if prompt := generatePrompt(config, run.Ticket); !strings.Contains(prompt, "Summary of earlier comments:\n- Jane") {
+			t.Errorf("Expected the summary in the prompt, got %q", prompt)
+		}
+	})
+
+	t.Run("AI summary", func(t *testing.T) {
+		config := &models.Config{}
+		config.Jira.Username = "bot"
+		config.AI.Prompt.MaxCommentTokens = 250
+		config.AI.Prompt.RecentComments = 2
+		config.AI.Prompt.Summarize = true
+		var summaryPrompt, summaryDir string
+		aiService := &mocks.MockClaudeService{
+			GenerateCodeFunc: func(prompt, dir string) (*models.ClaudeResponse, error) {
+				summaryPrompt, summaryDir = prompt, dir
+				return &models.ClaudeResponse{Result: "- Export must stream rows\n"}, nil
+			},
+		}
+		processor := newCheckTestProcessor(t, config, aiService, &mocks.MockJiraService{}, &mocks.MockGitHubService{})
+
+		run := newRun()
+		processor.condenseComments(context.Background(), run)
+
+		if run.Ticket.CommentSummary != "- Export must stream rows" {
+			t.Errorf("Expected the AI's summary, got %q", run.Ticket.CommentSummary)
+		}
+		if !strings.Contains(summaryPrompt, "comment 0 ") || strings.Contains(summaryPrompt, "comment 5 ") || strings.Contains(summaryPrompt, "progress") {
+			t.Errorf("Expected only the older comments to be summarized, got %q", summaryPrompt)
+		}
+		if !strings.HasSuffix(summaryDir, "TEST-1-summary") {
+			t.Errorf("Expected the summary to run in its own directory, got %q", summaryDir)
+		}
+	})
+
+	t.Run("AI failure", func(t *testing.T) {
+		config := &models.Config{}
+		config.AI.Prompt.MaxCommentTokens = 250
+		config.AI.Prompt.RecentComments = 2
+		config.AI.Prompt.Summarize = true
+		aiService := &mocks.MockClaudeService{
+			GenerateCodeFunc: func(prompt, dir string) (*models.ClaudeResponse, error) {
+				return nil, errors.New("rate limited")
+			},
+		}
+		processor := newCheckTestProcessor(t, config, aiService, &mocks.MockJiraService{}, &mocks.MockGitHubService{})
+
+		run := newRun()
+		processor.condenseComments(context.Background(), run)
+		if !strings.HasPrefix(run.Ticket.CommentSummary, "- Jane (") {
+			t.Errorf("Expected excerpts when the AI fails, got %q", run.Ticket.CommentSummary)
+		}
+	})
+}
+
+func TestCondenseDiffs(t *testing.T) {
+	large := "@@ -1,3 +1,400 @@ func Export()\n" + strings.Repeat("+row\n", 400) + "@@ -500,2 +900,3 @@ func Close()\n+x"
+	files := []models.GitHubPRFile{
+		{Filename: "small.go", Patch: "@@ -1 +1 @@\n-a\n+b"},
+		{Filename: "large.go", Patch: large},
+	}
+
+	if got := condenseDiffs(files, 10000); got[1].Patch != large {
+		t.Error("Expected diffs within the budget to be kept")
+	}
+
+	got := condenseDiffs(files, 100)
+	if got[0].Patch != files[0].Patch {
+		t.Errorf("Expected the small diff to be kept, got %q", got[0].Patch)
+	}
+	want := "[diff of 403 lines left out; read the file in the checkout. Changed hunks:]\n" +
+		"@@ -1,3 +1,400 @@ func Export()\n@@ -500,2 +900,3 @@ func Close()"
+	if got[1].Patch != want {
+		t.Errorf("Expected the large diff to be summarized by its hunks, got %q", got[1].Patch)
+	}
+	if files[1].Patch != large {
+		t.Error("Expected the pull request's files to be left unchanged")
+	}
+}
+
+func TestSplitComments_NonASCII(t *testing.T) {
+	comments := []models.JiraComment{
+		{ID: "0", Body: "x" + strings.Repeat("日本語", 200)},
+		{ID: "1", Body: strings.Repeat("ü", 300)},
+	}
+
+	kept, _, truncated := splitComments(comments, 2, 100)
+	if !truncated {
+		t.Fatal("Expected the comments to be truncated")
+	}
+	for _, comment := range kept {
+		if !utf8.ValidString(comment.Body) {
+			t.Errorf("Expected comment %s to be cut at a character boundary, got invalid UTF-8", comment.ID)
+		}
+	}
+	if excerpt := excerptComments(comments); !utf8.ValidString(excerpt) {
+		t.Error("Expected the excerpts to be cut at a character boundary, got invalid UTF-8")
+	}
+}
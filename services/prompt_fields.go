@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// resolvePromptFields adds the values of the configured custom fields, such as acceptance criteria, to the ticket for
+// the prompt. Fields that can't be resolved are left out of the prompt rather than failing the ticket.
+func (p *TicketProcessorImpl) resolvePromptFields(ctx context.Context, run *TicketRun) {
+	names := p.config.PromptFieldNames()
+	if len(names) == 0 {
+		return
+	}
+
+	fieldIDs := make(map[string]string, len(names))
+	for _, name := range names {
+		fieldID, err := p.jiraService.GetFieldIDByName(ctx, name)
+		if err != nil {
+			p.logger.Warn("Failed to resolve prompt field, leaving it out of the prompt",
+				zap.String("ticket", run.Key),
+				zap.String("field", name),
+				zap.Error(err))
+			continue
+		}
+		fieldIDs[name] = fieldID
+	}
+	if len(fieldIDs) == 0 {
+		return
+	}
+
+	fields, _, err := p.jiraService.GetTicketWithExpandedFields(ctx, run.Key)
+	if err != nil {
+		p.logger.Warn("Failed to get the ticket's custom fields, leaving them out of the prompt",
+			zap.String("ticket", run.Key),
+			zap.Error(err))
+		return
+	}
+
+	run.Ticket.PromptFields = nil
+	for _, name := range names {
+		fieldID, ok := fieldIDs[name]
+		if !ok {
+			continue
+		}
+		if value := promptFieldValue(fields[fieldID]); value != "" {
+			run.Ticket.PromptFields = append(run.Ticket.PromptFields, models.JiraPromptField{Name: name, Value: value})
+		}
+	}
+}
+
+// promptFieldValue renders the value of a custom field as text: options by their value or name, and lists one item
+// per line
+func promptFieldValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return fmt.Sprintf("%g", v)
+	case bool:
+		return fmt.Sprintf("%t", v)
+	case map[string]interface{}:
+		for _, key := range []string{"value", "name", "displayName", "key"} {
+			if text, ok := v[key].(string); ok {
+				return strings.TrimSpace(text)
+			}
+		}
+		return ""
+	case []interface{}:
+		var items []string
+		for _, item := range v {
+			if text := promptFieldValue(item); text != "" {
+				items = append(items, "- "+text)
+			}
+		}
+		return strings.Join(items, "\n")
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
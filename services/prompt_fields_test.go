@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+)
+
+func TestTicketProcessor_ResolvePromptFields(t *testing.T) {
+	config := &models.Config{}
+	config.Jira.PromptFields = []string{"Acceptance Criteria", "Unknown", "Definition of Done", "Story Points"}
+	jiraService := &mocks.MockJiraService{
+		GetFieldIDByNameFunc: func(fieldName string) (string, error) {
+			switch fieldName {
+			case "Acceptance Criteria":
+				return "customfield_1", nil
+			case "Definition of Done":
+				return "customfield_2", nil
+			case "Story Points":
+				return "customfield_3", nil
+			}
+			return "", errors.New("no such field")
+		},
+		GetTicketWithExpandedFieldsFunc: func(key string) (map[string]interface{}, map[string]string, error) {
+			return map[string]interface{}{
+				"customfield_1": "  Users can export reports as CSV.\n",
+				"customfield_2": []interface{}{map[string]interface{}{"value": "Tests added"}, map[string]interface{}{"value": "Docs updated"}},
+				"customfield_3": nil,
+			}, nil, nil
+		},
+	}
+	processor := newCheckTestProcessor(t, config, &mocks.MockClaudeService{}, jiraService, &mocks.MockGitHubService{})
+
+	run := &TicketRun{Key: "TEST-1", Ticket: &models.JiraTicketResponse{Key: "TEST-1"}}
+	processor.resolvePromptFields(context.Background(), run)
+
+	want := []models.JiraPromptField{
+		{Name: "Acceptance Criteria", Value: "Users can export reports as CSV."},
+		{Name: "Definition of Done", Value: "- Tests added\n- Docs updated"},
+	}
+	if len(run.Ticket.PromptFields) != len(want) {
+		t.Fatalf("PromptFields = %+v, want %+v", run.Ticket.PromptFields, want)
+	}
+	for i := range want {
+		if run.Ticket.PromptFields[i] != want[i] {
+			t.Errorf("PromptFields[%d] = %+v, want %+v", i, run.Ticket.PromptFields[i], want[i])
+		}
+	}
+
+	prompt := generatePrompt(config, run.Ticket)
+	if !strings.Contains(prompt, "Acceptance Criteria:\nUsers can export reports as CSV.\n\n") {
+		t.Errorf("Expected the acceptance criteria in the prompt, got %q", prompt)
+	}
+	if !strings.Contains(PreparePrompt(run.Ticket), "## Definition of Done\n\n- Tests added\n- Docs updated\n\n") {
+		t.Errorf("Expected the definition of done under its heading, got %q", PreparePrompt(run.Ticket))
+	}
+}
+
+func TestPromptFieldValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{name: "nil", value: nil, want: ""},
+		{name: "number", value: float64(3), want: "3"},
+		{name: "option", value: map[string]interface{}{"id": "1", "value": "High"}, want: "High"},
+		{name: "user", value: map[string]interface{}{"displayName": "Jane Doe"}, want: "Jane Doe"},
+		{name: "labels", value: []interface{}{"api", "", "export"}, want: "- api\n- export"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := promptFieldValue(tt.value); got != tt.want {
+				t.Errorf("promptFieldValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
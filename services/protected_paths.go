@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// revertProtectedPaths reverts the AI's changes to the repository's protected paths, recording the reverted paths
+// for the pull request body
+func (p *TicketProcessorImpl) revertProtectedPaths(ctx context.Context, run *TicketRun) error {
+	patterns := append(p.config.GetProtectedPaths(run.Owner, run.Repo), run.RepoConfig.GetForbiddenPaths()...)
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	reverted, err := p.githubService.RevertProtectedPaths(ctx, run.RepoDir, patterns)
+	if err != nil {
+		p.logger.Error("Failed to revert changes to protected paths",
+			zap.String("ticket", run.Key),
+			zap.String("repo_dir", run.RepoDir),
+			zap.Error(err))
+		p.handleFailure(ctx, run.Key, fmt.Sprintf("Failed to revert changes to protected paths: %v", err))
+		return err
+	}
+	if len(reverted) == 0 {
+		return nil
+	}
+
+	p.logger.Warn("Reverted AI changes to protected paths",
+		zap.String("ticket", run.Key),
+		zap.Strings("paths", reverted))
+	for _, path := range reverted {
+		if !slices.Contains(run.RevertedPaths, path) {
+			run.RevertedPaths = append(run.RevertedPaths, path)
+		}
+	}
+	return nil
+}
+
+// protectedPathsNote returns the note of the pull request body listing the protected paths whose changes were reverted
+func protectedPathsNote(reverted []string) string {
+	if len(reverted) == 0 {
+		return ""
+	}
+	paths := make([]string, 0, len(reverted))
+	for _, path := range reverted {
+		paths = append(paths, "`"+path+"`")
+	}
+	return "\n\n**Note:** The AI's changes to protected paths were reverted: " + strings.Join(paths, ", ")
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+)
+
+func TestTicketProcessor_RevertProtectedPaths(t *testing.T) {
+	config := &models.Config{}
+	config.ProtectedPaths.Paths = []string{"LICENSE"}
+	config.ProtectedPaths.Repositories = map[string][]string{"owner/repo": {"deploy/"}}
+
+	var patterns []string
+	githubService := &mocks.MockGitHubService{
+		RevertProtectedPathsFunc: func(directory string, p []string) ([]string, error) {
+			patterns = p
+			return []string{"deploy/app.yaml"}, nil
+		},
+	}
+	processor := newCheckTestProcessor(t, config, &mocks.MockClaudeService{}, &mocks.MockJiraService{}, githubService)
+
+	run := &TicketRun{Key: "TEST-1", Owner: "owner", Repo: "repo", RepoDir: t.TempDir()}
+	for i := 0; i < 2; i++ {
+		if err := processor.revertProtectedPaths(context.Background(), run); err != nil {
+			t.Fatalf("revertProtectedPaths() error = %v", err)
+		}
+	}
+	if strings.Join(patterns, ",") != "LICENSE,deploy/" {
+		t.Errorf("Expected the global and repository patterns, got %q", patterns)
+	}
+	if len(run.RevertedPaths) != 1 || run.RevertedPaths[0] != "deploy/app.yaml" {
+		t.Errorf("Expected the reverted path to be recorded once, got %q", run.RevertedPaths)
+	}
+	if note := protectedPathsNote(run.RevertedPaths); !strings.Contains(note, "`deploy/app.yaml`") {
+		t.Errorf("Expected the note to list the reverted path, got %q", note)
+	}
+}
+
+func TestTicketProcessor_RevertProtectedPaths_NoPatterns(t *testing.T) {
+	githubService := &mocks.MockGitHubService{
+		RevertProtectedPathsFunc: func(directory string, patterns []string) ([]string, error) {
+			t.Error("Expected nothing to be reverted without protected paths")
+			return nil, nil
+		},
+	}
+	processor := newCheckTestProcessor(t, &models.Config{}, &mocks.MockClaudeService{}, &mocks.MockJiraService{}, githubService)
+
+	if err := processor.revertProtectedPaths(context.Background(), &TicketRun{Key: "TEST-1", Owner: "owner", Repo: "repo"}); err != nil {
+		t.Fatalf("revertProtectedPaths() error = %v", err)
+	}
+	if note := protectedPathsNote(nil); note != "" {
+		t.Errorf("Expected no note, got %q", note)
+	}
+}
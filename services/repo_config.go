@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// loadRepoConfig reads the repository's checked-in configuration file from the checkout, if it has one. An invalid
+// file fails the ticket, since ignoring it could let the AI change paths the repository forbids.
+func (p *TicketProcessorImpl) loadRepoConfig(ctx context.Context, run *TicketRun) error {
+	data, err := os.ReadFile(filepath.Join(run.RepoDir, models.RepoConfigFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err == nil {
+		run.RepoConfig, err = models.ParseRepoConfig(data)
+	}
+	if err != nil {
+		p.logger.Error("Failed to load the repository configuration",
+			zap.String("ticket", run.Key),
+			zap.String("file", models.RepoConfigFile),
+			zap.Error(err))
+		p.handleFailure(ctx, run.Key, fmt.Sprintf("Invalid %s in the repository: %v", models.RepoConfigFile, err))
+		return err
+	}
+
+	p.logger.Info("Loaded the repository configuration",
+		zap.String("ticket", run.Key),
+		zap.String("file", models.RepoConfigFile),
+		zap.Bool("instructions", run.RepoConfig.Instructions != ""),
+		zap.Int("forbidden_paths", len(run.RepoConfig.ForbiddenPaths)))
+	return nil
+}
+
+// checkConfig returns the check of the given name (build, test or lint) of the ticket: the repository's, or the
+// component's configured one
+func (p *TicketProcessorImpl) checkConfig(run *TicketRun, name string) models.CheckCommandConfig {
+	var configured models.CheckCommandConfig
+	switch name {
+	case "build":
+		configured = p.config.GetBuildCheck(run.Component)
+	case "test":
+		configured = p.config.GetTestCheck(run.Component)
+	case "lint":
+		configured = p.config.GetLintCheck(run.Component)
+	}
+	return run.RepoConfig.GetCheck(name, configured)
+}
+
+// branchName returns the name of the ticket's branch from the branch template, behind the repository's branch prefix
+// or the configured one. A name the template can't render falls back to the ticket key.
+func (p *TicketProcessorImpl) branchName(run *TicketRun) string {
+	data := models.BranchNameData{
+		Prefix:    run.RepoConfig.GetBranchPrefix(p.config.GitHub.Branch.Prefix),
+		Key:       run.Key,
+		Component: run.Component,
+	}
+	if run.Ticket != nil {
+		data.Summary = run.Ticket.Fields.Summary
+		data.Type = run.Ticket.Fields.IssueType.Name
+	}
+	template := p.config.GitHub.Branch.Template
+	if template == "" {
+		template = models.DefaultBranchTemplate
+	}
+
+	name, err := models.RenderBranchName(template, p.config.GitHub.Branch.MaxLength, data)
+	if err != nil {
+		p.logger.Warn("Failed to name the branch from the branch template, using the ticket key",
+			zap.String("ticket", run.Key),
+			zap.Error(err))
+		return run.Key
+	}
+	return name
+}
+
+// repoInstructionsPrompt returns the repository's instructions for the generation prompt, or an empty string if it
+// has none
+func repoInstructionsPrompt(run *TicketRun) string {
+	if run.RepoConfig == nil || strings.TrimSpace(run.RepoConfig.Instructions) == "" {
+		return ""
+	}
+	return fmt.Sprintf("\n\nInstructions from the repository's %s:\n%s", models.RepoConfigFile, strings.TrimSpace(run.RepoConfig.Instructions))
+}
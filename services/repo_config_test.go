@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+)
+
+func TestTicketProcessor_LoadRepoConfig(t *testing.T) {
+	repoDir := t.TempDir()
+	data := "instructions: Run make generate after changing the API.\nforbidden_paths: [migrations/]\ntest:\n  command: make test\n"
+	if err := os.WriteFile(filepath.Join(repoDir, models.RepoConfigFile), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config := &models.Config{}
+	config.Test = models.CheckCommandConfig{Command: "go test ./..."}
+	config.ProtectedPaths.Paths = []string{"LICENSE"}
+
+	var patterns []string
+	githubService := &mocks.MockGitHubService{
+		RevertProtectedPathsFunc: func(directory string, p []string) ([]string, error) {
+			patterns = p
+			return nil, nil
+		},
+	}
+	processor := newCheckTestProcessor(t, config, &mocks.MockClaudeService{}, &mocks.MockJiraService{}, githubService)
+
+	run := &TicketRun{Key: "TEST-1", RepoDir: repoDir}
+	if err := processor.loadRepoConfig(context.Background(), run); err != nil {
+		t.Fatalf("loadRepoConfig() error = %v", err)
+	}
+	if check := processor.checkConfig(run, "test"); check.Command != "make test" {
+		t.Errorf("Expected the repository's test command, got %q", check.Command)
+	}
+	if prompt := repoInstructionsPrompt(run); !strings.Contains(prompt, "Run make generate after changing the API.") {
+		t.Errorf("Expected the instructions in the prompt, got %q", prompt)
+	}
+	if err := processor.revertProtectedPaths(context.Background(), run); err != nil {
+		t.Fatalf("revertProtectedPaths() error = %v", err)
+	}
+	if strings.Join(patterns, ",") != "LICENSE,migrations/" {
+		t.Errorf("Expected the configured and forbidden paths, got %q", patterns)
+	}
+}
+
+func TestTicketProcessor_LoadRepoConfig_Missing(t *testing.T) {
+	config := &models.Config{}
+	config.Test = models.CheckCommandConfig{Command: "go test ./..."}
+	processor := newCheckTestProcessor(t, config, &mocks.MockClaudeService{}, &mocks.MockJiraService{}, &mocks.MockGitHubService{})
+
+	run := &TicketRun{Key: "TEST-1", RepoDir: t.TempDir()}
+	if err := processor.loadRepoConfig(context.Background(), run); err != nil {
+		t.Fatalf("loadRepoConfig() error = %v", err)
+	}
+	if run.RepoConfig != nil {
+		t.Errorf("Expected no repository configuration, got %+v", run.RepoConfig)
+	}
+	if check := processor.checkConfig(run, "test"); check.Command != "go test ./..." {
+		t.Errorf("Expected the configured test command, got %q", check.Command)
+	}
+	if prompt := repoInstructionsPrompt(run); prompt != "" {
+		t.Errorf("Expected no instructions, got %q", prompt)
+	}
+}
+
+func TestTicketProcessor_LoadRepoConfig_Invalid(t *testing.T) {
+	repoDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repoDir, models.RepoConfigFile), []byte("forbiden_paths: [migrations/]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var comments []string
+	jiraService := &mocks.MockJiraService{
+		AddCommentFunc: func(key string, comment string) error {
+			comments = append(comments, comment)
+			return nil
+		},
+	}
+	processor := newCheckTestProcessor(t, &models.Config{}, &mocks.MockClaudeService{}, jiraService, &mocks.MockGitHubService{})
+
+	run := &TicketRun{Key: "TEST-1", RepoDir: repoDir}
+	if err := processor.loadRepoConfig(context.Background(), run); err == nil {
+		t.Fatal("Expected an invalid file to fail the ticket")
+	}
+	if len(comments) != 1 || !strings.Contains(comments[0], models.RepoConfigFile) {
+		t.Errorf("Expected a comment naming the file, got %q", comments)
+	}
+}
+
+func TestTicketProcessor_BranchName(t *testing.T) {
+	config := &models.Config{}
+	processor := newCheckTestProcessor(t, config, &mocks.MockClaudeService{}, &mocks.MockJiraService{}, &mocks.MockGitHubService{})
+	ticket := &models.JiraTicketResponse{Key: "TEST-1"}
+	ticket.Fields.Summary = "Add CSV export"
+	run := &TicketRun{Key: "TEST-1", Ticket: ticket}
+
+	if name := processor.branchName(run); name != "TEST-1" {
+		t.Errorf("Expected the ticket key by default, got %q", name)
+	}
+
+	config.GitHub.Branch.Template = "{{.Prefix}}{{.Key}}-{{slug .Summary}}"
+	config.GitHub.Branch.Prefix = "feature/"
+	if name := processor.branchName(run); name != "feature/TEST-1-add-csv-export" {
+		t.Errorf("Expected the configured prefix and the slugged summary, got %q", name)
+	}
+
+	run.RepoConfig = &models.RepoConfig{}
+	run.RepoConfig.Branch.Prefix = "ai/"
+	if name := processor.branchName(run); name != "ai/TEST-1-add-csv-export" {
+		t.Errorf("Expected the repository's prefix, got %q", name)
+	}
+
+	config.GitHub.Branch.Template = "{{.Key}} {{.Summary}}"
+	if name := processor.branchName(run); name != "TEST-1" {
+		t.Errorf("Expected the ticket key for an invalid name, got %q", name)
+	}
+}
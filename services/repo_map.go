@@ -0,0 +1,489 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// RepoMap is a concise map of a repository checkout, added to the generation prompt so the AI doesn't spend turns
+// rediscovering the project's structure
+type RepoMap struct {
+	Languages  []LanguageCount
+	Frameworks []string
+	Commands   []RepoCommand
+	KeyFiles   []KeyFileSummary
+	RootFiles  []string
+	Dirs       []RepoMapDir // directories in tree order
+	Truncated  bool         // whether directories were left out of Dirs
+}
+
+// LanguageCount is the number of source files of a language
+type LanguageCount struct {
+	Language string
+	Files    int
+}
+
+// RepoCommand is a command building, testing or linting the repository
+type RepoCommand struct {
+	Name       string // build, test or lint
+	Command    string
+	Configured bool // whether the command is a configured check the changes must pass
+}
+
+// KeyFileSummary summarizes a top-level file describing the project
+type KeyFileSummary struct {
+	Path    string
+	Summary string
+}
+
+// RepoMapDir is a directory of the repository tree
+type RepoMapDir struct {
+	Path  string // slash-separated path relative to the checkout
+	Depth int
+	Files int // files directly in the directory
+}
+
+// repoMapSkippedDirs are dependency and build output directories left out of the map, besides hidden ones
+var repoMapSkippedDirs = map[string]bool{
+	"node_modules":     true,
+	"vendor":           true,
+	"dist":             true,
+	"target":           true,
+	"__pycache__":      true,
+	"venv":             true,
+	"bower_components": true,
+}
+
+// languageExtensions maps source file extensions to their language
+var languageExtensions = map[string]string{
+	".go":    "Go",
+	".py":    "Python",
+	".js":    "JavaScript",
+	".jsx":   "JavaScript",
+	".mjs":   "JavaScript",
+	".ts":    "TypeScript",
+	".tsx":   "TypeScript",
+	".java":  "Java",
+	".kt":    "Kotlin",
+	".rb":    "Ruby",
+	".rs":    "Rust",
+	".c":     "C",
+	".h":     "C",
+	".cc":    "C++",
+	".cpp":   "C++",
+	".hpp":   "C++",
+	".cs":    "C#",
+	".php":   "PHP",
+	".swift": "Swift",
+	".scala": "Scala",
+	".sh":    "Shell",
+	".tf":    "Terraform",
+}
+
+// frameworkMarkers detect frameworks by a dependency named in a top-level manifest
+var frameworkMarkers = []struct {
+	files     []string
+	marker    string
+	framework string
+}{
+	{[]string{"package.json"}, `"react"`, "React"},
+	{[]string{"package.json"}, `"next"`, "Next.js"},
+	{[]string{"package.json"}, `"vue"`, "Vue"},
+	{[]string{"package.json"}, `"@angular/core"`, "Angular"},
+	{[]string{"package.json"}, `"express"`, "Express"},
+	{[]string{"package.json"}, `"jest"`, "Jest"},
+	{[]string{"package.json"}, `"vitest"`, "Vitest"},
+	{[]string{"go.mod"}, "github.com/gin-gonic/gin", "Gin"},
+	{[]string{"go.mod"}, "github.com/labstack/echo", "Echo"},
+	{[]string{"go.mod"}, "github.com/spf13/cobra", "Cobra"},
+	{[]string{"go.mod"}, "k8s.io/client-go", "Kubernetes client-go"},
+	{[]string{"pom.xml", "build.gradle", "build.gradle.kts"}, "spring-boot", "Spring Boot"},
+	{[]string{"pom.xml", "build.gradle", "build.gradle.kts"}, "quarkus", "Quarkus"},
+	{[]string{"pom.xml", "build.gradle", "build.gradle.kts"}, "junit", "JUnit"},
+	{[]string{"pyproject.toml", "requirements.txt"}, "django", "Django"},
+	{[]string{"pyproject.toml", "requirements.txt"}, "flask", "Flask"},
+	{[]string{"pyproject.toml", "requirements.txt"}, "fastapi", "FastAPI"},
+	{[]string{"pyproject.toml", "requirements.txt"}, "pytest", "pytest"},
+}
+
+// repoMapKeyFiles are the top-level files summarized in the map, in the order they are shown
+var repoMapKeyFiles = []string{
+	"README.md", "README.rst", "README", "go.mod", "package.json", "Cargo.toml", "pom.xml", "build.gradle",
+	"build.gradle.kts", "pyproject.toml", "requirements.txt", "Makefile", "Dockerfile",
+}
+
+// maxRepoMapRootFiles is the number of top-level files listed by name
+const maxRepoMapRootFiles = 30
+
+// makeTargetPattern matches the targets of a Makefile rule
+var makeTargetPattern = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9_.-]*)\s*:([^=]|$)`)
+
+// buildRepoMap maps the checkout in repoDir, showing directories up to maxDepth levels deep and at most maxEntries
+// of them
+func buildRepoMap(repoDir string, maxDepth, maxEntries int) (*RepoMap, error) {
+	repoMap := &RepoMap{}
+	languages := make(map[string]int)
+	var dirs []RepoMapDir
+	dirIndex := make(map[string]int)
+
+	err := filepath.WalkDir(repoDir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(repoDir, filePath)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			return nil
+		}
+
+		if entry.IsDir() {
+			if strings.HasPrefix(entry.Name(), ".") || repoMapSkippedDirs[entry.Name()] {
+				return filepath.SkipDir
+			}
+			depth := strings.Count(rel, "/") + 1
+			if depth <= maxDepth {
+				dirIndex[rel] = len(dirs)
+				dirs = append(dirs, RepoMapDir{Path: rel, Depth: depth})
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		if language, ok := languageExtensions[strings.ToLower(path.Ext(rel))]; ok {
+			languages[language]++
+		}
+		dir := path.Dir(rel)
+		if dir == "." {
+			repoMap.RootFiles = append(repoMap.RootFiles, rel)
+		} else if i, ok := dirIndex[dir]; ok {
+			dirs[i].Files++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk repository: %w", err)
+	}
+
+	for language, files := range languages {
+		repoMap.Languages = append(repoMap.Languages, LanguageCount{Language: language, Files: files})
+	}
+	sort.Slice(repoMap.Languages, func(i, j int) bool {
+		a, b := repoMap.Languages[i], repoMap.Languages[j]
+		if a.Files != b.Files {
+			return a.Files > b.Files
+		}
+		return a.Language < b.Language
+	})
+
+	// Shallow directories are kept first when the tree is too large
+	if len(dirs) > maxEntries {
+		sort.SliceStable(dirs, func(i, j int) bool { return dirs[i].Depth < dirs[j].Depth })
+		dirs = dirs[:maxEntries]
+		sort.Slice(dirs, func(i, j int) bool { return dirs[i].Path < dirs[j].Path })
+		repoMap.Truncated = true
+	}
+	repoMap.Dirs = dirs
+
+	repoMap.summarizeKeyFiles(repoDir)
+	return repoMap, nil
+}
+
+// summarizeKeyFiles summarizes the top-level project files and detects frameworks and commands from them
+func (m *RepoMap) summarizeKeyFiles(repoDir string) {
+	contents := make(map[string][]byte)
+	for _, name := range repoMapKeyFiles {
+		data, err := os.ReadFile(filepath.Join(repoDir, name))
+		if err != nil {
+			continue
+		}
+		contents[name] = data
+		if summary := summarizeKeyFile(name, data); summary != "" {
+			m.KeyFiles = append(m.KeyFiles, KeyFileSummary{Path: name, Summary: summary})
+		}
+	}
+
+	for _, marker := range frameworkMarkers {
+		for _, file := range marker.files {
+			if bytes.Contains(bytes.ToLower(contents[file]), []byte(marker.marker)) {
+				m.Frameworks = append(m.Frameworks, marker.framework)
+				break
+			}
+		}
+	}
+
+	addCommand := func(name, command string) {
+		for _, existing := range m.Commands {
+			if existing.Name == name {
+				return
+			}
+		}
+		m.Commands = append(m.Commands, RepoCommand{Name: name, Command: command})
+	}
+	targets := makeTargets(contents["Makefile"])
+	for _, name := range []string{"build", "test", "lint"} {
+		if targets[name] {
+			addCommand(name, "make "+name)
+		}
+	}
+	if data, ok := contents["package.json"]; ok {
+		var manifest struct {
+			Scripts map[string]string `json:"scripts"`
+		}
+		if json.Unmarshal(data, &manifest) == nil {
+			for _, name := range []string{"build", "test", "lint"} {
+				if _, ok := manifest.Scripts[name]; ok {
+					addCommand(name, "npm run "+name)
+				}
+			}
+		}
+	}
+	if _, ok := contents["go.mod"]; ok {
+		addCommand("build", "go build ./...")
+		addCommand("test", "go test ./...")
+		addCommand("lint", "go vet ./...")
+	}
+	if _, ok := contents["Cargo.toml"]; ok {
+		addCommand("build", "cargo build")
+		addCommand("test", "cargo test")
+	}
+	if _, ok := contents["pom.xml"]; ok {
+		addCommand("build", "mvn -q package -DskipTests")
+		addCommand("test", "mvn -q test")
+	}
+	if contents["build.gradle"] != nil || contents["build.gradle.kts"] != nil {
+		gradle := "gradle"
+		if _, err := os.Stat(filepath.Join(repoDir, "gradlew")); err == nil {
+			gradle = "./gradlew"
+		}
+		addCommand("build", gradle+" assemble")
+		addCommand("test", gradle+" test")
+	}
+}
+
+// summarizeKeyFile summarizes a top-level project file in a line, or returns an empty string if there is nothing
+// worth saying
+func summarizeKeyFile(name string, data []byte) string {
+	switch {
+	case strings.HasPrefix(name, "README"):
+		return truncateRepoMapLine(readmeSummary(data))
+	case name == "go.mod":
+		var module, version string
+		for _, line := range strings.Split(string(data), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) == 2 && fields[0] == "module" {
+				module = fields[1]
+			}
+			if len(fields) == 2 && fields[0] == "go" {
+				version = fields[1]
+			}
+		}
+		if module == "" {
+			return "Go module"
+		}
+		if version == "" {
+			return "Go module " + module
+		}
+		return fmt.Sprintf("Go module %s (Go %s)", module, version)
+	case name == "package.json":
+		var manifest struct {
+			Name        string            `json:"name"`
+			Description string            `json:"description"`
+			Scripts     map[string]string `json:"scripts"`
+		}
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return "npm package (invalid JSON)"
+		}
+		summary := "npm package"
+		if manifest.Name != "" {
+			summary += " " + manifest.Name
+		}
+		if manifest.Description != "" {
+			summary += ": " + manifest.Description
+		}
+		if len(manifest.Scripts) > 0 {
+			scripts := make([]string, 0, len(manifest.Scripts))
+			for script := range manifest.Scripts {
+				scripts = append(scripts, script)
+			}
+			sort.Strings(scripts)
+			summary += "; scripts: " + strings.Join(scripts, ", ")
+		}
+		return truncateRepoMapLine(summary)
+	case name == "Makefile":
+		targets := makeTargets(data)
+		if len(targets) == 0 {
+			return ""
+		}
+		names := make([]string, 0, len(targets))
+		for target := range targets {
+			names = append(names, target)
+		}
+		sort.Strings(names)
+		return truncateRepoMapLine("targets: " + strings.Join(names, ", "))
+	case name == "Dockerfile":
+		for _, line := range strings.Split(string(data), "\n") {
+			if fields := strings.Fields(line); len(fields) >= 2 && strings.EqualFold(fields[0], "FROM") {
+				return "container image based on " + fields[1]
+			}
+		}
+		return "container image"
+	case name == "Cargo.toml":
+		return "Rust crate"
+	case name == "pom.xml":
+		return "Maven project"
+	case strings.HasPrefix(name, "build.gradle"):
+		return "Gradle project"
+	case name == "pyproject.toml":
+		return "Python project"
+	case name == "requirements.txt":
+		return "Python dependencies"
+	}
+	return ""
+}
+
+// readmeSummary returns the first paragraph of prose of a README, skipping headings, badges and HTML
+func readmeSummary(data []byte) string {
+	var paragraph []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		prose := line != "" && !strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "[![") &&
+			!strings.HasPrefix(line, "![") && !strings.HasPrefix(line, "<") && !strings.HasPrefix(line, "=") &&
+			!strings.HasPrefix(line, "---")
+		if prose {
+			paragraph = append(paragraph, line)
+			continue
+		}
+		if len(paragraph) > 0 {
+			break
+		}
+	}
+	return strings.Join(paragraph, " ")
+}
+
+// makeTargets returns the explicit targets of a Makefile
+func makeTargets(data []byte) map[string]bool {
+	targets := make(map[string]bool)
+	for _, line := range strings.Split(string(data), "\n") {
+		if match := makeTargetPattern.FindStringSubmatch(line); match != nil && !strings.HasPrefix(match[1], ".") {
+			targets[match[1]] = true
+		}
+	}
+	return targets
+}
+
+// truncateRepoMapLine shortens a summary line of the map
+func truncateRepoMapLine(line string) string {
+	const maxLength = 200
+	if len(line) <= maxLength {
+		return line
+	}
+	return line[:maxLength] + "..."
+}
+
+// String formats the map for the generation prompt
+func (m *RepoMap) String() string {
+	var sb strings.Builder
+	if len(m.Languages) > 0 {
+		languages := make([]string, 0, len(m.Languages))
+		for _, language := range m.Languages {
+			languages = append(languages, fmt.Sprintf("%s (%d files)", language.Language, language.Files))
+		}
+		sb.WriteString("Languages: " + strings.Join(languages, ", ") + "\n")
+	}
+	if len(m.Frameworks) > 0 {
+		sb.WriteString("Frameworks and libraries: " + strings.Join(m.Frameworks, ", ") + "\n")
+	}
+	if len(m.Commands) > 0 {
+		sb.WriteString("Commands:\n")
+		for _, command := range m.Commands {
+			sb.WriteString(fmt.Sprintf("- %s: `%s`", command.Name, command.Command))
+			if command.Configured {
+				sb.WriteString(" (must pass before the changes are committed)")
+			}
+			sb.WriteString("\n")
+		}
+	}
+	if len(m.KeyFiles) > 0 {
+		sb.WriteString("Key files:\n")
+		for _, file := range m.KeyFiles {
+			sb.WriteString(fmt.Sprintf("- %s: %s\n", file.Path, file.Summary))
+		}
+	}
+	if len(m.RootFiles) > 0 {
+		files := m.RootFiles
+		more := ""
+		if len(files) > maxRepoMapRootFiles {
+			more = fmt.Sprintf(" and %d more", len(files)-maxRepoMapRootFiles)
+			files = files[:maxRepoMapRootFiles]
+		}
+		sb.WriteString("Top-level files: " + strings.Join(files, ", ") + more + "\n")
+	}
+	if len(m.Dirs) > 0 {
+		sb.WriteString("Directories (files directly inside):\n")
+		for _, dir := range m.Dirs {
+			sb.WriteString(fmt.Sprintf("%s%s/ (%d)\n", strings.Repeat("  ", dir.Depth-1), path.Base(dir.Path), dir.Files))
+		}
+		if m.Truncated {
+			sb.WriteString("(deeper directories omitted)\n")
+		}
+	}
+	return sb.String()
+}
+
+// repoMapPrompt returns the map of the ticket's checkout for the generation prompt, or an empty string if the map is
+// disabled. Configured checks replace the detected commands of the same name.
+func (p *TicketProcessorImpl) repoMapPrompt(run *TicketRun) string {
+	settings := p.config.AI.RepoMap
+	if !settings.Enabled {
+		return ""
+	}
+	repoMap, err := buildRepoMap(run.RepoDir, settings.MaxDepth, settings.MaxEntries)
+	if err != nil {
+		p.logger.Warn("Failed to map the repository, generating without the map",
+			zap.String("ticket", run.Key),
+			zap.String("repo_dir", run.RepoDir),
+			zap.Error(err))
+		return ""
+	}
+
+	configured := map[string]string{
+		"build": p.checkConfig(run, "build").Command,
+		"test":  p.checkConfig(run, "test").Command,
+		"lint":  p.checkConfig(run, "lint").Command,
+	}
+	for _, name := range []string{"build", "test", "lint"} {
+		if configured[name] == "" {
+			continue
+		}
+		command := RepoCommand{Name: name, Command: configured[name], Configured: true}
+		replaced := false
+		for i := range repoMap.Commands {
+			if repoMap.Commands[i].Name == name {
+				repoMap.Commands[i], replaced = command, true
+			}
+		}
+		if !replaced {
+			repoMap.Commands = append(repoMap.Commands, command)
+		}
+	}
+
+	return "\n\nRepository map, generated from the checkout; use it instead of exploring the project's structure from " +
+		"scratch:\n" + repoMap.String()
+}
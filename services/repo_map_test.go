@@ -0,0 +1,128 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+)
+
+func writeRepoMapFiles(t *testing.T, repoDir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(repoDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+}
+
+func TestBuildRepoMap(t *testing.T) {
+	repoDir := t.TempDir()
+	writeRepoMapFiles(t, repoDir, map[string]string{
+		"README.md":                    "# Widget\n\n[![CI](badge.svg)](ci)\n\nWidget serves widgets\nover HTTP.\n\n## Usage\n",
+		"go.mod":                       "module example.com/widget\n\ngo 1.22\n\nrequire github.com/spf13/cobra v1.8.0\n",
+		"Makefile":                     ".PHONY: build\nbuild:\n\tgo build ./...\nVERSION := 1\nrelease: build\n",
+		"main.go":                      "package main",
+		"cmd/serve.go":                 "package cmd",
+		"internal/store/store.go":      "package store",
+		"internal/store/store_test.go": "package store",
+		"internal/store/deep/a/b.go":   "package b",
+		"scripts/release.sh":           "#!/bin/sh",
+		"node_modules/lib/index.js":    "module.exports = {}",
+		".git/config":                  "[core]",
+	})
+
+	repoMap, err := buildRepoMap(repoDir, 2, 100)
+	if err != nil {
+		t.Fatalf("buildRepoMap() error = %v", err)
+	}
+
+	if len(repoMap.Languages) != 2 || repoMap.Languages[0] != (LanguageCount{Language: "Go", Files: 5}) {
+		t.Errorf("Languages = %+v, want 5 Go files and a Shell file", repoMap.Languages)
+	}
+	if len(repoMap.Frameworks) != 1 || repoMap.Frameworks[0] != "Cobra" {
+		t.Errorf("Frameworks = %q, want Cobra", repoMap.Frameworks)
+	}
+	wantCommands := []RepoCommand{{Name: "build", Command: "make build"}, {Name: "test", Command: "go test ./..."}, {Name: "lint", Command: "go vet ./..."}}
+	if len(repoMap.Commands) != len(wantCommands) {
+		t.Fatalf("Commands = %+v, want %+v", repoMap.Commands, wantCommands)
+	}
+	for i, command := range wantCommands {
+		if repoMap.Commands[i] != command {
+			t.Errorf("Commands[%d] = %+v, want %+v", i, repoMap.Commands[i], command)
+		}
+	}
+
+	formatted := repoMap.String()
+	for _, want := range []string{
+		"- README.md: Widget serves widgets over HTTP.\n",
+		"- go.mod: Go module example.com/widget (Go 1.22)\n",
+		"- Makefile: targets: build, release\n",
+		"Top-level files: Makefile, README.md, go.mod, main.go\n",
+		"internal/ (0)\n  store/ (2)\n",
+		"scripts/ (1)\n",
+	} {
+		if !strings.Contains(formatted, want) {
+			t.Errorf("Expected the map to contain %q, got:\n%s", want, formatted)
+		}
+	}
+	for _, unwanted := range []string{"node_modules", ".git", "deep/"} {
+		if strings.Contains(formatted, unwanted) {
+			t.Errorf("Expected the map not to contain %q, got:\n%s", unwanted, formatted)
+		}
+	}
+}
+
+func TestBuildRepoMap_Truncated(t *testing.T) {
+	repoDir := t.TempDir()
+	writeRepoMapFiles(t, repoDir, map[string]string{
+		"a/x/file.go": "package x",
+		"b/file.go":   "package b",
+		"c/file.go":   "package c",
+	})
+
+	repoMap, err := buildRepoMap(repoDir, 3, 3)
+	if err != nil {
+		t.Fatalf("buildRepoMap() error = %v", err)
+	}
+	if !repoMap.Truncated || len(repoMap.Dirs) != 3 {
+		t.Fatalf("Expected the tree to be truncated to 3 directories, got %+v", repoMap.Dirs)
+	}
+	for _, dir := range repoMap.Dirs {
+		if dir.Depth != 1 {
+			t.Errorf("Expected the deeper directory to be left out first, got %+v", repoMap.Dirs)
+		}
+	}
+}
+
+func TestTicketProcessor_RepoMapPrompt(t *testing.T) {
+	repoDir := t.TempDir()
+	writeRepoMapFiles(t, repoDir, map[string]string{"go.mod": "module example.com/widget\n"})
+
+	config := &models.Config{}
+	config.AI.RepoMap.MaxDepth = 3
+	config.AI.RepoMap.MaxEntries = 100
+	config.Test = models.CheckCommandConfig{Command: "make test-unit"}
+	processor := newCheckTestProcessor(t, config, &mocks.MockClaudeService{}, &mocks.MockJiraService{}, &mocks.MockGitHubService{})
+	run := &TicketRun{Key: "TEST-1", RepoDir: repoDir}
+
+	if prompt := processor.repoMapPrompt(run); prompt != "" {
+		t.Errorf("Expected no map when disabled, got %q", prompt)
+	}
+
+	config.AI.RepoMap.Enabled = true
+	prompt := processor.repoMapPrompt(run)
+	if !strings.Contains(prompt, "- test: `make test-unit` (must pass before the changes are committed)") {
+		t.Errorf("Expected the configured test command to replace the detected one, got:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "- build: `go build ./...`\n") {
+		t.Errorf("Expected the detected build command, got:\n%s", prompt)
+	}
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// projectPropertyTTL is how long the repository of a project's property is reused before the property is read again
+const projectPropertyTTL = 5 * time.Minute
+
+// RepositoryResolver resolves the repository a ticket's changes go to
+type RepositoryResolver interface {
+	// Resolve returns the ticket's first component, empty for tickets without components, and its repository
+	Resolve(ctx context.Context, ticket *models.JiraTicketResponse) (component, repoURL string, ok bool)
+}
+
+// RepositoryResolverImpl implements RepositoryResolver. The component_to_repo mapping and the repo_mappings come first,
+// then the repository in the Jira project property, and the default_repo last.
+type RepositoryResolverImpl struct {
+	jiraService JiraService
+	config      *models.Config
+	logger      *zap.Logger
+
+	mu         sync.Mutex
+	properties map[string]projectRepo // Repository of the project property by project key and property
+}
+
+// projectRepo is the repository read from a project's property, empty if the project doesn't have the property
+type projectRepo struct {
+	repoURL string
+	readAt  time.Time
+}
+
+// NewRepositoryResolver creates a new RepositoryResolver
+func NewRepositoryResolver(jiraService JiraService, config *models.Config, logger *zap.Logger) RepositoryResolver {
+	return &RepositoryResolverImpl{
+		jiraService: jiraService,
+		config:      config,
+		logger:      logger,
+		properties:  make(map[string]projectRepo),
+	}
+}
+
+// Resolve returns the ticket's first component and its repository
+func (r *RepositoryResolverImpl) Resolve(ctx context.Context, ticket *models.JiraTicketResponse) (component, repoURL string, ok bool) {
+	if len(ticket.Fields.Components) > 0 {
+		component = ticket.Fields.Components[0].Name
+	}
+	project := ticketProjectKey(ticket)
+	if repoURL, ok := r.config.ResolveMappedRepo(project, component); ok {
+		return component, repoURL, true
+	}
+
+	// Read once, so a reload can't mix the fallbacks of two configurations
+	property, defaultRepo := r.config.RepoFallbacks()
+	if property != "" {
+		repoURL, err := r.projectRepo(ctx, project, property)
+		if err != nil {
+			// Falling back to the default repository could open the ticket's PR in the wrong repository
+			r.logger.Error("Failed to read the repository of the project",
+				zap.String("ticket", ticket.Key),
+				zap.String("project", project),
+				zap.String("property", property),
+				zap.Error(err))
+			return component, "", false
+		}
+		if repoURL != "" {
+			return component, repoURL, true
+		}
+	}
+
+	if defaultRepo != "" {
+		return component, defaultRepo, true
+	}
+	return component, "", false
+}
+
+// projectRepo returns the repository in the project's property, or an empty one if the project doesn't have it
+func (r *RepositoryResolverImpl) projectRepo(ctx context.Context, project, property string) (string, error) {
+	// The property can change on a configuration reload
+	cacheKey := project + " " + property
+	r.mu.Lock()
+	cached, ok := r.properties[cacheKey]
+	r.mu.Unlock()
+	if ok && time.Since(cached.readAt) < projectPropertyTTL {
+		return cached.repoURL, nil
+	}
+
+	value, err := r.jiraService.GetProjectProperty(ctx, project, property)
+	if err != nil {
+		return "", err
+	}
+	var repoURL string
+	if value != nil {
+		if err := json.Unmarshal(value, &repoURL); err != nil {
+			return "", fmt.Errorf("property %s of project %s is not a repository URL: %s", property, project, string(value))
+		}
+	}
+
+	r.mu.Lock()
+	r.properties[cacheKey] = projectRepo{repoURL: repoURL, readAt: time.Now()}
+	r.mu.Unlock()
+	return repoURL, nil
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestRepositoryResolver_Resolve(t *testing.T) {
+	tests := []struct {
+		name          string
+		key           string
+		component     string
+		property      string
+		propertyErr   error
+		wantComponent string
+		wantRepo      string
+		wantOK        bool
+	}{
+		{
+			name:          "mapping before the project property",
+			key:           "PAY-12",
+			component:     "ledger-backend",
+			property:      `"https://github.com/example/billing.git"`,
+			wantComponent: "ledger-backend",
+			wantRepo:      "https://github.com/example/payments.git",
+			wantOK:        true,
+		},
+		{
+			name:          "project property without a mapping",
+			key:           "PAY-12",
+			component:     "ledger-frontend",
+			property:      `"https://github.com/example/billing.git"`,
+			wantComponent: "ledger-frontend",
+			wantRepo:      "https://github.com/example/billing.git",
+			wantOK:        true,
+		},
+		{
+			name:     "default repository without the project property",
+			key:      "OPS-3",
+			wantRepo: "https://github.com/example/monorepo.git",
+			wantOK:   true,
+		},
+		{
+			name:     "project property that isn't a URL",
+			key:      "OPS-3",
+			property: `{"repo": "https://github.com/example/ops.git"}`,
+		},
+		{
+			name:        "failure to read the project property",
+			key:         "OPS-3",
+			propertyErr: errors.New("jira unavailable"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &models.Config{}
+			config.RepoMappings = []models.RepoMapping{{Project: "PAY", Component: "*backend*", Repo: "https://github.com/example/payments.git"}}
+			config.RepoProjectProperty = "ai.bot.github.repo"
+			config.DefaultRepo = "https://github.com/example/monorepo.git"
+			jiraService := &mocks.MockJiraService{
+				GetProjectPropertyFunc: func(projectKey, propertyKey string) (json.RawMessage, error) {
+					if propertyKey != "ai.bot.github.repo" {
+						t.Errorf("Unexpected property %s", propertyKey)
+					}
+					if tt.property == "" {
+						return nil, tt.propertyErr
+					}
+					return json.RawMessage(tt.property), tt.propertyErr
+				},
+			}
+			resolver := NewRepositoryResolver(jiraService, config, zap.NewNop())
+
+			ticket := &models.JiraTicketResponse{Key: tt.key}
+			if tt.component != "" {
+				ticket.Fields.Components = []models.JiraComponent{{Name: tt.component}}
+			}
+			component, repoURL, ok := resolver.Resolve(context.Background(), ticket)
+			if component != tt.wantComponent || repoURL != tt.wantRepo || ok != tt.wantOK {
+				t.Errorf("Resolve() = %q, %q, %v, want %q, %q, %v", component, repoURL, ok, tt.wantComponent, tt.wantRepo, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRepositoryResolver_CachesProjectProperty(t *testing.T) {
+	config := &models.Config{}
+	config.RepoProjectProperty = "ai.bot.github.repo"
+	reads := 0
+	jiraService := &mocks.MockJiraService{
+		GetProjectPropertyFunc: func(projectKey, propertyKey string) (json.RawMessage, error) {
+			reads++
+			return json.RawMessage(`"https://github.com/example/billing.git"`), nil
+		},
+	}
+	resolver := NewRepositoryResolver(jiraService, config, zap.NewNop())
+
+	for _, key := range []string{"PAY-1", "PAY-2"} {
+		if _, repoURL, ok := resolver.Resolve(context.Background(), &models.JiraTicketResponse{Key: key}); !ok || repoURL != "https://github.com/example/billing.git" {
+			t.Errorf("Expected %s to resolve to the project's repository, got %q", key, repoURL)
+		}
+	}
+	if reads != 1 {
+		t.Errorf("Expected the property of the project to be read once, got %d reads", reads)
+	}
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// reviewRepliesFile is the file the AI writes its per-comment summaries to
+const reviewRepliesFile = ".ai-review-replies.json"
+
+// ReviewReply describes how the AI handled one inline review comment
+type ReviewReply struct {
+	CommentID int64  `json:"comment_id"`
+	Addressed bool   `json:"addressed"`
+	Reply     string `json:"reply"`
+}
+
+// reviewRepliesPrompt instructs the AI to summarize how it handled each inline review comment
+func reviewRepliesPrompt() string {
+	return fmt.Sprintf("\n\nFor every review thread with NEW comments, record how you handled it in a file named %s "+
+		"in the repository root with the format {\"replies\": [{\"comment_id\": 123, \"addressed\": true, \"reply\": \"...\"}]}. "+
+		"Use the thread IDs from the feedback above as comment_id. Set addressed to false and explain why when you did not change the code for a thread. "+
+		"Keep each reply to one or two sentences addressed to the reviewer. This file will not be committed.", reviewRepliesFile)
+}
+
+// threadStatusRank orders feedback statuses from least to most pressing
+var threadStatusRank = map[string]int{"✅ HANDLED": 1, "💬 CONTEXT ONLY": 2, "🔄 NEW": 3}
+
+// reviewThread is an inline review conversation: a comment on the diff and the replies to it
+type reviewThread struct {
+	root     models.GitHubPRComment
+	comments []models.GitHubPRComment // The root and its replies, oldest first
+}
+
+// id returns the ID GitHub identifies the thread by, the ID of its first comment
+func (t *reviewThread) id() int64 {
+	if t.root.InReplyTo != 0 {
+		return t.root.InReplyTo
+	}
+	return t.root.ID
+}
+
+// groupReviewThreads groups inline review comments into threads, in the order the threads were started.
+// GitHub points every reply at the first comment of its thread; replies whose root is missing start their own thread.
+func groupReviewThreads(comments []models.GitHubPRComment) []*reviewThread {
+	sorted := append([]models.GitHubPRComment(nil), comments...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	var threads []*reviewThread
+	byRoot := make(map[int64]*reviewThread)
+	for _, comment := range sorted {
+		if thread, ok := byRoot[comment.InReplyTo]; ok && comment.InReplyTo != 0 {
+			thread.comments = append(thread.comments, comment)
+			continue
+		}
+		thread := &reviewThread{root: comment, comments: []models.GitHubPRComment{comment}}
+		threads = append(threads, thread)
+		byRoot[comment.ID] = thread
+		if comment.InReplyTo != 0 {
+			byRoot[comment.InReplyTo] = thread
+		}
+	}
+	return threads
+}
+
+// collectInlineFeedback lists inline review threads with their IDs, code locations and diff hunks, marking each
+// comment as handled or new. The bot's own replies are included as context; threads only the bot wrote are skipped.
+func (p *PRReviewProcessorImpl) collectInlineFeedback(comments []models.GitHubPRComment, watermark models.FeedbackWatermark) string {
+	var feedback strings.Builder
+
+	for _, thread := range groupReviewThreads(comments) {
+		// A thread takes the most pressing status of its comments
+		threadStatus, humanComments := "", 0
+		for _, comment := range thread.comments {
+			if comment.User.Login == p.config.GitHub.BotUsername {
+				continue
+			}
+			humanComments++
+			status := p.feedbackStatus(comment.Body, watermark.IsNewReviewComment(comment))
+			if threadStatusRank[status] > threadStatusRank[threadStatus] {
+				threadStatus = status
+			}
+		}
+		if humanComments == 0 {
+			continue
+		}
+
+		if feedback.Len() == 0 {
+			feedback.WriteString("### Inline Review Threads\n\n")
+		}
+
+		line := thread.root.Line
+		location := fmt.Sprintf("%s:%d", thread.root.Path, line)
+		if line == 0 {
+			location = fmt.Sprintf("%s:%d (outdated)", thread.root.Path, thread.root.OriginalLine)
+		}
+		feedback.WriteString(fmt.Sprintf("#### Thread #%d on %s - %s\n\n", thread.id(), location, threadStatus))
+		if thread.root.DiffHunk != "" {
+			feedback.WriteString("```diff\n")
+			feedback.WriteString(thread.root.DiffHunk)
+			feedback.WriteString("\n```\n\n")
+		}
+
+		for _, comment := range thread.comments {
+			if comment.User.Login == p.config.GitHub.BotUsername {
+				feedback.WriteString("**Your earlier reply:**\n")
+			} else {
+				status := p.feedbackStatus(comment.Body, watermark.IsNewReviewComment(comment))
+				feedback.WriteString(fmt.Sprintf("**Comment #%d by %s - %s:**\n", comment.ID, comment.User.Login, status))
+			}
+			feedback.WriteString(comment.Body)
+			feedback.WriteString("\n\n")
+		}
+	}
+
+	return feedback.String()
+}
+
+// loadReviewReplies reads and removes the AI's per-comment summaries, keyed by comment ID
+func (p *PRReviewProcessorImpl) loadReviewReplies(ticketKey, repoDir string) map[int64]ReviewReply {
+	repliesPath := filepath.Join(repoDir, reviewRepliesFile)
+	data, err := os.ReadFile(repliesPath)
+	if err != nil {
+		return nil
+	}
+
+	// The summaries must never be committed
+	if err := os.Remove(repliesPath); err != nil {
+		p.logger.Warn("Failed to remove review replies file", zap.String("ticket", ticketKey), zap.Error(err))
+	}
+
+	var parsed struct {
+		Replies []ReviewReply `json:"replies"`
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		p.logger.Warn("Ignoring invalid review replies", zap.String("ticket", ticketKey), zap.Error(err))
+		return nil
+	}
+
+	replies := make(map[int64]ReviewReply, len(parsed.Replies))
+	for _, reply := range parsed.Replies {
+		replies[reply.CommentID] = reply
+	}
+	return replies
+}
+
+// replyToReviewComments answers each new inline review comment in its thread, so reviewers can resolve it
+func (p *PRReviewProcessorImpl) replyToReviewComments(ctx context.Context, ticketKey, owner, repo string, prNumber int, newComments []models.GitHubPRComment, replies map[int64]ReviewReply) {
+	replied := make(map[int64]bool)
+
+	for _, comment := range newComments {
+		// GitHub only accepts replies to the first comment of a thread
+		threadID := comment.ID
+		if comment.InReplyTo != 0 {
+			threadID = comment.InReplyTo
+		}
+		if replied[threadID] {
+			continue
+		}
+		replied[threadID] = true
+
+		// The AI is asked for a summary per thread, but may have used the ID of the new comment
+		reply, found := replies[threadID]
+		if !found {
+			reply, found = replies[comment.ID]
+		}
+		body := formatReviewReply(reply, found)
+		if err := p.githubService.ReplyToReviewComment(ctx, owner, repo, prNumber, threadID, body); err != nil {
+			p.logger.Warn("Failed to reply to review comment",
+				zap.String("ticket", ticketKey),
+				zap.Int64("comment_id", comment.ID),
+				zap.Error(err))
+		}
+	}
+}
+
+// formatReviewReply renders the reply to a review comment, with a generic note when the AI gave no summary
+func formatReviewReply(reply ReviewReply, found bool) string {
+	if !found || strings.TrimSpace(reply.Reply) == "" {
+		return "🤖 I've pushed an update based on the review feedback, including this comment. Please take another look."
+	}
+	if reply.Addressed {
+		return "🤖 Addressed: " + reply.Reply
+	}
+	return "🤖 Not changed: " + reply.Reply
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestPRReviewProcessor_CollectInlineFeedback(t *testing.T) {
+	processor := &PRReviewProcessorImpl{
+		config: newBotConfig("ai-bot"),
+	}
+
+	lastProcessed := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	comments := []models.GitHubPRComment{
+		{ID: 12, User: models.GitHubUser{Login: "reviewer1"}, Body: "Handle the error", Path: "main.go", Line: 20, CreatedAt: lastProcessed.Add(time.Hour)},
+		{ID: 11, User: models.GitHubUser{Login: "reviewer1"}, Body: "Rename this", Path: "main.go", Line: 10, DiffHunk: "@@ -8,3 +8,3 @@\n-x := 1\n+tmp := 1", CreatedAt: lastProcessed.Add(-2 * time.Hour)},
+		{ID: 13, User: models.GitHubUser{Login: "ai-bot"}, Body: "🤖 Addressed: Renamed to count.", InReplyTo: 11, CreatedAt: lastProcessed.Add(-time.Hour)},
+		{ID: 14, User: models.GitHubUser{Login: "reviewer2"}, Body: "count is taken, use total", InReplyTo: 11, CreatedAt: lastProcessed.Add(2 * time.Hour)},
+		{ID: 15, User: models.GitHubUser{Login: "ai-bot"}, Body: "Note to self", Path: "util.go", Line: 3, CreatedAt: lastProcessed.Add(time.Hour)},
+		{ID: 16, User: models.GitHubUser{Login: "reviewer1"}, Body: "Still needed?", Path: "old.go", OriginalLine: 7, CreatedAt: lastProcessed.Add(-time.Hour)},
+	}
+
+	feedback := processor.collectInlineFeedback(comments, models.FeedbackWatermark{ProcessedAt: lastProcessed})
+
+	want := "#### Thread #11 on main.go:10 - 🔄 NEW\n\n" +
+		"```diff\n@@ -8,3 +8,3 @@\n-x := 1\n+tmp := 1\n```\n\n" +
+		"**Comment #11 by reviewer1 - ✅ HANDLED:**\nRename this\n\n" +
+		"**Your earlier reply:**\n🤖 Addressed: Renamed to count.\n\n" +
+		"**Comment #14 by reviewer2 - 🔄 NEW:**\ncount is taken, use total\n\n"
+	if !strings.Contains(feedback, want) {
+		t.Errorf("Expected the thread with its diff hunk and replies in order, got:\n%s", feedback)
+	}
+	if !strings.Contains(feedback, "#### Thread #12 on main.go:20 - 🔄 NEW") {
+		t.Errorf("Expected a thread per root comment, got:\n%s", feedback)
+	}
+	if !strings.Contains(feedback, "#### Thread #16 on old.go:7 (outdated) - ✅ HANDLED") {
+		t.Errorf("Expected outdated threads to show their original line, got:\n%s", feedback)
+	}
+	if strings.Index(feedback, "Thread #11") > strings.Index(feedback, "Thread #12") {
+		t.Error("Expected threads in the order they were started")
+	}
+	if strings.Contains(feedback, "#15") {
+		t.Error("Expected threads only the bot wrote to be skipped")
+	}
+	if processor.collectInlineFeedback(nil, models.FeedbackWatermark{ProcessedAt: lastProcessed}) != "" {
+		t.Error("Expected no section without inline comments")
+	}
+}
+
+func TestPRReviewProcessor_ReplyToReviewComments(t *testing.T) {
+	repoDir := t.TempDir()
+	replies := `{"replies": [
+		{"comment_id": 12, "addressed": true, "reply": "Now returning the error to the caller."},
+		{"comment_id": 14, "addressed": false, "reply": "The name matches the API field."}
+	]}`
+	if err := os.WriteFile(filepath.Join(repoDir, reviewRepliesFile), []byte(replies), 0644); err != nil {
+		t.Fatalf("Failed to write replies file: %v", err)
+	}
+
+	posted := make(map[int64]string)
+	processor := &PRReviewProcessorImpl{
+		githubService: &mocks.MockGitHubService{
+			ReplyToReviewCommentFunc: func(owner, repo string, prNumber int, commentID int64, body string) error {
+				posted[commentID] = body
+				return nil
+			},
+		},
+		config: newBotConfig("ai-bot"),
+		logger: zap.NewNop(),
+	}
+
+	loaded := processor.loadReviewReplies("TEST-1", repoDir)
+	if _, err := os.Stat(filepath.Join(repoDir, reviewRepliesFile)); !os.IsNotExist(err) {
+		t.Error("Expected replies file to be removed before committing")
+	}
+
+	newComments := []models.GitHubPRComment{
+		{ID: 12},
+		{ID: 14},
+		{ID: 15, InReplyTo: 14}, // Follow-up in a thread that is already answered
+		{ID: 16, InReplyTo: 9},  // Follow-up in an older thread without a summary
+	}
+	processor.replyToReviewComments(context.Background(), "TEST-1", "owner", "repo", 1, newComments, loaded)
+
+	if len(posted) != 3 {
+		t.Fatalf("Expected one reply per thread, got %v", posted)
+	}
+	if posted[12] != "🤖 Addressed: Now returning the error to the caller." {
+		t.Errorf("Unexpected reply for comment 12: %s", posted[12])
+	}
+	if posted[14] != "🤖 Not changed: The name matches the API field." {
+		t.Errorf("Unexpected reply for comment 14: %s", posted[14])
+	}
+	if !strings.Contains(posted[9], "pushed an update") {
+		t.Errorf("Expected generic reply on the thread root of comment 16, got: %s", posted[9])
+	}
+}
+
+func TestGroupReviewThreads(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	comments := []models.GitHubPRComment{
+		{ID: 3, InReplyTo: 1, CreatedAt: base.Add(2 * time.Minute)},
+		{ID: 1, CreatedAt: base},
+		{ID: 2, CreatedAt: base.Add(time.Minute)},
+		{ID: 5, InReplyTo: 4, CreatedAt: base.Add(3 * time.Minute)}, // Root not among the comments
+		{ID: 6, InReplyTo: 4, CreatedAt: base.Add(4 * time.Minute)},
+	}
+
+	threads := groupReviewThreads(comments)
+
+	var got [][]int64
+	for _, thread := range threads {
+		var ids []int64
+		for _, comment := range thread.comments {
+			ids = append(ids, comment.ID)
+		}
+		got = append(got, ids)
+	}
+	if fmt.Sprint(got) != "[[1 3] [2] [5 6]]" {
+		t.Errorf("Unexpected threads: %v", got)
+	}
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// ReviewerAssigner defines the interface for requesting reviews on newly created pull requests
+type ReviewerAssigner interface {
+	// AssignReviewers requests reviews on a PR based on the reviewer configuration of the component.
+	// repoDir is the local clone of the repository, used to read its CODEOWNERS file.
+	AssignReviewers(ctx context.Context, owner, repo string, prNumber int, component, repoDir string) error
+}
+
+// ReviewerAssignerImpl implements the ReviewerAssigner interface
+type ReviewerAssignerImpl struct {
+	githubService GitHubService
+	config        *models.Config
+	logger        *zap.Logger
+}
+
+// NewReviewerAssigner creates a new ReviewerAssigner
+func NewReviewerAssigner(githubService GitHubService, config *models.Config, logger *zap.Logger) ReviewerAssigner {
+	return &ReviewerAssignerImpl{
+		githubService: githubService,
+		config:        config,
+		logger:        logger,
+	}
+}
+
+// AssignReviewers requests reviews from the CODEOWNERS of the changed paths, falling back to the
+// component's default reviewers, plus the least-loaded reviewer from the component's pool
+func (a *ReviewerAssignerImpl) AssignReviewers(ctx context.Context, owner, repo string, prNumber int, component, repoDir string) error {
+	var users, teams []string
+
+	if a.config.GitHub.CodeownersReviewers {
+		ownerUsers, ownerTeams, err := a.codeownersReviewers(ctx, owner, repo, prNumber, repoDir)
+		if err != nil {
+			a.logger.Warn("Failed to resolve CODEOWNERS reviewers",
+				zap.String("component", component),
+				zap.Int("pr_number", prNumber),
+				zap.Error(err))
+		}
+		users = append(users, ownerUsers...)
+		teams = append(teams, ownerTeams...)
+	}
+
+	if len(users) == 0 && len(teams) == 0 {
+		for _, reviewer := range a.config.GetDefaultReviewers(component) {
+			reviewer = strings.TrimPrefix(reviewer, "@")
+			if idx := strings.Index(reviewer, "/"); idx >= 0 {
+				teams = append(teams, reviewer[idx+1:])
+			} else {
+				users = append(users, reviewer)
+			}
+		}
+	}
+
+	if pool := a.config.GetReviewerPool(component); len(pool) > 0 {
+		reviewer, err := a.selectLeastLoadedReviewer(ctx, pool)
+		if err != nil {
+			if len(users) == 0 && len(teams) == 0 {
+				return err
+			}
+			a.logger.Warn("Failed to select reviewer from pool", zap.String("component", component), zap.Error(err))
+		} else {
+			users = append(users, reviewer)
+		}
+	}
+
+	users = a.filterReviewers(users)
+	teams = uniqueStrings(teams)
+	if len(users) == 0 && len(teams) == 0 {
+		a.logger.Debug("No reviewers configured, skipping reviewer assignment", zap.String("component", component))
+		return nil
+	}
+
+	if err := a.githubService.RequestReviewers(ctx, owner, repo, prNumber, users, teams); err != nil {
+		return fmt.Errorf("failed to request reviews: %w", err)
+	}
+
+	a.logger.Info("Requested reviews",
+		zap.Strings("reviewers", users),
+		zap.Strings("team_reviewers", teams),
+		zap.String("component", component),
+		zap.Int("pr_number", prNumber))
+	return nil
+}
+
+// codeownersReviewers resolves the users and teams owning the files changed in a PR
+func (a *ReviewerAssignerImpl) codeownersReviewers(ctx context.Context, owner, repo string, prNumber int, repoDir string) ([]string, []string, error) {
+	codeowners, err := LoadCodeowners(repoDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CODEOWNERS: %w", err)
+	}
+	if codeowners == nil {
+		a.logger.Debug("Repository has no CODEOWNERS file", zap.String("repo_dir", repoDir))
+		return nil, nil, nil
+	}
+
+	files, err := a.githubService.ListPRFiles(ctx, owner, repo, prNumber)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list PR files: %w", err)
+	}
+
+	var users, teams []string
+	for _, file := range files {
+		fileUsers, fileTeams := SplitCodeowners(codeowners.OwnersFor(file.Filename))
+		users = append(users, fileUsers...)
+		teams = append(teams, fileTeams...)
+	}
+	return users, teams, nil
+}
+
+// filterReviewers removes duplicates and the bot itself, which can't review its own PR
+func (a *ReviewerAssignerImpl) filterReviewers(reviewers []string) []string {
+	var filtered []string
+	for _, reviewer := range uniqueStrings(reviewers) {
+		if reviewer != a.config.GitHub.BotUsername {
+			filtered = append(filtered, reviewer)
+		}
+	}
+	return filtered
+}
+
+// uniqueStrings returns the distinct values of a slice, preserving order
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var unique []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+// selectLeastLoadedReviewer picks the candidate with the fewest open review requests.
+// Ties are broken by pool order, and candidates whose workload can't be determined are skipped.
+func (a *ReviewerAssignerImpl) selectLeastLoadedReviewer(ctx context.Context, pool []string) (string, error) {
+	selected := ""
+	lowestCount := -1
+
+	for _, candidate := range pool {
+		// Never request a review from the bot itself
+		if candidate == a.config.GitHub.BotUsername {
+			continue
+		}
+
+		count, err := a.githubService.CountOpenReviewRequests(ctx, candidate)
+		if err != nil {
+			a.logger.Warn("Failed to count open review requests, skipping candidate",
+				zap.String("candidate", candidate),
+				zap.Error(err))
+			continue
+		}
+
+		a.logger.Debug("Reviewer workload", zap.String("candidate", candidate), zap.Int("open_review_requests", count))
+
+		if lowestCount == -1 || count < lowestCount {
+			selected = candidate
+			lowestCount = count
+		}
+	}
+
+	if selected == "" {
+		return "", fmt.Errorf("no eligible reviewer found in pool")
+	}
+
+	return selected, nil
+}
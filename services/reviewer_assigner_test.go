@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestReviewerAssigner_AssignReviewers_LeastLoaded(t *testing.T) {
+	workloads := map[string]int{
+		"alice": 5,
+		"bob":   2,
+		"carol": 2,
+		"dave":  0,
+	}
+
+	var requested []string
+	mockGitHub := &mocks.MockGitHubService{
+		CountOpenReviewRequestsFunc: func(username string) (int, error) {
+			if username == "dave" {
+				return 0, errors.New("rate limited")
+			}
+			return workloads[username], nil
+		},
+		RequestReviewersFunc: func(owner, repo string, prNumber int, reviewers, teamReviewers []string) error {
+			requested = reviewers
+			return nil
+		},
+	}
+
+	config := &models.Config{}
+	config.GitHub.BotUsername = "ai-bot"
+	config.GitHub.ReviewerPool = []string{"global-reviewer"}
+	config.Components = map[string]models.ComponentConfig{
+		"backend": {ReviewerPool: []string{"ai-bot", "alice", "bob", "carol", "dave"}},
+	}
+
+	assigner := NewReviewerAssigner(mockGitHub, config, zap.NewNop())
+	if err := assigner.AssignReviewers(context.Background(), "example", "repo", 1, "backend", t.TempDir()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// dave is skipped because his workload is unknown, bob wins the tie with carol by pool order
+	if len(requested) != 1 || requested[0] != "bob" {
+		t.Errorf("Expected review to be requested from 'bob', got %v", requested)
+	}
+}
+
+func TestReviewerAssigner_AssignReviewers_NoPool(t *testing.T) {
+	mockGitHub := &mocks.MockGitHubService{
+		RequestReviewersFunc: func(owner, repo string, prNumber int, reviewers, teamReviewers []string) error {
+			t.Error("RequestReviewers should not be called without a reviewer pool")
+			return nil
+		},
+	}
+
+	assigner := NewReviewerAssigner(mockGitHub, &models.Config{}, zap.NewNop())
+	if err := assigner.AssignReviewers(context.Background(), "example", "repo", 1, "frontend", t.TempDir()); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+}
+
+func TestReviewerAssigner_AssignReviewers_NoEligibleReviewer(t *testing.T) {
+	mockGitHub := &mocks.MockGitHubService{
+		CountOpenReviewRequestsFunc: func(username string) (int, error) {
+			return 0, errors.New("not found")
+		},
+	}
+
+	config := &models.Config{}
+	config.GitHub.ReviewerPool = []string{"alice"}
+
+	assigner := NewReviewerAssigner(mockGitHub, config, zap.NewNop())
+	if err := assigner.AssignReviewers(context.Background(), "example", "repo", 1, "frontend", t.TempDir()); err == nil {
+		t.Error("Expected error when no reviewer workload can be determined")
+	}
+}
+
+func TestReviewerAssigner_AssignReviewers_Codeowners(t *testing.T) {
+	repoDir := t.TempDir()
+	content := "*.go @ai-bot @gopher @org/backend\n/docs/ @writer\n"
+	if err := os.WriteFile(filepath.Join(repoDir, "CODEOWNERS"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write CODEOWNERS: %v", err)
+	}
+
+	var requestedUsers, requestedTeams []string
+	mockGitHub := &mocks.MockGitHubService{
+		ListPRFilesFunc: func(owner, repo string, prNumber int) ([]models.GitHubPRFile, error) {
+			return []models.GitHubPRFile{{Filename: "main.go"}, {Filename: "services/jira.go"}}, nil
+		},
+		RequestReviewersFunc: func(owner, repo string, prNumber int, reviewers, teamReviewers []string) error {
+			requestedUsers = reviewers
+			requestedTeams = teamReviewers
+			return nil
+		},
+	}
+
+	config := &models.Config{}
+	config.GitHub.BotUsername = "ai-bot"
+	config.GitHub.CodeownersReviewers = true
+	config.GitHub.DefaultReviewers = []string{"fallback"}
+
+	assigner := NewReviewerAssigner(mockGitHub, config, zap.NewNop())
+	if err := assigner.AssignReviewers(context.Background(), "example", "repo", 1, "backend", repoDir); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// The bot is dropped, duplicates across files are collapsed and the defaults aren't used
+	if !reflect.DeepEqual(requestedUsers, []string{"gopher"}) {
+		t.Errorf("Expected users [gopher], got %v", requestedUsers)
+	}
+	if !reflect.DeepEqual(requestedTeams, []string{"backend"}) {
+		t.Errorf("Expected teams [backend], got %v", requestedTeams)
+	}
+}
+
+func TestReviewerAssigner_AssignReviewers_DefaultReviewers(t *testing.T) {
+	var requestedUsers, requestedTeams []string
+	mockGitHub := &mocks.MockGitHubService{
+		RequestReviewersFunc: func(owner, repo string, prNumber int, reviewers, teamReviewers []string) error {
+			requestedUsers = reviewers
+			requestedTeams = teamReviewers
+			return nil
+		},
+	}
+
+	config := &models.Config{}
+	config.GitHub.CodeownersReviewers = true
+	config.GitHub.DefaultReviewers = []string{"global"}
+	config.Components = map[string]models.ComponentConfig{
+		"frontend": {DefaultReviewers: []string{"@alice", "org/web"}},
+	}
+
+	// The repository has no CODEOWNERS, so the component's default reviewers are requested
+	assigner := NewReviewerAssigner(mockGitHub, config, zap.NewNop())
+	if err := assigner.AssignReviewers(context.Background(), "example", "repo", 1, "frontend", t.TempDir()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !reflect.DeepEqual(requestedUsers, []string{"alice"}) {
+		t.Errorf("Expected users [alice], got %v", requestedUsers)
+	}
+	if !reflect.DeepEqual(requestedTeams, []string{"web"}) {
+		t.Errorf("Expected teams [web], got %v", requestedTeams)
+	}
+}
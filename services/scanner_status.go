@@ -0,0 +1,51 @@
+package services
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// ScannerStatus is the live state of a background scanner
+type ScannerStatus struct {
+	Running    bool       `json:"running"`
+	LastScanAt *time.Time `json:"last_scan_at,omitempty"` // When the last scan finished; unset before the first one
+	InFlight   []string   `json:"in_flight"`              // Tickets the scanner picked up that aren't finished
+	Queued     int        `json:"queued"`                 // In-flight tickets that haven't started being worked on
+}
+
+// scanClock remembers when a scanner last finished a scan. It has its own lock, since scans finish while Stop holds
+// the scanner's.
+type scanClock struct {
+	mu         sync.Mutex
+	lastScanAt time.Time
+}
+
+// finished records that a scan finished now
+func (c *scanClock) finished() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastScanAt = time.Now().UTC()
+}
+
+// last returns when the last scan finished, or nil before the first one
+func (c *scanClock) last() *time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.lastScanAt.IsZero() {
+		return nil
+	}
+	lastScanAt := c.lastScanAt
+	return &lastScanAt
+}
+
+// inFlightKeys returns the ticket keys of an in-flight set in order
+func inFlightKeys(inFlight *sync.Map) []string {
+	keys := []string{}
+	inFlight.Range(func(key, _ interface{}) bool {
+		keys = append(keys, key.(string))
+		return true
+	})
+	sort.Strings(keys)
+	return keys
+}
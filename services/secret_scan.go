@@ -0,0 +1,247 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// maxSecretScanOutput bounds how much of an external scanner's output is reported when it blocks a push
+const maxSecretScanOutput = 2000
+
+// builtinSecretRules are the patterns of well-known credentials checked unless disabled
+var builtinSecretRules = []models.SecretRule{
+	{ID: "private-key", Pattern: `-----BEGIN (?:[A-Z]+ )*PRIVATE KEY(?: BLOCK)?-----`},
+	{ID: "aws-access-key-id", Pattern: `\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`},
+	{ID: "github-token", Pattern: `\b(?:gh[pousr]_[A-Za-z0-9]{36}|github_pat_[A-Za-z0-9_]{22,})\b`},
+	{ID: "gitlab-token", Pattern: `\bglpat-[A-Za-z0-9_-]{20}\b`},
+	{ID: "slack-token", Pattern: `\bxox[baprs]-[A-Za-z0-9-]{10,}`},
+	{ID: "google-api-key", Pattern: `\bAIza[0-9A-Za-z_-]{35}\b`},
+	{ID: "stripe-secret-key", Pattern: `\b[rs]k_live_[0-9A-Za-z]{24,}\b`},
+	{ID: "anthropic-api-key", Pattern: `\bsk-ant-[A-Za-z0-9_-]{20,}`},
+	{ID: "url-credentials", Pattern: `\b[a-z][a-z0-9+.-]*://[^/\s:@'"]+:[^/\s:@'"${}<>]{6,}@`},
+	{ID: "generic-credential", Pattern: `(?i)\b(?:api[_-]?key|secret|passw(?:or)?d|access[_-]?token|auth[_-]?token|credentials?)\b["']?\s*[:=]\s*["'][^"'\s${}<>]{12,}["']`},
+}
+
+// SecretScanner looks for secrets in a patch about to be pushed
+type SecretScanner interface {
+	// Scan returns the possible secrets in the lines the patch adds
+	Scan(ctx context.Context, directory, patch string) ([]models.SecretFinding, error)
+}
+
+// RegexSecretScanner implements the SecretScanner interface with regular expressions, like gitleaks rules
+type RegexSecretScanner struct {
+	rules         []regexSecretRule
+	allowedPaths  []*regexp.Regexp
+	allowedValues []*regexp.Regexp
+}
+
+// regexSecretRule is a compiled secret rule
+type regexSecretRule struct {
+	id      string
+	pattern *regexp.Regexp
+}
+
+// NewRegexSecretScanner creates a new SecretScanner checking the built-in and configured rules. The patterns are
+// validated when the configuration is loaded.
+func NewRegexSecretScanner(config models.SecretScanConfig) SecretScanner {
+	scanner := &RegexSecretScanner{}
+	for _, rule := range append(slices.Clone(builtinSecretRules), config.Rules...) {
+		if slices.Contains(config.DisabledRules, rule.ID) {
+			continue
+		}
+		scanner.rules = append(scanner.rules, regexSecretRule{id: rule.ID, pattern: regexp.MustCompile(rule.Pattern)})
+	}
+	for _, pattern := range config.AllowedPaths {
+		scanner.allowedPaths = append(scanner.allowedPaths, regexp.MustCompile(pattern))
+	}
+	for _, pattern := range config.AllowedValues {
+		scanner.allowedValues = append(scanner.allowedValues, regexp.MustCompile(pattern))
+	}
+	return scanner
+}
+
+// hunkHeaderPattern matches the header of a hunk, capturing the first line of the hunk in the new file
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// Scan returns the possible secrets in the lines the patch adds
+func (s *RegexSecretScanner) Scan(ctx context.Context, directory, patch string) ([]models.SecretFinding, error) {
+	var findings []models.SecretFinding
+	file, line := "", 0
+	scanner := bufio.NewScanner(strings.NewReader(patch))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		text := scanner.Text()
+		switch {
+		case strings.HasPrefix(text, "+++ "):
+			file = strings.TrimPrefix(strings.TrimPrefix(text, "+++ "), "b/")
+		case strings.HasPrefix(text, "@@"):
+			if match := hunkHeaderPattern.FindStringSubmatch(text); match != nil {
+				line, _ = strconv.Atoi(match[1])
+			}
+		case strings.HasPrefix(text, "+"):
+			if !s.pathAllowed(file) {
+				findings = append(findings, s.scanLine(file, line, text[1:])...)
+			}
+			line++
+		case strings.HasPrefix(text, " "):
+			line++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read patch: %w", err)
+	}
+	return findings, nil
+}
+
+// scanLine returns the possible secrets in an added line
+func (s *RegexSecretScanner) scanLine(file string, line int, text string) []models.SecretFinding {
+	var findings []models.SecretFinding
+	for _, rule := range s.rules {
+		for _, match := range rule.pattern.FindAllString(text, -1) {
+			if s.valueAllowed(match) {
+				continue
+			}
+			findings = append(findings, models.SecretFinding{RuleID: rule.id, File: file, Line: line, Match: maskSecret(match)})
+		}
+	}
+	return findings
+}
+
+// pathAllowed reports whether findings in the file are never reported
+func (s *RegexSecretScanner) pathAllowed(file string) bool {
+	for _, pattern := range s.allowedPaths {
+		if pattern.MatchString(file) {
+			return true
+		}
+	}
+	return false
+}
+
+// valueAllowed reports whether the match is never reported
+func (s *RegexSecretScanner) valueAllowed(match string) bool {
+	for _, pattern := range s.allowedValues {
+		if pattern.MatchString(match) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskSecret keeps only the start of a matched secret, enough to find it but not to use it
+func maskSecret(match string) string {
+	if len(match) <= 8 {
+		return strings.Repeat("*", len(match))
+	}
+	return match[:4] + strings.Repeat("*", min(len(match)-4, 12))
+}
+
+// CommandSecretScanner implements the SecretScanner interface by running an external scanner, e.g. gitleaks, with
+// the patch on stdin
+type CommandSecretScanner struct {
+	command string
+}
+
+// NewCommandSecretScanner creates a new SecretScanner running the shell command. A non-zero exit status is a finding.
+func NewCommandSecretScanner(command string) SecretScanner {
+	return &CommandSecretScanner{command: command}
+}
+
+// Scan runs the scanner in the checkout with the patch on stdin
+func (s *CommandSecretScanner) Scan(ctx context.Context, directory, patch string) ([]models.SecretFinding, error) {
+	cmd := exec.CommandContext(ctx, "sh", "-c", s.command)
+	cmd.Dir = directory
+	cmd.Stdin = strings.NewReader(patch)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && ctx.Err() == nil {
+		return []models.SecretFinding{{RuleID: "command", Match: tailString(strings.TrimSpace(output.String()), maxSecretScanOutput)}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to run secret scanner: %w", err)
+	}
+	return nil, nil
+}
+
+// secretScanningGitHubService is a GitHubService that scans the commits of a branch for secrets before pushing it,
+// refusing to push when any are found
+type secretScanningGitHubService struct {
+	GitHubService
+
+	scanners []SecretScanner
+	logger   *zap.Logger
+}
+
+// NewSecretScanningGitHubService creates a GitHubService scanning pushes of the given service for secrets, if enabled
+func NewSecretScanningGitHubService(githubService GitHubService, config *models.Config, logger *zap.Logger) GitHubService {
+	if !config.SecretScan.Enabled {
+		return githubService
+	}
+	scanners := []SecretScanner{NewRegexSecretScanner(config.SecretScan)}
+	if config.SecretScan.Command != "" {
+		scanners = append(scanners, NewCommandSecretScanner(config.SecretScan.Command))
+	}
+	return &secretScanningGitHubService{GitHubService: githubService, scanners: scanners, logger: logger}
+}
+
+// PushChanges pushes changes to the remote repository, unless they contain secrets
+func (s *secretScanningGitHubService) PushChanges(ctx context.Context, directory, branchName string) error {
+	if err := s.scan(ctx, directory, branchName); err != nil {
+		return err
+	}
+	return s.GitHubService.PushChanges(ctx, directory, branchName)
+}
+
+// ForcePushChanges force-pushes a rewritten branch, unless it contains secrets
+func (s *secretScanningGitHubService) ForcePushChanges(ctx context.Context, directory, branchName string) error {
+	if err := s.scan(ctx, directory, branchName); err != nil {
+		return err
+	}
+	return s.GitHubService.ForcePushChanges(ctx, directory, branchName)
+}
+
+// scan returns an error listing the possible secrets in the commits pushing the branch would publish
+func (s *secretScanningGitHubService) scan(ctx context.Context, directory, branchName string) error {
+	patch, err := s.GitHubService.OutgoingPatch(ctx, directory, branchName)
+	if err != nil {
+		return fmt.Errorf("failed to scan for secrets: %w", err)
+	}
+	if patch == "" {
+		return nil
+	}
+
+	var findings []string
+	for _, scanner := range s.scanners {
+		found, err := scanner.Scan(ctx, directory, patch)
+		if err != nil {
+			return fmt.Errorf("failed to scan for secrets: %w", err)
+		}
+		for _, finding := range found {
+			findings = append(findings, finding.String())
+		}
+	}
+	if len(findings) == 0 {
+		return nil
+	}
+
+	s.logger.Error("Blocked push of possible secrets",
+		zap.String("ticket", auditTicket(ctx)),
+		zap.String("branch", branchName),
+		zap.Strings("findings", findings))
+	return fmt.Errorf("push of %s blocked, the commits contain possible secrets:\n- %s", branchName, strings.Join(findings, "\n- "))
+}
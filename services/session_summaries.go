@@ -0,0 +1,172 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// sessionSummaryLogMutex serializes writes of all session summary logs, since the ticket and PR review processors
+// share the file
+var sessionSummaryLogMutex sync.Mutex
+
+// SessionSummaryLog records condensed summaries of the AI's sessions on each ticket, so later feedback rounds can
+// carry the decisions made in earlier ones forward
+type SessionSummaryLog interface {
+	// Record appends the summary of a session to the log
+	Record(summary models.AISessionSummary) error
+	// Load returns the summaries of a ticket's sessions, oldest first
+	Load(ticket string) ([]models.AISessionSummary, error)
+}
+
+// SessionSummaryLogImpl implements the SessionSummaryLog interface as an append-only file of JSON lines
+type SessionSummaryLogImpl struct {
+	path string
+}
+
+// NewSessionSummaryLog creates a new SessionSummaryLog stored in the given file. An empty path discards all summaries.
+func NewSessionSummaryLog(path string) SessionSummaryLog {
+	return &SessionSummaryLogImpl{path: path}
+}
+
+// Record appends the summary of a session to the log
+func (l *SessionSummaryLogImpl) Record(summary models.AISessionSummary) error {
+	if l.path == "" {
+		return nil
+	}
+	if summary.At.IsZero() {
+		summary.At = time.Now().UTC()
+	}
+	line, err := json.Marshal(summary)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session summary: %w", err)
+	}
+
+	sessionSummaryLogMutex.Lock()
+	defer sessionSummaryLogMutex.Unlock()
+
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open session summary log: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write session summary log: %w", err)
+	}
+	return nil
+}
+
+// Load returns the summaries of a ticket's sessions, oldest first. A missing file has no summaries.
+func (l *SessionSummaryLogImpl) Load(ticket string) ([]models.AISessionSummary, error) {
+	if l.path == "" {
+		return nil, nil
+	}
+	file, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session summary log: %w", err)
+	}
+	defer file.Close()
+
+	var summaries []models.AISessionSummary
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var summary models.AISessionSummary
+		if err := json.Unmarshal(scanner.Bytes(), &summary); err != nil {
+			return nil, fmt.Errorf("failed to parse session summary log: %w", err)
+		}
+		if summary.Ticket == ticket {
+			summaries = append(summaries, summary)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read session summary log: %w", err)
+	}
+	return summaries, nil
+}
+
+// condenseSessionSummary shortens the AI's final report of a session to at most maxTokens, keeping its lines but
+// dropping blank ones
+func condenseSessionSummary(result string, maxTokens int) string {
+	var lines []string
+	for _, line := range strings.Split(result, "\n") {
+		if line = strings.TrimRight(line, " \t"); strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	summary := strings.Join(lines, "\n")
+	if maxChars := maxTokens * charsPerToken; len(summary) > maxChars {
+		summary = summary[:maxChars] + "..."
+	}
+	return summary
+}
+
+// sessionSummaryInstructions asks the AI to end with the report its session summary is condensed from, or returns an
+// empty string if session summaries are disabled
+func sessionSummaryInstructions(config *models.Config) string {
+	if !config.AI.SessionSummaries.Enabled {
+		return ""
+	}
+	return "\n\nEnd your final message with a brief summary of what you changed and the decisions you made, such as " +
+		"approaches you chose or rejected; later sessions on this ticket are given it."
+}
+
+// recordSessionSummary condenses the AI's final report of a session on the ticket into the session summary log.
+// Failures are only logged, since the summaries only improve later prompts.
+func recordSessionSummary(config *models.Config, sessions SessionSummaryLog, logger *zap.Logger, ticketKey, kind string, response interface{}) {
+	settings := config.AI.SessionSummaries
+	if !settings.Enabled || sessions == nil {
+		return
+	}
+	summary := condenseSessionSummary(aiResultText(response), settings.MaxTokens)
+	if summary == "" {
+		return
+	}
+	if err := sessions.Record(models.AISessionSummary{Ticket: ticketKey, Kind: kind, Summary: summary}); err != nil {
+		logger.Warn("Failed to record the AI session summary", zap.String("ticket", ticketKey), zap.Error(err))
+	}
+}
+
+// sessionSummariesPrompt returns the summaries of the latest AI sessions on the ticket to prepend to a prompt, or an
+// empty string if disabled or there are none
+func sessionSummariesPrompt(config *models.Config, sessions SessionSummaryLog, logger *zap.Logger, ticketKey string) string {
+	settings := config.AI.SessionSummaries
+	if !settings.Enabled || sessions == nil {
+		return ""
+	}
+	summaries, err := sessions.Load(ticketKey)
+	if err != nil {
+		logger.Warn("Failed to load the AI session summaries", zap.String("ticket", ticketKey), zap.Error(err))
+		return ""
+	}
+	if len(summaries) == 0 {
+		return ""
+	}
+	if len(summaries) > settings.MaxSessions {
+		summaries = summaries[len(summaries)-settings.MaxSessions:]
+	}
+
+	var prompt strings.Builder
+	prompt.WriteString("## Earlier Sessions on This Ticket\n")
+	prompt.WriteString("You worked on this ticket before. Keep the decisions you made then unless the feedback asks otherwise:\n\n")
+	for _, summary := range summaries {
+		prompt.WriteString(fmt.Sprintf("### %s session, %s\n%s\n\n", summary.Kind, summary.At.Format("2006-01-02 15:04"), summary.Summary))
+	}
+	return prompt.String()
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestCondenseSessionSummary(t *testing.T) {
+	result := "Added an ExportInvoices handler.\n\n  \nChose CSV over XLSX to avoid a dependency.   \n"
+	if got, want := condenseSessionSummary(result, 100), "Added an ExportInvoices handler.\nChose CSV over XLSX to avoid a dependency."; got != want {
+		t.Errorf("condenseSessionSummary() = %q, want %q", got, want)
+	}
+	if got := condenseSessionSummary(strings.Repeat("x", 100), 5); got != strings.Repeat("x", 20)+"..." {
+		t.Errorf("Expected the summary to be truncated to 5 tokens, got %q", got)
+	}
+}
+
+func TestSessionSummariesPrompt(t *testing.T) {
+	config := &models.Config{}
+	config.AI.SessionSummaries.Enabled = true
+	config.AI.SessionSummaries.MaxSessions = 2
+	sessions := NewSessionSummaryLog(filepath.Join(t.TempDir(), "ai-sessions.log"))
+
+	if prompt := sessionSummariesPrompt(config, sessions, zap.NewNop(), "TEST-1"); prompt != "" {
+		t.Errorf("Expected no prompt without sessions, got %q", prompt)
+	}
+
+	for _, summary := range []models.AISessionSummary{
+		{Ticket: "TEST-1", Kind: models.AISessionInitial, Summary: "Added the export."},
+		{Ticket: "TEST-2", Kind: models.AISessionInitial, Summary: "Unrelated."},
+		{Ticket: "TEST-1", Kind: models.AISessionFeedback, Summary: "Renamed the handler."},
+		{Ticket: "TEST-1", Kind: models.AISessionFeedback, Summary: "Kept CSV as the reviewer agreed."},
+	} {
+		if err := sessions.Record(summary); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	prompt := sessionSummariesPrompt(config, sessions, zap.NewNop(), "TEST-1")
+	if !strings.HasPrefix(prompt, "## Earlier Sessions on This Ticket\n") {
+		t.Errorf("Expected the prompt to start with the sessions heading, got:\n%s", prompt)
+	}
+	renamed, kept := strings.Index(prompt, "Renamed the handler."), strings.Index(prompt, "Kept CSV as the reviewer agreed.")
+	if renamed < 0 || kept < renamed {
+		t.Errorf("Expected the latest sessions oldest first, got:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "Added the export.") || strings.Contains(prompt, "Unrelated.") {
+		t.Errorf("Expected only the latest sessions of the ticket, got:\n%s", prompt)
+	}
+
+	config.AI.SessionSummaries.Enabled = false
+	if prompt := sessionSummariesPrompt(config, sessions, zap.NewNop(), "TEST-1"); prompt != "" {
+		t.Errorf("Expected no prompt when disabled, got %q", prompt)
+	}
+}
+
+func TestPRReviewProcessor_ApplyFeedbackFixes_CarriesSessionSummaries(t *testing.T) {
+	config := newBotConfig("ai-bot")
+	config.TempDir = t.TempDir()
+	config.AI.SessionSummaries.Enabled = true
+	config.AI.SessionSummaries.MaxSessions = 5
+	config.AI.SessionSummaries.MaxTokens = 300
+	sessions := NewSessionSummaryLog(filepath.Join(t.TempDir(), "ai-sessions.log"))
+	if err := sessions.Record(models.AISessionSummary{Ticket: "TEST-1", Kind: models.AISessionInitial, Summary: "Chose CSV over XLSX."}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	githubService := &mocks.MockGitHubService{
+		CloneRepositoryFunc: func(repoURL, directory string, opts models.CloneOptions) error {
+			return os.MkdirAll(directory, 0755)
+		},
+	}
+	var prompt string
+	aiService := &mocks.MockClaudeService{
+		GenerateCodeFunc: func(p, repoDir string) (*models.ClaudeResponse, error) {
+			prompt = p
+			return &models.ClaudeResponse{Result: "Renamed the export handler as requested."}, nil
+		},
+	}
+	processor := &PRReviewProcessorImpl{
+		githubService:     githubService,
+		aiService:         aiService,
+		complianceChecker: NewComplianceChecker(config, zap.NewNop()),
+		commandRunner:     NewCommandRunner(config, zap.NewNop()),
+		sessions:          sessions,
+		notifier:          NewNotifier(nil, config, zap.NewNop()),
+		config:            config,
+		logger:            zap.NewNop(),
+	}
+
+	pr := &models.GitHubPRDetails{Number: 7}
+	pr.Head.Ref = "feature/TEST-1"
+	if _, err := processor.applyFeedbackFixes(context.Background(), "TEST-1", "", "https://github.com/bot/repo.git", pr, "Rename the handler", nil, false); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if !strings.HasPrefix(prompt, "## Earlier Sessions on This Ticket\n") || !strings.Contains(prompt, "Chose CSV over XLSX.") {
+		t.Errorf("Expected the earlier session to be prepended to the prompt, got:\n%s", prompt)
+	}
+	summaries, err := sessions.Load("TEST-1")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(summaries) != 2 || summaries[1].Kind != models.AISessionFeedback || summaries[1].Summary != "Renamed the export handler as requested." {
+		t.Errorf("Expected the feedback session to be recorded, got %+v", summaries)
+	}
+}
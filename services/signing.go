@@ -0,0 +1,70 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"jira-ai-issue-solver/models"
+)
+
+// configureSigning configures git in a repository to sign the bot's commits with the configured key
+func (s *GitHubServiceImpl) configureSigning(ctx context.Context, directory string) error {
+	signing := s.config.GitHub.Signing
+
+	var signingKey, format string
+	switch signing.Format {
+	case models.CommitSigningNone:
+		return nil
+	case models.CommitSigningGPG:
+		if err := s.importGPGKey(ctx); err != nil {
+			return err
+		}
+		signingKey, format = signing.KeyID, "openpgp"
+	case models.CommitSigningSSH:
+		// git accepts the path of an SSH private key as signing key
+		signingKey, format = signing.KeyFile, "ssh"
+	default:
+		return fmt.Errorf("unsupported commit signing format: %s", signing.Format)
+	}
+
+	settings := [][]string{
+		{"gpg.format", format},
+		{"user.signingkey", signingKey},
+		{"commit.gpgsign", "true"},
+	}
+	for _, setting := range settings {
+		if err := s.runGit(ctx, directory, "config", setting[0], setting[1]); err != nil {
+			return fmt.Errorf("failed to configure commit signing (%s): %w", setting[0], err)
+		}
+	}
+	return nil
+}
+
+// importGPGKey imports the OpenPGP signing key into the keyring git signs with. It runs once per process.
+func (s *GitHubServiceImpl) importGPGKey(ctx context.Context) error {
+	s.gpgImportOnce.Do(func() {
+		cmd := s.executor(ctx, "gpg", "--batch", "--import", s.config.GitHub.Signing.KeyFile)
+
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+
+		if err := cmd.Run(); err != nil {
+			s.gpgImportErr = fmt.Errorf("failed to import GPG signing key: %w, stderr: %s", err, stderr.String())
+		}
+	})
+	return s.gpgImportErr
+}
+
+// commitArgs builds the git commit arguments, requesting a signature when commit signing is enabled
+// and setting the author of the checkout if one was set
+func (s *GitHubServiceImpl) commitArgs(directory, message string) []string {
+	args := []string{"commit"}
+	if s.config.GitHub.Signing.Format != models.CommitSigningNone {
+		args = append(args, "--gpg-sign")
+	}
+	if author, ok := s.authors.Load(directory); ok {
+		args = append(args, "--author="+author.(models.GitIdentity).String())
+	}
+	return append(args, "-m", message)
+}
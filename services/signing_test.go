@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+)
+
+func TestConfigureSigning(t *testing.T) {
+	tests := []struct {
+		name             string
+		format           models.CommitSigningFormat
+		keyFile          string
+		keyID            string
+		expectedCommands []string
+	}{
+		{
+			name:             "disabled",
+			format:           models.CommitSigningNone,
+			expectedCommands: nil,
+		},
+		{
+			name:    "ssh",
+			format:  models.CommitSigningSSH,
+			keyFile: "/secrets/signing_key",
+			expectedCommands: []string{
+				"git config gpg.format ssh",
+				"git config user.signingkey /secrets/signing_key",
+				"git config commit.gpgsign true",
+			},
+		},
+		{
+			name:    "gpg",
+			format:  models.CommitSigningGPG,
+			keyFile: "/secrets/signing_key.asc",
+			keyID:   "ABCDEF0123456789",
+			expectedCommands: []string{
+				"gpg --batch --import /secrets/signing_key.asc",
+				"git config gpg.format openpgp",
+				"git config user.signingkey ABCDEF0123456789",
+				"git config commit.gpgsign true",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var executedCommands []string
+			mockExecutor := func(ctx context.Context, name string, args ...string) *exec.Cmd {
+				executedCommands = append(executedCommands, strings.Join(append([]string{name}, args...), " "))
+				return exec.Command("true")
+			}
+
+			config := &models.Config{}
+			config.GitHub.Signing.Format = tt.format
+			config.GitHub.Signing.KeyFile = tt.keyFile
+			config.GitHub.Signing.KeyID = tt.keyID
+			service := newTestGitHubService(config, mockExecutor).(*GitHubServiceImpl)
+
+			if err := service.configureSigning(context.Background(), t.TempDir()); err != nil {
+				t.Fatalf("configureSigning() error = %v", err)
+			}
+			if !reflect.DeepEqual(executedCommands, tt.expectedCommands) {
+				t.Errorf("Expected commands %v, got %v", tt.expectedCommands, executedCommands)
+			}
+		})
+	}
+}
+
+func TestCommitArgs(t *testing.T) {
+	config := &models.Config{}
+	service := newTestGitHubService(config).(*GitHubServiceImpl)
+
+	if got := service.commitArgs("/repo", "msg"); !reflect.DeepEqual(got, []string{"commit", "-m", "msg"}) {
+		t.Errorf("Expected unsigned commit, got %v", got)
+	}
+
+	config.GitHub.Signing.Format = models.CommitSigningSSH
+	if got := service.commitArgs("/repo", "msg"); !reflect.DeepEqual(got, []string{"commit", "--gpg-sign", "-m", "msg"}) {
+		t.Errorf("Expected signed commit, got %v", got)
+	}
+
+	service.SetCommitAuthor("/repo", &models.GitIdentity{Name: "Jane Doe", Email: "jane@example.com"})
+	if got := service.commitArgs("/repo", "msg"); !reflect.DeepEqual(got, []string{"commit", "--gpg-sign", "--author=Jane Doe <jane@example.com>", "-m", "msg"}) {
+		t.Errorf("Expected commit by the author, got %v", got)
+	}
+	if got := service.commitArgs("/other", "msg"); !reflect.DeepEqual(got, []string{"commit", "--gpg-sign", "-m", "msg"}) {
+		t.Errorf("Expected the author of other checkouts to be unchanged, got %v", got)
+	}
+
+	service.SetCommitAuthor("/repo", nil)
+	if got := service.commitArgs("/repo", "msg"); !reflect.DeepEqual(got, []string{"commit", "--gpg-sign", "-m", "msg"}) {
+		t.Errorf("Expected commit by the bot, got %v", got)
+	}
+}
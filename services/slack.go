@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// slackMessage is the payload of a Slack incoming webhook
+type slackMessage struct {
+	Text     string `json:"text"`
+	Channel  string `json:"channel,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+// SlackSink implements the NotificationSink interface by posting to Slack incoming webhooks
+type SlackSink struct {
+	config *models.Config
+	client *http.Client
+	logger *zap.Logger
+}
+
+// NewSlackSink creates a new NotificationSink posting to the Slack webhooks in the configuration
+func NewSlackSink(config *models.Config, logger *zap.Logger) NotificationSink {
+	return &SlackSink{
+		config: config,
+		client: &http.Client{Timeout: config.HTTPTimeout()},
+		logger: logger,
+	}
+}
+
+// Name returns the name of the sink in logs
+func (s *SlackSink) Name() string {
+	return "slack"
+}
+
+// Send posts the notification to the webhook of its event, unless the event isn't routed to Slack
+func (s *SlackSink) Send(ctx context.Context, notification models.Notification) error {
+	slack := s.config.Notifications.Slack
+	route, ok := slack.Route(notification.Event)
+	if !ok {
+		return nil
+	}
+
+	text, err := renderNotification(route, notification)
+	if err != nil {
+		return err
+	}
+	message := slackMessage{Text: text, Channel: slack.Channel, Username: slack.Username}
+	if route.Channel != "" {
+		message.Channel = route.Channel
+	}
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	webhookURL := slack.WebhookURL
+	if route.WebhookURL != "" {
+		webhookURL = route.WebhookURL
+	}
+	if err := postWebhook(ctx, s.client, webhookURL, payload); err != nil {
+		return fmt.Errorf("failed to post to Slack: %w", err)
+	}
+	s.logger.Debug("Sent Slack notification", zap.String("event", string(notification.Event)), zap.String("ticket", notification.Ticket))
+	return nil
+}
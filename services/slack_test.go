@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestSlackSink_Send(t *testing.T) {
+	config := &models.Config{}
+	config.Notifications.Slack.WebhookURL = "https://hooks.slack.com/services/T/B/default"
+	config.Notifications.Slack.Channel = "#ai"
+	config.Notifications.Slack.Username = "AI Bot"
+	config.Notifications.Slack.Events = map[models.NotificationEvent]models.NotificationRoute{
+		models.NotificationPRCreated:        {},
+		models.NotificationProcessingFailed: {Channel: "#ai-failures", WebhookURL: "https://hooks.slack.com/services/T/B/failures", Template: ":x: {{.Ticket}}: {{.Message}}"},
+	}
+
+	type request struct {
+		url     string
+		message slackMessage
+	}
+	var requests []request
+	sink := NewSlackSink(config, zap.NewNop()).(*SlackSink)
+	sink.client = NewTestClient(func(req *http.Request) (*http.Response, error) {
+		var message slackMessage
+		if err := json.NewDecoder(req.Body).Decode(&message); err != nil {
+			t.Fatalf("Failed to decode message: %v", err)
+		}
+		requests = append(requests, request{url: req.URL.String(), message: message})
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("ok")), Header: make(http.Header)}, nil
+	})
+
+	ctx := context.Background()
+	notifications := []models.Notification{
+		{Event: models.NotificationPRCreated, Ticket: "TEST-1", TicketURL: "https://jira/browse/TEST-1", PRURL: "https://github.com/example/repo/pull/7"},
+		{Event: models.NotificationProcessingFailed, Ticket: "TEST-2", Message: "AI made no changes to the repository"},
+		{Event: models.NotificationFeedbackApplied, Ticket: "TEST-3"},
+	}
+	for _, notification := range notifications {
+		if err := sink.Send(ctx, notification); err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("Expected only the routed events to be posted, got %+v", requests)
+	}
+	created, failed := requests[0], requests[1]
+	if created.url != config.Notifications.Slack.WebhookURL || created.message.Channel != "#ai" || created.message.Username != "AI Bot" {
+		t.Errorf("Expected the default webhook and channel, got %+v", created)
+	}
+	if !strings.Contains(created.message.Text, "https://github.com/example/repo/pull/7") {
+		t.Errorf("Expected the default template with the PR URL, got %q", created.message.Text)
+	}
+	if failed.url != "https://hooks.slack.com/services/T/B/failures" || failed.message.Channel != "#ai-failures" {
+		t.Errorf("Expected the event's webhook and channel, got %+v", failed)
+	}
+	if failed.message.Text != ":x: TEST-2: AI made no changes to the repository" {
+		t.Errorf("Expected the event's template, got %q", failed.message.Text)
+	}
+}
+
+func TestSlackSink_Send_WebhookError(t *testing.T) {
+	config := &models.Config{}
+	config.Notifications.Slack.WebhookURL = "https://hooks.slack.com/services/T/B/X"
+	sink := NewSlackSink(config, zap.NewNop()).(*SlackSink)
+	sink.client = NewTestClient(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader("no_service")), Header: make(http.Header)}, nil
+	})
+
+	err := sink.Send(context.Background(), models.Notification{Event: models.NotificationPRCreated, Ticket: "TEST-1"})
+	if err == nil || !strings.Contains(err.Error(), "no_service") {
+		t.Errorf("Expected the webhook's error, got %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+)
+
+// errNoActiveSprint is returned when the scan is restricted to a board's active sprint and the board has none
+var errNoActiveSprint = errors.New("no active sprint")
+
+// activeSprintJQL returns the JQL clause restricting a search to tickets in an active sprint, or an empty string if
+// the scan isn't restricted. Without a board, any open sprint matches; with a board, only the board's active sprints,
+// so sprints of other boards sharing the project don't release their tickets.
+func activeSprintJQL(ctx context.Context, jiraService JiraService, config *models.Config) (string, error) {
+	if !config.Jira.Sprint.ActiveOnly {
+		return "", nil
+	}
+	if config.Jira.Sprint.BoardID == 0 {
+		return " AND sprint in openSprints()", nil
+	}
+
+	sprints, err := jiraService.GetActiveSprints(ctx, config.Jira.Sprint.BoardID)
+	if err != nil {
+		return "", err
+	}
+	if len(sprints) == 0 {
+		return "", errNoActiveSprint
+	}
+	ids := make([]string, 0, len(sprints))
+	for _, sprint := range sprints {
+		ids = append(ids, strconv.Itoa(sprint.ID))
+	}
+	return fmt.Sprintf(" AND sprint in (%s)", strings.Join(ids, ", ")), nil
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+)
+
+func TestActiveSprintJQL(t *testing.T) {
+	tests := []struct {
+		name       string
+		activeOnly bool
+		boardID    int
+		sprints    []models.JiraSprint
+		sprintsErr error
+		want       string
+		wantErr    error
+	}{
+		{name: "not restricted", want: ""},
+		{name: "open sprints without a board", activeOnly: true, want: " AND sprint in openSprints()"},
+		{
+			name:       "active sprints of the board",
+			activeOnly: true,
+			boardID:    7,
+			sprints:    []models.JiraSprint{{ID: 37, State: "active"}, {ID: 41, State: "active"}},
+			want:       " AND sprint in (37, 41)",
+		},
+		{name: "board without an active sprint", activeOnly: true, boardID: 7, wantErr: errNoActiveSprint},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &models.Config{}
+			config.Jira.Sprint.ActiveOnly = tt.activeOnly
+			config.Jira.Sprint.BoardID = tt.boardID
+			jiraService := &mocks.MockJiraService{
+				GetActiveSprintsFunc: func(boardID int) ([]models.JiraSprint, error) {
+					if boardID != tt.boardID {
+						t.Errorf("Expected the sprints of board %d, got %d", tt.boardID, boardID)
+					}
+					return tt.sprints, tt.sprintsErr
+				},
+			}
+
+			got, err := activeSprintJQL(context.Background(), jiraService, config)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected error %v, got: %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
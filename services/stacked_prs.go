@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// stackPlanFile is the file the AI writes its commit grouping hints to
+const stackPlanFile = ".ai-stack.json"
+
+// StackPlan describes how a large change should be split into dependent pull requests
+type StackPlan struct {
+	Parts []StackPart `json:"parts"`
+}
+
+// StackPart is a single layer of a PR stack
+type StackPart struct {
+	Title string   `json:"title"`
+	Files []string `json:"files"`
+}
+
+// stackPlanPrompt instructs the AI to leave commit grouping hints for large changes
+func stackPlanPrompt(minChangedLines int) string {
+	return fmt.Sprintf("\n\nIf your change is large (more than about %d changed lines) and consists of independently reviewable steps "+
+		"(for example a refactoring followed by the feature that builds on it), describe how to split it by writing a file named %s "+
+		"in the repository root with the format {\"parts\": [{\"title\": \"...\", \"files\": [\"path/to/file\"]}]}. "+
+		"List the parts in the order they must be merged; each part must build on its own together with the parts before it. "+
+		"Do not create this file for small or tightly coupled changes.", minChangedLines, stackPlanFile)
+}
+
+// loadStackPlan reads and removes the AI's stack plan. It returns nil when the change should go into a single PR.
+func (p *TicketProcessorImpl) loadStackPlan(ctx context.Context, ticketKey, repoDir string) *StackPlan {
+	planPath := filepath.Join(repoDir, stackPlanFile)
+	data, err := os.ReadFile(planPath)
+	if err != nil {
+		return nil
+	}
+
+	// The hint file must never be committed
+	if err := os.Remove(planPath); err != nil {
+		p.logger.Warn("Failed to remove stack plan file", zap.String("ticket", ticketKey), zap.Error(err))
+	}
+
+	if !p.config.GitHub.StackedPRs.Enabled {
+		return nil
+	}
+
+	var plan StackPlan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		p.logger.Warn("Ignoring invalid stack plan", zap.String("ticket", ticketKey), zap.Error(err))
+		return nil
+	}
+	if len(plan.Parts) < 2 {
+		return nil
+	}
+	for _, part := range plan.Parts[:len(plan.Parts)-1] {
+		if len(part.Files) == 0 {
+			p.logger.Warn("Ignoring stack plan with an empty part", zap.String("ticket", ticketKey), zap.String("part", part.Title))
+			return nil
+		}
+	}
+
+	changedLines, err := p.githubService.CountChangedLines(ctx, repoDir)
+	if err != nil {
+		p.logger.Warn("Failed to measure change size, creating a single PR", zap.String("ticket", ticketKey), zap.Error(err))
+		return nil
+	}
+	if changedLines < p.config.GitHub.StackedPRs.MinChangedLines {
+		p.logger.Info("Change is below the stacked PR threshold, creating a single PR",
+			zap.String("ticket", ticketKey),
+			zap.Int("changed_lines", changedLines),
+			zap.Int("min_changed_lines", p.config.GitHub.StackedPRs.MinChangedLines))
+		return nil
+	}
+
+	return &plan
+}
+
+// commitStack commits each part of the plan on its own branch stacked on the previous one.
+// Files not listed in any part are committed with the last part.
+func (p *TicketProcessorImpl) commitStack(ctx context.Context, run *TicketRun) error {
+	total := len(run.StackPlan.Parts)
+
+	for i, part := range run.StackPlan.Parts {
+		partBranch := fmt.Sprintf("%s-part%d", run.BranchName, i+1)
+		if err := p.githubService.CreateBranchFromHead(ctx, run.RepoDir, partBranch); err != nil {
+			return err
+		}
+
+		message := fmt.Sprintf("%s: %s (%d/%d)", run.Key, part.Title, i+1, total)
+		if run.SignOff {
+			message = withSignOff(message, p.config)
+		}
+		var err error
+		if i == total-1 {
+			err = p.githubService.CommitChanges(ctx, run.RepoDir, message)
+			if errors.Is(err, ErrNoChanges) {
+				// The earlier parts took every file, so the last part would be an empty PR
+				p.logger.Info("Dropping empty last part of the stack", zap.String("ticket", run.Key), zap.String("part", part.Title))
+				run.StackPlan.Parts = run.StackPlan.Parts[:i]
+				return nil
+			}
+		} else {
+			err = p.githubService.CommitFiles(ctx, run.RepoDir, message, part.Files)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to commit part %d: %w", i+1, err)
+		}
+		run.Branches = append(run.Branches, partBranch)
+	}
+
+	return nil
+}
+
+// openStackedPullRequests opens a pull request per committed part of the plan, in merge order.
+// bodyNote is appended to every PR description.
+//
+// PRs from a fork can only target branches of the upstream repository, so every PR targets the target
+// branch and contains the commits of the parts below it; its diff shrinks as the earlier PRs are merged.
+func (p *TicketProcessorImpl) openStackedPullRequests(ctx context.Context, run *TicketRun, bodyNote string) ([]*models.GitHubCreatePRResponse, error) {
+	ticketKey, owner, repo := run.Key, run.Owner, run.Repo
+	total := len(run.StackPlan.Parts)
+	var prs []*models.GitHubCreatePRResponse
+
+	for i, partBranch := range run.Branches {
+		part := run.StackPlan.Parts[i]
+		title := fmt.Sprintf("%s [%d/%d]: %s", ticketKey, i+1, total, part.Title)
+		body := fmt.Sprintf("This PR is part %d of %d addressing the issue described in %s.\n\n**Summary:** %s",
+			i+1, total, ticketKey, run.Ticket.Fields.Summary)
+		if i > 0 {
+			body += fmt.Sprintf("\n\nDepends on #%d. Review only the last commit until the earlier PRs are merged.", prs[i-1].Number)
+		}
+		body += bodyNote
+
+		head := pullRequestHead(run, partBranch)
+		pr, err := p.openPullRequest(ctx, run, title, body, head)
+		if err != nil {
+			return prs, fmt.Errorf("failed to create pull request for part %d: %w", i+1, err)
+		}
+		prs = append(prs, pr)
+
+		p.logger.Info("Created stacked pull request",
+			zap.String("ticket", ticketKey),
+			zap.Int("part", i+1),
+			zap.Int("total", total),
+			zap.String("pr_url", pr.HTMLURL))
+	}
+
+	// Cross-link the stack on every PR now that all PR numbers are known
+	stackComment := formatStackComment(prs)
+	for _, pr := range prs {
+		if err := p.githubService.AddPRComment(ctx, owner, repo, pr.Number, stackComment); err != nil {
+			p.logger.Warn("Failed to add stack comment",
+				zap.String("ticket", ticketKey),
+				zap.String("pr_url", pr.HTMLURL),
+				zap.Error(err))
+		}
+	}
+
+	return prs, nil
+}
+
+// formatStackComment lists the PRs of a stack in merge order
+func formatStackComment(prs []*models.GitHubCreatePRResponse) string {
+	var sb strings.Builder
+	sb.WriteString("This change is split into stacked pull requests. Please merge them in order:\n\n")
+	for i, pr := range prs {
+		sb.WriteString(fmt.Sprintf("%d. #%d\n", i+1, pr.Number))
+	}
+	return sb.String()
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// suggestionPattern matches a GitHub suggested change block; the group is the suggested code
+var suggestionPattern = regexp.MustCompile("(?ms)^[ \\t]*```suggestion[ \\t]*\\r?\\n(.*?)^[ \\t]*```[ \\t]*$")
+
+// Suggestion is a change a reviewer suggested on lines of a file, which replaces those lines as is
+type Suggestion struct {
+	Comment   models.GitHubPRComment
+	StartLine int
+	EndLine   int
+	Lines     []string // The replacement lines; none deletes the commented lines
+}
+
+// parseSuggestion returns the suggested change of an inline review comment. Comments with more than one
+// suggestion block, and comments on code that changed since, are left to the AI.
+func parseSuggestion(comment models.GitHubPRComment) (*Suggestion, bool) {
+	matches := suggestionPattern.FindAllStringSubmatch(comment.Body, -1)
+	if len(matches) != 1 || comment.Path == "" || comment.Line == 0 {
+		return nil, false
+	}
+
+	startLine := comment.StartLine
+	if startLine == 0 || startLine > comment.Line {
+		startLine = comment.Line
+	}
+
+	var lines []string
+	if code := strings.ReplaceAll(matches[0][1], "\r\n", "\n"); code != "" {
+		lines = strings.Split(strings.TrimSuffix(code, "\n"), "\n")
+	}
+	return &Suggestion{Comment: comment, StartLine: startLine, EndLine: comment.Line, Lines: lines}, true
+}
+
+// splitSuggestions separates the new inline review comments carrying a suggested change from free-form ones
+func (p *PRReviewProcessorImpl) splitSuggestions(comments []models.GitHubPRComment) ([]*Suggestion, []models.GitHubPRComment) {
+	var suggestions []*Suggestion
+	var freeForm []models.GitHubPRComment
+	for _, comment := range comments {
+		if suggestion, ok := parseSuggestion(comment); ok {
+			suggestions = append(suggestions, suggestion)
+		} else {
+			freeForm = append(freeForm, comment)
+		}
+	}
+	return suggestions, freeForm
+}
+
+// suggestionPatchContext is the number of unchanged lines around a suggestion in its patch
+const suggestionPatchContext = 3
+
+// suggestionPatch renders the suggestion as a unified diff against the current content of its file
+func suggestionPatch(suggestion *Suggestion, content string) (string, error) {
+	missingNewline := content != "" && !strings.HasSuffix(content, "\n")
+	fileLines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	if suggestion.EndLine > len(fileLines) {
+		return "", fmt.Errorf("line %d is past the end of %s", suggestion.EndLine, suggestion.Comment.Path)
+	}
+
+	// Lines are numbered from 1; the hunk covers the suggestion and its context
+	first := max(1, suggestion.StartLine-suggestionPatchContext)
+	last := min(len(fileLines), suggestion.EndLine+suggestionPatchContext)
+	oldCount := last - first + 1
+	newCount := oldCount - (suggestion.EndLine - suggestion.StartLine + 1) + len(suggestion.Lines)
+	newFirst := first
+	if newCount == 0 {
+		// An empty hunk side starts at the line before it
+		newFirst--
+	}
+
+	// The file's missing final newline is marked after the last line of each side
+	noNewline := "\\ No newline at end of file\n"
+	endsFile := func(line int) bool { return missingNewline && line == len(fileLines) }
+
+	var patch strings.Builder
+	path := filepath.ToSlash(suggestion.Comment.Path)
+	patch.WriteString(fmt.Sprintf("--- a/%s\n+++ b/%s\n", path, path))
+	patch.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", first, oldCount, newFirst, newCount))
+	for line := first; line < suggestion.StartLine; line++ {
+		patch.WriteString(" " + fileLines[line-1] + "\n")
+	}
+	for line := suggestion.StartLine; line <= suggestion.EndLine; line++ {
+		patch.WriteString("-" + fileLines[line-1] + "\n")
+		if endsFile(line) {
+			patch.WriteString(noNewline)
+		}
+	}
+	for _, line := range suggestion.Lines {
+		patch.WriteString("+" + line + "\n")
+	}
+	if endsFile(suggestion.EndLine) && len(suggestion.Lines) > 0 {
+		patch.WriteString(noNewline)
+	}
+	for line := suggestion.EndLine + 1; line <= last; line++ {
+		patch.WriteString(" " + fileLines[line-1] + "\n")
+		if endsFile(line) {
+			patch.WriteString(noNewline)
+		}
+	}
+	return patch.String(), nil
+}
+
+// applySuggestions applies suggested changes to the checkout with git, bottom-up in each file so line numbers
+// of the remaining suggestions stay valid. It returns the review replies of the applied suggestions, keyed by
+// comment ID, and the comments whose suggestion couldn't be applied, which are left to the AI.
+func (p *PRReviewProcessorImpl) applySuggestions(ctx context.Context, ticketKey, repoDir string, suggestions []*Suggestion) (map[int64]ReviewReply, []models.GitHubPRComment) {
+	sorted := append([]*Suggestion(nil), suggestions...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Comment.Path != sorted[j].Comment.Path {
+			return sorted[i].Comment.Path < sorted[j].Comment.Path
+		}
+		return sorted[i].StartLine > sorted[j].StartLine
+	})
+
+	replies := make(map[int64]ReviewReply)
+	var failed []models.GitHubPRComment
+	appliedFrom := make(map[string]int) // First line of the topmost applied suggestion per file
+	for _, suggestion := range sorted {
+		err := func() error {
+			if first, ok := appliedFrom[suggestion.Comment.Path]; ok && suggestion.EndLine >= first {
+				return fmt.Errorf("overlaps another suggestion")
+			}
+			content, err := os.ReadFile(filepath.Join(repoDir, suggestion.Comment.Path))
+			if err != nil {
+				return fmt.Errorf("failed to read file: %w", err)
+			}
+			patch, err := suggestionPatch(suggestion, string(content))
+			if err != nil {
+				return err
+			}
+			return p.githubService.ApplyPatch(ctx, repoDir, patch)
+		}()
+		if err != nil {
+			p.logger.Warn("Leaving suggested change to the AI",
+				zap.String("ticket", ticketKey),
+				zap.Int64("comment_id", suggestion.Comment.ID),
+				zap.Error(err))
+			failed = append(failed, suggestion.Comment)
+			continue
+		}
+
+		appliedFrom[suggestion.Comment.Path] = suggestion.StartLine
+		replies[suggestion.Comment.ID] = ReviewReply{
+			CommentID: suggestion.Comment.ID,
+			Addressed: true,
+			Reply:     "Applied the suggested change.",
+		}
+	}
+
+	p.logger.Info("Applied suggested changes",
+		zap.String("ticket", ticketKey),
+		zap.Int("applied", len(replies)),
+		zap.Int("failed", len(failed)))
+	return replies, failed
+}
+
+// appliedSuggestionsPrompt tells the AI which suggested changes are already in the code
+func appliedSuggestionsPrompt(suggestions []*Suggestion, applied map[int64]ReviewReply) string {
+	var prompt strings.Builder
+	for _, suggestion := range suggestions {
+		if _, ok := applied[suggestion.Comment.ID]; !ok {
+			continue
+		}
+		if prompt.Len() == 0 {
+			prompt.WriteString("\n\n## Suggested Changes Already Applied\n")
+			prompt.WriteString("The suggested changes of these review comments were applied to the code as is. Do not apply them again:\n")
+		}
+		prompt.WriteString(fmt.Sprintf("- Comment #%d on %s:%d\n", suggestion.Comment.ID, suggestion.Comment.Path, suggestion.EndLine))
+	}
+	return prompt.String()
+}
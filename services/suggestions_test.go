@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestParseSuggestion(t *testing.T) {
+	tests := []struct {
+		name     string
+		comment  models.GitHubPRComment
+		ok       bool
+		start    int
+		end      int
+		expected []string
+	}{
+		{
+			name:     "single line",
+			comment:  models.GitHubPRComment{Path: "main.go", Line: 4, Body: "Use a clearer name\n```suggestion\n\tcount := 0\n```"},
+			ok:       true,
+			start:    4,
+			end:      4,
+			expected: []string{"\tcount := 0"},
+		},
+		{
+			name:     "multiple lines",
+			comment:  models.GitHubPRComment{Path: "main.go", StartLine: 2, Line: 3, Body: "```suggestion\na\nb\nc\n```\n"},
+			ok:       true,
+			start:    2,
+			end:      3,
+			expected: []string{"a", "b", "c"},
+		},
+		{
+			name:    "deletion",
+			comment: models.GitHubPRComment{Path: "main.go", Line: 4, Body: "```suggestion\n```"},
+			ok:      true,
+			start:   4,
+			end:     4,
+		},
+		{
+			name:    "free-form",
+			comment: models.GitHubPRComment{Path: "main.go", Line: 4, Body: "Please handle the error"},
+		},
+		{
+			name:    "outdated",
+			comment: models.GitHubPRComment{Path: "main.go", OriginalLine: 4, Body: "```suggestion\nx\n```"},
+		},
+		{
+			name:    "several suggestions",
+			comment: models.GitHubPRComment{Path: "main.go", Line: 4, Body: "```suggestion\nx\n```\nor\n```suggestion\ny\n```"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestion, ok := parseSuggestion(tt.comment)
+			if ok != tt.ok {
+				t.Fatalf("Expected ok %v, got %v", tt.ok, ok)
+			}
+			if !ok {
+				return
+			}
+			if suggestion.StartLine != tt.start || suggestion.EndLine != tt.end || !reflect.DeepEqual(suggestion.Lines, tt.expected) {
+				t.Errorf("Expected lines %d-%d replaced with %q, got %d-%d with %q",
+					tt.start, tt.end, tt.expected, suggestion.StartLine, suggestion.EndLine, suggestion.Lines)
+			}
+		})
+	}
+}
+
+func TestPRReviewProcessor_ApplySuggestions(t *testing.T) {
+	repoDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repoDir, "main.go"), []byte("one\ntwo\nthree\nfour\nfive"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	comments := []models.GitHubPRComment{
+		{ID: 1, Path: "main.go", Line: 1, Body: "```suggestion\nONE\n```"},
+		{ID: 2, Path: "main.go", StartLine: 4, Line: 5, Body: "```suggestion\nFOUR AND FIVE\n```"},
+		{ID: 3, Path: "main.go", Line: 2, Body: "```suggestion\n```"},
+		{ID: 4, Path: "main.go", StartLine: 3, Line: 4, Body: "```suggestion\nclashes with #2\n```"},
+		{ID: 5, Path: "main.go", Line: 3, Body: "Rename this"},
+	}
+	processor := &PRReviewProcessorImpl{
+		githubService: newTestGitHubService(&models.Config{}),
+		config:        newBotConfig("ai-bot"),
+		logger:        zap.NewNop(),
+	}
+
+	suggestions, freeForm := processor.splitSuggestions(comments)
+	if len(suggestions) != 4 || len(freeForm) != 1 || freeForm[0].ID != 5 {
+		t.Fatalf("Expected 4 suggestions and the free-form comment, got %d and %+v", len(suggestions), freeForm)
+	}
+
+	replies, failed := processor.applySuggestions(context.Background(), "TEST-1", repoDir, suggestions)
+	if len(failed) != 1 || failed[0].ID != 4 {
+		t.Errorf("Expected the overlapping suggestion to be left to the AI, got %+v", failed)
+	}
+	for _, id := range []int64{1, 2, 3} {
+		if reply, ok := replies[id]; !ok || !reply.Addressed {
+			t.Errorf("Expected a reply for applied suggestion %d, got %+v", id, replies)
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(repoDir, "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "ONE\nthree\nFOUR AND FIVE" {
+		t.Errorf("Expected the suggestions to be applied, got %q", content)
+	}
+
+	prompt := appliedSuggestionsPrompt(suggestions, replies)
+	if !strings.Contains(prompt, "Comment #2 on main.go:5") || strings.Contains(prompt, "Comment #4") {
+		t.Errorf("Expected the prompt to list the applied suggestions only, got:\n%s", prompt)
+	}
+}
+
+func TestPRReviewProcessor_ApplyFeedbackFixes_SuggestionsOnly(t *testing.T) {
+	config := newBotConfig("ai-bot")
+	config.TempDir = t.TempDir()
+
+	var patches []string
+	var commitMessage string
+	githubService := &mocks.MockGitHubService{
+		CloneRepositoryFunc: func(repoURL, directory string, opts models.CloneOptions) error {
+			if err := os.MkdirAll(directory, 0755); err != nil {
+				return err
+			}
+			return os.WriteFile(filepath.Join(directory, "main.go"), []byte("x := 1\n"), 0644)
+		},
+		ApplyPatchFunc: func(directory, patch string) error {
+			patches = append(patches, patch)
+			return nil
+		},
+		CommitChangesFunc: func(directory, message string) error {
+			commitMessage = message
+			return nil
+		},
+	}
+	aiService := &mocks.MockClaudeService{
+		GenerateCodeFunc: func(prompt, repoDir string) (*models.ClaudeResponse, error) {
+			t.Error("Expected the AI not to run for suggested changes only")
+			return nil, nil
+		},
+	}
+	processor := &PRReviewProcessorImpl{
+		githubService:     githubService,
+		aiService:         aiService,
+		complianceChecker: NewComplianceChecker(config, zap.NewNop()),
+		commandRunner:     NewCommandRunner(config, zap.NewNop()),
+		notifier:          NewNotifier(nil, config, zap.NewNop()),
+		config:            config,
+		logger:            zap.NewNop(),
+	}
+
+	pr := &models.GitHubPRDetails{Number: 7}
+	pr.Head.Ref = "feature/TEST-1"
+	suggestions, _ := processor.splitSuggestions([]models.GitHubPRComment{{ID: 1, Path: "main.go", Line: 1, Body: "```suggestion\ncount := 1\n```"}})
+	replies, err := processor.applyFeedbackFixes(context.Background(), "TEST-1", "", "https://github.com/bot/repo.git", pr, "", suggestions, true)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if len(patches) != 1 || !strings.Contains(patches[0], "-x := 1\n+count := 1\n") {
+		t.Errorf("Expected the suggestion to be applied as a patch, got %q", patches)
+	}
+	if commitMessage != "TEST-1: Apply suggested changes from PR review" {
+		t.Errorf("Unexpected commit message %q", commitMessage)
+	}
+	if !replies[1].Addressed {
+		t.Errorf("Expected a reply for the applied suggestion, got %+v", replies)
+	}
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// resolveTargetBranch sets the branch the ticket's pull request targets: the ticket's target branch field if it's
+// set, the component's target branch, or else the repository's default branch. A field value that isn't a branch
+// name fails the ticket rather than opening the pull request against the wrong branch.
+func (p *TicketProcessorImpl) resolveTargetBranch(ctx context.Context, run *TicketRun) error {
+	run.TargetBranch = p.config.GetTargetBranch(run.Component)
+	if err := p.resolveTicketTargetBranch(ctx, run); err != nil {
+		return err
+	}
+	if run.TargetBranch != "" {
+		return nil
+	}
+
+	branch, err := p.githubService.GetDefaultBranch(ctx, run.Owner, run.Repo)
+	if err != nil {
+		p.logger.Error("Failed to get the repository's default branch",
+			zap.String("ticket", run.Key),
+			zap.String("owner", run.Owner),
+			zap.String("repo", run.Repo),
+			zap.Error(err))
+		p.handleFailure(ctx, run.Key, fmt.Sprintf("Failed to get the default branch of %s/%s: %v", run.Owner, run.Repo, err))
+		return err
+	}
+	run.TargetBranch = branch
+	p.logger.Info("Using the repository's default branch",
+		zap.String("ticket", run.Key),
+		zap.String("target_branch", branch))
+	return nil
+}
+
+// resolveTicketTargetBranch replaces the target branch with the ticket's target branch field, if it's set
+func (p *TicketProcessorImpl) resolveTicketTargetBranch(ctx context.Context, run *TicketRun) error {
+	fieldName := p.config.Jira.TargetBranchFieldName
+	if fieldName == "" {
+		return nil
+	}
+
+	fieldID, err := p.jiraService.GetFieldIDByName(ctx, fieldName)
+	if err != nil {
+		p.logger.Warn("Failed to resolve the target branch field, using the component's target branch",
+			zap.String("ticket", run.Key),
+			zap.String("field", fieldName),
+			zap.Error(err))
+		return nil
+	}
+	fields, _, err := p.jiraService.GetTicketWithExpandedFields(ctx, run.Key)
+	if err != nil {
+		p.logger.Warn("Failed to get the ticket's target branch, using the component's target branch",
+			zap.String("ticket", run.Key),
+			zap.Error(err))
+		return nil
+	}
+
+	branch := promptFieldValue(fields[fieldID])
+	if branch == "" {
+		return nil
+	}
+	if err := models.ValidateBranchName(branch); err != nil {
+		p.logger.Error("Invalid target branch on ticket",
+			zap.String("ticket", run.Key),
+			zap.String("field", fieldName),
+			zap.Error(err))
+		p.handleFailure(ctx, run.Key, fmt.Sprintf("Invalid target branch in the %s field: %v", fieldName, err))
+		return err
+	}
+	run.TargetBranch = branch
+	p.logger.Info("Using the ticket's target branch",
+		zap.String("ticket", run.Key),
+		zap.String("target_branch", branch))
+	return nil
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+)
+
+func TestTicketProcessor_ResolveTargetBranch(t *testing.T) {
+	config := &models.Config{}
+	config.GitHub.TargetBranch = "main"
+	config.Components = map[string]models.ComponentConfig{"backend": {TargetBranch: "develop"}}
+
+	fieldValue := interface{}(nil)
+	var comments []string
+	jiraService := &mocks.MockJiraService{
+		GetFieldIDByNameFunc: func(fieldName string) (string, error) {
+			return "customfield_1", nil
+		},
+		GetTicketWithExpandedFieldsFunc: func(key string) (map[string]interface{}, map[string]string, error) {
+			return map[string]interface{}{"customfield_1": fieldValue}, nil, nil
+		},
+		AddCommentFunc: func(key, body string) error {
+			comments = append(comments, body)
+			return nil
+		},
+	}
+	githubService := &mocks.MockGitHubService{
+		GetDefaultBranchFunc: func(owner, repo string) (string, error) {
+			return "trunk", nil
+		},
+	}
+	processor := newCheckTestProcessor(t, config, &mocks.MockClaudeService{}, jiraService, githubService)
+
+	run := &TicketRun{Key: "TEST-1", Component: "backend", Owner: "example", Repo: "repo"}
+	if err := processor.resolveTargetBranch(context.Background(), run); err != nil || run.TargetBranch != "develop" {
+		t.Errorf("Expected the component's target branch, got %q (%v)", run.TargetBranch, err)
+	}
+
+	other := &TicketRun{Key: "TEST-2", Component: "frontend", Owner: "example", Repo: "repo"}
+	config.GitHub.TargetBranch = ""
+	if err := processor.resolveTargetBranch(context.Background(), other); err != nil || other.TargetBranch != "trunk" {
+		t.Errorf("Expected the repository's default branch without a target branch, got %q (%v)", other.TargetBranch, err)
+	}
+
+	config.Jira.TargetBranchFieldName = "Target Branch"
+	if err := processor.resolveTargetBranch(context.Background(), run); err != nil || run.TargetBranch != "develop" {
+		t.Errorf("Expected the component's target branch for an empty field, got %q (%v)", run.TargetBranch, err)
+	}
+
+	fieldValue = map[string]interface{}{"value": "release/2.x"}
+	if err := processor.resolveTargetBranch(context.Background(), run); err != nil || run.TargetBranch != "release/2.x" {
+		t.Errorf("Expected the ticket's target branch, got %q (%v)", run.TargetBranch, err)
+	}
+
+	fieldValue = "--upload-pack=evil"
+	if err := processor.resolveTargetBranch(context.Background(), run); err == nil {
+		t.Errorf("Expected an invalid target branch to fail the ticket, got %q", run.TargetBranch)
+	}
+	if len(comments) == 0 {
+		t.Error("Expected a failure comment on the ticket")
+	}
+}
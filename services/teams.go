@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// teamsMessage is the payload of a Teams incoming webhook, carrying an Adaptive Card
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+type teamsAttachment struct {
+	ContentType string       `json:"contentType"`
+	Content     adaptiveCard `json:"content"`
+}
+
+// adaptiveCard is an Adaptive Card of the message text with buttons opening the ticket and the pull request
+type adaptiveCard struct {
+	Schema  string               `json:"$schema"`
+	Type    string               `json:"type"`
+	Version string               `json:"version"`
+	Body    []adaptiveCardText   `json:"body"`
+	Actions []adaptiveCardAction `json:"actions,omitempty"`
+}
+
+type adaptiveCardText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+	Wrap bool   `json:"wrap"`
+}
+
+type adaptiveCardAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// TeamsSink implements the NotificationSink interface by posting Adaptive Cards to Microsoft Teams incoming webhooks
+type TeamsSink struct {
+	config *models.Config
+	client *http.Client
+	logger *zap.Logger
+}
+
+// NewTeamsSink creates a new NotificationSink posting to the Teams webhooks in the configuration
+func NewTeamsSink(config *models.Config, logger *zap.Logger) NotificationSink {
+	return &TeamsSink{
+		config: config,
+		client: &http.Client{Timeout: config.HTTPTimeout()},
+		logger: logger,
+	}
+}
+
+// Name returns the name of the sink in logs
+func (s *TeamsSink) Name() string {
+	return "teams"
+}
+
+// Send posts the notification to the webhook of its event, unless the event isn't routed to Teams
+func (s *TeamsSink) Send(ctx context.Context, notification models.Notification) error {
+	teams := s.config.Notifications.Teams
+	route, ok := teams.Route(notification.Event)
+	if !ok {
+		return nil
+	}
+
+	text, err := renderNotification(route, notification)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(newTeamsMessage(text, notification))
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	webhookURL := teams.WebhookURL
+	if route.WebhookURL != "" {
+		webhookURL = route.WebhookURL
+	}
+	if err := postWebhook(ctx, s.client, webhookURL, payload); err != nil {
+		return fmt.Errorf("failed to post to Teams: %w", err)
+	}
+	s.logger.Debug("Sent Teams notification", zap.String("event", string(notification.Event)), zap.String("ticket", notification.Ticket))
+	return nil
+}
+
+// newTeamsMessage creates the Adaptive Card message of a notification's text
+func newTeamsMessage(text string, notification models.Notification) teamsMessage {
+	card := adaptiveCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body:    []adaptiveCardText{{Type: "TextBlock", Text: text, Wrap: true}},
+	}
+	if notification.TicketURL != "" {
+		card.Actions = append(card.Actions, adaptiveCardAction{Type: "Action.OpenUrl", Title: "Open " + notification.Ticket, URL: notification.TicketURL})
+	}
+	if notification.PRURL != "" {
+		card.Actions = append(card.Actions, adaptiveCardAction{Type: "Action.OpenUrl", Title: "Open pull request", URL: notification.PRURL})
+	}
+	return teamsMessage{
+		Type:        "message",
+		Attachments: []teamsAttachment{{ContentType: "application/vnd.microsoft.card.adaptive", Content: card}},
+	}
+}
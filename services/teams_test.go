@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestTeamsSink_Send(t *testing.T) {
+	config := &models.Config{}
+	config.Notifications.Teams.WebhookURL = "https://example.webhook.office.com/default"
+	config.Notifications.Teams.Events = map[models.NotificationEvent]models.NotificationRoute{
+		models.NotificationPRCreated:       {},
+		models.NotificationFeedbackApplied: {WebhookURL: "https://example.webhook.office.com/reviews", Template: "Updated {{.PRURL}}"},
+	}
+
+	type request struct {
+		url     string
+		message teamsMessage
+	}
+	var requests []request
+	sink := NewTeamsSink(config, zap.NewNop()).(*TeamsSink)
+	sink.client = NewTestClient(func(req *http.Request) (*http.Response, error) {
+		var message teamsMessage
+		if err := json.NewDecoder(req.Body).Decode(&message); err != nil {
+			t.Fatalf("Failed to decode message: %v", err)
+		}
+		requests = append(requests, request{url: req.URL.String(), message: message})
+		return &http.Response{StatusCode: http.StatusAccepted, Body: io.NopCloser(strings.NewReader("")), Header: make(http.Header)}, nil
+	})
+
+	ctx := context.Background()
+	notifications := []models.Notification{
+		{Event: models.NotificationPRCreated, Ticket: "TEST-1", TicketURL: "https://jira/browse/TEST-1", PRURL: "https://github.com/example/repo/pull/7"},
+		{Event: models.NotificationFeedbackApplied, Ticket: "TEST-1", PRURL: "https://github.com/example/repo/pull/7"},
+		{Event: models.NotificationProcessingFailed, Ticket: "TEST-2"},
+	}
+	for _, notification := range notifications {
+		if err := sink.Send(ctx, notification); err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("Expected only the routed events to be posted, got %+v", requests)
+	}
+	created, applied := requests[0], requests[1]
+	if created.url != config.Notifications.Teams.WebhookURL {
+		t.Errorf("Expected the default webhook, got %s", created.url)
+	}
+	if len(created.message.Attachments) != 1 || created.message.Attachments[0].ContentType != "application/vnd.microsoft.card.adaptive" {
+		t.Fatalf("Expected an Adaptive Card, got %+v", created.message)
+	}
+	card := created.message.Attachments[0].Content
+	if len(card.Body) != 1 || !strings.Contains(card.Body[0].Text, "https://github.com/example/repo/pull/7") {
+		t.Errorf("Expected the default template with the PR URL, got %+v", card.Body)
+	}
+	if len(card.Actions) != 2 || card.Actions[0].URL != "https://jira/browse/TEST-1" || card.Actions[1].URL != "https://github.com/example/repo/pull/7" {
+		t.Errorf("Expected buttons opening the ticket and the PR, got %+v", card.Actions)
+	}
+	if applied.url != "https://example.webhook.office.com/reviews" {
+		t.Errorf("Expected the event's webhook, got %s", applied.url)
+	}
+	if text := applied.message.Attachments[0].Content.Body[0].Text; text != "Updated https://github.com/example/repo/pull/7" {
+		t.Errorf("Expected the event's template, got %q", text)
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -13,16 +14,30 @@ import (
 // TicketProcessor defines the interface for processing Jira tickets
 type TicketProcessor interface {
 	// ProcessTicket processes a single Jira ticket
-	ProcessTicket(ticketKey string) error
+	ProcessTicket(ctx context.Context, ticketKey string) error
+	// ProcessTicketBatch processes related tickets of an epic together, in the pull request of the first ticket
+	ProcessTicketBatch(ctx context.Context, ticketKeys []string) error
 }
 
 // TicketProcessorImpl implements the TicketProcessor interface
 type TicketProcessorImpl struct {
-	jiraService   JiraService
-	githubService GitHubService
-	aiService     AIService
-	config        *models.Config
-	logger        *zap.Logger
+	jiraService       JiraService
+	githubService     GitHubService
+	aiService         AIService
+	repoResolver      RepositoryResolver
+	reviewerAssigner  ReviewerAssigner
+	complianceChecker ComplianceChecker
+	commandRunner     CommandRunner
+	stateMachine      TicketStateMachine
+	docsBootstrap     DocsBootstrapStore
+	config            *models.Config
+	usageHistory      UsageHistory
+	botChanges        BotChangeIndex
+	sessions          SessionSummaryLog
+	notifier          Notifier
+	logger            *zap.Logger
+	ticketTimeout     time.Duration   // Bounds processing a ticket; zero means unlimited
+	processingLogs    *processingLogs // Captures the log of each run to attach it to the ticket; nil if disabled
 }
 
 // NewTicketProcessor creates a new TicketProcessor
@@ -30,54 +45,218 @@ func NewTicketProcessor(
 	jiraService JiraService,
 	githubService GitHubService,
 	aiService AIService,
+	stateMachine TicketStateMachine,
 	config *models.Config,
 	logger *zap.Logger,
 ) TicketProcessor {
+	// Capturing before the collaborators are created also captures what they log about the ticket
+	var logs *processingLogs
+	if config.ProcessingLogs.Attach {
+		logs = newProcessingLogs(config.ProcessingLogs.MaxSizeKB * 1024)
+		logger = logs.wrap(logger)
+	}
+
 	return &TicketProcessorImpl{
-		jiraService:   jiraService,
-		githubService: githubService,
-		aiService:     aiService,
-		config:        config,
-		logger:        logger,
+		jiraService:       jiraService,
+		githubService:     githubService,
+		aiService:         aiService,
+		repoResolver:      NewRepositoryResolver(jiraService, config, logger),
+		reviewerAssigner:  NewReviewerAssigner(githubService, config, logger),
+		complianceChecker: NewComplianceChecker(config, logger),
+		commandRunner:     NewCommandRunner(config, logger),
+		stateMachine:      stateMachine,
+		docsBootstrap:     NewDocsBootstrapStore(config.AI.DocsBootstrapFile),
+		config:            config,
+		usageHistory:      NewUsageHistory(config.UsageHistoryFile),
+		botChanges:        NewBotChangeIndex(config.AI.Precedents.IndexFile),
+		sessions:          NewSessionSummaryLog(config.AI.SessionSummaries.File),
+		notifier:          NewNotifier(jiraService, config, logger),
+		logger:            logger,
+		ticketTimeout:     time.Duration(config.Timeouts.TicketMinutes) * time.Minute,
+		processingLogs:    logs,
 	}
 }
 
-// ProcessTicket processes a Jira ticket
-func (p *TicketProcessorImpl) ProcessTicket(ticketKey string) error {
+// ProcessTicket processes a Jira ticket by running it through the pipeline steps configured for its component
+func (p *TicketProcessorImpl) ProcessTicket(ctx context.Context, ticketKey string) error {
+	return p.processTicket(ctx, ticketKey, nil)
+}
+
+// ProcessTicketBatch processes related tickets of an epic together: the first ticket runs through its pipeline,
+// resolving the others in the same change and pull request
+func (p *TicketProcessorImpl) ProcessTicketBatch(ctx context.Context, ticketKeys []string) error {
+	if len(ticketKeys) == 0 {
+		return nil
+	}
+	return p.processTicket(ctx, ticketKeys[0], ticketKeys[1:])
+}
+
+// processTicket runs a ticket through the pipeline steps configured for its component, together with the batched
+// tickets
+func (p *TicketProcessorImpl) processTicket(ctx context.Context, ticketKey string, batchKeys []string) (err error) {
+	run := &TicketRun{Key: ticketKey}
+	if p.processingLogs != nil {
+		p.processingLogs.start(ticketKey)
+		started, parent := time.Now(), ctx
+		defer func() {
+			// Interrupted tickets resume after a restart, so their log isn't complete yet
+			if parent.Err() != nil {
+				p.processingLogs.finish(ticketKey)
+				return
+			}
+			p.attachProcessingLog(withAuditTicket(context.WithoutCancel(parent), ticketKey), run, started, err)
+		}()
+	}
+
+	if p.config.Jira.Worklog.Enabled {
+		started, parent := time.Now(), ctx
+		defer func() {
+			// Interrupted tickets resume after a restart, and log the time of that run too
+			logWork(withAuditTicket(context.WithoutCancel(parent), ticketKey), p.jiraService, p.config, p.logger, ticketKey, started)
+		}()
+	}
+
 	p.logger.Info("Processing ticket", zap.String("ticket", ticketKey))
+	ctx = withAuditTicket(ctx, ticketKey)
+
+	defer run.cleanup()
+
+	// The ticket timeout bounds all steps together; parent tells a shutdown apart from running out of time
+	parent := ctx
+	if p.ticketTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.ticketTimeout)
+		defer cancel()
+	}
+
+	fail := func(err error) error {
+		// A canceled ticket didn't fail; it stays in its state and resumes after a restart
+		if parent.Err() != nil {
+			p.logger.Warn("Ticket processing interrupted", zap.String("ticket", ticketKey), zap.Error(err))
+			return err
+		}
+		reportCtx := ctx
+		if ctx.Err() != nil {
+			// The failure is still reported, so it must outlive the expired ticket context
+			reportCtx = context.WithoutCancel(ctx)
+			err = fmt.Errorf("exceeded the ticket timeout of %s: %w", p.ticketTimeout, err)
+			p.logger.Error("Ticket processing timed out", zap.String("ticket", ticketKey), zap.Error(err))
+			p.reportFailure(reportCtx, ticketKey, err.Error(), models.NotificationBudgetExceeded)
+		}
+		p.closeProgressComment(reportCtx, run)
+		p.failBatch(reportCtx, run, err)
+		if transitionErr := p.transition(run, models.TicketStateFailed, err); transitionErr != nil {
+			p.logger.Error("Failed to change ticket state", zap.String("ticket", ticketKey), zap.Error(transitionErr))
+		}
+		return err
+	}
+
+	state := models.TicketStateQueued
+	if err := p.stateMachine.Transition(ticketKey, state, nil); err != nil {
+		p.logger.Error("Failed to change ticket state", zap.String("ticket", ticketKey), zap.Error(err))
+		return err
+	}
+
+	// The component selects the pipeline, so the ticket is resolved before any step runs
+	if err := p.prepareTicket(ctx, run); err != nil {
+		return fail(err)
+	}
+	p.prepareBatch(ctx, run, batchKeys)
+
+	steps, err := p.pipelineSteps(run.Component)
+	if err != nil {
+		p.logger.Error("Failed to build pipeline", zap.String("ticket", ticketKey), zap.Error(err))
+		p.handleFailure(ctx, ticketKey, fmt.Sprintf("Failed to build pipeline: %v", err))
+		return fail(err)
+	}
+
+	for _, step := range steps {
+		if step.State() != state {
+			state = step.State()
+			if err := p.transition(run, state, nil); err != nil {
+				p.logger.Error("Failed to change ticket state", zap.String("ticket", ticketKey), zap.Error(err))
+				return err
+			}
+		}
+
+		p.logger.Debug("Running pipeline step", zap.String("ticket", ticketKey), zap.String("step", step.Name()))
+		p.updateProgress(ctx, run, step.Name())
+		stepStarted := time.Now()
+		if err := step.Run(ctx, run); err != nil {
+			return fail(err)
+		}
+		p.logger.Debug("Finished pipeline step",
+			zap.String("ticket", ticketKey),
+			zap.String("step", step.Name()),
+			zap.Duration("duration", time.Since(stepStarted)))
+	}
+
+	// Pipelines that don't open a PR have nothing left to follow up on
+	final := models.TicketStateDone
+	if len(run.PRs) > 0 {
+		final = models.TicketStatePROpen
+	}
+	if err := p.transition(run, final, nil); err != nil {
+		p.logger.Error("Failed to change ticket state", zap.String("ticket", ticketKey), zap.Error(err))
+		return err
+	}
+
+	p.logger.Info("Successfully processed ticket", zap.String("ticket", ticketKey))
+	return nil
+}
+
+// prepareTicket loads the ticket and resolves its component and repository
+func (p *TicketProcessorImpl) prepareTicket(ctx context.Context, run *TicketRun) error {
+	ticketKey := run.Key
 
 	// Get the ticket details
-	ticket, err := p.jiraService.GetTicket(ticketKey)
+	ticket, err := p.jiraService.GetTicket(ctx, ticketKey)
 	if err != nil {
 		p.logger.Error("Failed to get ticket details", zap.String("ticket", ticketKey), zap.Error(err))
-		p.handleFailure(ticketKey, fmt.Sprintf("Failed to get ticket details: %v", err))
+		p.handleFailure(ctx, ticketKey, fmt.Sprintf("Failed to get ticket details: %v", err))
 		return err
 	}
+	run.Ticket = ticket
+
+	// Tickets found by other means than the scan, such as resumed ones, may be of a type left to humans
+	if issueType := ticket.Fields.IssueType.Name; skipsIssueType(p.config, issueType) {
+		p.logger.Info("Skipping ticket of an issue type left to humans",
+			zap.String("ticket", ticketKey),
+			zap.String("issue_type", issueType))
+		p.handleFailure(ctx, ticketKey, fmt.Sprintf("Tickets of type %s are not processed by the AI", issueType))
+		return fmt.Errorf("issue type %s is skipped", issueType)
+	}
+	p.resolvePromptFields(ctx, run)
 
 	// Get the repository URL from the component mapping
-	if len(ticket.Fields.Components) == 0 {
+	firstComponent, repoURL, ok := p.repoResolver.Resolve(ctx, ticket)
+	if !ok && firstComponent == "" {
 		p.logger.Warn("No components found on ticket", zap.String("ticket", ticketKey))
-		p.handleFailure(ticketKey, "No components found on ticket")
+		p.handleFailure(ctx, ticketKey, "No components found on ticket")
 		return fmt.Errorf("no components found on ticket")
 	}
-
-	// Use the first component to find the repository
-	firstComponent := ticket.Fields.Components[0].Name
-	repoURL, ok := p.config.ComponentToRepo[firstComponent]
-	if !ok || repoURL == "" {
+	if !ok {
 		p.logger.Error("No repository mapping found for component",
 			zap.String("ticket", ticketKey),
 			zap.String("component", firstComponent))
-		p.handleFailure(ticketKey, fmt.Sprintf("No repository mapping found for component: %s", firstComponent))
+		p.handleFailure(ctx, ticketKey, fmt.Sprintf("No repository mapping found for component: %s", firstComponent))
 		return fmt.Errorf("no repository mapping found for component: %s", firstComponent)
 	}
+	run.Component = firstComponent
 	p.logger.Info("Found repository mapping for component",
 		zap.String("ticket", ticketKey),
 		zap.String("component", firstComponent),
 		zap.String("repo_url", repoURL))
 
+	// The label stays on the ticket, so every scan retries it; the failure is only logged, since reporting it would
+	// comment and notify again on every scan
+	if err := p.consumeTriggerLabel(ctx, ticketKey); err != nil {
+		p.logger.Warn("Failed to take up ticket, skipping it until the next scan", zap.String("ticket", ticketKey), zap.Error(err))
+		return err
+	}
+
 	// Update the ticket status to the configured "In Progress" status
-	err = p.jiraService.UpdateTicketStatus(ticketKey, p.config.Jira.StatusTransitions.InProgress)
+	err = p.jiraService.UpdateTicketStatus(ctx, ticketKey, p.config.Jira.StatusTransitions.InProgress)
 	if err != nil {
 		p.logger.Error("Failed to update ticket status",
 			zap.String("ticket", ticketKey),
@@ -92,37 +271,181 @@ func (p *TicketProcessorImpl) ProcessTicket(ticketKey string) error {
 			zap.String("ticket", ticketKey),
 			zap.String("repo_url", repoURL),
 			zap.Error(err))
-		p.handleFailure(ticketKey, fmt.Sprintf("Failed to extract repo info: %v", err))
+		p.handleFailure(ctx, ticketKey, fmt.Sprintf("Failed to extract repo info: %v", err))
 		return err
 	}
+	run.Owner, run.Repo = owner, repo
 	p.logger.Debug("Extracted repo info",
 		zap.String("ticket", ticketKey),
 		zap.String("owner", owner),
 		zap.String("repo", repo))
+	if err := p.resolveTargetBranch(ctx, run); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// cloneStep makes sure the bot's fork exists, checks it out on a new ticket branch and checks the contribution requirements
+func (p *TicketProcessorImpl) cloneStep(ctx context.Context, run *TicketRun) error {
+	ticketKey := run.Key
+	owner, repo := run.Owner, run.Repo
+
+	// In same-repo mode the bot has write access to the repository, so branches are pushed to it directly
+	run.HeadOwner = p.config.ForkOwner()
+	forkURL := fmt.Sprintf("https://github.com/%s/%s.git", owner, repo)
+	if p.config.IsSameRepo(run.Component) {
+		run.HeadOwner = owner
+		p.logger.Info("Pushing to the repository without a fork", zap.String("ticket", ticketKey))
+	} else {
+		var err error
+		if forkURL, err = p.ensureFork(ctx, ticketKey, owner, repo); err != nil {
+			return err
+		}
+		// The ticket branch starts from the fork's target branch, so bring it up to date with upstream first
+		if err := p.githubService.SyncForkWithUpstream(ctx, owner, repo, run.TargetBranch); err != nil {
+			p.logger.Warn("Failed to sync fork with upstream, the ticket branch may start from an outdated target branch",
+				zap.String("ticket", ticketKey),
+				zap.String("target_branch", run.TargetBranch),
+				zap.Error(err))
+		}
+	}
+	run.ForkURL = forkURL
+
+	run.RepoDir = strings.Join([]string{p.config.TempDir, ticketKey}, "/")
+	run.BranchName = p.branchName(run)
+	run.CloneOptions = p.config.GetCloneOptions(run.Component)
+	run.CloneOptions.Branch = run.TargetBranch
+	useWorktree := p.config.GitHub.Worktrees.Enabled
+	repoDir, branchName := run.RepoDir, run.BranchName
+
+	if useWorktree {
+		// Check the ticket branch out in a worktree of the cached clone instead of cloning again
+		err := p.githubService.CreateWorktree(ctx, forkURL, repoDir, branchName, run.TargetBranch, run.CloneOptions)
+		if err != nil {
+			p.logger.Error("Failed to create worktree",
+				zap.String("ticket", ticketKey),
+				zap.String("fork_url", forkURL),
+				zap.String("repo_dir", repoDir),
+				zap.Error(err))
+			p.handleFailure(ctx, ticketKey, fmt.Sprintf("Failed to create worktree: %v", err))
+			return err
+		}
+		run.AddCleanup(func() {
+			// The worktree is removed even when processing was canceled
+			if err := p.githubService.RemoveWorktree(context.WithoutCancel(ctx), forkURL, repoDir); err != nil {
+				p.logger.Warn("Failed to remove worktree",
+					zap.String("ticket", ticketKey),
+					zap.String("repo_dir", repoDir),
+					zap.Error(err))
+			}
+		})
+	} else {
+		// Clone the repository
+		err := p.githubService.CloneRepository(ctx, forkURL, repoDir, run.CloneOptions)
+		if err != nil {
+			p.logger.Error("Failed to clone repository",
+				zap.String("ticket", ticketKey),
+				zap.String("fork_url", forkURL),
+				zap.String("repo_dir", repoDir),
+				zap.Error(err))
+			p.handleFailure(ctx, ticketKey, fmt.Sprintf("Failed to clone repository: %v", err))
+			return err
+		}
+
+		// Switch to the target branch if we're not already on it
+		err = p.githubService.SwitchToTargetBranch(ctx, repoDir, run.TargetBranch)
+		if err != nil {
+			p.logger.Error("Failed to switch to target branch",
+				zap.String("ticket", ticketKey),
+				zap.String("repo_dir", repoDir),
+				zap.Error(err))
+			p.handleFailure(ctx, ticketKey, fmt.Sprintf("Failed to switch to target branch: %v", err))
+			return err
+		}
+	}
+
+	// Commits may be authored by a service identity or the reporter while the bot stays the committer
+	p.githubService.SetCommitAuthor(repoDir, resolveCommitAuthor(p.config, p.logger, run.Ticket, run.Component))
+	run.AddCleanup(func() {
+		p.githubService.SetCommitAuthor(repoDir, nil)
+	})
+
+	// Check CLA/DCO requirements before spending AI time on a repository we can't contribute to
+	compliance, err := p.complianceChecker.CheckCompliance(owner, repo, repoDir)
+	if err != nil {
+		p.logger.Error("Repository contribution requirements not met",
+			zap.String("ticket", ticketKey),
+			zap.String("owner", owner),
+			zap.String("repo", repo),
+			zap.Error(err))
+		p.handleFailure(ctx, ticketKey, fmt.Sprintf("Cannot contribute to repository: %v", err))
+		return err
+	}
+	run.SignOff = compliance.SignOff
+
+	// The repository's configuration can set the branch convention, so it's read before the branch is created
+	if err := p.loadRepoConfig(ctx, run); err != nil {
+		return err
+	}
+	if name := p.branchName(run); name != branchName {
+		run.BranchName, branchName = name, name
+		if useWorktree {
+			if err := p.githubService.CreateBranchFromHead(ctx, repoDir, branchName); err != nil {
+				p.logger.Error("Failed to create branch",
+					zap.String("ticket", ticketKey),
+					zap.String("repo_dir", repoDir),
+					zap.String("branch_name", branchName),
+					zap.Error(err))
+				p.handleFailure(ctx, ticketKey, fmt.Sprintf("Failed to create branch: %v", err))
+				return err
+			}
+		}
+	}
+
+	// Create a new branch; worktrees are already created on it
+	if !useWorktree {
+		err = p.githubService.CreateBranch(ctx, repoDir, branchName, run.TargetBranch)
+		if err != nil {
+			p.logger.Error("Failed to create branch",
+				zap.String("ticket", ticketKey),
+				zap.String("repo_dir", repoDir),
+				zap.String("branch_name", branchName),
+				zap.Error(err))
+			p.handleFailure(ctx, ticketKey, fmt.Sprintf("Failed to create branch: %v", err))
+			return err
+		}
+	}
 
+	p.postProgressComment(ctx, run)
+	return nil
+}
+
+// ensureFork returns the clone URL of the bot's fork of the repository, creating the fork if it doesn't exist yet
+func (p *TicketProcessorImpl) ensureFork(ctx context.Context, ticketKey, owner, repo string) (string, error) {
 	// Check if a fork already exists
-	exists, forkURL, err := p.githubService.CheckForkExists(owner, repo)
+	exists, forkURL, err := p.githubService.CheckForkExists(ctx, owner, repo)
 	if err != nil {
 		p.logger.Error("Failed to check if fork exists",
 			zap.String("ticket", ticketKey),
 			zap.String("owner", owner),
 			zap.String("repo", repo),
 			zap.Error(err))
-		p.handleFailure(ticketKey, fmt.Sprintf("Failed to check if fork exists: %v", err))
-		return err
+		p.handleFailure(ctx, ticketKey, fmt.Sprintf("Failed to check if fork exists: %v", err))
+		return "", err
 	}
 
 	if !exists {
 		// Create a fork
-		forkURL, err = p.githubService.ForkRepository(owner, repo)
+		forkURL, err = p.githubService.ForkRepository(ctx, owner, repo)
 		if err != nil {
 			p.logger.Error("Failed to create fork",
 				zap.String("ticket", ticketKey),
 				zap.String("owner", owner),
 				zap.String("repo", repo),
 				zap.Error(err))
-			p.handleFailure(ticketKey, fmt.Sprintf("Failed to create fork: %v", err))
-			return err
+			p.handleFailure(ctx, ticketKey, fmt.Sprintf("Failed to create fork: %v", err))
+			return "", err
 		}
 		p.logger.Info("Fork created successfully, waiting for fork to be ready",
 			zap.String("ticket", ticketKey),
@@ -130,7 +453,7 @@ func (p *TicketProcessorImpl) ProcessTicket(ticketKey string) error {
 
 		// Wait for the fork to be ready by checking if it exists
 		for i := 0; i < 10; i++ { // Try up to 10 times (50 seconds total)
-			exists, forkURL, err = p.githubService.CheckForkExists(owner, repo)
+			exists, forkURL, err = p.githubService.CheckForkExists(ctx, owner, repo)
 			if err != nil {
 				p.logger.Warn("Failed to check fork readiness",
 					zap.String("ticket", ticketKey),
@@ -156,117 +479,308 @@ func (p *TicketProcessorImpl) ProcessTicket(ticketKey string) error {
 		if !exists {
 			p.logger.Error("Fork failed to become ready after multiple attempts",
 				zap.String("ticket", ticketKey))
-			p.handleFailure(ticketKey, "Fork failed to become ready after multiple attempts")
-			return fmt.Errorf("fork failed to become ready after multiple attempts")
+			p.handleFailure(ctx, ticketKey, "Fork failed to become ready after multiple attempts")
+			return "", fmt.Errorf("fork failed to become ready after multiple attempts")
 		}
 	}
+	return forkURL, nil
+}
 
-	// Clone the repository
-	repoDir := strings.Join([]string{p.config.TempDir, ticketKey}, "/")
-	err = p.githubService.CloneRepository(forkURL, repoDir)
-	if err != nil {
-		p.logger.Error("Failed to clone repository",
-			zap.String("ticket", ticketKey),
-			zap.String("fork_url", forkURL),
-			zap.String("repo_dir", repoDir),
-			zap.Error(err))
-		p.handleFailure(ticketKey, fmt.Sprintf("Failed to clone repository: %v", err))
-		return err
+// forkBranchURL returns the URL of a branch in the repository the ticket's branches are pushed to
+func (p *TicketProcessorImpl) forkBranchURL(run *TicketRun, branch string) string {
+	return fmt.Sprintf("https://github.com/%s/%s/tree/%s", run.HeadOwner, run.Repo, branch)
+}
+
+// pullRequestHead returns the head of a pull request from a branch of the run, which is qualified with its owner
+// when the branch is in the bot's fork
+func pullRequestHead(run *TicketRun, branch string) string {
+	if run.HeadOwner == run.Owner {
+		return branch
 	}
+	return fmt.Sprintf("%s:%s", run.HeadOwner, branch)
+}
 
-	// Switch to the target branch if we're not already on it
-	err = p.githubService.SwitchToTargetBranch(repoDir)
-	if err != nil {
-		p.logger.Error("Failed to switch to target branch",
-			zap.String("ticket", ticketKey),
-			zap.String("repo_dir", repoDir),
-			zap.Error(err))
-		p.handleFailure(ticketKey, fmt.Sprintf("Failed to switch to target branch: %v", err))
-		return err
+// progressStepDescriptions describe the built-in pipeline steps in progress comments
+var progressStepDescriptions = map[models.PipelineStepName]string{
+	models.PipelineStepClone:        "cloning the repository",
+	models.PipelineStepGenerateDocs: "generating the repository documentation",
+	models.PipelineStepGitHistory:   "looking for related changes in the history",
+	models.PipelineStepGenerate:     "generating the changes with AI",
+	models.PipelineStepVerify:       "verifying the changes",
+	models.PipelineStepCommit:       "committing the changes",
+	models.PipelineStepPush:         "pushing the branch",
+	models.PipelineStepCreatePR:     "opening the pull request",
+}
+
+// updateProgress edits the progress comment to show the pipeline step the ticket entered, unless progress updates
+// are disabled. The notify step replaces the comment with the pull request links anyway.
+func (p *TicketProcessorImpl) updateProgress(ctx context.Context, run *TicketRun, step string) {
+	run.ProgressStep = step
+	run.ProgressStepSince = time.Now()
+	if p.config.Jira.DisableProgressUpdates || step == string(models.PipelineStepNotify) {
+		return
 	}
+	p.postProgressComment(ctx, run)
+}
 
-	// Create a new branch
-	branchName := ticketKey
-	err = p.githubService.CreateBranch(repoDir, branchName)
-	if err != nil {
-		p.logger.Error("Failed to create branch",
-			zap.String("ticket", ticketKey),
-			zap.String("repo_dir", repoDir),
-			zap.String("branch_name", branchName),
-			zap.Error(err))
-		p.handleFailure(ticketKey, fmt.Sprintf("Failed to create branch: %v", err))
-		return err
+// postProgressComment tells the ticket's watchers the AI is working on it, on which branch and in which step. The
+// comment shares its idempotency key with the pull request comment, so it is edited in place once the pull request
+// is open instead of adding one.
+func (p *TicketProcessorImpl) postProgressComment(ctx context.Context, run *TicketRun) {
+	comment := "AI is working on this ticket"
+	if run.BranchName != "" && run.Repo != "" {
+		comment += " on branch " + p.forkBranchURL(run, run.BranchName)
+	}
+	comment += ". The pull request link will appear here once it is opened."
+	if run.ProgressStep != "" {
+		description, ok := progressStepDescriptions[models.PipelineStepName(run.ProgressStep)]
+		if !ok {
+			description = "running " + run.ProgressStep
+		}
+		comment += fmt.Sprintf("\n\nCurrent step: %s (since %s UTC)", description, run.ProgressStepSince.UTC().Format("15:04"))
+	}
+	if err := upsertJiraComment(ctx, p.jiraService, p.logger, run.Key, commentKeyPRCreated, comment); err != nil {
+		p.logger.Error("Failed to add progress comment", zap.String("ticket", run.Key), zap.Error(err))
+		return
 	}
+	run.ProgressCommented = true
+}
 
-	// Generate documentation file (CLAUDE.md or GEMINI.md) if it doesn't exist
-	err = p.aiService.GenerateDocumentation(repoDir)
-	if err != nil {
-		p.logger.Warn("Failed to generate documentation",
-			zap.String("ticket", ticketKey),
-			zap.String("repo_dir", repoDir),
-			zap.Error(err))
-		// Continue processing even if documentation generation fails
+// closeProgressComment edits the progress comment of a ticket that failed before its pull request was opened, so it
+// doesn't keep promising a link
+func (p *TicketProcessorImpl) closeProgressComment(ctx context.Context, run *TicketRun) {
+	if !run.ProgressCommented || len(run.PRs) > 0 {
+		return
+	}
+	comment := fmt.Sprintf("AI stopped working on this ticket on branch %s without opening a pull request.",
+		p.forkBranchURL(run, run.BranchName))
+	if err := upsertJiraComment(ctx, p.jiraService, p.logger, run.Key, commentKeyPRCreated, comment); err != nil {
+		p.logger.Error("Failed to update progress comment", zap.String("ticket", run.Key), zap.Error(err))
+	}
+}
+
+// generateStep lets the AI change the code for the ticket, removes its hint files and runs the generate hooks
+func (p *TicketProcessorImpl) generateStep(ctx context.Context, run *TicketRun) error {
+	ticketKey, repoDir := run.Key, run.RepoDir
+
+	if err := p.runHooks(ctx, models.HookPreGenerate, run); err != nil {
+		return err
 	}
 
-	// Generate a prompt for Claude CLI
-	prompt := p.generatePrompt(ticket)
+	// Generate a prompt for Claude CLI, within the prompt's size limits
+	p.condenseComments(ctx, run)
+	prompt := p.generationPrompt(run)
 
 	// Run AI service to generate code changes
-	_, err = p.aiService.GenerateCode(prompt, repoDir)
+	var err error
+	started := time.Now()
+	run.AIResponse, err = p.aiService.GenerateCode(ctx, prompt, repoDir)
 	if err != nil {
 		p.logger.Error("Failed to generate code changes",
 			zap.String("ticket", ticketKey),
 			zap.String("repo_dir", repoDir),
 			zap.Error(err))
-		p.handleFailure(ticketKey, fmt.Sprintf("Failed to generate code changes: %v", err))
+		p.handleFailure(ctx, ticketKey, fmt.Sprintf("Failed to generate code changes: %v", err))
+		return err
+	}
+	p.recordUsage(run, prompt, time.Since(started), run.AIResponse)
+	recordSessionSummary(p.config, p.sessions, p.logger, ticketKey, models.AISessionInitial, run.AIResponse)
+
+	run.StackPlan = p.loadStackPlan(ctx, ticketKey, repoDir)
+	if p.config.Jira.FollowUps.Enabled {
+		run.FollowUps = parseFollowUps(aiResultText(run.AIResponse))
+	}
+	return p.runHooks(ctx, models.HookPostGenerate, run)
+}
+
+// generationPrompt returns the prompt the AI changes the code for the ticket with: the ticket and all the context
+// gathered for it
+func (p *TicketProcessorImpl) generationPrompt(run *TicketRun) string {
+	return generatePrompt(p.config, run.Ticket) + batchPrompt(run) + p.languageInstructionsPrompt(run) + repoInstructionsPrompt(run) +
+		p.repoMapPrompt(run) + run.GitHistory + p.precedentsPrompt(run) + sessionSummaryInstructions(p.config) +
+		shallowCloneInstructions(run.CloneOptions)
+}
+
+// recordUsage adds the AI usage of the ticket's run to the usage history cost estimates and reports are based on
+func (p *TicketProcessorImpl) recordUsage(run *TicketRun, prompt string, duration time.Duration, response interface{}) {
+	provider, inputTokens, outputTokens, costUSD, ok := aiUsage(response)
+	if !ok {
+		return
+	}
+	record := models.AIUsageRecord{
+		Ticket:          run.Key,
+		Provider:        provider,
+		PromptChars:     len(prompt),
+		InputTokens:     inputTokens,
+		OutputTokens:    outputTokens,
+		CostUSD:         costUSD,
+		Component:       run.Component,
+		DurationSeconds: duration.Seconds(),
+	}
+	if run.Ticket != nil {
+		record.Project = ticketProjectKey(run.Ticket)
+	}
+	if err := p.usageHistory.Record(record); err != nil {
+		p.logger.Warn("Failed to record AI usage", zap.String("ticket", run.Key), zap.Error(err))
+	}
+}
+
+// verifyStep reverts the AI's changes to protected paths, checks that it changed something else, retrying the AI if
+// not, within the diff size limits, formats it, and checks that the build, lint and tests pass before anything is committed
+func (p *TicketProcessorImpl) verifyStep(ctx context.Context, run *TicketRun) error {
+	if err := p.revertProtectedPaths(ctx, run); err != nil {
+		return err
+	}
+	if err := p.ensureChanges(ctx, run); err != nil {
+		return err
+	}
+	if err := p.guardDiffSize(ctx, run); err != nil {
 		return err
 	}
+	return p.runChecks(ctx, run)
+}
+
+// commitStep commits the changes on the ticket branch, or on a branch per part when the change is stacked
+func (p *TicketProcessorImpl) commitStep(ctx context.Context, run *TicketRun) error {
+	ticketKey, repoDir := run.Key, run.RepoDir
+	p.collectChangedFiles(ctx, run)
 
-	// Commit the changes
-	err = p.githubService.CommitChanges(repoDir, fmt.Sprintf("%s: %s", ticketKey, ticket.Fields.Summary))
+	var err error
+	if run.StackPlan != nil {
+		// Split a large change into dependent PRs following the AI's grouping hints
+		err = p.commitStack(ctx, run)
+	} else {
+		commitMessage := ticketTitle(run)
+		if run.SignOff {
+			commitMessage = withSignOff(commitMessage, p.config)
+		}
+		err = p.githubService.CommitChanges(ctx, repoDir, commitMessage)
+		if err == nil {
+			run.Branches = []string{run.BranchName}
+		}
+	}
 	if err != nil {
 		p.logger.Error("Failed to commit changes",
 			zap.String("ticket", ticketKey),
 			zap.String("repo_dir", repoDir),
 			zap.Error(err))
-		p.handleFailure(ticketKey, fmt.Sprintf("Failed to commit changes: %v", err))
+		p.handleFailure(ctx, ticketKey, fmt.Sprintf("Failed to commit changes: %v", err))
 		return err
 	}
+	return nil
+}
 
-	// Push the changes
-	err = p.githubService.PushChanges(repoDir, branchName)
-	if err != nil {
-		p.logger.Error("Failed to push changes",
-			zap.String("ticket", ticketKey),
-			zap.String("repo_dir", repoDir),
-			zap.String("branch_name", branchName),
-			zap.Error(err))
-		p.handleFailure(ticketKey, fmt.Sprintf("Failed to push changes: %v", err))
+// pushStep runs the pre-push hooks and pushes the committed branches to the fork
+func (p *TicketProcessorImpl) pushStep(ctx context.Context, run *TicketRun) error {
+	if err := p.runHooks(ctx, models.HookPrePush, run); err != nil {
 		return err
 	}
 
-	// Create a pull request
-	prTitle := fmt.Sprintf("%s: %s", ticketKey, ticket.Fields.Summary)
-	prBody := fmt.Sprintf("This PR addresses the issue described in %s.\n\n**Summary:** %s\n\n**Description:** %s",
-		ticketKey, ticket.Fields.Summary, ticket.Fields.Description)
+	for _, branchName := range run.Branches {
+		err := p.githubService.PushChanges(ctx, run.RepoDir, branchName)
+		if err != nil {
+			p.logger.Error("Failed to push changes",
+				zap.String("ticket", run.Key),
+				zap.String("repo_dir", run.RepoDir),
+				zap.String("branch_name", branchName),
+				zap.Error(err))
+			p.handleFailure(ctx, run.Key, fmt.Sprintf("Failed to push changes: %v", err))
+			return err
+		}
+	}
+	return nil
+}
+
+// createPRStep opens the pull requests of the pushed branches and requests reviews
+func (p *TicketProcessorImpl) createPRStep(ctx context.Context, run *TicketRun) error {
+	ticketKey := run.Key
+	owner, repo, component := run.Owner, run.Repo, run.Component
+	bodyNote := batchNote(run) + aiFailoverNote(run.AIResponse) + protectedPathsNote(run.RevertedPaths) + checksNote(p.config, run.Checks)
 
-	// When creating a pull request from a fork, the head parameter should be in the format "forkOwner:branchName"
-	head := fmt.Sprintf("%s:%s", p.config.GitHub.BotUsername, branchName)
-	pr, err := p.githubService.CreatePullRequest(owner, repo, prTitle, prBody, head, p.config.GitHub.TargetBranch)
-	if err != nil {
-		p.logger.Error("Failed to create pull request",
-			zap.String("ticket", ticketKey),
-			zap.String("owner", owner),
-			zap.String("repo", repo),
-			zap.String("head", head),
-			zap.Error(err))
-		p.handleFailure(ticketKey, fmt.Sprintf("Failed to create pull request: %v", err))
-		return err
+	if run.StackPlan != nil {
+		prs, err := p.openStackedPullRequests(ctx, run, bodyNote)
+		run.PRs = prs
+		if err != nil {
+			p.logger.Error("Failed to create stacked pull requests",
+				zap.String("ticket", ticketKey),
+				zap.Int("created", len(prs)),
+				zap.Error(err))
+			p.handleFailure(ctx, ticketKey, fmt.Sprintf("Failed to create stacked pull requests: %v", err))
+			return err
+		}
+	} else if len(run.Branches) > 0 {
+		prTitle := ticketTitle(run)
+		prBody := p.pullRequestBody(run)
+
+		head := pullRequestHead(run, run.Branches[0])
+		pr, err := p.openPullRequest(ctx, run, prTitle, prBody, head)
+		if err != nil {
+			p.logger.Error("Failed to create pull request",
+				zap.String("ticket", ticketKey),
+				zap.String("owner", owner),
+				zap.String("repo", repo),
+				zap.String("head", head),
+				zap.Error(err))
+			p.handleFailure(ctx, ticketKey, fmt.Sprintf("Failed to create pull request: %v", err))
+			return err
+		}
+		run.PRs = append(run.PRs, pr)
+	}
+	p.recordBotChange(run)
+
+	// Labels are added through the issues API, since creating a pull request ignores them
+	if labels := p.config.GetPRLabels(component); len(labels) > 0 {
+		for _, createdPR := range run.PRs {
+			if err := p.githubService.AddLabels(ctx, owner, repo, createdPR.Number, labels); err != nil {
+				p.logger.Error("Failed to add labels to pull request",
+					zap.String("ticket", ticketKey),
+					zap.String("pr_url", createdPR.HTMLURL),
+					zap.Strings("labels", labels),
+					zap.Error(err))
+				// Continue processing even if labeling fails
+			}
+		}
+	}
+	for _, createdPR := range run.PRs {
+		p.addToPlanning(ctx, run, createdPR)
+	}
+
+	for _, createdPR := range run.PRs {
+		notification := newNotification(p.config, models.NotificationPRCreated, ticketKey)
+		notification.PRURL = createdPR.HTMLURL
+		p.notifier.Notify(ctx, notification)
+	}
+
+	// Request reviews, unless the PR is a draft that reviewers shouldn't be pinged about yet
+	if !p.config.IsDraftPR(component) || p.markPullRequestsReady(ctx, run) {
+		for _, createdPR := range run.PRs {
+			err := p.reviewerAssigner.AssignReviewers(ctx, owner, repo, createdPR.Number, component, run.RepoDir)
+			if err != nil {
+				p.logger.Error("Failed to assign reviewers",
+					zap.String("ticket", ticketKey),
+					zap.String("pr_url", createdPR.HTMLURL),
+					zap.Error(err))
+				// Continue processing even if reviewer assignment fails
+			}
+		}
+	}
+
+	return nil
+}
+
+// notifyStep links the pull requests on the ticket, creates follow-up tickets and moves it to review
+func (p *TicketProcessorImpl) notifyStep(ctx context.Context, run *TicketRun) error {
+	ticketKey, prs := run.Key, run.PRs
+	if len(prs) == 0 {
+		return nil
 	}
 
+	// The bottom of the stack is merged first and tracks the ticket
+	pr := prs[0]
+
 	// Update the Git Pull Request field on the Jira ticket
 	if p.config.Jira.GitPullRequestFieldName != "" {
-		err = p.jiraService.UpdateTicketFieldByName(ticketKey, p.config.Jira.GitPullRequestFieldName, pr.HTMLURL)
+		err := p.jiraService.UpdateTicketFieldByName(ctx, ticketKey, p.config.Jira.GitPullRequestFieldName, pr.HTMLURL)
 		if err != nil {
 			p.logger.Error("Failed to update Git Pull Request field",
 				zap.String("ticket", ticketKey),
@@ -280,9 +794,26 @@ func (p *TicketProcessorImpl) ProcessTicket(ticketKey string) error {
 		}
 	}
 
-	// Add a comment to the ticket
+	// Show the pull requests in the ticket's links, next to the comment naming them
+	for _, createdPR := range prs {
+		linkPullRequest(ctx, p.jiraService, p.config, p.logger, ticketKey, createdPR.HTMLURL, createdPR.Title, false)
+	}
+
+	// Replace the progress comment with the pull request links
+	title := "Pull request opened"
 	comment := fmt.Sprintf("AI-generated pull request created: %s", pr.HTMLURL)
-	err = p.jiraService.AddComment(ticketKey, comment)
+	if len(prs) > 1 {
+		title = "Stacked pull requests opened"
+		comment = "AI-generated stacked pull requests created, to be merged in order:"
+		for _, createdPR := range prs {
+			comment += fmt.Sprintf("\n- %s", createdPR.HTMLURL)
+		}
+	}
+	if len(run.Branches) == 1 {
+		comment += fmt.Sprintf("\n\nBranch: %s", p.forkBranchURL(run, run.Branches[0]))
+	}
+	comment = successComment(p.config, title, comment, aiResultText(run.AIResponse))
+	err := upsertJiraComment(ctx, p.jiraService, p.logger, ticketKey, commentKeyPRCreated, comment)
 	if err != nil {
 		p.logger.Error("Failed to add comment",
 			zap.String("ticket", ticketKey),
@@ -291,24 +822,99 @@ func (p *TicketProcessorImpl) ProcessTicket(ticketKey string) error {
 		// Continue processing even if comment fails
 	}
 
+	p.createFollowUpTickets(ctx, run)
+
 	// Update the ticket status to the configured "In Review" status
-	err = p.jiraService.UpdateTicketStatus(ticketKey, p.config.Jira.StatusTransitions.InReview)
+	err = p.jiraService.UpdateTicketStatus(ctx, ticketKey, p.config.Jira.StatusTransitions.InReview)
 	if err != nil {
 		p.logger.Error("Failed to update ticket status",
 			zap.String("ticket", ticketKey),
 			zap.Error(err))
 		// Continue processing even if status update fails
 	}
+	p.notifyBatch(ctx, run, pr)
 
-	p.logger.Info("Successfully processed ticket", zap.String("ticket", ticketKey))
 	return nil
 }
 
+// openPullRequest opens a pull request of the run against its target branch, as a draft if configured for the
+// component
+func (p *TicketProcessorImpl) openPullRequest(ctx context.Context, run *TicketRun, title, body, head string) (*models.GitHubCreatePRResponse, error) {
+	if p.config.IsDraftPR(run.Component) {
+		// Draft PRs run CI without pinging reviewers until a human promotes them
+		return p.githubService.CreateDraftPullRequest(ctx, run.Owner, run.Repo, title, body, head, run.TargetBranch)
+	}
+	return p.githubService.CreatePullRequest(ctx, run.Owner, run.Repo, title, body, head, run.TargetBranch)
+}
+
+// markPullRequestsReady promotes the run's draft pull requests to ready for review once the build, lint and test
+// checks passed. Without checks, or if a pull request can't be promoted, they stay drafts for a human to promote.
+func (p *TicketProcessorImpl) markPullRequestsReady(ctx context.Context, run *TicketRun) bool {
+	if len(run.Checks) == 0 || len(run.PRs) == 0 {
+		return false
+	}
+	for _, pr := range run.PRs {
+		if err := p.githubService.MarkPullRequestReady(ctx, run.Owner, run.Repo, pr.Number); err != nil {
+			p.logger.Error("Failed to mark draft pull request ready for review",
+				zap.String("ticket", run.Key),
+				zap.String("pr_url", pr.HTMLURL),
+				zap.Error(err))
+			return false
+		}
+	}
+	return true
+}
+
+// addToPlanning sets the milestone of the pull request and adds it to the project board configured for the run's
+// component. Failures are only logged, since the pull request is open either way.
+func (p *TicketProcessorImpl) addToPlanning(ctx context.Context, run *TicketRun, pr *models.GitHubCreatePRResponse) {
+	planning := p.config.GetPlanning(run.Component)
+	if planning.Milestone != "" {
+		if err := p.githubService.SetMilestone(ctx, run.Owner, run.Repo, pr.Number, planning.Milestone); err != nil {
+			p.logger.Error("Failed to set milestone of pull request",
+				zap.String("ticket", run.Key),
+				zap.String("pr_url", pr.HTMLURL),
+				zap.String("milestone", planning.Milestone),
+				zap.Error(err))
+		}
+	}
+
+	project := planning.Project
+	if project.Number == 0 {
+		return
+	}
+	if err := p.githubService.AddToProject(ctx, project.Owner, project.Number, pr.NodeID, project.Field, project.Column); err != nil {
+		p.logger.Error("Failed to add pull request to project",
+			zap.String("ticket", run.Key),
+			zap.String("pr_url", pr.HTMLURL),
+			zap.String("project_owner", project.Owner),
+			zap.Int("project_number", project.Number),
+			zap.Error(err))
+	}
+}
+
 // handleFailure handles a failure in processing a ticket
-func (p *TicketProcessorImpl) handleFailure(ticketKey, errorMessage string) {
+func (p *TicketProcessorImpl) handleFailure(ctx context.Context, ticketKey, errorMessage string) {
+	p.reportFailure(ctx, ticketKey, errorMessage, models.NotificationProcessingFailed)
+}
+
+// reportFailure comments a failure on the ticket and notifies it as the event
+func (p *TicketProcessorImpl) reportFailure(ctx context.Context, ticketKey, errorMessage string, event models.NotificationEvent) {
+	// Failures caused by canceling the ticket aren't reported
+	if ctx.Err() != nil {
+		return
+	}
+	// Errors of git commands can carry the remote URL and its token
+	errorMessage = RedactSecrets(p.config, errorMessage)
+
+	notification := newNotification(p.config, event, ticketKey)
+	notification.Message = errorMessage
+	p.notifier.Notify(ctx, notification)
+
 	// Add a comment to the ticket only if error comments are not disabled
 	if !p.config.Jira.DisableErrorComments {
-		err := p.jiraService.AddComment(ticketKey, fmt.Sprintf("AI failed to process this ticket: %s", errorMessage))
+		comment := failureComment(p.config, "AI failed to process this ticket", errorMessage)
+		err := upsertJiraComment(ctx, p.jiraService, p.logger, ticketKey, commentKeyFailure, comment)
 		if err != nil {
 			p.logger.Error("Failed to add error comment", zap.String("ticket", ticketKey), zap.Error(err))
 		}
@@ -319,17 +925,23 @@ func (p *TicketProcessorImpl) handleFailure(ticketKey, errorMessage string) {
 }
 
 // generatePrompt generates a prompt for Claude CLI based on the ticket
-func (p *TicketProcessorImpl) generatePrompt(ticket *models.JiraTicketResponse) string {
+func generatePrompt(config *models.Config, ticket *models.JiraTicketResponse) string {
 	prompt := fmt.Sprintf("Please help me fix the issue described in Jira ticket %s.\n\n", ticket.Key)
 	prompt += fmt.Sprintf("Summary: %s\n\n", ticket.Fields.Summary)
 	prompt += fmt.Sprintf("Description: %s\n\n", ticket.Fields.Description)
+	for _, field := range ticket.PromptFields {
+		prompt += fmt.Sprintf("%s:\n%s\n\n", field.Name, field.Value)
+	}
 
 	// Add comments if available, filtering out bot comments
+	if ticket.CommentSummary != "" {
+		prompt += fmt.Sprintf("Summary of earlier comments:\n%s\n\n", ticket.CommentSummary)
+	}
 	if ticket.Fields.Comment.Comments != nil {
 		prompt += "Comments:\n"
 		for _, comment := range ticket.Fields.Comment.Comments {
 			// Skip comments made by our Jira bot
-			if comment.Author.Name == p.config.Jira.Username {
+			if comment.Author.Name == config.Jira.Username {
 				continue
 			}
 			prompt += fmt.Sprintf("- %s: %s\n", comment.Author.DisplayName, comment.Body)
@@ -337,8 +949,25 @@ func (p *TicketProcessorImpl) generatePrompt(ticket *models.JiraTicketResponse)
 		prompt += "\n"
 	}
 
+	prompt += issueTypePrompt(config, ticket)
 	prompt += "Please analyze the codebase and implement the necessary changes to fix this issue. " +
 		"Make sure to follow the existing code style and patterns in the codebase."
 
+	if config.GitHub.StackedPRs.Enabled {
+		prompt += stackPlanPrompt(config.GitHub.StackedPRs.MinChangedLines)
+	}
+	if config.Jira.FollowUps.Enabled {
+		prompt += followUpsPrompt()
+	}
+
 	return prompt
 }
+
+// ticketProjectKey returns the key of the ticket's project, falling back to the prefix of the ticket key
+func ticketProjectKey(ticket *models.JiraTicketResponse) string {
+	if ticket.Fields.Project.Key != "" {
+		return ticket.Fields.Project.Key
+	}
+	project, _, _ := strings.Cut(ticket.Key, "-")
+	return project
+}
@@ -1,6 +1,11 @@
 package services
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"jira-ai-issue-solver/mocks"
@@ -66,10 +71,10 @@ func TestTicketProcessor_ProcessTicket(t *testing.T) {
 	config.TempDir = "/tmp/test"
 
 	// Create ticket processor
-	processor := NewTicketProcessor(mockJiraService, mockGitHubService, mockClaudeService, config, logger)
+	processor := NewTicketProcessor(mockJiraService, mockGitHubService, mockClaudeService, newTestStateMachine(config), config, logger)
 
 	// Test processing a ticket
-	err := processor.ProcessTicket("TEST-123")
+	err := processor.ProcessTicket(context.Background(), "TEST-123")
 	if err != nil {
 		t.Errorf("Expected no error but got: %v", err)
 	}
@@ -85,6 +90,7 @@ func TestTicketProcessor_CreatePullRequestHeadFormat(t *testing.T) {
 	config.GitHub.BotEmail = "test@example.com"
 	config.GitHub.PersonalAccessToken = "test-token"
 	config.GitHub.PRLabel = "ai-pr"
+	config.GitHub.PRLabels = []string{"automated", "ai-pr"}
 	config.TempDir = "/tmp"
 	config.Jira.DisableErrorComments = true
 	config.ComponentToRepo = map[string]string{
@@ -93,8 +99,13 @@ func TestTicketProcessor_CreatePullRequestHeadFormat(t *testing.T) {
 
 	// Create mock services with captured values
 	var capturedHead, capturedCommitMessage, capturedPRTitle string
+	var capturedLabels []string
 
 	mockGitHub := &mocks.MockGitHubService{
+		AddLabelsFunc: func(owner, repo string, prNumber int, labels []string) error {
+			capturedLabels = labels
+			return nil
+		},
 		CommitChangesFunc: func(directory, message string) error {
 			capturedCommitMessage = message
 			return nil
@@ -140,10 +151,10 @@ func TestTicketProcessor_CreatePullRequestHeadFormat(t *testing.T) {
 	}
 	mockAI := &mocks.MockClaudeService{}
 
-	processor := NewTicketProcessor(mockJira, mockGitHub, mockAI, config, logger)
+	processor := NewTicketProcessor(mockJira, mockGitHub, mockAI, newTestStateMachine(config), config, logger)
 
 	// Process a ticket
-	err := processor.ProcessTicket("TEST-123")
+	err := processor.ProcessTicket(context.Background(), "TEST-123")
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
 	}
@@ -165,6 +176,60 @@ func TestTicketProcessor_CreatePullRequestHeadFormat(t *testing.T) {
 	if capturedPRTitle != expectedPRTitle {
 		t.Errorf("Expected PR title to be '%s', got '%s'", expectedPRTitle, capturedPRTitle)
 	}
+
+	// Verify that the configured labels were added once each
+	if strings.Join(capturedLabels, ",") != "ai-pr,automated" {
+		t.Errorf("Expected labels ai-pr and automated, got %q", capturedLabels)
+	}
+}
+
+func TestTicketProcessor_SameRepo(t *testing.T) {
+	config := &models.Config{}
+	config.GitHub.BotUsername = "test-bot"
+	config.TempDir = t.TempDir()
+	config.Jira.DisableErrorComments = true
+	config.ComponentToRepo = map[string]string{"frontend": "https://github.com/example/frontend.git"}
+	sameRepo := true
+	config.Components = map[string]models.ComponentConfig{"frontend": {SameRepo: &sameRepo}}
+
+	var clonedURL, head string
+	mockGitHub := &mocks.MockGitHubService{
+		CheckForkExistsFunc: func(owner, repo string) (exists bool, cloneURL string, err error) {
+			t.Error("Expected no fork lookup in same-repo mode")
+			return true, "https://github.com/test-bot/frontend.git", nil
+		},
+		CloneRepositoryFunc: func(repoURL, directory string, opts models.CloneOptions) error {
+			clonedURL = repoURL
+			return nil
+		},
+		CreatePullRequestFunc: func(owner, repo, title, body, h, base string) (*models.GitHubCreatePRResponse, error) {
+			head = h
+			return &models.GitHubCreatePRResponse{Number: 1, HTMLURL: "https://github.com/example/frontend/pull/1"}, nil
+		},
+	}
+	mockJira := &mocks.MockJiraService{
+		GetTicketFunc: func(key string) (*models.JiraTicketResponse, error) {
+			return &models.JiraTicketResponse{
+				Key: key,
+				Fields: models.JiraFields{
+					Summary:    "Fix button",
+					Components: []models.JiraComponent{{ID: "1", Name: "frontend"}},
+				},
+			}, nil
+		},
+	}
+
+	processor := NewTicketProcessor(mockJira, mockGitHub, &mocks.MockClaudeService{}, newTestStateMachine(config), config, zap.NewNop())
+	if err := processor.ProcessTicket(context.Background(), "TEST-123"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if clonedURL != "https://github.com/example/frontend.git" {
+		t.Errorf("Expected the repository itself to be cloned, got %q", clonedURL)
+	}
+	if head != "TEST-123" {
+		t.Errorf("Expected an unqualified head for a branch of the repository, got %q", head)
+	}
 }
 
 func TestTicketProcessor_ConfigurableStatusTransitions(t *testing.T) {
@@ -227,10 +292,10 @@ func TestTicketProcessor_ConfigurableStatusTransitions(t *testing.T) {
 	config.TempDir = "/tmp/test"
 
 	// Create ticket processor
-	processor := NewTicketProcessor(mockJiraService, mockGitHubService, mockClaudeService, config, zap.NewNop())
+	processor := NewTicketProcessor(mockJiraService, mockGitHubService, mockClaudeService, newTestStateMachine(config), config, zap.NewNop())
 
 	// Test processing a ticket
-	err := processor.ProcessTicket("TEST-123")
+	err := processor.ProcessTicket(context.Background(), "TEST-123")
 	if err != nil {
 		t.Errorf("Expected no error but got: %v", err)
 	}
@@ -251,3 +316,519 @@ func TestTicketProcessor_ConfigurableStatusTransitions(t *testing.T) {
 		}
 	}
 }
+
+func TestTicketProcessor_DraftPullRequestPerComponent(t *testing.T) {
+	var draftCalls, readyCalls int
+
+	mockJiraService := &mocks.MockJiraService{
+		GetTicketFunc: func(key string) (*models.JiraTicketResponse, error) {
+			return &models.JiraTicketResponse{
+				Key: key,
+				Fields: models.JiraFields{
+					Summary:    "Test ticket",
+					Components: []models.JiraComponent{{ID: "1", Name: "frontend"}},
+				},
+			}, nil
+		},
+	}
+	prResponse := &models.GitHubCreatePRResponse{Number: 1, HTMLURL: "https://github.com/example/repo/pull/1"}
+	mockGitHubService := &mocks.MockGitHubService{
+		CreatePullRequestFunc: func(owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error) {
+			readyCalls++
+			return prResponse, nil
+		},
+		CreateDraftPullRequestFunc: func(owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error) {
+			draftCalls++
+			return prResponse, nil
+		},
+		CheckForkExistsFunc: func(owner, repo string) (exists bool, cloneURL string, err error) {
+			return true, "https://github.com/mockuser/frontend.git", nil
+		},
+	}
+
+	draft := true
+	config := &models.Config{}
+	config.GitHub.BotUsername = "test-bot"
+	config.ComponentToRepo = map[string]string{
+		"frontend": "https://github.com/example/frontend.git",
+	}
+	config.Components = map[string]models.ComponentConfig{
+		"frontend": {DraftPR: &draft},
+	}
+	config.TempDir = "/tmp/test"
+
+	processor := NewTicketProcessor(mockJiraService, mockGitHubService, &mocks.MockClaudeService{}, newTestStateMachine(config), config, zap.NewNop())
+	if err := processor.ProcessTicket(context.Background(), "TEST-123"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if draftCalls != 1 || readyCalls != 0 {
+		t.Errorf("Expected a single draft PR, got %d draft and %d ready PRs", draftCalls, readyCalls)
+	}
+}
+
+func TestTicketProcessor_DraftPullRequestMarkedReady(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		build     string
+		wantReady bool
+	}{
+		{name: "checks passed", build: "true", wantReady: true},
+		{name: "no checks", wantReady: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var readyPRs []int
+			mockJiraService := &mocks.MockJiraService{
+				GetTicketFunc: func(key string) (*models.JiraTicketResponse, error) {
+					return &models.JiraTicketResponse{
+						Key: key,
+						Fields: models.JiraFields{
+							Summary:    "Test ticket",
+							Components: []models.JiraComponent{{ID: "1", Name: "frontend"}},
+						},
+					}, nil
+				},
+			}
+			mockGitHubService := &mocks.MockGitHubService{
+				CreateDraftPullRequestFunc: func(owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error) {
+					return &models.GitHubCreatePRResponse{Number: 7, HTMLURL: "https://github.com/example/frontend/pull/7"}, nil
+				},
+				MarkPullRequestReadyFunc: func(owner, repo string, prNumber int) error {
+					readyPRs = append(readyPRs, prNumber)
+					return nil
+				},
+				CheckForkExistsFunc: func(owner, repo string) (exists bool, cloneURL string, err error) {
+					return true, "https://github.com/mockuser/frontend.git", nil
+				},
+			}
+
+			config := &models.Config{}
+			config.GitHub.BotUsername = "test-bot"
+			config.GitHub.DraftPR = true
+			config.ComponentToRepo = map[string]string{
+				"frontend": "https://github.com/example/frontend.git",
+			}
+			config.Build = models.CheckCommandConfig{Command: tt.build}
+			config.TempDir = t.TempDir()
+
+			processor := NewTicketProcessor(mockJiraService, mockGitHubService, &mocks.MockClaudeService{}, newTestStateMachine(config), config, zap.NewNop())
+			if err := processor.ProcessTicket(context.Background(), "TEST-123"); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			if tt.wantReady && (len(readyPRs) != 1 || readyPRs[0] != 7) {
+				t.Errorf("Expected the draft PR to be marked ready once the checks passed, got %v", readyPRs)
+			}
+			if !tt.wantReady && len(readyPRs) != 0 {
+				t.Errorf("Expected the draft PR to stay a draft without checks, got %v", readyPRs)
+			}
+		})
+	}
+}
+
+func TestTicketProcessor_StackedPullRequests(t *testing.T) {
+	var jiraComment string
+	mockJiraService := &mocks.MockJiraService{
+		GetTicketFunc: func(key string) (*models.JiraTicketResponse, error) {
+			return &models.JiraTicketResponse{
+				Key: key,
+				Fields: models.JiraFields{
+					Summary:    "Add export feature",
+					Components: []models.JiraComponent{{ID: "1", Name: "frontend"}},
+				},
+			}, nil
+		},
+		AddCommentFunc: func(key, body string) error {
+			jiraComment = body
+			return nil
+		},
+	}
+
+	var branches []string
+	var committedFiles [][]string
+	var prHeads, prBodies []string
+	var stackComments int
+	mockGitHubService := &mocks.MockGitHubService{
+		CheckForkExistsFunc: func(owner, repo string) (exists bool, cloneURL string, err error) {
+			return true, "https://github.com/test-bot/frontend.git", nil
+		},
+		CountChangedLinesFunc: func(directory string) (int, error) {
+			return 900, nil
+		},
+		CreateBranchFromHeadFunc: func(directory, branchName string) error {
+			branches = append(branches, branchName)
+			return nil
+		},
+		CommitFilesFunc: func(directory, message string, files []string) error {
+			committedFiles = append(committedFiles, files)
+			return nil
+		},
+		CreatePullRequestFunc: func(owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error) {
+			prHeads = append(prHeads, head)
+			prBodies = append(prBodies, body)
+			number := len(prHeads)
+			return &models.GitHubCreatePRResponse{Number: number, HTMLURL: fmt.Sprintf("https://github.com/example/frontend/pull/%d", number)}, nil
+		},
+		AddPRCommentFunc: func(owner, repo string, prNumber int, body string) error {
+			stackComments++
+			return nil
+		},
+	}
+
+	var prompt string
+	mockClaudeService := &mocks.MockClaudeService{
+		GenerateCodeFunc: func(p string, repoDir string) (*models.ClaudeResponse, error) {
+			prompt = p
+			if err := os.MkdirAll(repoDir, 0755); err != nil {
+				return nil, err
+			}
+			plan := `{"parts": [{"title": "Refactor exporter", "files": ["exporter.go"]}, {"title": "Add CSV export", "files": ["csv.go"]}]}`
+			return &models.ClaudeResponse{}, os.WriteFile(filepath.Join(repoDir, stackPlanFile), []byte(plan), 0644)
+		},
+	}
+
+	config := &models.Config{}
+	config.GitHub.BotUsername = "test-bot"
+	config.GitHub.StackedPRs.Enabled = true
+	config.GitHub.StackedPRs.MinChangedLines = 400
+	config.ComponentToRepo = map[string]string{
+		"frontend": "https://github.com/example/frontend.git",
+	}
+	config.TempDir = t.TempDir()
+
+	processor := NewTicketProcessor(mockJiraService, mockGitHubService, mockClaudeService, newTestStateMachine(config), config, zap.NewNop())
+	if err := processor.ProcessTicket(context.Background(), "TEST-123"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !strings.Contains(prompt, stackPlanFile) {
+		t.Error("Expected prompt to ask for stack plan hints")
+	}
+	if _, err := os.Stat(filepath.Join(config.TempDir, "TEST-123", stackPlanFile)); !os.IsNotExist(err) {
+		t.Error("Expected stack plan file to be removed before committing")
+	}
+
+	expectedBranches := []string{"TEST-123-part1", "TEST-123-part2"}
+	if strings.Join(branches, ",") != strings.Join(expectedBranches, ",") {
+		t.Errorf("Expected branches %v, got %v", expectedBranches, branches)
+	}
+	// Only the first part is committed selectively, the last part takes the remaining changes
+	if len(committedFiles) != 1 || committedFiles[0][0] != "exporter.go" {
+		t.Errorf("Expected only the first part to be committed by file, got %v", committedFiles)
+	}
+	if len(prHeads) != 2 || prHeads[1] != "test-bot:TEST-123-part2" {
+		t.Fatalf("Expected two stacked PRs, got heads %v", prHeads)
+	}
+	if !strings.Contains(prBodies[1], "Depends on #1") {
+		t.Errorf("Expected second PR to depend on the first, got body: %s", prBodies[1])
+	}
+	if stackComments != 2 {
+		t.Errorf("Expected a stack comment on each PR, got %d", stackComments)
+	}
+	if !strings.Contains(jiraComment, "pull/1") || !strings.Contains(jiraComment, "pull/2") {
+		t.Errorf("Expected Jira comment to list both PRs, got: %s", jiraComment)
+	}
+}
+
+func TestTicketProcessor_StackedPullRequestsBelowThreshold(t *testing.T) {
+	var prCount int
+	mockJiraService := &mocks.MockJiraService{
+		GetTicketFunc: func(key string) (*models.JiraTicketResponse, error) {
+			return &models.JiraTicketResponse{
+				Key: key,
+				Fields: models.JiraFields{
+					Summary:    "Small fix",
+					Components: []models.JiraComponent{{ID: "1", Name: "frontend"}},
+				},
+			}, nil
+		},
+	}
+	mockGitHubService := &mocks.MockGitHubService{
+		CheckForkExistsFunc: func(owner, repo string) (exists bool, cloneURL string, err error) {
+			return true, "https://github.com/test-bot/frontend.git", nil
+		},
+		CountChangedLinesFunc: func(directory string) (int, error) {
+			return 50, nil
+		},
+		CreatePullRequestFunc: func(owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error) {
+			prCount++
+			return &models.GitHubCreatePRResponse{Number: 1, HTMLURL: "https://github.com/example/frontend/pull/1"}, nil
+		},
+	}
+	mockClaudeService := &mocks.MockClaudeService{
+		GenerateCodeFunc: func(p string, repoDir string) (*models.ClaudeResponse, error) {
+			if err := os.MkdirAll(repoDir, 0755); err != nil {
+				return nil, err
+			}
+			plan := `{"parts": [{"title": "One", "files": ["a.go"]}, {"title": "Two", "files": ["b.go"]}]}`
+			return &models.ClaudeResponse{}, os.WriteFile(filepath.Join(repoDir, stackPlanFile), []byte(plan), 0644)
+		},
+	}
+
+	config := &models.Config{}
+	config.GitHub.BotUsername = "test-bot"
+	config.GitHub.StackedPRs.Enabled = true
+	config.GitHub.StackedPRs.MinChangedLines = 400
+	config.ComponentToRepo = map[string]string{
+		"frontend": "https://github.com/example/frontend.git",
+	}
+	config.TempDir = t.TempDir()
+
+	processor := NewTicketProcessor(mockJiraService, mockGitHubService, mockClaudeService, newTestStateMachine(config), config, zap.NewNop())
+	if err := processor.ProcessTicket(context.Background(), "TEST-123"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if prCount != 1 {
+		t.Errorf("Expected a single PR for a small change, got %d", prCount)
+	}
+}
+
+func TestTicketProcessor_Worktrees(t *testing.T) {
+	mockJiraService := &mocks.MockJiraService{
+		GetTicketFunc: func(key string) (*models.JiraTicketResponse, error) {
+			return &models.JiraTicketResponse{
+				Key: key,
+				Fields: models.JiraFields{
+					Summary:    "Fix button",
+					Components: []models.JiraComponent{{ID: "1", Name: "frontend"}},
+				},
+			}, nil
+		},
+	}
+
+	var worktreeBranch, worktreeStart, removedDir string
+	mockGitHubService := &mocks.MockGitHubService{
+		CheckForkExistsFunc: func(owner, repo string) (exists bool, cloneURL string, err error) {
+			return true, "https://github.com/test-bot/frontend.git", nil
+		},
+		CreateWorktreeFunc: func(repoURL, directory, branch, startPoint string, opts models.CloneOptions) error {
+			worktreeBranch, worktreeStart = branch, startPoint
+			return nil
+		},
+		RemoveWorktreeFunc: func(repoURL, directory string) error {
+			removedDir = directory
+			return nil
+		},
+		CloneRepositoryFunc: func(repoURL, directory string, opts models.CloneOptions) error {
+			t.Error("Expected no clone when worktrees are enabled")
+			return nil
+		},
+		CreateBranchFunc: func(directory, branchName, baseBranch string) error {
+			t.Error("Expected worktree to be created on the ticket branch")
+			return nil
+		},
+		CreatePullRequestFunc: func(owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error) {
+			return &models.GitHubCreatePRResponse{Number: 1, HTMLURL: "https://github.com/example/frontend/pull/1"}, nil
+		},
+	}
+
+	config := &models.Config{}
+	config.GitHub.BotUsername = "test-bot"
+	config.GitHub.TargetBranch = "develop"
+	config.GitHub.Worktrees.Enabled = true
+	config.ComponentToRepo = map[string]string{
+		"frontend": "https://github.com/example/frontend.git",
+	}
+	config.TempDir = t.TempDir()
+
+	processor := NewTicketProcessor(mockJiraService, mockGitHubService, &mocks.MockClaudeService{}, newTestStateMachine(config), config, zap.NewNop())
+	if err := processor.ProcessTicket(context.Background(), "TEST-123"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if worktreeBranch != "TEST-123" || worktreeStart != "develop" {
+		t.Errorf("Expected worktree on TEST-123 starting at develop, got %s from %s", worktreeBranch, worktreeStart)
+	}
+	if removedDir != config.TempDir+"/TEST-123" {
+		t.Errorf("Expected worktree to be removed after processing, got '%s'", removedDir)
+	}
+}
+
+func TestTicketProcessor_States(t *testing.T) {
+	mockJiraService := &mocks.MockJiraService{
+		GetTicketFunc: func(key string) (*models.JiraTicketResponse, error) {
+			return &models.JiraTicketResponse{
+				Key: key,
+				Fields: models.JiraFields{
+					Summary:    "Test ticket",
+					Components: []models.JiraComponent{{ID: "1", Name: "frontend"}},
+				},
+			}, nil
+		},
+	}
+	mockGitHubService := &mocks.MockGitHubService{
+		CheckForkExistsFunc: func(owner, repo string) (exists bool, cloneURL string, err error) {
+			return true, "https://github.com/mockuser/frontend.git", nil
+		},
+		CreatePullRequestFunc: func(owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error) {
+			return &models.GitHubCreatePRResponse{Number: 1, HTMLURL: "https://github.com/example/frontend/pull/1"}, nil
+		},
+	}
+	mockClaudeService := &mocks.MockClaudeService{
+		GenerateCodeFunc: func(prompt string, repoDir string) (*models.ClaudeResponse, error) {
+			if strings.Contains(prompt, "TEST-2") {
+				return nil, fmt.Errorf("AI timed out")
+			}
+			return &models.ClaudeResponse{}, nil
+		},
+	}
+
+	config := &models.Config{}
+	config.ComponentToRepo = map[string]string{"frontend": "https://github.com/example/frontend.git"}
+	config.TempDir = t.TempDir()
+	stateMachine := newTestStateMachine(config)
+
+	var generating []string
+	stateMachine.OnEnter(models.TicketStateGenerating, func(ticketKey string, from, to models.TicketState, cause error) {
+		generating = append(generating, ticketKey)
+	})
+
+	processor := NewTicketProcessor(mockJiraService, mockGitHubService, mockClaudeService, stateMachine, config, zap.NewNop())
+	if err := processor.ProcessTicket(context.Background(), "TEST-1"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if err := processor.ProcessTicket(context.Background(), "TEST-2"); err == nil {
+		t.Fatal("Expected the AI error to be returned")
+	}
+
+	records := stateMachine.Records()
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+
+	var path []models.TicketState
+	for _, transition := range records[0].Transitions {
+		path = append(path, transition.To)
+	}
+	want := []models.TicketState{
+		models.TicketStateQueued, models.TicketStateCloning, models.TicketStateGenerating,
+		models.TicketStateVerifying, models.TicketStatePushing, models.TicketStatePROpen,
+	}
+	if fmt.Sprint(path) != fmt.Sprint(want) {
+		t.Errorf("Expected TEST-1 to pass through %v, got %v", want, path)
+	}
+
+	if records[1].State != models.TicketStateFailed || records[1].Error != "AI timed out" {
+		t.Errorf("Expected TEST-2 to fail with the AI error, got %+v", records[1])
+	}
+	if len(generating) != 2 {
+		t.Errorf("Expected the generating hook to run for both tickets, got %v", generating)
+	}
+}
+
+func TestTicketProcessor_ProgressComment(t *testing.T) {
+	githubService := &mocks.MockGitHubService{
+		CreatePullRequestFunc: func(owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error) {
+			return &models.GitHubCreatePRResponse{Number: 1, HTMLURL: "https://github.com/example/frontend/pull/1"}, nil
+		},
+	}
+
+	// Comments are kept like Jira does, so edits in place can be told from new comments
+	var comments []models.JiraComment
+	var progress string
+	jiraService := &mocks.MockJiraService{
+		GetCommentsFunc: func(key string) ([]models.JiraComment, error) {
+			return comments, nil
+		},
+		AddCommentFunc: func(key, comment string) error {
+			comments = append(comments, models.JiraComment{ID: fmt.Sprint(len(comments) + 1), Body: comment})
+			if progress == "" {
+				progress = comment
+			}
+			return nil
+		},
+		UpdateCommentFunc: func(key, commentID, comment string) error {
+			for i := range comments {
+				if comments[i].ID == commentID {
+					comments[i].Body = comment
+				}
+			}
+			return nil
+		},
+	}
+
+	processor, _, _ := newPipelineTestProcessor(t, githubService, jiraService, []models.PipelineStepConfig{
+		{Name: models.PipelineStepClone},
+		{Name: models.PipelineStepGenerate},
+		{Name: models.PipelineStepCommit},
+		{Name: models.PipelineStepPush},
+		{Name: models.PipelineStepCreatePR},
+		{Name: models.PipelineStepNotify},
+	})
+	if err := processor.ProcessTicket(context.Background(), "TEST-1"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if !strings.Contains(progress, "The pull request link will appear here") {
+		t.Errorf("Expected a progress comment right after the branch was created, got %q", progress)
+	}
+	if len(comments) != 1 {
+		t.Fatalf("Expected the progress comment to be edited in place, got %d comments", len(comments))
+	}
+	if body := comments[0].Body; !strings.Contains(body, "https://github.com/example/frontend/pull/1") || !strings.Contains(body, "/tree/TEST-1") {
+		t.Errorf("Expected the comment to link the pull request and branch, got %q", body)
+	}
+}
+
+func TestTicketProcessor_ProgressUpdates(t *testing.T) {
+	githubService := &mocks.MockGitHubService{
+		CreatePullRequestFunc: func(owner, repo, title, body, head, base string) (*models.GitHubCreatePRResponse, error) {
+			return &models.GitHubCreatePRResponse{Number: 1, HTMLURL: "https://github.com/example/frontend/pull/1"}, nil
+		},
+	}
+
+	// Every version of the single progress comment, in order
+	var versions []string
+	jiraService := &mocks.MockJiraService{}
+	jiraService.GetCommentsFunc = func(key string) ([]models.JiraComment, error) {
+		if len(versions) == 0 {
+			return nil, nil
+		}
+		return []models.JiraComment{{ID: "1", Body: versions[len(versions)-1]}}, nil
+	}
+	jiraService.AddCommentFunc = func(key, comment string) error {
+		if len(versions) > 0 {
+			t.Errorf("Expected the progress comment to be edited, got a new comment %q", comment)
+		}
+		versions = append(versions, comment)
+		return nil
+	}
+	jiraService.UpdateCommentFunc = func(key, commentID, comment string) error {
+		versions = append(versions, comment)
+		return nil
+	}
+
+	processor, _, _ := newPipelineTestProcessor(t, githubService, jiraService, []models.PipelineStepConfig{
+		{Name: models.PipelineStepClone},
+		{Name: models.PipelineStepGenerate},
+		{Name: "tests", Command: "true"},
+		{Name: models.PipelineStepCommit},
+		{Name: models.PipelineStepPush},
+		{Name: models.PipelineStepCreatePR},
+		{Name: models.PipelineStepNotify},
+	})
+	if err := processor.ProcessTicket(context.Background(), "TEST-1"); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	var steps []string
+	for _, version := range versions {
+		if _, step, ok := strings.Cut(version, "Current step: "); ok {
+			step, _, _ = strings.Cut(step, " (since")
+			if len(steps) == 0 || steps[len(steps)-1] != step {
+				steps = append(steps, step)
+			}
+		}
+	}
+	want := []string{
+		"cloning the repository", "generating the changes with AI", "running tests",
+		"committing the changes", "pushing the branch", "opening the pull request",
+	}
+	if fmt.Sprint(steps) != fmt.Sprint(want) {
+		t.Errorf("Expected the progress comment to show %v, got %v", want, steps)
+	}
+	if last := versions[len(versions)-1]; !strings.Contains(last, "https://github.com/example/frontend/pull/1") || strings.Contains(last, "Current step") {
+		t.Errorf("Expected the comment to end up linking the pull request, got %q", last)
+	}
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"sync"
+)
+
+// ticketQueue runs the queued work on a fixed number of workers, starting it in the order it was queued in, so
+// tickets are picked up in the scan's order however many are found at once
+type ticketQueue struct {
+	mu      sync.Mutex
+	ready   *sync.Cond
+	pending []queuedWork
+}
+
+// queuedWork is work waiting for a worker
+type queuedWork struct {
+	run     func()
+	dropped func() // Called instead of run when the work is dropped before it started
+}
+
+// newTicketQueue creates a queue with the number of workers, which stop once the context is done. Work still queued
+// then is dropped.
+func newTicketQueue(ctx context.Context, workers int) *ticketQueue {
+	q := &ticketQueue{}
+	q.ready = sync.NewCond(&q.mu)
+	context.AfterFunc(ctx, func() {
+		q.clear()
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		q.ready.Broadcast()
+	})
+	for i := 0; i < max(workers, 1); i++ {
+		go q.work(ctx)
+	}
+	return q
+}
+
+// push queues the work behind the work queued before. dropped is called instead of run if the queue is cleared
+// before the work started.
+func (q *ticketQueue) push(run, dropped func()) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending = append(q.pending, queuedWork{run: run, dropped: dropped})
+	q.ready.Signal()
+}
+
+// clear drops the work that didn't start yet and returns how much was dropped. Work already running isn't affected.
+func (q *ticketQueue) clear() int {
+	q.mu.Lock()
+	pending := q.pending
+	q.pending = nil
+	q.mu.Unlock()
+
+	for _, work := range pending {
+		work.dropped()
+	}
+	return len(pending)
+}
+
+// work runs the queued work in order until the context is done
+func (q *ticketQueue) work(ctx context.Context) {
+	for {
+		q.mu.Lock()
+		for len(q.pending) == 0 && ctx.Err() == nil {
+			q.ready.Wait()
+		}
+		if ctx.Err() != nil {
+			q.mu.Unlock()
+			return
+		}
+		next := q.pending[0]
+		q.pending = q.pending[1:]
+		q.mu.Unlock()
+
+		next.run()
+	}
+}
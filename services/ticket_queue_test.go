@@ -0,0 +1,64 @@
+package services
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTicketQueue_LimitsWorkers(t *testing.T) {
+	queue := newTicketQueue(t.Context(), 2)
+
+	var running, maxRunning atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		queue.push(func() {
+			defer wg.Done()
+			current := running.Add(1)
+			for {
+				previous := maxRunning.Load()
+				if current <= previous || maxRunning.CompareAndSwap(previous, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			running.Add(-1)
+		}, func() { t.Error("Expected no work to be dropped") })
+	}
+	wg.Wait()
+
+	if got := maxRunning.Load(); got != 2 {
+		t.Errorf("Expected 2 concurrent runs, got %d", got)
+	}
+}
+
+func TestTicketQueue_Clear(t *testing.T) {
+	queue := newTicketQueue(t.Context(), 1)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var ran, dropped atomic.Int32
+	queue.push(func() {
+		close(started)
+		<-release
+	}, func() { t.Error("Expected the running work not to be dropped") })
+	<-started
+	for i := 0; i < 3; i++ {
+		queue.push(func() { ran.Add(1) }, func() { dropped.Add(1) })
+	}
+
+	if got := queue.clear(); got != 3 {
+		t.Errorf("Expected 3 dropped jobs, got %d", got)
+	}
+	close(release)
+
+	// Work queued after clearing still runs
+	done := make(chan struct{})
+	queue.push(func() { close(done) }, func() {})
+	<-done
+	if ran.Load() != 0 || dropped.Load() != 3 {
+		t.Errorf("Expected the queued work to be dropped, got %d runs and %d drops", ran.Load(), dropped.Load())
+	}
+}
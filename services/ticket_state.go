@@ -0,0 +1,202 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// maxTicketStateTransitions bounds the transition history kept per ticket
+const maxTicketStateTransitions = 50
+
+// TicketStateHook is called after a ticket entered a state. cause is the error that failed the ticket, if any.
+type TicketStateHook func(ticketKey string, from, to models.TicketState, cause error)
+
+// TicketStateMachine tracks tickets through the pipeline states and persists every transition
+type TicketStateMachine interface {
+	// State returns the current state of a ticket and whether the ticket is tracked
+	State(ticketKey string) (models.TicketState, bool)
+	// Transition moves a ticket to a new state, persists it and runs the hooks of the new state.
+	// cause records why a ticket failed.
+	Transition(ticketKey string, to models.TicketState, cause error) error
+	// OnEnter registers a hook that runs whenever a ticket enters the state
+	OnEnter(state models.TicketState, hook TicketStateHook)
+	// Records returns the records of all tracked tickets, ordered by ticket key
+	Records() []models.TicketStateRecord
+	// Interrupted returns the tickets a previous run left before their pull request was opened
+	Interrupted() []string
+}
+
+// TicketStateMachineImpl implements the TicketStateMachine interface
+type TicketStateMachineImpl struct {
+	config  *models.Config
+	metrics Metrics
+	logger  *zap.Logger
+
+	mu      sync.RWMutex
+	records map[string]*models.TicketStateRecord
+	hooks   map[models.TicketState][]TicketStateHook
+}
+
+// NewTicketStateMachine creates a new TicketStateMachine and loads the persisted ticket states.
+// States are only kept in memory when no state file is configured.
+func NewTicketStateMachine(config *models.Config, metrics Metrics, logger *zap.Logger) (TicketStateMachine, error) {
+	machine := &TicketStateMachineImpl{
+		config:  config,
+		metrics: metrics,
+		logger:  logger,
+		records: make(map[string]*models.TicketStateRecord),
+		hooks:   make(map[models.TicketState][]TicketStateHook),
+	}
+
+	if config.StateFile != "" {
+		data, err := os.ReadFile(config.StateFile)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read ticket states: %w", err)
+		}
+		if err == nil {
+			if err := json.Unmarshal(data, &machine.records); err != nil {
+				return nil, fmt.Errorf("failed to parse ticket states: %w", err)
+			}
+		}
+	}
+
+	machine.updateStateGauges()
+	return machine, nil
+}
+
+// State returns the current state of a ticket and whether the ticket is tracked
+func (m *TicketStateMachineImpl) State(ticketKey string) (models.TicketState, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	record, ok := m.records[ticketKey]
+	if !ok {
+		return "", false
+	}
+	return record.State, true
+}
+
+// Transition moves a ticket to a new state, persists it and runs the hooks of the new state.
+// Untracked tickets may enter any state, so tickets processed before states were tracked can be picked up.
+func (m *TicketStateMachineImpl) Transition(ticketKey string, to models.TicketState, cause error) error {
+	if !to.IsValid() {
+		return fmt.Errorf("invalid ticket state: %s", to)
+	}
+
+	m.mu.Lock()
+	record, ok := m.records[ticketKey]
+	if !ok {
+		record = &models.TicketStateRecord{Ticket: ticketKey}
+		m.records[ticketKey] = record
+	}
+	from := record.State
+	if ok && !from.CanTransitionTo(to) {
+		m.mu.Unlock()
+		return fmt.Errorf("invalid ticket state transition from %s to %s", from, to)
+	}
+
+	now := time.Now().UTC()
+	transition := models.TicketStateTransition{From: from, To: to, At: now}
+	if cause != nil {
+		transition.Error = cause.Error()
+	}
+	record.State = to
+	record.UpdatedAt = now
+	record.Error = transition.Error
+	record.Transitions = append(record.Transitions, transition)
+	if len(record.Transitions) > maxTicketStateTransitions {
+		record.Transitions = record.Transitions[len(record.Transitions)-maxTicketStateTransitions:]
+	}
+
+	err := m.saveRecords()
+	hooks := append([]TicketStateHook(nil), m.hooks[to]...)
+	m.mu.Unlock()
+
+	if err != nil {
+		// The in-memory state stays authoritative; only resuming after a restart is affected
+		m.logger.Error("Failed to persist ticket state", zap.String("ticket", ticketKey), zap.Error(err))
+	}
+
+	m.logger.Debug("Ticket state changed",
+		zap.String("ticket", ticketKey),
+		zap.String("from", from.String()),
+		zap.String("to", to.String()))
+	m.metrics.IncCounter("ticket_state_transitions_total", map[string]string{"from": from.String(), "to": to.String()})
+	m.updateStateGauges()
+
+	for _, hook := range hooks {
+		hook(ticketKey, from, to, cause)
+	}
+	return nil
+}
+
+// OnEnter registers a hook that runs whenever a ticket enters the state
+func (m *TicketStateMachineImpl) OnEnter(state models.TicketState, hook TicketStateHook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks[state] = append(m.hooks[state], hook)
+}
+
+// Records returns the records of all tracked tickets, ordered by ticket key
+func (m *TicketStateMachineImpl) Records() []models.TicketStateRecord {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	records := make([]models.TicketStateRecord, 0, len(m.records))
+	for _, record := range m.records {
+		copied := *record
+		copied.Transitions = append([]models.TicketStateTransition(nil), record.Transitions...)
+		records = append(records, copied)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Ticket < records[j].Ticket })
+	return records
+}
+
+// Interrupted returns the tickets a previous run left before their pull request was opened
+func (m *TicketStateMachineImpl) Interrupted() []string {
+	var tickets []string
+	for _, record := range m.Records() {
+		if record.State.IsInterrupted() {
+			tickets = append(tickets, record.Ticket)
+		}
+	}
+	return tickets
+}
+
+// updateStateGauges sets the number of tickets in each state
+func (m *TicketStateMachineImpl) updateStateGauges() {
+	counts := make(map[models.TicketState]int)
+	m.mu.RLock()
+	for _, record := range m.records {
+		counts[record.State]++
+	}
+	m.mu.RUnlock()
+
+	for _, state := range models.TicketStates {
+		m.metrics.SetGauge("tickets_in_state", map[string]string{"state": state.String()}, float64(counts[state]))
+	}
+}
+
+// saveRecords persists the ticket states. The caller must hold the write lock.
+func (m *TicketStateMachineImpl) saveRecords() error {
+	if m.config.StateFile == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(m.records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal ticket states: %w", err)
+	}
+
+	if err := writeFileAtomic(m.config.StateFile, data); err != nil {
+		return fmt.Errorf("failed to save ticket states: %w", err)
+	}
+	return nil
+}
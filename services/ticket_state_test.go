@@ -0,0 +1,108 @@
+package services
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// newTestStateMachine returns a state machine that keeps ticket states in memory
+func newTestStateMachine(config *models.Config) TicketStateMachine {
+	stateMachine, err := NewTicketStateMachine(config, NewMetrics(), zap.NewNop())
+	if err != nil {
+		panic(err)
+	}
+	return stateMachine
+}
+
+func TestTicketStateMachine_Transition(t *testing.T) {
+	config := &models.Config{StateFile: filepath.Join(t.TempDir(), "ticket-states.json")}
+	metrics := NewMetrics()
+	stateMachine, err := NewTicketStateMachine(config, metrics, zap.NewNop())
+	if err != nil {
+		t.Fatalf("Failed to create state machine: %v", err)
+	}
+
+	var entered []string
+	stateMachine.OnEnter(models.TicketStateFailed, func(ticketKey string, from, to models.TicketState, cause error) {
+		entered = append(entered, ticketKey+":"+from.String()+":"+cause.Error())
+	})
+
+	for _, state := range []models.TicketState{models.TicketStateQueued, models.TicketStateCloning, models.TicketStateGenerating} {
+		if err := stateMachine.Transition("TEST-1", state, nil); err != nil {
+			t.Fatalf("Transition to %s failed: %v", state, err)
+		}
+	}
+	if err := stateMachine.Transition("TEST-1", models.TicketStateCloning, nil); err == nil {
+		t.Error("Expected moving back to the cloning state to be rejected")
+	}
+	if err := stateMachine.Transition("TEST-1", models.TicketStateFailed, errors.New("AI timed out")); err != nil {
+		t.Fatalf("Transition to failed failed: %v", err)
+	}
+	if err := stateMachine.Transition("TEST-1", models.TicketStateDone, nil); err == nil {
+		t.Error("Expected a failed ticket not to become done")
+	}
+
+	if len(entered) != 1 || entered[0] != "TEST-1:generating:AI timed out" {
+		t.Errorf("Unexpected failed state hook calls: %v", entered)
+	}
+
+	// Another state machine on the same file sees the persisted history
+	reloaded, err := NewTicketStateMachine(config, NewMetrics(), zap.NewNop())
+	if err != nil {
+		t.Fatalf("Failed to reload state machine: %v", err)
+	}
+	records := reloaded.Records()
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+	if records[0].State != models.TicketStateFailed || records[0].Error != "AI timed out" || len(records[0].Transitions) != 4 {
+		t.Errorf("Unexpected record: %+v", records[0])
+	}
+
+	// A failed ticket can be queued again
+	if err := reloaded.Transition("TEST-1", models.TicketStateQueued, nil); err != nil {
+		t.Errorf("Expected a failed ticket to be queued again, got: %v", err)
+	}
+
+	var sb strings.Builder
+	if err := metrics.WritePrometheus(&sb); err != nil {
+		t.Fatalf("Failed to write metrics: %v", err)
+	}
+	if !strings.Contains(sb.String(), `ticket_state_transitions_total{from="generating",to="failed"} 1`) ||
+		!strings.Contains(sb.String(), `tickets_in_state{state="failed"} 1`) {
+		t.Errorf("Unexpected metrics:\n%s", sb.String())
+	}
+}
+
+func TestTicketStateMachine_Interrupted(t *testing.T) {
+	stateMachine := newTestStateMachine(&models.Config{})
+
+	transitions := map[string][]models.TicketState{
+		"TEST-1": {models.TicketStateQueued, models.TicketStateCloning, models.TicketStateGenerating},
+		"TEST-2": {models.TicketStateQueued, models.TicketStateCloning, models.TicketStateGenerating, models.TicketStateVerifying, models.TicketStatePushing, models.TicketStatePROpen},
+		"TEST-3": {models.TicketStateQueued, models.TicketStateFailed},
+	}
+	for ticketKey, states := range transitions {
+		for _, state := range states {
+			if err := stateMachine.Transition(ticketKey, state, nil); err != nil {
+				t.Fatalf("Transition of %s to %s failed: %v", ticketKey, state, err)
+			}
+		}
+	}
+
+	interrupted := stateMachine.Interrupted()
+	if len(interrupted) != 1 || interrupted[0] != "TEST-1" {
+		t.Errorf("Expected only TEST-1 to be interrupted, got %v", interrupted)
+	}
+
+	// Tickets processed before states were tracked may enter any state
+	if err := stateMachine.Transition("TEST-4", models.TicketStateFeedback, nil); err != nil {
+		t.Errorf("Expected an untracked ticket to enter the feedback state, got: %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// timeoutTransport bounds every request it sends, so an unresponsive server can't hang an operation forever.
+// Wrapped by a retrying transport, each attempt gets the full timeout.
+type timeoutTransport struct {
+	base    http.RoundTripper
+	timeout time.Duration
+}
+
+// newTimeoutTransport creates a transport bounding requests by the timeout; without a timeout requests aren't bounded
+func newTimeoutTransport(base http.RoundTripper, timeout time.Duration) http.RoundTripper {
+	if timeout <= 0 {
+		return base
+	}
+	return &timeoutTransport{base: base, timeout: timeout}
+}
+
+// RoundTrip sends the request, canceling it once the timeout elapsed, including the reading of the response body
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose releases the context of a request once its response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and releases the request's context
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// gitContext bounds a git operation by the configured git timeout
+func (s *GitHubServiceImpl) gitContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.config.Timeouts.GitSeconds <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Duration(s.config.Timeouts.GitSeconds)*time.Second)
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTimeoutTransport(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := &http.Client{Transport: newTimeoutTransport(http.DefaultTransport, 200*time.Millisecond)}
+
+	t.Run("fast request", func(t *testing.T) {
+		resp, err := client.Get(server.URL + "/fast")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil || string(body) != "ok" {
+			t.Errorf("Expected body %q, got %q (%v)", "ok", body, err)
+		}
+	})
+
+	t.Run("hanging request", func(t *testing.T) {
+		start := time.Now()
+		_, err := client.Get(server.URL + "/slow")
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected a deadline exceeded error, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("Expected the request to be aborted after the timeout, took %s", elapsed)
+		}
+	})
+}
+
+func TestNewTimeoutTransport_Unbounded(t *testing.T) {
+	if transport := newTimeoutTransport(http.DefaultTransport, 0); transport != http.DefaultTransport {
+		t.Errorf("Expected requests without a timeout to use the base transport, got %T", transport)
+	}
+}
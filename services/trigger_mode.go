@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+)
+
+// triggerJQL returns the JQL condition matching the bot's tickets marked for processing: in the todo status, with the
+// trigger label, or both
+func triggerJQL(config *models.Config) string {
+	mode := config.Jira.TriggerMode
+	conditions := []string{"Contributors = currentUser()"}
+	if mode.UsesStatus() {
+		conditions = append(conditions, fmt.Sprintf(`status = "%s"`, config.Jira.StatusTransitions.Todo))
+	}
+	if mode.UsesLabel() {
+		conditions = append(conditions, fmt.Sprintf(`labels = "%s"`, config.Jira.TriggerLabel))
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+// consumeTriggerLabel removes the trigger label from a ticket taken up in the label mode, where the ticket's status
+// doesn't keep it from being found again. Adding the label back retries the ticket. A ticket whose label can't be
+// removed must not be processed, since the next scan would process it again.
+func (p *TicketProcessorImpl) consumeTriggerLabel(ctx context.Context, ticketKey string) error {
+	if p.config.Jira.TriggerMode != models.TriggerModeLabel {
+		return nil
+	}
+	if err := p.jiraService.UpdateTicketLabels(ctx, ticketKey, nil, []string{p.config.Jira.TriggerLabel}); err != nil {
+		return fmt.Errorf("failed to remove the trigger label %s: %w", p.config.Jira.TriggerLabel, err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestTriggerJQL(t *testing.T) {
+	tests := []struct {
+		mode models.TriggerMode
+		want string
+	}{
+		{mode: models.TriggerModeStatus, want: `Contributors = currentUser() AND status = "To Do"`},
+		{mode: models.TriggerModeLabel, want: `Contributors = currentUser() AND labels = "good-for-ai"`},
+		{mode: models.TriggerModeBoth, want: `Contributors = currentUser() AND status = "To Do" AND labels = "good-for-ai"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			config := &models.Config{}
+			config.Jira.StatusTransitions.Todo = "To Do"
+			config.Jira.TriggerMode = tt.mode
+			config.Jira.TriggerLabel = "good-for-ai"
+			if got := triggerJQL(config); got != tt.want {
+				t.Errorf("triggerJQL() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsumeTriggerLabel(t *testing.T) {
+	for _, mode := range []models.TriggerMode{models.TriggerModeStatus, models.TriggerModeLabel, models.TriggerModeBoth} {
+		t.Run(string(mode), func(t *testing.T) {
+			var removed []string
+			jiraService := &mocks.MockJiraService{
+				UpdateTicketLabelsFunc: func(key string, addLabels, removeLabels []string) error {
+					removed = append(removed, removeLabels...)
+					return nil
+				},
+			}
+			config := &models.Config{}
+			config.Jira.TriggerMode = mode
+			config.Jira.TriggerLabel = "good-for-ai"
+			processor := &TicketProcessorImpl{jiraService: jiraService, config: config, logger: zap.NewNop()}
+
+			if err := processor.consumeTriggerLabel(context.Background(), "TEST-1"); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			// Only the label mode relies on the label alone; otherwise leaving the todo status keeps the ticket out of scans
+			var want []string
+			if mode == models.TriggerModeLabel {
+				want = []string{"good-for-ai"}
+			}
+			if !reflect.DeepEqual(removed, want) {
+				t.Errorf("Expected %v to be removed, got %v", want, removed)
+			}
+		})
+	}
+}
+
+// TestTicketProcessor_TriggerLabelNotRemoved skips a ticket whose trigger label can't be removed, which the next scan
+// would process again, without reporting the failure on every scan
+func TestTicketProcessor_TriggerLabelNotRemoved(t *testing.T) {
+	config := &models.Config{}
+	config.TempDir = t.TempDir()
+	config.Jira.TriggerMode = models.TriggerModeLabel
+	config.Jira.TriggerLabel = "good-for-ai"
+	config.ComponentToRepo = map[string]string{"frontend": "https://github.com/example/frontend.git"}
+
+	var statuses []string
+	jiraService := &mocks.MockJiraService{
+		GetTicketFunc: func(key string) (*models.JiraTicketResponse, error) {
+			ticket := &models.JiraTicketResponse{Key: key}
+			ticket.Fields.Components = []models.JiraComponent{{Name: "frontend"}}
+			return ticket, nil
+		},
+		UpdateTicketLabelsFunc: func(key string, addLabels, removeLabels []string) error {
+			return errors.New("forbidden")
+		},
+		UpdateTicketStatusFunc: func(key string, status string) error {
+			statuses = append(statuses, status)
+			return nil
+		},
+		AddCommentFunc: func(key string, comment string) error {
+			t.Errorf("Expected the failure not to be commented, got %q", comment)
+			return nil
+		},
+	}
+	githubService := &mocks.MockGitHubService{
+		CloneRepositoryFunc: func(repoURL, directory string, opts models.CloneOptions) error {
+			t.Error("Expected the ticket not to be cloned")
+			return nil
+		},
+	}
+	processor := NewTicketProcessor(jiraService, githubService, &mocks.MockClaudeService{}, newTestStateMachine(config), config, zap.NewNop())
+
+	if err := processor.ProcessTicket(context.Background(), "TEST-1"); err == nil {
+		t.Fatal("Expected the ticket to fail")
+	}
+	if len(statuses) != 0 {
+		t.Errorf("Expected the ticket to stay in its status, got transitions to %v", statuses)
+	}
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"jira-ai-issue-solver/models"
+)
+
+// usageHistoryMutex serializes writes of all usage histories, which may share a file
+var usageHistoryMutex sync.Mutex
+
+// UsageHistory records the AI usage of processed tickets, the basis of cost estimates
+type UsageHistory interface {
+	// Record appends a record to the history
+	Record(record models.AIUsageRecord) error
+	// Load returns all records of the history in the order they were recorded
+	Load() ([]models.AIUsageRecord, error)
+}
+
+// UsageHistoryImpl implements the UsageHistory interface as an append-only file of JSON lines
+type UsageHistoryImpl struct {
+	path string
+}
+
+// NewUsageHistory creates a new UsageHistory stored in the given file. An empty path discards all records.
+func NewUsageHistory(path string) UsageHistory {
+	return &UsageHistoryImpl{path: path}
+}
+
+// Record appends a record to the history
+func (h *UsageHistoryImpl) Record(record models.AIUsageRecord) error {
+	if h.path == "" {
+		return nil
+	}
+	if record.Time.IsZero() {
+		record.Time = time.Now().UTC()
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal usage record: %w", err)
+	}
+
+	usageHistoryMutex.Lock()
+	defer usageHistoryMutex.Unlock()
+
+	file, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open usage history: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write usage history: %w", err)
+	}
+	return nil
+}
+
+// Load returns all records of the history in the order they were recorded. A missing file is an empty history.
+func (h *UsageHistoryImpl) Load() ([]models.AIUsageRecord, error) {
+	if h.path == "" {
+		return nil, nil
+	}
+	file, err := os.Open(h.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open usage history: %w", err)
+	}
+	defer file.Close()
+
+	var records []models.AIUsageRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record models.AIUsageRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("failed to parse usage history: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read usage history: %w", err)
+	}
+	return records, nil
+}
+
+// aiUsage returns the provider, token usage and cost an AI response reports, and whether it reports any
+func aiUsage(response interface{}) (provider string, inputTokens, outputTokens int, costUSD float64, ok bool) {
+	switch r := response.(type) {
+	case *AIFailoverResponse:
+		_, inputTokens, outputTokens, costUSD, ok = aiUsage(r.Response)
+		return r.Provider, inputTokens, outputTokens, costUSD, ok
+	case *models.ClaudeResponse:
+		if r != nil {
+			// Cached input is still input the run consumed, billed at a lower rate included in the cost
+			input := r.Usage.InputTokens + r.Usage.CacheCreationInputTokens + r.Usage.CacheReadInputTokens
+			return "claude", input, r.Usage.OutputTokens, r.TotalCostUsd, true
+		}
+	case *models.GeminiResponse:
+		if r != nil {
+			return "gemini", r.Usage.InputTokens, r.Usage.OutputTokens, r.TotalCostUsd, true
+		}
+	}
+	return "", 0, 0, 0, false
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func TestUsageHistory_RecordAndLoad(t *testing.T) {
+	history := NewUsageHistory(filepath.Join(t.TempDir(), "ai-usage.log"))
+
+	// A history that was never written is empty
+	records, err := history.Load()
+	if err != nil || len(records) != 0 {
+		t.Fatalf("Expected an empty history, got %v (%v)", records, err)
+	}
+
+	for _, ticket := range []string{"TEST-1", "TEST-2"} {
+		if err := history.Record(models.AIUsageRecord{Ticket: ticket, InputTokens: 1000, CostUSD: 0.5}); err != nil {
+			t.Fatalf("Expected no error but got: %v", err)
+		}
+	}
+
+	records, err = history.Load()
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(records) != 2 || records[0].Ticket != "TEST-1" || records[1].Ticket != "TEST-2" {
+		t.Fatalf("Expected the records in order, got %+v", records)
+	}
+	if records[0].Time.IsZero() || records[0].InputTokens != 1000 || records[0].CostUSD != 0.5 {
+		t.Errorf("Expected a timestamped record with its usage, got %+v", records[0])
+	}
+
+	// Without a file the history is disabled
+	if err := NewUsageHistory("").Record(models.AIUsageRecord{Ticket: "TEST-1"}); err != nil {
+		t.Errorf("Expected a disabled history to discard records, got %v", err)
+	}
+}
+
+func TestAIUsage(t *testing.T) {
+	claude := &models.ClaudeResponse{TotalCostUsd: 1.25}
+	claude.Usage.InputTokens = 100
+	claude.Usage.CacheReadInputTokens = 900
+	claude.Usage.OutputTokens = 50
+
+	tests := []struct {
+		name         string
+		response     interface{}
+		wantProvider string
+		wantInput    int
+		wantOutput   int
+		wantCost     float64
+		wantOK       bool
+	}{
+		{"claude counts cached input", claude, "claude", 1000, 50, 1.25, true},
+		{"gemini", &models.GeminiResponse{TotalCostUsd: 0.5, Usage: models.GeminiUsage{InputTokens: 200, OutputTokens: 20}}, "gemini", 200, 20, 0.5, true},
+		{"fallback provider", &AIFailoverResponse{Provider: "gemini-fallback", Response: claude}, "gemini-fallback", 1000, 50, 1.25, true},
+		{"unknown response", "text", "", 0, 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, input, output, cost, ok := aiUsage(tt.response)
+			if provider != tt.wantProvider || input != tt.wantInput || output != tt.wantOutput || cost != tt.wantCost || ok != tt.wantOK {
+				t.Errorf("Expected (%s, %d, %d, %g, %v), got (%s, %d, %d, %g, %v)",
+					tt.wantProvider, tt.wantInput, tt.wantOutput, tt.wantCost, tt.wantOK, provider, input, output, cost, ok)
+			}
+		})
+	}
+}
+
+func TestTicketProcessor_RecordUsage(t *testing.T) {
+	history := NewUsageHistory(filepath.Join(t.TempDir(), "ai-usage.log"))
+	processor := &TicketProcessorImpl{usageHistory: history, logger: zap.NewNop()}
+
+	response := &models.ClaudeResponse{TotalCostUsd: 0.75}
+	response.Usage.InputTokens = 3000
+	ticket := &models.JiraTicketResponse{Key: "TEST-1"}
+	ticket.Fields.Project.Key = "TEST"
+	processor.recordUsage(&TicketRun{Key: "TEST-1", Ticket: ticket, Component: "backend"}, "Fix the bug", 90*time.Second, response)
+	processor.recordUsage(&TicketRun{Key: "TEST-2"}, "Fix the bug", time.Minute, nil)
+
+	records, err := history.Load()
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("Expected only the run reporting usage to be recorded, got %+v", records)
+	}
+	if records[0].Ticket != "TEST-1" || records[0].Provider != "claude" || records[0].PromptChars != len("Fix the bug") || records[0].InputTokens != 3000 {
+		t.Errorf("Expected the usage of the run, got %+v", records[0])
+	}
+	if records[0].Project != "TEST" || records[0].Component != "backend" || records[0].DurationSeconds != 90 {
+		t.Errorf("Expected the project, component and duration of the run, got %+v", records[0])
+	}
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// VaultService reads secrets from HashiCorp Vault
+type VaultService interface {
+	// ReadSecret returns the value of a key of the secret at the path, e.g. secret/data/ai for a KV version 2 engine
+	// mounted at secret
+	ReadSecret(ctx context.Context, path, key string) (string, error)
+}
+
+// VaultServiceImpl implements the VaultService interface using the Vault HTTP API and a Vault token
+type VaultServiceImpl struct {
+	config *models.Config
+	client *http.Client
+	logger *zap.Logger
+}
+
+// NewVaultService creates a new VaultService for the Vault in the configuration
+func NewVaultService(config *models.Config, logger *zap.Logger) VaultService {
+	return &VaultServiceImpl{
+		config: config,
+		client: &http.Client{Timeout: config.HTTPTimeout()},
+		logger: logger,
+	}
+}
+
+// vaultSecretResponse is the response of reading a secret; KV version 2 engines nest the secret in data.data
+type vaultSecretResponse struct {
+	Data map[string]interface{} `json:"data"`
+}
+
+// ReadSecret returns the value of a key of the secret at the path, e.g. secret/data/ai for a KV version 2 engine
+// mounted at secret
+func (s *VaultServiceImpl) ReadSecret(ctx context.Context, path, key string) (string, error) {
+	url := strings.TrimSuffix(s.config.Vault.Address, "/") + "/v1/" + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", s.config.Vault.Token)
+	if s.config.Vault.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", s.config.Vault.Namespace)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed to read secret %s: status %d, body: %s", path, resp.StatusCode, string(body))
+	}
+
+	var secret vaultSecretResponse
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return "", fmt.Errorf("failed to decode secret %s: %w", path, err)
+	}
+
+	data := secret.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, found := data[key]; !found {
+			data = nested
+		}
+	}
+	value, ok := data[key].(string)
+	if !ok {
+		return "", fmt.Errorf("secret %s has no string key %s", path, key)
+	}
+	return value, nil
+}
+
+// ResolveSecrets replaces the secret values referencing Vault with the secrets read from it
+func ResolveSecrets(ctx context.Context, config *models.Config, logger *zap.Logger) error {
+	return resolveSecrets(ctx, config, NewVaultService(config, logger), logger)
+}
+
+// resolveSecrets replaces the secret values referencing Vault with the secrets the Vault service reads
+func resolveSecrets(ctx context.Context, config *models.Config, vault VaultService, logger *zap.Logger) error {
+	for _, setting := range config.SecretSettings() {
+		path, key, ok := models.ParseVaultReference(*setting.Value)
+		if !ok {
+			continue
+		}
+		value, err := vault.ReadSecret(ctx, path, key)
+		if err != nil {
+			return fmt.Errorf("failed to read %s from Vault: %w", setting.Path, err)
+		}
+		*setting.Value = value
+		logger.Info("Read secret from Vault", zap.String("setting", setting.Path), zap.String("path", path))
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+func newTestVaultService(t *testing.T, body string, statusCode int) *VaultServiceImpl {
+	t.Helper()
+	config := &models.Config{}
+	config.Vault.Address = "https://vault.example.com/"
+	config.Vault.Token = "vault-token"
+	config.Vault.Namespace = "team"
+
+	service := NewVaultService(config, zap.NewNop()).(*VaultServiceImpl)
+	service.client = NewTestClient(func(req *http.Request) (*http.Response, error) {
+		if req.URL.String() != "https://vault.example.com/v1/secret/data/ai" {
+			t.Errorf("Unexpected URL: %s", req.URL)
+		}
+		if req.Header.Get("X-Vault-Token") != "vault-token" || req.Header.Get("X-Vault-Namespace") != "team" {
+			t.Errorf("Unexpected headers: %v", req.Header)
+		}
+		return &http.Response{StatusCode: statusCode, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	})
+	return service
+}
+
+func TestVaultService_ReadSecret(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		statusCode int
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "KV version 2",
+			body:       `{"data":{"data":{"jira_token":"secret"},"metadata":{"version":3}}}`,
+			statusCode: http.StatusOK,
+			want:       "secret",
+		},
+		{
+			name:       "KV version 1",
+			body:       `{"data":{"jira_token":"secret"}}`,
+			statusCode: http.StatusOK,
+			want:       "secret",
+		},
+		{
+			name:       "missing key",
+			body:       `{"data":{"data":{"other":"secret"}}}`,
+			statusCode: http.StatusOK,
+			wantErr:    true,
+		},
+		{
+			name:       "permission denied",
+			body:       `{"errors":["permission denied"]}`,
+			statusCode: http.StatusForbidden,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestVaultService(t, tt.body, tt.statusCode)
+			got, err := service.ReadSecret(context.Background(), "secret/data/ai", "jira_token")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadSecret() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ReadSecret() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeVault returns the secrets keyed by path#key
+type fakeVault map[string]string
+
+func (f fakeVault) ReadSecret(ctx context.Context, path, key string) (string, error) {
+	value, ok := f[path+"#"+key]
+	if !ok {
+		return "", io.EOF
+	}
+	return value, nil
+}
+
+func TestResolveSecrets(t *testing.T) {
+	config := &models.Config{}
+	config.Jira.APIToken = "vault:secret/data/ai#jira_token"
+	config.GitHub.PersonalAccessToken = "inline-token"
+	config.GitHub.PrivateKey = "vault:secret/data/github#private_key"
+
+	vault := fakeVault{"secret/data/ai#jira_token": "jira-secret", "secret/data/github#private_key": "pem"}
+	if err := resolveSecrets(context.Background(), config, vault, zap.NewNop()); err != nil {
+		t.Fatalf("resolveSecrets() error = %v", err)
+	}
+	if config.Jira.APIToken != "jira-secret" || config.GitHub.PrivateKey != "pem" {
+		t.Errorf("Expected the references to be resolved, got %q and %q", config.Jira.APIToken, config.GitHub.PrivateKey)
+	}
+	if config.GitHub.PersonalAccessToken != "inline-token" {
+		t.Errorf("Expected the inline token to be kept, got %q", config.GitHub.PersonalAccessToken)
+	}
+
+	config.Server.AdminToken = "vault:secret/data/missing#token"
+	err := resolveSecrets(context.Background(), config, vault, zap.NewNop())
+	if err == nil || !strings.Contains(err.Error(), "server.admin_token") {
+		t.Errorf("resolveSecrets() error = %v, want an error naming server.admin_token", err)
+	}
+}
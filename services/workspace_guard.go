@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// maxReportedWorkspaceViolations bounds how many changed paths are listed in a violation error
+const maxReportedWorkspaceViolations = 10
+
+// defaultIgnoredHomePaths are paths under the home directory that AI CLIs and the builds they run change legitimately
+var defaultIgnoredHomePaths = []string{
+	".claude",
+	".claude.json",
+	".claude.json.backup",
+	".gemini",
+	".cache",
+	".npm",
+	".local/share",
+	"go",
+}
+
+// workspaceFileState is what the workspace guard records about a file to detect changes
+type workspaceFileState struct {
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+// workspaceManifest maps the paths under the guarded directories to their state
+type workspaceManifest map[string]workspaceFileState
+
+// WorkspaceGuardAIServiceImpl implements AIService by running another AIService and failing runs that
+// modified files outside the repository checkout, as a defense in depth against escaping the workspace
+type WorkspaceGuardAIServiceImpl struct {
+	service      AIService
+	guardedPaths []string
+	ignoredPaths []string
+	auditLog     AuditLog
+	metrics      Metrics
+	logger       *zap.Logger
+}
+
+// NewWorkspaceGuardAIService creates an AIService that guards the files outside the repository checkout of service runs
+func NewWorkspaceGuardAIService(service AIService, config *models.Config, metrics Metrics, logger *zap.Logger) AIService {
+	home, _ := os.UserHomeDir()
+	workDir, _ := os.Getwd()
+
+	guardedPaths := config.WorkspaceGuard.GuardedPaths
+	if len(guardedPaths) == 0 {
+		guardedPaths = []string{home, workDir}
+	}
+
+	// The bot's own files and the checkouts of other tickets change while the AI runs
+	ignoredPaths := append([]string{
+		config.TempDir,
+		config.GitHub.Worktrees.CacheDir,
+		config.StateFile,
+		config.AuditLog,
+		config.FeedbackWatermarkFile,
+		config.AI.DocsBootstrapFile,
+		config.AILogs.Dir,
+		config.Jira.Connect.InstallationsFile,
+	}, config.WorkspaceGuard.IgnoredPaths...)
+	if home != "" {
+		for _, path := range defaultIgnoredHomePaths {
+			ignoredPaths = append(ignoredPaths, filepath.Join(home, path))
+		}
+	}
+
+	return &WorkspaceGuardAIServiceImpl{
+		service:      service,
+		guardedPaths: absolutePaths(guardedPaths),
+		ignoredPaths: absolutePaths(ignoredPaths),
+		auditLog:     NewAuditLog(config.AuditLog),
+		metrics:      metrics,
+		logger:       logger,
+	}
+}
+
+// GenerateCode generates code, failing if the AI modified files outside the repository checkout
+func (s *WorkspaceGuardAIServiceImpl) GenerateCode(ctx context.Context, prompt string, repoDir string) (interface{}, error) {
+	var response interface{}
+	err := s.guard(repoDir, func() error {
+		var err error
+		response, err = s.service.GenerateCode(ctx, prompt, repoDir)
+		return err
+	})
+	return response, err
+}
+
+// GenerateDocumentation generates documentation, failing if the AI modified files outside the repository checkout
+func (s *WorkspaceGuardAIServiceImpl) GenerateDocumentation(ctx context.Context, repoDir string) error {
+	return s.guard(repoDir, func() error {
+		return s.service.GenerateDocumentation(ctx, repoDir)
+	})
+}
+
+// guard runs the AI and compares the guarded directories before and after the run
+func (s *WorkspaceGuardAIServiceImpl) guard(repoDir string, run func() error) error {
+	ignored := s.ignoredPaths
+	if absRepoDir, err := filepath.Abs(repoDir); err == nil {
+		ignored = append([]string{absRepoDir}, ignored...)
+	}
+
+	before := s.snapshot(ignored)
+	runErr := run()
+	changes := diffWorkspaceManifests(before, s.snapshot(ignored))
+	if len(changes) == 0 {
+		return runErr
+	}
+
+	reported := changes
+	if len(reported) > maxReportedWorkspaceViolations {
+		reported = append(reported[:maxReportedWorkspaceViolations:maxReportedWorkspaceViolations],
+			fmt.Sprintf("and %d more", len(changes)-maxReportedWorkspaceViolations))
+	}
+	err := fmt.Errorf("AI modified files outside the repository checkout %s: %s", repoDir, strings.Join(reported, ", "))
+
+	s.logger.Error("AI escaped the repository checkout",
+		zap.String("repo_dir", repoDir),
+		zap.Strings("changes", reported))
+	s.metrics.IncCounter("workspace_guard_violations_total", nil)
+	if auditErr := s.auditLog.Record(models.AuditEntry{
+		Action: "workspace_violation",
+		Name:   repoDir,
+		Error:  err.Error(),
+	}); auditErr != nil {
+		s.logger.Warn("Failed to record workspace violation in audit log", zap.String("repo_dir", repoDir), zap.Error(auditErr))
+	}
+	return err
+}
+
+// snapshot records the state of all files under the guarded directories, except for ignored paths
+func (s *WorkspaceGuardAIServiceImpl) snapshot(ignored []string) workspaceManifest {
+	manifest := workspaceManifest{}
+	for _, root := range s.guardedPaths {
+		// Unreadable entries can't be compared, so they're skipped rather than failing the run
+		_ = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if isIgnoredPath(path, ignored) {
+				if entry != nil && entry.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if err != nil {
+				return nil
+			}
+			if _, seen := manifest[path]; seen {
+				// Guarded directories may overlap
+				return nil
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return nil
+			}
+			state := workspaceFileState{mode: info.Mode()}
+			if !entry.IsDir() {
+				state.size = info.Size()
+				state.modTime = info.ModTime()
+			}
+			manifest[path] = state
+			return nil
+		})
+	}
+	return manifest
+}
+
+// diffWorkspaceManifests lists the paths created, deleted or modified between two snapshots
+func diffWorkspaceManifests(before, after workspaceManifest) []string {
+	var changes []string
+	for path, state := range after {
+		previous, existed := before[path]
+		switch {
+		case !existed:
+			changes = append(changes, path+" (created)")
+		case previous != state:
+			changes = append(changes, path+" (modified)")
+		}
+	}
+	for path := range before {
+		if _, exists := after[path]; !exists {
+			changes = append(changes, path+" (deleted)")
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
+
+// isIgnoredPath checks if path is one of the ignored paths or lies under one
+func isIgnoredPath(path string, ignored []string) bool {
+	for _, ignoredPath := range ignored {
+		if path == ignoredPath || strings.HasPrefix(path, ignoredPath+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// absolutePaths returns the non-empty paths made absolute
+func absolutePaths(paths []string) []string {
+	var absolute []string
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if abs, err := filepath.Abs(path); err == nil {
+			absolute = append(absolute, abs)
+		}
+	}
+	return absolute
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/mocks"
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// newTestWorkspaceGuard guards a temporary directory holding the repository checkout and a cache the AI may change
+func newTestWorkspaceGuard(t *testing.T, generate func(repoDir string) error) (AIService, string, string, Metrics) {
+	guarded := t.TempDir()
+	repoDir := filepath.Join(guarded, "TEST-1")
+	cacheDir := filepath.Join(guarded, "cache")
+	for _, dir := range []string{repoDir, cacheDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(guarded, "settings.txt"), []byte("original"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	config := &models.Config{}
+	config.WorkspaceGuard.GuardedPaths = []string{guarded}
+	config.WorkspaceGuard.IgnoredPaths = []string{cacheDir}
+	metrics := NewMetrics()
+
+	service := NewWorkspaceGuardAIService(&mocks.MockClaudeService{
+		GenerateCodeFunc: func(prompt, repoDir string) (*models.ClaudeResponse, error) {
+			return &models.ClaudeResponse{Result: "done"}, generate(repoDir)
+		},
+	}, config, metrics, zap.NewNop())
+	return service, guarded, repoDir, metrics
+}
+
+func TestWorkspaceGuard_ChangesInsideWorkspace(t *testing.T) {
+	service, guarded, repoDir, _ := newTestWorkspaceGuard(t, func(repoDir string) error {
+		if err := os.WriteFile(filepath.Join(repoDir, "main.go"), []byte("package main"), 0644); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(filepath.Dir(repoDir), "cache", "build.bin"), []byte("cached"), 0644)
+	})
+
+	response, err := service.GenerateCode(context.Background(), "fix it", repoDir)
+	if err != nil {
+		t.Fatalf("Expected changes in the checkout and ignored paths to be allowed, got %v", err)
+	}
+	if response.(*models.ClaudeResponse).Result != "done" {
+		t.Errorf("Expected the AI response to be returned, got %+v", response)
+	}
+	if _, err := os.Stat(filepath.Join(guarded, "TEST-1", "main.go")); err != nil {
+		t.Errorf("Expected the AI to run: %v", err)
+	}
+}
+
+func TestWorkspaceGuard_ChangesOutsideWorkspace(t *testing.T) {
+	tests := []struct {
+		name     string
+		escape   func(guarded string) error
+		wantPath string
+	}{
+		{
+			name: "created",
+			escape: func(guarded string) error {
+				return os.WriteFile(filepath.Join(guarded, "payload.sh"), []byte("#!/bin/sh"), 0755)
+			},
+			wantPath: "payload.sh (created)",
+		},
+		{
+			name: "modified",
+			escape: func(guarded string) error {
+				return os.WriteFile(filepath.Join(guarded, "settings.txt"), []byte("tampered"), 0644)
+			},
+			wantPath: "settings.txt (modified)",
+		},
+		{
+			name:     "deleted",
+			escape:   func(guarded string) error { return os.Remove(filepath.Join(guarded, "settings.txt")) },
+			wantPath: "settings.txt (deleted)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _, repoDir, metrics := newTestWorkspaceGuard(t, func(repoDir string) error {
+				return tt.escape(filepath.Dir(repoDir))
+			})
+
+			_, err := service.GenerateCode(context.Background(), "fix it", repoDir)
+			if err == nil || !strings.Contains(err.Error(), tt.wantPath) {
+				t.Fatalf("Expected an error reporting %q, got %v", tt.wantPath, err)
+			}
+
+			var output bytes.Buffer
+			if err := metrics.WritePrometheus(&output); err != nil {
+				t.Fatalf("Failed to write metrics: %v", err)
+			}
+			if !strings.Contains(output.String(), "workspace_guard_violations_total 1") {
+				t.Errorf("Expected the violation to be counted, got:\n%s", output.String())
+			}
+		})
+	}
+}
+
+func TestDiffWorkspaceManifests(t *testing.T) {
+	before := workspaceManifest{"/a": {size: 1}, "/b": {size: 2}}
+	after := workspaceManifest{"/a": {size: 1}, "/c": {size: 3}}
+
+	changes := diffWorkspaceManifests(before, after)
+	if strings.Join(changes, ", ") != "/b (deleted), /c (created)" {
+		t.Errorf("Unexpected changes: %v", changes)
+	}
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"jira-ai-issue-solver/models"
+
+	"go.uber.org/zap"
+)
+
+// worktreeCacheDir returns the directory of the cached clone of a repository
+func (s *GitHubServiceImpl) worktreeCacheDir(owner, repo string) string {
+	cacheDir := s.config.GitHub.Worktrees.CacheDir
+	if cacheDir == "" {
+		cacheDir = filepath.Join(s.config.TempDir, "repo-cache")
+	}
+	return filepath.Join(cacheDir, owner, repo)
+}
+
+// lockRepo serializes git operations on a cached clone. Worktrees share the object store and
+// refs of their cached clone, so concurrent tickets on the same repository must not fetch into
+// it or add and remove worktrees at the same time.
+func (s *GitHubServiceImpl) lockRepo(cacheDir string) func() {
+	lock, _ := s.repoLocks.LoadOrStore(cacheDir, &sync.Mutex{})
+	mu := lock.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+// CreateWorktree checks out branch, starting at origin/startPoint, in a worktree of the cached clone of repoURL.
+// The cached clone is created on first use and only fetches the start point afterwards, so tickets on
+// the same repository don't pay for a full clone each time.
+func (s *GitHubServiceImpl) CreateWorktree(ctx context.Context, repoURL, directory, branch, startPoint string, opts models.CloneOptions) error {
+	ctx, cancel := s.gitContext(ctx)
+	defer cancel()
+
+	owner, repo, err := ExtractRepoInfo(repoURL)
+	if err != nil {
+		return fmt.Errorf("failed to extract repo info: %w", err)
+	}
+
+	cacheDir := s.worktreeCacheDir(owner, repo)
+	unlock := s.lockRepo(cacheDir)
+	defer unlock()
+
+	if err := s.ensureCachedClone(ctx, repoURL, cacheDir, opts); err != nil {
+		return err
+	}
+
+	// Drop worktrees left behind by earlier runs, including one at the target directory
+	if err := s.runGit(ctx, cacheDir, "worktree", "prune"); err != nil {
+		return fmt.Errorf("failed to prune worktrees: %w", err)
+	}
+	if _, err := os.Stat(directory); err == nil {
+		if err := s.runGit(ctx, cacheDir, "worktree", "remove", "--force", directory); err != nil {
+			s.logger.Debug("Directory is not a worktree, removing it", zap.String("directory", directory))
+		}
+		if err := os.RemoveAll(directory); err != nil {
+			return fmt.Errorf("failed to remove existing directory: %w", err)
+		}
+	}
+
+	fetchArgs := []string{"fetch", "origin"}
+	if opts.Depth > 0 {
+		fetchArgs = append(fetchArgs, "--depth", strconv.Itoa(opts.Depth))
+	}
+	fetchArgs = append(fetchArgs, fmt.Sprintf("+refs/heads/%s:refs/remotes/origin/%s", startPoint, startPoint))
+	if err := s.runGit(ctx, cacheDir, fetchArgs...); err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", startPoint, err)
+	}
+
+	if s.config.GitHub.Worktrees.Mode == models.CheckoutModeReference {
+		return s.cloneFromCache(ctx, repoURL, cacheDir, directory, branch, startPoint, opts)
+	}
+
+	if err := s.runGit(ctx, cacheDir, "worktree", "add", "-B", branch, directory, "origin/"+startPoint); err != nil {
+		return fmt.Errorf("failed to create worktree: %w", err)
+	}
+
+	s.logger.Debug("Created worktree",
+		zap.String("cache_dir", cacheDir),
+		zap.String("directory", directory),
+		zap.String("branch", branch))
+	return nil
+}
+
+// cloneFromCache clones a repository into directory, copying the objects the cached clone already has instead of
+// downloading them again, and checks branch out at origin/startPoint. Unlike a worktree, the clone has a .git
+// directory of its own, for tools that don't support worktrees.
+func (s *GitHubServiceImpl) cloneFromCache(ctx context.Context, repoURL, cacheDir, directory, branch, startPoint string, opts models.CloneOptions) error {
+	opts.Branch = startPoint
+	opts.Reference = cacheDir
+	if err := s.CloneRepository(ctx, repoURL, directory, opts); err != nil {
+		return err
+	}
+	if err := s.runGit(ctx, directory, "checkout", "-B", branch, "origin/"+startPoint); err != nil {
+		return fmt.Errorf("failed to create branch %s: %w", branch, err)
+	}
+
+	s.logger.Debug("Cloned from cache",
+		zap.String("cache_dir", cacheDir),
+		zap.String("directory", directory),
+		zap.String("branch", branch))
+	return nil
+}
+
+// RemoveWorktree removes a worktree created by CreateWorktree. The cached clone is kept for the next ticket.
+func (s *GitHubServiceImpl) RemoveWorktree(ctx context.Context, repoURL, directory string) error {
+	ctx, cancel := s.gitContext(ctx)
+	defer cancel()
+
+	// Standalone clones don't touch the cached clone
+	if s.config.GitHub.Worktrees.Mode == models.CheckoutModeReference {
+		if err := os.RemoveAll(directory); err != nil {
+			return fmt.Errorf("failed to remove checkout: %w", err)
+		}
+		return nil
+	}
+
+	owner, repo, err := ExtractRepoInfo(repoURL)
+	if err != nil {
+		return fmt.Errorf("failed to extract repo info: %w", err)
+	}
+
+	cacheDir := s.worktreeCacheDir(owner, repo)
+	unlock := s.lockRepo(cacheDir)
+	defer unlock()
+
+	if err := s.runGit(ctx, cacheDir, "worktree", "remove", "--force", directory); err != nil {
+		// Fall back to deleting the directory; the next prune drops the stale worktree entry
+		if removeErr := os.RemoveAll(directory); removeErr != nil {
+			return fmt.Errorf("failed to remove worktree: %w", err)
+		}
+	}
+	return nil
+}
+
+// ensureCachedClone creates the bare cached clone of a repository if it doesn't exist yet and
+// refreshes its credentials otherwise
+func (s *GitHubServiceImpl) ensureCachedClone(ctx context.Context, repoURL, cacheDir string, opts models.CloneOptions) error {
+	if _, err := os.Stat(filepath.Join(cacheDir, "HEAD")); err == nil {
+		return s.refreshRemoteAuth(ctx, cacheDir)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cacheDir), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// A bare clone has no checked out branch of its own, so every branch can be checked out in a worktree
+	args, err := s.transportCloneArgs(repoURL, cacheDir, models.CloneOptions{Depth: opts.Depth, Filter: opts.Filter, SingleBranch: opts.SingleBranch})
+	if err != nil {
+		return err
+	}
+	args = append([]string{"clone", "--bare"}, args[1:]...)
+	cmd := s.executor(ctx, "git", args...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to clone repository: %w, stderr: %s", err, stderr.String())
+	}
+
+	// Bare clones map remote branches onto local ones; track them as origin/* like a regular clone
+	if err := s.runGit(ctx, cacheDir, "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*"); err != nil {
+		return fmt.Errorf("failed to configure fetch refspec: %w", err)
+	}
+
+	return s.configureRepository(ctx, repoURL, cacheDir)
+}
+
+// runGit runs a git command in a directory and includes stderr in the returned error
+func (s *GitHubServiceImpl) runGit(ctx context.Context, directory string, args ...string) error {
+	cmd := s.executor(ctx, "git", args...)
+	cmd.Dir = directory
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w, stderr: %s", err, stderr.String())
+	}
+	return nil
+}
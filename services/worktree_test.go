@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jira-ai-issue-solver/models"
+)
+
+func TestCreateWorktree(t *testing.T) {
+	var executedCommands []string
+	mockExecutor := func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		executedCommands = append(executedCommands, strings.Join(append([]string{name}, args...), " "))
+		return exec.Command("true")
+	}
+
+	config := &models.Config{}
+	config.GitHub.BotUsername = "test-bot"
+	config.GitHub.BotEmail = "test@example.com"
+	config.GitHub.PersonalAccessToken = "token"
+	config.TempDir = t.TempDir()
+
+	githubService := newTestGitHubService(config, mockExecutor)
+
+	// The mocked clone doesn't create the cached clone directory
+	cacheDir := filepath.Join(config.TempDir, "repo-cache", "test-bot", "repo")
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		t.Fatalf("Failed to create cache directory: %v", err)
+	}
+
+	directory := filepath.Join(config.TempDir, "TEST-123")
+	err := githubService.CreateWorktree(context.Background(), "https://github.com/test-bot/repo.git", directory, "TEST-123", "main", models.CloneOptions{Depth: 50})
+	if err != nil {
+		t.Fatalf("CreateWorktree() error = %v", err)
+	}
+
+	expectedCommands := []string{
+		"git clone --bare --depth 50 --no-single-branch https://github.com/test-bot/repo.git " + cacheDir,
+		"git config remote.origin.fetch +refs/heads/*:refs/remotes/origin/*",
+		"git worktree prune",
+		"git fetch origin --depth 50 +refs/heads/main:refs/remotes/origin/main",
+		"git worktree add -B TEST-123 " + directory + " origin/main",
+	}
+
+	for _, expected := range expectedCommands {
+		found := false
+		for _, command := range executedCommands {
+			if command == expected {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Expected command '%s' to be executed, got %v", expected, executedCommands)
+		}
+	}
+}
+
+func TestCreateWorktree_ReferenceMode(t *testing.T) {
+	var executedCommands []string
+	mockExecutor := func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		executedCommands = append(executedCommands, strings.Join(append([]string{name}, args...), " "))
+		return exec.Command("true")
+	}
+
+	config := &models.Config{}
+	config.GitHub.BotUsername = "test-bot"
+	config.GitHub.BotEmail = "test@example.com"
+	config.GitHub.PersonalAccessToken = "token"
+	config.GitHub.Worktrees.Mode = models.CheckoutModeReference
+	config.TempDir = t.TempDir()
+
+	githubService := newTestGitHubService(config, mockExecutor)
+
+	// The cached clone exists already, so it's only fetched
+	cacheDir := filepath.Join(config.TempDir, "repo-cache", "test-bot", "repo")
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		t.Fatalf("Failed to create cache directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cacheDir, "HEAD"), []byte("ref: refs/heads/main\n"), 0644); err != nil {
+		t.Fatalf("Failed to create cached clone: %v", err)
+	}
+
+	repoURL := "https://github.com/test-bot/repo.git"
+	directory := filepath.Join(config.TempDir, "TEST-123")
+	if err := githubService.CreateWorktree(context.Background(), repoURL, directory, "TEST-123", "main", models.CloneOptions{}); err != nil {
+		t.Fatalf("CreateWorktree() error = %v", err)
+	}
+
+	joined := strings.Join(executedCommands, "\n")
+	if !strings.Contains(joined, "git fetch origin +refs/heads/main:refs/remotes/origin/main") {
+		t.Errorf("Expected the cached clone to be fetched, got %v", executedCommands)
+	}
+	if !strings.Contains(joined, "git clone --branch main --reference-if-able "+cacheDir+" --dissociate") {
+		t.Errorf("Expected a clone borrowing the cached clone's objects, got %v", executedCommands)
+	}
+	if !strings.Contains(joined, "git checkout -B TEST-123 origin/main") {
+		t.Errorf("Expected the ticket branch to be checked out, got %v", executedCommands)
+	}
+	if strings.Contains(joined, "worktree add") {
+		t.Errorf("Expected no worktree in reference mode, got %v", executedCommands)
+	}
+
+	// Removing the checkout leaves the cached clone alone
+	executedCommands = nil
+	if err := githubService.RemoveWorktree(context.Background(), repoURL, directory); err != nil {
+		t.Fatalf("RemoveWorktree() error = %v", err)
+	}
+	if len(executedCommands) != 0 {
+		t.Errorf("Expected no git commands on the cached clone, got %v", executedCommands)
+	}
+	if _, err := os.Stat(directory); !os.IsNotExist(err) {
+		t.Errorf("Expected the checkout to be removed, got %v", err)
+	}
+}
+
+func TestWorktreeCacheDir(t *testing.T) {
+	config := &models.Config{}
+	config.TempDir = "/tmp/solver"
+	service := newTestGitHubService(config).(*GitHubServiceImpl)
+
+	if got := service.worktreeCacheDir("owner", "repo"); got != "/tmp/solver/repo-cache/owner/repo" {
+		t.Errorf("Expected default cache dir under temp_dir, got '%s'", got)
+	}
+
+	config.GitHub.Worktrees.CacheDir = "/var/cache/solver"
+	if got := service.worktreeCacheDir("owner", "repo"); got != "/var/cache/solver/owner/repo" {
+		t.Errorf("Expected configured cache dir, got '%s'", got)
+	}
+}
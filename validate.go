@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"jira-ai-issue-solver/models"
+	"jira-ai-issue-solver/services"
+
+	"go.uber.org/zap"
+)
+
+// runValidate runs the validate subcommand, which checks a configuration before it's deployed, and returns the exit
+// code. It fails if the configuration can't be loaded or any check fails.
+func runValidate(args []string) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	configPath := flags.String("config", "config.yaml", "Path to configuration file")
+	offline := flags.Bool("offline", false, "Skip the checks against Jira and Vault")
+	asJSON := flags.Bool("json", false, "Write the report as JSON")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	config, err := models.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "The configuration is invalid: %v\n", err)
+		return 1
+	}
+
+	// The report goes to stdout, so it can be redirected without the logs
+	InitLogger(config, os.Stderr)
+	defer Logger.Sync()
+
+	var jiraService services.JiraService
+	if !*offline {
+		jiraService, err = newCommandJiraService(config)
+		if err != nil {
+			Logger.Error("Failed to initialize Jira service", zap.Error(err))
+			return 1
+		}
+	}
+
+	report := services.NewConfigValidator(jiraService, config, Logger).Validate(context.Background())
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	} else {
+		err = services.WriteValidationReport(os.Stdout, report)
+	}
+	if err != nil {
+		Logger.Error("Failed to write report", zap.Error(err))
+		return 1
+	}
+	if !report.Valid() {
+		return 1
+	}
+	return 0
+}